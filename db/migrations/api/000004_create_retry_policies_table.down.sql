@@ -0,0 +1 @@
+DROP TABLE IF EXISTS retry_policies;
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS retry_policies
+(
+    user_id        INT PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    max_attempts   INT              NOT NULL,
+    backoff        TEXT             NOT NULL,
+    base_delay_ms  INT              NOT NULL,
+    max_delay_ms   INT              NOT NULL,
+    jitter         DOUBLE PRECISION NOT NULL,
+    stale_after_ms INT              NOT NULL,
+    created_at     TIMESTAMPTZ DEFAULT now(),
+    updated_at     TIMESTAMPTZ DEFAULT now()
+);
@@ -0,0 +1,4 @@
+ALTER TABLE contacts
+    DROP COLUMN IF EXISTS timezone;
+
+DROP TABLE IF EXISTS quiet_hours;
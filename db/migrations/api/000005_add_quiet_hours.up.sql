@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS quiet_hours
+(
+    user_id      INT PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    start_minute INT  NOT NULL CHECK (start_minute BETWEEN 0 AND 1439),
+    end_minute   INT  NOT NULL CHECK (end_minute BETWEEN 0 AND 1439),
+    timezone     TEXT NOT NULL,
+    created_at   TIMESTAMPTZ DEFAULT now(),
+    updated_at   TIMESTAMPTZ DEFAULT now()
+);
+
+ALTER TABLE contacts
+    ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT '';
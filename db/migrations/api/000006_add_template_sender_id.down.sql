@@ -0,0 +1,2 @@
+ALTER TABLE message_templates
+    DROP COLUMN IF EXISTS sender_id;
@@ -0,0 +1,2 @@
+ALTER TABLE message_templates
+    ADD COLUMN IF NOT EXISTS sender_id TEXT NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS contact_imports;
\ No newline at end of file
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS contact_imports
+(
+    id             SERIAL PRIMARY KEY,
+    user_id        INT REFERENCES users (id) ON DELETE CASCADE,
+    filename       TEXT NOT NULL,
+    s3_key         TEXT NOT NULL,
+    status         TEXT NOT NULL DEFAULT 'queued',
+    rows_read      INT  NOT NULL DEFAULT 0,
+    rows_inserted  INT  NOT NULL DEFAULT 0,
+    rows_duplicate INT  NOT NULL DEFAULT 0,
+    rows_invalid   INT  NOT NULL DEFAULT 0,
+    report_key     TEXT NOT NULL DEFAULT '',
+    error          TEXT NOT NULL DEFAULT '',
+    created_at     TIMESTAMPTZ DEFAULT now(),
+    updated_at     TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS contact_imports_user_id_idx ON contact_imports (user_id);
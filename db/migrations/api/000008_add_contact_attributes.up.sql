@@ -0,0 +1,2 @@
+ALTER TABLE contacts
+    ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}';
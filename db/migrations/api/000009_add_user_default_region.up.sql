@@ -0,0 +1,2 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS default_region VARCHAR(2) NOT NULL DEFAULT 'RU';
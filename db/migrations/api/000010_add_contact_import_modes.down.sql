@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS contact_import_rows;
+
+ALTER TABLE contact_imports
+    DROP COLUMN IF EXISTS mode,
+    DROP COLUMN IF EXISTS dry_run,
+    DROP COLUMN IF EXISTS rows_updated,
+    DROP COLUMN IF EXISTS rows_deleted,
+    DROP COLUMN IF EXISTS deletions_key;
@@ -0,0 +1,17 @@
+ALTER TABLE contact_imports
+    ADD COLUMN IF NOT EXISTS mode          TEXT    NOT NULL DEFAULT 'insert-only',
+    ADD COLUMN IF NOT EXISTS dry_run       BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS rows_updated  INT     NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS rows_deleted  INT     NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS deletions_key TEXT    NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS contact_import_rows
+(
+    id         BIGSERIAL PRIMARY KEY,
+    import_id  INT REFERENCES contact_imports (id) ON DELETE CASCADE,
+    name       TEXT  NOT NULL,
+    phone      TEXT  NOT NULL,
+    attributes JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX IF NOT EXISTS contact_import_rows_import_id_phone_idx ON contact_import_rows (import_id, phone);
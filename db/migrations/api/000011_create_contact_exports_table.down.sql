@@ -0,0 +1 @@
+DROP TABLE IF EXISTS contact_exports;
\ No newline at end of file
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS contact_exports
+(
+    id           SERIAL PRIMARY KEY,
+    user_id      INT REFERENCES users (id) ON DELETE CASCADE,
+    format       TEXT NOT NULL,
+    status       TEXT NOT NULL DEFAULT 'queued',
+    rows_written INT  NOT NULL DEFAULT 0,
+    s3_key       TEXT NOT NULL DEFAULT '',
+    error        TEXT NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ DEFAULT now(),
+    updated_at   TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS contact_exports_user_id_idx ON contact_exports (user_id);
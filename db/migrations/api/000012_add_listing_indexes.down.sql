@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS message_templates_user_id_updated_at_idx;
+DROP INDEX IF EXISTS message_templates_user_id_created_at_idx;
+DROP INDEX IF EXISTS message_templates_user_id_name_idx;
+
+DROP INDEX IF EXISTS contacts_attributes_idx;
+DROP INDEX IF EXISTS contacts_user_id_updated_at_idx;
+DROP INDEX IF EXISTS contacts_user_id_created_at_idx;
+DROP INDEX IF EXISTS contacts_user_id_name_idx;
\ No newline at end of file
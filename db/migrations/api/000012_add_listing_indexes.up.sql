@@ -0,0 +1,8 @@
+CREATE INDEX IF NOT EXISTS contacts_user_id_name_idx ON contacts (user_id, name, id);
+CREATE INDEX IF NOT EXISTS contacts_user_id_created_at_idx ON contacts (user_id, created_at, id);
+CREATE INDEX IF NOT EXISTS contacts_user_id_updated_at_idx ON contacts (user_id, updated_at, id);
+CREATE INDEX IF NOT EXISTS contacts_attributes_idx ON contacts USING GIN (attributes jsonb_path_ops);
+
+CREATE INDEX IF NOT EXISTS message_templates_user_id_name_idx ON message_templates (user_id, name, id);
+CREATE INDEX IF NOT EXISTS message_templates_user_id_created_at_idx ON message_templates (user_id, created_at, id);
+CREATE INDEX IF NOT EXISTS message_templates_user_id_updated_at_idx ON message_templates (user_id, updated_at, id);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS contact_attributes;
\ No newline at end of file
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS contact_attributes
+(
+    id         SERIAL PRIMARY KEY,
+    user_id    INT REFERENCES users (id) ON DELETE CASCADE,
+    name       TEXT    NOT NULL,
+    type       TEXT    NOT NULL,
+    options    JSONB   NOT NULL DEFAULT '[]',
+    required   BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS contact_attributes_user_id_name_idx ON contact_attributes (user_id, lower(name));
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS segments;
\ No newline at end of file
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS segments
+(
+    id         SERIAL PRIMARY KEY,
+    user_id    INT REFERENCES users (id) ON DELETE CASCADE,
+    name       TEXT NOT NULL,
+    rule       TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS segments_user_id_name_idx ON segments (user_id, lower(name));
@@ -0,0 +1,2 @@
+ALTER TABLE contacts
+    DROP COLUMN IF EXISTS endpoints;
\ No newline at end of file
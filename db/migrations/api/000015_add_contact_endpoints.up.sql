@@ -0,0 +1,2 @@
+ALTER TABLE contacts
+    ADD COLUMN endpoints JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS zones;
+
+DROP INDEX IF EXISTS contacts_location_idx;
+
+ALTER TABLE contacts
+    DROP COLUMN IF EXISTS location;
\ No newline at end of file
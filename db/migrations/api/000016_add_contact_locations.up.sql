@@ -0,0 +1,18 @@
+ALTER TABLE contacts
+    ADD COLUMN location POINT;
+
+COMMENT ON COLUMN contacts.location IS 'longitude as x, latitude as y';
+
+CREATE INDEX IF NOT EXISTS contacts_location_idx ON contacts USING gist (location);
+
+CREATE TABLE IF NOT EXISTS zones
+(
+    id         SERIAL PRIMARY KEY,
+    user_id    INT REFERENCES users (id) ON DELETE CASCADE,
+    name       TEXT  NOT NULL,
+    area       JSONB NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS zones_user_id_name_idx ON zones (user_id, lower(name));
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS cap_alerts;
\ No newline at end of file
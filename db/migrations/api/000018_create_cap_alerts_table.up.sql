@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS cap_alerts
+(
+    id           SERIAL PRIMARY KEY,
+    user_id      INT REFERENCES users (id) ON DELETE CASCADE,
+    sender       TEXT NOT NULL,
+    identifier   TEXT NOT NULL,
+    sent         TEXT NOT NULL,
+    msg_type     TEXT NOT NULL,
+    campaign_id  UUID,
+    cancelled_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ DEFAULT now()
+);
+
+COMMENT ON COLUMN cap_alerts.campaign_id IS 'campaign launched by an Alert or Update message, NULL for Cancel messages';
+
+CREATE UNIQUE INDEX IF NOT EXISTS cap_alerts_user_id_sender_identifier_idx ON cap_alerts (user_id, sender, identifier);
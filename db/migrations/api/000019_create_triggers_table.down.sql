@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS trigger_firings;
+DROP TABLE IF EXISTS triggers;
\ No newline at end of file
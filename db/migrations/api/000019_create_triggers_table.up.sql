@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS triggers
+(
+    id                   SERIAL PRIMARY KEY,
+    user_id              INT REFERENCES users (id) ON DELETE CASCADE,
+    name                 TEXT  NOT NULL,
+    matchers             JSONB NOT NULL,
+    template_id          INT   NOT NULL REFERENCES message_templates (id) ON DELETE CASCADE,
+    resolved_template_id INT REFERENCES message_templates (id) ON DELETE SET NULL,
+    contact_group        TEXT  NOT NULL DEFAULT '',
+    priority             TEXT  NOT NULL DEFAULT 'critical',
+    created_at           TIMESTAMPTZ DEFAULT now(),
+    updated_at           TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS triggers_user_id_name_idx ON triggers (user_id, lower(name));
+
+CREATE TABLE IF NOT EXISTS trigger_firings
+(
+    trigger_id  INT  NOT NULL REFERENCES triggers (id) ON DELETE CASCADE,
+    fingerprint TEXT NOT NULL,
+    fired_at    TIMESTAMPTZ DEFAULT now(),
+    PRIMARY KEY (trigger_id, fingerprint)
+);
+
+COMMENT ON TABLE trigger_firings IS 'alerts that fired a trigger and have not resolved yet';
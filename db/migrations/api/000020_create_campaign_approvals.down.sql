@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS campaign_approval_events;
+DROP TABLE IF EXISTS campaign_approvals;
+DROP TABLE IF EXISTS approval_policies;
\ No newline at end of file
@@ -0,0 +1,48 @@
+CREATE TABLE IF NOT EXISTS approval_policies
+(
+    user_id                 INT PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    recipient_threshold     INT     NOT NULL DEFAULT 0 CHECK (recipient_threshold >= 0),
+    require_for_critical    BOOLEAN NOT NULL DEFAULT false,
+    approval_window_minutes INT     NOT NULL CHECK (approval_window_minutes > 0),
+    approver_ids            INT[]   NOT NULL,
+    created_at              TIMESTAMPTZ DEFAULT now(),
+    updated_at              TIMESTAMPTZ DEFAULT now()
+);
+
+COMMENT ON COLUMN approval_policies.recipient_threshold IS 'sends to more contacts need approval, 0 disables the threshold';
+
+CREATE TABLE IF NOT EXISTS campaign_approvals
+(
+    id           SERIAL PRIMARY KEY,
+    user_id      INT REFERENCES users (id) ON DELETE CASCADE,
+    template_id  INT   NOT NULL REFERENCES message_templates (id) ON DELETE CASCADE,
+    message      TEXT  NOT NULL,
+    options      JSONB NOT NULL,
+    recipients   INT   NOT NULL,
+    reason       TEXT  NOT NULL,
+    status       TEXT  NOT NULL DEFAULT 'pending',
+    approver_ids INT[] NOT NULL,
+    expires_at   TIMESTAMPTZ NOT NULL,
+    decided_at   TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ DEFAULT now()
+);
+
+COMMENT ON COLUMN campaign_approvals.message IS 'template body when the approval was requested';
+COMMENT ON COLUMN campaign_approvals.approver_ids IS 'approvers of the policy when the approval was requested';
+
+CREATE INDEX IF NOT EXISTS campaign_approvals_user_id_created_at_idx ON campaign_approvals (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS campaign_approvals_approver_ids_idx ON campaign_approvals USING GIN (approver_ids);
+
+CREATE TABLE IF NOT EXISTS campaign_approval_events
+(
+    id          SERIAL PRIMARY KEY,
+    approval_id INT  NOT NULL REFERENCES campaign_approvals (id) ON DELETE CASCADE,
+    actor_id    INT REFERENCES users (id) ON DELETE SET NULL,
+    action      TEXT NOT NULL,
+    comment     TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ DEFAULT now()
+);
+
+COMMENT ON TABLE campaign_approval_events IS 'audit trail of the requests and decisions on campaign approvals';
+
+CREATE INDEX IF NOT EXISTS campaign_approval_events_approval_id_idx ON campaign_approval_events (approval_id);
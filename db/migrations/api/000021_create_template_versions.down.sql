@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS campaigns;
+DROP TABLE IF EXISTS template_versions;
+
+ALTER TABLE message_templates
+    DROP COLUMN IF EXISTS version;
\ No newline at end of file
@@ -0,0 +1,36 @@
+ALTER TABLE message_templates
+    ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS template_versions
+(
+    template_id INT  NOT NULL,
+    version     INT  NOT NULL,
+    user_id     INT  NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name        TEXT NOT NULL,
+    body        TEXT NOT NULL,
+    sender_id   TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ DEFAULT now(),
+    PRIMARY KEY (template_id, version)
+);
+
+COMMENT ON TABLE template_versions IS 'immutable contents of every version of a template, the current one included';
+COMMENT ON COLUMN template_versions.template_id IS 'not a foreign key: versions outlive their template so that campaigns keep the text they sent';
+
+INSERT INTO template_versions (template_id, version, user_id, name, body, sender_id, created_at)
+SELECT id, version, user_id, name, body, sender_id, updated_at
+FROM message_templates
+WHERE user_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS campaigns
+(
+    id               UUID PRIMARY KEY,
+    user_id          INT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    template_id      INT NOT NULL,
+    template_version INT NOT NULL,
+    created_at       TIMESTAMPTZ DEFAULT now(),
+    FOREIGN KEY (template_id, template_version) REFERENCES template_versions (template_id, version) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE campaigns IS 'campaigns sent from templates, with the template version they used';
+
+CREATE INDEX IF NOT EXISTS campaigns_user_id_created_at_idx ON campaigns (user_id, created_at DESC);
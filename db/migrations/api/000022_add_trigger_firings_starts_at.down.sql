@@ -0,0 +1,4 @@
+ALTER TABLE trigger_firings
+    DROP COLUMN IF EXISTS starts_at;
+
+COMMENT ON TABLE trigger_firings IS 'alerts that fired a trigger and have not resolved yet';
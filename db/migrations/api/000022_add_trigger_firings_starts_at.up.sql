@@ -0,0 +1,5 @@
+ALTER TABLE trigger_firings
+    ADD COLUMN IF NOT EXISTS starts_at TIMESTAMPTZ;
+
+COMMENT ON TABLE trigger_firings IS 'alerts that fired a trigger and have not resolved yet, or whose repeat interval has not passed';
+COMMENT ON COLUMN trigger_firings.starts_at IS 'start of the alert as reported by monitoring; another start is a new firing of the same alert';
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS approval_policy_changes;
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS approval_policy_changes
+(
+    id                      SERIAL PRIMARY KEY,
+    user_id                 INT   NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    recipient_threshold     INT,
+    require_for_critical    BOOLEAN,
+    approval_window_minutes INT,
+    new_approver_ids        INT[],
+    status                  TEXT  NOT NULL DEFAULT 'pending',
+    approver_ids            INT[] NOT NULL,
+    decided_by              INT REFERENCES users (id) ON DELETE SET NULL,
+    comment                 TEXT  NOT NULL DEFAULT '',
+    expires_at              TIMESTAMPTZ NOT NULL,
+    decided_at              TIMESTAMPTZ,
+    created_at              TIMESTAMPTZ DEFAULT now()
+);
+
+COMMENT ON TABLE approval_policy_changes IS 'changes relaxing or deleting approval policies, applied once an approver of the current policy approves them';
+COMMENT ON COLUMN approval_policy_changes.new_approver_ids IS 'approvers of the requested policy; NULL, along with the other policy columns, deletes the policy';
+COMMENT ON COLUMN approval_policy_changes.approver_ids IS 'approvers of the policy when the change was requested';
+
+CREATE INDEX IF NOT EXISTS approval_policy_changes_user_id_created_at_idx ON approval_policy_changes (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS approval_policy_changes_approver_ids_idx ON approval_policy_changes USING GIN (approver_ids);
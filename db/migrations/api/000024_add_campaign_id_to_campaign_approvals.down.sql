@@ -0,0 +1,8 @@
+DELETE FROM campaign_approvals
+WHERE template_id IS NULL;
+
+ALTER TABLE campaign_approvals
+    DROP COLUMN IF EXISTS campaign_id,
+    ALTER COLUMN template_id SET NOT NULL;
+
+COMMENT ON COLUMN campaign_approvals.message IS 'template body when the approval was requested';
@@ -0,0 +1,6 @@
+ALTER TABLE campaign_approvals
+    ALTER COLUMN template_id DROP NOT NULL,
+    ADD COLUMN IF NOT EXISTS campaign_id UUID;
+
+COMMENT ON COLUMN campaign_approvals.message IS 'template body when the approval was requested, or the message sent without a template';
+COMMENT ON COLUMN campaign_approvals.campaign_id IS 'campaign of a message sent without a template, such as a CAP alert';
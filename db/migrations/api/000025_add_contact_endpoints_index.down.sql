@@ -0,0 +1 @@
+DROP INDEX IF EXISTS contacts_endpoints_idx;
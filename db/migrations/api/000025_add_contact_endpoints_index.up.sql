@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS contacts_endpoints_idx ON contacts USING GIN (endpoints jsonb_path_ops);
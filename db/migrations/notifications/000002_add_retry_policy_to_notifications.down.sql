@@ -0,0 +1,7 @@
+ALTER TABLE notifications
+    DROP COLUMN IF EXISTS max_attempts,
+    DROP COLUMN IF EXISTS backoff,
+    DROP COLUMN IF EXISTS base_delay_ms,
+    DROP COLUMN IF EXISTS max_delay_ms,
+    DROP COLUMN IF EXISTS jitter,
+    DROP COLUMN IF EXISTS stale_after_ms;
@@ -0,0 +1,7 @@
+ALTER TABLE notifications
+    ADD COLUMN max_attempts   INT              NOT NULL DEFAULT 5,
+    ADD COLUMN backoff        TEXT             NOT NULL DEFAULT 'exponential',
+    ADD COLUMN base_delay_ms  INT              NOT NULL DEFAULT 1000,
+    ADD COLUMN max_delay_ms   INT              NOT NULL DEFAULT 300000,
+    ADD COLUMN jitter         DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN stale_after_ms INT              NOT NULL DEFAULT 300000;
@@ -0,0 +1,2 @@
+ALTER TABLE notifications
+    ADD COLUMN sender_id TEXT NOT NULL DEFAULT '';
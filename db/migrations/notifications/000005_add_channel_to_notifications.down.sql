@@ -0,0 +1,5 @@
+COMMENT ON COLUMN notifications.recipient_phone IS NULL;
+
+ALTER TABLE notifications
+    DROP COLUMN IF EXISTS fallbacks,
+    DROP COLUMN IF EXISTS channel;
\ No newline at end of file
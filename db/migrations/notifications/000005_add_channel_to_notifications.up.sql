@@ -0,0 +1,5 @@
+ALTER TABLE notifications
+    ADD COLUMN channel   TEXT  NOT NULL DEFAULT 'sms',
+    ADD COLUMN fallbacks JSONB NOT NULL DEFAULT '[]';
+
+COMMENT ON COLUMN notifications.recipient_phone IS 'address of the recipient on the channel: a phone number for sms, an e-mail address for email';
@@ -0,0 +1,34 @@
+DROP TABLE IF EXISTS cancelled_campaigns;
+
+DROP INDEX IF EXISTS idx_notifications_campaign_id;
+
+ALTER TABLE notifications
+    DROP COLUMN IF EXISTS campaign_id;
+
+UPDATE notifications
+SET status = 'failed'
+WHERE status = 'cancelled';
+
+DROP INDEX IF EXISTS idx_notifications_pending;
+
+ALTER TABLE notifications
+    ALTER COLUMN status DROP DEFAULT;
+
+ALTER TYPE notification_status RENAME TO notification_status_old;
+
+CREATE TYPE notification_status AS ENUM (
+    'pending',
+    'in_flight',
+    'sent',
+    'failed'
+    );
+
+ALTER TABLE notifications
+    ALTER COLUMN status TYPE notification_status USING status::text::notification_status,
+    ALTER COLUMN status SET DEFAULT 'pending';
+
+DROP TYPE notification_status_old;
+
+CREATE INDEX idx_notifications_pending
+    ON notifications (next_run_at, status)
+    WHERE status = 'pending';
\ No newline at end of file
@@ -0,0 +1,16 @@
+ALTER TYPE notification_status ADD VALUE 'cancelled';
+
+ALTER TABLE notifications
+    ADD COLUMN campaign_id uuid;
+
+CREATE INDEX idx_notifications_campaign_id
+    ON notifications (campaign_id);
+
+CREATE TABLE cancelled_campaigns
+(
+    campaign_id  uuid PRIMARY KEY,
+    user_id      INT         NOT NULL,
+    cancelled_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE cancelled_campaigns IS 'campaigns whose notifications not yet handed to a provider must not be sent';
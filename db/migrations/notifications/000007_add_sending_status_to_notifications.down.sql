@@ -0,0 +1,28 @@
+UPDATE notifications
+SET status = 'in_flight'
+WHERE status = 'sending';
+
+DROP INDEX IF EXISTS idx_notifications_pending;
+
+ALTER TABLE notifications
+    ALTER COLUMN status DROP DEFAULT;
+
+ALTER TYPE notification_status RENAME TO notification_status_old;
+
+CREATE TYPE notification_status AS ENUM (
+    'pending',
+    'in_flight',
+    'sent',
+    'failed',
+    'cancelled'
+    );
+
+ALTER TABLE notifications
+    ALTER COLUMN status TYPE notification_status USING status::text::notification_status,
+    ALTER COLUMN status SET DEFAULT 'pending';
+
+DROP TYPE notification_status_old;
+
+CREATE INDEX idx_notifications_pending
+    ON notifications (next_run_at, status)
+    WHERE status = 'pending';
@@ -0,0 +1 @@
+ALTER TYPE notification_status ADD VALUE 'sending';
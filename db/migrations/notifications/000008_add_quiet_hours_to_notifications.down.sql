@@ -0,0 +1,3 @@
+ALTER TABLE notifications
+    DROP COLUMN IF EXISTS quiet_hours,
+    DROP COLUMN IF EXISTS priority;
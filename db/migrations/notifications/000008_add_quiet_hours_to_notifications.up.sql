@@ -0,0 +1,6 @@
+ALTER TABLE notifications
+    ADD COLUMN priority    TEXT NOT NULL DEFAULT '',
+    ADD COLUMN quiet_hours JSONB;
+
+COMMENT ON COLUMN notifications.priority IS 'priority of the campaign; empty for notifications created before priorities were stored';
+COMMENT ON COLUMN notifications.quiet_hours IS 'quiet hours in the time zone of the recipient that retries and fallbacks of non-critical notifications wait for';
@@ -0,0 +1 @@
+ALTER TABLE notifications ALTER COLUMN jitter SET DEFAULT 0.2;
@@ -106,9 +106,12 @@ const TemplatePage = () => {
               </div>
             </> : <>
               <div className="text-lg font-medium mb-2">{tmpl.name}</div>
-              <div className="text-md text-gray-700 mb-4">
+              <div className="text-md text-gray-700 mb-2">
                 {tmpl.body}
               </div>
+              <div className="text-sm text-gray-500 mb-4">
+                {tmpl.encoding}, SMS-сегментов: {tmpl.segments}
+              </div>
               <div className="flex space-x-2">
                 <Button onClick={() => handleEditClick(tmpl)}>
                   Изменить
@@ -6,6 +6,9 @@ export interface Template {
   userId: number;
   name: string;
   body: string;
+  version: number;
+  encoding: "GSM-7" | "UCS-2";
+  segments: number;
   creationTime: Date;
   updateTime: Date;
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	_ "time/tzdata"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/route"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/bootstrap"
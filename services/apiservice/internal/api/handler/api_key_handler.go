@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/contextkeys"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// APIKeyHandler handles HTTP requests managing the API keys of a user.
+type APIKeyHandler struct {
+	service        domain.APIKeyService
+	logger         *zap.Logger
+	contextTimeout time.Duration
+}
+
+// NewAPIKeyHandler creates a new APIKeyHandler with the given service,
+// structured logger, and per-request timeout duration.
+func NewAPIKeyHandler(s domain.APIKeyService, logger *zap.Logger, timeout time.Duration) *APIKeyHandler {
+	return &APIKeyHandler{
+		service:        s,
+		logger:         logger,
+		contextTimeout: timeout,
+	}
+}
+
+func (akh *APIKeyHandler) logError(msg string, r *http.Request, fields ...zap.Field) {
+	cid := r.Header.Get("X-Correlation-ID")
+
+	allFields := []zap.Field{
+		zap.String("correlation_id", cid),
+		zap.String("uri", r.RequestURI),
+		zap.String("client_ip", r.RemoteAddr),
+		zap.String("user_agent", r.UserAgent()),
+	}
+	allFields = append(allFields, fields...)
+
+	akh.logger.Error(msg, allFields...)
+}
+
+// Get handles GET /api-keys requests to list the API keys of the user. The keys themselves are never returned.
+func (akh *APIKeyHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), akh.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		akh.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	keys, err := akh.service.GetAPIKeysByUserID(ctx, userID)
+	if err != nil {
+		akh.logError("failed to get api keys", r, zap.Int("user_id", userID), zap.Error(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(keys)
+	if err != nil {
+		akh.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// Post handles POST /api-keys requests to create an API key.
+// Returns 201 Created with the key, which is shown only in this response, or 422 for an invalid name.
+func (akh *APIKeyHandler) Post(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), akh.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		akh.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	var req domain.APIKeyRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	key, err := akh.service.CreateAPIKey(ctx, userID, req.Name)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidAPIKeyName) {
+			http.Error(w, "Invalid name", http.StatusUnprocessableEntity)
+		} else {
+			akh.logError("failed to create api key", r, zap.Int("user_id", userID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(key)
+	if err != nil {
+		akh.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// Delete handles DELETE /api-keys/{id} requests, revoking the key.
+// Returns 204 No Content on success, or 404 if the key doesn't exist.
+func (akh *APIKeyHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), akh.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		akh.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	keyID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+
+	err = akh.service.DeleteAPIKey(ctx, userID, keyID)
+	if err != nil {
+		if errors.Is(err, domain.ErrAPIKeyNotExists) {
+			http.Error(w, "API key does not exist", http.StatusNotFound)
+		} else {
+			akh.logError("failed to delete api key", r, zap.Int("user_id", userID), zap.Int("id", keyID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
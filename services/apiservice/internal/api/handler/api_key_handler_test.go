@@ -0,0 +1,120 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// --- POST /api-keys ---
+func TestAPIKeyHandler_Post(t *testing.T) {
+	created := &domain.CreatedAPIKey{
+		APIKey: &models.APIKey{ID: 3, UserID: 1, Name: "Feed", Prefix: "ens_abcdefgh", Hash: []byte{1, 2}},
+		Key:    "ens_abcdefghsecret",
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		setup      func(m *MockAPIKeyService)
+		wantStatus int
+	}{
+		{
+			name: "created",
+			body: `{"name":"Feed"}`,
+			setup: func(m *MockAPIKeyService) {
+				m.On("CreateAPIKey", mock.Anything, 1, "Feed").Return(created, nil).Once()
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "bad json",
+			body:       `{`,
+			setup:      func(m *MockAPIKeyService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid name",
+			body: `{"name":""}`,
+			setup: func(m *MockAPIKeyService) {
+				m.On("CreateAPIKey", mock.Anything, 1, "").Return(nil, domain.ErrInvalidAPIKeyName).Once()
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockAPIKeyService)
+			tc.setup(m)
+			h := handler.NewAPIKeyHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodPost, "/api-keys", strings.NewReader(tc.body))
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.Post(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			if tc.wantStatus == http.StatusCreated {
+				var got map[string]any
+				assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+				assert.Equal(t, created.Key, got["key"])
+				assert.Equal(t, "ens_abcdefgh", got["prefix"])
+				assert.NotContains(t, got, "hash")
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+// --- DELETE /api-keys/{id} ---
+func TestAPIKeyHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(m *MockAPIKeyService)
+		wantStatus int
+	}{
+		{
+			name: "deleted",
+			setup: func(m *MockAPIKeyService) {
+				m.On("DeleteAPIKey", mock.Anything, 1, 3).Return(nil).Once()
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "not found",
+			setup: func(m *MockAPIKeyService) {
+				m.On("DeleteAPIKey", mock.Anything, 1, 3).Return(domain.ErrAPIKeyNotExists).Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockAPIKeyService)
+			tc.setup(m)
+			h := handler.NewAPIKeyHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodDelete, "/api-keys/3", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "3"})
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.Delete(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			m.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/contextkeys"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// ApprovalPolicyHandler handles HTTP requests for managing the user's approval policy.
+type ApprovalPolicyHandler struct {
+	service        domain.ApprovalPolicyService
+	logger         *zap.Logger
+	contextTimeout time.Duration
+}
+
+// NewApprovalPolicyHandler creates a new ApprovalPolicyHandler with the provided service, logger, and timeout.
+func NewApprovalPolicyHandler(s domain.ApprovalPolicyService, logger *zap.Logger, timeout time.Duration) *ApprovalPolicyHandler {
+	return &ApprovalPolicyHandler{
+		service:        s,
+		logger:         logger,
+		contextTimeout: timeout,
+	}
+}
+
+func (aph *ApprovalPolicyHandler) logError(msg string, r *http.Request, fields ...zap.Field) {
+	cid := r.Header.Get("X-Correlation-ID")
+
+	allFields := []zap.Field{
+		zap.String("correlation_id", cid),
+		zap.String("uri", r.RequestURI),
+		zap.String("client_ip", r.RemoteAddr),
+		zap.String("user_agent", r.UserAgent()),
+	}
+	allFields = append(allFields, fields...)
+
+	aph.logger.Error(msg, allFields...)
+}
+
+// Get returns the approval policy of the authenticated user.
+// Responds with the JSON-encoded policy, 404 if none is configured, or 500 on error.
+func (aph *ApprovalPolicyHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), aph.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		aph.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	policy, err := aph.service.GetApprovalPolicy(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrApprovalPolicyNotExists) {
+			http.Error(w, "Approval policy is not configured", http.StatusNotFound)
+		} else {
+			aph.logError("failed to get approval policy", r, zap.Int("user_id", userID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(policy)
+	if err != nil {
+		aph.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// Put sets the approval policy of the authenticated user.
+// Responds with 200 and the stored policy, or with 202 and the pending change if the policy relaxes
+// the current one and awaits the approval of an approver. Responds with 409 if an earlier change is
+// still pending, or 400/422/500 on error.
+func (aph *ApprovalPolicyHandler) Put(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), aph.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		aph.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	var req models.ApprovalPolicy
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	policy, change, err := aph.service.UpdateApprovalPolicy(ctx, userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidApprovalPolicy):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		case errors.Is(err, domain.ErrApprovalPolicyChangePending):
+			http.Error(w, "Approval policy change is already pending", http.StatusConflict)
+		default:
+			aph.logError("failed to update approval policy", r, zap.Int("user_id", userID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if change != nil {
+		w.WriteHeader(http.StatusAccepted)
+		err = json.NewEncoder(w).Encode(change)
+	} else {
+		w.WriteHeader(http.StatusOK)
+		err = json.NewEncoder(w).Encode(policy)
+	}
+	if err != nil {
+		aph.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// Delete requests turning approval of campaigns off for the authenticated user, which takes effect
+// once an approver of the policy approves it. Responds with 202 and the pending change, 404 if no
+// policy is configured, 409 if an earlier change is still pending, or 500 on error.
+func (aph *ApprovalPolicyHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), aph.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		aph.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	change, err := aph.service.DeleteApprovalPolicy(ctx, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrApprovalPolicyNotExists):
+			http.Error(w, "Approval policy is not configured", http.StatusNotFound)
+		case errors.Is(err, domain.ErrApprovalPolicyChangePending):
+			http.Error(w, "Approval policy change is already pending", http.StatusConflict)
+		default:
+			aph.logError("failed to delete approval policy", r, zap.Int("user_id", userID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	err = json.NewEncoder(w).Encode(change)
+	if err != nil {
+		aph.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// GetChanges handles GET /approval-policy/changes requests. Responds with the approval policy
+// changes requested by the user or awaiting their decision, newest first.
+func (aph *ApprovalPolicyHandler) GetChanges(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), aph.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		aph.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	changes, err := aph.service.GetPolicyChanges(ctx, userID)
+	if err != nil {
+		aph.logError("failed to get approval policy changes", r, zap.Int("user_id", userID), zap.Error(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(changes)
+	if err != nil {
+		aph.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// ApproveChange handles POST /approval-policy/changes/{id}/approve requests with an optional comment,
+// applying the change. Responds with 200 and the approved change, 403 if the user is not its approver,
+// 409 if it has already been decided on or has expired, or 404 if it doesn't exist.
+func (aph *ApprovalPolicyHandler) ApproveChange(w http.ResponseWriter, r *http.Request) {
+	aph.decideChange(w, r, aph.service.ApprovePolicyChange)
+}
+
+// RejectChange handles POST /approval-policy/changes/{id}/reject requests with an optional comment.
+// Responds like ApproveChange.
+func (aph *ApprovalPolicyHandler) RejectChange(w http.ResponseWriter, r *http.Request) {
+	aph.decideChange(w, r, aph.service.RejectPolicyChange)
+}
+
+// decideChange records the decision of the user on the policy change with the ID of the path.
+func (aph *ApprovalPolicyHandler) decideChange(w http.ResponseWriter, r *http.Request, decide func(ctx context.Context, userID, changeID int, comment string) (*models.ApprovalPolicyChange, error)) {
+	ctx, cancel := context.WithTimeout(r.Context(), aph.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		aph.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	changeID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+
+	var req domain.ApprovalDecisionRequest
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	change, err := decide(ctx, userID, changeID, req.Comment)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrApprovalPolicyChangeNotExists):
+			http.Error(w, "Approval policy change does not exist", http.StatusNotFound)
+		case errors.Is(err, domain.ErrNotApprover):
+			http.Error(w, "Not an approver of the change", http.StatusForbidden)
+		case errors.Is(err, domain.ErrApprovalNotPending):
+			http.Error(w, "Change is already decided", http.StatusConflict)
+		case errors.Is(err, domain.ErrApprovalExpired):
+			http.Error(w, "Change has expired", http.StatusConflict)
+		case errors.Is(err, domain.ErrInvalidApprovalComment):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		default:
+			aph.logError("failed to decide on approval policy change", r, zap.Int("user_id", userID), zap.Int("id", changeID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(change)
+	if err != nil {
+		aph.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
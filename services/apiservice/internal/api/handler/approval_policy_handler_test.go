@@ -0,0 +1,330 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testApprovalPolicy = &models.ApprovalPolicy{
+	RecipientThreshold:    1000,
+	RequireForCritical:    true,
+	ApprovalWindowMinutes: 60,
+	Approvers:             []string{"bob@example.com"},
+}
+
+var testApprovalPolicyChange = &models.ApprovalPolicyChange{
+	ID:             3,
+	UserID:         1,
+	RequesterEmail: "alice@example.com",
+	Policy:         testApprovalPolicy,
+	Status:         models.ApprovalPending,
+}
+
+// --- GET /approval-policy ---
+func TestApprovalPolicyHandler_Get(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(m *MockApprovalPolicyService)
+		wantStatus int
+		wantBody   *models.ApprovalPolicy
+	}{
+		{
+			name: "success",
+			setup: func(m *MockApprovalPolicyService) {
+				m.
+					On("GetApprovalPolicy", mock.Anything, 1).
+					Return(testApprovalPolicy, nil).
+					Once()
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   testApprovalPolicy,
+		},
+		{
+			name: "not configured",
+			setup: func(m *MockApprovalPolicyService) {
+				m.
+					On("GetApprovalPolicy", mock.Anything, 1).
+					Return((*models.ApprovalPolicy)(nil), domain.ErrApprovalPolicyNotExists).
+					Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "service error",
+			setup: func(m *MockApprovalPolicyService) {
+				m.
+					On("GetApprovalPolicy", mock.Anything, 1).
+					Return((*models.ApprovalPolicy)(nil), assert.AnError).
+					Once()
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockApprovalPolicyService)
+			tc.setup(m)
+			h := handler.NewApprovalPolicyHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodGet, "/approval-policy", nil)
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.Get(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			if tc.wantBody != nil {
+				var got models.ApprovalPolicy
+				err := json.NewDecoder(rr.Body).Decode(&got)
+				assert.NoError(t, err)
+				assert.Equal(t, *tc.wantBody, got)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+// --- PUT /approval-policy ---
+func TestApprovalPolicyHandler_Put(t *testing.T) {
+	validBody, _ := json.Marshal(testApprovalPolicy)
+
+	tests := []struct {
+		name       string
+		body       []byte
+		setup      func(m *MockApprovalPolicyService)
+		wantStatus int
+		wantChange bool
+	}{
+		{
+			name: "success",
+			body: validBody,
+			setup: func(m *MockApprovalPolicyService) {
+				m.
+					On("UpdateApprovalPolicy", mock.Anything, 1, testApprovalPolicy).
+					Return(testApprovalPolicy, nil, nil).
+					Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "relaxing change pending",
+			body: validBody,
+			setup: func(m *MockApprovalPolicyService) {
+				m.
+					On("UpdateApprovalPolicy", mock.Anything, 1, testApprovalPolicy).
+					Return(nil, testApprovalPolicyChange, nil).
+					Once()
+			},
+			wantStatus: http.StatusAccepted,
+			wantChange: true,
+		},
+		{
+			name: "earlier change pending",
+			body: validBody,
+			setup: func(m *MockApprovalPolicyService) {
+				m.
+					On("UpdateApprovalPolicy", mock.Anything, 1, testApprovalPolicy).
+					Return(nil, nil, domain.ErrApprovalPolicyChangePending).
+					Once()
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "bad json",
+			body:       []byte("{bad"),
+			setup:      func(m *MockApprovalPolicyService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid policy",
+			body: validBody,
+			setup: func(m *MockApprovalPolicyService) {
+				m.
+					On("UpdateApprovalPolicy", mock.Anything, 1, testApprovalPolicy).
+					Return(nil, nil, domain.ErrInvalidApprovalPolicy).
+					Once()
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "service error",
+			body: validBody,
+			setup: func(m *MockApprovalPolicyService) {
+				m.
+					On("UpdateApprovalPolicy", mock.Anything, 1, testApprovalPolicy).
+					Return(nil, nil, assert.AnError).
+					Once()
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockApprovalPolicyService)
+			tc.setup(m)
+			h := handler.NewApprovalPolicyHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodPut, "/approval-policy", bytes.NewReader(tc.body))
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.Put(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			if tc.wantChange {
+				var got models.ApprovalPolicyChange
+				err := json.NewDecoder(rr.Body).Decode(&got)
+				assert.NoError(t, err)
+				assert.Equal(t, testApprovalPolicyChange.ID, got.ID)
+				assert.Equal(t, models.ApprovalPending, got.Status)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+// --- DELETE /approval-policy ---
+func TestApprovalPolicyHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name       string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "deletion pending", wantStatus: http.StatusAccepted},
+		{name: "not configured", serviceErr: domain.ErrApprovalPolicyNotExists, wantStatus: http.StatusNotFound},
+		{name: "earlier change pending", serviceErr: domain.ErrApprovalPolicyChangePending, wantStatus: http.StatusConflict},
+		{name: "service error", serviceErr: assert.AnError, wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockApprovalPolicyService)
+			change := &models.ApprovalPolicyChange{ID: 3, UserID: 1, Status: models.ApprovalPending}
+			if tc.serviceErr != nil {
+				change = nil
+			}
+			m.
+				On("DeleteApprovalPolicy", mock.Anything, 1).
+				Return(change, tc.serviceErr).
+				Once()
+			h := handler.NewApprovalPolicyHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodDelete, "/approval-policy", nil)
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.Delete(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+// --- GET /approval-policy/changes ---
+func TestApprovalPolicyHandler_GetChanges(t *testing.T) {
+	tests := []struct {
+		name       string
+		changes    []*models.ApprovalPolicyChange
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "success", changes: []*models.ApprovalPolicyChange{testApprovalPolicyChange}, wantStatus: http.StatusOK},
+		{name: "service error", serviceErr: assert.AnError, wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockApprovalPolicyService)
+			m.
+				On("GetPolicyChanges", mock.Anything, 1).
+				Return(tc.changes, tc.serviceErr).
+				Once()
+			h := handler.NewApprovalPolicyHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodGet, "/approval-policy/changes", nil)
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.GetChanges(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			if tc.serviceErr == nil {
+				var got []*models.ApprovalPolicyChange
+				err := json.NewDecoder(rr.Body).Decode(&got)
+				assert.NoError(t, err)
+				assert.Len(t, got, 1)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+// --- POST /approval-policy/changes/{id}/approve and /reject ---
+func TestApprovalPolicyHandler_DecideChange(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		body       string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "success", id: "3", body: `{"comment":"agreed"}`, wantStatus: http.StatusOK},
+		{name: "without body", id: "3", wantStatus: http.StatusOK},
+		{name: "invalid id", id: "abc", wantStatus: http.StatusBadRequest},
+		{name: "bad json", id: "3", body: "{bad", wantStatus: http.StatusBadRequest},
+		{name: "not found", id: "3", serviceErr: domain.ErrApprovalPolicyChangeNotExists, wantStatus: http.StatusNotFound},
+		{name: "not approver", id: "3", serviceErr: domain.ErrNotApprover, wantStatus: http.StatusForbidden},
+		{name: "already decided", id: "3", serviceErr: domain.ErrApprovalNotPending, wantStatus: http.StatusConflict},
+		{name: "expired", id: "3", serviceErr: domain.ErrApprovalExpired, wantStatus: http.StatusConflict},
+		{name: "comment too long", id: "3", serviceErr: domain.ErrInvalidApprovalComment, wantStatus: http.StatusUnprocessableEntity},
+		{name: "service error", id: "3", serviceErr: assert.AnError, wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, action := range []string{"approve", "reject"} {
+		for _, tc := range tests {
+			t.Run(action+" "+tc.name, func(t *testing.T) {
+				m := new(MockApprovalPolicyService)
+				method, decide := "ApprovePolicyChange", (*handler.ApprovalPolicyHandler).ApproveChange
+				if action == "reject" {
+					method, decide = "RejectPolicyChange", (*handler.ApprovalPolicyHandler).RejectChange
+				}
+				if tc.wantStatus != http.StatusBadRequest {
+					var comment string
+					if tc.body != "" {
+						comment = "agreed"
+					}
+					change := testApprovalPolicyChange
+					if tc.serviceErr != nil {
+						change = nil
+					}
+					m.On(method, mock.Anything, 2, 3, comment).Return(change, tc.serviceErr).Once()
+				}
+				h := handler.NewApprovalPolicyHandler(m, logger, timeout)
+
+				req := httptest.NewRequest(http.MethodPost, "/approval-policy/changes/"+tc.id+"/"+action, strings.NewReader(tc.body))
+				req = mux.SetURLVars(req, map[string]string{"id": tc.id})
+				req = injectUserID(req, 2)
+				rr := httptest.NewRecorder()
+
+				decide(h, rr, req)
+
+				assert.Equal(t, tc.wantStatus, rr.Code)
+				m.AssertExpectations(t)
+			})
+		}
+	}
+}
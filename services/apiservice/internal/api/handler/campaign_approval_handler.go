@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/contextkeys"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// CampaignApprovalHandler handles HTTP requests listing campaign approvals and deciding on them.
+type CampaignApprovalHandler struct {
+	service        domain.CampaignApprovalService
+	logger         *zap.Logger
+	contextTimeout time.Duration
+}
+
+// NewCampaignApprovalHandler creates a new CampaignApprovalHandler with the given service,
+// structured logger, and per-request timeout duration.
+func NewCampaignApprovalHandler(s domain.CampaignApprovalService, logger *zap.Logger, timeout time.Duration) *CampaignApprovalHandler {
+	return &CampaignApprovalHandler{
+		service:        s,
+		logger:         logger,
+		contextTimeout: timeout,
+	}
+}
+
+func (cah *CampaignApprovalHandler) logError(msg string, r *http.Request, fields ...zap.Field) {
+	cid := r.Header.Get("X-Correlation-ID")
+
+	allFields := []zap.Field{
+		zap.String("correlation_id", cid),
+		zap.String("uri", r.RequestURI),
+		zap.String("client_ip", r.RemoteAddr),
+		zap.String("user_agent", r.UserAgent()),
+	}
+	allFields = append(allFields, fields...)
+
+	cah.logger.Error(msg, allFields...)
+}
+
+// Get handles GET /approvals requests to list the campaign approvals requested by the user
+// or awaiting their decision.
+func (cah *CampaignApprovalHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), cah.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		cah.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	approvals, err := cah.service.GetApprovals(ctx, userID)
+	if err != nil {
+		cah.logError("failed to get campaign approvals", r, zap.Int("user_id", userID), zap.Error(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(approvals)
+	if err != nil {
+		cah.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// GetByID handles GET /approvals/{id} requests. Responds with the approval and its audit trail,
+// or 404 if the approval is neither requested by the user nor awaiting their decision.
+func (cah *CampaignApprovalHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), cah.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		cah.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	approvalID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+
+	approval, err := cah.service.GetApprovalByID(ctx, userID, approvalID)
+	if err != nil {
+		if errors.Is(err, domain.ErrCampaignApprovalNotExists) {
+			http.Error(w, "Approval does not exist", http.StatusNotFound)
+		} else {
+			cah.logError("failed to get campaign approval", r, zap.Int("user_id", userID), zap.Int("id", approvalID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(approval)
+	if err != nil {
+		cah.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// Approve handles POST /approvals/{id}/approve requests with an optional comment, sending the campaign.
+// Responds with 200 and the approved campaign, 403 if the user is not its approver, 409 if it has
+// already been decided on, has expired or its template has changed, or 404 if it doesn't exist.
+func (cah *CampaignApprovalHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	cah.decide(w, r, cah.service.ApproveCampaign)
+}
+
+// Reject handles POST /approvals/{id}/reject requests with an optional comment. Responds like Approve.
+func (cah *CampaignApprovalHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	cah.decide(w, r, cah.service.RejectCampaign)
+}
+
+// decide records the decision of the user on the approval with the ID of the path.
+func (cah *CampaignApprovalHandler) decide(w http.ResponseWriter, r *http.Request, decide func(ctx context.Context, userID, approvalID int, comment string) (*models.CampaignApproval, error)) {
+	ctx, cancel := context.WithTimeout(r.Context(), cah.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		cah.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	approvalID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+
+	var req domain.ApprovalDecisionRequest
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	approval, err := decide(ctx, userID, approvalID, req.Comment)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrCampaignApprovalNotExists):
+			http.Error(w, "Approval does not exist", http.StatusNotFound)
+		case errors.Is(err, domain.ErrNotApprover):
+			http.Error(w, "Not an approver of the campaign", http.StatusForbidden)
+		case errors.Is(err, domain.ErrApprovalNotPending):
+			http.Error(w, "Approval is already decided", http.StatusConflict)
+		case errors.Is(err, domain.ErrApprovalExpired):
+			http.Error(w, "Approval has expired", http.StatusConflict)
+		case errors.Is(err, domain.ErrApprovalTemplateChanged):
+			http.Error(w, "Template has changed since approval was requested", http.StatusConflict)
+		case errors.Is(err, domain.ErrInvalidApprovalComment):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		case errors.Is(err, domain.ErrContactNotExists):
+			http.Error(w, "No contacts", http.StatusNotFound)
+		default:
+			cah.logError("failed to decide on campaign approval", r, zap.Int("user_id", userID), zap.Int("id", approvalID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(approval)
+	if err != nil {
+		cah.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
@@ -0,0 +1,219 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testCampaignApproval = &models.CampaignApproval{
+	ID:         7,
+	UserID:     1,
+	TemplateID: 5,
+	Message:    "Evacuate",
+	Options:    []byte(`{}`),
+	Recipients: 1500,
+	Reason:     models.ApprovalReasonRecipients,
+	Status:     models.ApprovalPending,
+}
+
+// --- GET /approvals ---
+func TestCampaignApprovalHandler_Get(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		m := new(MockCampaignApprovalService)
+		m.
+			On("GetApprovals", mock.Anything, 2).
+			Return([]*models.CampaignApproval{testCampaignApproval}, nil).
+			Once()
+		h := handler.NewCampaignApprovalHandler(m, logger, timeout)
+
+		req := httptest.NewRequest(http.MethodGet, "/approvals", nil)
+		req = injectUserID(req, 2)
+		rr := httptest.NewRecorder()
+
+		h.Get(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var got []*models.CampaignApproval
+		err := json.NewDecoder(rr.Body).Decode(&got)
+		assert.NoError(t, err)
+		assert.Len(t, got, 1)
+		assert.Equal(t, 7, got[0].ID)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		m := new(MockCampaignApprovalService)
+		m.
+			On("GetApprovals", mock.Anything, 2).
+			Return(nil, assert.AnError).
+			Once()
+		h := handler.NewCampaignApprovalHandler(m, logger, timeout)
+
+		req := httptest.NewRequest(http.MethodGet, "/approvals", nil)
+		req = injectUserID(req, 2)
+		rr := httptest.NewRecorder()
+
+		h.Get(rr, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+		m.AssertExpectations(t)
+	})
+}
+
+// --- GET /approvals/{id} ---
+func TestCampaignApprovalHandler_GetByID(t *testing.T) {
+	tests := []struct {
+		name       string
+		idParam    string
+		setup      func(m *MockCampaignApprovalService)
+		wantStatus int
+	}{
+		{
+			name:    "success",
+			idParam: "7",
+			setup: func(m *MockCampaignApprovalService) {
+				m.
+					On("GetApprovalByID", mock.Anything, 2, 7).
+					Return(testCampaignApproval, nil).
+					Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid id",
+			idParam:    "abc",
+			setup:      func(m *MockCampaignApprovalService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "not found",
+			idParam: "7",
+			setup: func(m *MockCampaignApprovalService) {
+				m.
+					On("GetApprovalByID", mock.Anything, 2, 7).
+					Return(nil, domain.ErrCampaignApprovalNotExists).
+					Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockCampaignApprovalService)
+			tc.setup(m)
+			h := handler.NewCampaignApprovalHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodGet, "/approvals/"+tc.idParam, nil)
+			req = injectUserID(req, 2)
+			req = mux.SetURLVars(req, map[string]string{"id": tc.idParam})
+			rr := httptest.NewRecorder()
+
+			h.GetByID(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+// --- POST /approvals/{id}/approve ---
+func TestCampaignApprovalHandler_Approve(t *testing.T) {
+	body, _ := json.Marshal(domain.ApprovalDecisionRequest{Comment: "checked with duty officer"})
+
+	tests := []struct {
+		name       string
+		body       []byte
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "success", body: body, wantStatus: http.StatusOK},
+		{name: "no body", wantStatus: http.StatusOK},
+		{name: "bad json", body: []byte("{bad"), wantStatus: http.StatusBadRequest},
+		{name: "not found", body: body, serviceErr: domain.ErrCampaignApprovalNotExists, wantStatus: http.StatusNotFound},
+		{name: "not approver", body: body, serviceErr: domain.ErrNotApprover, wantStatus: http.StatusForbidden},
+		{name: "already decided", body: body, serviceErr: domain.ErrApprovalNotPending, wantStatus: http.StatusConflict},
+		{name: "expired", body: body, serviceErr: domain.ErrApprovalExpired, wantStatus: http.StatusConflict},
+		{name: "template changed", body: body, serviceErr: domain.ErrApprovalTemplateChanged, wantStatus: http.StatusConflict},
+		{name: "invalid comment", body: body, serviceErr: domain.ErrInvalidApprovalComment, wantStatus: http.StatusUnprocessableEntity},
+		{name: "service error", body: body, serviceErr: assert.AnError, wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockCampaignApprovalService)
+			if tc.wantStatus != http.StatusBadRequest {
+				var comment string
+				if tc.body != nil {
+					comment = "checked with duty officer"
+				}
+				var approval *models.CampaignApproval
+				if tc.serviceErr == nil {
+					approval = testCampaignApproval
+				}
+				m.
+					On("ApproveCampaign", mock.Anything, 2, 7, comment).
+					Return(approval, tc.serviceErr).
+					Once()
+			}
+			h := handler.NewCampaignApprovalHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodPost, "/approvals/7/approve", bytes.NewReader(tc.body))
+			req = injectUserID(req, 2)
+			req = mux.SetURLVars(req, map[string]string{"id": "7"})
+			rr := httptest.NewRecorder()
+
+			h.Approve(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+// --- POST /approvals/{id}/reject ---
+func TestCampaignApprovalHandler_Reject(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		m := new(MockCampaignApprovalService)
+		m.
+			On("RejectCampaign", mock.Anything, 2, 7, "too broad").
+			Return(testCampaignApproval, nil).
+			Once()
+		h := handler.NewCampaignApprovalHandler(m, logger, timeout)
+
+		req := httptest.NewRequest(http.MethodPost, "/approvals/7/reject", bytes.NewBufferString(`{"comment":"too broad"}`))
+		req = injectUserID(req, 2)
+		req = mux.SetURLVars(req, map[string]string{"id": "7"})
+		rr := httptest.NewRecorder()
+
+		h.Reject(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		m := new(MockCampaignApprovalService)
+		h := handler.NewCampaignApprovalHandler(m, logger, timeout)
+
+		req := httptest.NewRequest(http.MethodPost, "/approvals/x/reject", nil)
+		req = injectUserID(req, 2)
+		req = mux.SetURLVars(req, map[string]string{"id": "x"})
+		rr := httptest.NewRecorder()
+
+		h.Reject(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		m.AssertExpectations(t)
+	})
+}
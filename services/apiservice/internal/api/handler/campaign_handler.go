@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/contextkeys"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"go.uber.org/zap"
+)
+
+// CampaignHandler handles HTTP requests listing the campaigns a user sent from templates.
+type CampaignHandler struct {
+	service        domain.CampaignService
+	logger         *zap.Logger
+	contextTimeout time.Duration
+}
+
+// NewCampaignHandler creates a new CampaignHandler with the given service,
+// structured logger, and per-request timeout duration.
+func NewCampaignHandler(s domain.CampaignService, logger *zap.Logger, timeout time.Duration) *CampaignHandler {
+	return &CampaignHandler{
+		service:        s,
+		logger:         logger,
+		contextTimeout: timeout,
+	}
+}
+
+func (ch *CampaignHandler) logError(msg string, r *http.Request, fields ...zap.Field) {
+	cid := r.Header.Get("X-Correlation-ID")
+
+	allFields := []zap.Field{
+		zap.String("correlation_id", cid),
+		zap.String("uri", r.RequestURI),
+		zap.String("client_ip", r.RemoteAddr),
+		zap.String("user_agent", r.UserAgent()),
+	}
+	allFields = append(allFields, fields...)
+
+	ch.logger.Error(msg, allFields...)
+}
+
+// Get handles GET /campaigns requests to list a page of the user's campaigns, newest first, each
+// with the template version it was sent from. The templateId query parameter narrows them to one
+// template; pages follow limit and offset. Responds with 400 for a malformed template ID.
+func (ch *CampaignHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), ch.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		ch.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	query := r.URL.Query()
+
+	var filter domain.CampaignFilter
+	if raw := query.Get("templateId"); raw != "" {
+		tmplID, err := strconv.Atoi(raw)
+		if err != nil {
+			http.Error(w, "Invalid template id", http.StatusBadRequest)
+			return
+		}
+		filter.TemplateID = tmplID
+	}
+
+	campaigns, err := ch.service.GetCampaigns(ctx, userID, filter, parsePageRequest(query))
+	if err != nil {
+		ch.logError("failed to get campaigns", r, zap.Int("user_id", userID), zap.Error(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(campaigns)
+	if err != nil {
+		ch.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
@@ -0,0 +1,84 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCampaignHandler_Get(t *testing.T) {
+	campaigns := []*models.Campaign{
+		{ID: uuid.New(), UserID: 1, TemplateID: 4, TemplateVersion: 2, Body: "Evacuate"},
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		setup      func(m *MockCampaignService)
+		wantStatus int
+	}{
+		{
+			name:  "all campaigns",
+			query: "",
+			setup: func(m *MockCampaignService) {
+				m.On("GetCampaigns", mock.Anything, 1, domain.CampaignFilter{}, domain.PageRequest{}).Return(campaigns, nil).Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:  "by template with page",
+			query: "?templateId=4&limit=10&offset=20",
+			setup: func(m *MockCampaignService) {
+				m.
+					On("GetCampaigns", mock.Anything, 1, domain.CampaignFilter{TemplateID: 4}, domain.PageRequest{Limit: 10, Offset: 20}).
+					Return(campaigns, nil).
+					Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "bad template id",
+			query:      "?templateId=abc",
+			setup:      func(m *MockCampaignService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "service error",
+			query: "",
+			setup: func(m *MockCampaignService) {
+				m.On("GetCampaigns", mock.Anything, 1, domain.CampaignFilter{}, domain.PageRequest{}).Return(nil, assert.AnError).Once()
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockCampaignService)
+			tc.setup(m)
+			h := handler.NewCampaignHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodGet, "/campaigns"+tc.query, nil)
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.Get(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			if tc.wantStatus == http.StatusOK {
+				var got []*models.Campaign
+				assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+				assert.Equal(t, campaigns, got)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
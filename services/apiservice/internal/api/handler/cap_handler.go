@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/cap"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/contextkeys"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"go.uber.org/zap"
+)
+
+// CAPHandler handles Common Alerting Protocol alerts posted by alerting systems.
+type CAPHandler struct {
+	service        domain.CAPService
+	logger         *zap.Logger
+	contextTimeout time.Duration
+}
+
+// NewCAPHandler creates a new CAPHandler with the given service,
+// structured logger, and per-request timeout duration.
+func NewCAPHandler(s domain.CAPService, logger *zap.Logger, timeout time.Duration) *CAPHandler {
+	return &CAPHandler{
+		service:        s,
+		logger:         logger,
+		contextTimeout: timeout,
+	}
+}
+
+func (ch *CAPHandler) logError(msg string, r *http.Request, fields ...zap.Field) {
+	cid := r.Header.Get("X-Correlation-ID")
+
+	allFields := []zap.Field{
+		zap.String("correlation_id", cid),
+		zap.String("uri", r.RequestURI),
+		zap.String("client_ip", r.RemoteAddr),
+		zap.String("user_agent", r.UserAgent()),
+	}
+	allFields = append(allFields, fields...)
+
+	ch.logger.Error(msg, allFields...)
+}
+
+// PostAlert handles POST /cap/alerts requests carrying a CAP 1.2 XML document.
+// Responds with 202 Accepted and what was done with the alert, 200 OK if it was ignored,
+// 422 for an invalid alert or area, 409 for an alert received before, 404 for a cancellation
+// of unknown alerts, or 413 for documents larger than 1MB.
+func (ch *CAPHandler) PostAlert(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), ch.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		ch.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1MB
+
+	alert, err := cap.Parse(r.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		} else {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		}
+		return
+	}
+
+	res, err := ch.service.IngestAlert(ctx, userID, alert)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidCAPAlert), errors.Is(err, domain.ErrInvalidArea):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		case errors.Is(err, domain.ErrCAPAlertAlreadyExists):
+			http.Error(w, "Alert already received", http.StatusConflict)
+		case errors.Is(err, domain.ErrCAPAlertNotExists):
+			http.Error(w, "Referenced alert does not exist", http.StatusNotFound)
+		default:
+			ch.logError("failed to ingest cap alert", r, zap.Int("user_id", userID), zap.String("identifier", alert.Identifier), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	status := http.StatusAccepted
+	if res.Action == domain.CAPActionIgnored {
+		status = http.StatusOK
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	err = json.NewEncoder(w).Encode(res)
+	if err != nil {
+		ch.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
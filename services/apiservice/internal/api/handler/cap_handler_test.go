@@ -0,0 +1,124 @@
+package handler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testCAPDocument = `<?xml version="1.0" encoding="UTF-8"?>
+<alert xmlns="urn:oasis:names:tc:emergency:cap:1.2">
+  <identifier>A1</identifier>
+  <sender>alerts@example.org</sender>
+  <sent>2026-10-19T10:00:00+03:00</sent>
+  <status>Actual</status>
+  <msgType>Alert</msgType>
+  <scope>Public</scope>
+  <info>
+    <event>Flood</event>
+    <urgency>Immediate</urgency>
+    <severity>Severe</severity>
+    <certainty>Observed</certainty>
+    <description>The river is overflowing.</description>
+  </info>
+</alert>`
+
+// --- POST /cap/alerts ---
+func TestCAPHandler_PostAlert(t *testing.T) {
+	campaignID := uuid.New()
+
+	tests := []struct {
+		name       string
+		body       string
+		setup      func(m *MockCAPService)
+		wantStatus int
+	}{
+		{
+			name: "launched",
+			body: testCAPDocument,
+			setup: func(m *MockCAPService) {
+				m.On("IngestAlert", mock.Anything, 1, mock.Anything).
+					Return(&domain.CAPResult{Action: domain.CAPActionLaunched, CampaignID: &campaignID, CancelledCampaigns: []uuid.UUID{}}, nil).Once()
+			},
+			wantStatus: http.StatusAccepted,
+		},
+		{
+			name: "ignored",
+			body: testCAPDocument,
+			setup: func(m *MockCAPService) {
+				m.On("IngestAlert", mock.Anything, 1, mock.Anything).
+					Return(&domain.CAPResult{Action: domain.CAPActionIgnored, CancelledCampaigns: []uuid.UUID{}}, nil).Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "not cap",
+			body:       `<alert><identifier>A1</identifier></alert>`,
+			setup:      func(m *MockCAPService) {},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "too large",
+			body:       strings.Replace(testCAPDocument, "The river", strings.Repeat("x", 1<<20), 1),
+			setup:      func(m *MockCAPService) {},
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "invalid alert",
+			body: testCAPDocument,
+			setup: func(m *MockCAPService) {
+				m.On("IngestAlert", mock.Anything, 1, mock.Anything).Return(nil, domain.ErrInvalidCAPAlert).Once()
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "already received",
+			body: testCAPDocument,
+			setup: func(m *MockCAPService) {
+				m.On("IngestAlert", mock.Anything, 1, mock.Anything).Return(nil, domain.ErrCAPAlertAlreadyExists).Once()
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name: "unknown reference",
+			body: testCAPDocument,
+			setup: func(m *MockCAPService) {
+				m.On("IngestAlert", mock.Anything, 1, mock.Anything).Return(nil, domain.ErrCAPAlertNotExists).Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "service error",
+			body: testCAPDocument,
+			setup: func(m *MockCAPService) {
+				m.On("IngestAlert", mock.Anything, 1, mock.Anything).Return(nil, errors.New("db down")).Once()
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockCAPService)
+			tc.setup(m)
+			h := handler.NewCAPHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodPost, "/cap/alerts", strings.NewReader(tc.body))
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.PostAlert(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			m.AssertExpectations(t)
+		})
+	}
+}
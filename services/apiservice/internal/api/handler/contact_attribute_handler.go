@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/contextkeys"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// ContactAttributeHandler handles HTTP requests managing the typed contact attributes of a user.
+type ContactAttributeHandler struct {
+	service        domain.ContactAttributeService
+	logger         *zap.Logger
+	contextTimeout time.Duration
+}
+
+// NewContactAttributeHandler creates a new ContactAttributeHandler with the given service,
+// structured logger, and per-request timeout duration.
+func NewContactAttributeHandler(s domain.ContactAttributeService, logger *zap.Logger, timeout time.Duration) *ContactAttributeHandler {
+	return &ContactAttributeHandler{
+		service:        s,
+		logger:         logger,
+		contextTimeout: timeout,
+	}
+}
+
+func (ah *ContactAttributeHandler) logError(msg string, r *http.Request, fields ...zap.Field) {
+	cid := r.Header.Get("X-Correlation-ID")
+
+	allFields := []zap.Field{
+		zap.String("correlation_id", cid),
+		zap.String("uri", r.RequestURI),
+		zap.String("client_ip", r.RemoteAddr),
+		zap.String("user_agent", r.UserAgent()),
+	}
+	allFields = append(allFields, fields...)
+
+	ah.logger.Error(msg, allFields...)
+}
+
+// Get handles GET /contacts/attributes requests to list the contact attributes defined by the user.
+func (ah *ContactAttributeHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), ah.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		ah.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	attrs, err := ah.service.GetContactAttributes(ctx, userID)
+	if err != nil {
+		ah.logError("failed to get contact attributes", r, zap.Int("user_id", userID), zap.Error(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(attrs)
+	if err != nil {
+		ah.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// Post handles POST /contacts/attributes requests to define a new contact attribute.
+// Returns 201 Created with the attribute, 422 for an invalid definition or 409 if the
+// user already has an attribute with the name.
+func (ah *ContactAttributeHandler) Post(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), ah.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		ah.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	var req domain.ContactAttributeRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	attr, err := ah.service.CreateContactAttribute(ctx, &models.ContactAttribute{
+		UserID:   userID,
+		Name:     req.Name,
+		Type:     req.Type,
+		Options:  req.Options,
+		Required: req.Required,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidContactAttribute):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		case errors.Is(err, domain.ErrContactAttributeAlreadyExists):
+			http.Error(w, "Contact attribute already exists", http.StatusConflict)
+		default:
+			ah.logError("failed to create contact attribute", r, zap.Int("user_id", userID), zap.String("name", req.Name), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(attr)
+	if err != nil {
+		ah.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// Put handles PUT /contacts/attributes/{id} requests to replace the definition of a contact
+// attribute. Returns 404 if the user has no such attribute.
+func (ah *ContactAttributeHandler) Put(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), ah.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		ah.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	attrID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+
+	var req domain.ContactAttributeRequest
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	attr, err := ah.service.UpdateContactAttribute(ctx, userID, attrID, &models.ContactAttribute{
+		UserID:   userID,
+		Name:     req.Name,
+		Type:     req.Type,
+		Options:  req.Options,
+		Required: req.Required,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidContactAttribute):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		case errors.Is(err, domain.ErrContactAttributeNotExists):
+			http.Error(w, "Contact attribute does not exist", http.StatusNotFound)
+		case errors.Is(err, domain.ErrContactAttributeAlreadyExists):
+			http.Error(w, "Contact attribute already exists", http.StatusConflict)
+		default:
+			ah.logError("failed to update contact attribute", r, zap.Int("user_id", userID), zap.Int("id", attrID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(attr)
+	if err != nil {
+		ah.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// Delete handles DELETE /contacts/attributes/{id} requests to remove a contact attribute
+// definition. Values contacts hold for it are kept as untyped attributes.
+// Returns 204 No Content on success, or 404 if the attribute doesn't exist.
+func (ah *ContactAttributeHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), ah.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		ah.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	attrID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+
+	err = ah.service.DeleteContactAttribute(ctx, userID, attrID)
+	if err != nil {
+		if errors.Is(err, domain.ErrContactAttributeNotExists) {
+			http.Error(w, "Contact attribute does not exist", http.StatusNotFound)
+		} else {
+			ah.logError("failed to delete contact attribute", r, zap.Int("user_id", userID), zap.Int("id", attrID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,225 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testContactAttribute = &models.ContactAttribute{
+	ID:       3,
+	UserID:   1,
+	Name:     "Shift",
+	Type:     models.AttributeEnum,
+	Options:  []string{"Day", "Night"},
+	Required: true,
+}
+
+// --- GET /contacts/attributes ---
+func TestContactAttributeHandler_Get(t *testing.T) {
+	m := new(MockContactAttributeService)
+	m.
+		On("GetContactAttributes", mock.Anything, 1).
+		Return([]*models.ContactAttribute{testContactAttribute}, nil).
+		Once()
+	h := handler.NewContactAttributeHandler(m, logger, timeout)
+
+	req := httptest.NewRequest(http.MethodGet, "/contacts/attributes", nil)
+	req = injectUserID(req, 1)
+	rr := httptest.NewRecorder()
+
+	h.Get(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var got []*models.ContactAttribute
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+	assert.Equal(t, []*models.ContactAttribute{testContactAttribute}, got)
+	m.AssertExpectations(t)
+}
+
+// --- POST /contacts/attributes ---
+func TestContactAttributeHandler_Post(t *testing.T) {
+	attr := &models.ContactAttribute{
+		UserID:   1,
+		Name:     "Shift",
+		Type:     models.AttributeEnum,
+		Options:  []string{"Day", "Night"},
+		Required: true,
+	}
+	body := `{"name":"Shift","type":"enum","options":["Day","Night"],"required":true}`
+
+	tests := []struct {
+		name       string
+		body       string
+		setup      func(m *MockContactAttributeService)
+		wantStatus int
+	}{
+		{
+			name: "created",
+			body: body,
+			setup: func(m *MockContactAttributeService) {
+				m.
+					On("CreateContactAttribute", mock.Anything, attr).
+					Return(testContactAttribute, nil).
+					Once()
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "bad json",
+			body:       `{`,
+			setup:      func(m *MockContactAttributeService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid definition",
+			body: body,
+			setup: func(m *MockContactAttributeService) {
+				m.
+					On("CreateContactAttribute", mock.Anything, attr).
+					Return(nil, domain.ErrInvalidContactAttributeOptions).
+					Once()
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "already exists",
+			body: body,
+			setup: func(m *MockContactAttributeService) {
+				m.
+					On("CreateContactAttribute", mock.Anything, attr).
+					Return(nil, domain.ErrContactAttributeAlreadyExists).
+					Once()
+			},
+			wantStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockContactAttributeService)
+			tc.setup(m)
+			h := handler.NewContactAttributeHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodPost, "/contacts/attributes", strings.NewReader(tc.body))
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.Post(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+// --- PUT /contacts/attributes/{id} ---
+func TestContactAttributeHandler_Put(t *testing.T) {
+	attr := &models.ContactAttribute{UserID: 1, Name: "Floor", Type: models.AttributeNumber}
+	body := `{"name":"Floor","type":"number"}`
+
+	tests := []struct {
+		name       string
+		idParam    string
+		setup      func(m *MockContactAttributeService)
+		wantStatus int
+	}{
+		{
+			name:    "updated",
+			idParam: "3",
+			setup: func(m *MockContactAttributeService) {
+				m.
+					On("UpdateContactAttribute", mock.Anything, 1, 3, attr).
+					Return(attr, nil).
+					Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid id",
+			idParam:    "abc",
+			setup:      func(m *MockContactAttributeService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "not found",
+			idParam: "3",
+			setup: func(m *MockContactAttributeService) {
+				m.
+					On("UpdateContactAttribute", mock.Anything, 1, 3, attr).
+					Return(nil, domain.ErrContactAttributeNotExists).
+					Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockContactAttributeService)
+			tc.setup(m)
+			h := handler.NewContactAttributeHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodPut, "/contacts/attributes/"+tc.idParam, strings.NewReader(body))
+			req = mux.SetURLVars(req, map[string]string{"id": tc.idParam})
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.Put(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+// --- DELETE /contacts/attributes/{id} ---
+func TestContactAttributeHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(m *MockContactAttributeService)
+		wantStatus int
+	}{
+		{
+			name: "deleted",
+			setup: func(m *MockContactAttributeService) {
+				m.On("DeleteContactAttribute", mock.Anything, 1, 3).Return(nil).Once()
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "not found",
+			setup: func(m *MockContactAttributeService) {
+				m.On("DeleteContactAttribute", mock.Anything, 1, 3).Return(domain.ErrContactAttributeNotExists).Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockContactAttributeService)
+			tc.setup(m)
+			h := handler.NewContactAttributeHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodDelete, "/contacts/attributes/3", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "3"})
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.Delete(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			m.AssertExpectations(t)
+		})
+	}
+}
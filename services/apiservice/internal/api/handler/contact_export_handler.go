@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/contextkeys"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// ContactExportHandler handles HTTP requests for exporting contacts.
+type ContactExportHandler struct {
+	service        domain.ContactExportService
+	logger         *zap.Logger
+	contextTimeout time.Duration
+	exportTimeout  time.Duration
+}
+
+// NewContactExportHandler constructs a ContactExportHandler. exportTimeout bounds streamed
+// exports, which may take much longer than regular requests.
+func NewContactExportHandler(s domain.ContactExportService, logger *zap.Logger, timeout, exportTimeout time.Duration) *ContactExportHandler {
+	return &ContactExportHandler{
+		service:        s,
+		logger:         logger,
+		contextTimeout: timeout,
+		exportTimeout:  exportTimeout,
+	}
+}
+
+func (ceh *ContactExportHandler) logError(msg string, r *http.Request, fields ...zap.Field) {
+	cid := r.Header.Get("X-Correlation-ID")
+
+	allFields := []zap.Field{
+		zap.String("correlation_id", cid),
+		zap.String("uri", r.RequestURI),
+		zap.String("client_ip", r.RemoteAddr),
+		zap.String("user_agent", r.UserAgent()),
+	}
+	allFields = append(allFields, fields...)
+
+	ceh.logger.Error(msg, allFields...)
+}
+
+// Export handles GET /contacts/export?format=csv|xlsx requests and streams the user's
+// contacts as a file download, optionally narrowed by the same filters as GET /contacts.
+// The format defaults to CSV. Returns 422 for other formats.
+func (ceh *ContactExportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), ceh.exportTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		ceh.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	query := r.URL.Query()
+	format := models.ExportFormat(query.Get("format"))
+	if format == "" {
+		format = models.ExportFormatCSV
+	}
+	if format != models.ExportFormatCSV && format != models.ExportFormatXLSX {
+		http.Error(w, "Invalid format", http.StatusUnprocessableEntity)
+		return
+	}
+	filter, err := parseContactFilter(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="contacts.%s"`, format))
+
+	// Once the first bytes are sent the status can no longer change, so a failure
+	// midway only cuts the download short.
+	err = ceh.service.ExportContacts(ctx, userID, format, filter, w)
+	if err != nil {
+		ceh.logError("failed to export contacts", r, zap.Int("user_id", userID), zap.String("format", string(format)), zap.Error(err))
+	}
+}
+
+// Post handles POST /contacts/exports requests. It starts writing the user's contacts to
+// S3 in the background and responds with 202 and the queued export job, whose location
+// is given in the Location header. Returns 422 for formats other than CSV and XLSX.
+func (ceh *ContactExportHandler) Post(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), ceh.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		ceh.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	var req domain.PostContactExportRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	if req.Format == "" {
+		req.Format = models.ExportFormatCSV
+	}
+	filter := domain.ContactFilter{
+		Group:  req.Group,
+		Search: req.Search,
+	}
+
+	contactExport, err := ceh.service.StartContactExport(ctx, userID, req.Format, filter)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidExportFormat) {
+			http.Error(w, "Invalid format", http.StatusUnprocessableEntity)
+		} else {
+			ceh.logError("failed to start contact export", r, zap.Int("user_id", userID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("/contacts/exports/%d", contactExport.ID))
+	w.WriteHeader(http.StatusAccepted)
+	err = json.NewEncoder(w).Encode(contactExport)
+	if err != nil {
+		ceh.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// GetByID handles GET /contacts/exports/{id} requests and returns the status of an export
+// job owned by the user, with a presigned download link once it is done. Returns 404 if not found.
+func (ceh *ContactExportHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), ceh.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		ceh.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	exportID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+
+	contactExport, err := ceh.service.GetContactExport(ctx, userID, exportID)
+	if err != nil {
+		if errors.Is(err, domain.ErrContactExportNotExists) {
+			http.Error(w, "Export does not exist", http.StatusNotFound)
+		} else {
+			ceh.logError("failed to get contact export", r, zap.Int("user_id", userID), zap.Int("id", exportID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(contactExport)
+	if err != nil {
+		ceh.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
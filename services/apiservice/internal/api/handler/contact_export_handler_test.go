@@ -0,0 +1,255 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testContactExport = &models.ContactExport{
+	ID:          4,
+	UserID:      1,
+	Format:      models.ExportFormatXLSX,
+	Status:      models.ExportStatusDone,
+	RowsWritten: 2,
+	DownloadURL: "http://minio:9000/contacts-bucket/exports/4/contacts.xlsx?X-Amz-Expires=3600",
+}
+
+// --- GET /contacts/export ---
+func TestContactExportHandler_Export(t *testing.T) {
+	file := "Name,Phone\nAlice,+79123456789\n"
+
+	tests := []struct {
+		name            string
+		query           string
+		setup           func(m *MockContactExportService)
+		wantStatus      int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:  "csv by default",
+			query: "",
+			setup: func(m *MockContactExportService) {
+				m.
+					On("ExportContacts", mock.Anything, 1, models.ExportFormatCSV, domain.ContactFilter{}).
+					Return(file, nil).
+					Once()
+			},
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/csv",
+			wantBody:        file,
+		},
+		{
+			name:  "xlsx with filter",
+			query: "?format=xlsx&group=Sales&search=ali",
+			setup: func(m *MockContactExportService) {
+				m.
+					On("ExportContacts", mock.Anything, 1, models.ExportFormatXLSX, domain.ContactFilter{Group: "Sales", Search: "ali"}).
+					Return("xlsx", nil).
+					Once()
+			},
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+			wantBody:        "xlsx",
+		},
+		{
+			name:       "invalid format",
+			query:      "?format=pdf",
+			setup:      func(m *MockContactExportService) {},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockContactExportService)
+			tc.setup(m)
+			h := handler.NewContactExportHandler(m, logger, timeout, timeout)
+
+			req := httptest.NewRequest(http.MethodGet, "/contacts/export"+tc.query, nil)
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.Export(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			if tc.wantStatus == http.StatusOK {
+				assert.Equal(t, tc.wantContentType, rr.Header().Get("Content-Type"))
+				assert.Contains(t, rr.Header().Get("Content-Disposition"), "attachment")
+				assert.Equal(t, tc.wantBody, rr.Body.String())
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+// --- POST /contacts/exports ---
+func TestContactExportHandler_Post(t *testing.T) {
+	queued := &models.ContactExport{ID: 4, UserID: 1, Format: models.ExportFormatXLSX, Status: models.ExportStatusQueued}
+
+	tests := []struct {
+		name         string
+		body         string
+		setup        func(m *MockContactExportService)
+		wantStatus   int
+		wantLocation string
+	}{
+		{
+			name: "accepted",
+			body: `{"format":"xlsx","group":"Sales"}`,
+			setup: func(m *MockContactExportService) {
+				m.
+					On("StartContactExport", mock.Anything, 1, models.ExportFormatXLSX, domain.ContactFilter{Group: "Sales"}).
+					Return(queued, nil).
+					Once()
+			},
+			wantStatus:   http.StatusAccepted,
+			wantLocation: "/contacts/exports/4",
+		},
+		{
+			name: "csv by default",
+			body: `{}`,
+			setup: func(m *MockContactExportService) {
+				m.
+					On("StartContactExport", mock.Anything, 1, models.ExportFormatCSV, domain.ContactFilter{}).
+					Return(queued, nil).
+					Once()
+			},
+			wantStatus:   http.StatusAccepted,
+			wantLocation: "/contacts/exports/4",
+		},
+		{
+			name:       "bad json",
+			body:       `{`,
+			setup:      func(m *MockContactExportService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid format",
+			body: `{"format":"pdf"}`,
+			setup: func(m *MockContactExportService) {
+				m.
+					On("StartContactExport", mock.Anything, 1, models.ExportFormat("pdf"), domain.ContactFilter{}).
+					Return(nil, domain.ErrInvalidExportFormat).
+					Once()
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "service error",
+			body: `{"format":"csv"}`,
+			setup: func(m *MockContactExportService) {
+				m.
+					On("StartContactExport", mock.Anything, 1, models.ExportFormatCSV, domain.ContactFilter{}).
+					Return(nil, assert.AnError).
+					Once()
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockContactExportService)
+			tc.setup(m)
+			h := handler.NewContactExportHandler(m, logger, timeout, timeout)
+
+			req := httptest.NewRequest(http.MethodPost, "/contacts/exports", strings.NewReader(tc.body))
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.Post(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			assert.Equal(t, tc.wantLocation, rr.Header().Get("Location"))
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+// --- GET /contacts/exports/{id} ---
+func TestContactExportHandler_GetByID(t *testing.T) {
+	tests := []struct {
+		name       string
+		idParam    string
+		setup      func(m *MockContactExportService)
+		wantStatus int
+		wantBody   *models.ContactExport
+	}{
+		{
+			name:    "success",
+			idParam: "4",
+			setup: func(m *MockContactExportService) {
+				m.
+					On("GetContactExport", mock.Anything, 1, 4).
+					Return(testContactExport, nil).
+					Once()
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   testContactExport,
+		},
+		{
+			name:       "invalid id",
+			idParam:    "abc",
+			setup:      func(m *MockContactExportService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "not found",
+			idParam: "4",
+			setup: func(m *MockContactExportService) {
+				m.
+					On("GetContactExport", mock.Anything, 1, 4).
+					Return(nil, domain.ErrContactExportNotExists).
+					Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:    "service error",
+			idParam: "4",
+			setup: func(m *MockContactExportService) {
+				m.
+					On("GetContactExport", mock.Anything, 1, 4).
+					Return(nil, assert.AnError).
+					Once()
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockContactExportService)
+			tc.setup(m)
+			h := handler.NewContactExportHandler(m, logger, timeout, timeout)
+
+			req := httptest.NewRequest(http.MethodGet, "/contacts/exports/"+tc.idParam, nil)
+			req = injectUserID(req, 1)
+			req = mux.SetURLVars(req, map[string]string{"id": tc.idParam})
+			rr := httptest.NewRecorder()
+
+			h.GetByID(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			if tc.wantBody != nil {
+				var got models.ContactExport
+				err := json.NewDecoder(rr.Body).Decode(&got)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.wantBody.DownloadURL, got.DownloadURL)
+				assert.Equal(t, tc.wantBody.RowsWritten, got.RowsWritten)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
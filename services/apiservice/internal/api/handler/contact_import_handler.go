@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/contextkeys"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// ContactImportHandler handles HTTP requests for contact import jobs.
+type ContactImportHandler struct {
+	service        domain.ContactImportService
+	logger         *zap.Logger
+	contextTimeout time.Duration
+}
+
+// NewContactImportHandler constructs a ContactImportHandler.
+func NewContactImportHandler(s domain.ContactImportService, logger *zap.Logger, timeout time.Duration) *ContactImportHandler {
+	return &ContactImportHandler{
+		service:        s,
+		logger:         logger,
+		contextTimeout: timeout,
+	}
+}
+
+func (cih *ContactImportHandler) logError(msg string, r *http.Request, fields ...zap.Field) {
+	cid := r.Header.Get("X-Correlation-ID")
+
+	allFields := []zap.Field{
+		zap.String("correlation_id", cid),
+		zap.String("uri", r.RequestURI),
+		zap.String("client_ip", r.RemoteAddr),
+		zap.String("user_agent", r.UserAgent()),
+	}
+	allFields = append(allFields, fields...)
+
+	cih.logger.Error(msg, allFields...)
+}
+
+// GetByID handles GET /imports/{id} requests and returns the status and row counters
+// of an import job owned by the user. Returns 404 if not found.
+func (cih *ContactImportHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), cih.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		cih.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	importID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+
+	contactImport, err := cih.service.GetContactImport(ctx, userID, importID)
+	if err != nil {
+		if errors.Is(err, domain.ErrContactImportNotExists) {
+			http.Error(w, "Import does not exist", http.StatusNotFound)
+		} else {
+			cih.logError("failed to get contact import", r, zap.Int("user_id", userID), zap.Int("id", importID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(contactImport)
+	if err != nil {
+		cih.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// GetReport handles GET /imports/{id}/report requests and streams the CSV report
+// listing every rejected row of the import with its reason. Returns 404 if the
+// import does not exist or has no rejected rows.
+func (cih *ContactImportHandler) GetReport(w http.ResponseWriter, r *http.Request) {
+	cih.serveReport(w, r, "rejected", cih.service.GetRejectionReport, domain.ErrContactImportReportNotExists, "Import has no rejected rows")
+}
+
+// GetDeletions handles GET /imports/{id}/deletions requests and streams the CSV report
+// listing the contacts a sync import deleted, or would delete in a dry run. Returns 404
+// if the import does not exist or deletes no contacts.
+func (cih *ContactImportHandler) GetDeletions(w http.ResponseWriter, r *http.Request) {
+	cih.serveReport(w, r, "deleted", cih.service.GetDeletionsReport, domain.ErrContactImportDeletionsNotExists, "Import deletes no contacts")
+}
+
+// serveReport streams the CSV report opened by open as import-{id}-{name}.csv.
+// missingErr is answered with 404 and the given message.
+func (cih *ContactImportHandler) serveReport(
+	w http.ResponseWriter,
+	r *http.Request,
+	name string,
+	open func(ctx context.Context, userID, importID int) (io.ReadCloser, error),
+	missingErr error,
+	missingMsg string,
+) {
+	ctx, cancel := context.WithTimeout(r.Context(), cih.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		cih.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	importID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+
+	report, err := open(ctx, userID, importID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrContactImportNotExists):
+			http.Error(w, "Import does not exist", http.StatusNotFound)
+		case errors.Is(err, missingErr):
+			http.Error(w, missingMsg, http.StatusNotFound)
+		default:
+			cih.logError("failed to get contact import report", r, zap.Int("user_id", userID), zap.Int("id", importID), zap.String("report", name), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+	defer func() {
+		err := report.Close()
+		if err != nil {
+			cih.logError("failed to close contact import report", r, zap.Error(err))
+		}
+	}()
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="import-%d-%s.csv"`, importID, name))
+	w.WriteHeader(http.StatusOK)
+	_, err = io.Copy(w, report)
+	if err != nil {
+		cih.logError("failed to write report to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
@@ -0,0 +1,257 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testContactImport = &models.ContactImport{
+	ID:           3,
+	UserID:       1,
+	Filename:     "contacts.csv",
+	Status:       models.ImportStatusDone,
+	RowsRead:     10,
+	RowsInserted: 7,
+	RowsInvalid:  3,
+	ReportURL:    "/imports/3/report",
+}
+
+// --- GET /imports/{id} ---
+func TestContactImportHandler_GetByID(t *testing.T) {
+	tests := []struct {
+		name       string
+		idParam    string
+		setup      func(m *MockContactImportService)
+		wantStatus int
+		wantBody   *models.ContactImport
+	}{
+		{
+			name:    "success",
+			idParam: "3",
+			setup: func(m *MockContactImportService) {
+				m.
+					On("GetContactImport", mock.Anything, 1, 3).
+					Return(testContactImport, nil).
+					Once()
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   testContactImport,
+		},
+		{
+			name:       "invalid id",
+			idParam:    "abc",
+			setup:      func(m *MockContactImportService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "not found",
+			idParam: "3",
+			setup: func(m *MockContactImportService) {
+				m.
+					On("GetContactImport", mock.Anything, 1, 3).
+					Return((*models.ContactImport)(nil), domain.ErrContactImportNotExists).
+					Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:    "service error",
+			idParam: "3",
+			setup: func(m *MockContactImportService) {
+				m.
+					On("GetContactImport", mock.Anything, 1, 3).
+					Return((*models.ContactImport)(nil), assert.AnError).
+					Once()
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockContactImportService)
+			tc.setup(m)
+			h := handler.NewContactImportHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodGet, "/imports/"+tc.idParam, nil)
+			req = injectUserID(req, 1)
+			req = mux.SetURLVars(req, map[string]string{"id": tc.idParam})
+			rr := httptest.NewRecorder()
+
+			h.GetByID(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			if tc.wantBody != nil {
+				var got models.ContactImport
+				err := json.NewDecoder(rr.Body).Decode(&got)
+				assert.NoError(t, err)
+				assert.Equal(t, *tc.wantBody, got)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+// --- GET /imports/{id}/report ---
+func TestContactImportHandler_GetReport(t *testing.T) {
+	report := "line,name,phone,reason\n2,Bob,123,invalid phone\n"
+
+	tests := []struct {
+		name       string
+		idParam    string
+		setup      func(m *MockContactImportService)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:    "success",
+			idParam: "3",
+			setup: func(m *MockContactImportService) {
+				m.
+					On("GetRejectionReport", mock.Anything, 1, 3).
+					Return(io.NopCloser(strings.NewReader(report)), nil).
+					Once()
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   report,
+		},
+		{
+			name:       "invalid id",
+			idParam:    "abc",
+			setup:      func(m *MockContactImportService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "import not found",
+			idParam: "3",
+			setup: func(m *MockContactImportService) {
+				m.
+					On("GetRejectionReport", mock.Anything, 1, 3).
+					Return((io.ReadCloser)(nil), domain.ErrContactImportNotExists).
+					Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:    "no rejected rows",
+			idParam: "3",
+			setup: func(m *MockContactImportService) {
+				m.
+					On("GetRejectionReport", mock.Anything, 1, 3).
+					Return((io.ReadCloser)(nil), domain.ErrContactImportReportNotExists).
+					Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:    "service error",
+			idParam: "3",
+			setup: func(m *MockContactImportService) {
+				m.
+					On("GetRejectionReport", mock.Anything, 1, 3).
+					Return((io.ReadCloser)(nil), assert.AnError).
+					Once()
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockContactImportService)
+			tc.setup(m)
+			h := handler.NewContactImportHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodGet, "/imports/"+tc.idParam+"/report", nil)
+			req = injectUserID(req, 1)
+			req = mux.SetURLVars(req, map[string]string{"id": tc.idParam})
+			rr := httptest.NewRecorder()
+
+			h.GetReport(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			if tc.wantBody != "" {
+				assert.Equal(t, "text/csv", rr.Header().Get("Content-Type"))
+				assert.Equal(t, tc.wantBody, rr.Body.String())
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+// --- GET /imports/{id}/deletions ---
+func TestContactImportHandler_GetDeletions(t *testing.T) {
+	report := "name,phone\nCarol,+79123456787\n"
+
+	tests := []struct {
+		name       string
+		setup      func(m *MockContactImportService)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "success",
+			setup: func(m *MockContactImportService) {
+				m.
+					On("GetDeletionsReport", mock.Anything, 1, 3).
+					Return(io.NopCloser(strings.NewReader(report)), nil).
+					Once()
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   report,
+		},
+		{
+			name: "no deleted contacts",
+			setup: func(m *MockContactImportService) {
+				m.
+					On("GetDeletionsReport", mock.Anything, 1, 3).
+					Return((io.ReadCloser)(nil), domain.ErrContactImportDeletionsNotExists).
+					Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "service error",
+			setup: func(m *MockContactImportService) {
+				m.
+					On("GetDeletionsReport", mock.Anything, 1, 3).
+					Return((io.ReadCloser)(nil), assert.AnError).
+					Once()
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockContactImportService)
+			tc.setup(m)
+			h := handler.NewContactImportHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodGet, "/imports/3/deletions", nil)
+			req = injectUserID(req, 1)
+			req = mux.SetURLVars(req, map[string]string{"id": "3"})
+			rr := httptest.NewRecorder()
+
+			h.GetDeletions(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			if tc.wantBody != "" {
+				assert.Equal(t, `attachment; filename="import-3-deleted.csv"`, rr.Header().Get("Content-Disposition"))
+				assert.Equal(t, tc.wantBody, rr.Body.String())
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
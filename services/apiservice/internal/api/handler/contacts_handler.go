@@ -47,8 +47,10 @@ func (ch *ContactsHandler) logError(msg string, r *http.Request, fields ...zap.F
 	ch.logger.Error(msg, allFields...)
 }
 
-// Get handles GET /contacts requests to retrieve all contacts
-// belonging to the authenticated user.
+// Get handles GET /contacts requests to retrieve a page of the contacts belonging to the
+// authenticated user, filtered by the search, group, attr, createdFrom and createdTo query
+// parameters and ordered by sort. Pages follow nextCursor of the previous response, or
+// limit and offset. Returns 400 for malformed filters, sorts or cursors.
 func (ch *ContactsHandler) Get(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), ch.contextTimeout)
 	defer cancel()
@@ -62,17 +64,28 @@ func (ch *ContactsHandler) Get(w http.ResponseWriter, r *http.Request) {
 	}
 
 	query := r.URL.Query()
-	limit, _ := strconv.Atoi(query.Get("limit"))
-	offset, _ := strconv.Atoi(query.Get("offset"))
+	filter, err := parseContactFilter(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	page := parsePageRequest(query)
 
-	contactsPage, err := ch.service.GetContactsPageByUserID(ctx, userID, limit, offset)
+	contactsPage, nextCursor, err := ch.service.GetContactsPageByUserID(ctx, userID, filter, page)
 	if err != nil {
-		ch.logError("failed to get contacts page", r, zap.Int("user_id", userID), zap.Error(err))
-		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		switch {
+		case errors.Is(err, domain.ErrInvalidSort):
+			http.Error(w, "Invalid sort", http.StatusBadRequest)
+		case errors.Is(err, domain.ErrInvalidCursor):
+			http.Error(w, "Invalid cursor", http.StatusBadRequest)
+		default:
+			ch.logError("failed to get contacts page", r, zap.Int("user_id", userID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
 		return
 	}
 
-	contactsCount, err := ch.service.GetContactsCountByUserID(ctx, userID)
+	contactsCount, err := ch.service.GetContactsCountByUserID(ctx, userID, filter)
 	if err != nil {
 		ch.logError("failed to get contacts count", r, zap.Int("user_id", userID), zap.Error(err))
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -82,8 +95,9 @@ func (ch *ContactsHandler) Get(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	err = json.NewEncoder(w).Encode(&domain.GetContactsResponse{
-		Contacts: contactsPage,
-		Total:    contactsCount,
+		Contacts:   contactsPage,
+		Total:      contactsCount,
+		NextCursor: nextCursor,
 	})
 	if err != nil {
 		ch.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
@@ -155,12 +169,16 @@ func (ch *ContactsHandler) Post(w http.ResponseWriter, r *http.Request) {
 	}
 
 	newContact := &models.Contact{
-		UserID: userID,
-		Name:   req.Name,
-		Phone:  req.Phone,
+		UserID:     userID,
+		Name:       req.Name,
+		Phone:      req.Phone,
+		TimeZone:   req.TimeZone,
+		Attributes: req.Attributes,
+		Endpoints:  req.Endpoints,
+		Location:   req.Location,
 	}
 
-	newContact, err = ch.service.CreateContact(ctx, newContact)
+	newContact, err = ch.service.CreateContact(ctx, newContact, req.Region)
 	if err != nil {
 		switch {
 		case errors.Is(err, domain.ErrInvalidContact):
@@ -212,13 +230,7 @@ func (ch *ContactsHandler) Put(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	updatedContact := &models.Contact{
-		UserID: userID,
-		Name:   req.Name,
-		Phone:  req.Phone,
-	}
-
-	updatedContact, err = ch.service.UpdateContact(ctx, userID, contactID, updatedContact)
+	updatedContact, err := ch.service.UpdateContact(ctx, userID, contactID, &req)
 	if err != nil {
 		switch {
 		case errors.Is(err, domain.ErrInvalidContact):
@@ -277,3 +289,164 @@ func (ch *ContactsHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// PostBulk handles POST /contacts/bulk requests to create many contacts of the user at once.
+// Returns 200 with the outcome of every item: invalid items and duplicate phone numbers are
+// reported rather than failing the request. Returns 422 if the request has too many contacts.
+func (ch *ContactsHandler) PostBulk(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), ch.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		ch.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	var req domain.PostContactsBulkRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := ch.service.CreateContacts(ctx, userID, req.Contacts)
+	if err != nil {
+		if errors.Is(err, domain.ErrTooManyContacts) {
+			http.Error(w, "Too many contacts", http.StatusUnprocessableEntity)
+		} else {
+			ch.logError("failed to create contacts", r, zap.Int("user_id", userID), zap.Int("count", len(req.Contacts)), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		ch.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// PostBulkDelete handles POST /contacts/bulk/delete requests to delete the user's contacts
+// matching the filter, by IDs or by the same criteria as listing. Returns 200 with the number
+// of deleted contacts, or 422 for an empty filter or too many IDs.
+func (ch *ContactsHandler) PostBulkDelete(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), ch.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		ch.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	var req domain.PostContactsDeleteRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	affected, err := ch.service.DeleteContacts(ctx, userID, req.Filter)
+	if err != nil {
+		ch.writeBulkError(w, r, "failed to delete contacts", userID, err)
+		return
+	}
+
+	ch.writeBulkResponse(w, r, userID, affected)
+}
+
+// PostBulkGroup handles POST /contacts/bulk/group requests to assign the user's contacts
+// matching the filter to a group, or to remove them from their groups when the group is empty.
+// Returns 200 with the number of changed contacts, or 422 for an empty filter, too many IDs
+// or a too long group.
+func (ch *ContactsHandler) PostBulkGroup(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), ch.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		ch.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	var req domain.PostContactsGroupRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	affected, err := ch.service.SetContactsGroup(ctx, userID, req.Filter, req.Group)
+	if err != nil {
+		ch.writeBulkError(w, r, "failed to set contacts group", userID, err)
+		return
+	}
+
+	ch.writeBulkResponse(w, r, userID, affected)
+}
+
+// DeleteAll handles DELETE /contacts?all=true requests to delete every contact of the user.
+// The all parameter guards against deleting everything by accident; without it the request
+// is rejected with 400. Returns 200 with the number of deleted contacts.
+func (ch *ContactsHandler) DeleteAll(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), ch.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		ch.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	all, err := strconv.ParseBool(r.URL.Query().Get("all"))
+	if err != nil || !all {
+		http.Error(w, "Invalid all", http.StatusBadRequest)
+		return
+	}
+
+	affected, err := ch.service.DeleteAllContacts(ctx, userID)
+	if err != nil {
+		ch.logError("failed to delete all contacts", r, zap.Int("user_id", userID), zap.Error(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	ch.writeBulkResponse(w, r, userID, affected)
+}
+
+func (ch *ContactsHandler) writeBulkError(w http.ResponseWriter, r *http.Request, msg string, userID int, err error) {
+	switch {
+	case errors.Is(err, domain.ErrEmptyContactFilter):
+		http.Error(w, "Empty filter", http.StatusUnprocessableEntity)
+	case errors.Is(err, domain.ErrTooManyContacts):
+		http.Error(w, "Too many contacts", http.StatusUnprocessableEntity)
+	case errors.Is(err, domain.ErrInvalidContactGroup):
+		http.Error(w, "Invalid group", http.StatusUnprocessableEntity)
+	default:
+		ch.logError(msg, r, zap.Int("user_id", userID), zap.Error(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
+
+func (ch *ContactsHandler) writeBulkResponse(w http.ResponseWriter, r *http.Request, userID, affected int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err := json.NewEncoder(w).Encode(&domain.BulkContactsResponse{Affected: affected})
+	if err != nil {
+		ch.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
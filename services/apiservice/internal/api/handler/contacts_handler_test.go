@@ -31,13 +31,17 @@ var (
 // --- GET /contacts ---
 func TestContactsHandler_Get(t *testing.T) {
 	type resp struct {
-		Contacts []*models.Contact `json:"contacts"`
-		Total    int               `json:"total"`
+		Contacts   []*models.Contact `json:"contacts"`
+		Total      int               `json:"total"`
+		NextCursor string            `json:"nextCursor"`
 	}
 
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
 	tests := []struct {
 		name       string
 		userID     int
+		query      string
 		setup      func(m *MockContactsService)
 		wantStatus int
 		wantBody   resp
@@ -47,11 +51,11 @@ func TestContactsHandler_Get(t *testing.T) {
 			userID: 1,
 			setup: func(m *MockContactsService) {
 				m.
-					On("GetContactsPageByUserID", mock.Anything, 1, mock.Anything, mock.Anything).
-					Return([]*models.Contact{{ID: 5, UserID: 1, Name: "A", Phone: "P"}}, nil).
+					On("GetContactsPageByUserID", mock.Anything, 1, domain.ContactFilter{}, domain.PageRequest{}).
+					Return([]*models.Contact{{ID: 5, UserID: 1, Name: "A", Phone: "P"}}, "", nil).
 					Once()
 				m.
-					On("GetContactsCountByUserID", mock.Anything, 1).
+					On("GetContactsCountByUserID", mock.Anything, 1, domain.ContactFilter{}).
 					Return(1, nil).
 					Once()
 			},
@@ -63,13 +67,80 @@ func TestContactsHandler_Get(t *testing.T) {
 				Total: 1,
 			},
 		},
+		{
+			name:   "filtered, sorted and paged by cursor",
+			userID: 1,
+			query:  "?search=ali&group=Sales&attr=City:Moscow&createdFrom=2025-01-01T00:00:00Z&sort=-name&limit=1&cursor=abc",
+			setup: func(m *MockContactsService) {
+				filter := domain.ContactFilter{
+					Search:      "ali",
+					Group:       "Sales",
+					Attributes:  map[string]string{"City": "Moscow"},
+					CreatedFrom: from,
+				}
+				m.
+					On("GetContactsPageByUserID", mock.Anything, 1, filter, domain.PageRequest{Limit: 1, Cursor: "abc", Sort: domain.Sort{Field: "name", Desc: true}}).
+					Return([]*models.Contact{{ID: 5, UserID: 1, Name: "Alice", Phone: "P"}}, "next", nil).
+					Once()
+				m.
+					On("GetContactsCountByUserID", mock.Anything, 1, filter).
+					Return(3, nil).
+					Once()
+			},
+			wantStatus: http.StatusOK,
+			wantBody: resp{
+				Contacts: []*models.Contact{
+					{ID: 5, UserID: 1, Name: "Alice", Phone: "P"},
+				},
+				Total:      3,
+				NextCursor: "next",
+			},
+		},
+		{
+			name:       "invalid created range",
+			userID:     1,
+			query:      "?createdTo=yesterday",
+			setup:      func(m *MockContactsService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid attribute filter",
+			userID:     1,
+			query:      "?attr=City",
+			setup:      func(m *MockContactsService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid sort",
+			userID: 1,
+			query:  "?sort=timezone",
+			setup: func(m *MockContactsService) {
+				m.
+					On("GetContactsPageByUserID", mock.Anything, 1, domain.ContactFilter{}, domain.PageRequest{Sort: domain.Sort{Field: "timezone"}}).
+					Return(([]*models.Contact)(nil), "", domain.ErrInvalidSort).
+					Once()
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid cursor",
+			userID: 1,
+			query:  "?cursor=xyz",
+			setup: func(m *MockContactsService) {
+				m.
+					On("GetContactsPageByUserID", mock.Anything, 1, domain.ContactFilter{}, domain.PageRequest{Cursor: "xyz"}).
+					Return(([]*models.Contact)(nil), "", domain.ErrInvalidCursor).
+					Once()
+			},
+			wantStatus: http.StatusBadRequest,
+		},
 		{
 			name:   "service error",
 			userID: 2,
 			setup: func(m *MockContactsService) {
 				m.
 					On("GetContactsPageByUserID", mock.Anything, 2, mock.Anything, mock.Anything).
-					Return(([]*models.Contact)(nil), assert.AnError).
+					Return(([]*models.Contact)(nil), "", assert.AnError).
 					Once()
 			},
 			wantStatus: http.StatusInternalServerError,
@@ -82,7 +153,7 @@ func TestContactsHandler_Get(t *testing.T) {
 			tc.setup(m)
 			h := handler.NewContactsHandler(m, logger, timeout)
 
-			req := httptest.NewRequest("GET", "/contacts", nil)
+			req := httptest.NewRequest("GET", "/contacts"+tc.query, nil)
 			req = injectUserID(req, tc.userID)
 			rr := httptest.NewRecorder()
 
@@ -99,6 +170,7 @@ func TestContactsHandler_Get(t *testing.T) {
 				assert.NoError(t, err)
 				assert.Equal(t, tc.wantBody, got)
 			}
+			m.AssertExpectations(t)
 		})
 	}
 }
@@ -199,7 +271,7 @@ func TestContactsHandler_Post(t *testing.T) {
 			body:   domain.PostContactRequest{Name: "", Phone: "P"},
 			setup: func(m *MockContactsService) {
 				m.
-					On("CreateContact", mock.Anything, mock.Anything).
+					On("CreateContact", mock.Anything, mock.Anything, "").
 					Return((*models.Contact)(nil), domain.ErrInvalidContact).
 					Once()
 			},
@@ -211,7 +283,7 @@ func TestContactsHandler_Post(t *testing.T) {
 			body:   domain.PostContactRequest{Name: "A", Phone: "P"},
 			setup: func(m *MockContactsService) {
 				m.
-					On("CreateContact", mock.Anything, &models.Contact{UserID: 1, Name: "A", Phone: "P"}).
+					On("CreateContact", mock.Anything, &models.Contact{UserID: 1, Name: "A", Phone: "P"}, "").
 					Return((*models.Contact)(nil), domain.ErrContactAlreadyExists).
 					Once()
 			},
@@ -223,13 +295,26 @@ func TestContactsHandler_Post(t *testing.T) {
 			body:   domain.PostContactRequest{Name: "A", Phone: "P"},
 			setup: func(m *MockContactsService) {
 				m.
-					On("CreateContact", mock.Anything, &models.Contact{UserID: 1, Name: "A", Phone: "P"}).
+					On("CreateContact", mock.Anything, &models.Contact{UserID: 1, Name: "A", Phone: "P"}, "").
 					Return(&models.Contact{ID: 9, UserID: 1, Name: "A", Phone: "P"}, nil).
 					Once()
 			},
 			wantStatus: http.StatusCreated,
 			wantBody:   &models.Contact{ID: 9, UserID: 1, Name: "A", Phone: "P"},
 		},
+		{
+			name:   "success with region",
+			userID: 1,
+			body:   domain.PostContactRequest{Name: "A", Phone: "P", Region: "US"},
+			setup: func(m *MockContactsService) {
+				m.
+					On("CreateContact", mock.Anything, &models.Contact{UserID: 1, Name: "A", Phone: "P"}, "US").
+					Return(&models.Contact{ID: 9, UserID: 1, Name: "A", Phone: "+1P"}, nil).
+					Once()
+			},
+			wantStatus: http.StatusCreated,
+			wantBody:   &models.Contact{ID: 9, UserID: 1, Name: "A", Phone: "+1P"},
+		},
 	}
 
 	for _, tc := range tests {
@@ -296,23 +381,37 @@ func TestContactsHandler_Put(t *testing.T) {
 			name:    "not found",
 			userID:  1,
 			idParam: "2",
-			body:    domain.PutContactRequest{Name: "N", Phone: "P"},
+			body:    map[string]string{"name": "N", "phone": "P"},
 			setup: func(m *MockContactsService) {
 				m.
-					On("UpdateContact", mock.Anything, 1, 2, &models.Contact{UserID: 1, Name: "N", Phone: "P"}).
+					On("UpdateContact", mock.Anything, 1, 2, &domain.PutContactRequest{Name: "N", Phone: "P"}).
 					Return((*models.Contact)(nil), domain.ErrContactNotExists).
 					Once()
 			},
 			wantStatus: http.StatusNotFound,
 		},
+		{
+			name:    "null location is told from an omitted one",
+			userID:  1,
+			idParam: "4",
+			body:    map[string]any{"name": "N", "phone": "P", "location": nil},
+			setup: func(m *MockContactsService) {
+				m.
+					On("UpdateContact", mock.Anything, 1, 4, &domain.PutContactRequest{Name: "N", Phone: "P", Location: domain.NullableLocation{Set: true}}).
+					Return(&models.Contact{ID: 4, UserID: 1, Name: "N", Phone: "P"}, nil).
+					Once()
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   &models.Contact{ID: 4, UserID: 1, Name: "N", Phone: "P"},
+		},
 		{
 			name:    "success",
 			userID:  1,
 			idParam: "3",
-			body:    domain.PutContactRequest{Name: "N", Phone: "P"},
+			body:    map[string]string{"name": "N", "phone": "P"},
 			setup: func(m *MockContactsService) {
 				m.
-					On("UpdateContact", mock.Anything, 1, 3, &models.Contact{UserID: 1, Name: "N", Phone: "P"}).
+					On("UpdateContact", mock.Anything, 1, 3, &domain.PutContactRequest{Name: "N", Phone: "P"}).
 					Return(&models.Contact{ID: 3, UserID: 1, Name: "N", Phone: "P"}, nil).
 					Once()
 			},
@@ -416,3 +515,299 @@ func TestContactsHandler_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestContactsHandler_PostBulk(t *testing.T) {
+	contacts := []domain.PostContactRequest{{Name: "A", Phone: "P"}, {Name: "", Phone: "Q"}}
+	result := &domain.BulkCreateContactsResponse{
+		Created: 1,
+		Invalid: 1,
+		Results: []*domain.BulkContactResult{
+			{Index: 0, Status: domain.BulkItemCreated, Contact: &models.Contact{ID: 9, UserID: 1, Name: "A", Phone: "P"}},
+			{Index: 1, Status: domain.BulkItemInvalid, Error: "invalid contact: invalid name"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		body       any
+		setup      func(m *MockContactsService)
+		wantStatus int
+		wantBody   *domain.BulkCreateContactsResponse
+	}{
+		{
+			name:       "invalid json",
+			body:       `{"contacts":`,
+			setup:      func(m *MockContactsService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "too many contacts",
+			body: domain.PostContactsBulkRequest{Contacts: contacts},
+			setup: func(m *MockContactsService) {
+				m.
+					On("CreateContacts", mock.Anything, 1, contacts).
+					Return(nil, domain.ErrTooManyContacts).
+					Once()
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "success",
+			body: domain.PostContactsBulkRequest{Contacts: contacts},
+			setup: func(m *MockContactsService) {
+				m.
+					On("CreateContacts", mock.Anything, 1, contacts).
+					Return(result, nil).
+					Once()
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   result,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockContactsService)
+			tc.setup(m)
+			h := handler.NewContactsHandler(m, logger, timeout)
+
+			var buf bytes.Buffer
+			if err := json.NewEncoder(&buf).Encode(tc.body); err != nil {
+				t.Fatal(err)
+			}
+
+			req := httptest.NewRequest("POST", "/contacts/bulk", &buf)
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.PostBulk(rr, req)
+			res := rr.Result()
+			defer func() {
+				_ = res.Body.Close()
+			}()
+
+			assert.Equal(t, tc.wantStatus, res.StatusCode)
+			if tc.wantBody != nil {
+				var got domain.BulkCreateContactsResponse
+				assert.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+				assert.Equal(t, tc.wantBody, &got)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestContactsHandler_PostBulkDelete(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		setup      func(m *MockContactsService)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "invalid json",
+			body:       `{"filter":`,
+			setup:      func(m *MockContactsService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "empty filter",
+			body: `{"filter":{}}`,
+			setup: func(m *MockContactsService) {
+				m.
+					On("DeleteContacts", mock.Anything, 1, domain.ContactFilter{}).
+					Return(0, domain.ErrEmptyContactFilter).
+					Once()
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "by ids",
+			body: `{"filter":{"ids":[3,4]}}`,
+			setup: func(m *MockContactsService) {
+				m.
+					On("DeleteContacts", mock.Anything, 1, domain.ContactFilter{IDs: []int{3, 4}}).
+					Return(2, nil).
+					Once()
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"affected":2}`,
+		},
+		{
+			name: "by group",
+			body: `{"filter":{"group":"Staff"}}`,
+			setup: func(m *MockContactsService) {
+				m.
+					On("DeleteContacts", mock.Anything, 1, domain.ContactFilter{Group: "Staff"}).
+					Return(5, nil).
+					Once()
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"affected":5}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockContactsService)
+			tc.setup(m)
+			h := handler.NewContactsHandler(m, logger, timeout)
+
+			req := httptest.NewRequest("POST", "/contacts/bulk/delete", bytes.NewBufferString(tc.body))
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.PostBulkDelete(rr, req)
+			res := rr.Result()
+			defer func() {
+				_ = res.Body.Close()
+			}()
+
+			assert.Equal(t, tc.wantStatus, res.StatusCode)
+			if tc.wantBody != "" {
+				assert.JSONEq(t, tc.wantBody, rr.Body.String())
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestContactsHandler_PostBulkGroup(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		setup      func(m *MockContactsService)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "group too long",
+			body: `{"filter":{"ids":[3]},"group":"x"}`,
+			setup: func(m *MockContactsService) {
+				m.
+					On("SetContactsGroup", mock.Anything, 1, domain.ContactFilter{IDs: []int{3}}, "x").
+					Return(0, domain.ErrInvalidContactGroup).
+					Once()
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "too many ids",
+			body: `{"filter":{"ids":[3,4]},"group":"x"}`,
+			setup: func(m *MockContactsService) {
+				m.
+					On("SetContactsGroup", mock.Anything, 1, domain.ContactFilter{IDs: []int{3, 4}}, "x").
+					Return(0, domain.ErrTooManyContacts).
+					Once()
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "success",
+			body: `{"filter":{"search":"ali"},"group":"Staff"}`,
+			setup: func(m *MockContactsService) {
+				m.
+					On("SetContactsGroup", mock.Anything, 1, domain.ContactFilter{Search: "ali"}, "Staff").
+					Return(2, nil).
+					Once()
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"affected":2}`,
+		},
+		{
+			name: "service error",
+			body: `{"filter":{"search":"ali"},"group":"Staff"}`,
+			setup: func(m *MockContactsService) {
+				m.
+					On("SetContactsGroup", mock.Anything, 1, domain.ContactFilter{Search: "ali"}, "Staff").
+					Return(0, assert.AnError).
+					Once()
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockContactsService)
+			tc.setup(m)
+			h := handler.NewContactsHandler(m, logger, timeout)
+
+			req := httptest.NewRequest("POST", "/contacts/bulk/group", bytes.NewBufferString(tc.body))
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.PostBulkGroup(rr, req)
+			res := rr.Result()
+			defer func() {
+				_ = res.Body.Close()
+			}()
+
+			assert.Equal(t, tc.wantStatus, res.StatusCode)
+			if tc.wantBody != "" {
+				assert.JSONEq(t, tc.wantBody, rr.Body.String())
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestContactsHandler_DeleteAll(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		setup      func(m *MockContactsService)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "without all",
+			query:      "",
+			setup:      func(m *MockContactsService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "all false",
+			query:      "?all=false",
+			setup:      func(m *MockContactsService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "success",
+			query: "?all=true",
+			setup: func(m *MockContactsService) {
+				m.
+					On("DeleteAllContacts", mock.Anything, 1).
+					Return(7, nil).
+					Once()
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"affected":7}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockContactsService)
+			tc.setup(m)
+			h := handler.NewContactsHandler(m, logger, timeout)
+
+			req := httptest.NewRequest("DELETE", "/contacts"+tc.query, nil)
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.DeleteAll(rr, req)
+			res := rr.Result()
+			defer func() {
+				_ = res.Body.Close()
+			}()
+
+			assert.Equal(t, tc.wantStatus, res.StatusCode)
+			if tc.wantBody != "" {
+				assert.JSONEq(t, tc.wantBody, rr.Body.String())
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
@@ -2,18 +2,28 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/contextkeys"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
 	"go.uber.org/zap"
 )
 
+// contactFileExtensions are the extensions of the contact files the contacts worker can read.
+// vCard, JSON and NDJSON files are plain text like CSV and are told apart by the worker.
+var contactFileExtensions = []string{".csv", ".xlsx", ".vcf", ".json", ".ndjson", ".jsonl"}
+
 // LoadContactsHandler handles multipart file uploads for loading contacts into S3
 // and enqueuing background processing tasks via Kafka.
 type LoadContactsHandler struct {
@@ -46,6 +56,12 @@ func (lch *LoadContactsHandler) logError(msg string, r *http.Request, fields ...
 }
 
 // LoadContactsFile handles POST /load-contacts requests with a multipart "file" field.
+// Optional "name" and "phone" fields map the contact fields to columns of the file,
+// by header name or 1-based column number. An optional "region" overrides the user's
+// default region for phone numbers without a country code. An optional "mode" is one of
+// insert-only (the default), upsert or sync, and "dryRun=true" only reports the changes.
+// Repeated "sheet" fields select the sheets of an XLSX workbook, see parseSheetSelection.
+// Responds with 202 Accepted and the queued import job, whose status is served at /imports/{id}.
 func (lch *LoadContactsHandler) LoadContactsFile(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), lch.contextTimeout)
 	defer cancel()
@@ -79,8 +95,8 @@ func (lch *LoadContactsHandler) LoadContactsFile(w http.ResponseWriter, r *http.
 	}(file)
 
 	ext := strings.ToLower(filepath.Ext(header.Filename))
-	if ext != ".csv" && ext != ".xlsx" {
-		http.Error(w, "Only csv and xlsx files allowed", http.StatusUnprocessableEntity)
+	if !slices.Contains(contactFileExtensions, ext) {
+		http.Error(w, "Only csv, xlsx, vcf, json and ndjson files allowed", http.StatusUnprocessableEntity)
 		return
 	}
 
@@ -89,7 +105,7 @@ func (lch *LoadContactsHandler) LoadContactsFile(w http.ResponseWriter, r *http.
 	contentType := http.DetectContentType(buf[:n])
 
 	switch contentType {
-	case "text/plain; charset=utf-8", "text/csv":
+	case "text/plain; charset=utf-8", "text/plain; charset=utf-16le", "text/plain; charset=utf-16be", "text/csv":
 	case "application/zip":
 	default:
 		http.Error(w, "Invalid file type", http.StatusUnprocessableEntity)
@@ -109,12 +125,72 @@ func (lch *LoadContactsHandler) LoadContactsFile(w http.ResponseWriter, r *http.
 		return
 	}
 
-	err = lch.service.ProcessUpload(ctx, userID, header.Filename, file)
+	opts := domain.ImportOptions{
+		Mapping: domain.ColumnMapping{
+			Name:  strings.TrimSpace(r.FormValue("name")),
+			Phone: strings.TrimSpace(r.FormValue("phone")),
+		},
+		Region: strings.TrimSpace(r.FormValue("region")),
+		Mode:   models.ImportMode(strings.TrimSpace(r.FormValue("mode"))),
+	}
+
+	opts.Sheets = parseSheetSelection(r.Form["sheet"])
+
+	if rawDryRun := strings.TrimSpace(r.FormValue("dryRun")); rawDryRun != "" {
+		opts.DryRun, err = strconv.ParseBool(rawDryRun)
+		if err != nil {
+			http.Error(w, "Invalid dryRun", http.StatusUnprocessableEntity)
+			return
+		}
+	}
+
+	contactImport, err := lch.service.ProcessUpload(ctx, userID, header.Filename, opts, file)
 	if err != nil {
-		lch.logError("failed to process contacts file upload", r, zap.Int("user_id", userID), zap.Error(err))
-		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		switch {
+		case errors.Is(err, domain.ErrInvalidPhoneRegion):
+			http.Error(w, "Invalid region", http.StatusUnprocessableEntity)
+		case errors.Is(err, domain.ErrInvalidImportMode):
+			http.Error(w, "Invalid import mode", http.StatusUnprocessableEntity)
+		case errors.Is(err, domain.ErrInvalidSheetSelection):
+			http.Error(w, "Invalid sheet", http.StatusUnprocessableEntity)
+		default:
+			lch.logError("failed to process contacts file upload", r, zap.Int("user_id", userID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("/imports/%d", contactImport.ID))
 	w.WriteHeader(http.StatusAccepted)
+	err = json.NewEncoder(w).Encode(contactImport)
+	if err != nil {
+		lch.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// parseSheetSelection reads "sheet" form values. Each one is a sheet name, optionally followed
+// by ":" and the contact group of the sheet, or "*" for every sheet. Neither "*" nor ":" may
+// appear in Excel sheet names, so "*" together with "Sales:Sales team" imports all sheets and
+// groups the contacts of the Sales sheet.
+func parseSheetSelection(values []string) domain.SheetSelection {
+	var sheets domain.SheetSelection
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "*" {
+			sheets.All = true
+			continue
+		}
+
+		name, group, hasGroup := strings.Cut(v, ":")
+		name = strings.TrimSpace(name)
+		sheets.Names = append(sheets.Names, name)
+		if hasGroup {
+			if sheets.Groups == nil {
+				sheets.Groups = make(map[string]string)
+			}
+			sheets.Groups[name] = strings.TrimSpace(group)
+		}
+	}
+	return sheets
 }
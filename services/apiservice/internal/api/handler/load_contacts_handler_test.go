@@ -13,14 +13,19 @@ import (
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/contextkeys"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
-func makeMultipartRequest(t *testing.T, fieldName, fileName string, content []byte) *http.Request {
+func makeMultipartRequest(t *testing.T, fieldName, fileName string, content []byte, fields ...string) *http.Request {
 	var buf bytes.Buffer
 	w := multipart.NewWriter(&buf)
+	for i := 0; i+1 < len(fields); i += 2 {
+		require.NoError(t, w.WriteField(fields[i], fields[i+1]))
+	}
 	fw, err := w.CreateFormFile(fieldName, fileName)
 	require.NoError(t, err)
 	_, err = fw.Write(content)
@@ -42,6 +47,7 @@ func TestLoadContactsHandler_LoadContactsFile(t *testing.T) {
 		buildRequest   func() *http.Request
 		setupMock      func(s *MockLoadContactsService)
 		wantStatusCode int
+		wantLocation   string
 	}{
 		{
 			name:         "missing userID in context",
@@ -119,8 +125,8 @@ func TestLoadContactsHandler_LoadContactsFile(t *testing.T) {
 			},
 			setupMock: func(m *MockLoadContactsService) {
 				m.
-					On("ProcessUpload", mock.Anything, 42, "data.csv", mock.Anything).
-					Return(errors.New("oops")).
+					On("ProcessUpload", mock.Anything, 42, "data.csv", domain.ImportOptions{}, mock.Anything).
+					Return((*models.ContactImport)(nil), errors.New("oops")).
 					Once()
 			},
 			wantStatusCode: http.StatusInternalServerError,
@@ -136,11 +142,213 @@ func TestLoadContactsHandler_LoadContactsFile(t *testing.T) {
 			},
 			setupMock: func(m *MockLoadContactsService) {
 				m.
-					On("ProcessUpload", mock.Anything, 7, "data.csv", mock.Anything).
-					Return(nil).
+					On("ProcessUpload", mock.Anything, 7, "data.csv", domain.ImportOptions{}, mock.Anything).
+					Return(&models.ContactImport{ID: 3, UserID: 7, Filename: "data.csv", Status: models.ImportStatusQueued}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusAccepted,
+			wantLocation:   "/imports/3",
+		},
+		{
+			name: "success with column mapping",
+			setupContext: func(r *http.Request) {
+				ctx := context.WithValue(r.Context(), contextkeys.UserID, 7)
+				*r = *r.WithContext(ctx)
+			},
+			buildRequest: func() *http.Request {
+				return makeMultipartRequest(t, "file", "data.csv", []byte("Full Name,Mobile\nAlice,+79123456789"),
+					"name", "Full Name", "phone", " Mobile ")
+			},
+			setupMock: func(m *MockLoadContactsService) {
+				m.
+					On("ProcessUpload", mock.Anything, 7, "data.csv", domain.ImportOptions{Mapping: domain.ColumnMapping{Name: "Full Name", Phone: "Mobile"}}, mock.Anything).
+					Return(&models.ContactImport{ID: 5, UserID: 7, Filename: "data.csv", Status: models.ImportStatusQueued}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusAccepted,
+			wantLocation:   "/imports/5",
+		},
+		{
+			name: "success with region",
+			setupContext: func(r *http.Request) {
+				ctx := context.WithValue(r.Context(), contextkeys.UserID, 7)
+				*r = *r.WithContext(ctx)
+			},
+			buildRequest: func() *http.Request {
+				return makeMultipartRequest(t, "file", "data.csv", []byte("Alice,(202) 555-0123"), "region", "US")
+			},
+			setupMock: func(m *MockLoadContactsService) {
+				m.
+					On("ProcessUpload", mock.Anything, 7, "data.csv", domain.ImportOptions{Region: "US"}, mock.Anything).
+					Return(&models.ContactImport{ID: 8, UserID: 7, Filename: "data.csv", Status: models.ImportStatusQueued}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusAccepted,
+			wantLocation:   "/imports/8",
+		},
+		{
+			name: "invalid region",
+			setupContext: func(r *http.Request) {
+				ctx := context.WithValue(r.Context(), contextkeys.UserID, 7)
+				*r = *r.WithContext(ctx)
+			},
+			buildRequest: func() *http.Request {
+				return makeMultipartRequest(t, "file", "data.csv", []byte("Alice,+79123456789"), "region", "XX")
+			},
+			setupMock: func(m *MockLoadContactsService) {
+				m.
+					On("ProcessUpload", mock.Anything, 7, "data.csv", domain.ImportOptions{Region: "XX"}, mock.Anything).
+					Return((*models.ContactImport)(nil), domain.ErrInvalidPhoneRegion).
+					Once()
+			},
+			wantStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "sync dry run",
+			setupContext: func(r *http.Request) {
+				ctx := context.WithValue(r.Context(), contextkeys.UserID, 7)
+				*r = *r.WithContext(ctx)
+			},
+			buildRequest: func() *http.Request {
+				return makeMultipartRequest(t, "file", "data.csv", []byte("Alice,+79123456789"), "mode", "sync", "dryRun", "true")
+			},
+			setupMock: func(m *MockLoadContactsService) {
+				m.
+					On("ProcessUpload", mock.Anything, 7, "data.csv", domain.ImportOptions{Mode: models.ImportModeSync, DryRun: true}, mock.Anything).
+					Return(&models.ContactImport{ID: 8, UserID: 7, Filename: "data.csv", Mode: models.ImportModeSync, DryRun: true, Status: models.ImportStatusQueued}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusAccepted,
+			wantLocation:   "/imports/8",
+		},
+		{
+			name: "xlsx sheets with groups",
+			setupContext: func(r *http.Request) {
+				ctx := context.WithValue(r.Context(), contextkeys.UserID, 7)
+				*r = *r.WithContext(ctx)
+			},
+			buildRequest: func() *http.Request {
+				return makeMultipartRequest(t, "file", "staff.xlsx", []byte("PK\x03\x04"), "sheet", "*", "sheet", " Sales : Sales team ")
+			},
+			setupMock: func(m *MockLoadContactsService) {
+				opts := domain.ImportOptions{Sheets: domain.SheetSelection{
+					All:    true,
+					Names:  []string{"Sales"},
+					Groups: map[string]string{"Sales": "Sales team"},
+				}}
+				m.
+					On("ProcessUpload", mock.Anything, 7, "staff.xlsx", opts, mock.Anything).
+					Return(&models.ContactImport{ID: 8, UserID: 7, Filename: "staff.xlsx", Status: models.ImportStatusQueued}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusAccepted,
+			wantLocation:   "/imports/8",
+		},
+		{
+			name: "invalid sheet",
+			setupContext: func(r *http.Request) {
+				ctx := context.WithValue(r.Context(), contextkeys.UserID, 7)
+				*r = *r.WithContext(ctx)
+			},
+			buildRequest: func() *http.Request {
+				return makeMultipartRequest(t, "file", "staff.xlsx", []byte("PK\x03\x04"), "sheet", ":Sales team")
+			},
+			setupMock: func(m *MockLoadContactsService) {
+				opts := domain.ImportOptions{Sheets: domain.SheetSelection{
+					Names:  []string{""},
+					Groups: map[string]string{"": "Sales team"},
+				}}
+				m.
+					On("ProcessUpload", mock.Anything, 7, "staff.xlsx", opts, mock.Anything).
+					Return((*models.ContactImport)(nil), domain.ErrInvalidSheetSelection).
+					Once()
+			},
+			wantStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "invalid dryRun",
+			setupContext: func(r *http.Request) {
+				ctx := context.WithValue(r.Context(), contextkeys.UserID, 7)
+				*r = *r.WithContext(ctx)
+			},
+			buildRequest: func() *http.Request {
+				return makeMultipartRequest(t, "file", "data.csv", []byte("Alice,+79123456789"), "dryRun", "maybe")
+			},
+			setupMock:      func(m *MockLoadContactsService) {},
+			wantStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "invalid mode",
+			setupContext: func(r *http.Request) {
+				ctx := context.WithValue(r.Context(), contextkeys.UserID, 7)
+				*r = *r.WithContext(ctx)
+			},
+			buildRequest: func() *http.Request {
+				return makeMultipartRequest(t, "file", "data.csv", []byte("Alice,+79123456789"), "mode", "replace")
+			},
+			setupMock: func(m *MockLoadContactsService) {
+				m.
+					On("ProcessUpload", mock.Anything, 7, "data.csv", domain.ImportOptions{Mode: "replace"}, mock.Anything).
+					Return((*models.ContactImport)(nil), domain.ErrInvalidImportMode).
+					Once()
+			},
+			wantStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "success .csv in UTF-16LE",
+			setupContext: func(r *http.Request) {
+				ctx := context.WithValue(r.Context(), contextkeys.UserID, 7)
+				*r = *r.WithContext(ctx)
+			},
+			buildRequest: func() *http.Request {
+				// byte order mark followed by "a,b" in UTF-16LE, as saved by Excel's "Unicode text"
+				content := []byte("\xFF\xFEa\x00,\x00b\x00")
+				return makeMultipartRequest(t, "file", "data.csv", content)
+			},
+			setupMock: func(m *MockLoadContactsService) {
+				m.
+					On("ProcessUpload", mock.Anything, 7, "data.csv", domain.ImportOptions{}, mock.Anything).
+					Return(&models.ContactImport{ID: 6, UserID: 7, Filename: "data.csv", Status: models.ImportStatusQueued}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusAccepted,
+			wantLocation:   "/imports/6",
+		},
+		{
+			name: "success .vcf",
+			setupContext: func(r *http.Request) {
+				ctx := context.WithValue(r.Context(), contextkeys.UserID, 7)
+				*r = *r.WithContext(ctx)
+			},
+			buildRequest: func() *http.Request {
+				return makeMultipartRequest(t, "file", "phone.vcf", []byte("BEGIN:VCARD\r\nFN:Alice\r\nTEL:+79123456789\r\nEND:VCARD\r\n"))
+			},
+			setupMock: func(m *MockLoadContactsService) {
+				m.
+					On("ProcessUpload", mock.Anything, 7, "phone.vcf", domain.ImportOptions{}, mock.Anything).
+					Return(&models.ContactImport{ID: 9, UserID: 7, Filename: "phone.vcf", Status: models.ImportStatusQueued}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusAccepted,
+			wantLocation:   "/imports/9",
+		},
+		{
+			name: "success .ndjson",
+			setupContext: func(r *http.Request) {
+				ctx := context.WithValue(r.Context(), contextkeys.UserID, 7)
+				*r = *r.WithContext(ctx)
+			},
+			buildRequest: func() *http.Request {
+				return makeMultipartRequest(t, "file", "export.ndjson", []byte(`{"name":"Alice","phone":"+79123456789"}`+"\n"))
+			},
+			setupMock: func(m *MockLoadContactsService) {
+				m.
+					On("ProcessUpload", mock.Anything, 7, "export.ndjson", domain.ImportOptions{}, mock.Anything).
+					Return(&models.ContactImport{ID: 10, UserID: 7, Filename: "export.ndjson", Status: models.ImportStatusQueued}, nil).
 					Once()
 			},
 			wantStatusCode: http.StatusAccepted,
+			wantLocation:   "/imports/10",
 		},
 		{
 			name: "success .xlsx (ZIP content)",
@@ -155,11 +363,12 @@ func TestLoadContactsHandler_LoadContactsFile(t *testing.T) {
 			},
 			setupMock: func(m *MockLoadContactsService) {
 				m.
-					On("ProcessUpload", mock.Anything, 99, "sheet.xlsx", mock.Anything).
-					Return(nil).
+					On("ProcessUpload", mock.Anything, 99, "sheet.xlsx", domain.ImportOptions{}, mock.Anything).
+					Return(&models.ContactImport{ID: 4, UserID: 99, Filename: "sheet.xlsx", Status: models.ImportStatusQueued}, nil).
 					Once()
 			},
 			wantStatusCode: http.StatusAccepted,
+			wantLocation:   "/imports/4",
 		},
 	}
 
@@ -178,6 +387,7 @@ func TestLoadContactsHandler_LoadContactsFile(t *testing.T) {
 			m.AssertExpectations(t)
 
 			require.Equal(t, tc.wantStatusCode, rr.Code)
+			require.Equal(t, tc.wantLocation, rr.Header().Get("Location"))
 		})
 	}
 }
@@ -4,8 +4,10 @@ import (
 	"context"
 	"io"
 
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/cap"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -13,14 +15,14 @@ type MockContactsService struct {
 	mock.Mock
 }
 
-func (m *MockContactsService) GetContactsCountByUserID(ctx context.Context, userID int) (int, error) {
-	args := m.Called(ctx, userID)
+func (m *MockContactsService) GetContactsCountByUserID(ctx context.Context, userID int, filter domain.ContactFilter) (int, error) {
+	args := m.Called(ctx, userID, filter)
 	return args.Get(0).(int), args.Error(1)
 }
 
-func (m *MockContactsService) GetContactsPageByUserID(ctx context.Context, userID, limit, offset int) ([]*models.Contact, error) {
-	args := m.Called(ctx, userID, limit, offset)
-	return args.Get(0).([]*models.Contact), args.Error(1)
+func (m *MockContactsService) GetContactsPageByUserID(ctx context.Context, userID int, filter domain.ContactFilter, page domain.PageRequest) ([]*models.Contact, string, error) {
+	args := m.Called(ctx, userID, filter, page)
+	return args.Get(0).([]*models.Contact), args.String(1), args.Error(2)
 }
 
 func (m *MockContactsService) GetContactByID(ctx context.Context, userID, contactID int) (*models.Contact, error) {
@@ -28,13 +30,13 @@ func (m *MockContactsService) GetContactByID(ctx context.Context, userID, contac
 	return args.Get(0).(*models.Contact), args.Error(1)
 }
 
-func (m *MockContactsService) CreateContact(ctx context.Context, contact *models.Contact) (*models.Contact, error) {
-	args := m.Called(ctx, contact)
+func (m *MockContactsService) CreateContact(ctx context.Context, contact *models.Contact, region string) (*models.Contact, error) {
+	args := m.Called(ctx, contact, region)
 	return args.Get(0).(*models.Contact), args.Error(1)
 }
 
-func (m *MockContactsService) UpdateContact(ctx context.Context, userID, contactID int, contact *models.Contact) (*models.Contact, error) {
-	args := m.Called(ctx, userID, contactID, contact)
+func (m *MockContactsService) UpdateContact(ctx context.Context, userID, contactID int, req *domain.PutContactRequest) (*models.Contact, error) {
+	args := m.Called(ctx, userID, contactID, req)
 	return args.Get(0).(*models.Contact), args.Error(1)
 }
 
@@ -42,6 +44,27 @@ func (m *MockContactsService) DeleteContact(ctx context.Context, userID, contact
 	return m.Called(ctx, userID, contactID).Error(0)
 }
 
+func (m *MockContactsService) CreateContacts(ctx context.Context, userID int, contacts []domain.PostContactRequest) (*domain.BulkCreateContactsResponse, error) {
+	args := m.Called(ctx, userID, contacts)
+	resp, _ := args.Get(0).(*domain.BulkCreateContactsResponse)
+	return resp, args.Error(1)
+}
+
+func (m *MockContactsService) DeleteContacts(ctx context.Context, userID int, filter domain.ContactFilter) (int, error) {
+	args := m.Called(ctx, userID, filter)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockContactsService) DeleteAllContacts(ctx context.Context, userID int) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockContactsService) SetContactsGroup(ctx context.Context, userID int, filter domain.ContactFilter, group string) (int, error) {
+	args := m.Called(ctx, userID, filter, group)
+	return args.Int(0), args.Error(1)
+}
+
 type MockHealthCheckService struct {
 	mock.Mock
 }
@@ -54,8 +77,9 @@ type MockLoadContactsService struct {
 	mock.Mock
 }
 
-func (m *MockLoadContactsService) ProcessUpload(ctx context.Context, userID int, filename string, payload io.ReadSeeker) error {
-	return m.Called(ctx, userID, filename, payload).Error(0)
+func (m *MockLoadContactsService) ProcessUpload(ctx context.Context, userID int, filename string, opts domain.ImportOptions, payload io.ReadSeeker) (*models.ContactImport, error) {
+	args := m.Called(ctx, userID, filename, opts, payload)
+	return args.Get(0).(*models.ContactImport), args.Error(1)
 }
 
 type MockLoginService struct {
@@ -80,6 +104,11 @@ func (m *MockProfileService) GetUserByID(ctx context.Context, id int) (*models.U
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
+func (m *MockProfileService) UpdateDefaultRegion(ctx context.Context, id int, region string) (*models.User, error) {
+	args := m.Called(ctx, id, region)
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
 type MockRefreshTokenService struct {
 	mock.Mock
 }
@@ -93,8 +122,23 @@ type MockSendNotificationService struct {
 	mock.Mock
 }
 
-func (m *MockSendNotificationService) SendNotification(ctx context.Context, userID, templateID int) error {
-	return m.Called(ctx, userID, templateID).Error(0)
+func (m *MockSendNotificationService) SendNotification(ctx context.Context, userID, templateID int, opts *domain.SendNotificationRequest) error {
+	return m.Called(ctx, userID, templateID, opts).Error(0)
+}
+
+func (m *MockSendNotificationService) SendMessage(ctx context.Context, userID int, campaignID uuid.UUID, text string, opts *domain.SendNotificationRequest) error {
+	return m.Called(ctx, userID, campaignID, text, opts).Error(0)
+}
+
+func (m *MockSendNotificationService) CountRecipients(ctx context.Context, userID int, opts *domain.SendNotificationRequest) (int, error) {
+	args := m.Called(ctx, userID, opts)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockSendNotificationService) PreviewNotification(ctx context.Context, userID, templateID int, opts *domain.SendNotificationRequest) (*domain.CampaignPreview, error) {
+	args := m.Called(ctx, userID, templateID, opts)
+	preview, _ := args.Get(0).(*domain.CampaignPreview)
+	return preview, args.Error(1)
 }
 
 type MockSignupService struct {
@@ -110,14 +154,14 @@ type MockTemplateService struct {
 	mock.Mock
 }
 
-func (m *MockTemplateService) GetTemplatesCountByUserID(ctx context.Context, userID int) (int, error) {
-	args := m.Called(ctx, userID)
+func (m *MockTemplateService) GetTemplatesCountByUserID(ctx context.Context, userID int, filter domain.TemplateFilter) (int, error) {
+	args := m.Called(ctx, userID, filter)
 	return args.Get(0).(int), args.Error(1)
 }
 
-func (m *MockTemplateService) GetTemplatesPageByUserID(ctx context.Context, userID, limit, offset int) ([]*models.Template, error) {
-	args := m.Called(ctx, userID, limit, offset)
-	return args.Get(0).([]*models.Template), args.Error(1)
+func (m *MockTemplateService) GetTemplatesPageByUserID(ctx context.Context, userID int, filter domain.TemplateFilter, page domain.PageRequest) ([]*models.Template, string, error) {
+	args := m.Called(ctx, userID, filter, page)
+	return args.Get(0).([]*models.Template), args.String(1), args.Error(2)
 }
 
 func (m *MockTemplateService) GetTemplateByID(ctx context.Context, userID int, tmplID int) (*models.Template, error) {
@@ -139,3 +183,374 @@ func (m *MockTemplateService) DeleteTemplate(ctx context.Context, userID int, tm
 	args := m.Called(ctx, userID, tmplID)
 	return args.Error(0)
 }
+
+func (m *MockTemplateService) GetTemplateVersions(ctx context.Context, userID, tmplID int) ([]*models.TemplateVersion, error) {
+	args := m.Called(ctx, userID, tmplID)
+	versions, _ := args.Get(0).([]*models.TemplateVersion)
+	return versions, args.Error(1)
+}
+
+func (m *MockTemplateService) GetTemplateVersion(ctx context.Context, userID, tmplID, version int) (*models.TemplateVersion, error) {
+	args := m.Called(ctx, userID, tmplID, version)
+	v, _ := args.Get(0).(*models.TemplateVersion)
+	return v, args.Error(1)
+}
+
+func (m *MockTemplateService) DiffTemplateVersions(ctx context.Context, userID, tmplID, from, to int) (*domain.TemplateDiff, error) {
+	args := m.Called(ctx, userID, tmplID, from, to)
+	diff, _ := args.Get(0).(*domain.TemplateDiff)
+	return diff, args.Error(1)
+}
+
+func (m *MockTemplateService) RestoreTemplateVersion(ctx context.Context, userID, tmplID, version int) (*models.Template, error) {
+	args := m.Called(ctx, userID, tmplID, version)
+	tmpl, _ := args.Get(0).(*models.Template)
+	return tmpl, args.Error(1)
+}
+
+type MockRetryPolicyService struct {
+	mock.Mock
+}
+
+func (m *MockRetryPolicyService) GetRetryPolicy(ctx context.Context, userID int) (*models.RetryPolicy, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(*models.RetryPolicy), args.Error(1)
+}
+
+func (m *MockRetryPolicyService) UpdateRetryPolicy(ctx context.Context, userID int, policy *models.RetryPolicy) (*models.RetryPolicy, error) {
+	args := m.Called(ctx, userID, policy)
+	return args.Get(0).(*models.RetryPolicy), args.Error(1)
+}
+
+type MockQuietHoursService struct {
+	mock.Mock
+}
+
+func (m *MockQuietHoursService) GetQuietHours(ctx context.Context, userID int) (*models.QuietHours, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(*models.QuietHours), args.Error(1)
+}
+
+func (m *MockQuietHoursService) UpdateQuietHours(ctx context.Context, userID int, qh *models.QuietHours) (*models.QuietHours, error) {
+	args := m.Called(ctx, userID, qh)
+	return args.Get(0).(*models.QuietHours), args.Error(1)
+}
+
+func (m *MockQuietHoursService) DeleteQuietHours(ctx context.Context, userID int) error {
+	return m.Called(ctx, userID).Error(0)
+}
+
+type MockContactImportService struct {
+	mock.Mock
+}
+
+func (m *MockContactImportService) GetContactImport(ctx context.Context, userID, importID int) (*models.ContactImport, error) {
+	args := m.Called(ctx, userID, importID)
+	return args.Get(0).(*models.ContactImport), args.Error(1)
+}
+
+func (m *MockContactImportService) GetRejectionReport(ctx context.Context, userID, importID int) (io.ReadCloser, error) {
+	args := m.Called(ctx, userID, importID)
+	report, _ := args.Get(0).(io.ReadCloser)
+	return report, args.Error(1)
+}
+
+func (m *MockContactImportService) GetDeletionsReport(ctx context.Context, userID, importID int) (io.ReadCloser, error) {
+	args := m.Called(ctx, userID, importID)
+	report, _ := args.Get(0).(io.ReadCloser)
+	return report, args.Error(1)
+}
+
+type MockContactExportService struct {
+	mock.Mock
+}
+
+// ExportContacts writes the string given to Return to w.
+func (m *MockContactExportService) ExportContacts(ctx context.Context, userID int, format models.ExportFormat, filter domain.ContactFilter, w io.Writer) error {
+	args := m.Called(ctx, userID, format, filter)
+	_, _ = io.WriteString(w, args.String(0))
+	return args.Error(1)
+}
+
+func (m *MockContactExportService) StartContactExport(ctx context.Context, userID int, format models.ExportFormat, filter domain.ContactFilter) (*models.ContactExport, error) {
+	args := m.Called(ctx, userID, format, filter)
+	export, _ := args.Get(0).(*models.ContactExport)
+	return export, args.Error(1)
+}
+
+func (m *MockContactExportService) GetContactExport(ctx context.Context, userID, exportID int) (*models.ContactExport, error) {
+	args := m.Called(ctx, userID, exportID)
+	export, _ := args.Get(0).(*models.ContactExport)
+	return export, args.Error(1)
+}
+
+type MockContactAttributeService struct {
+	mock.Mock
+}
+
+func (m *MockContactAttributeService) GetContactAttributes(ctx context.Context, userID int) ([]*models.ContactAttribute, error) {
+	args := m.Called(ctx, userID)
+	attrs, _ := args.Get(0).([]*models.ContactAttribute)
+	return attrs, args.Error(1)
+}
+
+func (m *MockContactAttributeService) CreateContactAttribute(ctx context.Context, attr *models.ContactAttribute) (*models.ContactAttribute, error) {
+	args := m.Called(ctx, attr)
+	created, _ := args.Get(0).(*models.ContactAttribute)
+	return created, args.Error(1)
+}
+
+func (m *MockContactAttributeService) UpdateContactAttribute(ctx context.Context, userID, attrID int, attr *models.ContactAttribute) (*models.ContactAttribute, error) {
+	args := m.Called(ctx, userID, attrID, attr)
+	updated, _ := args.Get(0).(*models.ContactAttribute)
+	return updated, args.Error(1)
+}
+
+func (m *MockContactAttributeService) DeleteContactAttribute(ctx context.Context, userID, attrID int) error {
+	args := m.Called(ctx, userID, attrID)
+	return args.Error(0)
+}
+
+type MockSegmentService struct {
+	mock.Mock
+}
+
+func (m *MockSegmentService) GetSegmentsByUserID(ctx context.Context, userID int) ([]*models.Segment, error) {
+	args := m.Called(ctx, userID)
+	segments, _ := args.Get(0).([]*models.Segment)
+	return segments, args.Error(1)
+}
+
+func (m *MockSegmentService) GetSegmentByID(ctx context.Context, userID, segmentID int) (*models.Segment, error) {
+	args := m.Called(ctx, userID, segmentID)
+	segment, _ := args.Get(0).(*models.Segment)
+	return segment, args.Error(1)
+}
+
+func (m *MockSegmentService) CreateSegment(ctx context.Context, segment *models.Segment) (*models.Segment, error) {
+	args := m.Called(ctx, segment)
+	created, _ := args.Get(0).(*models.Segment)
+	return created, args.Error(1)
+}
+
+func (m *MockSegmentService) UpdateSegment(ctx context.Context, userID, segmentID int, segment *models.Segment) (*models.Segment, error) {
+	args := m.Called(ctx, userID, segmentID, segment)
+	updated, _ := args.Get(0).(*models.Segment)
+	return updated, args.Error(1)
+}
+
+func (m *MockSegmentService) DeleteSegment(ctx context.Context, userID, segmentID int) error {
+	args := m.Called(ctx, userID, segmentID)
+	return args.Error(0)
+}
+
+func (m *MockSegmentService) PreviewRule(ctx context.Context, userID int, rule string) (*domain.SegmentPreview, error) {
+	args := m.Called(ctx, userID, rule)
+	preview, _ := args.Get(0).(*domain.SegmentPreview)
+	return preview, args.Error(1)
+}
+
+func (m *MockSegmentService) PreviewSegment(ctx context.Context, userID, segmentID int) (*domain.SegmentPreview, error) {
+	args := m.Called(ctx, userID, segmentID)
+	preview, _ := args.Get(0).(*domain.SegmentPreview)
+	return preview, args.Error(1)
+}
+
+type MockZoneService struct {
+	mock.Mock
+}
+
+func (m *MockZoneService) GetZonesByUserID(ctx context.Context, userID int) ([]*models.Zone, error) {
+	args := m.Called(ctx, userID)
+	zones, _ := args.Get(0).([]*models.Zone)
+	return zones, args.Error(1)
+}
+
+func (m *MockZoneService) GetZoneByID(ctx context.Context, userID, zoneID int) (*models.Zone, error) {
+	args := m.Called(ctx, userID, zoneID)
+	zone, _ := args.Get(0).(*models.Zone)
+	return zone, args.Error(1)
+}
+
+func (m *MockZoneService) CreateZone(ctx context.Context, zone *models.Zone) (*models.Zone, error) {
+	args := m.Called(ctx, zone)
+	created, _ := args.Get(0).(*models.Zone)
+	return created, args.Error(1)
+}
+
+func (m *MockZoneService) UpdateZone(ctx context.Context, userID, zoneID int, zone *models.Zone) (*models.Zone, error) {
+	args := m.Called(ctx, userID, zoneID, zone)
+	updated, _ := args.Get(0).(*models.Zone)
+	return updated, args.Error(1)
+}
+
+func (m *MockZoneService) DeleteZone(ctx context.Context, userID, zoneID int) error {
+	args := m.Called(ctx, userID, zoneID)
+	return args.Error(0)
+}
+
+type MockAPIKeyService struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyService) GetAPIKeysByUserID(ctx context.Context, userID int) ([]*models.APIKey, error) {
+	args := m.Called(ctx, userID)
+	keys, _ := args.Get(0).([]*models.APIKey)
+	return keys, args.Error(1)
+}
+
+func (m *MockAPIKeyService) CreateAPIKey(ctx context.Context, userID int, name string) (*domain.CreatedAPIKey, error) {
+	args := m.Called(ctx, userID, name)
+	key, _ := args.Get(0).(*domain.CreatedAPIKey)
+	return key, args.Error(1)
+}
+
+func (m *MockAPIKeyService) DeleteAPIKey(ctx context.Context, userID, keyID int) error {
+	args := m.Called(ctx, userID, keyID)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyService) Authenticate(ctx context.Context, key string) (int, error) {
+	args := m.Called(ctx, key)
+	return args.Int(0), args.Error(1)
+}
+
+type MockCAPService struct {
+	mock.Mock
+}
+
+func (m *MockCAPService) IngestAlert(ctx context.Context, userID int, alert *cap.Alert) (*domain.CAPResult, error) {
+	args := m.Called(ctx, userID, alert)
+	result, _ := args.Get(0).(*domain.CAPResult)
+	return result, args.Error(1)
+}
+
+type MockTriggerService struct {
+	mock.Mock
+}
+
+func (m *MockTriggerService) GetTriggersByUserID(ctx context.Context, userID int) ([]*models.Trigger, error) {
+	args := m.Called(ctx, userID)
+	triggers, _ := args.Get(0).([]*models.Trigger)
+	return triggers, args.Error(1)
+}
+
+func (m *MockTriggerService) GetTriggerByID(ctx context.Context, userID, triggerID int) (*models.Trigger, error) {
+	args := m.Called(ctx, userID, triggerID)
+	trigger, _ := args.Get(0).(*models.Trigger)
+	return trigger, args.Error(1)
+}
+
+func (m *MockTriggerService) CreateTrigger(ctx context.Context, trigger *models.Trigger) (*models.Trigger, error) {
+	args := m.Called(ctx, trigger)
+	created, _ := args.Get(0).(*models.Trigger)
+	return created, args.Error(1)
+}
+
+func (m *MockTriggerService) UpdateTrigger(ctx context.Context, userID, triggerID int, trigger *models.Trigger) (*models.Trigger, error) {
+	args := m.Called(ctx, userID, triggerID, trigger)
+	updated, _ := args.Get(0).(*models.Trigger)
+	return updated, args.Error(1)
+}
+
+func (m *MockTriggerService) DeleteTrigger(ctx context.Context, userID, triggerID int) error {
+	args := m.Called(ctx, userID, triggerID)
+	return args.Error(0)
+}
+
+func (m *MockTriggerService) HandleAlerts(ctx context.Context, userID int, alerts []*domain.WebhookAlert) (*domain.WebhookResult, error) {
+	args := m.Called(ctx, userID, alerts)
+	result, _ := args.Get(0).(*domain.WebhookResult)
+	return result, args.Error(1)
+}
+
+type MockApprovalPolicyService struct {
+	mock.Mock
+}
+
+func (m *MockApprovalPolicyService) GetApprovalPolicy(ctx context.Context, userID int) (*models.ApprovalPolicy, error) {
+	args := m.Called(ctx, userID)
+	policy, _ := args.Get(0).(*models.ApprovalPolicy)
+	return policy, args.Error(1)
+}
+
+func (m *MockApprovalPolicyService) UpdateApprovalPolicy(ctx context.Context, userID int, p *models.ApprovalPolicy) (*models.ApprovalPolicy, *models.ApprovalPolicyChange, error) {
+	args := m.Called(ctx, userID, p)
+	policy, _ := args.Get(0).(*models.ApprovalPolicy)
+	change, _ := args.Get(1).(*models.ApprovalPolicyChange)
+	return policy, change, args.Error(2)
+}
+
+func (m *MockApprovalPolicyService) DeleteApprovalPolicy(ctx context.Context, userID int) (*models.ApprovalPolicyChange, error) {
+	args := m.Called(ctx, userID)
+	change, _ := args.Get(0).(*models.ApprovalPolicyChange)
+	return change, args.Error(1)
+}
+
+func (m *MockApprovalPolicyService) GetPolicyChanges(ctx context.Context, userID int) ([]*models.ApprovalPolicyChange, error) {
+	args := m.Called(ctx, userID)
+	changes, _ := args.Get(0).([]*models.ApprovalPolicyChange)
+	return changes, args.Error(1)
+}
+
+func (m *MockApprovalPolicyService) ApprovePolicyChange(ctx context.Context, userID, changeID int, comment string) (*models.ApprovalPolicyChange, error) {
+	args := m.Called(ctx, userID, changeID, comment)
+	change, _ := args.Get(0).(*models.ApprovalPolicyChange)
+	return change, args.Error(1)
+}
+
+func (m *MockApprovalPolicyService) RejectPolicyChange(ctx context.Context, userID, changeID int, comment string) (*models.ApprovalPolicyChange, error) {
+	args := m.Called(ctx, userID, changeID, comment)
+	change, _ := args.Get(0).(*models.ApprovalPolicyChange)
+	return change, args.Error(1)
+}
+
+type MockCampaignApprovalService struct {
+	mock.Mock
+}
+
+func (m *MockCampaignApprovalService) RequestApproval(ctx context.Context, userID, templateID int, opts *domain.SendNotificationRequest) (*models.CampaignApproval, error) {
+	args := m.Called(ctx, userID, templateID, opts)
+	approval, _ := args.Get(0).(*models.CampaignApproval)
+	return approval, args.Error(1)
+}
+
+func (m *MockCampaignApprovalService) RequestMessageApproval(ctx context.Context, userID int, campaignID uuid.UUID, text string, opts *domain.SendNotificationRequest) (*models.CampaignApproval, error) {
+	args := m.Called(ctx, userID, campaignID, text, opts)
+	approval, _ := args.Get(0).(*models.CampaignApproval)
+	return approval, args.Error(1)
+}
+
+func (m *MockCampaignApprovalService) GetApprovals(ctx context.Context, userID int) ([]*models.CampaignApproval, error) {
+	args := m.Called(ctx, userID)
+	approvals, _ := args.Get(0).([]*models.CampaignApproval)
+	return approvals, args.Error(1)
+}
+
+func (m *MockCampaignApprovalService) GetApprovalByID(ctx context.Context, userID, approvalID int) (*models.CampaignApproval, error) {
+	args := m.Called(ctx, userID, approvalID)
+	approval, _ := args.Get(0).(*models.CampaignApproval)
+	return approval, args.Error(1)
+}
+
+func (m *MockCampaignApprovalService) ApproveCampaign(ctx context.Context, userID, approvalID int, comment string) (*models.CampaignApproval, error) {
+	args := m.Called(ctx, userID, approvalID, comment)
+	approval, _ := args.Get(0).(*models.CampaignApproval)
+	return approval, args.Error(1)
+}
+
+func (m *MockCampaignApprovalService) RejectCampaign(ctx context.Context, userID, approvalID int, comment string) (*models.CampaignApproval, error) {
+	args := m.Called(ctx, userID, approvalID, comment)
+	approval, _ := args.Get(0).(*models.CampaignApproval)
+	return approval, args.Error(1)
+}
+
+type MockCampaignService struct {
+	mock.Mock
+}
+
+func (m *MockCampaignService) GetCampaigns(ctx context.Context, userID int, filter domain.CampaignFilter, page domain.PageRequest) ([]*models.Campaign, error) {
+	args := m.Called(ctx, userID, filter, page)
+	campaigns, _ := args.Get(0).([]*models.Campaign)
+	return campaigns, args.Error(1)
+}
@@ -12,7 +12,7 @@ import (
 	"go.uber.org/zap"
 )
 
-// ProfileHandler handles HTTP requests related to user profile retrieval.
+// ProfileHandler handles HTTP requests related to the user profile.
 type ProfileHandler struct {
 	service        domain.ProfileService
 	logger         *zap.Logger
@@ -76,3 +76,48 @@ func (ph *ProfileHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 		ph.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
 	}
 }
+
+// PutProfile handles HTTP PUT requests to update the authenticated user's profile settings.
+// Responds with 200 and the updated profile, or 400/404/422/500 on error.
+func (ph *ProfileHandler) PutProfile(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), ph.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+
+	userID, ok := rawUserID.(int)
+	if !ok {
+		ph.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	var req domain.PutProfileRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	profile, err := ph.service.UpdateDefaultRegion(ctx, userID, req.DefaultRegion)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidPhoneRegion):
+			http.Error(w, "Invalid default region", http.StatusUnprocessableEntity)
+		case errors.Is(err, domain.ErrUserNotExists):
+			http.Error(w, "User does not exist", http.StatusNotFound)
+		default:
+			ph.logError("failed to update user profile", r, zap.Int("user_id", userID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(&profile)
+	if err != nil {
+		ph.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -67,11 +68,11 @@ func TestProfileHandler_GetProfile(t *testing.T) {
 			},
 			setupMock: func(m *MockProfileService) {
 				m.On("GetUserByID", mock.Anything, 103).
-					Return(&models.User{ID: 103, Email: "jane@example.com"}, nil).
+					Return(&models.User{ID: 103, Email: "jane@example.com", DefaultRegion: "RU"}, nil).
 					Once()
 			},
 			wantStatusCode: http.StatusOK,
-			wantBody:       `{"id":103,"email":"jane@example.com","creationTime":"0001-01-01T00:00:00Z"}`,
+			wantBody:       `{"id":103,"email":"jane@example.com","defaultRegion":"RU","creationTime":"0001-01-01T00:00:00Z"}`,
 		},
 	}
 
@@ -105,3 +106,107 @@ func TestProfileHandler_GetProfile(t *testing.T) {
 		})
 	}
 }
+
+func TestProfileHandler_PutProfile(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupContext   func(*http.Request)
+		body           string
+		setupMock      func(m *MockProfileService)
+		wantStatusCode int
+		wantBody       string
+	}{
+		{
+			name:           "user ID missing from context",
+			setupContext:   func(r *http.Request) {},
+			body:           `{"defaultRegion":"DE"}`,
+			wantStatusCode: http.StatusInternalServerError,
+			wantBody:       "Internal Server Error\n",
+		},
+		{
+			name: "malformed json",
+			setupContext: func(r *http.Request) {
+				ctx := context.WithValue(r.Context(), contextkeys.UserID, 101)
+				*r = *r.WithContext(ctx)
+			},
+			body:           `{"defaultRegion":`,
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       "Bad Request\n",
+		},
+		{
+			name: "invalid region",
+			setupContext: func(r *http.Request) {
+				ctx := context.WithValue(r.Context(), contextkeys.UserID, 101)
+				*r = *r.WithContext(ctx)
+			},
+			body: `{"defaultRegion":"XX"}`,
+			setupMock: func(m *MockProfileService) {
+				m.On("UpdateDefaultRegion", mock.Anything, 101, "XX").
+					Return((*models.User)(nil), domain.ErrInvalidPhoneRegion).
+					Once()
+			},
+			wantStatusCode: http.StatusUnprocessableEntity,
+			wantBody:       "Invalid default region\n",
+		},
+		{
+			name: "user not found",
+			setupContext: func(r *http.Request) {
+				ctx := context.WithValue(r.Context(), contextkeys.UserID, 102)
+				*r = *r.WithContext(ctx)
+			},
+			body: `{"defaultRegion":"DE"}`,
+			setupMock: func(m *MockProfileService) {
+				m.On("UpdateDefaultRegion", mock.Anything, 102, "DE").
+					Return((*models.User)(nil), domain.ErrUserNotExists).
+					Once()
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantBody:       "User does not exist\n",
+		},
+		{
+			name: "successful update",
+			setupContext: func(r *http.Request) {
+				ctx := context.WithValue(r.Context(), contextkeys.UserID, 103)
+				*r = *r.WithContext(ctx)
+			},
+			body: `{"defaultRegion":"de"}`,
+			setupMock: func(m *MockProfileService) {
+				m.On("UpdateDefaultRegion", mock.Anything, 103, "de").
+					Return(&models.User{ID: 103, Email: "jane@example.com", DefaultRegion: "DE"}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"id":103,"email":"jane@example.com","defaultRegion":"DE","creationTime":"0001-01-01T00:00:00Z"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockSvc := new(MockProfileService)
+
+			if tc.setupMock != nil {
+				tc.setupMock(mockSvc)
+			}
+
+			h := handler.NewProfileHandler(mockSvc, zap.NewNop(), 100*time.Millisecond)
+
+			req := httptest.NewRequest(http.MethodPut, "/profile", strings.NewReader(tc.body))
+			if tc.setupContext != nil {
+				tc.setupContext(req)
+			}
+
+			rec := httptest.NewRecorder()
+			h.PutProfile(rec, req)
+
+			require.Equal(t, tc.wantStatusCode, rec.Code)
+
+			if tc.wantStatusCode == http.StatusOK {
+				require.JSONEq(t, tc.wantBody, rec.Body.String())
+			} else {
+				require.Equal(t, tc.wantBody, rec.Body.String())
+			}
+
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
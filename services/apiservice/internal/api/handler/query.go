@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+)
+
+// queryParamError reports a malformed query parameter. Its message is meant for the client.
+type queryParamError struct {
+	param string
+}
+
+func (e *queryParamError) Error() string {
+	return "Invalid " + e.param
+}
+
+// parsePageRequest reads the limit, offset, cursor and sort query parameters. sort names
+// the column to order by, prefixed with "-" for descending order.
+func parsePageRequest(query url.Values) domain.PageRequest {
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	offset, _ := strconv.Atoi(query.Get("offset"))
+
+	sort := domain.Sort{Field: query.Get("sort")}
+	if field, ok := strings.CutPrefix(sort.Field, "-"); ok {
+		sort = domain.Sort{Field: field, Desc: true}
+	}
+
+	return domain.PageRequest{
+		Limit:  limit,
+		Offset: offset,
+		Cursor: query.Get("cursor"),
+		Sort:   sort,
+	}
+}
+
+// parseContactFilter reads the search, group, createdFrom, createdTo and attr query parameters.
+// Each attr value is an attribute name and the value it must have, separated by ":".
+func parseContactFilter(query url.Values) (domain.ContactFilter, error) {
+	from, to, err := parseCreatedRange(query)
+	if err != nil {
+		return domain.ContactFilter{}, err
+	}
+
+	var attributes map[string]string
+	for _, attr := range query["attr"] {
+		name, value, ok := strings.Cut(attr, ":")
+		if !ok || name == "" {
+			return domain.ContactFilter{}, &queryParamError{param: "attr"}
+		}
+		if attributes == nil {
+			attributes = make(map[string]string)
+		}
+		attributes[name] = value
+	}
+
+	return domain.ContactFilter{
+		Search:      query.Get("search"),
+		Group:       query.Get("group"),
+		Attributes:  attributes,
+		CreatedFrom: from,
+		CreatedTo:   to,
+	}, nil
+}
+
+// parseTemplateFilter reads the search, createdFrom and createdTo query parameters.
+func parseTemplateFilter(query url.Values) (domain.TemplateFilter, error) {
+	from, to, err := parseCreatedRange(query)
+	if err != nil {
+		return domain.TemplateFilter{}, err
+	}
+
+	return domain.TemplateFilter{
+		Search:      query.Get("search"),
+		CreatedFrom: from,
+		CreatedTo:   to,
+	}, nil
+}
+
+// parseCreatedRange reads the createdFrom and createdTo query parameters as RFC 3339 timestamps.
+// Missing parameters are returned as zero times.
+func parseCreatedRange(query url.Values) (from, to time.Time, err error) {
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{
+		{name: "createdFrom", dst: &from},
+		{name: "createdTo", dst: &to},
+	} {
+		raw := query.Get(p.name)
+		if raw == "" {
+			continue
+		}
+		*p.dst, err = time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return time.Time{}, time.Time{}, &queryParamError{param: p.name}
+		}
+	}
+	return from, to, nil
+}
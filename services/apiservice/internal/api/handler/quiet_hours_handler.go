@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/contextkeys"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"go.uber.org/zap"
+)
+
+// QuietHoursHandler handles HTTP requests for managing the user's quiet hours.
+type QuietHoursHandler struct {
+	service        domain.QuietHoursService
+	logger         *zap.Logger
+	contextTimeout time.Duration
+}
+
+// NewQuietHoursHandler creates a new QuietHoursHandler with the provided service, logger, and timeout.
+func NewQuietHoursHandler(s domain.QuietHoursService, logger *zap.Logger, timeout time.Duration) *QuietHoursHandler {
+	return &QuietHoursHandler{
+		service:        s,
+		logger:         logger,
+		contextTimeout: timeout,
+	}
+}
+
+func (qhh *QuietHoursHandler) logError(msg string, r *http.Request, fields ...zap.Field) {
+	cid := r.Header.Get("X-Correlation-ID")
+
+	allFields := []zap.Field{
+		zap.String("correlation_id", cid),
+		zap.String("uri", r.RequestURI),
+		zap.String("client_ip", r.RemoteAddr),
+		zap.String("user_agent", r.UserAgent()),
+	}
+	allFields = append(allFields, fields...)
+
+	qhh.logger.Error(msg, allFields...)
+}
+
+// Get returns the quiet hours of the authenticated user.
+// Responds with JSON-encoded quiet hours, 404 if none are configured, or 500 on error.
+func (qhh *QuietHoursHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), qhh.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		qhh.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	qh, err := qhh.service.GetQuietHours(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrQuietHoursNotExists) {
+			http.Error(w, "Quiet hours are not configured", http.StatusNotFound)
+		} else {
+			qhh.logError("failed to get quiet hours", r, zap.Int("user_id", userID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(qh)
+	if err != nil {
+		qhh.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// Put sets the quiet hours of the authenticated user.
+// Responds with 200 and the stored quiet hours, or 400/422/500 on error.
+func (qhh *QuietHoursHandler) Put(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), qhh.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		qhh.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	var req models.QuietHours
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	qh, err := qhh.service.UpdateQuietHours(ctx, userID, &req)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidQuietHours) {
+			http.Error(w, "Invalid quiet hours", http.StatusUnprocessableEntity)
+		} else {
+			qhh.logError("failed to update quiet hours", r, zap.Int("user_id", userID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(qh)
+	if err != nil {
+		qhh.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// Delete turns quiet hours off for the authenticated user.
+// Responds with 204 on success, 404 if none are configured, or 500 on error.
+func (qhh *QuietHoursHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), qhh.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		qhh.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	err := qhh.service.DeleteQuietHours(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrQuietHoursNotExists) {
+			http.Error(w, "Quiet hours are not configured", http.StatusNotFound)
+		} else {
+			qhh.logError("failed to delete quiet hours", r, zap.Int("user_id", userID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
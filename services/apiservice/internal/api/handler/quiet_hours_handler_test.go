@@ -0,0 +1,184 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testQuietHours = &models.QuietHours{StartMinute: 22 * 60, EndMinute: 7 * 60, TimeZone: "Europe/Moscow"}
+
+// --- GET /quiet-hours ---
+func TestQuietHoursHandler_Get(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(m *MockQuietHoursService)
+		wantStatus int
+		wantBody   *models.QuietHours
+	}{
+		{
+			name: "success",
+			setup: func(m *MockQuietHoursService) {
+				m.
+					On("GetQuietHours", mock.Anything, 1).
+					Return(testQuietHours, nil).
+					Once()
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   testQuietHours,
+		},
+		{
+			name: "not configured",
+			setup: func(m *MockQuietHoursService) {
+				m.
+					On("GetQuietHours", mock.Anything, 1).
+					Return((*models.QuietHours)(nil), domain.ErrQuietHoursNotExists).
+					Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "service error",
+			setup: func(m *MockQuietHoursService) {
+				m.
+					On("GetQuietHours", mock.Anything, 1).
+					Return((*models.QuietHours)(nil), assert.AnError).
+					Once()
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockQuietHoursService)
+			tc.setup(m)
+			h := handler.NewQuietHoursHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodGet, "/quiet-hours", nil)
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.Get(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			if tc.wantBody != nil {
+				var got models.QuietHours
+				err := json.NewDecoder(rr.Body).Decode(&got)
+				assert.NoError(t, err)
+				assert.Equal(t, *tc.wantBody, got)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+// --- PUT /quiet-hours ---
+func TestQuietHoursHandler_Put(t *testing.T) {
+	validBody, _ := json.Marshal(testQuietHours)
+
+	tests := []struct {
+		name       string
+		body       []byte
+		setup      func(m *MockQuietHoursService)
+		wantStatus int
+	}{
+		{
+			name: "success",
+			body: validBody,
+			setup: func(m *MockQuietHoursService) {
+				m.
+					On("UpdateQuietHours", mock.Anything, 1, testQuietHours).
+					Return(testQuietHours, nil).
+					Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "bad json",
+			body:       []byte("{bad"),
+			setup:      func(m *MockQuietHoursService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid quiet hours",
+			body: validBody,
+			setup: func(m *MockQuietHoursService) {
+				m.
+					On("UpdateQuietHours", mock.Anything, 1, testQuietHours).
+					Return((*models.QuietHours)(nil), domain.ErrInvalidQuietHours).
+					Once()
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "service error",
+			body: validBody,
+			setup: func(m *MockQuietHoursService) {
+				m.
+					On("UpdateQuietHours", mock.Anything, 1, testQuietHours).
+					Return((*models.QuietHours)(nil), assert.AnError).
+					Once()
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockQuietHoursService)
+			tc.setup(m)
+			h := handler.NewQuietHoursHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodPut, "/quiet-hours", bytes.NewReader(tc.body))
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.Put(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+// --- DELETE /quiet-hours ---
+func TestQuietHoursHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name       string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "success", wantStatus: http.StatusNoContent},
+		{name: "not configured", serviceErr: domain.ErrQuietHoursNotExists, wantStatus: http.StatusNotFound},
+		{name: "service error", serviceErr: assert.AnError, wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockQuietHoursService)
+			m.
+				On("DeleteQuietHours", mock.Anything, 1).
+				Return(tc.serviceErr).
+				Once()
+			h := handler.NewQuietHoursHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodDelete, "/quiet-hours", nil)
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.Delete(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			m.AssertExpectations(t)
+		})
+	}
+}
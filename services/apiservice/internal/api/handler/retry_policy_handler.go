@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/contextkeys"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"go.uber.org/zap"
+)
+
+// RetryPolicyHandler handles HTTP requests for reading and updating the user's default retry policy.
+type RetryPolicyHandler struct {
+	service        domain.RetryPolicyService
+	logger         *zap.Logger
+	contextTimeout time.Duration
+}
+
+// NewRetryPolicyHandler creates a new RetryPolicyHandler with the provided service, logger, and timeout.
+func NewRetryPolicyHandler(s domain.RetryPolicyService, logger *zap.Logger, timeout time.Duration) *RetryPolicyHandler {
+	return &RetryPolicyHandler{
+		service:        s,
+		logger:         logger,
+		contextTimeout: timeout,
+	}
+}
+
+func (rph *RetryPolicyHandler) logError(msg string, r *http.Request, fields ...zap.Field) {
+	cid := r.Header.Get("X-Correlation-ID")
+
+	allFields := []zap.Field{
+		zap.String("correlation_id", cid),
+		zap.String("uri", r.RequestURI),
+		zap.String("client_ip", r.RemoteAddr),
+		zap.String("user_agent", r.UserAgent()),
+	}
+	allFields = append(allFields, fields...)
+
+	rph.logger.Error(msg, allFields...)
+}
+
+// Get returns the retry policy applied to the authenticated user's campaigns.
+// Responds with JSON-encoded policy or 500 on error.
+func (rph *RetryPolicyHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), rph.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		rph.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	policy, err := rph.service.GetRetryPolicy(ctx, userID)
+	if err != nil {
+		rph.logError("failed to get retry policy", r, zap.Int("user_id", userID), zap.Error(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(policy)
+	if err != nil {
+		rph.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// Put replaces the authenticated user's default retry policy.
+// Responds with 200 and the stored policy, or 400/422/500 on error.
+func (rph *RetryPolicyHandler) Put(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), rph.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		rph.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	var req models.RetryPolicy
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	policy, err := rph.service.UpdateRetryPolicy(ctx, userID, &req)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidRetryPolicy) {
+			http.Error(w, "Invalid retry policy", http.StatusUnprocessableEntity)
+		} else {
+			rph.logError("failed to update retry policy", r, zap.Int("user_id", userID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(policy)
+	if err != nil {
+		rph.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
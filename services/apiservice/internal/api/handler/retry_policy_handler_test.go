@@ -0,0 +1,148 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testRetryPolicy = &models.RetryPolicy{
+	MaxAttempts:  5,
+	Backoff:      models.BackoffExponential,
+	BaseDelayMs:  1000,
+	MaxDelayMs:   300_000,
+	Jitter:       0.2,
+	StaleAfterMs: 300_000,
+}
+
+// --- GET /retry-policy ---
+func TestRetryPolicyHandler_Get(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(m *MockRetryPolicyService)
+		wantStatus int
+		wantBody   *models.RetryPolicy
+	}{
+		{
+			name: "success",
+			setup: func(m *MockRetryPolicyService) {
+				m.
+					On("GetRetryPolicy", mock.Anything, 1).
+					Return(testRetryPolicy, nil).
+					Once()
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   testRetryPolicy,
+		},
+		{
+			name: "service error",
+			setup: func(m *MockRetryPolicyService) {
+				m.
+					On("GetRetryPolicy", mock.Anything, 1).
+					Return((*models.RetryPolicy)(nil), assert.AnError).
+					Once()
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockRetryPolicyService)
+			tc.setup(m)
+			h := handler.NewRetryPolicyHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodGet, "/retry-policy", nil)
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.Get(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			if tc.wantBody != nil {
+				var got models.RetryPolicy
+				err := json.NewDecoder(rr.Body).Decode(&got)
+				assert.NoError(t, err)
+				assert.Equal(t, *tc.wantBody, got)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+// --- PUT /retry-policy ---
+func TestRetryPolicyHandler_Put(t *testing.T) {
+	validBody, _ := json.Marshal(testRetryPolicy)
+
+	tests := []struct {
+		name       string
+		body       []byte
+		setup      func(m *MockRetryPolicyService)
+		wantStatus int
+	}{
+		{
+			name: "success",
+			body: validBody,
+			setup: func(m *MockRetryPolicyService) {
+				m.
+					On("UpdateRetryPolicy", mock.Anything, 1, testRetryPolicy).
+					Return(testRetryPolicy, nil).
+					Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "bad json",
+			body:       []byte("{bad"),
+			setup:      func(m *MockRetryPolicyService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid policy",
+			body: validBody,
+			setup: func(m *MockRetryPolicyService) {
+				m.
+					On("UpdateRetryPolicy", mock.Anything, 1, testRetryPolicy).
+					Return((*models.RetryPolicy)(nil), domain.ErrInvalidRetryPolicy).
+					Once()
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "service error",
+			body: validBody,
+			setup: func(m *MockRetryPolicyService) {
+				m.
+					On("UpdateRetryPolicy", mock.Anything, 1, testRetryPolicy).
+					Return((*models.RetryPolicy)(nil), assert.AnError).
+					Once()
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockRetryPolicyService)
+			tc.setup(m)
+			h := handler.NewRetryPolicyHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodPut, "/retry-policy", bytes.NewReader(tc.body))
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.Put(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			m.AssertExpectations(t)
+		})
+	}
+}
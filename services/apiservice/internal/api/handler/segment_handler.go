@@ -0,0 +1,335 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/contextkeys"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// SegmentHandler handles HTTP requests managing and previewing the segments of a user.
+type SegmentHandler struct {
+	service        domain.SegmentService
+	logger         *zap.Logger
+	contextTimeout time.Duration
+}
+
+// NewSegmentHandler creates a new SegmentHandler with the given service,
+// structured logger, and per-request timeout duration.
+func NewSegmentHandler(s domain.SegmentService, logger *zap.Logger, timeout time.Duration) *SegmentHandler {
+	return &SegmentHandler{
+		service:        s,
+		logger:         logger,
+		contextTimeout: timeout,
+	}
+}
+
+func (sh *SegmentHandler) logError(msg string, r *http.Request, fields ...zap.Field) {
+	cid := r.Header.Get("X-Correlation-ID")
+
+	allFields := []zap.Field{
+		zap.String("correlation_id", cid),
+		zap.String("uri", r.RequestURI),
+		zap.String("client_ip", r.RemoteAddr),
+		zap.String("user_agent", r.UserAgent()),
+	}
+	allFields = append(allFields, fields...)
+
+	sh.logger.Error(msg, allFields...)
+}
+
+// Get handles GET /segments requests to list the segments of the user.
+func (sh *SegmentHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), sh.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		sh.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	segments, err := sh.service.GetSegmentsByUserID(ctx, userID)
+	if err != nil {
+		sh.logError("failed to get segments", r, zap.Int("user_id", userID), zap.Error(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(segments)
+	if err != nil {
+		sh.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// GetByID handles GET /segments/{id} requests. Returns 404 if the user has no such segment.
+func (sh *SegmentHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), sh.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		sh.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	segmentID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+
+	segment, err := sh.service.GetSegmentByID(ctx, userID, segmentID)
+	if err != nil {
+		if errors.Is(err, domain.ErrSegmentNotExists) {
+			http.Error(w, "Segment does not exist", http.StatusNotFound)
+		} else {
+			sh.logError("failed to get segment", r, zap.Int("user_id", userID), zap.Int("id", segmentID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(segment)
+	if err != nil {
+		sh.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// Post handles POST /segments requests to save a new segment.
+// Returns 201 Created with the segment, 422 for an invalid name or rule or 409 if the
+// user already has a segment with the name.
+func (sh *SegmentHandler) Post(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), sh.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		sh.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	var req domain.SegmentRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	segment, err := sh.service.CreateSegment(ctx, &models.Segment{
+		UserID: userID,
+		Name:   req.Name,
+		Rule:   req.Rule,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidSegment):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		case errors.Is(err, domain.ErrSegmentAlreadyExists):
+			http.Error(w, "Segment already exists", http.StatusConflict)
+		default:
+			sh.logError("failed to create segment", r, zap.Int("user_id", userID), zap.String("name", req.Name), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(segment)
+	if err != nil {
+		sh.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// Put handles PUT /segments/{id} requests to replace the name and rule of a segment.
+// Returns 404 if the user has no such segment.
+func (sh *SegmentHandler) Put(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), sh.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		sh.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	segmentID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+
+	var req domain.SegmentRequest
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	segment, err := sh.service.UpdateSegment(ctx, userID, segmentID, &models.Segment{
+		UserID: userID,
+		Name:   req.Name,
+		Rule:   req.Rule,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidSegment):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		case errors.Is(err, domain.ErrSegmentNotExists):
+			http.Error(w, "Segment does not exist", http.StatusNotFound)
+		case errors.Is(err, domain.ErrSegmentAlreadyExists):
+			http.Error(w, "Segment already exists", http.StatusConflict)
+		default:
+			sh.logError("failed to update segment", r, zap.Int("user_id", userID), zap.Int("id", segmentID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(segment)
+	if err != nil {
+		sh.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// Delete handles DELETE /segments/{id} requests.
+// Returns 204 No Content on success, or 404 if the segment doesn't exist.
+func (sh *SegmentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), sh.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		sh.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	segmentID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+
+	err = sh.service.DeleteSegment(ctx, userID, segmentID)
+	if err != nil {
+		if errors.Is(err, domain.ErrSegmentNotExists) {
+			http.Error(w, "Segment does not exist", http.StatusNotFound)
+		} else {
+			sh.logError("failed to delete segment", r, zap.Int("user_id", userID), zap.Int("id", segmentID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PostPreview handles POST /segments/preview requests to check a rule before saving it.
+// Returns the number of matching contacts and a sample of them, or 422 for an invalid rule.
+func (sh *SegmentHandler) PostPreview(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), sh.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		sh.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	var req domain.SegmentPreviewRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	preview, err := sh.service.PreviewRule(ctx, userID, req.Rule)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidSegmentRule) {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		} else {
+			sh.logError("failed to preview segment rule", r, zap.Int("user_id", userID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(preview)
+	if err != nil {
+		sh.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// GetPreview handles GET /segments/{id}/preview requests to count the contacts a saved
+// segment matches right now and return a sample of them.
+func (sh *SegmentHandler) GetPreview(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), sh.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		sh.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	segmentID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+
+	preview, err := sh.service.PreviewSegment(ctx, userID, segmentID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrSegmentNotExists):
+			http.Error(w, "Segment does not exist", http.StatusNotFound)
+		case errors.Is(err, domain.ErrInvalidSegmentRule):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		default:
+			sh.logError("failed to preview segment", r, zap.Int("user_id", userID), zap.Int("id", segmentID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(preview)
+	if err != nil {
+		sh.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
@@ -0,0 +1,234 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testSegment = &models.Segment{ID: 7, UserID: 1, Name: "Night B", Rule: `building = "B" AND shift = night`}
+
+// --- POST /segments ---
+func TestSegmentHandler_Post(t *testing.T) {
+	segment := &models.Segment{UserID: 1, Name: testSegment.Name, Rule: testSegment.Rule}
+	body := `{"name":"Night B","rule":"building = \"B\" AND shift = night"}`
+
+	tests := []struct {
+		name       string
+		body       string
+		setup      func(m *MockSegmentService)
+		wantStatus int
+	}{
+		{
+			name: "created",
+			body: body,
+			setup: func(m *MockSegmentService) {
+				m.On("CreateSegment", mock.Anything, segment).Return(testSegment, nil).Once()
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "bad json",
+			body:       `{`,
+			setup:      func(m *MockSegmentService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid rule",
+			body: body,
+			setup: func(m *MockSegmentService) {
+				m.On("CreateSegment", mock.Anything, segment).Return(nil, domain.ErrInvalidSegmentRule).Once()
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "already exists",
+			body: body,
+			setup: func(m *MockSegmentService) {
+				m.On("CreateSegment", mock.Anything, segment).Return(nil, domain.ErrSegmentAlreadyExists).Once()
+			},
+			wantStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockSegmentService)
+			tc.setup(m)
+			h := handler.NewSegmentHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodPost, "/segments", strings.NewReader(tc.body))
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.Post(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+// --- GET /segments/{id} ---
+func TestSegmentHandler_GetByID(t *testing.T) {
+	tests := []struct {
+		name       string
+		idParam    string
+		setup      func(m *MockSegmentService)
+		wantStatus int
+	}{
+		{
+			name:    "success",
+			idParam: "7",
+			setup: func(m *MockSegmentService) {
+				m.On("GetSegmentByID", mock.Anything, 1, 7).Return(testSegment, nil).Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid id",
+			idParam:    "abc",
+			setup:      func(m *MockSegmentService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "not found",
+			idParam: "7",
+			setup: func(m *MockSegmentService) {
+				m.On("GetSegmentByID", mock.Anything, 1, 7).Return(nil, domain.ErrSegmentNotExists).Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockSegmentService)
+			tc.setup(m)
+			h := handler.NewSegmentHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodGet, "/segments/"+tc.idParam, nil)
+			req = mux.SetURLVars(req, map[string]string{"id": tc.idParam})
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.GetByID(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			if tc.wantStatus == http.StatusOK {
+				var got models.Segment
+				assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+				assert.Equal(t, *testSegment, got)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+// --- POST /segments/preview ---
+func TestSegmentHandler_PostPreview(t *testing.T) {
+	preview := &domain.SegmentPreview{
+		Count:  25,
+		Sample: []*models.Contact{{ID: 1, UserID: 1, Name: "Alice", Phone: "+79123456789"}},
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		setup      func(m *MockSegmentService)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "success",
+			body: `{"rule":"shift = night"}`,
+			setup: func(m *MockSegmentService) {
+				m.On("PreviewRule", mock.Anything, 1, "shift = night").Return(preview, nil).Once()
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `"count":25`,
+		},
+		{
+			name: "invalid rule",
+			body: `{"rule":"shift ="}`,
+			setup: func(m *MockSegmentService) {
+				m.
+					On("PreviewRule", mock.Anything, 1, "shift =").
+					Return(nil, fmt.Errorf("%w: syntax error at position 8: expected value, got end of rule", domain.ErrInvalidSegmentRule)).
+					Once()
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody:   "position 8",
+		},
+		{
+			name: "service error",
+			body: `{"rule":"shift = night"}`,
+			setup: func(m *MockSegmentService) {
+				m.On("PreviewRule", mock.Anything, 1, "shift = night").Return(nil, assert.AnError).Once()
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockSegmentService)
+			tc.setup(m)
+			h := handler.NewSegmentHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodPost, "/segments/preview", strings.NewReader(tc.body))
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.PostPreview(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			assert.Contains(t, rr.Body.String(), tc.wantBody)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+// --- GET /segments/{id}/preview ---
+func TestSegmentHandler_GetPreview(t *testing.T) {
+	m := new(MockSegmentService)
+	m.On("PreviewSegment", mock.Anything, 1, 7).Return(nil, domain.ErrSegmentNotExists).Once()
+	h := handler.NewSegmentHandler(m, logger, timeout)
+
+	req := httptest.NewRequest(http.MethodGet, "/segments/7/preview", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "7"})
+	req = injectUserID(req, 1)
+	rr := httptest.NewRecorder()
+
+	h.GetPreview(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	m.AssertExpectations(t)
+}
+
+// --- DELETE /segments/{id} ---
+func TestSegmentHandler_Delete(t *testing.T) {
+	m := new(MockSegmentService)
+	m.On("DeleteSegment", mock.Anything, 1, 7).Return(nil).Once()
+	h := handler.NewSegmentHandler(m, logger, timeout)
+
+	req := httptest.NewRequest(http.MethodDelete, "/segments/7", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "7"})
+	req = injectUserID(req, 1)
+	rr := httptest.NewRecorder()
+
+	h.Delete(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	m.AssertExpectations(t)
+}
@@ -2,13 +2,17 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/contextkeys"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 )
@@ -16,16 +20,19 @@ import (
 // SendNotificationHandler handles HTTP requests to initiate notification sending.
 // It extracts authentication context, parameters, and delegates to the SendNotificationService.
 // Responses are HTTP status codes indicating success or error.
+// Campaigns that need approval under the user's approval policy are held by the CampaignApprovalService instead.
 type SendNotificationHandler struct {
 	service        domain.SendNotificationService
+	approvals      domain.CampaignApprovalService
 	logger         *zap.Logger
 	contextTimeout time.Duration
 }
 
 // NewSendNotificationHandler constructs a SendNotificationHandler.
-func NewSendNotificationHandler(s domain.SendNotificationService, logger *zap.Logger, timeout time.Duration) *SendNotificationHandler {
+func NewSendNotificationHandler(s domain.SendNotificationService, as domain.CampaignApprovalService, logger *zap.Logger, timeout time.Duration) *SendNotificationHandler {
 	return &SendNotificationHandler{
 		service:        s,
+		approvals:      as,
 		logger:         logger,
 		contextTimeout: timeout,
 	}
@@ -46,8 +53,11 @@ func (snh *SendNotificationHandler) logError(msg string, r *http.Request, fields
 }
 
 // SendNotification handles POST /send-notification/{id}.
-// It reads the user ID from context, parses the template ID path param,
-// and calls the service to send notifications
+// It reads the user ID from context, parses the template ID path param and
+// an optional JSON body with campaign options (retry policy, priority, recipients), and calls the service to send notifications.
+// Responds with 202 Accepted, along with the pending approval if the campaign waits for one.
+// With ?testGroup=<group> the campaign is sent only to the contacts of the test group, and with
+// ?dryRun=true nothing is sent: the handler responds with 200 and a preview of the campaign instead.
 func (snh *SendNotificationHandler) SendNotification(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), snh.contextTimeout)
 	defer cancel()
@@ -68,9 +78,58 @@ func (snh *SendNotificationHandler) SendNotification(w http.ResponseWriter, r *h
 		return
 	}
 
-	err = snh.service.SendNotification(ctx, userID, templateID)
+	query := r.URL.Query()
+
+	dryRun := false
+	if query.Has("dryRun") {
+		dryRun, err = strconv.ParseBool(query.Get("dryRun"))
+		if err != nil {
+			http.Error(w, "Invalid dryRun", http.StatusBadRequest)
+			return
+		}
+	}
+
+	req := &domain.SendNotificationRequest{}
+
+	err = json.NewDecoder(r.Body).Decode(req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	if query.Has("testGroup") {
+		testGroup := strings.TrimSpace(query.Get("testGroup"))
+		if testGroup == "" {
+			http.Error(w, "Invalid test group", http.StatusUnprocessableEntity)
+			return
+		}
+		req = req.ForTestGroup(testGroup)
+	}
+
+	var preview *domain.CampaignPreview
+	var approval *models.CampaignApproval
+	if dryRun {
+		preview, err = snh.service.PreviewNotification(ctx, userID, templateID, req)
+	} else {
+		approval, err = snh.approvals.RequestApproval(ctx, userID, templateID, req)
+		if err == nil && approval == nil {
+			err = snh.service.SendNotification(ctx, userID, templateID, req)
+		}
+	}
 	if err != nil {
 		switch {
+		case errors.Is(err, domain.ErrInvalidRetryPolicy):
+			http.Error(w, "Invalid retry policy", http.StatusUnprocessableEntity)
+		case errors.Is(err, domain.ErrInvalidPriority):
+			http.Error(w, "Invalid priority", http.StatusUnprocessableEntity)
+		case errors.Is(err, domain.ErrInvalidSegmentRule):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		case errors.Is(err, domain.ErrSegmentNotExists):
+			http.Error(w, "Segment does not exist", http.StatusNotFound)
+		case errors.Is(err, domain.ErrInvalidArea):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		case errors.Is(err, domain.ErrZoneNotExists):
+			http.Error(w, "Zone does not exist", http.StatusNotFound)
 		case errors.Is(err, domain.ErrTemplateNotExists):
 			http.Error(w, "Template does not exist", http.StatusNotFound)
 		case errors.Is(err, domain.ErrContactNotExists):
@@ -82,5 +141,19 @@ func (snh *SendNotificationHandler) SendNotification(w http.ResponseWriter, r *h
 		return
 	}
 
-	w.WriteHeader(http.StatusAccepted)
+	switch {
+	case preview != nil:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		err = json.NewEncoder(w).Encode(preview)
+	case approval != nil:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		err = json.NewEncoder(w).Encode(approval)
+	default:
+		w.WriteHeader(http.StatusAccepted)
+	}
+	if err != nil {
+		snh.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
 }
@@ -2,14 +2,19 @@ package handler_test
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/contextkeys"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/geo"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -24,6 +29,7 @@ func TestSendNotificationHandler(t *testing.T) {
 	tests := []struct {
 		name           string
 		templateID     string
+		body           string
 		userInContext  any
 		mockSetup      func(m *MockSendNotificationService)
 		expectedStatus int
@@ -34,12 +40,119 @@ func TestSendNotificationHandler(t *testing.T) {
 			userInContext: userID,
 			mockSetup: func(m *MockSendNotificationService) {
 				m.
-					On("SendNotification", mock.Anything, userID, validID).
+					On("SendNotification", mock.Anything, userID, validID, &domain.SendNotificationRequest{}).
 					Return(nil).
 					Once()
 			},
 			expectedStatus: http.StatusAccepted,
 		},
+		{
+			name:          "success with campaign retry policy",
+			templateID:    validIDStr,
+			body:          `{"retryPolicy":{"maxAttempts":2,"backoff":"constant","baseDelayMs":500,"maxDelayMs":500,"staleAfterMs":60000}}`,
+			userInContext: userID,
+			mockSetup: func(m *MockSendNotificationService) {
+				m.
+					On("SendNotification", mock.Anything, userID, validID, &domain.SendNotificationRequest{
+						RetryPolicy: &models.RetryPolicy{
+							MaxAttempts:  2,
+							Backoff:      models.BackoffConstant,
+							BaseDelayMs:  500,
+							MaxDelayMs:   500,
+							StaleAfterMs: 60000,
+						},
+					}).
+					Return(nil).
+					Once()
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "malformed body",
+			templateID:     validIDStr,
+			body:           `{"retryPolicy":`,
+			userInContext:  userID,
+			mockSetup:      func(m *MockSendNotificationService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:          "invalid retry policy",
+			templateID:    validIDStr,
+			body:          `{"retryPolicy":{"maxAttempts":0}}`,
+			userInContext: userID,
+			mockSetup: func(m *MockSendNotificationService) {
+				m.
+					On("SendNotification", mock.Anything, userID, validID, mock.AnythingOfType("*domain.SendNotificationRequest")).
+					Return(domain.ErrInvalidRetryPolicy).
+					Once()
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:          "success with normal priority",
+			templateID:    validIDStr,
+			body:          `{"priority":"normal"}`,
+			userInContext: userID,
+			mockSetup: func(m *MockSendNotificationService) {
+				m.
+					On("SendNotification", mock.Anything, userID, validID, &domain.SendNotificationRequest{Priority: models.PriorityNormal}).
+					Return(nil).
+					Once()
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:          "invalid priority",
+			templateID:    validIDStr,
+			body:          `{"priority":"whenever"}`,
+			userInContext: userID,
+			mockSetup: func(m *MockSendNotificationService) {
+				m.
+					On("SendNotification", mock.Anything, userID, validID, mock.AnythingOfType("*domain.SendNotificationRequest")).
+					Return(domain.ErrInvalidPriority).
+					Once()
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:          "unknown segment",
+			templateID:    validIDStr,
+			body:          `{"segmentId":7}`,
+			userInContext: userID,
+			mockSetup: func(m *MockSendNotificationService) {
+				m.
+					On("SendNotification", mock.Anything, userID, validID, &domain.SendNotificationRequest{SegmentID: 7}).
+					Return(domain.ErrSegmentNotExists).
+					Once()
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:          "unknown zone",
+			templateID:    validIDStr,
+			body:          `{"zoneId":3}`,
+			userInContext: userID,
+			mockSetup: func(m *MockSendNotificationService) {
+				m.
+					On("SendNotification", mock.Anything, userID, validID, &domain.SendNotificationRequest{ZoneID: 3}).
+					Return(domain.ErrZoneNotExists).
+					Once()
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:          "invalid area",
+			templateID:    validIDStr,
+			body:          `{"area":{"circle":{"lat":95,"lon":37,"radius":100}}}`,
+			userInContext: userID,
+			mockSetup: func(m *MockSendNotificationService) {
+				m.
+					On("SendNotification", mock.Anything, userID, validID, mock.AnythingOfType("*domain.SendNotificationRequest")).
+					Return(fmt.Errorf("%w: %v", domain.ErrInvalidArea, geo.ErrInvalidCircle)).
+					Once()
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
 		{
 			name:           "invalid user ID type",
 			templateID:     validIDStr,
@@ -60,7 +173,7 @@ func TestSendNotificationHandler(t *testing.T) {
 			userInContext: userID,
 			mockSetup: func(m *MockSendNotificationService) {
 				m.
-					On("SendNotification", mock.Anything, userID, validID).
+					On("SendNotification", mock.Anything, userID, validID, &domain.SendNotificationRequest{}).
 					Return(domain.ErrTemplateNotExists).
 					Once()
 			},
@@ -72,7 +185,7 @@ func TestSendNotificationHandler(t *testing.T) {
 			userInContext: userID,
 			mockSetup: func(m *MockSendNotificationService) {
 				m.
-					On("SendNotification", mock.Anything, userID, validID).
+					On("SendNotification", mock.Anything, userID, validID, &domain.SendNotificationRequest{}).
 					Return(domain.ErrContactNotExists).
 					Once()
 			},
@@ -84,7 +197,7 @@ func TestSendNotificationHandler(t *testing.T) {
 			userInContext: userID,
 			mockSetup: func(m *MockSendNotificationService) {
 				m.
-					On("SendNotification", mock.Anything, userID, validID).
+					On("SendNotification", mock.Anything, userID, validID, &domain.SendNotificationRequest{}).
 					Return(assert.AnError).
 					Once()
 			},
@@ -96,10 +209,12 @@ func TestSendNotificationHandler(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			m := new(MockSendNotificationService)
 			tt.mockSetup(m)
+			as := new(MockCampaignApprovalService)
+			as.On("RequestApproval", mock.Anything, userID, validID, mock.Anything).Return(nil, nil).Maybe()
 
-			h := handler.NewSendNotificationHandler(m, zaptest.NewLogger(t), time.Second)
+			h := handler.NewSendNotificationHandler(m, as, zaptest.NewLogger(t), time.Second)
 
-			r := httptest.NewRequest(http.MethodPost, "/send-notification/"+tt.templateID, nil)
+			r := httptest.NewRequest(http.MethodPost, "/send-notification/"+tt.templateID, strings.NewReader(tt.body))
 			r = mux.SetURLVars(r, map[string]string{"id": tt.templateID})
 			ctx := context.WithValue(r.Context(), contextkeys.UserID, tt.userInContext)
 			r = r.WithContext(ctx)
@@ -113,3 +228,160 @@ func TestSendNotificationHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestSendNotificationHandler_Approval(t *testing.T) {
+	approval := &models.CampaignApproval{ID: 4, UserID: 1, TemplateID: 123, Recipients: 5000, Reason: models.ApprovalReasonRecipients, Status: models.ApprovalPending}
+
+	tests := []struct {
+		name           string
+		setup          func(as *MockCampaignApprovalService)
+		expectedStatus int
+		expectApproval bool
+	}{
+		{
+			name: "held for approval",
+			setup: func(as *MockCampaignApprovalService) {
+				as.On("RequestApproval", mock.Anything, 1, 123, &domain.SendNotificationRequest{}).Return(approval, nil).Once()
+			},
+			expectedStatus: http.StatusAccepted,
+			expectApproval: true,
+		},
+		{
+			name: "invalid options",
+			setup: func(as *MockCampaignApprovalService) {
+				as.On("RequestApproval", mock.Anything, 1, 123, &domain.SendNotificationRequest{}).Return(nil, domain.ErrInvalidPriority).Once()
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(MockSendNotificationService)
+			as := new(MockCampaignApprovalService)
+			tt.setup(as)
+			h := handler.NewSendNotificationHandler(m, as, logger, timeout)
+
+			r := httptest.NewRequest(http.MethodPost, "/send-notification/123", nil)
+			r = mux.SetURLVars(r, map[string]string{"id": "123"})
+			r = injectUserID(r, 1)
+			w := httptest.NewRecorder()
+
+			h.SendNotification(w, r)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectApproval {
+				var got models.CampaignApproval
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+				assert.Equal(t, approval.ID, got.ID)
+				assert.Equal(t, models.ApprovalPending, got.Status)
+			}
+			m.AssertNotCalled(t, "SendNotification", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			as.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSendNotificationHandler_DryRunAndTestSend(t *testing.T) {
+	preview := &domain.CampaignPreview{
+		Recipients: 2, Encoding: "UCS-2", SegmentsPerMessage: 1, TotalSegments: 2, EstimatedCost: 4.5, Currency: "RUB",
+		Samples: []*domain.PreviewMessage{{Name: "Alice", Phone: "+79123456789", Text: "Внимание"}},
+	}
+	testOpts := &domain.SendNotificationRequest{Priority: models.PriorityNormal, Filter: &domain.ContactFilter{Group: "qa"}, Test: true}
+
+	tests := []struct {
+		name           string
+		query          string
+		body           string
+		setup          func(m *MockSendNotificationService, as *MockCampaignApprovalService)
+		expectedStatus int
+		expectPreview  bool
+	}{
+		{
+			name:  "dry run",
+			query: "?dryRun=true",
+			setup: func(m *MockSendNotificationService, as *MockCampaignApprovalService) {
+				m.On("PreviewNotification", mock.Anything, 1, 123, &domain.SendNotificationRequest{}).Return(preview, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectPreview:  true,
+		},
+		{
+			name:  "dry run of test send",
+			query: "?dryRun=1&testGroup=qa",
+			body:  `{"priority":"normal","segmentId":3}`,
+			setup: func(m *MockSendNotificationService, as *MockCampaignApprovalService) {
+				m.On("PreviewNotification", mock.Anything, 1, 123, testOpts).Return(preview, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectPreview:  true,
+		},
+		{
+			name:  "dry run without recipients",
+			query: "?dryRun=true",
+			setup: func(m *MockSendNotificationService, as *MockCampaignApprovalService) {
+				m.On("PreviewNotification", mock.Anything, 1, 123, &domain.SendNotificationRequest{}).Return(nil, domain.ErrContactNotExists).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid dry run",
+			query:          "?dryRun=maybe",
+			setup:          func(m *MockSendNotificationService, as *MockCampaignApprovalService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "test send",
+			query: "?testGroup=qa",
+			body:  `{"priority":"normal","area":{"circle":{"lat":55.75,"lon":37.62,"radius":1000}}}`,
+			setup: func(m *MockSendNotificationService, as *MockCampaignApprovalService) {
+				as.On("RequestApproval", mock.Anything, 1, 123, testOpts).Return(nil, nil).Once()
+				m.On("SendNotification", mock.Anything, 1, 123, testOpts).Return(nil).Once()
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:  "test send is normal by default",
+			query: "?testGroup=qa",
+			setup: func(m *MockSendNotificationService, as *MockCampaignApprovalService) {
+				as.On("RequestApproval", mock.Anything, 1, 123, testOpts).Return(nil, nil).Once()
+				m.On("SendNotification", mock.Anything, 1, 123, testOpts).Return(nil).Once()
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "empty test group",
+			query:          "?testGroup=%20",
+			setup:          func(m *MockSendNotificationService, as *MockCampaignApprovalService) {},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(MockSendNotificationService)
+			as := new(MockCampaignApprovalService)
+			tt.setup(m, as)
+			h := handler.NewSendNotificationHandler(m, as, logger, timeout)
+
+			r := httptest.NewRequest(http.MethodPost, "/send-notification/123"+tt.query, strings.NewReader(tt.body))
+			r = mux.SetURLVars(r, map[string]string{"id": "123"})
+			r = injectUserID(r, 1)
+			w := httptest.NewRecorder()
+
+			h.SendNotification(w, r)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectPreview {
+				var got domain.CampaignPreview
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+				assert.Equal(t, *preview, got)
+			}
+			if strings.Contains(tt.query, "dryRun") {
+				m.AssertNotCalled(t, "SendNotification", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			m.AssertExpectations(t)
+			as.AssertExpectations(t)
+		})
+	}
+}
@@ -46,8 +46,9 @@ func (th *TemplateHandler) logError(msg string, r *http.Request, fields ...zap.F
 	th.logger.Error(msg, allFields...)
 }
 
-// Get retrieves all message templates for the authenticated user.
-// Responds with JSON-encoded list of templates or a 500 error.
+// Get retrieves a page of message templates for the authenticated user, filtered by the search,
+// createdFrom and createdTo query parameters and ordered by sort. Pages follow nextCursor of the
+// previous response, or limit and offset. Responds with 400 for malformed filters, sorts or cursors.
 func (th *TemplateHandler) Get(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), th.contextTimeout)
 	defer cancel()
@@ -61,17 +62,28 @@ func (th *TemplateHandler) Get(w http.ResponseWriter, r *http.Request) {
 	}
 
 	query := r.URL.Query()
-	limit, _ := strconv.Atoi(query.Get("limit"))
-	offset, _ := strconv.Atoi(query.Get("offset"))
+	filter, err := parseTemplateFilter(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	page := parsePageRequest(query)
 
-	templatesPage, err := th.service.GetTemplatesPageByUserID(ctx, userID, limit, offset)
+	templatesPage, nextCursor, err := th.service.GetTemplatesPageByUserID(ctx, userID, filter, page)
 	if err != nil {
-		th.logError("failed to get templates page", r, zap.Int("user_id", userID), zap.Error(err))
-		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		switch {
+		case errors.Is(err, domain.ErrInvalidSort):
+			http.Error(w, "Invalid sort", http.StatusBadRequest)
+		case errors.Is(err, domain.ErrInvalidCursor):
+			http.Error(w, "Invalid cursor", http.StatusBadRequest)
+		default:
+			th.logError("failed to get templates page", r, zap.Int("user_id", userID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
 		return
 	}
 
-	templatesCount, err := th.service.GetTemplatesCountByUserID(ctx, userID)
+	templatesCount, err := th.service.GetTemplatesCountByUserID(ctx, userID, filter)
 	if err != nil {
 		th.logError("failed to get templates count", r, zap.Int("user_id", userID), zap.Error(err))
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -81,8 +93,9 @@ func (th *TemplateHandler) Get(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	err = json.NewEncoder(w).Encode(&domain.GetTemplatesResponse{
-		Templates: templatesPage,
-		Total:     templatesCount,
+		Templates:  templatesPage,
+		Total:      templatesCount,
+		NextCursor: nextCursor,
 	})
 	if err != nil {
 		th.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
@@ -153,16 +166,17 @@ func (th *TemplateHandler) Post(w http.ResponseWriter, r *http.Request) {
 	}
 
 	newTmpl := &models.Template{
-		UserID: userID,
-		Name:   req.Name,
-		Body:   req.Body,
+		UserID:   userID,
+		Name:     req.Name,
+		Body:     req.Body,
+		SenderID: req.SenderID,
 	}
 
 	newTmpl, err = th.service.CreateTemplate(ctx, newTmpl)
 	if err != nil {
 		switch {
 		case errors.Is(err, domain.ErrInvalidTemplate):
-			http.Error(w, "Invalid template", http.StatusUnprocessableEntity)
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
 		case errors.Is(err, domain.ErrTemplateAlreadyExists):
 			http.Error(w, "Template already exists", http.StatusConflict)
 		default:
@@ -211,16 +225,17 @@ func (th *TemplateHandler) Put(w http.ResponseWriter, r *http.Request) {
 	}
 
 	updatedTmpl := &models.Template{
-		UserID: userID,
-		Name:   req.Name,
-		Body:   req.Body,
+		UserID:   userID,
+		Name:     req.Name,
+		Body:     req.Body,
+		SenderID: req.SenderID,
 	}
 
 	updatedTmpl, err = th.service.UpdateTemplate(ctx, userID, tmplID, updatedTmpl)
 	if err != nil {
 		switch {
 		case errors.Is(err, domain.ErrInvalidTemplate):
-			http.Error(w, "Invalid template", http.StatusUnprocessableEntity)
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
 		case errors.Is(err, domain.ErrTemplateNotExists):
 			http.Error(w, "Template not exists", http.StatusNotFound)
 		case errors.Is(err, domain.ErrTemplateAlreadyExists):
@@ -275,3 +290,193 @@ func (th *TemplateHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// GetVersions retrieves every version of a message template of the authenticated user, newest first.
+// Responds with 400 for a malformed ID or 404 if the template has no versions.
+func (th *TemplateHandler) GetVersions(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), th.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		th.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	tmplID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+
+	versions, err := th.service.GetTemplateVersions(ctx, userID, tmplID)
+	if err != nil {
+		if errors.Is(err, domain.ErrTemplateNotExists) {
+			http.Error(w, "Template does not exist", http.StatusNotFound)
+		} else {
+			th.logError("failed to get template versions", r, zap.Int("user_id", userID), zap.Int("template_id", tmplID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(versions)
+	if err != nil {
+		th.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// GetVersion retrieves a single version of a message template of the authenticated user.
+// Responds with 400 for a malformed ID or version, or 404 if there is no such version.
+func (th *TemplateHandler) GetVersion(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), th.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		th.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	vars := mux.Vars(r)
+	tmplID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+	version, err := strconv.Atoi(vars["version"])
+	if err != nil {
+		http.Error(w, "Invalid version", http.StatusBadRequest)
+		return
+	}
+
+	v, err := th.service.GetTemplateVersion(ctx, userID, tmplID, version)
+	if err != nil {
+		if errors.Is(err, domain.ErrTemplateVersionNotExists) {
+			http.Error(w, "Template version does not exist", http.StatusNotFound)
+		} else {
+			th.logError("failed to get template version", r, zap.Int("user_id", userID), zap.Int("template_id", tmplID), zap.Int("version", version), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(v)
+	if err != nil {
+		th.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// GetDiff compares the versions given by the from and to query parameters of a message template
+// of the authenticated user, word by word.
+// Responds with 400 for a malformed ID or versions, or 404 if either version doesn't exist.
+func (th *TemplateHandler) GetDiff(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), th.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		th.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	tmplID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	from, err := strconv.Atoi(query.Get("from"))
+	if err != nil {
+		http.Error(w, "Invalid from version", http.StatusBadRequest)
+		return
+	}
+	to, err := strconv.Atoi(query.Get("to"))
+	if err != nil {
+		http.Error(w, "Invalid to version", http.StatusBadRequest)
+		return
+	}
+
+	diff, err := th.service.DiffTemplateVersions(ctx, userID, tmplID, from, to)
+	if err != nil {
+		if errors.Is(err, domain.ErrTemplateVersionNotExists) {
+			http.Error(w, "Template version does not exist", http.StatusNotFound)
+		} else {
+			th.logError("failed to diff template versions", r, zap.Int("user_id", userID), zap.Int("template_id", tmplID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(diff)
+	if err != nil {
+		th.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// Restore brings a past version of a message template of the authenticated user back as its new
+// version. Responds with 200 and the updated template, 400 for a malformed ID or version, 404 if
+// the template or the version doesn't exist, 409 if another template took its name since, or 422
+// if the version no longer passes validation.
+func (th *TemplateHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), th.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		th.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	vars := mux.Vars(r)
+	tmplID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+	version, err := strconv.Atoi(vars["version"])
+	if err != nil {
+		http.Error(w, "Invalid version", http.StatusBadRequest)
+		return
+	}
+
+	tmpl, err := th.service.RestoreTemplateVersion(ctx, userID, tmplID, version)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidTemplate):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		case errors.Is(err, domain.ErrTemplateVersionNotExists):
+			http.Error(w, "Template version does not exist", http.StatusNotFound)
+		case errors.Is(err, domain.ErrTemplateNotExists):
+			http.Error(w, "Template does not exist", http.StatusNotFound)
+		case errors.Is(err, domain.ErrTemplateAlreadyExists):
+			http.Error(w, "Template already exists", http.StatusConflict)
+		default:
+			th.logError("failed to restore template version", r, zap.Int("user_id", userID), zap.Int("template_id", tmplID), zap.Int("version", version), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(tmpl)
+	if err != nil {
+		th.logError("failed to write json to client", r, zap.Error(err))
+	}
+}
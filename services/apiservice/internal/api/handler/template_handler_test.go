@@ -6,10 +6,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/textdiff"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -18,13 +20,15 @@ import (
 // --- GET /templates ---
 func TestTemplateHandler_Get(t *testing.T) {
 	type resp struct {
-		Templates []*models.Template `json:"templates"`
-		Total     int                `json:"total"`
+		Templates  []*models.Template `json:"templates"`
+		Total      int                `json:"total"`
+		NextCursor string             `json:"nextCursor"`
 	}
 
 	tests := []struct {
 		name       string
 		userID     int
+		query      string
 		setup      func(m *MockTemplateService)
 		wantStatus int
 		wantBody   resp
@@ -34,11 +38,11 @@ func TestTemplateHandler_Get(t *testing.T) {
 			userID: 1,
 			setup: func(m *MockTemplateService) {
 				m.
-					On("GetTemplatesPageByUserID", mock.Anything, 1, mock.Anything, mock.Anything).
-					Return([]*models.Template{{ID: 1, UserID: 1, Name: "T1", Body: "B1"}}, nil).
+					On("GetTemplatesPageByUserID", mock.Anything, 1, domain.TemplateFilter{}, domain.PageRequest{}).
+					Return([]*models.Template{{ID: 1, UserID: 1, Name: "T1", Body: "B1"}}, "", nil).
 					Once()
 				m.
-					On("GetTemplatesCountByUserID", mock.Anything, 1).
+					On("GetTemplatesCountByUserID", mock.Anything, 1, domain.TemplateFilter{}).
 					Return(1, nil).
 					Once()
 			},
@@ -50,13 +54,56 @@ func TestTemplateHandler_Get(t *testing.T) {
 				Total: 1,
 			},
 		},
+		{
+			name:   "filtered and sorted",
+			userID: 1,
+			query:  "?search=flood&createdTo=2025-06-01T00:00:00Z&sort=-createdAt&limit=10",
+			setup: func(m *MockTemplateService) {
+				filter := domain.TemplateFilter{Search: "flood", CreatedTo: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)}
+				m.
+					On("GetTemplatesPageByUserID", mock.Anything, 1, filter, domain.PageRequest{Limit: 10, Sort: domain.Sort{Field: "createdAt", Desc: true}}).
+					Return([]*models.Template{{ID: 1, UserID: 1, Name: "Flood", Body: "B1"}}, "next", nil).
+					Once()
+				m.
+					On("GetTemplatesCountByUserID", mock.Anything, 1, filter).
+					Return(11, nil).
+					Once()
+			},
+			wantStatus: http.StatusOK,
+			wantBody: resp{
+				Templates: []*models.Template{
+					{ID: 1, UserID: 1, Name: "Flood", Body: "B1"},
+				},
+				Total:      11,
+				NextCursor: "next",
+			},
+		},
+		{
+			name:       "invalid created range",
+			userID:     1,
+			query:      "?createdFrom=2025-01-01",
+			setup:      func(m *MockTemplateService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid sort",
+			userID: 1,
+			query:  "?sort=body",
+			setup: func(m *MockTemplateService) {
+				m.
+					On("GetTemplatesPageByUserID", mock.Anything, 1, domain.TemplateFilter{}, domain.PageRequest{Sort: domain.Sort{Field: "body"}}).
+					Return(([]*models.Template)(nil), "", domain.ErrInvalidSort).
+					Once()
+			},
+			wantStatus: http.StatusBadRequest,
+		},
 		{
 			name:   "service error",
 			userID: 2,
 			setup: func(m *MockTemplateService) {
 				m.
 					On("GetTemplatesPageByUserID", mock.Anything, 2, mock.Anything, mock.Anything).
-					Return(([]*models.Template)(nil), assert.AnError).
+					Return(([]*models.Template)(nil), "", assert.AnError).
 					Once()
 			},
 			wantStatus: http.StatusInternalServerError,
@@ -69,7 +116,7 @@ func TestTemplateHandler_Get(t *testing.T) {
 			tc.setup(m)
 			h := handler.NewTemplateHandler(m, logger, timeout)
 
-			req := httptest.NewRequest("GET", "/templates", nil)
+			req := httptest.NewRequest("GET", "/templates"+tc.query, nil)
 			req = injectUserID(req, tc.userID)
 			rr := httptest.NewRecorder()
 
@@ -228,6 +275,19 @@ func TestTemplateHandler_Post(t *testing.T) {
 			wantStatus: http.StatusCreated,
 			wantBody:   &models.Template{ID: 7, UserID: 1, Name: "N", Body: "B"},
 		},
+		{
+			name:   "success with sender id",
+			userID: 1,
+			body:   domain.PostTemplateRequest{Name: "N", Body: "B", SenderID: "CityAlert"},
+			setup: func(m *MockTemplateService) {
+				m.
+					On("CreateTemplate", mock.Anything, &models.Template{UserID: 1, Name: "N", Body: "B", SenderID: "CityAlert"}).
+					Return(&models.Template{ID: 8, UserID: 1, Name: "N", Body: "B", SenderID: "CityAlert"}, nil).
+					Once()
+			},
+			wantStatus: http.StatusCreated,
+			wantBody:   &models.Template{ID: 8, UserID: 1, Name: "N", Body: "B", SenderID: "CityAlert"},
+		},
 	}
 
 	for _, tc := range tests {
@@ -456,3 +516,260 @@ func TestTemplateHandler_Delete(t *testing.T) {
 		})
 	}
 }
+
+// --- GET /templates/{id}/versions ---
+func TestTemplateHandler_GetVersions(t *testing.T) {
+	versions := []*models.TemplateVersion{
+		{TemplateID: 4, Version: 2, Name: "n", Body: "new"},
+		{TemplateID: 4, Version: 1, Name: "n", Body: "old"},
+	}
+
+	tests := []struct {
+		name       string
+		idParam    string
+		setup      func(m *MockTemplateService)
+		wantStatus int
+	}{
+		{
+			name:       "bad id",
+			idParam:    "x",
+			setup:      func(m *MockTemplateService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "not found",
+			idParam: "5",
+			setup: func(m *MockTemplateService) {
+				m.On("GetTemplateVersions", mock.Anything, 1, 5).Return(nil, domain.ErrTemplateNotExists).Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:    "success",
+			idParam: "4",
+			setup: func(m *MockTemplateService) {
+				m.On("GetTemplateVersions", mock.Anything, 1, 4).Return(versions, nil).Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockTemplateService)
+			tc.setup(m)
+			h := handler.NewTemplateHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodGet, "/templates/"+tc.idParam+"/versions", nil)
+			req = injectUserID(req, 1)
+			req = mux.SetURLVars(req, map[string]string{"id": tc.idParam})
+			rr := httptest.NewRecorder()
+
+			h.GetVersions(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			if tc.wantStatus == http.StatusOK {
+				var got []*models.TemplateVersion
+				assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+				assert.Equal(t, versions, got)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+// --- GET /templates/{id}/versions/{version} ---
+func TestTemplateHandler_GetVersion(t *testing.T) {
+	tests := []struct {
+		name         string
+		versionParam string
+		setup        func(m *MockTemplateService)
+		wantStatus   int
+	}{
+		{
+			name:         "bad version",
+			versionParam: "first",
+			setup:        func(m *MockTemplateService) {},
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name:         "not found",
+			versionParam: "9",
+			setup: func(m *MockTemplateService) {
+				m.On("GetTemplateVersion", mock.Anything, 1, 4, 9).Return(nil, domain.ErrTemplateVersionNotExists).Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:         "success",
+			versionParam: "1",
+			setup: func(m *MockTemplateService) {
+				m.
+					On("GetTemplateVersion", mock.Anything, 1, 4, 1).
+					Return(&models.TemplateVersion{TemplateID: 4, Version: 1, Name: "n", Body: "old"}, nil).
+					Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockTemplateService)
+			tc.setup(m)
+			h := handler.NewTemplateHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodGet, "/templates/4/versions/"+tc.versionParam, nil)
+			req = injectUserID(req, 1)
+			req = mux.SetURLVars(req, map[string]string{"id": "4", "version": tc.versionParam})
+			rr := httptest.NewRecorder()
+
+			h.GetVersion(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+// --- GET /templates/{id}/diff ---
+func TestTemplateHandler_GetDiff(t *testing.T) {
+	diff := &domain.TemplateDiff{
+		TemplateID: 4,
+		From:       1,
+		To:         2,
+		Name:       []textdiff.Edit{{Op: textdiff.OpEqual, Text: "n"}},
+		Body:       []textdiff.Edit{{Op: textdiff.OpDelete, Text: "old"}, {Op: textdiff.OpInsert, Text: "new"}},
+		SenderID:   []textdiff.Edit{},
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		setup      func(m *MockTemplateService)
+		wantStatus int
+	}{
+		{
+			name:       "missing versions",
+			query:      "",
+			setup:      func(m *MockTemplateService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "bad to version",
+			query:      "?from=1&to=last",
+			setup:      func(m *MockTemplateService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "version not found",
+			query: "?from=1&to=9",
+			setup: func(m *MockTemplateService) {
+				m.On("DiffTemplateVersions", mock.Anything, 1, 4, 1, 9).Return(nil, domain.ErrTemplateVersionNotExists).Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:  "success",
+			query: "?from=1&to=2",
+			setup: func(m *MockTemplateService) {
+				m.On("DiffTemplateVersions", mock.Anything, 1, 4, 1, 2).Return(diff, nil).Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockTemplateService)
+			tc.setup(m)
+			h := handler.NewTemplateHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodGet, "/templates/4/diff"+tc.query, nil)
+			req = injectUserID(req, 1)
+			req = mux.SetURLVars(req, map[string]string{"id": "4"})
+			rr := httptest.NewRecorder()
+
+			h.GetDiff(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			if tc.wantStatus == http.StatusOK {
+				var got domain.TemplateDiff
+				assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+				assert.Equal(t, *diff, got)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+// --- POST /templates/{id}/versions/{version}/restore ---
+func TestTemplateHandler_Restore(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(m *MockTemplateService)
+		wantStatus int
+	}{
+		{
+			name: "version not found",
+			setup: func(m *MockTemplateService) {
+				m.On("RestoreTemplateVersion", mock.Anything, 1, 4, 1).Return(nil, domain.ErrTemplateVersionNotExists).Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "template deleted",
+			setup: func(m *MockTemplateService) {
+				m.On("RestoreTemplateVersion", mock.Anything, 1, 4, 1).Return(nil, domain.ErrTemplateNotExists).Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "name taken",
+			setup: func(m *MockTemplateService) {
+				m.On("RestoreTemplateVersion", mock.Anything, 1, 4, 1).Return(nil, domain.ErrTemplateAlreadyExists).Once()
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name: "no longer valid",
+			setup: func(m *MockTemplateService) {
+				m.On("RestoreTemplateVersion", mock.Anything, 1, 4, 1).Return(nil, domain.ErrInvalidTemplate).Once()
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "success",
+			setup: func(m *MockTemplateService) {
+				m.
+					On("RestoreTemplateVersion", mock.Anything, 1, 4, 1).
+					Return(&models.Template{ID: 4, UserID: 1, Name: "n", Body: "old", Version: 3}, nil).
+					Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockTemplateService)
+			tc.setup(m)
+			h := handler.NewTemplateHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodPost, "/templates/4/versions/1/restore", nil)
+			req = injectUserID(req, 1)
+			req = mux.SetURLVars(req, map[string]string{"id": "4", "version": "1"})
+			rr := httptest.NewRecorder()
+
+			h.Restore(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			if tc.wantStatus == http.StatusOK {
+				var got models.Template
+				assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+				assert.Equal(t, 3, got.Version)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/contextkeys"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// TriggerHandler handles HTTP requests managing the triggers of a user.
+type TriggerHandler struct {
+	service        domain.TriggerService
+	logger         *zap.Logger
+	contextTimeout time.Duration
+}
+
+// NewTriggerHandler creates a new TriggerHandler with the given service,
+// structured logger, and per-request timeout duration.
+func NewTriggerHandler(s domain.TriggerService, logger *zap.Logger, timeout time.Duration) *TriggerHandler {
+	return &TriggerHandler{
+		service:        s,
+		logger:         logger,
+		contextTimeout: timeout,
+	}
+}
+
+func (th *TriggerHandler) logError(msg string, r *http.Request, fields ...zap.Field) {
+	cid := r.Header.Get("X-Correlation-ID")
+
+	allFields := []zap.Field{
+		zap.String("correlation_id", cid),
+		zap.String("uri", r.RequestURI),
+		zap.String("client_ip", r.RemoteAddr),
+		zap.String("user_agent", r.UserAgent()),
+	}
+	allFields = append(allFields, fields...)
+
+	th.logger.Error(msg, allFields...)
+}
+
+// Get handles GET /triggers requests to list the triggers of the user.
+func (th *TriggerHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), th.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		th.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	triggers, err := th.service.GetTriggersByUserID(ctx, userID)
+	if err != nil {
+		th.logError("failed to get triggers", r, zap.Int("user_id", userID), zap.Error(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(triggers)
+	if err != nil {
+		th.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// GetByID handles GET /triggers/{id} requests. Returns 404 if the user has no such trigger.
+func (th *TriggerHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), th.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		th.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	triggerID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := th.service.GetTriggerByID(ctx, userID, triggerID)
+	if err != nil {
+		if errors.Is(err, domain.ErrTriggerNotExists) {
+			http.Error(w, "Trigger does not exist", http.StatusNotFound)
+		} else {
+			th.logError("failed to get trigger", r, zap.Int("user_id", userID), zap.Int("id", triggerID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(trigger)
+	if err != nil {
+		th.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// Post handles POST /triggers requests to create a trigger.
+// Returns 201 Created with the trigger, 422 for an invalid trigger or a template the user
+// doesn't have, or 409 if the user already has a trigger with the name.
+func (th *TriggerHandler) Post(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), th.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		th.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	var req domain.TriggerRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := th.service.CreateTrigger(ctx, &models.Trigger{
+		UserID:             userID,
+		Name:               req.Name,
+		Matchers:           req.Matchers,
+		TemplateID:         req.TemplateID,
+		ResolvedTemplateID: req.ResolvedTemplateID,
+		Group:              req.Group,
+		Priority:           req.Priority,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidTrigger), errors.Is(err, domain.ErrInvalidContactGroup), errors.Is(err, domain.ErrInvalidPriority):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		case errors.Is(err, domain.ErrTemplateNotExists):
+			http.Error(w, "Template does not exist", http.StatusUnprocessableEntity)
+		case errors.Is(err, domain.ErrTriggerAlreadyExists):
+			http.Error(w, "Trigger already exists", http.StatusConflict)
+		default:
+			th.logError("failed to create trigger", r, zap.Int("user_id", userID), zap.String("name", req.Name), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(trigger)
+	if err != nil {
+		th.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// Put handles PUT /triggers/{id} requests to replace a trigger.
+// Returns 404 if the user has no such trigger.
+func (th *TriggerHandler) Put(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), th.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		th.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	triggerID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+
+	var req domain.TriggerRequest
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := th.service.UpdateTrigger(ctx, userID, triggerID, &models.Trigger{
+		UserID:             userID,
+		Name:               req.Name,
+		Matchers:           req.Matchers,
+		TemplateID:         req.TemplateID,
+		ResolvedTemplateID: req.ResolvedTemplateID,
+		Group:              req.Group,
+		Priority:           req.Priority,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidTrigger), errors.Is(err, domain.ErrInvalidContactGroup), errors.Is(err, domain.ErrInvalidPriority):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		case errors.Is(err, domain.ErrTemplateNotExists):
+			http.Error(w, "Template does not exist", http.StatusUnprocessableEntity)
+		case errors.Is(err, domain.ErrTriggerNotExists):
+			http.Error(w, "Trigger does not exist", http.StatusNotFound)
+		case errors.Is(err, domain.ErrTriggerAlreadyExists):
+			http.Error(w, "Trigger already exists", http.StatusConflict)
+		default:
+			th.logError("failed to update trigger", r, zap.Int("user_id", userID), zap.Int("id", triggerID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(trigger)
+	if err != nil {
+		th.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// Delete handles DELETE /triggers/{id} requests.
+// Returns 204 No Content on success, or 404 if the trigger doesn't exist.
+func (th *TriggerHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), th.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		th.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	triggerID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+
+	err = th.service.DeleteTrigger(ctx, userID, triggerID)
+	if err != nil {
+		if errors.Is(err, domain.ErrTriggerNotExists) {
+			http.Error(w, "Trigger does not exist", http.StatusNotFound)
+		} else {
+			th.logError("failed to delete trigger", r, zap.Int("user_id", userID), zap.Int("id", triggerID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
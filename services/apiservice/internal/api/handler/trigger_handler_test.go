@@ -0,0 +1,241 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testTrigger = &models.Trigger{
+	ID: 3, UserID: 1, Name: "Disk full", TemplateID: 7, Group: "ops", Priority: models.PriorityCritical,
+	Matchers: []models.LabelMatcher{{Name: "alertname", Type: models.MatchEqual, Value: "DiskFull"}},
+}
+
+// --- POST /triggers ---
+func TestTriggerHandler_Post(t *testing.T) {
+	trigger := &models.Trigger{UserID: 1, Name: testTrigger.Name, Matchers: testTrigger.Matchers, TemplateID: 7, Group: "ops"}
+	body := `{"name":"Disk full","matchers":[{"name":"alertname","type":"=","value":"DiskFull"}],"templateId":7,"group":"ops"}`
+
+	tests := []struct {
+		name       string
+		body       string
+		setup      func(m *MockTriggerService)
+		wantStatus int
+	}{
+		{
+			name: "created",
+			body: body,
+			setup: func(m *MockTriggerService) {
+				m.On("CreateTrigger", mock.Anything, trigger).Return(testTrigger, nil).Once()
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "bad json",
+			body:       `{`,
+			setup:      func(m *MockTriggerService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid matchers",
+			body: body,
+			setup: func(m *MockTriggerService) {
+				m.On("CreateTrigger", mock.Anything, trigger).Return(nil, domain.ErrInvalidTriggerMatchers).Once()
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "invalid priority",
+			body: body,
+			setup: func(m *MockTriggerService) {
+				m.On("CreateTrigger", mock.Anything, trigger).Return(nil, domain.ErrInvalidPriority).Once()
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "unknown template",
+			body: body,
+			setup: func(m *MockTriggerService) {
+				m.On("CreateTrigger", mock.Anything, trigger).Return(nil, domain.ErrTemplateNotExists).Once()
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "already exists",
+			body: body,
+			setup: func(m *MockTriggerService) {
+				m.On("CreateTrigger", mock.Anything, trigger).Return(nil, domain.ErrTriggerAlreadyExists).Once()
+			},
+			wantStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockTriggerService)
+			tc.setup(m)
+			h := handler.NewTriggerHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodPost, "/triggers", strings.NewReader(tc.body))
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.Post(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+// --- GET /triggers/{id} ---
+func TestTriggerHandler_GetByID(t *testing.T) {
+	tests := []struct {
+		name       string
+		idParam    string
+		setup      func(m *MockTriggerService)
+		wantStatus int
+	}{
+		{
+			name:    "success",
+			idParam: "3",
+			setup: func(m *MockTriggerService) {
+				m.On("GetTriggerByID", mock.Anything, 1, 3).Return(testTrigger, nil).Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid id",
+			idParam:    "abc",
+			setup:      func(m *MockTriggerService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "not found",
+			idParam: "3",
+			setup: func(m *MockTriggerService) {
+				m.On("GetTriggerByID", mock.Anything, 1, 3).Return(nil, domain.ErrTriggerNotExists).Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockTriggerService)
+			tc.setup(m)
+			h := handler.NewTriggerHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodGet, "/triggers/"+tc.idParam, nil)
+			req = mux.SetURLVars(req, map[string]string{"id": tc.idParam})
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.GetByID(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			if tc.wantStatus == http.StatusOK {
+				var got models.Trigger
+				assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+				assert.Equal(t, *testTrigger, got)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+// --- PUT /triggers/{id} ---
+func TestTriggerHandler_Put(t *testing.T) {
+	trigger := &models.Trigger{UserID: 1, Name: testTrigger.Name, Matchers: testTrigger.Matchers, TemplateID: 7}
+	body := `{"name":"Disk full","matchers":[{"name":"alertname","type":"=","value":"DiskFull"}],"templateId":7}`
+
+	tests := []struct {
+		name       string
+		setup      func(m *MockTriggerService)
+		wantStatus int
+	}{
+		{
+			name: "updated",
+			setup: func(m *MockTriggerService) {
+				m.On("UpdateTrigger", mock.Anything, 1, 3, trigger).Return(testTrigger, nil).Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "not found",
+			setup: func(m *MockTriggerService) {
+				m.On("UpdateTrigger", mock.Anything, 1, 3, trigger).Return(nil, domain.ErrTriggerNotExists).Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockTriggerService)
+			tc.setup(m)
+			h := handler.NewTriggerHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodPut, "/triggers/3", strings.NewReader(body))
+			req = mux.SetURLVars(req, map[string]string{"id": "3"})
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.Put(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+// --- DELETE /triggers/{id} ---
+func TestTriggerHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(m *MockTriggerService)
+		wantStatus int
+	}{
+		{
+			name: "deleted",
+			setup: func(m *MockTriggerService) {
+				m.On("DeleteTrigger", mock.Anything, 1, 3).Return(nil).Once()
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "not found",
+			setup: func(m *MockTriggerService) {
+				m.On("DeleteTrigger", mock.Anything, 1, 3).Return(domain.ErrTriggerNotExists).Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockTriggerService)
+			tc.setup(m)
+			h := handler.NewTriggerHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodDelete, "/triggers/3", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "3"})
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.Delete(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			m.AssertExpectations(t)
+		})
+	}
+}
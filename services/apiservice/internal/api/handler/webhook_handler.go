@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/contextkeys"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"go.uber.org/zap"
+)
+
+// WebhookHandler handles alerts posted by monitoring systems, evaluating the triggers of the user.
+type WebhookHandler struct {
+	service        domain.TriggerService
+	logger         *zap.Logger
+	contextTimeout time.Duration
+}
+
+// NewWebhookHandler creates a new WebhookHandler with the given service,
+// structured logger, and per-request timeout duration.
+func NewWebhookHandler(s domain.TriggerService, logger *zap.Logger, timeout time.Duration) *WebhookHandler {
+	return &WebhookHandler{
+		service:        s,
+		logger:         logger,
+		contextTimeout: timeout,
+	}
+}
+
+func (wh *WebhookHandler) logError(msg string, r *http.Request, fields ...zap.Field) {
+	cid := r.Header.Get("X-Correlation-ID")
+
+	allFields := []zap.Field{
+		zap.String("correlation_id", cid),
+		zap.String("uri", r.RequestURI),
+		zap.String("client_ip", r.RemoteAddr),
+		zap.String("user_agent", r.UserAgent()),
+	}
+	allFields = append(allFields, fields...)
+
+	wh.logger.Error(msg, allFields...)
+}
+
+// PostAlertmanager handles POST /webhooks/alertmanager requests carrying the payload of the
+// Prometheus Alertmanager webhook receiver.
+// Responds with 200 OK and what was done with the alerts, 400 for malformed JSON,
+// 422 for alerts that can't be handled, or 413 for payloads larger than 1MB.
+func (wh *WebhookHandler) PostAlertmanager(w http.ResponseWriter, r *http.Request) {
+	var payload domain.AlertmanagerWebhook
+	wh.handleAlerts(w, r, &payload, func() []*domain.WebhookAlert {
+		return payload.Alerts
+	})
+}
+
+// PostGeneric handles POST /webhooks/generic requests carrying a single alert, for monitoring
+// systems other than Alertmanager. Responds like PostAlertmanager.
+func (wh *WebhookHandler) PostGeneric(w http.ResponseWriter, r *http.Request) {
+	var alert domain.WebhookAlert
+	wh.handleAlerts(w, r, &alert, func() []*domain.WebhookAlert {
+		return []*domain.WebhookAlert{&alert}
+	})
+}
+
+// handleAlerts decodes the body into payload and handles the alerts returned by alerts.
+func (wh *WebhookHandler) handleAlerts(w http.ResponseWriter, r *http.Request, payload any, alerts func() []*domain.WebhookAlert) {
+	ctx, cancel := context.WithTimeout(r.Context(), wh.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		wh.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1MB
+
+	err := json.NewDecoder(r.Body).Decode(payload)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		} else {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		}
+		return
+	}
+
+	res, err := wh.service.HandleAlerts(ctx, userID, alerts())
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidWebhook) {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		} else {
+			wh.logError("failed to handle webhook alerts", r, zap.Int("user_id", userID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(res)
+	if err != nil {
+		wh.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
@@ -0,0 +1,145 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// alertmanagerPayload is a notification of the Alertmanager webhook receiver, trimmed to the essentials.
+const alertmanagerPayload = `{
+  "version": "4",
+  "groupKey": "{}:{alertname=\"DiskFull\"}",
+  "status": "firing",
+  "receiver": "ens",
+  "groupLabels": {"alertname": "DiskFull"},
+  "alerts": [
+    {
+      "status": "firing",
+      "labels": {"alertname": "DiskFull", "instance": "db-1", "severity": "critical"},
+      "annotations": {"summary": "Disk is almost full"},
+      "startsAt": "2026-10-19T10:00:00Z",
+      "endsAt": "0001-01-01T00:00:00Z",
+      "fingerprint": "c7f5a1b2d3e4f506"
+    },
+    {
+      "status": "resolved",
+      "labels": {"alertname": "DiskFull", "instance": "db-2", "severity": "critical"},
+      "startsAt": "2026-10-19T09:00:00Z",
+      "endsAt": "2026-10-19T09:30:00Z",
+      "fingerprint": "a1b2c3d4e5f60718"
+    }
+  ]
+}`
+
+func TestWebhookHandler_PostAlertmanager(t *testing.T) {
+	diskStart := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	resolvedStart := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	alerts := []*domain.WebhookAlert{
+		{
+			Status:      domain.AlertStatusFiring,
+			Labels:      map[string]string{"alertname": "DiskFull", "instance": "db-1", "severity": "critical"},
+			Annotations: map[string]string{"summary": "Disk is almost full"},
+			Fingerprint: "c7f5a1b2d3e4f506",
+			StartsAt:    &diskStart,
+		},
+		{
+			Status:      domain.AlertStatusResolved,
+			Labels:      map[string]string{"alertname": "DiskFull", "instance": "db-2", "severity": "critical"},
+			Fingerprint: "a1b2c3d4e5f60718",
+			StartsAt:    &resolvedStart,
+		},
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		setup      func(m *MockTriggerService)
+		wantStatus int
+	}{
+		{
+			name: "handled",
+			body: alertmanagerPayload,
+			setup: func(m *MockTriggerService) {
+				m.On("HandleAlerts", mock.Anything, 1, alerts).Return(&domain.WebhookResult{Fired: 1, Resolved: 1}, nil).Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "bad json",
+			body:       `{"alerts":`,
+			setup:      func(m *MockTriggerService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "too large",
+			body:       fmt.Sprintf(`{"receiver":"%s"}`, strings.Repeat("a", 1<<20)),
+			setup:      func(m *MockTriggerService) {},
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "invalid alerts",
+			body: alertmanagerPayload,
+			setup: func(m *MockTriggerService) {
+				m.On("HandleAlerts", mock.Anything, 1, alerts).Return(nil, fmt.Errorf("%w: alert has no labels", domain.ErrInvalidWebhook)).Once()
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "service error",
+			body: alertmanagerPayload,
+			setup: func(m *MockTriggerService) {
+				m.On("HandleAlerts", mock.Anything, 1, alerts).Return(nil, assert.AnError).Once()
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockTriggerService)
+			tc.setup(m)
+			h := handler.NewWebhookHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks/alertmanager", strings.NewReader(tc.body))
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.PostAlertmanager(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			if tc.wantStatus == http.StatusOK {
+				var got domain.WebhookResult
+				assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+				assert.Equal(t, domain.WebhookResult{Fired: 1, Resolved: 1}, got)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookHandler_PostGeneric(t *testing.T) {
+	m := new(MockTriggerService)
+	m.On("HandleAlerts", mock.Anything, 1, []*domain.WebhookAlert{
+		{Status: domain.AlertStatusFiring, Labels: map[string]string{"service": "payments"}},
+	}).Return(&domain.WebhookResult{Fired: 1}, nil).Once()
+	h := handler.NewWebhookHandler(m, logger, timeout)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/generic", strings.NewReader(`{"status":"firing","labels":{"service":"payments"}}`))
+	req = injectUserID(req, 1)
+	rr := httptest.NewRecorder()
+
+	h.PostGeneric(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	m.AssertExpectations(t)
+}
@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/contextkeys"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// ZoneHandler handles HTTP requests managing the saved target zones of a user.
+type ZoneHandler struct {
+	service        domain.ZoneService
+	logger         *zap.Logger
+	contextTimeout time.Duration
+}
+
+// NewZoneHandler creates a new ZoneHandler with the given service,
+// structured logger, and per-request timeout duration.
+func NewZoneHandler(s domain.ZoneService, logger *zap.Logger, timeout time.Duration) *ZoneHandler {
+	return &ZoneHandler{
+		service:        s,
+		logger:         logger,
+		contextTimeout: timeout,
+	}
+}
+
+func (zh *ZoneHandler) logError(msg string, r *http.Request, fields ...zap.Field) {
+	cid := r.Header.Get("X-Correlation-ID")
+
+	allFields := []zap.Field{
+		zap.String("correlation_id", cid),
+		zap.String("uri", r.RequestURI),
+		zap.String("client_ip", r.RemoteAddr),
+		zap.String("user_agent", r.UserAgent()),
+	}
+	allFields = append(allFields, fields...)
+
+	zh.logger.Error(msg, allFields...)
+}
+
+// Get handles GET /zones requests to list the zones of the user.
+func (zh *ZoneHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), zh.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		zh.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	zones, err := zh.service.GetZonesByUserID(ctx, userID)
+	if err != nil {
+		zh.logError("failed to get zones", r, zap.Int("user_id", userID), zap.Error(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(zones)
+	if err != nil {
+		zh.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// GetByID handles GET /zones/{id} requests. Returns 404 if the user has no such zone.
+func (zh *ZoneHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), zh.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		zh.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	zoneID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+
+	zone, err := zh.service.GetZoneByID(ctx, userID, zoneID)
+	if err != nil {
+		if errors.Is(err, domain.ErrZoneNotExists) {
+			http.Error(w, "Zone does not exist", http.StatusNotFound)
+		} else {
+			zh.logError("failed to get zone", r, zap.Int("user_id", userID), zap.Int("id", zoneID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(zone)
+	if err != nil {
+		zh.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// Post handles POST /zones requests to save a new zone.
+// Returns 201 Created with the zone, 422 for an invalid name or area or 409 if the
+// user already has a zone with the name.
+func (zh *ZoneHandler) Post(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), zh.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		zh.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	var req domain.ZoneRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	zone, err := zh.service.CreateZone(ctx, &models.Zone{
+		UserID: userID,
+		Name:   req.Name,
+		Area:   req.Area,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidZone), errors.Is(err, domain.ErrInvalidArea):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		case errors.Is(err, domain.ErrZoneAlreadyExists):
+			http.Error(w, "Zone already exists", http.StatusConflict)
+		default:
+			zh.logError("failed to create zone", r, zap.Int("user_id", userID), zap.String("name", req.Name), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(zone)
+	if err != nil {
+		zh.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// Put handles PUT /zones/{id} requests to replace the name and area of a zone.
+// Returns 404 if the user has no such zone.
+func (zh *ZoneHandler) Put(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), zh.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		zh.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	zoneID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+
+	var req domain.ZoneRequest
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	zone, err := zh.service.UpdateZone(ctx, userID, zoneID, &models.Zone{
+		UserID: userID,
+		Name:   req.Name,
+		Area:   req.Area,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidZone), errors.Is(err, domain.ErrInvalidArea):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		case errors.Is(err, domain.ErrZoneNotExists):
+			http.Error(w, "Zone does not exist", http.StatusNotFound)
+		case errors.Is(err, domain.ErrZoneAlreadyExists):
+			http.Error(w, "Zone already exists", http.StatusConflict)
+		default:
+			zh.logError("failed to update zone", r, zap.Int("user_id", userID), zap.Int("id", zoneID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(zone)
+	if err != nil {
+		zh.logError("failed to write json to client", r, zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// Delete handles DELETE /zones/{id} requests.
+// Returns 204 No Content on success, or 404 if the zone doesn't exist.
+func (zh *ZoneHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), zh.contextTimeout)
+	defer cancel()
+
+	rawUserID := ctx.Value(contextkeys.UserID)
+	userID, ok := rawUserID.(int)
+	if !ok {
+		zh.logError("userID context value is not int", r, zap.Any("user_id", rawUserID))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	zoneID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+
+	err = zh.service.DeleteZone(ctx, userID, zoneID)
+	if err != nil {
+		if errors.Is(err, domain.ErrZoneNotExists) {
+			http.Error(w, "Zone does not exist", http.StatusNotFound)
+		} else {
+			zh.logError("failed to delete zone", r, zap.Int("user_id", userID), zap.Int("id", zoneID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
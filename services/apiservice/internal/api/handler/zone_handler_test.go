@@ -0,0 +1,186 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/geo"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testZone = &models.Zone{ID: 3, UserID: 1, Name: "Center", Area: geo.Area{Circle: &geo.Circle{Lat: 55.75, Lon: 37.62, Radius: 2000}}}
+
+// --- POST /zones ---
+func TestZoneHandler_Post(t *testing.T) {
+	zone := &models.Zone{UserID: 1, Name: testZone.Name, Area: testZone.Area}
+	body := `{"name":"Center","area":{"circle":{"lat":55.75,"lon":37.62,"radius":2000}}}`
+
+	tests := []struct {
+		name       string
+		body       string
+		setup      func(m *MockZoneService)
+		wantStatus int
+	}{
+		{
+			name: "created",
+			body: body,
+			setup: func(m *MockZoneService) {
+				m.On("CreateZone", mock.Anything, zone).Return(testZone, nil).Once()
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "bad json",
+			body:       `{`,
+			setup:      func(m *MockZoneService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid name",
+			body: body,
+			setup: func(m *MockZoneService) {
+				m.On("CreateZone", mock.Anything, zone).Return(nil, domain.ErrInvalidZoneName).Once()
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "invalid area",
+			body: body,
+			setup: func(m *MockZoneService) {
+				m.On("CreateZone", mock.Anything, zone).Return(nil, domain.ErrInvalidArea).Once()
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "already exists",
+			body: body,
+			setup: func(m *MockZoneService) {
+				m.On("CreateZone", mock.Anything, zone).Return(nil, domain.ErrZoneAlreadyExists).Once()
+			},
+			wantStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockZoneService)
+			tc.setup(m)
+			h := handler.NewZoneHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodPost, "/zones", strings.NewReader(tc.body))
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.Post(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+// --- GET /zones/{id} ---
+func TestZoneHandler_GetByID(t *testing.T) {
+	tests := []struct {
+		name       string
+		idParam    string
+		setup      func(m *MockZoneService)
+		wantStatus int
+	}{
+		{
+			name:    "success",
+			idParam: "3",
+			setup: func(m *MockZoneService) {
+				m.On("GetZoneByID", mock.Anything, 1, 3).Return(testZone, nil).Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid id",
+			idParam:    "abc",
+			setup:      func(m *MockZoneService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "not found",
+			idParam: "3",
+			setup: func(m *MockZoneService) {
+				m.On("GetZoneByID", mock.Anything, 1, 3).Return(nil, domain.ErrZoneNotExists).Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockZoneService)
+			tc.setup(m)
+			h := handler.NewZoneHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodGet, "/zones/"+tc.idParam, nil)
+			req = mux.SetURLVars(req, map[string]string{"id": tc.idParam})
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.GetByID(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			if tc.wantStatus == http.StatusOK {
+				var got models.Zone
+				assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+				assert.Equal(t, *testZone, got)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+// --- DELETE /zones/{id} ---
+func TestZoneHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(m *MockZoneService)
+		wantStatus int
+	}{
+		{
+			name: "deleted",
+			setup: func(m *MockZoneService) {
+				m.On("DeleteZone", mock.Anything, 1, 3).Return(nil).Once()
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "not found",
+			setup: func(m *MockZoneService) {
+				m.On("DeleteZone", mock.Anything, 1, 3).Return(domain.ErrZoneNotExists).Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(MockZoneService)
+			tc.setup(m)
+			h := handler.NewZoneHandler(m, logger, timeout)
+
+			req := httptest.NewRequest(http.MethodDelete, "/zones/3", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "3"})
+			req = injectUserID(req, 1)
+			rr := httptest.NewRecorder()
+
+			h.Delete(rr, req)
+
+			assert.Equal(t, tc.wantStatus, rr.Code)
+			m.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/contextkeys"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"go.uber.org/zap"
+)
+
+// APIKeyAuthMiddleware returns an HTTP middleware that authenticates machine clients by the API key
+// in the X-API-Key header, or in the Authorization header as a bearer token for clients that can't
+// set custom headers, such as Prometheus Alertmanager. If the key is valid, the ID of its owner is
+// stored in the request context for downstream handlers, just like JwtAuthMiddleware does.
+// A missing, unknown or revoked key results in HTTP 401 Unauthorized.
+func APIKeyAuthMiddleware(s domain.APIKeyService, logger *zap.Logger, timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get("X-API-Key")
+			if key == "" {
+				if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+					key = token
+				}
+			}
+			if key == "" {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			userID, err := s.Authenticate(ctx, key)
+			cancel()
+			if err != nil {
+				if errors.Is(err, domain.ErrAPIKeyNotExists) {
+					http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				} else {
+					logger.Error("failed to authenticate api key",
+						zap.String("correlation_id", r.Header.Get("X-Correlation-ID")),
+						zap.String("uri", r.RequestURI),
+						zap.Error(err),
+					)
+					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				}
+				return
+			}
+
+			ctx = context.WithValue(r.Context(), contextkeys.UserID, userID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
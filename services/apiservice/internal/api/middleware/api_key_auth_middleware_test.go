@@ -0,0 +1,109 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/middleware"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/contextkeys"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+// stubAPIKeyService authenticates the keys in the map; its other methods are not used by the middleware.
+type stubAPIKeyService struct {
+	domain.APIKeyService
+	keys map[string]int
+	err  error
+}
+
+func (s *stubAPIKeyService) Authenticate(_ context.Context, key string) (int, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	userID, ok := s.keys[key]
+	if !ok {
+		return 0, domain.ErrAPIKeyNotExists
+	}
+	return userID, nil
+}
+
+func TestAPIKeyAuthMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		key            string
+		authorization  string
+		serviceErr     error
+		expectStatus   int
+		expectNextCall bool
+		expectUserID   any
+	}{
+		{
+			name:         "no header",
+			expectStatus: http.StatusUnauthorized,
+		},
+		{
+			name:         "unknown key",
+			key:          "ens_unknown",
+			expectStatus: http.StatusUnauthorized,
+		},
+		{
+			name:         "service error",
+			key:          "ens_valid",
+			serviceErr:   assert.AnError,
+			expectStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "valid key",
+			key:            "ens_valid",
+			expectStatus:   http.StatusOK,
+			expectNextCall: true,
+			expectUserID:   42,
+		},
+		{
+			name:           "valid bearer token",
+			authorization:  "Bearer ens_valid",
+			expectStatus:   http.StatusOK,
+			expectNextCall: true,
+			expectUserID:   42,
+		},
+		{
+			name:          "basic credentials",
+			authorization: "Basic ZW5zX3ZhbGlk",
+			expectStatus:  http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			nextCalled := false
+			var gotUserID any
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nextCalled = true
+				gotUserID = r.Context().Value(contextkeys.UserID)
+				w.WriteHeader(http.StatusOK)
+			})
+
+			s := &stubAPIKeyService{keys: map[string]int{"ens_valid": 42}, err: tc.serviceErr}
+			h := middleware.APIKeyAuthMiddleware(s, zaptest.NewLogger(t), time.Second)(next)
+			req := httptest.NewRequest(http.MethodPost, "/cap/alerts", nil)
+			if tc.key != "" {
+				req.Header.Set("X-API-Key", tc.key)
+			}
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			rr := httptest.NewRecorder()
+
+			h.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectStatus, rr.Code)
+			assert.Equal(t, tc.expectNextCall, nextCalled)
+			assert.Equal(t, tc.expectUserID, gotUserID)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package route
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/repository"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// NewAPIKeyRoute registers endpoints for listing, creating and revoking the API keys of a user under /api-keys.
+func NewAPIKeyRoute(mux *mux.Router, db domain.DBConn, logger *zap.Logger, timeout time.Duration) {
+	akr := repository.NewAPIKeyRepository(db)
+	aks := service.NewAPIKeyService(akr)
+	akh := handler.NewAPIKeyHandler(aks, logger, timeout)
+
+	mux.HandleFunc("/api-keys", akh.Get).Methods(http.MethodGet, http.MethodOptions)
+	mux.HandleFunc("/api-keys", akh.Post).Methods(http.MethodPost, http.MethodOptions)
+	mux.HandleFunc("/api-keys/{id}", akh.Delete).Methods(http.MethodDelete, http.MethodOptions)
+}
@@ -0,0 +1,54 @@
+package route
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/bootstrap"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/repository"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// NewApprovalPolicyRoute registers GET, PUT and DELETE /approval-policy for managing the user's approval
+// policy, and the endpoints listing the changes relaxing policies and approving or rejecting them
+// under /approval-policy/changes.
+func NewApprovalPolicyRoute(mux *mux.Router, db domain.DBConn, logger *zap.Logger, timeout time.Duration) {
+	apr := repository.NewApprovalPolicyRepository(db)
+	ur := repository.NewUserRepository(db)
+	aps := service.NewApprovalPolicyService(apr, ur)
+	aph := handler.NewApprovalPolicyHandler(aps, logger, timeout)
+
+	mux.HandleFunc("/approval-policy", aph.Get).Methods(http.MethodGet, http.MethodOptions)
+	mux.HandleFunc("/approval-policy", aph.Put).Methods(http.MethodPut, http.MethodOptions)
+	mux.HandleFunc("/approval-policy", aph.Delete).Methods(http.MethodDelete, http.MethodOptions)
+	mux.HandleFunc("/approval-policy/changes", aph.GetChanges).Methods(http.MethodGet, http.MethodOptions)
+	mux.HandleFunc("/approval-policy/changes/{id}/approve", aph.ApproveChange).Methods(http.MethodPost, http.MethodOptions)
+	mux.HandleFunc("/approval-policy/changes/{id}/reject", aph.RejectChange).Methods(http.MethodPost, http.MethodOptions)
+}
+
+// NewCampaignApprovalRoute registers the endpoints listing campaign approvals and approving or
+// rejecting them under /approvals. Approved campaigns are sent to the notification topic.
+func NewCampaignApprovalRoute(mux *mux.Router, db domain.DBConn, logger *zap.Logger, kafkaFactory *bootstrap.KafkaFactory, topic string, timeout time.Duration, contactsPerMessage int, writerBatchTimeout time.Duration, defaultRetryPolicy models.RetryPolicy) {
+	sns := newSendNotificationService(db, kafkaFactory, topic, contactsPerMessage, writerBatchTimeout, defaultRetryPolicy, models.SMSPricing{}, 0)
+	cas := newCampaignApprovalService(db, sns)
+	cah := handler.NewCampaignApprovalHandler(cas, logger, timeout)
+
+	mux.HandleFunc("/approvals", cah.Get).Methods(http.MethodGet, http.MethodOptions)
+	mux.HandleFunc("/approvals/{id}", cah.GetByID).Methods(http.MethodGet, http.MethodOptions)
+	mux.HandleFunc("/approvals/{id}/approve", cah.Approve).Methods(http.MethodPost, http.MethodOptions)
+	mux.HandleFunc("/approvals/{id}/reject", cah.Reject).Methods(http.MethodPost, http.MethodOptions)
+}
+
+// newCampaignApprovalService wires a CampaignApprovalService sending approved campaigns through sns.
+func newCampaignApprovalService(db domain.DBConn, sns domain.SendNotificationService) *service.CampaignApprovalService {
+	car := repository.NewCampaignApprovalRepository(db)
+	apr := repository.NewApprovalPolicyRepository(db)
+	tr := repository.NewTemplateRepository(db)
+
+	return service.NewCampaignApprovalService(car, apr, tr, sns)
+}
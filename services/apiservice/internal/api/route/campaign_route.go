@@ -0,0 +1,22 @@
+package route
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/repository"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// NewCampaignRoute registers the endpoint listing the campaigns sent from templates under /campaigns.
+func NewCampaignRoute(mux *mux.Router, db domain.DBConn, logger *zap.Logger, timeout time.Duration, paginationDefaultLimit, paginationMaxLimit int) {
+	cr := repository.NewCampaignRepository(db)
+	cs := service.NewCampaignService(cr, paginationDefaultLimit, paginationMaxLimit)
+	ch := handler.NewCampaignHandler(cs, logger, timeout)
+
+	mux.HandleFunc("/campaigns", ch.Get).Methods(http.MethodGet, http.MethodOptions)
+}
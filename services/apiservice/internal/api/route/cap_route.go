@@ -0,0 +1,29 @@
+package route
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/bootstrap"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/repository"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// NewCAPRoute registers the endpoint receiving CAP alerts on the router authenticated by API key,
+// so that it can be called by feeds and other systems.
+func NewCAPRoute(mux *mux.Router, db domain.DBConn, logger *zap.Logger, kafkaFactory *bootstrap.KafkaFactory, notificationTopic, cancellationsTopic string, timeout time.Duration, contactsPerMessage int, writerBatchTimeout time.Duration, defaultRetryPolicy models.RetryPolicy) {
+	sns := newSendNotificationService(db, kafkaFactory, notificationTopic, contactsPerMessage, writerBatchTimeout, defaultRetryPolicy, models.SMSPricing{}, 0)
+
+	car := repository.NewCAPAlertRepository(db)
+	ckw := kafkaFactory.NewWriter(cancellationsTopic)
+	cas := newCampaignApprovalService(db, sns)
+	cs := service.NewCAPService(car, sns, cas, ckw)
+	ch := handler.NewCAPHandler(cs, logger, timeout)
+
+	mux.HandleFunc("/cap/alerts", ch.PostAlert).Methods(http.MethodPost, http.MethodOptions)
+}
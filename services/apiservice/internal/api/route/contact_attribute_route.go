@@ -0,0 +1,26 @@
+package route
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/repository"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// NewContactAttributeRoute registers CRUD endpoints for the typed contact attributes of a user
+// under /contacts/attributes. It must be registered before the /contacts/{id} routes.
+func NewContactAttributeRoute(mux *mux.Router, db domain.DBConn, logger *zap.Logger, timeout time.Duration) {
+	ar := repository.NewContactAttributeRepository(db)
+	as := service.NewContactAttributeService(ar)
+	ah := handler.NewContactAttributeHandler(as, logger, timeout)
+
+	mux.HandleFunc("/contacts/attributes", ah.Get).Methods(http.MethodGet, http.MethodOptions)
+	mux.HandleFunc("/contacts/attributes", ah.Post).Methods(http.MethodPost, http.MethodOptions)
+	mux.HandleFunc("/contacts/attributes/{id}", ah.Put).Methods(http.MethodPut, http.MethodOptions)
+	mux.HandleFunc("/contacts/attributes/{id}", ah.Delete).Methods(http.MethodDelete, http.MethodOptions)
+}
@@ -0,0 +1,38 @@
+package route
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/repository"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// NewContactExportRoute registers GET /contacts/export for streaming the user's contacts
+// as CSV or XLSX, and POST /contacts/exports with GET /contacts/exports/{id} for exporting
+// them to S3 in the background. It must be registered before the /contacts/{id} routes.
+// The returned service is waited on at shutdown so that running exports can finish.
+func NewContactExportRoute(
+	mux *mux.Router,
+	db domain.DBConn,
+	logger *zap.Logger,
+	s3Client *s3.S3,
+	bucket string,
+	timeout, exportTimeout, linkExpiry time.Duration,
+) *service.ContactExportService {
+	cer := repository.NewContactExportRepository(db)
+	cr := repository.NewContactsRepository(db)
+	ces := service.NewContactExportService(cer, cr, s3Client, s3Client, bucket, exportTimeout, linkExpiry, logger)
+	ceh := handler.NewContactExportHandler(ces, logger, timeout, exportTimeout)
+
+	mux.HandleFunc("/contacts/export", ceh.Export).Methods(http.MethodGet, http.MethodOptions)
+	mux.HandleFunc("/contacts/exports", ceh.Post).Methods(http.MethodPost, http.MethodOptions)
+	mux.HandleFunc("/contacts/exports/{id}", ceh.GetByID).Methods(http.MethodGet, http.MethodOptions)
+
+	return ces
+}
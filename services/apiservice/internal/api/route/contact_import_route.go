@@ -0,0 +1,27 @@
+package route
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/repository"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// NewContactImportRoute registers GET /imports/{id} for following a contacts upload
+// with GET /imports/{id}/report and GET /imports/{id}/deletions for downloading its
+// rejected rows and the contacts deleted by a sync.
+func NewContactImportRoute(mux *mux.Router, db domain.DBConn, logger *zap.Logger, s3Client *s3.S3, bucket string, timeout time.Duration) {
+	cir := repository.NewContactImportRepository(db)
+	cis := service.NewContactImportService(cir, s3Client, bucket)
+	cih := handler.NewContactImportHandler(cis, logger, timeout)
+
+	mux.HandleFunc("/imports/{id}", cih.GetByID).Methods(http.MethodGet, http.MethodOptions)
+	mux.HandleFunc("/imports/{id}/report", cih.GetReport).Methods(http.MethodGet, http.MethodOptions)
+	mux.HandleFunc("/imports/{id}/deletions", cih.GetDeletions).Methods(http.MethodGet, http.MethodOptions)
+}
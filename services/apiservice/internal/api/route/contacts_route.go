@@ -12,12 +12,19 @@ import (
 	"go.uber.org/zap"
 )
 
-// NewContactsRoute registers CRUD endpoints for managing contacts.
-func NewContactsRoute(mux *mux.Router, db domain.DBConn, logger *zap.Logger, timeout time.Duration, paginationDefaultLimit, paginationMaxLimit int) {
+// NewContactsRoute registers CRUD and bulk endpoints for managing contacts.
+func NewContactsRoute(mux *mux.Router, db domain.DBConn, logger *zap.Logger, timeout time.Duration, paginationDefaultLimit, paginationMaxLimit, bulkLimit int) {
 	cr := repository.NewContactsRepository(db)
-	cs := service.NewContactsService(cr, paginationDefaultLimit, paginationMaxLimit)
+	ur := repository.NewUserRepository(db)
+	ar := repository.NewContactAttributeRepository(db)
+	cs := service.NewContactsService(cr, ur, ar, paginationDefaultLimit, paginationMaxLimit, bulkLimit)
 	ch := handler.NewContactsHandler(cs, logger, timeout)
 
+	mux.HandleFunc("/contacts/bulk", ch.PostBulk).Methods(http.MethodPost, http.MethodOptions)
+	mux.HandleFunc("/contacts/bulk/delete", ch.PostBulkDelete).Methods(http.MethodPost, http.MethodOptions)
+	mux.HandleFunc("/contacts/bulk/group", ch.PostBulkGroup).Methods(http.MethodPost, http.MethodOptions)
+	mux.HandleFunc("/contacts", ch.DeleteAll).Methods(http.MethodDelete, http.MethodOptions)
+
 	mux.HandleFunc("/contacts", ch.Get).Methods(http.MethodGet, http.MethodOptions)
 	mux.HandleFunc("/contacts/{id}", ch.GetByID).Methods(http.MethodGet, http.MethodOptions)
 	mux.HandleFunc("/contacts", ch.Post).Methods(http.MethodPost, http.MethodOptions)
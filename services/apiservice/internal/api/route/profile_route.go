@@ -13,11 +13,12 @@ import (
 )
 
 // NewProfileRoute sets up the profile route on the given mux.Router and
-// registers the GET /profile endpoint with the handler.
+// registers the GET and PUT /profile endpoints with the handler.
 func NewProfileRoute(mux *mux.Router, db domain.DBConn, logger *zap.Logger, timeout time.Duration) {
 	ur := repository.NewUserRepository(db)
 	ps := service.NewProfileService(ur)
 	ph := handler.NewProfileHandler(ps, logger, timeout)
 
 	mux.HandleFunc("/profile", ph.GetProfile).Methods(http.MethodGet, http.MethodOptions)
+	mux.HandleFunc("/profile", ph.PutProfile).Methods(http.MethodPut, http.MethodOptions)
 }
@@ -0,0 +1,24 @@
+package route
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/repository"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// NewQuietHoursRoute registers GET, PUT and DELETE /quiet-hours for managing the user's quiet hours.
+func NewQuietHoursRoute(mux *mux.Router, db domain.DBConn, logger *zap.Logger, timeout time.Duration) {
+	qhr := repository.NewQuietHoursRepository(db)
+	qhs := service.NewQuietHoursService(qhr)
+	qhh := handler.NewQuietHoursHandler(qhs, logger, timeout)
+
+	mux.HandleFunc("/quiet-hours", qhh.Get).Methods(http.MethodGet, http.MethodOptions)
+	mux.HandleFunc("/quiet-hours", qhh.Put).Methods(http.MethodPut, http.MethodOptions)
+	mux.HandleFunc("/quiet-hours", qhh.Delete).Methods(http.MethodDelete, http.MethodOptions)
+}
@@ -0,0 +1,24 @@
+package route
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/repository"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// NewRetryPolicyRoute registers GET and PUT /retry-policy for managing the user's default retry policy.
+func NewRetryPolicyRoute(mux *mux.Router, db domain.DBConn, logger *zap.Logger, timeout time.Duration, defaultPolicy models.RetryPolicy) {
+	rpr := repository.NewRetryPolicyRepository(db)
+	rps := service.NewRetryPolicyService(rpr, defaultPolicy)
+	rph := handler.NewRetryPolicyHandler(rps, logger, timeout)
+
+	mux.HandleFunc("/retry-policy", rph.Get).Methods(http.MethodGet, http.MethodOptions)
+	mux.HandleFunc("/retry-policy", rph.Put).Methods(http.MethodPut, http.MethodOptions)
+}
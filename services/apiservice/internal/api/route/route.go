@@ -1,16 +1,28 @@
 package route
 
 import (
+	"context"
+	"errors"
 	"log"
 	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/middleware"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/bootstrap"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/repository"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
 	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+// shutdownTimeout bounds how long in-flight requests may take once the server is stopping.
+const shutdownTimeout = 10 * time.Second
+
 // Serve configures and starts the HTTP server with routing and middleware.
+// On SIGINT or SIGTERM it stops accepting requests, lets in-flight ones finish and
+// waits for background contact exports before returning.
 func Serve(app *bootstrap.Application) {
 	db := app.DB
 	logger := app.Logger
@@ -34,18 +46,62 @@ func Serve(app *bootstrap.Application) {
 	// private endpoints
 	paginationDefaultLimit := app.Config.App.PaginationDefaultLimit
 	paginationMaxLimit := app.Config.App.PaginationMaxLimit
-	NewContactsRoute(private, db, logger, timeout, paginationDefaultLimit, paginationMaxLimit)
-	NewTemplateRoute(private, db, logger, timeout, paginationDefaultLimit, paginationMaxLimit)
+	contactsBucket := app.Config.S3.Buckets["contacts"]
+	exportTimeout := app.Config.App.ContactsExportTimeout
+	exportLinkExpiry := app.Config.App.ContactsExportLinkExpiry
+	NewContactAttributeRoute(private, db, logger, timeout)
+	exports := NewContactExportRoute(private, db, logger, app.S3Client, contactsBucket, timeout, exportTimeout, exportLinkExpiry)
+	NewContactsRoute(private, db, logger, timeout, paginationDefaultLimit, paginationMaxLimit, app.Config.App.BulkContactsLimit)
+	NewTemplateRoute(private, db, logger, timeout, paginationDefaultLimit, paginationMaxLimit, app.Config.App.TemplateMaxSegments)
+	NewSegmentRoute(private, db, logger, timeout)
+	NewZoneRoute(private, db, logger, timeout)
 	NewProfileRoute(private, db, logger, timeout)
+	NewRetryPolicyRoute(private, db, logger, timeout, app.Config.App.DefaultRetryPolicy)
+	NewQuietHoursRoute(private, db, logger, timeout)
+	NewAPIKeyRoute(private, db, logger, timeout)
+	NewApprovalPolicyRoute(private, db, logger, timeout)
 
-	contactsBucket := app.Config.S3.Buckets["contacts"]
 	contactsTopic := app.Config.Kafka.Topics["contacts.loading.tasks"]
-	NewLoadContactsRoute(private, logger, app.S3Client, contactsBucket, app.KafkaFactory, contactsTopic, timeout)
+	NewLoadContactsRoute(private, db, logger, app.S3Client, contactsBucket, app.KafkaFactory, contactsTopic, timeout)
+	NewContactImportRoute(private, db, logger, app.S3Client, contactsBucket, timeout)
 
 	notificationTopic := app.Config.Kafka.Topics["notification.requests"]
 	contactsPerMessage := app.Config.App.ContactsPerKafkaMessage
 	writerBatchTimeout := app.Config.Kafka.NotificationRequestsBatchTimeout
-	NewSendNotificationRoute(private, db, logger, app.KafkaFactory, notificationTopic, timeout, contactsPerMessage, writerBatchTimeout)
+	NewSendNotificationRoute(private, db, logger, app.KafkaFactory, notificationTopic, timeout, contactsPerMessage, writerBatchTimeout, app.Config.App.DefaultRetryPolicy, app.Config.App.SMSPricing, app.Config.App.TemplateMaxSegments)
+	NewCampaignRoute(private, db, logger, timeout, paginationDefaultLimit, paginationMaxLimit)
+	NewCampaignApprovalRoute(private, db, logger, app.KafkaFactory, notificationTopic, timeout, contactsPerMessage, writerBatchTimeout, app.Config.App.DefaultRetryPolicy)
+
+	NewTriggerRoute(private, db, logger, timeout)
+
+	apiKey := r.NewRoute().Subrouter()
+	apiKey.Use(middleware.APIKeyAuthMiddleware(service.NewAPIKeyService(repository.NewAPIKeyRepository(db)), logger, timeout))
+
+	// endpoints authenticated by API key
+	cancellationsTopic := app.Config.Kafka.Topics["notification.cancellations"]
+	NewCAPRoute(apiKey, db, logger, app.KafkaFactory, notificationTopic, cancellationsTopic, timeout, contactsPerMessage, writerBatchTimeout, app.Config.App.DefaultRetryPolicy)
+	NewWebhookRoute(apiKey, db, logger, app.KafkaFactory, notificationTopic, timeout, contactsPerMessage, writerBatchTimeout, app.Config.App.DefaultRetryPolicy, app.Config.App.TriggerRepeatInterval)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	srv := &http.Server{Addr: ":" + app.Config.App.Port, Handler: r}
+	go func() {
+		err := srv.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Printf("shutting down")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	err := srv.Shutdown(shutdownCtx)
+	if err != nil {
+		log.Printf("failed to shut down the server gracefully: %v", err)
+	}
 
-	log.Fatal(http.ListenAndServe(":"+app.Config.App.Port, r))
+	exports.Wait()
 }
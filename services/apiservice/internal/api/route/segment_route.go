@@ -0,0 +1,30 @@
+package route
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/repository"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// NewSegmentRoute registers CRUD and preview endpoints for the segments of a user under /segments.
+func NewSegmentRoute(mux *mux.Router, db domain.DBConn, logger *zap.Logger, timeout time.Duration) {
+	sr := repository.NewSegmentRepository(db)
+	ar := repository.NewContactAttributeRepository(db)
+	cr := repository.NewContactsRepository(db)
+	ss := service.NewSegmentService(sr, ar, cr)
+	sh := handler.NewSegmentHandler(ss, logger, timeout)
+
+	mux.HandleFunc("/segments", sh.Get).Methods(http.MethodGet, http.MethodOptions)
+	mux.HandleFunc("/segments", sh.Post).Methods(http.MethodPost, http.MethodOptions)
+	mux.HandleFunc("/segments/preview", sh.PostPreview).Methods(http.MethodPost, http.MethodOptions)
+	mux.HandleFunc("/segments/{id}", sh.GetByID).Methods(http.MethodGet, http.MethodOptions)
+	mux.HandleFunc("/segments/{id}", sh.Put).Methods(http.MethodPut, http.MethodOptions)
+	mux.HandleFunc("/segments/{id}", sh.Delete).Methods(http.MethodDelete, http.MethodOptions)
+	mux.HandleFunc("/segments/{id}/preview", sh.GetPreview).Methods(http.MethodGet, http.MethodOptions)
+}
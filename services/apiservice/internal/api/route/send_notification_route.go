@@ -7,6 +7,7 @@ import (
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/bootstrap"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/repository"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
 	"github.com/gorilla/mux"
@@ -15,13 +16,29 @@ import (
 
 // NewSendNotificationRoute registers the HTTP route for sending notifications.
 // It sets up the necessary repository, service, and handler layers, wiring them together.
-func NewSendNotificationRoute(mux *mux.Router, db domain.DBConn, logger *zap.Logger, kafkaFactory *bootstrap.KafkaFactory, topic string, timeout time.Duration, contactsPerMessage int, writerBatchTimeout time.Duration) {
+// The pricing is used to estimate the cost of campaigns in dry runs, which also check the rendered
+// messages against the template limit of maxSegments SMS segments.
+func NewSendNotificationRoute(mux *mux.Router, db domain.DBConn, logger *zap.Logger, kafkaFactory *bootstrap.KafkaFactory, topic string, timeout time.Duration, contactsPerMessage int, writerBatchTimeout time.Duration, defaultRetryPolicy models.RetryPolicy, pricing models.SMSPricing, maxSegments int) {
+	sns := newSendNotificationService(db, kafkaFactory, topic, contactsPerMessage, writerBatchTimeout, defaultRetryPolicy, pricing, maxSegments)
+	cas := newCampaignApprovalService(db, sns)
+	snh := handler.NewSendNotificationHandler(sns, cas, logger, timeout)
+
+	mux.HandleFunc("/send-notification/{id}", snh.SendNotification).Methods(http.MethodPost, http.MethodOptions)
+}
+
+// newSendNotificationService wires a SendNotificationService writing to the notification topic,
+// shared by the routes that send campaigns. Routes that don't preview campaigns need no pricing
+// nor segment limit.
+func newSendNotificationService(db domain.DBConn, kafkaFactory *bootstrap.KafkaFactory, topic string, contactsPerMessage int, writerBatchTimeout time.Duration, defaultRetryPolicy models.RetryPolicy, pricing models.SMSPricing, maxSegments int) *service.SendNotificationService {
 	cr := repository.NewContactsRepository(db)
 	tr := repository.NewTemplateRepository(db)
+	rpr := repository.NewRetryPolicyRepository(db)
+	qhr := repository.NewQuietHoursRepository(db)
+	sr := repository.NewSegmentRepository(db)
+	ar := repository.NewContactAttributeRepository(db)
+	zr := repository.NewZoneRepository(db)
+	cmr := repository.NewCampaignRepository(db)
 	kw := kafkaFactory.NewWriter(topic, bootstrap.WithBatchTimeout(writerBatchTimeout))
 
-	sns := service.NewSendNotificationService(cr, tr, kw, contactsPerMessage)
-	snh := handler.NewSendNotificationHandler(sns, logger, timeout)
-
-	mux.HandleFunc("/send-notification/{id}", snh.SendNotification).Methods(http.MethodPost, http.MethodOptions)
+	return service.NewSendNotificationService(cr, tr, rpr, qhr, sr, ar, zr, cmr, kw, contactsPerMessage, defaultRetryPolicy, pricing, maxSegments)
 }
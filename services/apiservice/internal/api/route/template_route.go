@@ -13,10 +13,12 @@ import (
 )
 
 // NewTemplateRoute registers HTTP routes for managing message templates on the given mux.Router.
-// Routes include GET, POST, PUT, and DELETE operations for /templates and /templates/{id}.
-func NewTemplateRoute(mux *mux.Router, db domain.DBConn, logger *zap.Logger, timeout time.Duration, paginationDefaultLimit, paginationMaxLimit int) {
+// Routes include GET, POST, PUT, and DELETE operations for /templates and /templates/{id}, and
+// the version history of a template under /templates/{id}/versions.
+// Template bodies may take at most maxSegments SMS segments.
+func NewTemplateRoute(mux *mux.Router, db domain.DBConn, logger *zap.Logger, timeout time.Duration, paginationDefaultLimit, paginationMaxLimit, maxSegments int) {
 	tr := repository.NewTemplateRepository(db)
-	ts := service.NewTemplateService(tr, paginationDefaultLimit, paginationMaxLimit)
+	ts := service.NewTemplateService(tr, paginationDefaultLimit, paginationMaxLimit, maxSegments)
 	th := handler.NewTemplateHandler(ts, logger, timeout)
 
 	mux.HandleFunc("/templates", th.Get).Methods(http.MethodGet, http.MethodOptions)
@@ -24,4 +26,8 @@ func NewTemplateRoute(mux *mux.Router, db domain.DBConn, logger *zap.Logger, tim
 	mux.HandleFunc("/templates", th.Post).Methods(http.MethodPost, http.MethodOptions)
 	mux.HandleFunc("/templates/{id}", th.Put).Methods(http.MethodPut, http.MethodOptions)
 	mux.HandleFunc("/templates/{id}", th.Delete).Methods(http.MethodDelete, http.MethodOptions)
+	mux.HandleFunc("/templates/{id}/versions", th.GetVersions).Methods(http.MethodGet, http.MethodOptions)
+	mux.HandleFunc("/templates/{id}/versions/{version}", th.GetVersion).Methods(http.MethodGet, http.MethodOptions)
+	mux.HandleFunc("/templates/{id}/versions/{version}/restore", th.Restore).Methods(http.MethodPost, http.MethodOptions)
+	mux.HandleFunc("/templates/{id}/diff", th.GetDiff).Methods(http.MethodGet, http.MethodOptions)
 }
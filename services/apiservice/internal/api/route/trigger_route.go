@@ -0,0 +1,45 @@
+package route
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/bootstrap"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/repository"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// NewTriggerRoute registers CRUD endpoints for the triggers of a user under /triggers.
+// Triggers only manage rules, so they don't need a notification sender.
+func NewTriggerRoute(mux *mux.Router, db domain.DBConn, logger *zap.Logger, timeout time.Duration) {
+	tr := repository.NewTriggerRepository(db)
+	ts := service.NewTriggerService(tr, repository.NewTemplateRepository(db), nil, nil, 0)
+	th := handler.NewTriggerHandler(ts, logger, timeout)
+
+	mux.HandleFunc("/triggers", th.Get).Methods(http.MethodGet, http.MethodOptions)
+	mux.HandleFunc("/triggers", th.Post).Methods(http.MethodPost, http.MethodOptions)
+	mux.HandleFunc("/triggers/{id}", th.GetByID).Methods(http.MethodGet, http.MethodOptions)
+	mux.HandleFunc("/triggers/{id}", th.Put).Methods(http.MethodPut, http.MethodOptions)
+	mux.HandleFunc("/triggers/{id}", th.Delete).Methods(http.MethodDelete, http.MethodOptions)
+}
+
+// NewWebhookRoute registers the endpoints receiving alerts from monitoring systems on the router
+// authenticated by API key. The alerts send notifications through the triggers of the key's owner;
+// alerts that keep firing notify again after repeatInterval.
+func NewWebhookRoute(mux *mux.Router, db domain.DBConn, logger *zap.Logger, kafkaFactory *bootstrap.KafkaFactory, notificationTopic string, timeout time.Duration, contactsPerMessage int, writerBatchTimeout time.Duration, defaultRetryPolicy models.RetryPolicy, repeatInterval time.Duration) {
+	sns := newSendNotificationService(db, kafkaFactory, notificationTopic, contactsPerMessage, writerBatchTimeout, defaultRetryPolicy, models.SMSPricing{}, 0)
+
+	cas := newCampaignApprovalService(db, sns)
+
+	tr := repository.NewTriggerRepository(db)
+	ts := service.NewTriggerService(tr, repository.NewTemplateRepository(db), sns, cas, repeatInterval)
+	wh := handler.NewWebhookHandler(ts, logger, timeout)
+
+	mux.HandleFunc("/webhooks/alertmanager", wh.PostAlertmanager).Methods(http.MethodPost, http.MethodOptions)
+	mux.HandleFunc("/webhooks/generic", wh.PostGeneric).Methods(http.MethodPost, http.MethodOptions)
+}
@@ -0,0 +1,26 @@
+package route
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/api/handler"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/repository"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// NewZoneRoute registers CRUD endpoints for the saved target zones of a user under /zones.
+func NewZoneRoute(mux *mux.Router, db domain.DBConn, logger *zap.Logger, timeout time.Duration) {
+	zr := repository.NewZoneRepository(db)
+	zs := service.NewZoneService(zr)
+	zh := handler.NewZoneHandler(zs, logger, timeout)
+
+	mux.HandleFunc("/zones", zh.Get).Methods(http.MethodGet, http.MethodOptions)
+	mux.HandleFunc("/zones", zh.Post).Methods(http.MethodPost, http.MethodOptions)
+	mux.HandleFunc("/zones/{id}", zh.GetByID).Methods(http.MethodGet, http.MethodOptions)
+	mux.HandleFunc("/zones/{id}", zh.Put).Methods(http.MethodPut, http.MethodOptions)
+	mux.HandleFunc("/zones/{id}", zh.Delete).Methods(http.MethodDelete, http.MethodOptions)
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
 	"github.com/joho/godotenv"
 )
 
@@ -20,14 +21,21 @@ type Config struct {
 
 // AppConfig holds general application settings.
 type AppConfig struct {
-	AppEnv                  string
-	Port                    string
-	ContextTimeout          time.Duration
-	FrontendOrigin          string
-	Jwt                     *JWTConfig
-	ContactsPerKafkaMessage int
-	PaginationDefaultLimit  int
-	PaginationMaxLimit      int
+	AppEnv                   string
+	Port                     string
+	ContextTimeout           time.Duration
+	FrontendOrigin           string
+	Jwt                      *JWTConfig
+	ContactsPerKafkaMessage  int
+	PaginationDefaultLimit   int
+	PaginationMaxLimit       int
+	DefaultRetryPolicy       models.RetryPolicy
+	SMSPricing               models.SMSPricing
+	TemplateMaxSegments      int
+	ContactsExportTimeout    time.Duration
+	ContactsExportLinkExpiry time.Duration
+	BulkContactsLimit        int
+	TriggerRepeatInterval    time.Duration
 }
 
 // JWTConfig holds JWT secret keys and expiry durations for access and refresh tokens.
@@ -86,6 +94,16 @@ func NewConfig() *Config {
 			ContactsPerKafkaMessage: getEnvAsInt("CONTACTS_PER_KAFKA_MESSAGE", 10_000),
 			PaginationDefaultLimit:  getEnvAsInt("PAGINATION_DEFAULT_LIMIT", 50),
 			PaginationMaxLimit:      getEnvAsInt("PAGINATION_MAX_LIMIT", 100),
+			DefaultRetryPolicy:      getEnvAsRetryPolicy(),
+			SMSPricing: models.SMSPricing{
+				SegmentPrice: getEnvAsFloat("SMS_SEGMENT_PRICE", 0),
+				Currency:     getEnv("SMS_PRICE_CURRENCY", "RUB"),
+			},
+			TemplateMaxSegments:      getEnvAsInt("TEMPLATE_MAX_SEGMENTS", 3),
+			ContactsExportTimeout:    getEnvAsDuration("CONTACTS_EXPORT_TIMEOUT_MS", 600_000) * time.Millisecond,
+			ContactsExportLinkExpiry: getEnvAsDuration("CONTACTS_EXPORT_LINK_EXPIRY_MIN", 60) * time.Minute,
+			BulkContactsLimit:        getEnvAsInt("BULK_CONTACTS_LIMIT", 1000),
+			TriggerRepeatInterval:    getEnvAsDuration("TRIGGER_REPEAT_INTERVAL_MIN", 240) * time.Minute,
 		},
 		DB: &DBConfig{
 			Host:              getEnv("DB_HOST", "apiservice"),
@@ -107,8 +125,9 @@ func NewConfig() *Config {
 		Kafka: &KafkaConfig{
 			KafkaAddrs: getEnvAsSlice("KAFKA_ADDRS", []string{"kafka:9092"}, ","),
 			Topics: map[string]string{
-				"contacts.loading.tasks": getEnv("KAFKA_TOPIC_CONTACTS_LOADING_TASKS", "contacts.loading.tasks"),
-				"notification.requests":  getEnv("KAFKA_TOPIC_NOTIFICATION_REQUESTS", "notification.requests"),
+				"contacts.loading.tasks":     getEnv("KAFKA_TOPIC_CONTACTS_LOADING_TASKS", "contacts.loading.tasks"),
+				"notification.requests":      getEnv("KAFKA_TOPIC_NOTIFICATION_REQUESTS", "notification.requests"),
+				"notification.cancellations": getEnv("KAFKA_TOPIC_NOTIFICATION_CANCELLATIONS", "notification.cancellations"),
 			},
 			NotificationRequestsBatchTimeout: getEnvAsDuration("KAFKA_NOTIFICATION_REQUESTS_BATCH_TIMEOUT_MS", 1) * time.Millisecond,
 		},
@@ -135,6 +154,17 @@ func getEnvAsInt(name string, defaultVal int) int {
 	return value
 }
 
+func getEnvAsFloat(name string, defaultVal float64) float64 {
+	valueStr := getEnv(name, "")
+
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return defaultVal
+	}
+
+	return value
+}
+
 func getEnvAsDuration(name string, defaultVal time.Duration) time.Duration {
 	value := getEnvAsInt(name, int(defaultVal))
 	return time.Duration(value)
@@ -161,3 +191,23 @@ func getEnvAsSlice(name string, defaultVal []string, sep string) []string {
 
 	return result
 }
+
+// getEnvAsRetryPolicy reads the default retry policy from MAX_NOTIFICATION_ATTEMPTS and the RETRY_*
+// variables. An invalid policy is fatal.
+func getEnvAsRetryPolicy() models.RetryPolicy {
+	policy := models.RetryPolicy{
+		MaxAttempts:  getEnvAsInt("MAX_NOTIFICATION_ATTEMPTS", 5),
+		Backoff:      models.BackoffKind(getEnv("RETRY_BACKOFF", string(models.BackoffExponential))),
+		BaseDelayMs:  getEnvAsInt("RETRY_BASE_DELAY_MS", 1000),
+		MaxDelayMs:   getEnvAsInt("RETRY_MAX_DELAY_MS", 300_000),
+		Jitter:       getEnvAsFloat("RETRY_JITTER", 0.2),
+		StaleAfterMs: getEnvAsInt("RETRY_STALE_AFTER_MS", 300_000),
+	}
+
+	err := policy.Validate()
+	if err != nil {
+		log.Fatalf("invalid default retry policy: %v", err)
+	}
+
+	return policy
+}
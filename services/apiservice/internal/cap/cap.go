@@ -0,0 +1,358 @@
+// Package cap reads alerts in the OASIS Common Alerting Protocol, version 1.2, the XML format
+// official and corporate emergency feeds publish warnings in. Only the parts of the format the
+// notification system acts on are decoded: the identity and type of the message, the
+// references of updates and cancellations, and the text, severity and areas of its infos.
+package cap
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/geo"
+)
+
+// Namespace is the XML namespace of CAP 1.2 alerts.
+const Namespace = "urn:oasis:names:tc:emergency:cap:1.2"
+
+var (
+	// ErrInvalidAlert indicates a document that is not a valid CAP 1.2 alert; the error describes the problem.
+	ErrInvalidAlert = errors.New("invalid CAP alert")
+	// ErrUnsupportedArea indicates an area described only by geocodes, which can't be resolved offline,
+	// or a circle crossing the antimeridian or a pole.
+	ErrUnsupportedArea = errors.New("unsupported CAP area")
+)
+
+// Values of the status element.
+const (
+	StatusActual   = "Actual"
+	StatusExercise = "Exercise"
+	StatusSystem   = "System"
+	StatusTest     = "Test"
+	StatusDraft    = "Draft"
+)
+
+// Values of the msgType element.
+const (
+	MsgTypeAlert  = "Alert"
+	MsgTypeUpdate = "Update"
+	MsgTypeCancel = "Cancel"
+	MsgTypeAck    = "Ack"
+	MsgTypeError  = "Error"
+)
+
+// Values of the urgency element.
+const (
+	UrgencyImmediate = "Immediate"
+	UrgencyExpected  = "Expected"
+	UrgencyFuture    = "Future"
+	UrgencyPast      = "Past"
+	UrgencyUnknown   = "Unknown"
+)
+
+// Values of the severity element.
+const (
+	SeverityExtreme  = "Extreme"
+	SeveritySevere   = "Severe"
+	SeverityModerate = "Moderate"
+	SeverityMinor    = "Minor"
+	SeverityUnknown  = "Unknown"
+)
+
+// Values of the certainty element.
+const (
+	CertaintyObserved = "Observed"
+	CertaintyLikely   = "Likely"
+	CertaintyPossible = "Possible"
+	CertaintyUnlikely = "Unlikely"
+	CertaintyUnknown  = "Unknown"
+)
+
+// circleVertices is the number of vertices of the polygons circles are approximated with when
+// an alert mixes them with polygons.
+const circleVertices = 64
+
+// Alert is a CAP alert message.
+type Alert struct {
+	XMLName    xml.Name `xml:"urn:oasis:names:tc:emergency:cap:1.2 alert"`
+	Identifier string   `xml:"identifier"`
+	Sender     string   `xml:"sender"`
+	Sent       string   `xml:"sent"`
+	Status     string   `xml:"status"`
+	MsgType    string   `xml:"msgType"`
+	Scope      string   `xml:"scope"`
+	References string   `xml:"references"`
+	Infos      []Info   `xml:"info"`
+}
+
+// Info is a block of an alert describing the event in one language.
+type Info struct {
+	Language    string `xml:"language"`
+	Event       string `xml:"event"`
+	Urgency     string `xml:"urgency"`
+	Severity    string `xml:"severity"`
+	Certainty   string `xml:"certainty"`
+	Expires     string `xml:"expires"`
+	Headline    string `xml:"headline"`
+	Description string `xml:"description"`
+	Instruction string `xml:"instruction"`
+	Areas       []Area `xml:"area"`
+}
+
+// Area is an affected area of an info. Polygons are lists of "lat,lon" pairs separated by
+// spaces, with the first and last pairs equal; circles are a "lat,lon" pair followed by a
+// radius in kilometres.
+type Area struct {
+	Desc     string    `xml:"areaDesc"`
+	Polygons []string  `xml:"polygon"`
+	Circles  []string  `xml:"circle"`
+	Geocodes []Geocode `xml:"geocode"`
+}
+
+// Geocode is a coded area, such as a postal or administrative code.
+type Geocode struct {
+	ValueName string `xml:"valueName"`
+	Value     string `xml:"value"`
+}
+
+// Reference identifies an earlier alert that an update or cancellation refers to.
+type Reference struct {
+	Sender     string
+	Identifier string
+	Sent       string
+}
+
+// Parse decodes a CAP 1.2 alert and checks the elements the format requires.
+// Returns an error wrapping ErrInvalidAlert for malformed or incomplete documents.
+func Parse(r io.Reader) (*Alert, error) {
+	var a Alert
+	err := xml.NewDecoder(r).Decode(&a)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAlert, err)
+	}
+
+	err = a.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+func (a *Alert) validate() error {
+	a.Identifier = strings.TrimSpace(a.Identifier)
+	a.Sender = strings.TrimSpace(a.Sender)
+	a.Sent = strings.TrimSpace(a.Sent)
+
+	if a.Identifier == "" || strings.ContainsAny(a.Identifier, " ,") {
+		return fmt.Errorf("%w: identifier must be non-empty without spaces or commas", ErrInvalidAlert)
+	}
+	if a.Sender == "" || strings.ContainsAny(a.Sender, " ,") {
+		return fmt.Errorf("%w: sender must be non-empty without spaces or commas", ErrInvalidAlert)
+	}
+	if _, err := time.Parse(time.RFC3339, a.Sent); err != nil {
+		return fmt.Errorf("%w: sent must be a date and time with a time zone", ErrInvalidAlert)
+	}
+
+	switch a.Status {
+	case StatusActual, StatusExercise, StatusSystem, StatusTest, StatusDraft:
+	default:
+		return fmt.Errorf("%w: unknown status %q", ErrInvalidAlert, a.Status)
+	}
+	switch a.MsgType {
+	case MsgTypeAlert, MsgTypeUpdate, MsgTypeCancel, MsgTypeAck, MsgTypeError:
+	default:
+		return fmt.Errorf("%w: unknown msgType %q", ErrInvalidAlert, a.MsgType)
+	}
+
+	for i := range a.Infos {
+		info := &a.Infos[i]
+		if !oneOf(info.Urgency, UrgencyImmediate, UrgencyExpected, UrgencyFuture, UrgencyPast, UrgencyUnknown) {
+			return fmt.Errorf("%w: unknown urgency %q", ErrInvalidAlert, info.Urgency)
+		}
+		if !oneOf(info.Severity, SeverityExtreme, SeveritySevere, SeverityModerate, SeverityMinor, SeverityUnknown) {
+			return fmt.Errorf("%w: unknown severity %q", ErrInvalidAlert, info.Severity)
+		}
+		if !oneOf(info.Certainty, CertaintyObserved, CertaintyLikely, CertaintyPossible, CertaintyUnlikely, CertaintyUnknown) {
+			return fmt.Errorf("%w: unknown certainty %q", ErrInvalidAlert, info.Certainty)
+		}
+		if info.Expires != "" {
+			if _, err := time.Parse(time.RFC3339, info.Expires); err != nil {
+				return fmt.Errorf("%w: expires must be a date and time with a time zone", ErrInvalidAlert)
+			}
+		}
+	}
+
+	_, err := a.ParseReferences()
+	return err
+}
+
+func oneOf(v string, values ...string) bool {
+	for _, value := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseReferences returns the earlier alerts the alert refers to.
+// Returns an error wrapping ErrInvalidAlert for a malformed references element.
+func (a *Alert) ParseReferences() ([]Reference, error) {
+	fields := strings.Fields(a.References)
+	refs := make([]Reference, 0, len(fields))
+	for _, f := range fields {
+		parts := strings.Split(f, ",")
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("%w: malformed reference %q", ErrInvalidAlert, f)
+		}
+		refs = append(refs, Reference{Sender: parts[0], Identifier: parts[1], Sent: parts[2]})
+	}
+	return refs, nil
+}
+
+// Expired reports whether every info of the alert that has an expiry time has expired by now.
+// Alerts without expiry times never expire.
+func (a *Alert) Expired(now time.Time) bool {
+	expired := false
+	for _, info := range a.Infos {
+		if info.Expires == "" {
+			return false
+		}
+		expires, err := time.Parse(time.RFC3339, info.Expires)
+		if err != nil || !expires.Before(now) {
+			return false
+		}
+		expired = true
+	}
+	return expired
+}
+
+// Text returns the message text of the info: the description followed by the instruction,
+// or the headline or the event when both are empty.
+func (i *Info) Text() string {
+	parts := make([]string, 0, 2)
+	for _, p := range []string{i.Description, i.Instruction} {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	if len(parts) > 0 {
+		return strings.Join(parts, "\n")
+	}
+	if h := strings.TrimSpace(i.Headline); h != "" {
+		return h
+	}
+	return strings.TrimSpace(i.Event)
+}
+
+// Area returns the union of the areas of all infos of the alert as a single target area, or
+// nil if the alert has no areas, meaning it concerns everyone. A lone circle is kept as is;
+// otherwise polygons and circles, approximated by polygons, are joined into a MultiPolygon.
+// Returns an error wrapping ErrUnsupportedArea for areas without polygons or circles and an
+// error wrapping ErrInvalidAlert for malformed ones.
+func (a *Alert) Area() (*geo.Area, error) {
+	var circles []geo.Circle
+	var rings []geo.Ring
+	for _, info := range a.Infos {
+		for _, area := range info.Areas {
+			if len(area.Polygons) == 0 && len(area.Circles) == 0 {
+				return nil, fmt.Errorf("%w: area %q has neither a polygon nor a circle", ErrUnsupportedArea, strings.TrimSpace(area.Desc))
+			}
+			for _, p := range area.Polygons {
+				ring, err := parsePolygon(p)
+				if err != nil {
+					return nil, err
+				}
+				rings = append(rings, ring)
+			}
+			for _, c := range area.Circles {
+				circle, err := parseCircle(c)
+				if err != nil {
+					return nil, err
+				}
+				circles = append(circles, circle)
+			}
+		}
+	}
+
+	if len(circles) == 0 && len(rings) == 0 {
+		return nil, nil
+	}
+	if len(circles) == 1 && len(rings) == 0 {
+		return &geo.Area{Circle: &circles[0]}, nil
+	}
+
+	for _, c := range circles {
+		ring, ok := c.Ring(circleVertices)
+		if !ok {
+			return nil, fmt.Errorf("%w: circle around %v,%v crosses the antimeridian or a pole", ErrUnsupportedArea, c.Lat, c.Lon)
+		}
+		rings = append(rings, ring)
+	}
+
+	coordinates := make([][]geo.Ring, len(rings))
+	for i, r := range rings {
+		coordinates[i] = []geo.Ring{r}
+	}
+	raw, err := json.Marshal(coordinates)
+	if err != nil {
+		return nil, err
+	}
+
+	return &geo.Area{Polygon: &geo.Geometry{Type: geo.GeometryMultiPolygon, Coordinates: raw}}, nil
+}
+
+// parsePolygon converts a CAP polygon of "lat,lon" pairs into a ring of [lon, lat] positions.
+func parsePolygon(s string) (geo.Ring, error) {
+	pairs := strings.Fields(s)
+	ring := make(geo.Ring, len(pairs))
+	for i, pair := range pairs {
+		lat, lon, err := parsePoint(pair)
+		if err != nil {
+			return nil, err
+		}
+		ring[i] = [2]float64{lon, lat}
+	}
+	if len(ring) < 4 || ring[0] != ring[len(ring)-1] {
+		return nil, fmt.Errorf("%w: polygon must have at least 4 points with the first and last equal", ErrInvalidAlert)
+	}
+	return ring, nil
+}
+
+// parseCircle converts a CAP circle, "lat,lon radius" in kilometres, into a geo.Circle in metres.
+func parseCircle(s string) (geo.Circle, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return geo.Circle{}, fmt.Errorf("%w: malformed circle %q", ErrInvalidAlert, s)
+	}
+	lat, lon, err := parsePoint(fields[0])
+	if err != nil {
+		return geo.Circle{}, err
+	}
+	radius, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil || radius < 0 {
+		return geo.Circle{}, fmt.Errorf("%w: malformed circle radius %q", ErrInvalidAlert, fields[1])
+	}
+	return geo.Circle{Lat: lat, Lon: lon, Radius: radius * 1000}, nil
+}
+
+func parsePoint(s string) (lat, lon float64, err error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("%w: malformed point %q", ErrInvalidAlert, s)
+	}
+	lat, err = strconv.ParseFloat(parts[0], 64)
+	if err == nil {
+		lon, err = strconv.ParseFloat(parts[1], 64)
+	}
+	if err != nil || !geo.ValidLatLon(lat, lon) {
+		return 0, 0, fmt.Errorf("%w: malformed point %q", ErrInvalidAlert, s)
+	}
+	return lat, lon, nil
+}
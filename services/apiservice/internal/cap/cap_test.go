@@ -0,0 +1,142 @@
+package cap_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/cap"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/geo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const flood = `<?xml version="1.0" encoding="UTF-8"?>
+<alert xmlns="urn:oasis:names:tc:emergency:cap:1.2">
+  <identifier>KSTO1055887203</identifier>
+  <sender>KSTO@NWS.NOAA.GOV</sender>
+  <sent>2003-06-17T14:57:00-07:00</sent>
+  <status>Actual</status>
+  <msgType>Update</msgType>
+  <scope>Public</scope>
+  <references>KSTO@NWS.NOAA.GOV,KSTO1055887200,2003-06-17T14:50:00-07:00</references>
+  <info>
+    <category>Met</category>
+    <event>FLOOD WARNING</event>
+    <urgency>Immediate</urgency>
+    <severity>Severe</severity>
+    <certainty>Observed</certainty>
+    <expires>2003-06-17T16:00:00-07:00</expires>
+    <headline>FLOOD WARNING</headline>
+    <description> The river is expected to overflow. </description>
+    <instruction>Move to higher ground.</instruction>
+    <area>
+      <areaDesc>Lower river valley</areaDesc>
+      <polygon>38.47,-120.14 38.34,-119.95 38.52,-119.74 38.62,-119.89 38.47,-120.14</polygon>
+    </area>
+  </info>
+</alert>`
+
+func TestParse(t *testing.T) {
+	alert, err := cap.Parse(strings.NewReader(flood))
+	require.NoError(t, err)
+
+	assert.Equal(t, "KSTO1055887203", alert.Identifier)
+	assert.Equal(t, "KSTO@NWS.NOAA.GOV", alert.Sender)
+	assert.Equal(t, cap.StatusActual, alert.Status)
+	assert.Equal(t, cap.MsgTypeUpdate, alert.MsgType)
+	require.Len(t, alert.Infos, 1)
+	assert.Equal(t, cap.SeveritySevere, alert.Infos[0].Severity)
+	assert.Equal(t, "The river is expected to overflow.\nMove to higher ground.", alert.Infos[0].Text())
+
+	refs, err := alert.ParseReferences()
+	require.NoError(t, err)
+	assert.Equal(t, []cap.Reference{{Sender: "KSTO@NWS.NOAA.GOV", Identifier: "KSTO1055887200", Sent: "2003-06-17T14:50:00-07:00"}}, refs)
+
+	assert.True(t, alert.Expired(time.Date(2003, 6, 18, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, alert.Expired(time.Date(2003, 6, 17, 20, 0, 0, 0, time.UTC)))
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{name: "not xml", doc: `{"identifier":"1"}`},
+		{name: "wrong namespace", doc: strings.Replace(flood, "cap:1.2", "cap:1.1", 1)},
+		{name: "identifier with space", doc: strings.Replace(flood, "KSTO1055887203", "KSTO 1055887203", 1)},
+		{name: "sent without zone", doc: strings.Replace(flood, "2003-06-17T14:57:00-07:00", "2003-06-17T14:57:00", 1)},
+		{name: "unknown msgType", doc: strings.Replace(flood, "<msgType>Update", "<msgType>Replace", 1)},
+		{name: "unknown severity", doc: strings.Replace(flood, "<severity>Severe", "<severity>Bad", 1)},
+		{name: "malformed reference", doc: strings.Replace(flood, "KSTO@NWS.NOAA.GOV,KSTO1055887200,", "KSTO1055887200", 1)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := cap.Parse(strings.NewReader(tc.doc))
+			assert.ErrorIs(t, err, cap.ErrInvalidAlert)
+		})
+	}
+}
+
+func TestInfo_Text(t *testing.T) {
+	assert.Equal(t, "Tornado", (&cap.Info{Event: "Tornado"}).Text())
+	assert.Equal(t, "Take cover", (&cap.Info{Event: "Tornado", Headline: " Take cover "}).Text())
+	assert.Equal(t, "Go to the basement", (&cap.Info{Headline: "Take cover", Instruction: "Go to the basement"}).Text())
+}
+
+func TestAlert_Area(t *testing.T) {
+	polygon := "38.47,-120.14 38.34,-119.95 38.52,-119.74 38.47,-120.14"
+
+	t.Run("no areas", func(t *testing.T) {
+		area, err := (&cap.Alert{Infos: []cap.Info{{}}}).Area()
+		require.NoError(t, err)
+		assert.Nil(t, area)
+	})
+
+	t.Run("single circle", func(t *testing.T) {
+		alert := &cap.Alert{Infos: []cap.Info{{Areas: []cap.Area{{Circles: []string{"32.9525,-115.5527 2.5"}}}}}}
+
+		area, err := alert.Area()
+		require.NoError(t, err)
+		assert.Equal(t, &geo.Area{Circle: &geo.Circle{Lat: 32.9525, Lon: -115.5527, Radius: 2500}}, area)
+	})
+
+	t.Run("polygons and circles of several infos", func(t *testing.T) {
+		alert := &cap.Alert{Infos: []cap.Info{
+			{Areas: []cap.Area{{Polygons: []string{polygon}}}},
+			{Areas: []cap.Area{{Circles: []string{"32.9525,-115.5527 2.5"}}}},
+		}}
+
+		area, err := alert.Area()
+		require.NoError(t, err)
+		require.NotNil(t, area.Polygon)
+		require.NoError(t, area.Validate())
+		polygons, err := area.Polygon.Polygons()
+		require.NoError(t, err)
+		require.Len(t, polygons, 2)
+		assert.Equal(t, geo.Ring{{-120.14, 38.47}, {-119.95, 38.34}, {-119.74, 38.52}, {-120.14, 38.47}}, polygons[0][0])
+		assert.Len(t, polygons[1][0], 65)
+	})
+
+	t.Run("geocode only", func(t *testing.T) {
+		alert := &cap.Alert{Infos: []cap.Info{{Areas: []cap.Area{{Desc: "County", Geocodes: []cap.Geocode{{ValueName: "FIPS6", Value: "006109"}}}}}}}
+
+		_, err := alert.Area()
+		assert.ErrorIs(t, err, cap.ErrUnsupportedArea)
+	})
+
+	t.Run("open polygon", func(t *testing.T) {
+		alert := &cap.Alert{Infos: []cap.Info{{Areas: []cap.Area{{Polygons: []string{"38.47,-120.14 38.34,-119.95 38.52,-119.74 38.62,-119.89"}}}}}}
+
+		_, err := alert.Area()
+		assert.ErrorIs(t, err, cap.ErrInvalidAlert)
+	})
+
+	t.Run("point out of range", func(t *testing.T) {
+		alert := &cap.Alert{Infos: []cap.Info{{Areas: []cap.Area{{Circles: []string{"-120.14,38.47 10"}}}}}}
+
+		_, err := alert.Area()
+		assert.ErrorIs(t, err, cap.ErrInvalidAlert)
+	})
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+)
+
+var (
+	// ErrAPIKeyNotExists is returned when an API key is not found in the database.
+	ErrAPIKeyNotExists = fmt.Errorf("api key doesn't exist")
+	// ErrInvalidAPIKeyName indicates an empty or too long API key name.
+	ErrInvalidAPIKeyName = fmt.Errorf("invalid api key name")
+)
+
+// APIKeyRepository defines operations on the user's API keys. UseAPIKey finds a key by its
+// hash and records that it was used.
+type APIKeyRepository interface {
+	GetAPIKeysByUserID(ctx context.Context, userID int) ([]*models.APIKey, error)
+	CreateAPIKey(ctx context.Context, key *models.APIKey) (*models.APIKey, error)
+	DeleteAPIKey(ctx context.Context, userID, keyID int) error
+	UseAPIKey(ctx context.Context, hash []byte) (*models.APIKey, error)
+}
+
+// APIKeyService defines business logic around API keys. Authenticate returns the ID of the
+// user owning the key, or ErrAPIKeyNotExists for an unknown or revoked key.
+type APIKeyService interface {
+	GetAPIKeysByUserID(ctx context.Context, userID int) ([]*models.APIKey, error)
+	CreateAPIKey(ctx context.Context, userID int, name string) (*CreatedAPIKey, error)
+	DeleteAPIKey(ctx context.Context, userID, keyID int) error
+	Authenticate(ctx context.Context, key string) (int, error)
+}
+
+// APIKeyRequest defines the payload for creating an API key.
+type APIKeyRequest struct {
+	Name string `json:"name"`
+}
+
+// CreatedAPIKey is a newly created API key along with the key itself, which is shown only once.
+type CreatedAPIKey struct {
+	*models.APIKey
+	Key string `json:"key"`
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+)
+
+// CampaignFilter narrows the campaigns of a user to those sent from the template TemplateID,
+// or matches every campaign if it is zero.
+type CampaignFilter struct {
+	TemplateID int
+}
+
+// CampaignRepository defines the data access for the record of the campaigns sent from templates.
+type CampaignRepository interface {
+	CreateCampaign(ctx context.Context, c *models.Campaign) error
+	GetCampaignsByUserID(ctx context.Context, userID int, filter CampaignFilter, limit, offset int) ([]*models.Campaign, error)
+}
+
+// CampaignService defines the business logic for listing the campaigns sent from templates.
+type CampaignService interface {
+	GetCampaigns(ctx context.Context, userID int, filter CampaignFilter, page PageRequest) ([]*models.Campaign, error)
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrApprovalPolicyNotExists is returned when a user has not configured an approval policy.
+	ErrApprovalPolicyNotExists = fmt.Errorf("approval policy doesn't exist")
+	// ErrInvalidApprovalPolicy is returned for invalid approval policies; the error describes the problem.
+	ErrInvalidApprovalPolicy = fmt.Errorf("invalid approval policy")
+	// ErrApprovalPolicyChangeNotExists is returned when a change of an approval policy is not found,
+	// or is neither requested by the user nor awaiting their decision.
+	ErrApprovalPolicyChangeNotExists = fmt.Errorf("approval policy change doesn't exist")
+	// ErrApprovalPolicyChangePending is returned when relaxing or deleting an approval policy while
+	// an earlier change of it still awaits a decision.
+	ErrApprovalPolicyChangePending = fmt.Errorf("approval policy change is already pending")
+	// ErrCampaignApprovalNotExists is returned when a campaign approval is not found, or is neither
+	// requested by the user nor awaiting their decision.
+	ErrCampaignApprovalNotExists = fmt.Errorf("campaign approval doesn't exist")
+	// ErrNotApprover is returned when a user decides on a campaign or a policy change they may not
+	// approve, including their own.
+	ErrNotApprover = fmt.Errorf("user is not an approver of the campaign")
+	// ErrApprovalNotPending is returned when deciding on a campaign that has already been decided on.
+	ErrApprovalNotPending = fmt.Errorf("campaign approval is not pending")
+	// ErrApprovalExpired is returned when deciding on a campaign after its deadline.
+	ErrApprovalExpired = fmt.Errorf("campaign approval has expired")
+	// ErrInvalidApprovalComment is returned when the comment of a decision is too long.
+	ErrInvalidApprovalComment = fmt.Errorf("invalid approval comment")
+	// ErrApprovalTemplateChanged is returned when approving a campaign whose template has changed
+	// since approval was requested, so that approvers never send a text they haven't seen.
+	ErrApprovalTemplateChanged = fmt.Errorf("template has changed since approval was requested")
+)
+
+// ApprovalPolicyRepository defines the interface for persisting per-user approval policies and
+// the changes relaxing or deleting them. Changes are visible to the user who requested them and
+// to their approvers. DecidePolicyChange applies an approved change along with the decision.
+type ApprovalPolicyRepository interface {
+	GetApprovalPolicyByUserID(ctx context.Context, userID int) (*models.ApprovalPolicy, error)
+	UpsertApprovalPolicy(ctx context.Context, userID int, p *models.ApprovalPolicy) (*models.ApprovalPolicy, error)
+	CreatePolicyChange(ctx context.Context, c *models.ApprovalPolicyChange) (*models.ApprovalPolicyChange, error)
+	GetPolicyChangesByUserID(ctx context.Context, userID int) ([]*models.ApprovalPolicyChange, error)
+	GetPolicyChangeByID(ctx context.Context, userID, changeID int) (*models.ApprovalPolicyChange, error)
+	DecidePolicyChange(ctx context.Context, approverID, changeID int, status models.ApprovalStatus, comment string) (*models.ApprovalPolicyChange, error)
+}
+
+// ApprovalPolicyService defines the interface for managing a user's approval policy. Changes that
+// relax an existing policy, and its deletion, are returned as pending until an approver of the
+// policy approves them; stricter policies take effect right away.
+type ApprovalPolicyService interface {
+	GetApprovalPolicy(ctx context.Context, userID int) (*models.ApprovalPolicy, error)
+	UpdateApprovalPolicy(ctx context.Context, userID int, p *models.ApprovalPolicy) (*models.ApprovalPolicy, *models.ApprovalPolicyChange, error)
+	DeleteApprovalPolicy(ctx context.Context, userID int) (*models.ApprovalPolicyChange, error)
+	GetPolicyChanges(ctx context.Context, userID int) ([]*models.ApprovalPolicyChange, error)
+	ApprovePolicyChange(ctx context.Context, userID, changeID int, comment string) (*models.ApprovalPolicyChange, error)
+	RejectPolicyChange(ctx context.Context, userID, changeID int, comment string) (*models.ApprovalPolicyChange, error)
+}
+
+// CampaignApprovalRepository defines the persistence of campaign approvals and their audit trail.
+// Approvals are visible to the user who requested them and to their approvers. CreateApproval,
+// DecideApproval, ExpireApproval and FailApproval record the matching event along with the change.
+type CampaignApprovalRepository interface {
+	CreateApproval(ctx context.Context, a *models.CampaignApproval) (*models.CampaignApproval, error)
+	GetApprovalsByUserID(ctx context.Context, userID int) ([]*models.CampaignApproval, error)
+	GetApprovalByID(ctx context.Context, userID, approvalID int) (*models.CampaignApproval, error)
+	DecideApproval(ctx context.Context, approverID, approvalID int, status models.ApprovalStatus, comment string) (*models.CampaignApproval, error)
+	ExpireApproval(ctx context.Context, approvalID int) error
+	FailApproval(ctx context.Context, approvalID int) error
+}
+
+// CampaignApprovalService defines the two-person approval of campaigns. RequestApproval submits
+// a campaign for approval if the user's policy requires it, and returns nil if it can be sent
+// right away; RequestMessageApproval does the same for a text that isn't a stored template.
+// The campaign is sent when an approver approves it.
+type CampaignApprovalService interface {
+	RequestApproval(ctx context.Context, userID, templateID int, opts *SendNotificationRequest) (*models.CampaignApproval, error)
+	RequestMessageApproval(ctx context.Context, userID int, campaignID uuid.UUID, text string, opts *SendNotificationRequest) (*models.CampaignApproval, error)
+	GetApprovals(ctx context.Context, userID int) ([]*models.CampaignApproval, error)
+	GetApprovalByID(ctx context.Context, userID, approvalID int) (*models.CampaignApproval, error)
+	ApproveCampaign(ctx context.Context, userID, approvalID int, comment string) (*models.CampaignApproval, error)
+	RejectCampaign(ctx context.Context, userID, approvalID int, comment string) (*models.CampaignApproval, error)
+}
+
+// ApprovalDecisionRequest defines the optional payload of approving or rejecting a campaign.
+type ApprovalDecisionRequest struct {
+	Comment string `json:"comment"`
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/cap"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrCAPAlertAlreadyExists is returned when the user already received an alert with the
+	// same sender and identifier.
+	ErrCAPAlertAlreadyExists = fmt.Errorf("cap alert already exists")
+	// ErrCAPAlertNotExists is returned when a cancellation refers to no alert the user received.
+	ErrCAPAlertNotExists = fmt.Errorf("cap alert doesn't exist")
+	// ErrInvalidCAPAlert indicates an alert that is malformed or can't be acted on; the error
+	// describes the problem.
+	ErrInvalidCAPAlert = fmt.Errorf("invalid cap alert")
+)
+
+// Actions taken on a CAP alert.
+const (
+	CAPActionLaunched  = "launched"
+	CAPActionUpdated   = "updated"
+	CAPActionCancelled = "cancelled"
+	CAPActionIgnored   = "ignored"
+)
+
+// CAPAlertRepository defines operations on the CAP alerts received by the user.
+// GetCAPAlertsByReferences returns the alerts matching the sender and identifier of any reference.
+type CAPAlertRepository interface {
+	CreateCAPAlert(ctx context.Context, alert *models.CAPAlert) (*models.CAPAlert, error)
+	DeleteCAPAlert(ctx context.Context, userID, alertID int) error
+	GetCAPAlertsByReferences(ctx context.Context, userID int, refs []cap.Reference) ([]*models.CAPAlert, error)
+	CancelCAPAlerts(ctx context.Context, userID int, alertIDs []int) error
+}
+
+// CAPService turns CAP alerts into campaigns: alerts launch them, updates replace the campaigns
+// of the alerts they refer to and cancellations cancel them.
+type CAPService interface {
+	IngestAlert(ctx context.Context, userID int, alert *cap.Alert) (*CAPResult, error)
+}
+
+// CAPResult describes what was done with a CAP alert: Action is one of the CAPAction constants,
+// CampaignID the campaign launched and CancelledCampaigns the campaigns cancelled or superseded.
+// Approval is set if the launched campaign waits for approval under the user's approval policy.
+type CAPResult struct {
+	Action             string                   `json:"action"`
+	CampaignID         *uuid.UUID               `json:"campaignId,omitempty"`
+	CancelledCampaigns []uuid.UUID              `json:"cancelledCampaigns"`
+	Approval           *models.CampaignApproval `json:"approval,omitempty"`
+}
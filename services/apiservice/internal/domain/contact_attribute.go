@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+)
+
+var (
+	// ErrContactAttributeNotExists is returned when a contact attribute is not found in the database.
+	ErrContactAttributeNotExists = fmt.Errorf("contact attribute doesn't exist")
+	// ErrContactAttributeAlreadyExists is returned when the user already has an attribute with the name.
+	ErrContactAttributeAlreadyExists = fmt.Errorf("contact attribute already exists")
+	// ErrInvalidContactAttribute is the base error for invalid attribute definitions.
+	ErrInvalidContactAttribute = fmt.Errorf("invalid contact attribute")
+	// ErrInvalidContactAttributeName indicates an empty or too long attribute name.
+	ErrInvalidContactAttributeName = fmt.Errorf("%w: invalid name", ErrInvalidContactAttribute)
+	// ErrInvalidContactAttributeType indicates an unknown attribute type.
+	ErrInvalidContactAttributeType = fmt.Errorf("%w: invalid type", ErrInvalidContactAttribute)
+	// ErrInvalidContactAttributeOptions indicates an enum without options, duplicate or too long
+	// options, or options given for another type.
+	ErrInvalidContactAttributeOptions = fmt.Errorf("%w: invalid options", ErrInvalidContactAttribute)
+)
+
+// ContactAttributeRepository defines CRUD operations on the user's contact attribute definitions.
+type ContactAttributeRepository interface {
+	GetContactAttributes(ctx context.Context, userID int) ([]*models.ContactAttribute, error)
+	CreateContactAttribute(ctx context.Context, attr *models.ContactAttribute) (*models.ContactAttribute, error)
+	UpdateContactAttribute(ctx context.Context, userID, attrID int, attr *models.ContactAttribute) (*models.ContactAttribute, error)
+	DeleteContactAttribute(ctx context.Context, userID, attrID int) error
+}
+
+// ContactAttributeService defines business logic around the user's contact attribute definitions.
+type ContactAttributeService interface {
+	GetContactAttributes(ctx context.Context, userID int) ([]*models.ContactAttribute, error)
+	CreateContactAttribute(ctx context.Context, attr *models.ContactAttribute) (*models.ContactAttribute, error)
+	UpdateContactAttribute(ctx context.Context, userID, attrID int, attr *models.ContactAttribute) (*models.ContactAttribute, error)
+	DeleteContactAttribute(ctx context.Context, userID, attrID int) error
+}
+
+// ContactAttributeRequest defines the payload for creating or updating a contact attribute.
+type ContactAttributeRequest struct {
+	Name     string               `json:"name"`
+	Type     models.AttributeType `json:"type"`
+	Options  []string             `json:"options"`
+	Required bool                 `json:"required"`
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+)
+
+var (
+	// ErrInvalidExportFormat is returned when an export asks for a format other than CSV or XLSX.
+	ErrInvalidExportFormat = fmt.Errorf("invalid export format")
+	// ErrContactExportNotExists is returned when an export is missing or belongs to another user.
+	ErrContactExportNotExists = fmt.Errorf("contact export doesn't exist")
+)
+
+// PostContactExportRequest is the payload for starting an asynchronous contacts export.
+type PostContactExportRequest struct {
+	Format models.ExportFormat `json:"format"`
+	Group  string              `json:"group"`
+	Search string              `json:"search"`
+}
+
+// ContactExportRepository defines persistence operations for asynchronous contact exports.
+type ContactExportRepository interface {
+	CreateContactExport(ctx context.Context, export *models.ContactExport) (*models.ContactExport, error)
+	GetContactExportByID(ctx context.Context, userID, exportID int) (*models.ContactExport, error)
+	StartContactExport(ctx context.Context, exportID int) error
+	CompleteContactExport(ctx context.Context, exportID, rowsWritten int, s3Key string) error
+	FailContactExport(ctx context.Context, exportID int, reason string) error
+}
+
+// ContactExportService writes a user's contacts to CSV or XLSX, either streamed to the
+// caller or asynchronously to S3, from where the finished file is served by a presigned link.
+type ContactExportService interface {
+	ExportContacts(ctx context.Context, userID int, format models.ExportFormat, filter ContactFilter, w io.Writer) error
+	StartContactExport(ctx context.Context, userID int, format models.ExportFormat, filter ContactFilter) (*models.ContactExport, error)
+	GetContactExport(ctx context.Context, userID, exportID int) (*models.ContactExport, error)
+}
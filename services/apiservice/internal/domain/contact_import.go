@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+)
+
+var (
+	// ErrContactImportNotExists is returned when an import job is missing or belongs to another user.
+	ErrContactImportNotExists = fmt.Errorf("contact import doesn't exist")
+	// ErrContactImportReportNotExists is returned when an import job has no rejected rows to report.
+	ErrContactImportReportNotExists = fmt.Errorf("contact import report doesn't exist")
+	// ErrContactImportDeletionsNotExists is returned when an import job has no deleted contacts to report.
+	ErrContactImportDeletionsNotExists = fmt.Errorf("contact import deletions report doesn't exist")
+)
+
+// ContactImportRepository defines persistence operations for contact import jobs.
+type ContactImportRepository interface {
+	CreateContactImport(ctx context.Context, contactImport *models.ContactImport) (*models.ContactImport, error)
+	GetContactImportByID(ctx context.Context, userID, importID int) (*models.ContactImport, error)
+	FailContactImport(ctx context.Context, importID int, reason string) error
+}
+
+// ContactImportService exposes the status of import jobs, their rejection reports and
+// the reports of contacts deleted, or to be deleted in a dry run, by sync imports.
+type ContactImportService interface {
+	GetContactImport(ctx context.Context, userID, importID int) (*models.ContactImport, error)
+	GetRejectionReport(ctx context.Context, userID, importID int) (io.ReadCloser, error)
+	GetDeletionsReport(ctx context.Context, userID, importID int) (io.ReadCloser, error)
+}
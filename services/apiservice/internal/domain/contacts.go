@@ -2,9 +2,13 @@ package domain
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/geo"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/segmentrule"
 )
 
 var (
@@ -16,47 +20,215 @@ var (
 	ErrInvalidContactName = fmt.Errorf("%w: invalid name", ErrInvalidContact)
 	// ErrInvalidContactPhone indicates the contact's phone number failed validation.
 	ErrInvalidContactPhone = fmt.Errorf("%w: invalid phone", ErrInvalidContact)
+	// ErrInvalidContactTimeZone indicates the contact's time zone is not a known IANA zone name.
+	ErrInvalidContactTimeZone = fmt.Errorf("%w: invalid time zone", ErrInvalidContact)
+	// ErrInvalidContactRegion indicates the region given to parse the contact's phone number is not a supported ISO 3166-1 alpha-2 code.
+	ErrInvalidContactRegion = fmt.Errorf("%w: invalid phone region", ErrInvalidContact)
+	// ErrInvalidContactAttributes indicates the contact has too many attributes or an attribute name or value of invalid length.
+	ErrInvalidContactAttributes = fmt.Errorf("%w: invalid attributes", ErrInvalidContact)
+	// ErrInvalidContactAttributeValue indicates an attribute value not matching the type of the
+	// attribute definition; the error names the attribute.
+	ErrInvalidContactAttributeValue = fmt.Errorf("%w: invalid attribute value", ErrInvalidContact)
+	// ErrMissingContactAttribute indicates a required attribute missing from a contact; the error
+	// names the attribute.
+	ErrMissingContactAttribute = fmt.Errorf("%w: missing required attribute", ErrInvalidContact)
+	// ErrInvalidContactEndpoints indicates the contact has too many endpoints, or one of an unknown
+	// channel, with an invalid address or label, or repeating another endpoint of the contact.
+	ErrInvalidContactEndpoints = fmt.Errorf("%w: invalid endpoints", ErrInvalidContact)
+	// ErrInvalidContactLocation indicates a latitude or longitude out of range.
+	ErrInvalidContactLocation = fmt.Errorf("%w: invalid location", ErrInvalidContact)
 	// ErrContactAlreadyExists indicates a uniqueness constraint violation on create/update.
 	ErrContactAlreadyExists = fmt.Errorf("contact already exists")
+	// ErrTooManyContacts indicates a bulk request naming more contacts than allowed per request.
+	ErrTooManyContacts = fmt.Errorf("too many contacts in one request")
+	// ErrEmptyContactFilter indicates a bulk change that would apply to every contact without
+	// being requested as such.
+	ErrEmptyContactFilter = fmt.Errorf("empty contact filter")
+	// ErrInvalidContactGroup indicates a group name longer than an attribute value may be.
+	ErrInvalidContactGroup = fmt.Errorf("invalid contact group")
 )
 
+// ContactFilter narrows the contacts of a user. IDs lists the contacts to match; Search
+// matches a part of the name or phone, case-insensitively; Group matches the "Group"
+// attribute and Attributes the given attribute values exactly. CreatedFrom and CreatedTo
+// bound the creation time, inclusive and exclusive respectively. Rule, the compiled rule of a
+// segment, and Area, the area contacts must be located in, are set by the services and never
+// decoded from requests. Empty fields match every contact.
+type ContactFilter struct {
+	IDs         []int             `json:"ids"`
+	Search      string            `json:"search"`
+	Group       string            `json:"group"`
+	Attributes  map[string]string `json:"attributes"`
+	CreatedFrom time.Time         `json:"createdFrom"`
+	CreatedTo   time.Time         `json:"createdTo"`
+	Rule        *segmentrule.Expr `json:"-"`
+	Area        *geo.Area         `json:"-"`
+}
+
+// IsEmpty reports whether the filter matches every contact.
+func (f ContactFilter) IsEmpty() bool {
+	return len(f.IDs) == 0 && f.Search == "" && f.Group == "" && len(f.Attributes) == 0 &&
+		f.CreatedFrom.IsZero() && f.CreatedTo.IsZero() && f.Rule == nil && f.Area == nil
+}
+
 // ContactsRepository defines CRUD operations against the persistence layer.
 // Implementations should handle SQL details and map domain errors.
 type ContactsRepository interface {
 	GetAllContactsByUserID(ctx context.Context, userID int) ([]*models.Contact, error)
-	GetContactsCountByUserID(ctx context.Context, userID int) (int, error)
-	GetContactsPageByUserID(ctx context.Context, userID, limit, offset int) ([]*models.Contact, error)
+	GetContactsCountByUserID(ctx context.Context, userID int, filter ContactFilter) (int, error)
+	GetContactsPageByUserID(ctx context.Context, userID int, filter ContactFilter, page PageRequest) ([]*models.Contact, string, error)
 	GetContactByID(ctx context.Context, userID, contactID int) (*models.Contact, error)
 	CreateContact(ctx context.Context, contact *models.Contact) (*models.Contact, error)
 	UpdateContact(ctx context.Context, userID, contactID int, updatedContact *models.Contact) (*models.Contact, error)
+	GetTakenContactAddresses(ctx context.Context, userID, exceptContactID int, addresses []*models.ContactEndpoint) ([]*models.ContactEndpoint, error)
 	DeleteContact(ctx context.Context, userID, contactID int) error
+	CreateContacts(ctx context.Context, userID int, contacts []*models.Contact) ([]*models.Contact, error)
+	DeleteContacts(ctx context.Context, userID int, filter ContactFilter) (int, error)
+	SetContactsGroup(ctx context.Context, userID int, filter ContactFilter, group string) (int, error)
+	GetContactAttributeNames(ctx context.Context, userID int, filter ContactFilter) ([]string, error)
+	StreamContacts(ctx context.Context, userID int, filter ContactFilter, fn func(*models.Contact) error) error
 }
 
 // ContactsService defines business logic methods for contacts.
 // It validates input and delegates persistence to ContactsRepository.
 type ContactsService interface {
-	GetContactsCountByUserID(ctx context.Context, userID int) (int, error)
-	GetContactsPageByUserID(ctx context.Context, userID, limit, offset int) ([]*models.Contact, error)
+	GetContactsCountByUserID(ctx context.Context, userID int, filter ContactFilter) (int, error)
+	GetContactsPageByUserID(ctx context.Context, userID int, filter ContactFilter, page PageRequest) ([]*models.Contact, string, error)
 	GetContactByID(ctx context.Context, userID, contactID int) (*models.Contact, error)
-	CreateContact(ctx context.Context, contact *models.Contact) (*models.Contact, error)
-	UpdateContact(ctx context.Context, userID, contactID int, updatedContact *models.Contact) (*models.Contact, error)
+	CreateContact(ctx context.Context, contact *models.Contact, region string) (*models.Contact, error)
+	UpdateContact(ctx context.Context, userID, contactID int, req *PutContactRequest) (*models.Contact, error)
 	DeleteContact(ctx context.Context, userID, contactID int) error
+	CreateContacts(ctx context.Context, userID int, contacts []PostContactRequest) (*BulkCreateContactsResponse, error)
+	DeleteContacts(ctx context.Context, userID int, filter ContactFilter) (int, error)
+	DeleteAllContacts(ctx context.Context, userID int) (int, error)
+	SetContactsGroup(ctx context.Context, userID int, filter ContactFilter, group string) (int, error)
 }
 
 // PostContactRequest defines the payload for creating a new contact via API.
+// TimeZone is optional; when empty it is inferred from the phone number at send time.
+// Region overrides the user's default region for parsing a phone number without a country code.
+// Endpoints are the further phones and e-mails of the contact in order of preference.
+// Location holds the optional coordinates of the contact.
 type PostContactRequest struct {
-	Name  string `json:"name"`
-	Phone string `json:"phone"`
+	Name       string                    `json:"name"`
+	Phone      string                    `json:"phone"`
+	TimeZone   string                    `json:"timeZone"`
+	Attributes map[string]string         `json:"attributes"`
+	Endpoints  []*models.ContactEndpoint `json:"endpoints"`
+	Location   *models.Location          `json:"location"`
+	Region     string                    `json:"region"`
 }
 
 // PutContactRequest defines the payload for updating an existing contact.
+// The name and phone are replaced; omitted optional fields keep their stored values. An empty
+// timeZone reverts to inferring it from the phone number, an empty object or list clears the
+// attributes or endpoints, and an explicit null location clears the location.
 type PutContactRequest struct {
-	Name  string `json:"name"`
-	Phone string `json:"phone"`
+	Name       string                    `json:"name"`
+	Phone      string                    `json:"phone"`
+	TimeZone   *string                   `json:"timeZone"`
+	Attributes map[string]string         `json:"attributes"`
+	Endpoints  []*models.ContactEndpoint `json:"endpoints"`
+	Location   NullableLocation          `json:"location"`
+	Region     string                    `json:"region"`
+}
+
+// NullableLocation tells an omitted location, with Set false, from an explicit null one.
+type NullableLocation struct {
+	Set      bool
+	Location *models.Location
+}
+
+// UnmarshalJSON records that the location was given, possibly as null.
+func (l *NullableLocation) UnmarshalJSON(data []byte) error {
+	l.Set = true
+	return json.Unmarshal(data, &l.Location)
+}
+
+// Merge returns the contact of the user updated by the request: the stored contact with the
+// fields the request gives replaced.
+func (r *PutContactRequest) Merge(userID int, stored *models.Contact) *models.Contact {
+	contact := &models.Contact{
+		UserID:     userID,
+		Name:       r.Name,
+		Phone:      r.Phone,
+		TimeZone:   stored.TimeZone,
+		Attributes: stored.Attributes,
+		Endpoints:  stored.Endpoints,
+		Location:   stored.Location,
+	}
+
+	if r.TimeZone != nil {
+		contact.TimeZone = *r.TimeZone
+	}
+	if r.Attributes != nil {
+		contact.Attributes = r.Attributes
+	}
+	if r.Endpoints != nil {
+		contact.Endpoints = r.Endpoints
+	}
+	if r.Location.Set {
+		contact.Location = r.Location.Location
+	}
+
+	return contact
 }
 
 // GetContactsResponse represents the response payload for getting the list of user's contacts.
+// Total counts every contact matching the filter; NextCursor is empty on the last page.
 type GetContactsResponse struct {
-	Contacts []*models.Contact `json:"contacts"`
-	Total    int               `json:"total"`
+	Contacts   []*models.Contact `json:"contacts"`
+	Total      int               `json:"total"`
+	NextCursor string            `json:"nextCursor,omitempty"`
+}
+
+// BulkItemStatus is the outcome of one contact of a bulk create request.
+type BulkItemStatus string
+
+const (
+	// BulkItemCreated means the contact was created.
+	BulkItemCreated BulkItemStatus = "created"
+	// BulkItemDuplicate means the user already has a contact with the phone number,
+	// or an earlier item of the request has it.
+	BulkItemDuplicate BulkItemStatus = "duplicate"
+	// BulkItemInvalid means the contact failed validation; Error gives the reason.
+	BulkItemInvalid BulkItemStatus = "invalid"
+)
+
+// PostContactsBulkRequest defines the payload for creating many contacts at once.
+type PostContactsBulkRequest struct {
+	Contacts []PostContactRequest `json:"contacts"`
+}
+
+// BulkContactResult is the outcome of the item at Index of a bulk create request.
+type BulkContactResult struct {
+	Index   int             `json:"index"`
+	Status  BulkItemStatus  `json:"status"`
+	Contact *models.Contact `json:"contact,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// BulkCreateContactsResponse reports the outcome of a bulk create request, per item and in total.
+type BulkCreateContactsResponse struct {
+	Created    int                  `json:"created"`
+	Duplicates int                  `json:"duplicates"`
+	Invalid    int                  `json:"invalid"`
+	Results    []*BulkContactResult `json:"results"`
+}
+
+// PostContactsDeleteRequest defines the payload for deleting the contacts matching a filter.
+type PostContactsDeleteRequest struct {
+	Filter ContactFilter `json:"filter"`
+}
+
+// PostContactsGroupRequest defines the payload for assigning the contacts matching a filter
+// to a group. An empty group removes the contacts from their groups.
+type PostContactsGroupRequest struct {
+	Filter ContactFilter `json:"filter"`
+	Group  string        `json:"group"`
+}
+
+// BulkContactsResponse reports how many contacts a bulk delete or group assignment affected.
+type BulkContactsResponse struct {
+	Affected int `json:"affected"`
 }
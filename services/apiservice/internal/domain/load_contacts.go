@@ -2,19 +2,65 @@ package domain
 
 import (
 	"context"
+	"fmt"
 	"io"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+)
+
+var (
+	// ErrInvalidImportMode is returned when an upload asks for an unknown import mode.
+	ErrInvalidImportMode = fmt.Errorf("invalid import mode")
+	// ErrInvalidSheetSelection is returned when an upload names an invalid sheet or group.
+	ErrInvalidSheetSelection = fmt.Errorf("invalid sheet selection")
 )
 
 // LoadContactsService defines the interface for services that handle
 // uploading contact files and initiating their asynchronous processing.
-// Implementations should store the file payload and enqueue a processing task.
+// Implementations should store the file payload, record an import job and enqueue a processing task.
 type LoadContactsService interface {
-	ProcessUpload(ctx context.Context, userID int, filename string, payload io.ReadSeeker) error
+	ProcessUpload(ctx context.Context, userID int, filename string, opts ImportOptions, payload io.ReadSeeker) (*models.ContactImport, error)
+}
+
+// ImportOptions are the settings given with an uploaded contacts file.
+// Region parses phone numbers without a country code; when empty the user's default region is used.
+// Mode defaults to insert-only; a DryRun reports the changes of the import without making them.
+// Sheets selects the sheets of an XLSX workbook.
+type ImportOptions struct {
+	Mapping ColumnMapping
+	Region  string
+	Mode    models.ImportMode
+	DryRun  bool
+	Sheets  SheetSelection
+}
+
+// ColumnMapping tells the contacts worker which columns of an uploaded file hold the
+// contact's name and phone. Each value is either a header name (case-insensitive) or a
+// 1-based column number; empty values are detected from the header row.
+type ColumnMapping struct {
+	Name  string `json:"name,omitempty"`
+	Phone string `json:"phone,omitempty"`
+}
+
+// SheetSelection names the sheets of an XLSX workbook to import, matched case-insensitively.
+// All imports every sheet; otherwise the named sheets are imported, or only the first one when
+// none are named. Groups maps sheet names to the contact group stored in the "Group" attribute
+// of the contacts of that sheet.
+type SheetSelection struct {
+	All    bool              `json:"all,omitempty"`
+	Names  []string          `json:"names,omitempty"`
+	Groups map[string]string `json:"groups,omitempty"`
 }
 
 // LoadContactsTask represents the message payload published to Kafka
 // for initiating contact file processing.
 type LoadContactsTask struct {
-	S3Key  string `json:"s3Key"`
-	UserID int    `json:"userID"`
+	ImportID int               `json:"importID"`
+	S3Key    string            `json:"s3Key"`
+	UserID   int               `json:"userID"`
+	Mapping  ColumnMapping     `json:"mapping"`
+	Region   string            `json:"region"`
+	Mode     models.ImportMode `json:"mode"`
+	DryRun   bool              `json:"dryRun"`
+	Sheets   SheetSelection    `json:"sheets"`
 }
@@ -0,0 +1,27 @@
+package domain
+
+import "fmt"
+
+var (
+	// ErrInvalidCursor is returned when a page cursor is malformed or was issued for another sort order.
+	ErrInvalidCursor = fmt.Errorf("invalid cursor")
+	// ErrInvalidSort is returned when a listing is sorted by a column that does not support sorting.
+	ErrInvalidSort = fmt.Errorf("invalid sort")
+)
+
+// Sort orders a listing by Field, ascending unless Desc is set. Rows with equal
+// values are ordered by ID. An empty Field sorts by ID.
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// PageRequest selects a page of a listing. Cursor, when set, continues right after the
+// last row of the page it was returned with, and Offset is ignored; keyset pagination
+// keeps deep pages as cheap as the first one.
+type PageRequest struct {
+	Limit  int
+	Offset int
+	Cursor string
+	Sort   Sort
+}
@@ -6,7 +6,13 @@ import (
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
 )
 
-// ProfileService defines the interface for retrieving user profile information.
+// ProfileService defines the interface for retrieving and updating user profile information.
 type ProfileService interface {
 	GetUserByID(ctx context.Context, id int) (*models.User, error)
+	UpdateDefaultRegion(ctx context.Context, id int, region string) (*models.User, error)
+}
+
+// PutProfileRequest defines the payload for updating the user's profile settings.
+type PutProfileRequest struct {
+	DefaultRegion string `json:"defaultRegion"`
 }
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+)
+
+var (
+	// ErrQuietHoursNotExists is returned when a user has not configured quiet hours.
+	ErrQuietHoursNotExists = fmt.Errorf("quiet hours don't exist")
+	// ErrInvalidQuietHours is returned when the quiet hours window or time zone is invalid.
+	ErrInvalidQuietHours = fmt.Errorf("invalid quiet hours")
+)
+
+// QuietHoursRepository defines the interface for persisting per-user quiet hours.
+type QuietHoursRepository interface {
+	GetQuietHoursByUserID(ctx context.Context, userID int) (*models.QuietHours, error)
+	UpsertQuietHours(ctx context.Context, userID int, qh *models.QuietHours) (*models.QuietHours, error)
+	DeleteQuietHours(ctx context.Context, userID int) error
+}
+
+// QuietHoursService defines the interface for managing a user's quiet hours.
+type QuietHoursService interface {
+	GetQuietHours(ctx context.Context, userID int) (*models.QuietHours, error)
+	UpdateQuietHours(ctx context.Context, userID int, qh *models.QuietHours) (*models.QuietHours, error)
+	DeleteQuietHours(ctx context.Context, userID int) error
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+)
+
+var (
+	// ErrRetryPolicyNotExists is returned when a user has not stored a retry policy.
+	ErrRetryPolicyNotExists = fmt.Errorf("retry policy doesn't exist")
+	// ErrInvalidRetryPolicy is returned when a retry policy has out-of-range values or an unknown backoff.
+	ErrInvalidRetryPolicy = fmt.Errorf("invalid retry policy")
+)
+
+// RetryPolicyRepository defines the interface for persisting per-user default retry policies.
+type RetryPolicyRepository interface {
+	GetRetryPolicyByUserID(ctx context.Context, userID int) (*models.RetryPolicy, error)
+	UpsertRetryPolicy(ctx context.Context, userID int, policy *models.RetryPolicy) (*models.RetryPolicy, error)
+}
+
+// RetryPolicyService defines the interface for reading and updating a user's default retry policy.
+type RetryPolicyService interface {
+	GetRetryPolicy(ctx context.Context, userID int) (*models.RetryPolicy, error)
+	UpdateRetryPolicy(ctx context.Context, userID int, policy *models.RetryPolicy) (*models.RetryPolicy, error)
+}
@@ -6,9 +6,16 @@ import (
 	"github.com/aws/aws-sdk-go/service/s3"
 )
 
-// S3Client abstracts AWS S3 PutObject operations.
-// Implementations of this interface should handle uploading data streams
-// to S3 with context-based cancellation and retries.
+// S3Client abstracts AWS S3 PutObject and GetObject operations.
+// Implementations of this interface should handle transferring data streams
+// to and from S3 with context-based cancellation and retries.
 type S3Client interface {
+	GetObjectWithContext(ctx aws.Context, input *s3.GetObjectInput, opts ...request.Option) (*s3.GetObjectOutput, error)
 	PutObjectWithContext(ctx aws.Context, input *s3.PutObjectInput, opts ...request.Option) (*s3.PutObjectOutput, error)
 }
+
+// S3Presigner creates requests for S3 objects that can be presigned into download links.
+// *s3.S3 implements it.
+type S3Presigner interface {
+	GetObjectRequest(input *s3.GetObjectInput) (*request.Request, *s3.GetObjectOutput)
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+)
+
+var (
+	// ErrSegmentNotExists is returned when a segment is not found in the database.
+	ErrSegmentNotExists = fmt.Errorf("segment doesn't exist")
+	// ErrSegmentAlreadyExists is returned when the user already has a segment with the name.
+	ErrSegmentAlreadyExists = fmt.Errorf("segment already exists")
+	// ErrInvalidSegment is the base error for invalid segments.
+	ErrInvalidSegment = fmt.Errorf("invalid segment")
+	// ErrInvalidSegmentName indicates an empty or too long segment name.
+	ErrInvalidSegmentName = fmt.Errorf("%w: invalid name", ErrInvalidSegment)
+	// ErrInvalidSegmentRule indicates a rule that can't be parsed or compares a typed attribute
+	// with a value of another type; the error describes the problem.
+	ErrInvalidSegmentRule = fmt.Errorf("%w: invalid rule", ErrInvalidSegment)
+)
+
+// SegmentRepository defines CRUD operations on the user's segments.
+type SegmentRepository interface {
+	GetSegmentsByUserID(ctx context.Context, userID int) ([]*models.Segment, error)
+	GetSegmentByID(ctx context.Context, userID, segmentID int) (*models.Segment, error)
+	CreateSegment(ctx context.Context, segment *models.Segment) (*models.Segment, error)
+	UpdateSegment(ctx context.Context, userID, segmentID int, segment *models.Segment) (*models.Segment, error)
+	DeleteSegment(ctx context.Context, userID, segmentID int) error
+}
+
+// SegmentService defines business logic around the user's segments. PreviewRule and
+// PreviewSegment count the contacts a rule matches right now and return a few of them.
+type SegmentService interface {
+	GetSegmentsByUserID(ctx context.Context, userID int) ([]*models.Segment, error)
+	GetSegmentByID(ctx context.Context, userID, segmentID int) (*models.Segment, error)
+	CreateSegment(ctx context.Context, segment *models.Segment) (*models.Segment, error)
+	UpdateSegment(ctx context.Context, userID, segmentID int, segment *models.Segment) (*models.Segment, error)
+	DeleteSegment(ctx context.Context, userID, segmentID int) error
+	PreviewRule(ctx context.Context, userID int, rule string) (*SegmentPreview, error)
+	PreviewSegment(ctx context.Context, userID, segmentID int) (*SegmentPreview, error)
+}
+
+// SegmentRequest defines the payload for creating or updating a segment.
+type SegmentRequest struct {
+	Name string `json:"name"`
+	Rule string `json:"rule"`
+}
+
+// SegmentPreviewRequest defines the payload for previewing a rule before saving it.
+type SegmentPreviewRequest struct {
+	Rule string `json:"rule"`
+}
+
+// SegmentPreview is the number of contacts a segment matches and the first of them by ID.
+type SegmentPreview struct {
+	Count  int               `json:"count"`
+	Sample []*models.Contact `json:"sample"`
+}
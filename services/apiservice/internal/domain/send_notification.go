@@ -2,20 +2,106 @@ package domain
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/geo"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/smsutils"
+	"github.com/google/uuid"
 )
 
+// ErrInvalidPriority is returned when a campaign is sent with an unknown priority.
+var ErrInvalidPriority = fmt.Errorf("invalid priority")
+
 // SendNotificationService defines the behavior for sending notifications.
+// SendMessage sends a text that isn't a stored template as the campaign with the given ID.
+// CountRecipients validates the options and counts the contacts a campaign would be sent to.
+// PreviewNotification reports what a campaign would send without sending it.
 type SendNotificationService interface {
-	SendNotification(ctx context.Context, userID int, templateID int) error
+	SendNotification(ctx context.Context, userID int, templateID int, opts *SendNotificationRequest) error
+	SendMessage(ctx context.Context, userID int, campaignID uuid.UUID, text string, opts *SendNotificationRequest) error
+	CountRecipients(ctx context.Context, userID int, opts *SendNotificationRequest) (int, error)
+	PreviewNotification(ctx context.Context, userID int, templateID int, opts *SendNotificationRequest) (*CampaignPreview, error)
+}
+
+// SendNotificationRequest represents the optional request payload for sending notifications.
+// RetryPolicy overrides the user's default retry policy for this campaign only.
+// Priority defaults to models.PriorityCritical; other priorities honour the user's quiet hours.
+// Filter and SegmentID narrow the recipients to the matching contacts, and Area or the saved
+// zone ZoneID to the contacts located in it; all contacts are notified without them.
+// Test marks a test send of a campaign to its test group.
+type SendNotificationRequest struct {
+	RetryPolicy *models.RetryPolicy `json:"retryPolicy"`
+	Priority    models.Priority     `json:"priority"`
+	Filter      *ContactFilter      `json:"filter"`
+	SegmentID   int                 `json:"segmentId"`
+	Area        *geo.Area           `json:"area"`
+	ZoneID      int                 `json:"zoneId"`
+	Test        bool                `json:"-"`
+}
+
+// ForTestGroup returns the options of a test send of the campaign: the same retry policy and
+// priority, but sent only to the contacts of the test group instead of the targeted recipients.
+// Without a priority, test sends are normal rather than critical.
+func (r *SendNotificationRequest) ForTestGroup(group string) *SendNotificationRequest {
+	priority := r.Priority
+	if priority == "" {
+		priority = models.PriorityNormal
+	}
+
+	return &SendNotificationRequest{
+		RetryPolicy: r.RetryPolicy,
+		Priority:    priority,
+		Filter:      &ContactFilter{Group: group},
+		Test:        true,
+	}
+}
+
+// CampaignPreview reports what a campaign would send. Encoding is UCS-2 if the message of any
+// recipient needs it, SegmentsPerMessage is the most segments a message takes, and EstimatedCost
+// is the price of TotalSegments in Currency. Samples holds the messages of the first recipients.
+// MaxSegments is the limit on the segments of a template body, which is checked as the body is
+// written; RecipientsOverMaxSegments counts the recipients whose rendered message exceeds it.
+type CampaignPreview struct {
+	Recipients                int               `json:"recipients"`
+	Encoding                  smsutils.Encoding `json:"encoding"`
+	SegmentsPerMessage        int               `json:"segmentsPerMessage"`
+	TotalSegments             int               `json:"totalSegments"`
+	MaxSegments               int               `json:"maxSegments"`
+	RecipientsOverMaxSegments int               `json:"recipientsOverMaxSegments"`
+	EstimatedCost             float64           `json:"estimatedCost"`
+	Currency                  string            `json:"currency"`
+	Samples                   []*PreviewMessage `json:"samples"`
+}
+
+// PreviewMessage is the message a campaign would send to a recipient, with its segmentation.
+type PreviewMessage struct {
+	Name  string `json:"name"`
+	Phone string `json:"phone"`
+	Text  string `json:"text"`
+	smsutils.Segmentation
 }
 
 // OutgoingNotification represents the payload sent to the notification topic.
-// UserID identifies the sender user, Template is the message body,
-// and Contacts lists the phone-number targets for this batch.
+// UserID identifies the sender user, CampaignID the campaign the batch belongs to, Template is the message body,
+// Contacts lists the phone-number targets for this batch,
+// and RetryPolicy is the retry policy resolved for the campaign.
+// QuietHours is set only for non-critical campaigns of users that configured quiet hours.
+// SenderID is the template's branded sender ID, used where the destination country allows it.
 type OutgoingNotification struct {
-	UserID   int                   `json:"userID"`
-	Template string                `json:"template"`
-	Contacts []*models.SlimContact `json:"contacts"`
+	UserID      int                   `json:"userID"`
+	CampaignID  uuid.UUID             `json:"campaignId"`
+	Template    string                `json:"template"`
+	Contacts    []*models.SlimContact `json:"contacts"`
+	RetryPolicy *models.RetryPolicy   `json:"retryPolicy"`
+	Priority    models.Priority       `json:"priority"`
+	QuietHours  *models.QuietHours    `json:"quietHours,omitempty"`
+	SenderID    string                `json:"senderId,omitempty"`
+}
+
+// CampaignCancellation represents the payload sent to the campaign cancellations topic: the
+// notifications of the campaign that were not handed to a provider yet are cancelled.
+type CampaignCancellation struct {
+	UserID     int       `json:"userID"`
+	CampaignID uuid.UUID `json:"campaignId"`
 }
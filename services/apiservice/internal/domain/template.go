@@ -3,53 +3,91 @@ package domain
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/textdiff"
 )
 
 var (
 	// ErrTemplateNotExists is returned when a template is not found in the database.
 	ErrTemplateNotExists = fmt.Errorf("template doesn't exist")
-	// ErrInvalidTemplate is returned when a template body is either too short or exceeds the allowed length.
-	ErrInvalidTemplate = fmt.Errorf("template is too long or too short")
+	// ErrInvalidTemplate is returned when a template name is too short or too long, or its body is
+	// empty or takes more SMS segments than allowed.
+	ErrInvalidTemplate = fmt.Errorf("invalid template")
+	// ErrInvalidTemplateSenderID is returned when a template's branded sender ID is not a valid alphanumeric sender ID.
+	ErrInvalidTemplateSenderID = fmt.Errorf("%w: invalid sender id", ErrInvalidTemplate)
 	// ErrTemplateAlreadyExists is returned when template with given name already exists
 	ErrTemplateAlreadyExists = fmt.Errorf("template already exists")
+	// ErrTemplateVersionNotExists is returned when a template has no version with the given number.
+	ErrTemplateVersionNotExists = fmt.Errorf("template version doesn't exist")
 )
 
+// TemplateFilter narrows the templates of a user. Search matches a part of the name or
+// body, case-insensitively. CreatedFrom and CreatedTo bound the creation time, inclusive
+// and exclusive respectively. Empty fields match every template.
+type TemplateFilter struct {
+	Search      string
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+}
+
 // TemplateRepository defines the interface for persisting and retrieving message templates from a data store.
 type TemplateRepository interface {
-	GetTemplatesCountByUserID(ctx context.Context, userID int) (int, error)
-	GetTemplatesPageByUserID(ctx context.Context, userID, limit, offset int) ([]*models.Template, error)
+	GetTemplatesCountByUserID(ctx context.Context, userID int, filter TemplateFilter) (int, error)
+	GetTemplatesPageByUserID(ctx context.Context, userID int, filter TemplateFilter, page PageRequest) ([]*models.Template, string, error)
 	GetTemplateByID(ctx context.Context, userID, templateID int) (*models.Template, error)
 	CreateTemplate(ctx context.Context, tmpl *models.Template) (*models.Template, error)
 	UpdateTemplate(ctx context.Context, userID, tmplID int, updatedTmpl *models.Template) (*models.Template, error)
 	DeleteTemplate(ctx context.Context, userID, tmplID int) error
+	GetTemplateVersions(ctx context.Context, userID, tmplID int) ([]*models.TemplateVersion, error)
+	GetTemplateVersion(ctx context.Context, userID, tmplID, version int) (*models.TemplateVersion, error)
 }
 
 // TemplateService defines the interface for business logic operations on message templates.
+// Every update of a template creates a new version; past versions can be compared and restored.
 type TemplateService interface {
-	GetTemplatesCountByUserID(ctx context.Context, userID int) (int, error)
-	GetTemplatesPageByUserID(ctx context.Context, userID, limit, offset int) ([]*models.Template, error)
+	GetTemplatesCountByUserID(ctx context.Context, userID int, filter TemplateFilter) (int, error)
+	GetTemplatesPageByUserID(ctx context.Context, userID int, filter TemplateFilter, page PageRequest) ([]*models.Template, string, error)
 	GetTemplateByID(ctx context.Context, userID, templateID int) (*models.Template, error)
 	CreateTemplate(ctx context.Context, template *models.Template) (*models.Template, error)
 	UpdateTemplate(ctx context.Context, userID, tmplID int, updatedTmpl *models.Template) (*models.Template, error)
 	DeleteTemplate(ctx context.Context, userID, tmplID int) error
+	GetTemplateVersions(ctx context.Context, userID, tmplID int) ([]*models.TemplateVersion, error)
+	GetTemplateVersion(ctx context.Context, userID, tmplID, version int) (*models.TemplateVersion, error)
+	DiffTemplateVersions(ctx context.Context, userID, tmplID, from, to int) (*TemplateDiff, error)
+	RestoreTemplateVersion(ctx context.Context, userID, tmplID, version int) (*models.Template, error)
 }
 
 // PostTemplateRequest represents the request payload for creating a new template.
 type PostTemplateRequest struct {
-	Name string `json:"name"`
-	Body string `json:"body"`
+	Name     string `json:"name"`
+	Body     string `json:"body"`
+	SenderID string `json:"senderId"`
 }
 
 // PutTemplateRequest represents the request payload for updating an existing template.
 type PutTemplateRequest struct {
-	Name string `json:"name"`
-	Body string `json:"body"`
+	Name     string `json:"name"`
+	Body     string `json:"body"`
+	SenderID string `json:"senderId"`
 }
 
 // GetTemplatesResponse represents the response payload for getting the list of user's templates.
+// Total counts every template matching the filter; NextCursor is empty on the last page.
 type GetTemplatesResponse struct {
-	Templates []*models.Template `json:"templates"`
-	Total     int                `json:"total"`
+	Templates  []*models.Template `json:"templates"`
+	Total      int                `json:"total"`
+	NextCursor string             `json:"nextCursor,omitempty"`
+}
+
+// TemplateDiff holds the word-level edits turning the name, body and sender ID of the version From
+// of a template into those of the version To.
+type TemplateDiff struct {
+	TemplateID int             `json:"templateId"`
+	From       int             `json:"from"`
+	To         int             `json:"to"`
+	Name       []textdiff.Edit `json:"name"`
+	Body       []textdiff.Edit `json:"body"`
+	SenderID   []textdiff.Edit `json:"senderId"`
 }
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+)
+
+var (
+	// ErrTriggerNotExists is returned when a trigger is not found in the database.
+	ErrTriggerNotExists = fmt.Errorf("trigger doesn't exist")
+	// ErrTriggerAlreadyExists is returned when the user already has a trigger with the name.
+	ErrTriggerAlreadyExists = fmt.Errorf("trigger already exists")
+	// ErrInvalidTrigger is the base error for invalid triggers; the error describes the problem.
+	ErrInvalidTrigger = fmt.Errorf("invalid trigger")
+	// ErrInvalidTriggerName indicates an empty or too long trigger name.
+	ErrInvalidTriggerName = fmt.Errorf("%w: invalid name", ErrInvalidTrigger)
+	// ErrInvalidTriggerMatchers indicates missing or malformed label matchers.
+	ErrInvalidTriggerMatchers = fmt.Errorf("%w: invalid matchers", ErrInvalidTrigger)
+	// ErrInvalidWebhook indicates a webhook payload with alerts that can't be handled; the error
+	// describes the problem.
+	ErrInvalidWebhook = fmt.Errorf("invalid webhook payload")
+)
+
+// Statuses of webhook alerts.
+const (
+	AlertStatusFiring   = "firing"
+	AlertStatusResolved = "resolved"
+)
+
+// TriggerRepository defines CRUD operations on the user's triggers and tracks the alerts
+// firing them. StartFiring records that the alert with the fingerprint fired the trigger and
+// ResolveFiring that it resolved; each reports false if the alert already was in that state.
+// A firing alert with another start, or one that fired more than repeatInterval ago, fires again.
+type TriggerRepository interface {
+	GetTriggersByUserID(ctx context.Context, userID int) ([]*models.Trigger, error)
+	GetTriggerByID(ctx context.Context, userID, triggerID int) (*models.Trigger, error)
+	CreateTrigger(ctx context.Context, trigger *models.Trigger) (*models.Trigger, error)
+	UpdateTrigger(ctx context.Context, userID, triggerID int, trigger *models.Trigger) (*models.Trigger, error)
+	DeleteTrigger(ctx context.Context, userID, triggerID int) error
+	StartFiring(ctx context.Context, triggerID int, fingerprint string, startsAt *time.Time, repeatInterval time.Duration) (bool, error)
+	ResolveFiring(ctx context.Context, triggerID int, fingerprint string) (bool, error)
+}
+
+// TriggerService defines business logic around the user's triggers. HandleAlerts sends the
+// notifications of the triggers matching the alerts received by a webhook.
+type TriggerService interface {
+	GetTriggersByUserID(ctx context.Context, userID int) ([]*models.Trigger, error)
+	GetTriggerByID(ctx context.Context, userID, triggerID int) (*models.Trigger, error)
+	CreateTrigger(ctx context.Context, trigger *models.Trigger) (*models.Trigger, error)
+	UpdateTrigger(ctx context.Context, userID, triggerID int, trigger *models.Trigger) (*models.Trigger, error)
+	DeleteTrigger(ctx context.Context, userID, triggerID int) error
+	HandleAlerts(ctx context.Context, userID int, alerts []*WebhookAlert) (*WebhookResult, error)
+}
+
+// TriggerRequest defines the payload for creating or updating a trigger.
+type TriggerRequest struct {
+	Name               string                `json:"name"`
+	Matchers           []models.LabelMatcher `json:"matchers"`
+	TemplateID         int                   `json:"templateId"`
+	ResolvedTemplateID *int                  `json:"resolvedTemplateId"`
+	Group              string                `json:"group"`
+	Priority           models.Priority       `json:"priority"`
+}
+
+// WebhookAlert is an alert received by a webhook, and the payload of the generic webhook.
+// Status is "firing" or "resolved". Fingerprint identifies the alert across its notifications;
+// if empty, it is derived from the labels. StartsAt, if set, tells apart the firings of an alert
+// whose resolution was never received.
+type WebhookAlert struct {
+	Status      string            `json:"status"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Fingerprint string            `json:"fingerprint"`
+	StartsAt    *time.Time        `json:"startsAt,omitempty"`
+}
+
+// AlertmanagerWebhook is the payload of the Prometheus Alertmanager webhook receiver, version 4.
+// Only the fields used to evaluate triggers are decoded.
+type AlertmanagerWebhook struct {
+	Version  string          `json:"version"`
+	Status   string          `json:"status"`
+	Receiver string          `json:"receiver"`
+	Alerts   []*WebhookAlert `json:"alerts"`
+}
+
+// WebhookResult counts what was done with the alerts of a webhook: triggers Fired and Resolved,
+// repeated notifications of alerts already in that state that were Deduplicated, and alerts
+// matching no trigger that were Unmatched. PendingApproval counts the notifications of fired and
+// resolved triggers held for approval under the user's approval policy.
+type WebhookResult struct {
+	Fired           int `json:"fired"`
+	Resolved        int `json:"resolved"`
+	Deduplicated    int `json:"deduplicated"`
+	Unmatched       int `json:"unmatched"`
+	PendingApproval int `json:"pendingApproval"`
+}
@@ -10,6 +10,8 @@ import (
 var (
 	// ErrUserNotExists is returned when a requested user cannot be found in the repository.
 	ErrUserNotExists = fmt.Errorf("user doesn't exist")
+	// ErrInvalidPhoneRegion is returned when a phone region is not a supported ISO 3166-1 alpha-2 code.
+	ErrInvalidPhoneRegion = fmt.Errorf("invalid phone region")
 )
 
 // UserRepository defines the interface for user persistence operations.
@@ -17,4 +19,5 @@ type UserRepository interface {
 	CreateUser(ctx context.Context, user *models.User) (*models.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
 	GetUserByID(ctx context.Context, id int) (*models.User, error)
+	UpdateUserDefaultRegion(ctx context.Context, id int, region string) (*models.User, error)
 }
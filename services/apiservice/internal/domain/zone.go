@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/geo"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+)
+
+var (
+	// ErrZoneNotExists is returned when a zone is not found in the database.
+	ErrZoneNotExists = fmt.Errorf("zone doesn't exist")
+	// ErrZoneAlreadyExists is returned when the user already has a zone with the name.
+	ErrZoneAlreadyExists = fmt.Errorf("zone already exists")
+	// ErrInvalidZone is the base error for invalid zones.
+	ErrInvalidZone = fmt.Errorf("invalid zone")
+	// ErrInvalidZoneName indicates an empty or too long zone name.
+	ErrInvalidZoneName = fmt.Errorf("%w: invalid name", ErrInvalidZone)
+	// ErrInvalidArea indicates a malformed circle or polygon; the error describes the problem.
+	ErrInvalidArea = fmt.Errorf("invalid area")
+)
+
+// ZoneRepository defines CRUD operations on the user's zones.
+type ZoneRepository interface {
+	GetZonesByUserID(ctx context.Context, userID int) ([]*models.Zone, error)
+	GetZoneByID(ctx context.Context, userID, zoneID int) (*models.Zone, error)
+	CreateZone(ctx context.Context, zone *models.Zone) (*models.Zone, error)
+	UpdateZone(ctx context.Context, userID, zoneID int, zone *models.Zone) (*models.Zone, error)
+	DeleteZone(ctx context.Context, userID, zoneID int) error
+}
+
+// ZoneService defines business logic around the user's zones.
+type ZoneService interface {
+	GetZonesByUserID(ctx context.Context, userID int) ([]*models.Zone, error)
+	GetZoneByID(ctx context.Context, userID, zoneID int) (*models.Zone, error)
+	CreateZone(ctx context.Context, zone *models.Zone) (*models.Zone, error)
+	UpdateZone(ctx context.Context, userID, zoneID int, zone *models.Zone) (*models.Zone, error)
+	DeleteZone(ctx context.Context, userID, zoneID int) error
+}
+
+// ZoneRequest defines the payload for creating or updating a zone.
+type ZoneRequest struct {
+	Name string   `json:"name"`
+	Area geo.Area `json:"area"`
+}
@@ -0,0 +1,223 @@
+// Package geo describes the areas notifications are targeted at: circles around a point and
+// GeoJSON polygons. Areas are checked here and matched against contact locations in Postgres
+// with its built-in geometric types, so no external geocoding or GIS service is involved.
+package geo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidCircle indicates a circle with out-of-range coordinates or radius.
+	ErrInvalidCircle = errors.New("invalid circle")
+	// ErrInvalidPolygon indicates a malformed GeoJSON polygon.
+	ErrInvalidPolygon = errors.New("invalid polygon")
+	// ErrAmbiguousArea indicates an area that is neither or both a circle and a polygon.
+	ErrAmbiguousArea = errors.New("area must be either a circle or a polygon")
+)
+
+const (
+	// EarthRadius is the mean radius of the Earth in metres.
+	EarthRadius = 6371008.8
+	// MaxRadius caps the radius of a circle in metres.
+	MaxRadius = 1000000
+	// MaxVertices caps the number of positions of all rings of a polygon.
+	MaxVertices = 10000
+)
+
+const (
+	// GeometryPolygon is the GeoJSON type of a polygon with optional holes.
+	GeometryPolygon = "Polygon"
+	// GeometryMultiPolygon is the GeoJSON type of a set of polygons.
+	GeometryMultiPolygon = "MultiPolygon"
+)
+
+// Circle is the area within Radius metres of the point at Lat, Lon, measured along the Earth's surface.
+type Circle struct {
+	Lat    float64 `json:"lat"`
+	Lon    float64 `json:"lon"`
+	Radius float64 `json:"radius"`
+}
+
+// Geometry is a GeoJSON geometry of type Polygon or MultiPolygon. Positions are
+// [longitude, latitude]; rings must be closed and the first ring of a polygon is its
+// exterior, the others are holes. Edges are straight lines in longitude and latitude,
+// as GeoJSON prescribes, and may not cross the antimeridian.
+type Geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// Area is a target area: exactly one of Circle and Polygon is set.
+type Area struct {
+	Circle  *Circle   `json:"circle,omitempty"`
+	Polygon *Geometry `json:"polygon,omitempty"`
+}
+
+// Ring is a closed line of [longitude, latitude] positions.
+type Ring [][2]float64
+
+// Polygon is an exterior ring followed by the rings of its holes.
+type Polygon []Ring
+
+// ValidLatLon reports whether lat and lon are a valid latitude and longitude in degrees.
+func ValidLatLon(lat, lon float64) bool {
+	return lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180
+}
+
+// Validate checks that the area is a valid circle or polygon.
+func (a *Area) Validate() error {
+	if (a.Circle == nil) == (a.Polygon == nil) {
+		return ErrAmbiguousArea
+	}
+	if a.Circle != nil {
+		return a.Circle.Validate()
+	}
+	_, err := a.Polygon.Polygons()
+	return err
+}
+
+// Validate checks the centre and the radius of the circle.
+func (c *Circle) Validate() error {
+	if !ValidLatLon(c.Lat, c.Lon) {
+		return fmt.Errorf("%w: centre out of range", ErrInvalidCircle)
+	}
+	if !(c.Radius > 0 && c.Radius <= MaxRadius) {
+		return fmt.Errorf("%w: radius must be between 0 and %d metres", ErrInvalidCircle, MaxRadius)
+	}
+	return nil
+}
+
+// BoundingBox returns the smallest longitude/latitude box holding the circle. ok is false when
+// the box would cross the antimeridian or a pole, in which case there is no such simple box.
+func (c *Circle) BoundingBox() (minLon, minLat, maxLon, maxLat float64, ok bool) {
+	dLat := c.Radius / EarthRadius * 180 / math.Pi
+	minLat, maxLat = c.Lat-dLat, c.Lat+dLat
+	if minLat < -90 || maxLat > 90 {
+		return 0, 0, 0, 0, false
+	}
+
+	// the widest point of the circle is nearer the pole than its centre
+	widest := math.Max(math.Abs(minLat), math.Abs(maxLat))
+	dLon := dLat / math.Cos(widest*math.Pi/180)
+	minLon, maxLon = c.Lon-dLon, c.Lon+dLon
+	if minLon < -180 || maxLon > 180 {
+		return 0, 0, 0, 0, false
+	}
+
+	return minLon, minLat, maxLon, maxLat, true
+}
+
+// Ring returns a closed ring of n vertices around the circle, circumscribed so that the circle
+// lies entirely inside it. ok is false when the circle crosses the antimeridian or a pole.
+func (c *Circle) Ring(n int) (ring Ring, ok bool) {
+	if _, _, _, _, ok := c.BoundingBox(); !ok {
+		return nil, false
+	}
+
+	// distance to the vertices as an angle, enlarged so that the edges touch the circle
+	d := c.Radius / EarthRadius / math.Cos(math.Pi/float64(n))
+	lat1, lon1 := c.Lat*math.Pi/180, c.Lon*math.Pi/180
+
+	ring = make(Ring, n+1)
+	for i := 0; i < n; i++ {
+		bearing := 2 * math.Pi * float64(i) / float64(n)
+		lat2 := math.Asin(math.Sin(lat1)*math.Cos(d) + math.Cos(lat1)*math.Sin(d)*math.Cos(bearing))
+		lon2 := lon1 + math.Atan2(math.Sin(bearing)*math.Sin(d)*math.Cos(lat1), math.Cos(d)-math.Sin(lat1)*math.Sin(lat2))
+		ring[i] = [2]float64{lon2 * 180 / math.Pi, lat2 * 180 / math.Pi}
+		if !ValidLatLon(ring[i][1], ring[i][0]) {
+			return nil, false
+		}
+	}
+	ring[n] = ring[0]
+
+	return ring, true
+}
+
+// Polygons parses and checks the geometry and returns its polygons.
+func (g *Geometry) Polygons() ([]Polygon, error) {
+	var raw [][][][]float64
+	var err error
+	switch g.Type {
+	case GeometryPolygon:
+		var p [][][]float64
+		err = json.Unmarshal(g.Coordinates, &p)
+		raw = [][][][]float64{p}
+	case GeometryMultiPolygon:
+		err = json.Unmarshal(g.Coordinates, &raw)
+	default:
+		return nil, fmt.Errorf("%w: type must be %s or %s", ErrInvalidPolygon, GeometryPolygon, GeometryMultiPolygon)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed coordinates", ErrInvalidPolygon)
+	}
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("%w: no polygons", ErrInvalidPolygon)
+	}
+
+	vertices := 0
+	polygons := make([]Polygon, len(raw))
+	for i, p := range raw {
+		if len(p) == 0 {
+			return nil, fmt.Errorf("%w: polygon without rings", ErrInvalidPolygon)
+		}
+		polygons[i] = make(Polygon, len(p))
+		for j, r := range p {
+			vertices += len(r)
+			if vertices > MaxVertices {
+				return nil, fmt.Errorf("%w: more than %d positions", ErrInvalidPolygon, MaxVertices)
+			}
+			ring, err := parseRing(r)
+			if err != nil {
+				return nil, err
+			}
+			polygons[i][j] = ring
+		}
+	}
+
+	return polygons, nil
+}
+
+func parseRing(positions [][]float64) (Ring, error) {
+	if len(positions) < 4 {
+		return nil, fmt.Errorf("%w: ring needs at least 4 positions", ErrInvalidPolygon)
+	}
+
+	ring := make(Ring, len(positions))
+	for i, pos := range positions {
+		// a third element, the altitude, is allowed and ignored
+		if len(pos) < 2 || len(pos) > 3 || !ValidLatLon(pos[1], pos[0]) {
+			return nil, fmt.Errorf("%w: invalid position %v", ErrInvalidPolygon, pos)
+		}
+		ring[i] = [2]float64{pos[0], pos[1]}
+	}
+	if ring[0] != ring[len(ring)-1] {
+		return nil, fmt.Errorf("%w: ring is not closed", ErrInvalidPolygon)
+	}
+
+	return ring, nil
+}
+
+// String formats the ring as a Postgres polygon literal with longitude as x and latitude as y.
+func (r Ring) String() string {
+	var b strings.Builder
+	b.WriteByte('(')
+	// Postgres polygons are implicitly closed, the repeated last position is left out
+	for i, pos := range r[:len(r)-1] {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('(')
+		b.WriteString(strconv.FormatFloat(pos[0], 'f', -1, 64))
+		b.WriteByte(',')
+		b.WriteString(strconv.FormatFloat(pos[1], 'f', -1, 64))
+		b.WriteByte(')')
+	}
+	b.WriteByte(')')
+	return b.String()
+}
@@ -0,0 +1,86 @@
+package geo_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/geo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArea_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		area    string
+		wantErr error
+	}{
+		{name: "circle", area: `{"circle":{"lat":55.75,"lon":37.62,"radius":5000}}`},
+		{name: "polygon", area: `{"polygon":{"type":"Polygon","coordinates":[[[37,55],[38,55],[38,56],[37,55]]]}}`},
+		{name: "polygon with hole and altitude", area: `{"polygon":{"type":"Polygon","coordinates":[[[0,0,10],[4,0,10],[4,4,10],[0,4,10],[0,0,10]],[[1,1],[2,1],[2,2],[1,1]]]}}`},
+		{name: "multipolygon", area: `{"polygon":{"type":"MultiPolygon","coordinates":[[[[0,0],[1,0],[1,1],[0,0]]],[[[5,5],[6,5],[6,6],[5,5]]]]}}`},
+		{name: "empty", area: `{}`, wantErr: geo.ErrAmbiguousArea},
+		{name: "both", area: `{"circle":{"lat":1,"lon":1,"radius":1},"polygon":{"type":"Polygon","coordinates":[]}}`, wantErr: geo.ErrAmbiguousArea},
+		{name: "zero radius", area: `{"circle":{"lat":55,"lon":37,"radius":0}}`, wantErr: geo.ErrInvalidCircle},
+		{name: "huge radius", area: `{"circle":{"lat":55,"lon":37,"radius":2000000}}`, wantErr: geo.ErrInvalidCircle},
+		{name: "latitude out of range", area: `{"circle":{"lat":91,"lon":37,"radius":10}}`, wantErr: geo.ErrInvalidCircle},
+		{name: "point geometry", area: `{"polygon":{"type":"Point","coordinates":[37,55]}}`, wantErr: geo.ErrInvalidPolygon},
+		{name: "open ring", area: `{"polygon":{"type":"Polygon","coordinates":[[[37,55],[38,55],[38,56],[37,56]]]}}`, wantErr: geo.ErrInvalidPolygon},
+		{name: "short ring", area: `{"polygon":{"type":"Polygon","coordinates":[[[37,55],[38,55],[37,55]]]}}`, wantErr: geo.ErrInvalidPolygon},
+		{name: "swapped coordinates", area: `{"polygon":{"type":"Polygon","coordinates":[[[55,137],[55,138],[56,138],[55,137]]]}}`, wantErr: geo.ErrInvalidPolygon},
+		{name: "malformed coordinates", area: `{"polygon":{"type":"Polygon","coordinates":[37,55]}}`, wantErr: geo.ErrInvalidPolygon},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var area geo.Area
+			require.NoError(t, json.Unmarshal([]byte(tc.area), &area))
+
+			err := area.Validate()
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCircle_BoundingBox(t *testing.T) {
+	c := geo.Circle{Lat: 60, Lon: 30, Radius: 111195}
+
+	minLon, minLat, maxLon, maxLat, ok := c.BoundingBox()
+	require.True(t, ok)
+	assert.InDelta(t, 59, minLat, 0.001)
+	assert.InDelta(t, 61, maxLat, 0.001)
+	// one degree of latitude is about two degrees of longitude at 60°N, a bit more at 61°N
+	assert.InDelta(t, 27.94, minLon, 0.01)
+	assert.InDelta(t, 32.06, maxLon, 0.01)
+
+	_, _, _, _, ok = (&geo.Circle{Lat: 89.9, Lon: 0, Radius: 50000}).BoundingBox()
+	assert.False(t, ok)
+	_, _, _, _, ok = (&geo.Circle{Lat: 0, Lon: 179.9, Radius: 50000}).BoundingBox()
+	assert.False(t, ok)
+}
+
+func TestCircle_Ring(t *testing.T) {
+	c := geo.Circle{Lat: 55.75, Lon: 37.62, Radius: 10000}
+
+	ring, ok := c.Ring(32)
+	require.True(t, ok)
+	require.Len(t, ring, 33)
+	assert.Equal(t, ring[0], ring[32])
+	// the first vertex is due north, a bit farther than the radius
+	assert.InDelta(t, 37.62, ring[0][0], 1e-9)
+	assert.InDelta(t, 55.75+10000/geo.EarthRadius*180/math.Pi, ring[0][1], 0.001)
+	assert.Greater(t, ring[0][1], 55.75+10000/geo.EarthRadius*180/math.Pi)
+
+	_, ok = (&geo.Circle{Lat: 0, Lon: 179.99, Radius: 5000}).Ring(32)
+	assert.False(t, ok)
+}
+
+func TestRing_String(t *testing.T) {
+	ring := geo.Ring{{37.5, 55}, {38, 55}, {38, 56.25}, {37.5, 55}}
+	assert.Equal(t, "((37.5,55),(38,55),(38,56.25))", ring.String())
+}
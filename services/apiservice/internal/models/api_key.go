@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// APIKey is a long-lived credential that lets machines, such as alerting systems, act on behalf
+// of a user. Only the SHA-256 hash of the key is stored; Prefix, the start of the key, lets the
+// user tell keys apart.
+type APIKey struct {
+	ID           int        `json:"id"`
+	UserID       int        `json:"userId"`
+	Name         string     `json:"name"`
+	Prefix       string     `json:"prefix"`
+	Hash         []byte     `json:"-"`
+	CreationTime time.Time  `json:"creationTime"`
+	LastUseTime  *time.Time `json:"lastUseTime"`
+}
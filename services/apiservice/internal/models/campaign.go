@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Campaign records a campaign sent from a template, along with the exact version of the
+// template it used and its text, so that what was sent can be told after the template changes.
+type Campaign struct {
+	ID              uuid.UUID `json:"id"`
+	UserID          int       `json:"userId"`
+	TemplateID      int       `json:"templateId"`
+	TemplateVersion int       `json:"templateVersion"`
+	Body            string    `json:"body"`
+	CreationTime    time.Time `json:"creationTime"`
+}
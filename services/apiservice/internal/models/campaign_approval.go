@@ -0,0 +1,113 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ApprovalPolicy makes campaigns of the user wait for the approval of another user before they
+// are sent: campaigns to more than RecipientThreshold contacts, unless it is zero, and critical
+// campaigns if RequireForCritical is set. Any of the Approvers, given by e-mail, can approve or
+// reject the campaign within ApprovalWindowMinutes.
+type ApprovalPolicy struct {
+	RecipientThreshold    int      `json:"recipientThreshold"`
+	RequireForCritical    bool     `json:"requireForCritical"`
+	ApprovalWindowMinutes int      `json:"approvalWindowMinutes"`
+	Approvers             []string `json:"approvers"`
+	ApproverIDs           []int    `json:"-"`
+}
+
+// ApprovalPolicyChange is a change that relaxes or deletes an approval policy, so that the
+// owner of the policy can't opt out of two-person approval alone. It takes effect only once one
+// of the approvers of the current policy, ApproverIDs, approves it before ExpiresAt. Policy is
+// the requested policy, or nil if the change deletes the policy. Changes reuse the statuses of
+// campaign approvals, except for failed.
+type ApprovalPolicyChange struct {
+	ID             int             `json:"id"`
+	UserID         int             `json:"userId"`
+	RequesterEmail string          `json:"requesterEmail"`
+	Policy         *ApprovalPolicy `json:"policy"`
+	Status         ApprovalStatus  `json:"status"`
+	ApproverIDs    []int           `json:"-"`
+	DeciderEmail   string          `json:"deciderEmail,omitempty"`
+	Comment        string          `json:"comment,omitempty"`
+	ExpiresAt      time.Time       `json:"expiresAt"`
+	DecisionTime   *time.Time      `json:"decisionTime"`
+	CreationTime   time.Time       `json:"creationTime"`
+}
+
+// ApprovalReason tells why a campaign needs approval.
+type ApprovalReason string
+
+const (
+	// ApprovalReasonRecipients is used for campaigns to more contacts than the threshold of the policy.
+	ApprovalReasonRecipients ApprovalReason = "recipients"
+	// ApprovalReasonCritical is used for critical campaigns when the policy requires it.
+	ApprovalReasonCritical ApprovalReason = "critical"
+)
+
+// ApprovalStatus is the state of a campaign approval.
+type ApprovalStatus string
+
+const (
+	// ApprovalPending is the status of campaigns waiting for a decision.
+	ApprovalPending ApprovalStatus = "pending"
+	// ApprovalApproved is the status of approved campaigns, which have been sent.
+	ApprovalApproved ApprovalStatus = "approved"
+	// ApprovalRejected is the status of rejected campaigns.
+	ApprovalRejected ApprovalStatus = "rejected"
+	// ApprovalExpired is the status of campaigns not decided on before their deadline.
+	ApprovalExpired ApprovalStatus = "expired"
+	// ApprovalFailed is the status of approved campaigns that could not be sent.
+	ApprovalFailed ApprovalStatus = "failed"
+)
+
+// ApprovalAction is an entry of the audit trail of a campaign approval.
+type ApprovalAction string
+
+const (
+	// ApprovalActionRequested records that the campaign was submitted for approval.
+	ApprovalActionRequested ApprovalAction = "requested"
+	// ApprovalActionApproved records that an approver approved the campaign.
+	ApprovalActionApproved ApprovalAction = "approved"
+	// ApprovalActionRejected records that an approver rejected the campaign.
+	ApprovalActionRejected ApprovalAction = "rejected"
+	// ApprovalActionExpired records that the deadline passed without a decision.
+	ApprovalActionExpired ApprovalAction = "expired"
+	// ApprovalActionFailed records that sending the approved campaign failed.
+	ApprovalActionFailed ApprovalAction = "failed"
+)
+
+// CampaignApproval is a campaign waiting for, or given, the decision of an approver.
+// Message is the body of the template when approval was requested, and Options the campaign
+// options the campaign is sent with once approved. ApproverIDs are the users allowed to decide.
+// Messages sent without a template, such as CAP alerts, have no TemplateID but the CampaignID
+// they are sent as.
+type CampaignApproval struct {
+	ID             int              `json:"id"`
+	UserID         int              `json:"userId"`
+	RequesterEmail string           `json:"requesterEmail"`
+	TemplateID     int              `json:"templateId,omitempty"`
+	CampaignID     *uuid.UUID       `json:"campaignId,omitempty"`
+	Message        string           `json:"message"`
+	Options        json.RawMessage  `json:"options"`
+	Recipients     int              `json:"recipients"`
+	Reason         ApprovalReason   `json:"reason"`
+	Status         ApprovalStatus   `json:"status"`
+	ApproverIDs    []int            `json:"-"`
+	ExpiresAt      time.Time        `json:"expiresAt"`
+	DecisionTime   *time.Time       `json:"decisionTime"`
+	CreationTime   time.Time        `json:"creationTime"`
+	Events         []*ApprovalEvent `json:"events,omitempty"`
+}
+
+// ApprovalEvent is an entry of the audit trail of a campaign approval. ActorEmail is empty for
+// events not caused by a user, such as expiry.
+type ApprovalEvent struct {
+	Action       ApprovalAction `json:"action"`
+	ActorEmail   string         `json:"actorEmail,omitempty"`
+	Comment      string         `json:"comment,omitempty"`
+	CreationTime time.Time      `json:"creationTime"`
+}
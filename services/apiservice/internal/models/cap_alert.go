@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CAPAlert records a Common Alerting Protocol message received from an alerting system, so that
+// later updates and cancellations referring to it by sender and identifier find the campaign it
+// launched. CampaignID is nil for cancellations; CancelTime is set once the campaign was
+// cancelled or superseded by an update.
+type CAPAlert struct {
+	ID           int        `json:"id"`
+	UserID       int        `json:"userId"`
+	Sender       string     `json:"sender"`
+	Identifier   string     `json:"identifier"`
+	Sent         string     `json:"sent"`
+	MsgType      string     `json:"msgType"`
+	CampaignID   *uuid.UUID `json:"campaignId"`
+	CancelTime   *time.Time `json:"cancelTime"`
+	CreationTime time.Time  `json:"creationTime"`
+}
@@ -2,21 +2,61 @@ package models
 
 import "time"
 
+// GroupAttribute is the contact attribute holding the group a contact belongs to.
+const GroupAttribute = "Group"
+
+// Channel is the way a message reaches a contact endpoint.
+type Channel string
+
+const (
+	// ChannelSMS delivers text messages to a phone number.
+	ChannelSMS Channel = "sms"
+	// ChannelEmail delivers e-mails to an address.
+	ChannelEmail Channel = "email"
+)
+
+// ContactEndpoint is a further phone number or e-mail address of a contact, such as a
+// personal mobile next to the work one. Label names it for the user, e.g. "personal".
+type ContactEndpoint struct {
+	Channel Channel `json:"channel"`
+	Address string  `json:"address"`
+	Label   string  `json:"label,omitempty"`
+}
+
 // Contact represents a user's contact information stored in the system.
+// Attributes holds free-form fields, such as the extra columns of an imported spreadsheet.
+// Phone is the primary endpoint of the contact; Endpoints lists the others in order of
+// preference, each tried when delivery to the previous one fails permanently.
+// Location is optional and used to target notifications at an area.
 type Contact struct {
-	ID           int       `json:"id"`
-	UserID       int       `json:"userId"`
-	Name         string    `json:"name"`
-	Phone        string    `json:"phone"`
-	CreationTime time.Time `json:"creationTime"`
-	UpdateTime   time.Time `json:"updateTime"`
+	ID           int                `json:"id"`
+	UserID       int                `json:"userId"`
+	Name         string             `json:"name"`
+	Phone        string             `json:"phone"`
+	TimeZone     string             `json:"timeZone"`
+	Attributes   map[string]string  `json:"attributes,omitempty"`
+	Endpoints    []*ContactEndpoint `json:"endpoints,omitempty"`
+	Location     *Location          `json:"location,omitempty"`
+	CreationTime time.Time          `json:"creationTime"`
+	UpdateTime   time.Time          `json:"updateTime"`
+}
+
+// Fallback is an endpoint a notification falls back to; the label stays with the contact.
+type Fallback struct {
+	Channel Channel `json:"channel"`
+	Address string  `json:"address"`
 }
 
-// SlimContact contains only the minimal fields (Name and Phone)
+// SlimContact contains only the minimal fields (Name, Phone and TimeZone)
 // needed when sending contact data to other services or clients.
+// Text is the message rendered for the contact from a template with placeholders.
+// Fallbacks are the further endpoints of the contact in order of preference.
 type SlimContact struct {
-	Name  string `json:"name"`
-	Phone string `json:"phone"`
+	Name      string      `json:"name"`
+	Phone     string      `json:"phone"`
+	TimeZone  string      `json:"timeZone,omitempty"`
+	Text      string      `json:"text,omitempty"`
+	Fallbacks []*Fallback `json:"fallbacks,omitempty"`
 }
 
 // ToSlim transforms a slice of full Contact pointers into a slice
@@ -25,8 +65,12 @@ func ToSlim(contacts []*Contact) []*SlimContact {
 	slim := make([]*SlimContact, len(contacts))
 	for i, c := range contacts {
 		slim[i] = &SlimContact{
-			Name:  c.Name,
-			Phone: c.Phone,
+			Name:     c.Name,
+			Phone:    c.Phone,
+			TimeZone: c.TimeZone,
+		}
+		for _, e := range c.Endpoints {
+			slim[i].Fallbacks = append(slim[i].Fallbacks, &Fallback{Channel: e.Channel, Address: e.Address})
 		}
 	}
 	return slim
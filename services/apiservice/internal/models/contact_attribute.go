@@ -0,0 +1,89 @@
+package models
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AttributeType is the type of the values of a contact attribute.
+type AttributeType string
+
+const (
+	// AttributeText accepts any value.
+	AttributeText AttributeType = "text"
+	// AttributeNumber accepts decimal numbers, with a point or a comma.
+	AttributeNumber AttributeType = "number"
+	// AttributeBoolean accepts true/false, yes/no, да/нет and 1/0.
+	AttributeBoolean AttributeType = "boolean"
+	// AttributeDate accepts dates as 2006-01-02 or 02.01.2006.
+	AttributeDate AttributeType = "date"
+	// AttributeEnum accepts one of the options of the attribute, case-insensitively.
+	AttributeEnum AttributeType = "enum"
+)
+
+// dateLayouts lists the accepted date formats; values are stored in the first one.
+var dateLayouts = []string{"2006-01-02", "02.01.2006"}
+
+// ContactAttribute defines a typed attribute of the user's contacts, such as a department
+// or an employee ID. Values of contacts' attributes with the same name, compared
+// case-insensitively, must match its type; Options lists the values of enum attributes.
+// Required attributes must be set on every contact created or updated.
+type ContactAttribute struct {
+	ID           int           `json:"id"`
+	UserID       int           `json:"userId"`
+	Name         string        `json:"name"`
+	Type         AttributeType `json:"type"`
+	Options      []string      `json:"options,omitempty"`
+	Required     bool          `json:"required"`
+	CreationTime time.Time     `json:"creationTime"`
+	UpdateTime   time.Time     `json:"updateTime"`
+}
+
+// Normalize checks the value against the attribute type and returns it in its canonical
+// form: numbers without insignificant zeros, booleans as true or false, dates as
+// 2006-01-02 and enum values spelled as the option. It reports false for invalid values.
+func (a *ContactAttribute) Normalize(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+
+	switch a.Type {
+	case AttributeText:
+		return value, true
+
+	case AttributeNumber:
+		f, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+		if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+			return "", false
+		}
+		return strconv.FormatFloat(f, 'f', -1, 64), true
+
+	case AttributeBoolean:
+		switch strings.ToLower(value) {
+		case "true", "yes", "да", "1":
+			return "true", true
+		case "false", "no", "нет", "0":
+			return "false", true
+		}
+		return "", false
+
+	case AttributeDate:
+		for _, layout := range dateLayouts {
+			t, err := time.Parse(layout, value)
+			if err == nil {
+				return t.Format(dateLayouts[0]), true
+			}
+		}
+		return "", false
+
+	case AttributeEnum:
+		for _, option := range a.Options {
+			if strings.EqualFold(option, value) {
+				return option, true
+			}
+		}
+		return "", false
+	}
+
+	return "", false
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// ExportFormat is the file format of a contacts export.
+type ExportFormat string
+
+const (
+	// ExportFormatCSV writes contacts as a UTF-8 CSV file.
+	ExportFormatCSV ExportFormat = "csv"
+	// ExportFormatXLSX writes contacts as an Excel workbook.
+	ExportFormatXLSX ExportFormat = "xlsx"
+)
+
+// ContentType returns the MIME type of files in the format.
+func (f ExportFormat) ContentType() string {
+	if f == ExportFormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv"
+}
+
+// ExportStatus describes the lifecycle stage of an asynchronous contacts export.
+type ExportStatus string
+
+const (
+	// ExportStatusQueued means the export is created and waits to be written.
+	ExportStatusQueued ExportStatus = "queued"
+	// ExportStatusProcessing means the contacts are being written to S3.
+	ExportStatusProcessing ExportStatus = "processing"
+	// ExportStatusDone means the file is ready to be downloaded.
+	ExportStatusDone ExportStatus = "done"
+	// ExportStatusFailed means the file could not be written; see Error for the reason.
+	ExportStatusFailed ExportStatus = "failed"
+)
+
+// ContactExport tracks an asynchronous contacts export. Once it is done, DownloadURL
+// is a presigned link to the exported file in S3.
+type ContactExport struct {
+	ID           int          `json:"id"`
+	UserID       int          `json:"userId"`
+	Format       ExportFormat `json:"format"`
+	Status       ExportStatus `json:"status"`
+	RowsWritten  int          `json:"rowsWritten"`
+	S3Key        string       `json:"-"`
+	DownloadURL  string       `json:"downloadUrl,omitempty"`
+	Error        string       `json:"error,omitempty"`
+	CreationTime time.Time    `json:"creationTime"`
+	UpdateTime   time.Time    `json:"updateTime"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// ImportStatus describes the lifecycle stage of a contact import job.
+type ImportStatus string
+
+const (
+	// ImportStatusQueued means the file is uploaded and waits for the contacts worker.
+	ImportStatusQueued ImportStatus = "queued"
+	// ImportStatusProcessing means the contacts worker is reading the file.
+	ImportStatusProcessing ImportStatus = "processing"
+	// ImportStatusDone means every row of the file was handled.
+	ImportStatusDone ImportStatus = "done"
+	// ImportStatusFailed means the file could not be processed; see Error for the reason.
+	ImportStatusFailed ImportStatus = "failed"
+)
+
+// ImportMode decides what happens to contacts that already exist or are missing from the file.
+type ImportMode string
+
+const (
+	// ImportModeInsertOnly adds new contacts and leaves existing ones untouched.
+	ImportModeInsertOnly ImportMode = "insert-only"
+	// ImportModeUpsert adds new contacts and updates the name and attributes of existing ones.
+	ImportModeUpsert ImportMode = "upsert"
+	// ImportModeSync upserts the contacts of the file and deletes the user's contacts missing from it.
+	ImportModeSync ImportMode = "sync"
+)
+
+// ContactImport tracks a single contacts file upload and the outcome of its processing.
+// RowsRead counts every data row of the file; each of them is either inserted, updated,
+// skipped as a duplicate of an existing contact, or rejected as invalid. RowsDeleted counts
+// the contacts a sync removed. A dry run only counts the changes the import would make.
+type ContactImport struct {
+	ID            int          `json:"id"`
+	UserID        int          `json:"userId"`
+	Filename      string       `json:"filename"`
+	S3Key         string       `json:"-"`
+	Mode          ImportMode   `json:"mode"`
+	DryRun        bool         `json:"dryRun"`
+	Status        ImportStatus `json:"status"`
+	RowsRead      int          `json:"rowsRead"`
+	RowsInserted  int          `json:"rowsInserted"`
+	RowsUpdated   int          `json:"rowsUpdated"`
+	RowsDuplicate int          `json:"rowsDuplicate"`
+	RowsInvalid   int          `json:"rowsInvalid"`
+	RowsDeleted   int          `json:"rowsDeleted"`
+	ReportKey     string       `json:"-"`
+	ReportURL     string       `json:"reportUrl,omitempty"`
+	DeletionsKey  string       `json:"-"`
+	DeletionsURL  string       `json:"deletionsUrl,omitempty"`
+	Error         string       `json:"error,omitempty"`
+	CreationTime  time.Time    `json:"creationTime"`
+	UpdateTime    time.Time    `json:"updateTime"`
+}
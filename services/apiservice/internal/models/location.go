@@ -0,0 +1,29 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Location is the position of a contact in degrees, entered by the user or geocoded from the
+// contact's address beforehand. It is stored as a Postgres point with the longitude as x and
+// the latitude as y.
+type Location struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// ScanPoint implements pgtype.PointScanner.
+func (l *Location) ScanPoint(v pgtype.Point) error {
+	if !v.Valid {
+		return fmt.Errorf("cannot scan NULL into *Location")
+	}
+	l.Lon, l.Lat = v.P.X, v.P.Y
+	return nil
+}
+
+// PointValue implements pgtype.PointValuer.
+func (l Location) PointValue() (pgtype.Point, error) {
+	return pgtype.Point{P: pgtype.Vec2{X: l.Lon, Y: l.Lat}, Valid: true}, nil
+}
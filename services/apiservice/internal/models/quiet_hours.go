@@ -0,0 +1,21 @@
+package models
+
+// Priority classifies a campaign by urgency.
+type Priority string
+
+const (
+	// PriorityCritical is used for emergency alerts. They are delivered immediately and ignore quiet hours.
+	PriorityCritical Priority = "critical"
+	// PriorityNormal is used for informational sends. They are deferred while the recipient is in quiet hours.
+	PriorityNormal Priority = "normal"
+)
+
+// QuietHours is a daily window, in the recipient's local time, during which non-critical
+// notifications are not delivered. StartMinute and EndMinute are minutes since local midnight;
+// a window with StartMinute > EndMinute wraps over midnight (e.g. 22:00-07:00).
+// TimeZone is used for recipients whose own time zone is unknown.
+type QuietHours struct {
+	StartMinute int    `json:"startMinute"`
+	EndMinute   int    `json:"endMinute"`
+	TimeZone    string `json:"timeZone"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import "fmt"
+
+// BackoffKind names the curve used to grow the delay between delivery attempts.
+type BackoffKind string
+
+const (
+	// BackoffExponential doubles the delay after every attempt: base * 2^(attempt-1).
+	BackoffExponential BackoffKind = "exponential"
+	// BackoffLinear grows the delay linearly: base * attempt.
+	BackoffLinear BackoffKind = "linear"
+	// BackoffConstant waits the base delay between every attempt.
+	BackoffConstant BackoffKind = "constant"
+)
+
+// RetryPolicy describes how a notification is retried after a failed delivery attempt.
+// A user may store a default policy and override it for a single campaign.
+type RetryPolicy struct {
+	MaxAttempts  int         `json:"maxAttempts"`
+	Backoff      BackoffKind `json:"backoff"`
+	BaseDelayMs  int         `json:"baseDelayMs"`
+	MaxDelayMs   int         `json:"maxDelayMs"`
+	Jitter       float64     `json:"jitter"`
+	StaleAfterMs int         `json:"staleAfterMs"`
+}
+
+// Bounds of the retry policies accepted from users and from the configuration.
+const (
+	RetryPolicyMaxAttemptsLimit = 50
+	RetryPolicyMinBaseDelayMs   = 100
+	RetryPolicyMaxDelayMsLimit  = 24 * 60 * 60 * 1000
+	RetryPolicyMinStaleAfterMs  = 60 * 1000
+)
+
+// Validate reports why the policy is out of the allowed bounds or has an unknown backoff, if it is.
+func (p RetryPolicy) Validate() error {
+	switch p.Backoff {
+	case BackoffExponential, BackoffLinear, BackoffConstant:
+	default:
+		return fmt.Errorf("unknown backoff %q", p.Backoff)
+	}
+
+	if p.MaxAttempts < 1 || p.MaxAttempts > RetryPolicyMaxAttemptsLimit {
+		return fmt.Errorf("max attempts must be between 1 and %d", RetryPolicyMaxAttemptsLimit)
+	}
+	if p.BaseDelayMs < RetryPolicyMinBaseDelayMs {
+		return fmt.Errorf("base delay must be at least %d ms", RetryPolicyMinBaseDelayMs)
+	}
+	if p.MaxDelayMs < p.BaseDelayMs || p.MaxDelayMs > RetryPolicyMaxDelayMsLimit {
+		return fmt.Errorf("max delay must be between the base delay and %d ms", RetryPolicyMaxDelayMsLimit)
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return fmt.Errorf("jitter must be between 0 and 1")
+	}
+	if p.StaleAfterMs < RetryPolicyMinStaleAfterMs {
+		return fmt.Errorf("stale-after must be at least %d ms", RetryPolicyMinStaleAfterMs)
+	}
+
+	return nil
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Segment is a saved selection of the user's contacts defined by a rule over their name,
+// phone and attributes, such as `building = "B" AND shift = night`. The rule is evaluated
+// whenever the segment is used, so the segment follows changes to the contacts.
+type Segment struct {
+	ID           int       `json:"id"`
+	UserID       int       `json:"userId"`
+	Name         string    `json:"name"`
+	Rule         string    `json:"rule"`
+	CreationTime time.Time `json:"creationTime"`
+	UpdateTime   time.Time `json:"updateTime"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// SMSPricing is the price of sending SMS, used to estimate the cost of campaigns.
+// SegmentPrice is charged for every segment sent, in Currency.
+type SMSPricing struct {
+	SegmentPrice float64 `json:"segmentPrice"`
+	Currency     string  `json:"currency"`
+}
@@ -1,13 +1,34 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/smsutils"
+)
 
 // Template represents a message template created by a user.
+// SenderID is an optional branded alphanumeric sender ID used in countries that allow it.
+// Version is the number of the current version of the template, which grows with every update.
+// Segmentation tells how the body is sent as SMS; placeholders are counted as written.
 type Template struct {
 	ID           int       `json:"id"`
 	UserID       int       `json:"userId"`
 	Name         string    `json:"name"`
 	Body         string    `json:"body"`
+	SenderID     string    `json:"senderId"`
+	Version      int       `json:"version"`
 	CreationTime time.Time `json:"creationTime"`
 	UpdateTime   time.Time `json:"updateTime"`
+	smsutils.Segmentation
+}
+
+// TemplateVersion is the immutable content of a template as of one of its versions.
+// Versions are kept after their template is deleted, as campaigns refer to them.
+type TemplateVersion struct {
+	TemplateID   int       `json:"templateId"`
+	Version      int       `json:"version"`
+	Name         string    `json:"name"`
+	Body         string    `json:"body"`
+	SenderID     string    `json:"senderId"`
+	CreationTime time.Time `json:"creationTime"`
 }
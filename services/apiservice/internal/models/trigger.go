@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// MatchType is the operator of a label matcher, as in Prometheus and Alertmanager.
+type MatchType string
+
+const (
+	// MatchEqual selects labels exactly equal to the value.
+	MatchEqual MatchType = "="
+	// MatchNotEqual selects labels not equal to the value.
+	MatchNotEqual MatchType = "!="
+	// MatchRegexp selects labels fully matching the regular expression.
+	MatchRegexp MatchType = "=~"
+	// MatchNotRegexp selects labels not fully matching the regular expression.
+	MatchNotRegexp MatchType = "!~"
+)
+
+// LabelMatcher matches the label Name of an alert against Value; a missing label is matched as empty.
+type LabelMatcher struct {
+	Name  string    `json:"name"`
+	Type  MatchType `json:"type"`
+	Value string    `json:"value"`
+}
+
+// Trigger is a rule sending a notification when an alert matching all of its matchers fires.
+// The template is sent with the priority to the contacts of Group, or to all contacts if it is empty.
+// ResolvedTemplateID, if set, is sent the same way once the alert resolves.
+type Trigger struct {
+	ID                 int            `json:"id"`
+	UserID             int            `json:"userId"`
+	Name               string         `json:"name"`
+	Matchers           []LabelMatcher `json:"matchers"`
+	TemplateID         int            `json:"templateId"`
+	ResolvedTemplateID *int           `json:"resolvedTemplateId"`
+	Group              string         `json:"group"`
+	Priority           Priority       `json:"priority"`
+	CreationTime       time.Time      `json:"creationTime"`
+	UpdateTime         time.Time      `json:"updateTime"`
+}
@@ -4,8 +4,10 @@ import "time"
 
 // User represents a registered user in the system.
 type User struct {
-	ID           int       `json:"id"`
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"-"`
-	CreationTime time.Time `json:"creationTime"`
+	ID           int    `json:"id"`
+	Email        string `json:"email"`
+	PasswordHash string `json:"-"`
+	// DefaultRegion is the ISO 3166-1 alpha-2 code used to parse phone numbers written without a country code.
+	DefaultRegion string    `json:"defaultRegion"`
+	CreationTime  time.Time `json:"creationTime"`
 }
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/geo"
+)
+
+// Zone is a named area saved by the user, such as a district or the surroundings of a plant,
+// that notifications can be targeted at by its ID.
+type Zone struct {
+	ID           int       `json:"id"`
+	UserID       int       `json:"userId"`
+	Name         string    `json:"name"`
+	Area         geo.Area  `json:"area"`
+	CreationTime time.Time `json:"creationTime"`
+	UpdateTime   time.Time `json:"updateTime"`
+}
@@ -2,6 +2,7 @@ package phoneutils
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/nyaruka/phonenumbers"
 )
@@ -15,6 +16,16 @@ const (
 	RegionRU ISO3166Alpha2 = "RU"
 )
 
+// ParseRegion normalizes a region code such as "de" or " US " and reports whether
+// it is a region known to the phone number metadata.
+func ParseRegion(region string) (ISO3166Alpha2, bool) {
+	code := strings.ToUpper(strings.TrimSpace(region))
+	if !phonenumbers.GetSupportedRegions()[code] {
+		return "", false
+	}
+	return ISO3166Alpha2(code), true
+}
+
 // FormatToE164 parses a raw phone number string using the provided default region,
 // validates it, and returns the number formatted in E.164 standard (e.g. +1234567890).
 // Returns the formatted E.164 string on success, or an error if parsing or validation fails.
@@ -29,3 +40,21 @@ func FormatToE164(rawPhoneNumber string, defaultRegion ISO3166Alpha2) (string, e
 
 	return phonenumbers.Format(num, phonenumbers.E164), nil
 }
+
+// TimeZoneForNumber returns the IANA time zone of an E.164 phone number using the libphonenumber
+// geocoding data. The second return value is false if the zone is unknown or the number prefix
+// spans several time zones (e.g. Russian mobile numbers), in which case the caller should fall back
+// to a zone configured elsewhere.
+func TimeZoneForNumber(e164 string) (string, bool) {
+	num, err := phonenumbers.Parse(e164, "")
+	if err != nil {
+		return "", false
+	}
+
+	tzs, err := phonenumbers.GetTimezonesForNumber(num)
+	if err != nil || len(tzs) != 1 || tzs[0] == phonenumbers.UNKNOWN_TIMEZONE {
+		return "", false
+	}
+
+	return tzs[0], true
+}
@@ -7,6 +7,29 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+func TestParseRegion(t *testing.T) {
+	tests := []struct {
+		name   string
+		region string
+		want   phoneutils.ISO3166Alpha2
+		wantOK bool
+	}{
+		{name: "Upper case", region: "DE", want: "DE", wantOK: true},
+		{name: "Lower case with spaces", region: " us ", want: "US", wantOK: true},
+		{name: "Empty", region: "", wantOK: false},
+		{name: "Unknown region", region: "XX", wantOK: false},
+		{name: "Alpha-3 code", region: "RUS", wantOK: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := phoneutils.ParseRegion(tc.region)
+			assert.Equal(t, tc.wantOK, ok)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
 func TestFormatToE164(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -29,6 +52,19 @@ func TestFormatToE164(t *testing.T) {
 			want:      "+79123456789",
 			expectErr: false,
 		},
+		{
+			name:      "Valid US local number with US region",
+			raw:       "(202) 555-0123",
+			region:    "US",
+			want:      "+12025550123",
+			expectErr: false,
+		},
+		{
+			name:      "Russian local number with US region",
+			raw:       "8 (912) 345-6789",
+			region:    "US",
+			expectErr: true,
+		},
 		{
 			name:      "Invalid too short",
 			raw:       "12345",
@@ -62,3 +98,43 @@ func TestFormatToE164(t *testing.T) {
 		})
 	}
 }
+
+func TestTimeZoneForNumber(t *testing.T) {
+	tests := []struct {
+		name   string
+		e164   string
+		want   string
+		wantOK bool
+	}{
+		{
+			name:   "Moscow landline",
+			e164:   "+74951234567",
+			want:   "Europe/Moscow",
+			wantOK: true,
+		},
+		{
+			name:   "US geographic number",
+			e164:   "+12025550123",
+			want:   "America/New_York",
+			wantOK: true,
+		},
+		{
+			name:   "US toll free spans many zones",
+			e164:   "+18005550199",
+			wantOK: false,
+		},
+		{
+			name:   "Malformed number",
+			e164:   "phone123",
+			wantOK: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := phoneutils.TimeZoneForNumber(tc.e164)
+			assert.Equal(t, tc.wantOK, ok)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
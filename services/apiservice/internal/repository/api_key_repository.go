@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// APIKeyRepository handles operations on the api_keys table.
+type APIKeyRepository struct {
+	db domain.DBConn
+}
+
+// NewAPIKeyRepository constructs an APIKeyRepository using the provided DB connection.
+func NewAPIKeyRepository(db domain.DBConn) *APIKeyRepository {
+	return &APIKeyRepository{
+		db: db,
+	}
+}
+
+// GetAPIKeysByUserID retrieves every API key of the user, newest first.
+func (akr *APIKeyRepository) GetAPIKeysByUserID(ctx context.Context, userID int) ([]*models.APIKey, error) {
+	const q = `
+		SELECT id, user_id, name, prefix, key_hash, created_at, last_used_at
+		FROM api_keys
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+	`
+
+	rows, err := akr.db.Query(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]*models.APIKey, 0)
+	for rows.Next() {
+		var k models.APIKey
+
+		err := rows.Scan(&k.ID, &k.UserID, &k.Name, &k.Prefix, &k.Hash, &k.CreationTime, &k.LastUseTime)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, &k)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// CreateAPIKey inserts a new API key and returns the created record.
+func (akr *APIKeyRepository) CreateAPIKey(ctx context.Context, key *models.APIKey) (*models.APIKey, error) {
+	const q = `
+		INSERT INTO api_keys (user_id, name, prefix, key_hash)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, user_id, name, prefix, key_hash, created_at, last_used_at
+	`
+
+	var k models.APIKey
+
+	row := akr.db.QueryRow(ctx, q, key.UserID, key.Name, key.Prefix, key.Hash)
+	err := row.Scan(&k.ID, &k.UserID, &k.Name, &k.Prefix, &k.Hash, &k.CreationTime, &k.LastUseTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return &k, nil
+}
+
+// DeleteAPIKey removes an API key by ID and user ID, revoking it.
+// Returns domain.ErrAPIKeyNotExists if no row was deleted.
+func (akr *APIKeyRepository) DeleteAPIKey(ctx context.Context, userID, keyID int) error {
+	const q = `
+		DELETE
+		FROM api_keys
+		WHERE id = $1
+		  AND user_id = $2
+	`
+
+	res, err := akr.db.Exec(ctx, q, keyID, userID)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return domain.ErrAPIKeyNotExists
+	}
+
+	return nil
+}
+
+// UseAPIKey finds the API key with the hash and sets its last use time to now.
+// Returns domain.ErrAPIKeyNotExists if there is no such key.
+func (akr *APIKeyRepository) UseAPIKey(ctx context.Context, hash []byte) (*models.APIKey, error) {
+	const q = `
+		UPDATE api_keys
+		SET last_used_at = now()
+		WHERE key_hash = $1
+		RETURNING id, user_id, name, prefix, key_hash, created_at, last_used_at
+	`
+
+	var k models.APIKey
+
+	row := akr.db.QueryRow(ctx, q, hash)
+	err := row.Scan(&k.ID, &k.UserID, &k.Name, &k.Prefix, &k.Hash, &k.CreationTime, &k.LastUseTime)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrAPIKeyNotExists
+		}
+		return nil, err
+	}
+
+	return &k, nil
+}
@@ -0,0 +1,52 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+func clearAPIKeys(t *testing.T, db *sql.DB) {
+	t.Helper()
+	_, err := db.Exec("TRUNCATE api_keys")
+	require.NoError(t, err)
+}
+
+func TestAPIKeyRepository_CRUD(t *testing.T) {
+	t.Cleanup(func() { clearAPIKeys(t, testDB) })
+
+	fixtures := makeFixtures(t, testDB, "../../../../db/fixtures/users.yml")
+	require.NoError(t, fixtures.Load())
+
+	ctx := context.Background()
+	repo := repository.NewAPIKeyRepository(testPool)
+
+	created, err := repo.CreateAPIKey(ctx, &models.APIKey{UserID: 1, Name: "Feed", Prefix: "ens_abcdefgh", Hash: []byte("hash")})
+	require.NoError(t, err)
+	require.Nil(t, created.LastUseTime)
+
+	used, err := repo.UseAPIKey(ctx, []byte("hash"))
+	require.NoError(t, err)
+	require.Equal(t, 1, used.UserID)
+	require.NotNil(t, used.LastUseTime)
+	_, err = repo.UseAPIKey(ctx, []byte("other"))
+	require.ErrorIs(t, err, domain.ErrAPIKeyNotExists)
+
+	keys, err := repo.GetAPIKeysByUserID(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Equal(t, "ens_abcdefgh", keys[0].Prefix)
+
+	require.ErrorIs(t, repo.DeleteAPIKey(ctx, 2, created.ID), domain.ErrAPIKeyNotExists)
+	require.NoError(t, repo.DeleteAPIKey(ctx, 1, created.ID))
+	_, err = repo.UseAPIKey(ctx, []byte("hash"))
+	require.ErrorIs(t, err, domain.ErrAPIKeyNotExists)
+}
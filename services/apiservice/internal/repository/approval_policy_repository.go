@@ -0,0 +1,274 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// approvalPolicyChangeColumns selects an approval policy change c joined with its requester u and
+// the approver d who decided on it. Pending changes past their deadline are reported as expired.
+const approvalPolicyChangeColumns = `
+	c.id, c.user_id, u.email, c.recipient_threshold, c.require_for_critical, c.approval_window_minutes, c.new_approver_ids,
+	ARRAY(SELECT a.email FROM users a WHERE a.id = ANY (c.new_approver_ids) ORDER BY a.email),
+	CASE WHEN c.status = 'pending' AND c.expires_at <= now() THEN 'expired' ELSE c.status END,
+	c.approver_ids, COALESCE(d.email, ''), c.comment, c.expires_at, c.decided_at, c.created_at
+`
+
+// ApprovalPolicyRepository handles operations on the approval_policies table and the changes
+// relaxing or deleting policies in the approval_policy_changes table.
+type ApprovalPolicyRepository struct {
+	db domain.DBConn
+}
+
+// NewApprovalPolicyRepository constructs an ApprovalPolicyRepository using the provided DB connection.
+func NewApprovalPolicyRepository(db domain.DBConn) *ApprovalPolicyRepository {
+	return &ApprovalPolicyRepository{
+		db: db,
+	}
+}
+
+// GetApprovalPolicyByUserID retrieves the approval policy of the user along with the e-mails of the approvers.
+// Returns domain.ErrApprovalPolicyNotExists if the user has not configured one.
+func (apr *ApprovalPolicyRepository) GetApprovalPolicyByUserID(ctx context.Context, userID int) (*models.ApprovalPolicy, error) {
+	const q = `
+		SELECT p.recipient_threshold, p.require_for_critical, p.approval_window_minutes, p.approver_ids,
+		       ARRAY(SELECT u.email FROM users u WHERE u.id = ANY (p.approver_ids) ORDER BY u.email)
+		FROM approval_policies p
+		WHERE p.user_id = $1
+	`
+
+	p, err := scanApprovalPolicy(apr.db.QueryRow(ctx, q, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrApprovalPolicyNotExists
+		}
+
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// UpsertApprovalPolicy creates or replaces the approval policy of the user and returns the stored record.
+// Approvers are stored by p.ApproverIDs.
+func (apr *ApprovalPolicyRepository) UpsertApprovalPolicy(ctx context.Context, userID int, p *models.ApprovalPolicy) (*models.ApprovalPolicy, error) {
+	const q = `
+		INSERT INTO approval_policies AS p (user_id, recipient_threshold, require_for_critical, approval_window_minutes, approver_ids)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE
+		SET recipient_threshold     = EXCLUDED.recipient_threshold,
+		    require_for_critical    = EXCLUDED.require_for_critical,
+		    approval_window_minutes = EXCLUDED.approval_window_minutes,
+		    approver_ids            = EXCLUDED.approver_ids,
+		    updated_at              = now()
+		RETURNING p.recipient_threshold, p.require_for_critical, p.approval_window_minutes, p.approver_ids,
+		          ARRAY(SELECT u.email FROM users u WHERE u.id = ANY (p.approver_ids) ORDER BY u.email)
+	`
+
+	return scanApprovalPolicy(apr.db.QueryRow(ctx, q, userID, p.RecipientThreshold, p.RequireForCritical, p.ApprovalWindowMinutes, p.ApproverIDs))
+}
+
+// CreatePolicyChange inserts a pending change of the user's approval policy and returns the created record.
+// Returns domain.ErrApprovalPolicyChangePending if an earlier change of the user still awaits a decision.
+func (apr *ApprovalPolicyRepository) CreatePolicyChange(ctx context.Context, c *models.ApprovalPolicyChange) (*models.ApprovalPolicyChange, error) {
+	const q = `
+		WITH c AS (
+			INSERT INTO approval_policy_changes (user_id, recipient_threshold, require_for_critical, approval_window_minutes,
+			                                     new_approver_ids, approver_ids, expires_at)
+			SELECT $1::int, $2::int, $3::boolean, $4::int, $5::int[], $6::int[], $7::timestamptz
+			WHERE NOT EXISTS (
+				SELECT 1
+				FROM approval_policy_changes
+				WHERE user_id = $1
+				  AND status = 'pending'
+				  AND expires_at > now()
+			)
+			RETURNING *
+		)
+		SELECT ` + approvalPolicyChangeColumns + `
+		FROM c
+		JOIN users u ON u.id = c.user_id
+		LEFT JOIN users d ON d.id = c.decided_by
+	`
+
+	var (
+		threshold, window *int
+		critical          *bool
+		approverIDs       []int
+	)
+	if c.Policy != nil {
+		threshold, critical, window = &c.Policy.RecipientThreshold, &c.Policy.RequireForCritical, &c.Policy.ApprovalWindowMinutes
+		approverIDs = c.Policy.ApproverIDs
+	}
+
+	created, err := scanApprovalPolicyChange(apr.db.QueryRow(ctx, q,
+		c.UserID, threshold, critical, window, approverIDs, c.ApproverIDs, c.ExpiresAt,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrApprovalPolicyChangePending
+		}
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// GetPolicyChangesByUserID retrieves the approval policy changes requested by the user or awaiting
+// their decision, newest first.
+func (apr *ApprovalPolicyRepository) GetPolicyChangesByUserID(ctx context.Context, userID int) ([]*models.ApprovalPolicyChange, error) {
+	const q = `
+		SELECT ` + approvalPolicyChangeColumns + `
+		FROM approval_policy_changes c
+		JOIN users u ON u.id = c.user_id
+		LEFT JOIN users d ON d.id = c.decided_by
+		WHERE c.user_id = $1
+		   OR $1 = ANY (c.approver_ids)
+		ORDER BY c.created_at DESC, c.id DESC
+	`
+
+	rows, err := apr.db.Query(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := make([]*models.ApprovalPolicyChange, 0)
+	for rows.Next() {
+		c, err := scanApprovalPolicyChange(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		changes = append(changes, c)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// GetPolicyChangeByID retrieves an approval policy change requested by the user or awaiting their decision.
+// Returns domain.ErrApprovalPolicyChangeNotExists if there is no such change.
+func (apr *ApprovalPolicyRepository) GetPolicyChangeByID(ctx context.Context, userID, changeID int) (*models.ApprovalPolicyChange, error) {
+	const q = `
+		SELECT ` + approvalPolicyChangeColumns + `
+		FROM approval_policy_changes c
+		JOIN users u ON u.id = c.user_id
+		LEFT JOIN users d ON d.id = c.decided_by
+		WHERE c.id = $1
+		  AND (c.user_id = $2 OR $2 = ANY (c.approver_ids))
+	`
+
+	c, err := scanApprovalPolicyChange(apr.db.QueryRow(ctx, q, changeID, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrApprovalPolicyChangeNotExists
+		}
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// DecidePolicyChange sets the status of a pending approval policy change to the decision of the
+// approver, approved or rejected, with the comment, and returns the updated record. An approved
+// change replaces or deletes the policy of its user in the same statement.
+// Returns domain.ErrApprovalNotPending if the change is not pending, is past its deadline or
+// doesn't await the decision of the approver, so that only one decision is ever made.
+func (apr *ApprovalPolicyRepository) DecidePolicyChange(ctx context.Context, approverID, changeID int, status models.ApprovalStatus, comment string) (*models.ApprovalPolicyChange, error) {
+	const q = `
+		WITH c AS (
+			UPDATE approval_policy_changes
+			SET status     = $3,
+			    decided_by = $1,
+			    comment    = $4,
+			    decided_at = now()
+			WHERE id = $2
+			  AND $1 = ANY (approver_ids)
+			  AND status = 'pending'
+			  AND expires_at > now()
+			RETURNING *
+		), deleted AS (
+			DELETE
+			FROM approval_policies p
+			USING c
+			WHERE c.status = 'approved'
+			  AND c.new_approver_ids IS NULL
+			  AND p.user_id = c.user_id
+		), updated AS (
+			UPDATE approval_policies p
+			SET recipient_threshold     = c.recipient_threshold,
+			    require_for_critical    = c.require_for_critical,
+			    approval_window_minutes = c.approval_window_minutes,
+			    approver_ids            = c.new_approver_ids,
+			    updated_at              = now()
+			FROM c
+			WHERE c.status = 'approved'
+			  AND c.new_approver_ids IS NOT NULL
+			  AND p.user_id = c.user_id
+		)
+		SELECT ` + approvalPolicyChangeColumns + `
+		FROM c
+		JOIN users u ON u.id = c.user_id
+		LEFT JOIN users d ON d.id = c.decided_by
+	`
+
+	c, err := scanApprovalPolicyChange(apr.db.QueryRow(ctx, q, approverID, changeID, string(status), comment))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrApprovalNotPending
+		}
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func scanApprovalPolicy(row pgx.Row) (*models.ApprovalPolicy, error) {
+	var p models.ApprovalPolicy
+
+	err := row.Scan(&p.RecipientThreshold, &p.RequireForCritical, &p.ApprovalWindowMinutes, &p.ApproverIDs, &p.Approvers)
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+func scanApprovalPolicyChange(row pgx.Row) (*models.ApprovalPolicyChange, error) {
+	var (
+		c                 models.ApprovalPolicyChange
+		threshold, window *int
+		critical          *bool
+		approverIDs       []int
+		approvers         []string
+	)
+
+	err := row.Scan(
+		&c.ID, &c.UserID, &c.RequesterEmail, &threshold, &critical, &window, &approverIDs, &approvers,
+		&c.Status, &c.ApproverIDs, &c.DeciderEmail, &c.Comment, &c.ExpiresAt, &c.DecisionTime, &c.CreationTime,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if approverIDs != nil {
+		c.Policy = &models.ApprovalPolicy{
+			RecipientThreshold:    *threshold,
+			RequireForCritical:    *critical,
+			ApprovalWindowMinutes: *window,
+			Approvers:             approvers,
+			ApproverIDs:           approverIDs,
+		}
+	}
+
+	return &c, nil
+}
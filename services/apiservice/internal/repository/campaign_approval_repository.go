@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// campaignApprovalColumns selects a campaign approval a joined with its requester u. Pending
+// approvals past their deadline are reported as expired, even before their expiry is recorded.
+const campaignApprovalColumns = `
+	a.id, a.user_id, u.email, COALESCE(a.template_id, 0), a.campaign_id, a.message, a.options, a.recipients, a.reason,
+	CASE WHEN a.status = 'pending' AND a.expires_at <= now() THEN 'expired' ELSE a.status END,
+	a.approver_ids, a.expires_at, a.decided_at, a.created_at
+`
+
+// CampaignApprovalRepository handles operations on the campaign_approvals table and its audit
+// trail in the campaign_approval_events table.
+type CampaignApprovalRepository struct {
+	db domain.DBConn
+}
+
+// NewCampaignApprovalRepository constructs a CampaignApprovalRepository using the provided DB connection.
+func NewCampaignApprovalRepository(db domain.DBConn) *CampaignApprovalRepository {
+	return &CampaignApprovalRepository{
+		db: db,
+	}
+}
+
+// CreateApproval inserts a pending campaign approval, recording that its user requested it,
+// and returns the created record. Approvals of messages sent without a template have no template ID.
+func (car *CampaignApprovalRepository) CreateApproval(ctx context.Context, a *models.CampaignApproval) (*models.CampaignApproval, error) {
+	const q = `
+		WITH a AS (
+			INSERT INTO campaign_approvals (user_id, template_id, campaign_id, message, options, recipients, reason, approver_ids, expires_at)
+			VALUES ($1, NULLIF($2, 0), $3, $4, $5::jsonb, $6, $7, $8, $9)
+			RETURNING *
+		), requested AS (
+			INSERT INTO campaign_approval_events (approval_id, actor_id, action)
+			SELECT id, user_id, 'requested'
+			FROM a
+		)
+		SELECT ` + campaignApprovalColumns + `
+		FROM a
+		JOIN users u ON u.id = a.user_id
+	`
+
+	return scanCampaignApproval(car.db.QueryRow(ctx, q,
+		a.UserID, a.TemplateID, a.CampaignID, a.Message, string(a.Options), a.Recipients, a.Reason, a.ApproverIDs, a.ExpiresAt,
+	))
+}
+
+// GetApprovalsByUserID retrieves the campaign approvals requested by the user or awaiting their
+// decision, newest first, without their audit trails.
+func (car *CampaignApprovalRepository) GetApprovalsByUserID(ctx context.Context, userID int) ([]*models.CampaignApproval, error) {
+	const q = `
+		SELECT ` + campaignApprovalColumns + `
+		FROM campaign_approvals a
+		JOIN users u ON u.id = a.user_id
+		WHERE a.user_id = $1
+		   OR $1 = ANY (a.approver_ids)
+		ORDER BY a.created_at DESC, a.id DESC
+	`
+
+	rows, err := car.db.Query(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	approvals := make([]*models.CampaignApproval, 0)
+	for rows.Next() {
+		a, err := scanCampaignApproval(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		approvals = append(approvals, a)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return approvals, nil
+}
+
+// GetApprovalByID retrieves a campaign approval requested by the user or awaiting their decision,
+// along with its audit trail.
+// Returns domain.ErrCampaignApprovalNotExists if there is no such approval.
+func (car *CampaignApprovalRepository) GetApprovalByID(ctx context.Context, userID, approvalID int) (*models.CampaignApproval, error) {
+	const q = `
+		SELECT ` + campaignApprovalColumns + `
+		FROM campaign_approvals a
+		JOIN users u ON u.id = a.user_id
+		WHERE a.id = $1
+		  AND (a.user_id = $2 OR $2 = ANY (a.approver_ids))
+	`
+
+	a, err := scanCampaignApproval(car.db.QueryRow(ctx, q, approvalID, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrCampaignApprovalNotExists
+		}
+		return nil, err
+	}
+
+	const eventsQuery = `
+		SELECT e.action, COALESCE(u.email, ''), e.comment, e.created_at
+		FROM campaign_approval_events e
+		LEFT JOIN users u ON u.id = e.actor_id
+		WHERE e.approval_id = $1
+		ORDER BY e.id
+	`
+
+	rows, err := car.db.Query(ctx, eventsQuery, approvalID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	a.Events = make([]*models.ApprovalEvent, 0)
+	for rows.Next() {
+		var e models.ApprovalEvent
+
+		err = rows.Scan(&e.Action, &e.ActorEmail, &e.Comment, &e.CreationTime)
+		if err != nil {
+			return nil, err
+		}
+
+		a.Events = append(a.Events, &e)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DecideApproval sets the status of a pending campaign approval to the decision of the approver,
+// approved or rejected, recording the decision with the comment, and returns the updated record.
+// Returns domain.ErrApprovalNotPending if the approval is not pending, is past its deadline or
+// doesn't await the decision of the approver, so that only one decision is ever made.
+func (car *CampaignApprovalRepository) DecideApproval(ctx context.Context, approverID, approvalID int, status models.ApprovalStatus, comment string) (*models.CampaignApproval, error) {
+	const q = `
+		WITH a AS (
+			UPDATE campaign_approvals
+			SET status     = $3,
+			    decided_at = now()
+			WHERE id = $2
+			  AND $1 = ANY (approver_ids)
+			  AND status = 'pending'
+			  AND expires_at > now()
+			RETURNING *
+		), decided AS (
+			INSERT INTO campaign_approval_events (approval_id, actor_id, action, comment)
+			SELECT id, $1, $3, $4
+			FROM a
+		)
+		SELECT ` + campaignApprovalColumns + `
+		FROM a
+		JOIN users u ON u.id = a.user_id
+	`
+
+	a, err := scanCampaignApproval(car.db.QueryRow(ctx, q, approverID, approvalID, string(status), comment))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrApprovalNotPending
+		}
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// ExpireApproval records that a pending campaign approval passed its deadline without a decision.
+// Does nothing if it is not pending or not past its deadline.
+func (car *CampaignApprovalRepository) ExpireApproval(ctx context.Context, approvalID int) error {
+	const q = `
+		WITH a AS (
+			UPDATE campaign_approvals
+			SET status     = 'expired',
+			    decided_at = expires_at
+			WHERE id = $1
+			  AND status = 'pending'
+			  AND expires_at <= now()
+			RETURNING id
+		)
+		INSERT INTO campaign_approval_events (approval_id, action)
+		SELECT id, 'expired'
+		FROM a
+	`
+
+	_, err := car.db.Exec(ctx, q, approvalID)
+	return err
+}
+
+// FailApproval records that an approved campaign could not be sent.
+// Does nothing if the approval is not approved.
+func (car *CampaignApprovalRepository) FailApproval(ctx context.Context, approvalID int) error {
+	const q = `
+		WITH a AS (
+			UPDATE campaign_approvals
+			SET status = 'failed'
+			WHERE id = $1
+			  AND status = 'approved'
+			RETURNING id
+		)
+		INSERT INTO campaign_approval_events (approval_id, action)
+		SELECT id, 'failed'
+		FROM a
+	`
+
+	_, err := car.db.Exec(ctx, q, approvalID)
+	return err
+}
+
+func scanCampaignApproval(row pgx.Row) (*models.CampaignApproval, error) {
+	var a models.CampaignApproval
+
+	err := row.Scan(
+		&a.ID, &a.UserID, &a.RequesterEmail, &a.TemplateID, &a.CampaignID, &a.Message, &a.Options, &a.Recipients, &a.Reason,
+		&a.Status, &a.ApproverIDs, &a.ExpiresAt, &a.DecisionTime, &a.CreationTime,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
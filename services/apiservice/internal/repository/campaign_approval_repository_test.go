@@ -0,0 +1,201 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+func clearCampaignApprovals(t *testing.T, db *sql.DB) {
+	t.Helper()
+	_, err := db.Exec("TRUNCATE approval_policies, approval_policy_changes, campaign_approvals RESTART IDENTITY CASCADE")
+	require.NoError(t, err)
+}
+
+func TestApprovalPolicyRepository(t *testing.T) {
+	t.Cleanup(func() { clearCampaignApprovals(t, testDB) })
+
+	fixtures := makeFixtures(t, testDB, "../../../../db/fixtures/users.yml")
+	require.NoError(t, fixtures.Load())
+
+	ctx := context.Background()
+	repo := repository.NewApprovalPolicyRepository(testPool)
+
+	_, err := repo.GetApprovalPolicyByUserID(ctx, 1)
+	require.ErrorIs(t, err, domain.ErrApprovalPolicyNotExists)
+
+	policy, err := repo.UpsertApprovalPolicy(ctx, 1, &models.ApprovalPolicy{RecipientThreshold: 100, ApprovalWindowMinutes: 30, ApproverIDs: []int{2}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"bob@example.com"}, policy.Approvers)
+	require.Equal(t, []int{2}, policy.ApproverIDs)
+
+	policy, err = repo.UpsertApprovalPolicy(ctx, 1, &models.ApprovalPolicy{RequireForCritical: true, ApprovalWindowMinutes: 60, ApproverIDs: []int{2}})
+	require.NoError(t, err)
+	require.Zero(t, policy.RecipientThreshold)
+	require.True(t, policy.RequireForCritical)
+
+	got, err := repo.GetApprovalPolicyByUserID(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, policy, got)
+}
+
+func TestApprovalPolicyRepository_PolicyChanges(t *testing.T) {
+	t.Cleanup(func() { clearCampaignApprovals(t, testDB) })
+
+	fixtures := makeFixtures(t, testDB, "../../../../db/fixtures/users.yml")
+	require.NoError(t, fixtures.Load())
+
+	ctx := context.Background()
+	repo := repository.NewApprovalPolicyRepository(testPool)
+
+	_, err := repo.UpsertApprovalPolicy(ctx, 1, &models.ApprovalPolicy{RecipientThreshold: 100, RequireForCritical: true, ApprovalWindowMinutes: 30, ApproverIDs: []int{2}})
+	require.NoError(t, err)
+
+	// a relaxed policy is applied only once an approver approves it
+	relaxed, err := repo.CreatePolicyChange(ctx, &models.ApprovalPolicyChange{
+		UserID:      1,
+		Policy:      &models.ApprovalPolicy{RecipientThreshold: 500, ApprovalWindowMinutes: 30, ApproverIDs: []int{2}},
+		ApproverIDs: []int{2},
+		ExpiresAt:   time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, models.ApprovalPending, relaxed.Status)
+	require.Equal(t, "alice@example.com", relaxed.RequesterEmail)
+	require.Equal(t, []string{"bob@example.com"}, relaxed.Policy.Approvers)
+
+	_, err = repo.CreatePolicyChange(ctx, &models.ApprovalPolicyChange{UserID: 1, ApproverIDs: []int{2}, ExpiresAt: time.Now().Add(time.Hour)})
+	require.ErrorIs(t, err, domain.ErrApprovalPolicyChangePending)
+
+	for _, userID := range []int{1, 2} {
+		changes, err := repo.GetPolicyChangesByUserID(ctx, userID)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+	}
+	_, err = repo.GetPolicyChangeByID(ctx, 3, relaxed.ID)
+	require.ErrorIs(t, err, domain.ErrApprovalPolicyChangeNotExists)
+
+	_, err = repo.DecidePolicyChange(ctx, 1, relaxed.ID, models.ApprovalApproved, "")
+	require.ErrorIs(t, err, domain.ErrApprovalNotPending)
+
+	approved, err := repo.DecidePolicyChange(ctx, 2, relaxed.ID, models.ApprovalApproved, "agreed")
+	require.NoError(t, err)
+	require.Equal(t, models.ApprovalApproved, approved.Status)
+	require.Equal(t, "bob@example.com", approved.DeciderEmail)
+	require.Equal(t, "agreed", approved.Comment)
+	require.NotNil(t, approved.DecisionTime)
+
+	policy, err := repo.GetApprovalPolicyByUserID(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, 500, policy.RecipientThreshold)
+	require.False(t, policy.RequireForCritical)
+
+	// a rejected deletion leaves the policy in place
+	deletion, err := repo.CreatePolicyChange(ctx, &models.ApprovalPolicyChange{UserID: 1, ApproverIDs: []int{2}, ExpiresAt: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	require.Nil(t, deletion.Policy)
+
+	_, err = repo.DecidePolicyChange(ctx, 2, deletion.ID, models.ApprovalRejected, "")
+	require.NoError(t, err)
+	_, err = repo.GetApprovalPolicyByUserID(ctx, 1)
+	require.NoError(t, err)
+
+	// an expired change can't be approved
+	overdue, err := repo.CreatePolicyChange(ctx, &models.ApprovalPolicyChange{UserID: 1, ApproverIDs: []int{2}, ExpiresAt: time.Now().Add(-time.Minute)})
+	require.NoError(t, err)
+	require.Equal(t, models.ApprovalExpired, overdue.Status)
+	_, err = repo.DecidePolicyChange(ctx, 2, overdue.ID, models.ApprovalApproved, "")
+	require.ErrorIs(t, err, domain.ErrApprovalNotPending)
+
+	// an approved deletion removes the policy
+	deletion, err = repo.CreatePolicyChange(ctx, &models.ApprovalPolicyChange{UserID: 1, ApproverIDs: []int{2}, ExpiresAt: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	_, err = repo.DecidePolicyChange(ctx, 2, deletion.ID, models.ApprovalApproved, "")
+	require.NoError(t, err)
+	_, err = repo.GetApprovalPolicyByUserID(ctx, 1)
+	require.ErrorIs(t, err, domain.ErrApprovalPolicyNotExists)
+}
+
+func TestCampaignApprovalRepository(t *testing.T) {
+	t.Cleanup(func() {
+		clearCampaignApprovals(t, testDB)
+		clearTemplates(t, testDB)
+	})
+
+	fixtures := makeFixtures(t, testDB, "../../../../db/fixtures/users.yml")
+	require.NoError(t, fixtures.Load())
+
+	ctx := context.Background()
+	tmpl, err := repository.NewTemplateRepository(testPool).CreateTemplate(ctx, &models.Template{UserID: 1, Name: "Flood", Body: "Evacuate"})
+	require.NoError(t, err)
+
+	repo := repository.NewCampaignApprovalRepository(testPool)
+	request := func(expiresAt time.Time) *models.CampaignApproval {
+		a, err := repo.CreateApproval(ctx, &models.CampaignApproval{
+			UserID: 1, TemplateID: tmpl.ID, Message: tmpl.Body, Options: []byte(`{"priority":"critical"}`),
+			Recipients: 1500, Reason: models.ApprovalReasonCritical, ApproverIDs: []int{2}, ExpiresAt: expiresAt,
+		})
+		require.NoError(t, err)
+		return a
+	}
+
+	pending := request(time.Now().Add(time.Hour))
+	require.Equal(t, models.ApprovalPending, pending.Status)
+	require.Equal(t, "alice@example.com", pending.RequesterEmail)
+	require.JSONEq(t, `{"priority":"critical"}`, string(pending.Options))
+
+	for _, userID := range []int{1, 2} {
+		approvals, err := repo.GetApprovalsByUserID(ctx, userID)
+		require.NoError(t, err)
+		require.Len(t, approvals, 1)
+	}
+
+	_, err = repo.DecideApproval(ctx, 1, pending.ID, models.ApprovalApproved, "")
+	require.ErrorIs(t, err, domain.ErrApprovalNotPending)
+
+	approved, err := repo.DecideApproval(ctx, 2, pending.ID, models.ApprovalApproved, "looks right")
+	require.NoError(t, err)
+	require.Equal(t, models.ApprovalApproved, approved.Status)
+	require.NotNil(t, approved.DecisionTime)
+
+	_, err = repo.DecideApproval(ctx, 2, pending.ID, models.ApprovalRejected, "")
+	require.ErrorIs(t, err, domain.ErrApprovalNotPending)
+
+	require.NoError(t, repo.FailApproval(ctx, pending.ID))
+
+	got, err := repo.GetApprovalByID(ctx, 2, pending.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.ApprovalFailed, got.Status)
+	require.Len(t, got.Events, 3)
+	require.Equal(t, models.ApprovalActionRequested, got.Events[0].Action)
+	require.Equal(t, "alice@example.com", got.Events[0].ActorEmail)
+	require.Equal(t, models.ApprovalActionApproved, got.Events[1].Action)
+	require.Equal(t, "bob@example.com", got.Events[1].ActorEmail)
+	require.Equal(t, "looks right", got.Events[1].Comment)
+	require.Equal(t, models.ApprovalActionFailed, got.Events[2].Action)
+
+	overdue := request(time.Now().Add(-time.Minute))
+	got, err = repo.GetApprovalByID(ctx, 1, overdue.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.ApprovalExpired, got.Status)
+
+	_, err = repo.DecideApproval(ctx, 2, overdue.ID, models.ApprovalApproved, "")
+	require.ErrorIs(t, err, domain.ErrApprovalNotPending)
+
+	require.NoError(t, repo.ExpireApproval(ctx, overdue.ID))
+	got, err = repo.GetApprovalByID(ctx, 1, overdue.ID)
+	require.NoError(t, err)
+	require.Len(t, got.Events, 2)
+	require.Equal(t, models.ApprovalActionExpired, got.Events[1].Action)
+
+	_, err = repo.GetApprovalByID(ctx, 3, overdue.ID)
+	require.ErrorIs(t, err, domain.ErrCampaignApprovalNotExists)
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+)
+
+// CampaignRepository handles operations on the campaigns table, which records the template
+// version every campaign was sent from.
+type CampaignRepository struct {
+	db domain.DBConn
+}
+
+// NewCampaignRepository constructs a CampaignRepository using the provided DB connection.
+func NewCampaignRepository(db domain.DBConn) *CampaignRepository {
+	return &CampaignRepository{
+		db: db,
+	}
+}
+
+// CreateCampaign records a campaign sent from the given version of a template.
+func (cr *CampaignRepository) CreateCampaign(ctx context.Context, c *models.Campaign) error {
+	const q = `
+		INSERT INTO campaigns (id, user_id, template_id, template_version)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	_, err := cr.db.Exec(ctx, q, c.ID, c.UserID, c.TemplateID, c.TemplateVersion)
+	return err
+}
+
+// GetCampaignsByUserID retrieves a page of the user's campaigns matching the filter, newest first,
+// along with the body of the template version each of them was sent from.
+func (cr *CampaignRepository) GetCampaignsByUserID(ctx context.Context, userID int, filter domain.CampaignFilter, limit, offset int) ([]*models.Campaign, error) {
+	conds := []string{"c.user_id = $1"}
+	args := []any{userID}
+
+	if filter.TemplateID != 0 {
+		args = append(args, filter.TemplateID)
+		conds = append(conds, fmt.Sprintf("c.template_id = $%d", len(args)))
+	}
+
+	args = append(args, limit, offset)
+	q := fmt.Sprintf(`
+		SELECT c.id, c.user_id, c.template_id, c.template_version, v.body, c.created_at
+		FROM campaigns c
+		JOIN template_versions v ON v.template_id = c.template_id AND v.version = c.template_version
+		WHERE %s
+		ORDER BY c.created_at DESC, c.id
+		LIMIT $%d OFFSET $%d
+	`, strings.Join(conds, " AND "), len(args)-1, len(args))
+
+	rows, err := cr.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	campaigns := make([]*models.Campaign, 0)
+	for rows.Next() {
+		var c models.Campaign
+
+		err := rows.Scan(&c.ID, &c.UserID, &c.TemplateID, &c.TemplateVersion, &c.Body, &c.CreationTime)
+		if err != nil {
+			return nil, err
+		}
+
+		campaigns = append(campaigns, &c)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return campaigns, nil
+}
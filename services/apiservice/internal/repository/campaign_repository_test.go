@@ -0,0 +1,71 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCampaignRepository(t *testing.T) {
+	t.Cleanup(func() { clearTemplates(t, testDB) })
+
+	fixtures := makeFixtures(t, testDB, "../../../../db/fixtures/users.yml")
+	require.NoError(t, fixtures.Load())
+
+	ctx := context.Background()
+	tr := repository.NewTemplateRepository(testPool)
+	repo := repository.NewCampaignRepository(testPool)
+
+	flood, err := tr.CreateTemplate(ctx, &models.Template{UserID: 1, Name: "Flood", Body: "Leave the area"})
+	require.NoError(t, err)
+	fire, err := tr.CreateTemplate(ctx, &models.Template{UserID: 1, Name: "Fire", Body: "Fire nearby"})
+	require.NoError(t, err)
+
+	first := &models.Campaign{ID: uuid.New(), UserID: 1, TemplateID: flood.ID, TemplateVersion: flood.Version}
+	require.NoError(t, repo.CreateCampaign(ctx, first))
+
+	flood, err = tr.UpdateTemplate(ctx, 1, flood.ID, &models.Template{UserID: 1, Name: "Flood", Body: "Leave the district"})
+	require.NoError(t, err)
+
+	second := &models.Campaign{ID: uuid.New(), UserID: 1, TemplateID: flood.ID, TemplateVersion: flood.Version}
+	require.NoError(t, repo.CreateCampaign(ctx, second))
+	require.NoError(t, repo.CreateCampaign(ctx, &models.Campaign{ID: uuid.New(), UserID: 1, TemplateID: fire.ID, TemplateVersion: fire.Version}))
+
+	err = repo.CreateCampaign(ctx, &models.Campaign{ID: uuid.New(), UserID: 1, TemplateID: flood.ID, TemplateVersion: 9})
+	require.Error(t, err)
+
+	campaigns, err := repo.GetCampaignsByUserID(ctx, 1, domain.CampaignFilter{TemplateID: flood.ID}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, campaigns, 2)
+
+	byID := map[uuid.UUID]*models.Campaign{}
+	for _, c := range campaigns {
+		byID[c.ID] = c
+	}
+	require.Equal(t, 1, byID[first.ID].TemplateVersion)
+	require.Equal(t, "Leave the area", byID[first.ID].Body)
+	require.Equal(t, 2, byID[second.ID].TemplateVersion)
+	require.Equal(t, "Leave the district", byID[second.ID].Body)
+
+	require.NoError(t, tr.DeleteTemplate(ctx, 1, flood.ID))
+
+	all, err := repo.GetCampaignsByUserID(ctx, 1, domain.CampaignFilter{}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+
+	page, err := repo.GetCampaignsByUserID(ctx, 1, domain.CampaignFilter{}, 2, 2)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+
+	none, err := repo.GetCampaignsByUserID(ctx, 2, domain.CampaignFilter{}, 10, 0)
+	require.NoError(t, err)
+	require.Empty(t, none)
+}
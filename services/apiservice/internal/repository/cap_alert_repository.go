@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/cap"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// CAPAlertRepository handles operations on the cap_alerts table.
+type CAPAlertRepository struct {
+	db domain.DBConn
+}
+
+// NewCAPAlertRepository constructs a CAPAlertRepository using the provided DB connection.
+func NewCAPAlertRepository(db domain.DBConn) *CAPAlertRepository {
+	return &CAPAlertRepository{
+		db: db,
+	}
+}
+
+// CreateCAPAlert inserts a received alert and returns the created record.
+// Returns domain.ErrCAPAlertAlreadyExists if the user already received an alert with the same
+// sender and identifier.
+func (car *CAPAlertRepository) CreateCAPAlert(ctx context.Context, alert *models.CAPAlert) (*models.CAPAlert, error) {
+	const q = `
+		INSERT INTO cap_alerts (user_id, sender, identifier, sent, msg_type, campaign_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, user_id, sender, identifier, sent, msg_type, campaign_id, cancelled_at, created_at
+	`
+
+	row := car.db.QueryRow(ctx, q, alert.UserID, alert.Sender, alert.Identifier, alert.Sent, alert.MsgType, alert.CampaignID)
+	a, err := scanCAPAlert(row)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, domain.ErrCAPAlertAlreadyExists
+		}
+
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DeleteCAPAlert removes a received alert, so that it can be sent again.
+// Returns domain.ErrCAPAlertNotExists if no row was deleted.
+func (car *CAPAlertRepository) DeleteCAPAlert(ctx context.Context, userID, alertID int) error {
+	const q = `
+		DELETE
+		FROM cap_alerts
+		WHERE id = $1
+		  AND user_id = $2
+	`
+
+	res, err := car.db.Exec(ctx, q, alertID, userID)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return domain.ErrCAPAlertNotExists
+	}
+
+	return nil
+}
+
+// GetCAPAlertsByReferences retrieves the alerts of the user with the sender and identifier of any of
+// the references. The sent time of a reference is not compared, as senders format it inconsistently.
+func (car *CAPAlertRepository) GetCAPAlertsByReferences(ctx context.Context, userID int, refs []cap.Reference) ([]*models.CAPAlert, error) {
+	const q = `
+		SELECT id, user_id, sender, identifier, sent, msg_type, campaign_id, cancelled_at, created_at
+		FROM cap_alerts
+		WHERE user_id = $1
+		  AND (sender, identifier) IN (SELECT * FROM unnest($2::text[], $3::text[]))
+		ORDER BY id
+	`
+
+	senders := make([]string, len(refs))
+	identifiers := make([]string, len(refs))
+	for i, ref := range refs {
+		senders[i] = ref.Sender
+		identifiers[i] = ref.Identifier
+	}
+
+	rows, err := car.db.Query(ctx, q, userID, senders, identifiers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	alerts := make([]*models.CAPAlert, 0)
+	for rows.Next() {
+		a, err := scanCAPAlert(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		alerts = append(alerts, a)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return alerts, nil
+}
+
+// CancelCAPAlerts records that the campaigns of the alerts were cancelled.
+func (car *CAPAlertRepository) CancelCAPAlerts(ctx context.Context, userID int, alertIDs []int) error {
+	const q = `
+		UPDATE cap_alerts
+		SET cancelled_at = now()
+		WHERE user_id = $1
+		  AND id = ANY ($2)
+		  AND cancelled_at IS NULL
+	`
+
+	_, err := car.db.Exec(ctx, q, userID, alertIDs)
+	return err
+}
+
+func scanCAPAlert(row pgx.Row) (*models.CAPAlert, error) {
+	var a models.CAPAlert
+
+	err := row.Scan(&a.ID, &a.UserID, &a.Sender, &a.Identifier, &a.Sent, &a.MsgType, &a.CampaignID, &a.CancelTime, &a.CreationTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
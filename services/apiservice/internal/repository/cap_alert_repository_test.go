@@ -0,0 +1,75 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/cap"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func clearCAPAlerts(t *testing.T, db *sql.DB) {
+	t.Helper()
+	_, err := db.Exec("TRUNCATE cap_alerts")
+	require.NoError(t, err)
+}
+
+func TestCAPAlertRepository(t *testing.T) {
+	t.Cleanup(func() { clearCAPAlerts(t, testDB) })
+
+	fixtures := makeFixtures(t, testDB, "../../../../db/fixtures/users.yml")
+	require.NoError(t, fixtures.Load())
+
+	ctx := context.Background()
+	repo := repository.NewCAPAlertRepository(testPool)
+	campaignID := uuid.New()
+
+	alert := &models.CAPAlert{
+		UserID:     1,
+		Sender:     "alerts@example.org",
+		Identifier: "A1",
+		Sent:       "2026-10-19T10:00:00+03:00",
+		MsgType:    cap.MsgTypeAlert,
+		CampaignID: &campaignID,
+	}
+	created, err := repo.CreateCAPAlert(ctx, alert)
+	require.NoError(t, err)
+	_, err = repo.CreateCAPAlert(ctx, alert)
+	require.ErrorIs(t, err, domain.ErrCAPAlertAlreadyExists)
+
+	other := *alert
+	other.Identifier = "A2"
+	other.CampaignID = nil
+	_, err = repo.CreateCAPAlert(ctx, &other)
+	require.NoError(t, err)
+
+	refs := []cap.Reference{
+		{Sender: "alerts@example.org", Identifier: "A1", Sent: "2026-10-19T10:00:00+03:00"},
+		{Sender: "other@example.org", Identifier: "A2", Sent: "2026-10-19T10:00:00+03:00"},
+	}
+	found, err := repo.GetCAPAlertsByReferences(ctx, 1, refs)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	require.Equal(t, &campaignID, found[0].CampaignID)
+	require.Nil(t, found[0].CancelTime)
+
+	found, err = repo.GetCAPAlertsByReferences(ctx, 2, refs)
+	require.NoError(t, err)
+	require.Empty(t, found)
+
+	require.NoError(t, repo.CancelCAPAlerts(ctx, 1, []int{created.ID}))
+	found, err = repo.GetCAPAlertsByReferences(ctx, 1, refs)
+	require.NoError(t, err)
+	require.NotNil(t, found[0].CancelTime)
+
+	require.NoError(t, repo.DeleteCAPAlert(ctx, 1, created.ID))
+	require.ErrorIs(t, repo.DeleteCAPAlert(ctx, 1, created.ID), domain.ErrCAPAlertNotExists)
+}
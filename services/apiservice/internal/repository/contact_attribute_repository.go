@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ContactAttributeRepository handles CRUD operations on the contact_attributes table.
+type ContactAttributeRepository struct {
+	db domain.DBConn
+}
+
+// NewContactAttributeRepository constructs a ContactAttributeRepository using the provided DB connection.
+func NewContactAttributeRepository(db domain.DBConn) *ContactAttributeRepository {
+	return &ContactAttributeRepository{
+		db: db,
+	}
+}
+
+// GetContactAttributes retrieves every contact attribute defined by the user, ordered by name.
+func (ar *ContactAttributeRepository) GetContactAttributes(ctx context.Context, userID int) ([]*models.ContactAttribute, error) {
+	const q = `
+		SELECT id, user_id, name, type, options, required, created_at, updated_at
+		FROM contact_attributes
+		WHERE user_id = $1
+		ORDER BY lower(name)
+	`
+
+	rows, err := ar.db.Query(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attrs := make([]*models.ContactAttribute, 0)
+	for rows.Next() {
+		var a models.ContactAttribute
+
+		err := rows.Scan(&a.ID, &a.UserID, &a.Name, &a.Type, &a.Options, &a.Required, &a.CreationTime, &a.UpdateTime)
+		if err != nil {
+			return nil, err
+		}
+
+		attrs = append(attrs, &a)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return attrs, nil
+}
+
+// CreateContactAttribute inserts a new contact attribute and returns the created record.
+// Returns domain.ErrContactAttributeAlreadyExists if the user has an attribute with the same name.
+func (ar *ContactAttributeRepository) CreateContactAttribute(ctx context.Context, attr *models.ContactAttribute) (*models.ContactAttribute, error) {
+	const q = `
+		INSERT INTO contact_attributes (user_id, name, type, options, required)
+		VALUES ($1, $2, $3, COALESCE($4::jsonb, '[]'), $5)
+		RETURNING id, user_id, name, type, options, required, created_at, updated_at
+	`
+
+	var a models.ContactAttribute
+
+	row := ar.db.QueryRow(ctx, q, attr.UserID, attr.Name, attr.Type, attr.Options, attr.Required)
+	err := row.Scan(&a.ID, &a.UserID, &a.Name, &a.Type, &a.Options, &a.Required, &a.CreationTime, &a.UpdateTime)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, domain.ErrContactAttributeAlreadyExists
+		}
+
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+// UpdateContactAttribute replaces the definition of an existing contact attribute.
+// Returns domain.ErrContactAttributeNotExists if no attribute was updated.
+func (ar *ContactAttributeRepository) UpdateContactAttribute(ctx context.Context, userID, attrID int, attr *models.ContactAttribute) (*models.ContactAttribute, error) {
+	const q = `
+		UPDATE contact_attributes
+		SET name       = $1,
+		    type       = $2,
+		    options    = COALESCE($3::jsonb, '[]'),
+		    required   = $4,
+		    updated_at = now()
+		WHERE id = $5
+		  AND user_id = $6
+		RETURNING id, user_id, name, type, options, required, created_at, updated_at
+	`
+
+	var a models.ContactAttribute
+
+	row := ar.db.QueryRow(ctx, q, attr.Name, attr.Type, attr.Options, attr.Required, attrID, userID)
+	err := row.Scan(&a.ID, &a.UserID, &a.Name, &a.Type, &a.Options, &a.Required, &a.CreationTime, &a.UpdateTime)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrContactAttributeNotExists
+		}
+
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, domain.ErrContactAttributeAlreadyExists
+		}
+
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+// DeleteContactAttribute removes a contact attribute definition by ID and user ID. The values
+// contacts hold for it are kept as untyped attributes.
+// Returns domain.ErrContactAttributeNotExists if no row was deleted.
+func (ar *ContactAttributeRepository) DeleteContactAttribute(ctx context.Context, userID, attrID int) error {
+	const q = `
+		DELETE
+		FROM contact_attributes
+		WHERE id = $1
+		  AND user_id = $2
+	`
+
+	res, err := ar.db.Exec(ctx, q, attrID, userID)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return domain.ErrContactAttributeNotExists
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+func clearContactAttributes(t *testing.T, db *sql.DB) {
+	t.Helper()
+	_, err := db.Exec("TRUNCATE contact_attributes")
+	require.NoError(t, err)
+}
+
+func TestContactAttributeRepository_CRUD(t *testing.T) {
+	t.Cleanup(func() { clearContactAttributes(t, testDB) })
+
+	fixtures := makeFixtures(t, testDB, "../../../../db/fixtures/users.yml")
+	require.NoError(t, fixtures.Load())
+
+	ctx := context.Background()
+	repo := repository.NewContactAttributeRepository(testPool)
+
+	shift, err := repo.CreateContactAttribute(ctx, &models.ContactAttribute{
+		UserID: 1, Name: "Shift", Type: models.AttributeEnum, Options: []string{"Day", "Night"}, Required: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Day", "Night"}, shift.Options)
+
+	_, err = repo.CreateContactAttribute(ctx, &models.ContactAttribute{UserID: 1, Name: "Building", Type: models.AttributeText})
+	require.NoError(t, err)
+
+	// names are unique per user regardless of case
+	_, err = repo.CreateContactAttribute(ctx, &models.ContactAttribute{UserID: 1, Name: "SHIFT", Type: models.AttributeText})
+	require.ErrorIs(t, err, domain.ErrContactAttributeAlreadyExists)
+	_, err = repo.CreateContactAttribute(ctx, &models.ContactAttribute{UserID: 2, Name: "Shift", Type: models.AttributeText})
+	require.NoError(t, err)
+
+	attrs, err := repo.GetContactAttributes(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, attrs, 2)
+	require.Equal(t, "Building", attrs[0].Name)
+	require.Empty(t, attrs[0].Options)
+
+	updated, err := repo.UpdateContactAttribute(ctx, 1, shift.ID, &models.ContactAttribute{Name: "Shift", Type: models.AttributeText})
+	require.NoError(t, err)
+	require.Equal(t, models.AttributeText, updated.Type)
+	require.Empty(t, updated.Options)
+	require.False(t, updated.Required)
+
+	_, err = repo.UpdateContactAttribute(ctx, 2, shift.ID, &models.ContactAttribute{Name: "Shift", Type: models.AttributeText})
+	require.ErrorIs(t, err, domain.ErrContactAttributeNotExists)
+
+	require.NoError(t, repo.DeleteContactAttribute(ctx, 1, shift.ID))
+	require.ErrorIs(t, repo.DeleteContactAttribute(ctx, 1, shift.ID), domain.ErrContactAttributeNotExists)
+}
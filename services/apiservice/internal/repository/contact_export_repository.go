@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// ContactExportRepository handles operations on the contact_exports table.
+type ContactExportRepository struct {
+	db domain.DBConn
+}
+
+// NewContactExportRepository constructs a ContactExportRepository using the provided DB connection.
+func NewContactExportRepository(db domain.DBConn) *ContactExportRepository {
+	return &ContactExportRepository{
+		db: db,
+	}
+}
+
+// CreateContactExport inserts a new export job in the queued state and returns the created record.
+func (cer *ContactExportRepository) CreateContactExport(ctx context.Context, export *models.ContactExport) (*models.ContactExport, error) {
+	const q = `
+		INSERT INTO contact_exports (user_id, format, status)
+		VALUES ($1, $2, $3)
+		RETURNING id, user_id, format, status, rows_written, s3_key, error, created_at, updated_at
+	`
+
+	row := cer.db.QueryRow(ctx, q, export.UserID, export.Format, models.ExportStatusQueued)
+	return scanContactExport(row)
+}
+
+// GetContactExportByID retrieves an export job owned by the user.
+// Returns domain.ErrContactExportNotExists if no row is found.
+func (cer *ContactExportRepository) GetContactExportByID(ctx context.Context, userID, exportID int) (*models.ContactExport, error) {
+	const q = `
+		SELECT id, user_id, format, status, rows_written, s3_key, error, created_at, updated_at
+		FROM contact_exports
+		WHERE user_id = $1
+		  AND id = $2
+	`
+
+	ce, err := scanContactExport(cer.db.QueryRow(ctx, q, userID, exportID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrContactExportNotExists
+		}
+
+		return nil, err
+	}
+
+	return ce, nil
+}
+
+// StartContactExport moves an export job to the processing state.
+// Returns domain.ErrContactExportNotExists if no row is found.
+func (cer *ContactExportRepository) StartContactExport(ctx context.Context, exportID int) error {
+	const q = `
+		UPDATE contact_exports
+		SET status     = $2,
+		    updated_at = now()
+		WHERE id = $1
+	`
+
+	return cer.exec(ctx, q, exportID, models.ExportStatusProcessing)
+}
+
+// CompleteContactExport marks an export job as done, recording the number of contacts
+// written and the S3 key of the file.
+// Returns domain.ErrContactExportNotExists if no row is found.
+func (cer *ContactExportRepository) CompleteContactExport(ctx context.Context, exportID, rowsWritten int, s3Key string) error {
+	const q = `
+		UPDATE contact_exports
+		SET status       = $2,
+		    rows_written = $3,
+		    s3_key       = $4,
+		    updated_at   = now()
+		WHERE id = $1
+	`
+
+	return cer.exec(ctx, q, exportID, models.ExportStatusDone, rowsWritten, s3Key)
+}
+
+// FailContactExport marks an export job as failed with the given reason.
+// Returns domain.ErrContactExportNotExists if no row is found.
+func (cer *ContactExportRepository) FailContactExport(ctx context.Context, exportID int, reason string) error {
+	const q = `
+		UPDATE contact_exports
+		SET status     = $2,
+		    error      = $3,
+		    updated_at = now()
+		WHERE id = $1
+	`
+
+	return cer.exec(ctx, q, exportID, models.ExportStatusFailed, reason)
+}
+
+func (cer *ContactExportRepository) exec(ctx context.Context, q string, args ...any) error {
+	res, err := cer.db.Exec(ctx, q, args...)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrContactExportNotExists
+	}
+
+	return nil
+}
+
+func scanContactExport(row pgx.Row) (*models.ContactExport, error) {
+	var ce models.ContactExport
+
+	err := row.Scan(&ce.ID, &ce.UserID, &ce.Format, &ce.Status, &ce.RowsWritten, &ce.S3Key, &ce.Error,
+		&ce.CreationTime, &ce.UpdateTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ce, nil
+}
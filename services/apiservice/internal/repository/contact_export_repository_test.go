@@ -0,0 +1,64 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+func clearContactExports(t *testing.T, db *sql.DB) {
+	t.Helper()
+	_, err := db.Exec("TRUNCATE contact_exports")
+	require.NoError(t, err)
+}
+
+func TestContactExportRepository(t *testing.T) {
+	t.Cleanup(func() { clearContactExports(t, testDB) })
+
+	fixtures := makeFixtures(t, testDB, "../../../../db/fixtures/users.yml")
+	require.NoError(t, fixtures.Load())
+
+	ctx := context.Background()
+	repo := repository.NewContactExportRepository(testPool)
+
+	created, err := repo.CreateContactExport(ctx, &models.ContactExport{UserID: 1, Format: models.ExportFormatXLSX})
+	require.NoError(t, err)
+	require.NotZero(t, created.ID)
+	require.Equal(t, models.ExportStatusQueued, created.Status)
+	require.Equal(t, models.ExportFormatXLSX, created.Format)
+
+	got, err := repo.GetContactExportByID(ctx, 1, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, created, got)
+
+	_, err = repo.GetContactExportByID(ctx, 2, created.ID)
+	require.ErrorIs(t, err, domain.ErrContactExportNotExists)
+
+	require.NoError(t, repo.StartContactExport(ctx, created.ID))
+	got, err = repo.GetContactExportByID(ctx, 1, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.ExportStatusProcessing, got.Status)
+
+	require.NoError(t, repo.CompleteContactExport(ctx, created.ID, 42, "exports/1/contacts.xlsx"))
+	got, err = repo.GetContactExportByID(ctx, 1, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.ExportStatusDone, got.Status)
+	require.Equal(t, 42, got.RowsWritten)
+	require.Equal(t, "exports/1/contacts.xlsx", got.S3Key)
+
+	require.NoError(t, repo.FailContactExport(ctx, created.ID, "upload failed"))
+	got, err = repo.GetContactExportByID(ctx, 1, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.ExportStatusFailed, got.Status)
+	require.Equal(t, "upload failed", got.Error)
+
+	require.ErrorIs(t, repo.StartContactExport(ctx, created.ID+1), domain.ErrContactExportNotExists)
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// ContactImportRepository handles operations on the contact_imports table.
+type ContactImportRepository struct {
+	db domain.DBConn
+}
+
+// NewContactImportRepository constructs a ContactImportRepository using the provided DB connection.
+func NewContactImportRepository(db domain.DBConn) *ContactImportRepository {
+	return &ContactImportRepository{
+		db: db,
+	}
+}
+
+// CreateContactImport inserts a new import job in the queued state and returns the created record.
+func (cir *ContactImportRepository) CreateContactImport(ctx context.Context, contactImport *models.ContactImport) (*models.ContactImport, error) {
+	const q = `
+		INSERT INTO contact_imports (user_id, filename, s3_key, mode, dry_run, status)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, user_id, filename, s3_key, mode, dry_run, status, rows_read, rows_inserted, rows_updated,
+		          rows_duplicate, rows_invalid, rows_deleted, report_key, deletions_key, error, created_at, updated_at
+	`
+
+	row := cir.db.QueryRow(ctx, q, contactImport.UserID, contactImport.Filename, contactImport.S3Key,
+		contactImport.Mode, contactImport.DryRun, models.ImportStatusQueued)
+	return scanContactImport(row)
+}
+
+// GetContactImportByID retrieves an import job owned by the user.
+// Returns domain.ErrContactImportNotExists if no row is found.
+func (cir *ContactImportRepository) GetContactImportByID(ctx context.Context, userID, importID int) (*models.ContactImport, error) {
+	const q = `
+		SELECT id, user_id, filename, s3_key, mode, dry_run, status, rows_read, rows_inserted, rows_updated,
+		       rows_duplicate, rows_invalid, rows_deleted, report_key, deletions_key, error, created_at, updated_at
+		FROM contact_imports
+		WHERE user_id = $1
+		  AND id = $2
+	`
+
+	ci, err := scanContactImport(cir.db.QueryRow(ctx, q, userID, importID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrContactImportNotExists
+		}
+
+		return nil, err
+	}
+
+	return ci, nil
+}
+
+// FailContactImport marks an import job as failed with the given reason.
+// Returns domain.ErrContactImportNotExists if no row is found.
+func (cir *ContactImportRepository) FailContactImport(ctx context.Context, importID int, reason string) error {
+	const q = `
+		UPDATE contact_imports
+		SET status     = $2,
+		    error      = $3,
+		    updated_at = now()
+		WHERE id = $1
+	`
+
+	res, err := cir.db.Exec(ctx, q, importID, models.ImportStatusFailed, reason)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrContactImportNotExists
+	}
+
+	return nil
+}
+
+func scanContactImport(row pgx.Row) (*models.ContactImport, error) {
+	var ci models.ContactImport
+
+	err := row.Scan(&ci.ID, &ci.UserID, &ci.Filename, &ci.S3Key, &ci.Mode, &ci.DryRun, &ci.Status, &ci.RowsRead,
+		&ci.RowsInserted, &ci.RowsUpdated, &ci.RowsDuplicate, &ci.RowsInvalid, &ci.RowsDeleted, &ci.ReportKey,
+		&ci.DeletionsKey, &ci.Error, &ci.CreationTime, &ci.UpdateTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ci, nil
+}
@@ -0,0 +1,61 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+func clearContactImports(t *testing.T, db *sql.DB) {
+	t.Helper()
+	_, err := db.Exec("TRUNCATE contact_imports CASCADE")
+	require.NoError(t, err)
+}
+
+func TestContactImportRepository(t *testing.T) {
+	t.Cleanup(func() { clearContactImports(t, testDB) })
+
+	fixtures := makeFixtures(t, testDB, "../../../../db/fixtures/users.yml")
+	require.NoError(t, fixtures.Load())
+
+	ctx := context.Background()
+	repo := repository.NewContactImportRepository(testPool)
+
+	created, err := repo.CreateContactImport(ctx, &models.ContactImport{
+		UserID:   1,
+		Filename: "contacts.csv",
+		S3Key:    "contacts/1_contacts.csv",
+		Mode:     models.ImportModeSync,
+		DryRun:   true,
+	})
+	require.NoError(t, err)
+	require.NotZero(t, created.ID)
+	require.Equal(t, models.ImportStatusQueued, created.Status)
+	require.Equal(t, "contacts/1_contacts.csv", created.S3Key)
+	require.Equal(t, models.ImportModeSync, created.Mode)
+	require.True(t, created.DryRun)
+	require.Zero(t, created.RowsRead)
+
+	got, err := repo.GetContactImportByID(ctx, 1, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, created, got)
+
+	_, err = repo.GetContactImportByID(ctx, 2, created.ID)
+	require.ErrorIs(t, err, domain.ErrContactImportNotExists)
+
+	require.NoError(t, repo.FailContactImport(ctx, created.ID, "failed to enqueue import"))
+	got, err = repo.GetContactImportByID(ctx, 1, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.ImportStatusFailed, got.Status)
+	require.Equal(t, "failed to enqueue import", got.Error)
+
+	require.ErrorIs(t, repo.FailContactImport(ctx, created.ID+1, "x"), domain.ErrContactImportNotExists)
+}
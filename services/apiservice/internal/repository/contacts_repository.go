@@ -2,7 +2,10 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
@@ -25,7 +28,7 @@ func NewContactsRepository(db domain.DBConn) *ContactsRepository {
 // GetAllContactsByUserID retrieves all contacts for a specific user identified by userID.
 func (cr *ContactsRepository) GetAllContactsByUserID(ctx context.Context, userID int) ([]*models.Contact, error) {
 	const q = `
-		SELECT id, user_id, name, phone, created_at, updated_at
+		SELECT id, user_id, name, phone, timezone, attributes, endpoints, location, created_at, updated_at
 		FROM contacts
 		WHERE user_id = $1
 	`
@@ -41,7 +44,7 @@ func (cr *ContactsRepository) GetAllContactsByUserID(ctx context.Context, userID
 	for rows.Next() {
 		var c models.Contact
 
-		err := rows.Scan(&c.ID, &c.UserID, &c.Name, &c.Phone, &c.CreationTime, &c.UpdateTime)
+		err := rows.Scan(&c.ID, &c.UserID, &c.Name, &c.Phone, &c.TimeZone, &c.Attributes, &c.Endpoints, &c.Location, &c.CreationTime, &c.UpdateTime)
 		if err != nil {
 			return nil, err
 		}
@@ -57,16 +60,17 @@ func (cr *ContactsRepository) GetAllContactsByUserID(ctx context.Context, userID
 	return contacts, nil
 }
 
-// GetContactsCountByUserID retrieves count of contacts belonging to the specified user.
-func (cr *ContactsRepository) GetContactsCountByUserID(ctx context.Context, userID int) (int, error) {
-	const q = `
+// GetContactsCountByUserID retrieves count of the user's contacts matching the filter.
+func (cr *ContactsRepository) GetContactsCountByUserID(ctx context.Context, userID int, filter domain.ContactFilter) (int, error) {
+	conds, args := contactFilterConds(userID, filter)
+	q := `
 		SELECT COUNT(*)
 		FROM contacts
-		WHERE user_id = $1
+		WHERE ` + strings.Join(conds, " AND ") + `
 	`
 
 	var count int
-	err := cr.db.QueryRow(ctx, q, userID).Scan(&count)
+	err := cr.db.QueryRow(ctx, q, args...).Scan(&count)
 	if err != nil {
 		return 0, err
 	}
@@ -74,31 +78,44 @@ func (cr *ContactsRepository) GetContactsCountByUserID(ctx context.Context, user
 	return count, nil
 }
 
-// GetContactsPageByUserID retrieves a paginated list of contacts for the specified user.
-// It applies the given limit and offset for pagination.
-func (cr *ContactsRepository) GetContactsPageByUserID(ctx context.Context, userID, limit, offset int) ([]*models.Contact, error) {
-	const q = `
-		SELECT id, user_id, name, phone, created_at, updated_at
+// contactSortColumns lists the columns contacts can be sorted by besides the ID.
+var contactSortColumns = map[string]sortColumn{
+	"name":      {expr: "name", cast: "text"},
+	"phone":     {expr: "phone", cast: "text"},
+	"createdAt": {expr: "created_at", cast: "timestamptz"},
+	"updatedAt": {expr: "updated_at", cast: "timestamptz"},
+}
+
+// GetContactsPageByUserID retrieves a page of the user's contacts matching the filter, along with
+// the cursor of the next page, which is empty on the last one. Pages are read by keyset when a
+// cursor is given, and by limit and offset otherwise.
+// Returns domain.ErrInvalidSort or domain.ErrInvalidCursor for an unsupported sort or a bad cursor.
+func (cr *ContactsRepository) GetContactsPageByUserID(ctx context.Context, userID int, filter domain.ContactFilter, page domain.PageRequest) ([]*models.Contact, string, error) {
+	conds, args := contactFilterConds(userID, filter)
+	clause, args, err := pageClause(contactSortColumns, page, conds, args)
+	if err != nil {
+		return nil, "", err
+	}
+	q := `
+		SELECT id, user_id, name, phone, timezone, attributes, endpoints, location, created_at, updated_at
 		FROM contacts
-		WHERE user_id = $1
-		ORDER BY id
-		LIMIT $2 OFFSET $3
+		` + clause + `
 	`
 
 	contacts := make([]*models.Contact, 0)
 
-	rows, err := cr.db.Query(ctx, q, userID, limit, offset)
+	rows, err := cr.db.Query(ctx, q, args...)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
 		var c models.Contact
 
-		err := rows.Scan(&c.ID, &c.UserID, &c.Name, &c.Phone, &c.CreationTime, &c.UpdateTime)
+		err := rows.Scan(&c.ID, &c.UserID, &c.Name, &c.Phone, &c.TimeZone, &c.Attributes, &c.Endpoints, &c.Location, &c.CreationTime, &c.UpdateTime)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 
 		contacts = append(contacts, &c)
@@ -106,17 +123,39 @@ func (cr *ContactsRepository) GetContactsPageByUserID(ctx context.Context, userI
 
 	err = rows.Err()
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	return contacts, nil
+	if len(contacts) <= page.Limit {
+		return contacts, "", nil
+	}
+
+	contacts = contacts[:page.Limit]
+	last := contacts[len(contacts)-1]
+	return contacts, nextCursor(page.Sort, last.ID, contactSortValue(last, page.Sort.Field)), nil
+}
+
+// contactSortValue returns the value of the sort column of the contact for its cursor.
+func contactSortValue(c *models.Contact, field string) string {
+	switch field {
+	case "name":
+		return c.Name
+	case "phone":
+		return c.Phone
+	case "createdAt":
+		return formatTime(c.CreationTime)
+	case "updatedAt":
+		return formatTime(c.UpdateTime)
+	default:
+		return ""
+	}
 }
 
 // GetContactByID retrieves a single contact for a user by its contact ID.
 // Returns domain.ErrContactNotExists if no row is found.
 func (cr *ContactsRepository) GetContactByID(ctx context.Context, userID int, contactID int) (*models.Contact, error) {
 	const q = `
-		SELECT id, user_id, name, phone, created_at, updated_at
+		SELECT id, user_id, name, phone, timezone, attributes, endpoints, location, created_at, updated_at
 		FROM contacts
 		WHERE user_id = $1
 		  AND id = $2
@@ -125,7 +164,7 @@ func (cr *ContactsRepository) GetContactByID(ctx context.Context, userID int, co
 	var c models.Contact
 
 	row := cr.db.QueryRow(ctx, q, userID, contactID)
-	err := row.Scan(&c.ID, &c.UserID, &c.Name, &c.Phone, &c.CreationTime, &c.UpdateTime)
+	err := row.Scan(&c.ID, &c.UserID, &c.Name, &c.Phone, &c.TimeZone, &c.Attributes, &c.Endpoints, &c.Location, &c.CreationTime, &c.UpdateTime)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrContactNotExists
@@ -141,15 +180,15 @@ func (cr *ContactsRepository) GetContactByID(ctx context.Context, userID int, co
 // If the unique constraint on (user_id, name, phone) is violated, returns domain.ErrContactAlreadyExists.
 func (cr *ContactsRepository) CreateContact(ctx context.Context, contact *models.Contact) (*models.Contact, error) {
 	const q = `
-		INSERT INTO contacts (user_id, name, phone)
-		VALUES ($1, $2, $3)
-		RETURNING id, user_id, name, phone, created_at, updated_at
+		INSERT INTO contacts (user_id, name, phone, timezone, attributes, endpoints, location)
+		VALUES ($1, $2, $3, $4, COALESCE($5::jsonb, '{}'), COALESCE($6::jsonb, '[]'), $7::point)
+		RETURNING id, user_id, name, phone, timezone, attributes, endpoints, location, created_at, updated_at
 	`
 
 	var c models.Contact
 
-	row := cr.db.QueryRow(ctx, q, contact.UserID, contact.Name, contact.Phone)
-	err := row.Scan(&c.ID, &c.UserID, &c.Name, &c.Phone, &c.CreationTime, &c.UpdateTime)
+	row := cr.db.QueryRow(ctx, q, contact.UserID, contact.Name, contact.Phone, contact.TimeZone, contact.Attributes, contact.Endpoints, contact.Location)
+	err := row.Scan(&c.ID, &c.UserID, &c.Name, &c.Phone, &c.TimeZone, &c.Attributes, &c.Endpoints, &c.Location, &c.CreationTime, &c.UpdateTime)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
@@ -162,7 +201,7 @@ func (cr *ContactsRepository) CreateContact(ctx context.Context, contact *models
 	return &c, nil
 }
 
-// UpdateContact modifies an existing contact's name and phone, updating its timestamp.
+// UpdateContact modifies an existing contact's name, phone, time zone, attributes, endpoints and location, updating its timestamp.
 // Returns domain.ErrContactNotExists if no row matches, or domain.ErrContactAlreadyExists on unique violation.
 func (cr *ContactsRepository) UpdateContact(ctx context.Context, userID int, contactID int, updatedContact *models.Contact) (*models.Contact, error) {
 	const q = `
@@ -170,16 +209,20 @@ func (cr *ContactsRepository) UpdateContact(ctx context.Context, userID int, con
 		SET user_id    = $1,
 			name       = $2,
 			phone      = $3,
+			timezone   = $4,
+			attributes = COALESCE($5::jsonb, '{}'),
+			endpoints  = COALESCE($6::jsonb, '[]'),
+			location   = $7::point,
 			updated_at = now()
-		WHERE id = $4
-		  AND user_id = $5
-		RETURNING id, user_id, name, phone, created_at, updated_at
+		WHERE id = $8
+		  AND user_id = $9
+		RETURNING id, user_id, name, phone, timezone, attributes, endpoints, location, created_at, updated_at
 	`
 
-	row := cr.db.QueryRow(ctx, q, updatedContact.UserID, updatedContact.Name, updatedContact.Phone, contactID, userID)
+	row := cr.db.QueryRow(ctx, q, updatedContact.UserID, updatedContact.Name, updatedContact.Phone, updatedContact.TimeZone, updatedContact.Attributes, updatedContact.Endpoints, updatedContact.Location, contactID, userID)
 
 	var c models.Contact
-	err := row.Scan(&c.ID, &c.UserID, &c.Name, &c.Phone, &c.CreationTime, &c.UpdateTime)
+	err := row.Scan(&c.ID, &c.UserID, &c.Name, &c.Phone, &c.TimeZone, &c.Attributes, &c.Endpoints, &c.Location, &c.CreationTime, &c.UpdateTime)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrContactNotExists
@@ -196,6 +239,52 @@ func (cr *ContactsRepository) UpdateContact(ctx context.Context, userID int, con
 	return &c, nil
 }
 
+// GetTakenContactAddresses returns those of the addresses that a contact of the user other than
+// exceptContactID already uses, as its phone number or as one of its endpoints.
+func (cr *ContactsRepository) GetTakenContactAddresses(ctx context.Context, userID, exceptContactID int, addresses []*models.ContactEndpoint) ([]*models.ContactEndpoint, error) {
+	const q = `
+		SELECT a.channel, a.address
+		FROM unnest($3::text[], $4::text[]) AS a(channel, address)
+		WHERE EXISTS (
+			SELECT 1
+			FROM contacts c
+			WHERE c.user_id = $1
+			  AND c.id <> $2
+			  AND ((a.channel = 'sms' AND c.phone = a.address)
+				OR c.endpoints @> jsonb_build_array(jsonb_build_object('channel', a.channel, 'address', a.address)))
+		)
+	`
+
+	channels := make([]string, len(addresses))
+	values := make([]string, len(addresses))
+	for i, a := range addresses {
+		channels[i], values[i] = string(a.Channel), a.Address
+	}
+
+	rows, err := cr.db.Query(ctx, q, userID, exceptContactID, channels, values)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	taken := make([]*models.ContactEndpoint, 0)
+	for rows.Next() {
+		var a models.ContactEndpoint
+		err := rows.Scan(&a.Channel, &a.Address)
+		if err != nil {
+			return nil, err
+		}
+		taken = append(taken, &a)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return taken, nil
+}
+
 // DeleteContact removes a contact record for a user. Returns domain.ErrContactNotExists if no deletion occurred.
 func (cr *ContactsRepository) DeleteContact(ctx context.Context, userID int, contactID int) error {
 	const q = `
@@ -216,3 +305,219 @@ func (cr *ContactsRepository) DeleteContact(ctx context.Context, userID int, con
 
 	return nil
 }
+
+// CreateContacts inserts the contacts of the user in one statement, so that either all of them
+// are written or none. Contacts whose phone number the user already has are skipped; the
+// created ones are returned.
+func (cr *ContactsRepository) CreateContacts(ctx context.Context, userID int, contacts []*models.Contact) ([]*models.Contact, error) {
+	const q = `
+		INSERT INTO contacts (user_id, name, phone, timezone, attributes, endpoints, location)
+		SELECT $1, c.name, c.phone, c.timezone, COALESCE(c.attributes::jsonb, '{}'), COALESCE(c.endpoints::jsonb, '[]'), c.location
+		FROM unnest($2::text[], $3::text[], $4::text[], $5::text[], $6::text[], $7::point[]) AS c(name, phone, timezone, attributes, endpoints, location)
+		ON CONFLICT DO NOTHING
+		RETURNING id, user_id, name, phone, timezone, attributes, endpoints, location, created_at, updated_at
+	`
+
+	names := make([]string, len(contacts))
+	phones := make([]string, len(contacts))
+	timeZones := make([]string, len(contacts))
+	attributes := make([]*string, len(contacts))
+	endpoints := make([]*string, len(contacts))
+	locations := make([]*models.Location, len(contacts))
+	for i, c := range contacts {
+		locations[i] = c.Location
+		names[i], phones[i], timeZones[i] = c.Name, c.Phone, c.TimeZone
+		if len(c.Attributes) > 0 {
+			data, err := json.Marshal(c.Attributes)
+			if err != nil {
+				return nil, err
+			}
+			attrs := string(data)
+			attributes[i] = &attrs
+		}
+		if len(c.Endpoints) > 0 {
+			data, err := json.Marshal(c.Endpoints)
+			if err != nil {
+				return nil, err
+			}
+			eps := string(data)
+			endpoints[i] = &eps
+		}
+	}
+
+	created := make([]*models.Contact, 0, len(contacts))
+
+	rows, err := cr.db.Query(ctx, q, userID, names, phones, timeZones, attributes, endpoints, locations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c models.Contact
+
+		err := rows.Scan(&c.ID, &c.UserID, &c.Name, &c.Phone, &c.TimeZone, &c.Attributes, &c.Endpoints, &c.Location, &c.CreationTime, &c.UpdateTime)
+		if err != nil {
+			return nil, err
+		}
+
+		created = append(created, &c)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// DeleteContacts removes the user's contacts matching the filter and returns how many were deleted.
+// An empty filter deletes every contact of the user.
+func (cr *ContactsRepository) DeleteContacts(ctx context.Context, userID int, filter domain.ContactFilter) (int, error) {
+	conds, args := contactFilterConds(userID, filter)
+	q := `
+		DELETE
+		FROM contacts
+		WHERE ` + strings.Join(conds, " AND ") + `
+	`
+
+	res, err := cr.db.Exec(ctx, q, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(res.RowsAffected()), nil
+}
+
+// SetContactsGroup sets the group attribute of the user's contacts matching the filter, or removes
+// it when group is empty, and returns how many contacts were changed.
+func (cr *ContactsRepository) SetContactsGroup(ctx context.Context, userID int, filter domain.ContactFilter, group string) (int, error) {
+	conds, args := contactFilterConds(userID, filter)
+	args = append(args, models.GroupAttribute, group)
+	q := fmt.Sprintf(`
+		UPDATE contacts
+		SET attributes = CASE
+		                     WHEN $%[2]d::text = '' THEN attributes - $%[1]d::text
+		                     ELSE attributes || jsonb_build_object($%[1]d::text, $%[2]d::text)
+		                 END,
+		    updated_at = now()
+		WHERE %[3]s
+	`, len(args)-1, len(args), strings.Join(conds, " AND "))
+
+	res, err := cr.db.Exec(ctx, q, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(res.RowsAffected()), nil
+}
+
+// GetContactAttributeNames returns the sorted names of every attribute set on the user's
+// contacts matching the filter.
+func (cr *ContactsRepository) GetContactAttributeNames(ctx context.Context, userID int, filter domain.ContactFilter) ([]string, error) {
+	conds, args := contactFilterConds(userID, filter)
+	q := `
+		SELECT DISTINCT jsonb_object_keys(attributes) AS name
+		FROM contacts
+		WHERE ` + strings.Join(conds, " AND ") + `
+		ORDER BY name
+	`
+
+	names := make([]string, 0)
+
+	rows, err := cr.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+
+		err := rows.Scan(&name)
+		if err != nil {
+			return nil, err
+		}
+
+		names = append(names, name)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+// StreamContacts calls fn for every contact of the user matching the filter, in ID order.
+// Rows are read from the database as fn consumes them, so the whole address book is never
+// held in memory. An error returned by fn stops the iteration and is returned as is.
+func (cr *ContactsRepository) StreamContacts(ctx context.Context, userID int, filter domain.ContactFilter, fn func(*models.Contact) error) error {
+	conds, args := contactFilterConds(userID, filter)
+	q := `
+		SELECT id, user_id, name, phone, timezone, attributes, endpoints, location, created_at, updated_at
+		FROM contacts
+		WHERE ` + strings.Join(conds, " AND ") + `
+		ORDER BY id
+	`
+
+	rows, err := cr.db.Query(ctx, q, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c models.Contact
+
+		err := rows.Scan(&c.ID, &c.UserID, &c.Name, &c.Phone, &c.TimeZone, &c.Attributes, &c.Endpoints, &c.Location, &c.CreationTime, &c.UpdateTime)
+		if err != nil {
+			return err
+		}
+
+		err = fn(&c)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// contactFilterConds returns the WHERE conditions selecting the user's contacts matching
+// the filter, together with their positional arguments.
+func contactFilterConds(userID int, filter domain.ContactFilter) ([]string, []any) {
+	conds := []string{"user_id = $1"}
+	args := []any{userID}
+
+	if len(filter.IDs) > 0 {
+		args = append(args, filter.IDs)
+		conds = append(conds, fmt.Sprintf("id = ANY($%d)", len(args)))
+	}
+	if filter.Search != "" {
+		args = append(args, "%"+escapeLike(filter.Search)+"%")
+		conds = append(conds, fmt.Sprintf("(name ILIKE $%[1]d OR phone LIKE $%[1]d)", len(args)))
+	}
+	if filter.Group != "" {
+		args = append(args, models.GroupAttribute, filter.Group)
+		conds = append(conds, fmt.Sprintf("attributes->>$%d::text = $%d", len(args)-1, len(args)))
+	}
+	if len(filter.Attributes) > 0 {
+		args = append(args, filter.Attributes)
+		conds = append(conds, fmt.Sprintf("attributes @> $%d::jsonb", len(args)))
+	}
+	if filter.Rule != nil {
+		var cond string
+		cond, args = segmentRuleCond(filter.Rule, args)
+		conds = append(conds, cond)
+	}
+	if filter.Area != nil {
+		var cond string
+		cond, args = areaCond(filter.Area, args)
+		conds = append(conds, cond)
+	}
+
+	return createdRangeConds(filter.CreatedFrom, filter.CreatedTo, conds, args)
+}
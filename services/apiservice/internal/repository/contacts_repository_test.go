@@ -6,7 +6,9 @@ package repository_test
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
@@ -53,7 +55,7 @@ func TestContactsRepository_GetContactsCountByUserID(t *testing.T) {
 	userID := 1
 	repo := repository.NewContactsRepository(testPool)
 
-	count, err := repo.GetContactsCountByUserID(ctx, userID)
+	count, err := repo.GetContactsCountByUserID(ctx, userID, domain.ContactFilter{})
 	require.NoError(t, err)
 	require.Equal(t, 0, count)
 
@@ -61,7 +63,7 @@ func TestContactsRepository_GetContactsCountByUserID(t *testing.T) {
 	_, err = repo.CreateContact(ctx, contact)
 	require.NoError(t, err)
 
-	count, err = repo.GetContactsCountByUserID(ctx, userID)
+	count, err = repo.GetContactsCountByUserID(ctx, userID, domain.ContactFilter{})
 	require.NoError(t, err)
 	require.Equal(t, 1, count)
 }
@@ -97,6 +99,55 @@ func TestCreateAndGetContact(t *testing.T) {
 		require.Equal(t, created.Name, got.Name)
 		require.Equal(t, created.Phone, got.Phone)
 	})
+
+	t.Run("create contact with time zone", func(t *testing.T) {
+		contact := &models.Contact{UserID: userID, Name: "Bob", Phone: "+155512345", TimeZone: "America/Chicago"}
+		created, err := repo.CreateContact(ctx, contact)
+		require.NoError(t, err)
+		got, err := repo.GetContactByID(ctx, userID, created.ID)
+		require.NoError(t, err)
+		require.Equal(t, "America/Chicago", got.TimeZone)
+	})
+
+	t.Run("create contact with attributes", func(t *testing.T) {
+		contact := &models.Contact{UserID: userID, Name: "Carol", Phone: "+155500001", Attributes: map[string]string{"Department": "IT"}}
+		created, err := repo.CreateContact(ctx, contact)
+		require.NoError(t, err)
+		require.Equal(t, contact.Attributes, created.Attributes)
+
+		created.Attributes = nil
+		updated, err := repo.UpdateContact(ctx, userID, created.ID, created)
+		require.NoError(t, err)
+		require.Empty(t, updated.Attributes)
+	})
+
+	t.Run("create contact with endpoints", func(t *testing.T) {
+		endpoints := []*models.ContactEndpoint{
+			{Channel: models.ChannelSMS, Address: "+155500003", Label: "personal"},
+			{Channel: models.ChannelEmail, Address: "dave@example.com"},
+		}
+		contact := &models.Contact{UserID: userID, Name: "Dave", Phone: "+155500002", Endpoints: endpoints}
+		created, err := repo.CreateContact(ctx, contact)
+		require.NoError(t, err)
+		require.Equal(t, endpoints, created.Endpoints)
+
+		created.Endpoints = endpoints[1:]
+		updated, err := repo.UpdateContact(ctx, userID, created.ID, created)
+		require.NoError(t, err)
+		require.Equal(t, endpoints[1:], updated.Endpoints)
+	})
+
+	t.Run("create contact with location", func(t *testing.T) {
+		contact := &models.Contact{UserID: userID, Name: "Erin", Phone: "+155500004", Location: &models.Location{Lat: 55.75, Lon: 37.62}}
+		created, err := repo.CreateContact(ctx, contact)
+		require.NoError(t, err)
+		require.Equal(t, &models.Location{Lat: 55.75, Lon: 37.62}, created.Location)
+
+		created.Location = nil
+		updated, err := repo.UpdateContact(ctx, userID, created.ID, created)
+		require.NoError(t, err)
+		require.Nil(t, updated.Location)
+	})
 }
 
 func TestContactsRepository_GetContactsByUserID(t *testing.T) {
@@ -118,8 +169,9 @@ func TestContactsRepository_GetContactsByUserID(t *testing.T) {
 	_, err = repo.CreateContact(ctx, contactB)
 	require.NoError(t, err)
 
-	contacts, err := repo.GetContactsPageByUserID(ctx, userID, 100, 0)
+	contacts, next, err := repo.GetContactsPageByUserID(ctx, userID, domain.ContactFilter{}, domain.PageRequest{Limit: 100})
 	require.NoError(t, err)
+	require.Empty(t, next)
 	require.GreaterOrEqual(t, len(contacts), 2)
 	names := []string{contacts[0].Name, contacts[1].Name}
 	require.Contains(t, names, "Alpha")
@@ -248,3 +300,260 @@ func TestContactsRepository_DeleteContact_WrongUser(t *testing.T) {
 	err = repo.DeleteContact(ctx, userID+1, created.ID)
 	require.ErrorIs(t, err, domain.ErrContactNotExists)
 }
+
+func TestContactsRepository_StreamContacts(t *testing.T) {
+	t.Cleanup(func() { clearContacts(t, testDB) })
+
+	fixtures := makeFixtures(t, testDB, "../../../../db/fixtures/users.yml")
+	require.NoError(t, fixtures.Load())
+
+	ctx := context.Background()
+	repo := repository.NewContactsRepository(testPool)
+
+	for _, c := range []*models.Contact{
+		{UserID: 1, Name: "Alice", Phone: "+79123456789", Attributes: map[string]string{"Group": "Sales", "City": "Moscow"}},
+		{UserID: 1, Name: "Bob", Phone: "+79123456788", Attributes: map[string]string{"Group": "IT"}},
+		{UserID: 1, Name: "Carol_1", Phone: "+79123456787", Attributes: map[string]string{"Group": "Sales", "Floor": "2"}},
+		{UserID: 2, Name: "Dave", Phone: "+79123456786", Attributes: map[string]string{"Group": "Sales", "Desk": "4"}},
+	} {
+		_, err := repo.CreateContact(ctx, c)
+		require.NoError(t, err)
+	}
+
+	stream := func(filter domain.ContactFilter) []string {
+		names := make([]string, 0)
+		err := repo.StreamContacts(ctx, 1, filter, func(c *models.Contact) error {
+			names = append(names, c.Name)
+			return nil
+		})
+		require.NoError(t, err)
+		return names
+	}
+
+	require.Equal(t, []string{"Alice", "Bob", "Carol_1"}, stream(domain.ContactFilter{}))
+	require.Equal(t, []string{"Alice", "Carol_1"}, stream(domain.ContactFilter{Group: "Sales"}))
+	require.Equal(t, []string{"Alice", "Carol_1"}, stream(domain.ContactFilter{Search: "A"}))
+	require.Equal(t, []string{"Carol_1"}, stream(domain.ContactFilter{Search: "_1"}))
+	require.Equal(t, []string{"Bob"}, stream(domain.ContactFilter{Search: "788"}))
+	require.Empty(t, stream(domain.ContactFilter{Search: "%"}))
+
+	names, err := repo.GetContactAttributeNames(ctx, 1, domain.ContactFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"City", "Floor", "Group"}, names)
+
+	names, err = repo.GetContactAttributeNames(ctx, 1, domain.ContactFilter{Group: "IT"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Group"}, names)
+
+	errStop := errors.New("stop")
+	err = repo.StreamContacts(ctx, 1, domain.ContactFilter{}, func(*models.Contact) error {
+		return errStop
+	})
+	require.ErrorIs(t, err, errStop)
+}
+
+func TestContactsRepository_GetContactsPageByUserID_Keyset(t *testing.T) {
+	t.Cleanup(func() { clearContacts(t, testDB) })
+
+	fixtures := makeFixtures(t, testDB, "../../../../db/fixtures/users.yml")
+	require.NoError(t, fixtures.Load())
+
+	ctx := context.Background()
+	repo := repository.NewContactsRepository(testPool)
+
+	for _, c := range []*models.Contact{
+		{UserID: 1, Name: "Carol", Phone: "+79123456781", Attributes: map[string]string{"City": "Moscow"}},
+		{UserID: 1, Name: "Alice", Phone: "+79123456782", Attributes: map[string]string{"City": "Kazan"}},
+		{UserID: 1, Name: "Bob", Phone: "+79123456783", Attributes: map[string]string{"City": "Moscow"}},
+		{UserID: 1, Name: "Alice", Phone: "+79123456784"},
+		{UserID: 1, Name: "Dave", Phone: "+79123456785"},
+	} {
+		_, err := repo.CreateContact(ctx, c)
+		require.NoError(t, err)
+	}
+
+	readAll := func(filter domain.ContactFilter, sort domain.Sort) []string {
+		phones := make([]string, 0)
+		page := domain.PageRequest{Limit: 2, Sort: sort}
+		for {
+			contacts, next, err := repo.GetContactsPageByUserID(ctx, 1, filter, page)
+			require.NoError(t, err)
+			for _, c := range contacts {
+				phones = append(phones, c.Phone)
+			}
+			if next == "" {
+				return phones
+			}
+			page.Cursor = next
+		}
+	}
+
+	require.Equal(t,
+		[]string{"+79123456781", "+79123456782", "+79123456783", "+79123456784", "+79123456785"},
+		readAll(domain.ContactFilter{}, domain.Sort{}))
+	require.Equal(t,
+		[]string{"+79123456782", "+79123456784", "+79123456783", "+79123456781", "+79123456785"},
+		readAll(domain.ContactFilter{}, domain.Sort{Field: "name"}))
+	require.Equal(t,
+		[]string{"+79123456785", "+79123456784", "+79123456783", "+79123456782", "+79123456781"},
+		readAll(domain.ContactFilter{}, domain.Sort{Field: "createdAt", Desc: true}))
+	require.Equal(t,
+		[]string{"+79123456781", "+79123456783"},
+		readAll(domain.ContactFilter{Attributes: map[string]string{"City": "Moscow"}}, domain.Sort{Field: "phone"}))
+
+	count, err := repo.GetContactsCountByUserID(ctx, 1, domain.ContactFilter{Search: "alice"})
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	count, err = repo.GetContactsCountByUserID(ctx, 1, domain.ContactFilter{CreatedFrom: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	_, next, err := repo.GetContactsPageByUserID(ctx, 1, domain.ContactFilter{}, domain.PageRequest{Limit: 2, Sort: domain.Sort{Field: "name"}})
+	require.NoError(t, err)
+	_, _, err = repo.GetContactsPageByUserID(ctx, 1, domain.ContactFilter{}, domain.PageRequest{Limit: 2, Cursor: next})
+	require.ErrorIs(t, err, domain.ErrInvalidCursor)
+
+	_, _, err = repo.GetContactsPageByUserID(ctx, 1, domain.ContactFilter{}, domain.PageRequest{Limit: 2, Cursor: "!!"})
+	require.ErrorIs(t, err, domain.ErrInvalidCursor)
+
+	_, _, err = repo.GetContactsPageByUserID(ctx, 1, domain.ContactFilter{}, domain.PageRequest{Limit: 2, Sort: domain.Sort{Field: "timezone"}})
+	require.ErrorIs(t, err, domain.ErrInvalidSort)
+}
+
+func TestContactsRepository_CreateContacts(t *testing.T) {
+	t.Cleanup(func() { clearContacts(t, testDB) })
+
+	fixtures := makeFixtures(t, testDB, "../../../../db/fixtures/users.yml")
+	require.NoError(t, fixtures.Load())
+
+	ctx := context.Background()
+	repo := repository.NewContactsRepository(testPool)
+
+	_, err := repo.CreateContact(ctx, &models.Contact{UserID: 1, Name: "Existing", Phone: "+79123456789"})
+	require.NoError(t, err)
+
+	created, err := repo.CreateContacts(ctx, 1, []*models.Contact{
+		{UserID: 1, Name: "Alice", Phone: "+79123456789"},
+		{UserID: 1, Name: "Bob", Phone: "+79123456788", TimeZone: "Europe/Moscow", Attributes: map[string]string{"Group": "IT"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	require.Equal(t, "Bob", created[0].Name)
+	require.Equal(t, "Europe/Moscow", created[0].TimeZone)
+	require.Equal(t, map[string]string{"Group": "IT"}, created[0].Attributes)
+
+	total, err := repo.GetContactsCountByUserID(ctx, 1, domain.ContactFilter{})
+	require.NoError(t, err)
+	require.Equal(t, 2, total)
+}
+
+func TestContactsRepository_DeleteContacts(t *testing.T) {
+	t.Cleanup(func() { clearContacts(t, testDB) })
+
+	fixtures := makeFixtures(t, testDB, "../../../../db/fixtures/users.yml")
+	require.NoError(t, fixtures.Load())
+
+	ctx := context.Background()
+	repo := repository.NewContactsRepository(testPool)
+
+	created, err := repo.CreateContacts(ctx, 1, []*models.Contact{
+		{UserID: 1, Name: "Alice", Phone: "+79123456789", Attributes: map[string]string{"Group": "Sales"}},
+		{UserID: 1, Name: "Bob", Phone: "+79123456788", Attributes: map[string]string{"Group": "IT"}},
+		{UserID: 1, Name: "Carol", Phone: "+79123456787", Attributes: map[string]string{"Group": "Sales"}},
+	})
+	require.NoError(t, err)
+	_, err = repo.CreateContact(ctx, &models.Contact{UserID: 2, Name: "Dave", Phone: "+79123456786"})
+	require.NoError(t, err)
+
+	// other users' contacts are never matched by IDs
+	n, err := repo.DeleteContacts(ctx, 2, domain.ContactFilter{IDs: []int{created[0].ID}})
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+
+	n, err = repo.DeleteContacts(ctx, 1, domain.ContactFilter{IDs: []int{created[1].ID}})
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	n, err = repo.DeleteContacts(ctx, 1, domain.ContactFilter{Group: "Sales"})
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	// an empty filter deletes the rest of the user's contacts only
+	n, err = repo.DeleteContacts(ctx, 2, domain.ContactFilter{})
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+}
+
+func TestContactsRepository_SetContactsGroup(t *testing.T) {
+	t.Cleanup(func() { clearContacts(t, testDB) })
+
+	fixtures := makeFixtures(t, testDB, "../../../../db/fixtures/users.yml")
+	require.NoError(t, fixtures.Load())
+
+	ctx := context.Background()
+	repo := repository.NewContactsRepository(testPool)
+
+	created, err := repo.CreateContacts(ctx, 1, []*models.Contact{
+		{UserID: 1, Name: "Alice", Phone: "+79123456789", Attributes: map[string]string{"City": "Moscow"}},
+		{UserID: 1, Name: "Bob", Phone: "+79123456788", Attributes: map[string]string{"Group": "IT"}},
+	})
+	require.NoError(t, err)
+
+	n, err := repo.SetContactsGroup(ctx, 1, domain.ContactFilter{IDs: []int{created[0].ID, created[1].ID}}, "Sales")
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	alice, err := repo.GetContactByID(ctx, 1, created[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"City": "Moscow", "Group": "Sales"}, alice.Attributes)
+
+	n, err = repo.SetContactsGroup(ctx, 1, domain.ContactFilter{Search: "bob"}, "")
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	bob, err := repo.GetContactByID(ctx, 1, created[1].ID)
+	require.NoError(t, err)
+	require.Empty(t, bob.Attributes)
+}
+
+func TestContactsRepository_GetTakenContactAddresses(t *testing.T) {
+	t.Cleanup(func() { clearContacts(t, testDB) })
+
+	fixtures := makeFixtures(t, testDB, "../../../../db/fixtures/users.yml")
+	require.NoError(t, fixtures.Load())
+
+	ctx := context.Background()
+	repo := repository.NewContactsRepository(testPool)
+
+	created, err := repo.CreateContact(ctx, &models.Contact{
+		UserID: 1,
+		Name:   "Ivan",
+		Phone:  "+79123456789",
+		Endpoints: []*models.ContactEndpoint{
+			{Channel: models.ChannelSMS, Address: "+79123456780", Label: "personal"},
+			{Channel: models.ChannelEmail, Address: "ivan@example.com"},
+		},
+	})
+	require.NoError(t, err)
+
+	addresses := []*models.ContactEndpoint{
+		{Channel: models.ChannelSMS, Address: "+79123456789"},
+		{Channel: models.ChannelSMS, Address: "+79123456780"},
+		{Channel: models.ChannelEmail, Address: "ivan@example.com"},
+		{Channel: models.ChannelEmail, Address: "+79123456780"},
+		{Channel: models.ChannelSMS, Address: "+79123456781"},
+	}
+
+	taken, err := repo.GetTakenContactAddresses(ctx, 1, 0, addresses)
+	require.NoError(t, err)
+	require.ElementsMatch(t, addresses[:3], taken)
+
+	taken, err = repo.GetTakenContactAddresses(ctx, 1, created.ID, addresses)
+	require.NoError(t, err)
+	require.Empty(t, taken)
+
+	taken, err = repo.GetTakenContactAddresses(ctx, 2, 0, addresses)
+	require.NoError(t, err)
+	require.Empty(t, taken)
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+)
+
+// sortColumn is a column a listing can be sorted by.
+type sortColumn struct {
+	// expr is the SQL expression rows are ordered by.
+	expr string
+	// cast is the SQL type the cursor value is cast to.
+	cast string
+}
+
+// cursor marks the last row of a page. Sort is the sort the page was read in, so a cursor
+// cannot be reused with another order; Value is the sort column of the row and ID breaks ties.
+type cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v,omitempty"`
+	ID    int    `json:"id"`
+}
+
+func sortKey(s domain.Sort) string {
+	field := s.Field
+	if field == "" {
+		field = "id"
+	}
+	if s.Desc {
+		return "-" + field
+	}
+	return field
+}
+
+func encodeCursor(c cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string) (cursor, error) {
+	var c cursor
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, domain.ErrInvalidCursor
+	}
+	err = json.Unmarshal(data, &c)
+	if err != nil {
+		return c, domain.ErrInvalidCursor
+	}
+
+	return c, nil
+}
+
+// pageClause appends the keyset condition of the page to conds and returns the WHERE,
+// ORDER BY and LIMIT/OFFSET clauses with their arguments. One row more than the limit is
+// requested so that the caller can tell whether another page follows.
+// Returns domain.ErrInvalidSort for fields missing from columns and domain.ErrInvalidCursor
+// for cursors that are malformed or were issued for another sort.
+func pageClause(columns map[string]sortColumn, page domain.PageRequest, conds []string, args []any) (string, []any, error) {
+	var col sortColumn
+	if page.Sort.Field != "" && page.Sort.Field != "id" {
+		c, ok := columns[page.Sort.Field]
+		if !ok {
+			return "", nil, domain.ErrInvalidSort
+		}
+		col = c
+	}
+
+	op, dir := ">", "ASC"
+	if page.Sort.Desc {
+		op, dir = "<", "DESC"
+	}
+
+	offset := page.Offset
+	if page.Cursor != "" {
+		c, err := decodeCursor(page.Cursor)
+		if err != nil {
+			return "", nil, err
+		}
+		if c.Sort != sortKey(page.Sort) {
+			return "", nil, domain.ErrInvalidCursor
+		}
+
+		if col.expr == "" {
+			args = append(args, c.ID)
+			conds = append(conds, fmt.Sprintf("id %s $%d", op, len(args)))
+		} else {
+			args = append(args, c.Value, c.ID)
+			conds = append(conds, fmt.Sprintf("(%s, id) %s ($%d::%s, $%d)", col.expr, op, len(args)-1, col.cast, len(args)))
+		}
+		offset = 0
+	}
+
+	order := "id " + dir
+	if col.expr != "" {
+		order = col.expr + " " + dir + ", " + order
+	}
+
+	args = append(args, page.Limit+1, offset)
+	clause := fmt.Sprintf("WHERE %s\n\t\tORDER BY %s\n\t\tLIMIT $%d OFFSET $%d", strings.Join(conds, " AND "), order, len(args)-1, len(args))
+
+	return clause, args, nil
+}
+
+// createdRangeConds appends the conditions bounding created_at to [from, to) to conds.
+// Zero times leave the range open.
+func createdRangeConds(from, to time.Time, conds []string, args []any) ([]string, []any) {
+	if !from.IsZero() {
+		args = append(args, from)
+		conds = append(conds, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+	if !to.IsZero() {
+		args = append(args, to)
+		conds = append(conds, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+	return conds, args
+}
+
+// nextCursor returns the cursor continuing after the row with the given ID and sort value.
+func nextCursor(sort domain.Sort, id int, value string) string {
+	return encodeCursor(cursor{Sort: sortKey(sort), Value: value, ID: id})
+}
+
+// escapeLike escapes the LIKE wildcards of s so that it matches literally.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// formatTime formats timestamps in cursors losslessly for PostgreSQL.
+func formatTime(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
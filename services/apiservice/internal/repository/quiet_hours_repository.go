@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// QuietHoursRepository handles operations on the quiet_hours table.
+type QuietHoursRepository struct {
+	db domain.DBConn
+}
+
+// NewQuietHoursRepository constructs a QuietHoursRepository using the provided DB connection.
+func NewQuietHoursRepository(db domain.DBConn) *QuietHoursRepository {
+	return &QuietHoursRepository{
+		db: db,
+	}
+}
+
+// GetQuietHoursByUserID retrieves the quiet hours configured by the user.
+// Returns domain.ErrQuietHoursNotExists if the user has not configured them.
+func (qhr *QuietHoursRepository) GetQuietHoursByUserID(ctx context.Context, userID int) (*models.QuietHours, error) {
+	const q = `
+		SELECT start_minute, end_minute, timezone
+		FROM quiet_hours
+		WHERE user_id = $1
+	`
+
+	var qh models.QuietHours
+
+	err := qhr.db.QueryRow(ctx, q, userID).Scan(&qh.StartMinute, &qh.EndMinute, &qh.TimeZone)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrQuietHoursNotExists
+		}
+
+		return nil, err
+	}
+
+	return &qh, nil
+}
+
+// UpsertQuietHours creates or replaces the quiet hours of the user and returns the stored record.
+func (qhr *QuietHoursRepository) UpsertQuietHours(ctx context.Context, userID int, qh *models.QuietHours) (*models.QuietHours, error) {
+	const q = `
+		INSERT INTO quiet_hours (user_id, start_minute, end_minute, timezone)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE
+		SET start_minute = EXCLUDED.start_minute,
+		    end_minute   = EXCLUDED.end_minute,
+		    timezone     = EXCLUDED.timezone,
+		    updated_at   = now()
+		RETURNING start_minute, end_minute, timezone
+	`
+
+	var stored models.QuietHours
+
+	err := qhr.db.QueryRow(ctx, q, userID, qh.StartMinute, qh.EndMinute, qh.TimeZone).Scan(&stored.StartMinute, &stored.EndMinute, &stored.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stored, nil
+}
+
+// DeleteQuietHours removes the quiet hours of the user.
+// Returns domain.ErrQuietHoursNotExists if no row was deleted.
+func (qhr *QuietHoursRepository) DeleteQuietHours(ctx context.Context, userID int) error {
+	const q = `
+		DELETE
+		FROM quiet_hours
+		WHERE user_id = $1
+	`
+
+	res, err := qhr.db.Exec(ctx, q, userID)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return domain.ErrQuietHoursNotExists
+	}
+
+	return nil
+}
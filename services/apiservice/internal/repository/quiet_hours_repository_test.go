@@ -0,0 +1,52 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+func clearQuietHours(t *testing.T, db *sql.DB) {
+	t.Helper()
+	_, err := db.Exec("TRUNCATE quiet_hours")
+	require.NoError(t, err)
+}
+
+func TestQuietHoursRepository_CRUD(t *testing.T) {
+	t.Cleanup(func() { clearQuietHours(t, testDB) })
+
+	fixtures := makeFixtures(t, testDB, "../../../../db/fixtures/users.yml")
+	require.NoError(t, fixtures.Load())
+
+	ctx := context.Background()
+	userID := 1
+	repo := repository.NewQuietHoursRepository(testPool)
+
+	_, err := repo.GetQuietHoursByUserID(ctx, userID)
+	require.ErrorIs(t, err, domain.ErrQuietHoursNotExists)
+
+	qh := &models.QuietHours{StartMinute: 22 * 60, EndMinute: 7 * 60, TimeZone: "Europe/Moscow"}
+	created, err := repo.UpsertQuietHours(ctx, userID, qh)
+	require.NoError(t, err)
+	require.Equal(t, qh, created)
+
+	qh.StartMinute = 23 * 60
+	updated, err := repo.UpsertQuietHours(ctx, userID, qh)
+	require.NoError(t, err)
+	require.Equal(t, qh, updated)
+
+	got, err := repo.GetQuietHoursByUserID(ctx, userID)
+	require.NoError(t, err)
+	require.Equal(t, qh, got)
+
+	require.NoError(t, repo.DeleteQuietHours(ctx, userID))
+	require.ErrorIs(t, repo.DeleteQuietHours(ctx, userID), domain.ErrQuietHoursNotExists)
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// RetryPolicyRepository handles operations on the retry_policies table.
+type RetryPolicyRepository struct {
+	db domain.DBConn
+}
+
+// NewRetryPolicyRepository constructs a RetryPolicyRepository using the provided DB connection.
+func NewRetryPolicyRepository(db domain.DBConn) *RetryPolicyRepository {
+	return &RetryPolicyRepository{
+		db: db,
+	}
+}
+
+// GetRetryPolicyByUserID retrieves the default retry policy stored for the user.
+// Returns domain.ErrRetryPolicyNotExists if the user has not stored one.
+func (rpr *RetryPolicyRepository) GetRetryPolicyByUserID(ctx context.Context, userID int) (*models.RetryPolicy, error) {
+	const q = `
+		SELECT max_attempts, backoff, base_delay_ms, max_delay_ms, jitter, stale_after_ms
+		FROM retry_policies
+		WHERE user_id = $1
+	`
+
+	var p models.RetryPolicy
+
+	row := rpr.db.QueryRow(ctx, q, userID)
+	err := row.Scan(&p.MaxAttempts, &p.Backoff, &p.BaseDelayMs, &p.MaxDelayMs, &p.Jitter, &p.StaleAfterMs)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrRetryPolicyNotExists
+		}
+
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// UpsertRetryPolicy creates or replaces the default retry policy of the user and returns the stored record.
+func (rpr *RetryPolicyRepository) UpsertRetryPolicy(ctx context.Context, userID int, policy *models.RetryPolicy) (*models.RetryPolicy, error) {
+	const q = `
+		INSERT INTO retry_policies (user_id, max_attempts, backoff, base_delay_ms, max_delay_ms, jitter, stale_after_ms)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id) DO UPDATE
+		SET max_attempts   = EXCLUDED.max_attempts,
+		    backoff        = EXCLUDED.backoff,
+		    base_delay_ms  = EXCLUDED.base_delay_ms,
+		    max_delay_ms   = EXCLUDED.max_delay_ms,
+		    jitter         = EXCLUDED.jitter,
+		    stale_after_ms = EXCLUDED.stale_after_ms,
+		    updated_at     = now()
+		RETURNING max_attempts, backoff, base_delay_ms, max_delay_ms, jitter, stale_after_ms
+	`
+
+	var p models.RetryPolicy
+
+	row := rpr.db.QueryRow(ctx, q, userID, policy.MaxAttempts, policy.Backoff, policy.BaseDelayMs, policy.MaxDelayMs, policy.Jitter, policy.StaleAfterMs)
+	err := row.Scan(&p.MaxAttempts, &p.Backoff, &p.BaseDelayMs, &p.MaxDelayMs, &p.Jitter, &p.StaleAfterMs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
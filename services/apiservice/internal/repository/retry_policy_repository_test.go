@@ -0,0 +1,57 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+func clearRetryPolicies(t *testing.T, db *sql.DB) {
+	t.Helper()
+	_, err := db.Exec("TRUNCATE retry_policies")
+	require.NoError(t, err)
+}
+
+func TestRetryPolicyRepository_GetAndUpsert(t *testing.T) {
+	t.Cleanup(func() { clearRetryPolicies(t, testDB) })
+
+	fixtures := makeFixtures(t, testDB, "../../../../db/fixtures/users.yml")
+	require.NoError(t, fixtures.Load())
+
+	ctx := context.Background()
+	userID := 1
+	repo := repository.NewRetryPolicyRepository(testPool)
+
+	_, err := repo.GetRetryPolicyByUserID(ctx, userID)
+	require.ErrorIs(t, err, domain.ErrRetryPolicyNotExists)
+
+	policy := &models.RetryPolicy{
+		MaxAttempts:  5,
+		Backoff:      models.BackoffExponential,
+		BaseDelayMs:  1000,
+		MaxDelayMs:   300_000,
+		Jitter:       0.2,
+		StaleAfterMs: 300_000,
+	}
+	created, err := repo.UpsertRetryPolicy(ctx, userID, policy)
+	require.NoError(t, err)
+	require.Equal(t, policy, created)
+
+	policy.MaxAttempts = 10
+	policy.Backoff = models.BackoffLinear
+	updated, err := repo.UpsertRetryPolicy(ctx, userID, policy)
+	require.NoError(t, err)
+	require.Equal(t, policy, updated)
+
+	got, err := repo.GetRetryPolicyByUserID(ctx, userID)
+	require.NoError(t, err)
+	require.Equal(t, policy, got)
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/segmentrule"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// SegmentRepository handles CRUD operations on the segments table.
+type SegmentRepository struct {
+	db domain.DBConn
+}
+
+// NewSegmentRepository constructs a SegmentRepository using the provided DB connection.
+func NewSegmentRepository(db domain.DBConn) *SegmentRepository {
+	return &SegmentRepository{
+		db: db,
+	}
+}
+
+// GetSegmentsByUserID retrieves every segment of the user, ordered by name.
+func (sr *SegmentRepository) GetSegmentsByUserID(ctx context.Context, userID int) ([]*models.Segment, error) {
+	const q = `
+		SELECT id, user_id, name, rule, created_at, updated_at
+		FROM segments
+		WHERE user_id = $1
+		ORDER BY lower(name)
+	`
+
+	rows, err := sr.db.Query(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	segments := make([]*models.Segment, 0)
+	for rows.Next() {
+		var s models.Segment
+
+		err := rows.Scan(&s.ID, &s.UserID, &s.Name, &s.Rule, &s.CreationTime, &s.UpdateTime)
+		if err != nil {
+			return nil, err
+		}
+
+		segments = append(segments, &s)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return segments, nil
+}
+
+// GetSegmentByID retrieves a segment by its ID and user ID.
+// Returns domain.ErrSegmentNotExists if the user has no such segment.
+func (sr *SegmentRepository) GetSegmentByID(ctx context.Context, userID, segmentID int) (*models.Segment, error) {
+	const q = `
+		SELECT id, user_id, name, rule, created_at, updated_at
+		FROM segments
+		WHERE id = $1
+		  AND user_id = $2
+	`
+
+	var s models.Segment
+
+	row := sr.db.QueryRow(ctx, q, segmentID, userID)
+	err := row.Scan(&s.ID, &s.UserID, &s.Name, &s.Rule, &s.CreationTime, &s.UpdateTime)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrSegmentNotExists
+		}
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// CreateSegment inserts a new segment and returns the created record.
+// Returns domain.ErrSegmentAlreadyExists if the user has a segment with the same name.
+func (sr *SegmentRepository) CreateSegment(ctx context.Context, segment *models.Segment) (*models.Segment, error) {
+	const q = `
+		INSERT INTO segments (user_id, name, rule)
+		VALUES ($1, $2, $3)
+		RETURNING id, user_id, name, rule, created_at, updated_at
+	`
+
+	var s models.Segment
+
+	row := sr.db.QueryRow(ctx, q, segment.UserID, segment.Name, segment.Rule)
+	err := row.Scan(&s.ID, &s.UserID, &s.Name, &s.Rule, &s.CreationTime, &s.UpdateTime)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, domain.ErrSegmentAlreadyExists
+		}
+
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// UpdateSegment replaces the name and rule of an existing segment.
+// Returns domain.ErrSegmentNotExists if no segment was updated.
+func (sr *SegmentRepository) UpdateSegment(ctx context.Context, userID, segmentID int, segment *models.Segment) (*models.Segment, error) {
+	const q = `
+		UPDATE segments
+		SET name       = $1,
+		    rule       = $2,
+		    updated_at = now()
+		WHERE id = $3
+		  AND user_id = $4
+		RETURNING id, user_id, name, rule, created_at, updated_at
+	`
+
+	var s models.Segment
+
+	row := sr.db.QueryRow(ctx, q, segment.Name, segment.Rule, segmentID, userID)
+	err := row.Scan(&s.ID, &s.UserID, &s.Name, &s.Rule, &s.CreationTime, &s.UpdateTime)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrSegmentNotExists
+		}
+
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, domain.ErrSegmentAlreadyExists
+		}
+
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// DeleteSegment removes a segment by ID and user ID.
+// Returns domain.ErrSegmentNotExists if no row was deleted.
+func (sr *SegmentRepository) DeleteSegment(ctx context.Context, userID, segmentID int) error {
+	const q = `
+		DELETE
+		FROM segments
+		WHERE id = $1
+		  AND user_id = $2
+	`
+
+	res, err := sr.db.Exec(ctx, q, segmentID, userID)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return domain.ErrSegmentNotExists
+	}
+
+	return nil
+}
+
+// segmentRuleCond compiles a segment rule into a condition on the contacts table. Fields and
+// values are always passed as positional arguments appended to args, never spliced into the
+// query. Comparisons of attributes a contact doesn't have are false, so != and NOT match it.
+func segmentRuleCond(e *segmentrule.Expr, args []any) (string, []any) {
+	switch e.Op {
+	case segmentrule.OpAnd, segmentrule.OpOr:
+		conds := make([]string, 0, len(e.Operands))
+		for _, o := range e.Operands {
+			var cond string
+			cond, args = segmentRuleCond(o, args)
+			conds = append(conds, cond)
+		}
+		join := " AND "
+		if e.Op == segmentrule.OpOr {
+			join = " OR "
+		}
+		return "(" + strings.Join(conds, join) + ")", args
+	case segmentrule.OpNot:
+		cond, args := segmentRuleCond(e.Operands[0], args)
+		return "NOT " + cond, args
+	}
+
+	var column string
+	switch e.Field {
+	case segmentrule.FieldName:
+		column = "name"
+	case segmentrule.FieldPhone:
+		column = "phone"
+	}
+
+	if column != "" {
+		switch e.Op {
+		case segmentrule.OpEq:
+			args = append(args, e.Values[0])
+			return fmt.Sprintf("%s = $%d", column, len(args)), args
+		case segmentrule.OpNe:
+			args = append(args, e.Values[0])
+			return fmt.Sprintf("%s <> $%d", column, len(args)), args
+		case segmentrule.OpIn:
+			args = append(args, e.Values)
+			return fmt.Sprintf("%s = ANY($%d::text[])", column, len(args)), args
+		default:
+			args = append(args, "%"+escapeLike(e.Values[0])+"%")
+			return fmt.Sprintf("%s ILIKE $%d", column, len(args)), args
+		}
+	}
+
+	switch e.Op {
+	case segmentrule.OpEq:
+		args = append(args, map[string]string{e.Field: e.Values[0]})
+		return fmt.Sprintf("attributes @> $%d::jsonb", len(args)), args
+	case segmentrule.OpNe:
+		args = append(args, map[string]string{e.Field: e.Values[0]})
+		return fmt.Sprintf("NOT attributes @> $%d::jsonb", len(args)), args
+	case segmentrule.OpIn:
+		args = append(args, e.Field, e.Values)
+		return fmt.Sprintf("COALESCE(attributes->>$%d::text = ANY($%d::text[]), false)", len(args)-1, len(args)), args
+	default:
+		args = append(args, e.Field, "%"+escapeLike(e.Values[0])+"%")
+		return fmt.Sprintf("COALESCE(attributes->>$%d::text ILIKE $%d, false)", len(args)-1, len(args)), args
+	}
+}
@@ -0,0 +1,96 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/repository"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/segmentrule"
+	"github.com/stretchr/testify/require"
+)
+
+func clearSegments(t *testing.T, db *sql.DB) {
+	t.Helper()
+	_, err := db.Exec("TRUNCATE segments")
+	require.NoError(t, err)
+}
+
+func TestSegmentRepository_CRUD(t *testing.T) {
+	t.Cleanup(func() { clearSegments(t, testDB) })
+
+	fixtures := makeFixtures(t, testDB, "../../../../db/fixtures/users.yml")
+	require.NoError(t, fixtures.Load())
+
+	ctx := context.Background()
+	repo := repository.NewSegmentRepository(testPool)
+
+	night, err := repo.CreateSegment(ctx, &models.Segment{UserID: 1, Name: "Night", Rule: "shift = night"})
+	require.NoError(t, err)
+	_, err = repo.CreateSegment(ctx, &models.Segment{UserID: 1, Name: "NIGHT", Rule: "shift = day"})
+	require.ErrorIs(t, err, domain.ErrSegmentAlreadyExists)
+
+	got, err := repo.GetSegmentByID(ctx, 1, night.ID)
+	require.NoError(t, err)
+	require.Equal(t, "shift = night", got.Rule)
+	_, err = repo.GetSegmentByID(ctx, 2, night.ID)
+	require.ErrorIs(t, err, domain.ErrSegmentNotExists)
+
+	updated, err := repo.UpdateSegment(ctx, 1, night.ID, &models.Segment{Name: "Night B", Rule: `shift = night AND building = "B"`})
+	require.NoError(t, err)
+	require.Equal(t, "Night B", updated.Name)
+
+	segments, err := repo.GetSegmentsByUserID(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, segments, 1)
+
+	require.NoError(t, repo.DeleteSegment(ctx, 1, night.ID))
+	require.ErrorIs(t, repo.DeleteSegment(ctx, 1, night.ID), domain.ErrSegmentNotExists)
+}
+
+func TestContactsRepository_SegmentRule(t *testing.T) {
+	t.Cleanup(func() { clearContacts(t, testDB) })
+
+	fixtures := makeFixtures(t, testDB, "../../../../db/fixtures/users.yml")
+	require.NoError(t, fixtures.Load())
+
+	ctx := context.Background()
+	repo := repository.NewContactsRepository(testPool)
+
+	_, err := repo.CreateContacts(ctx, 1, []*models.Contact{
+		{UserID: 1, Name: "Alice", Phone: "+79990000001", Attributes: map[string]string{"building": "B", "shift": "night"}},
+		{UserID: 1, Name: "Bob", Phone: "+79990000002", Attributes: map[string]string{"building": "B", "shift": "day"}},
+		{UserID: 1, Name: "Carol", Phone: "+79990000003", Attributes: map[string]string{"building": "A"}},
+		{UserID: 1, Name: "Dave", Phone: "+79990000004", Attributes: map[string]string{"building": "B'; DROP TABLE contacts; --"}},
+	})
+	require.NoError(t, err)
+
+	match := func(rule string) []string {
+		expr, err := segmentrule.Parse(rule)
+		require.NoError(t, err)
+
+		var names []string
+		err = repo.StreamContacts(ctx, 1, domain.ContactFilter{Rule: expr}, func(c *models.Contact) error {
+			names = append(names, c.Name)
+			return nil
+		})
+		require.NoError(t, err)
+		sort.Strings(names)
+		return names
+	}
+
+	require.Equal(t, []string{"Alice"}, match(`building = "B" AND shift = "night"`))
+	require.Equal(t, []string{"Alice", "Bob", "Carol"}, match(`building IN (A, B)`))
+	require.Equal(t, []string{"Carol", "Dave"}, match(`building != B`))
+	require.Equal(t, []string{"Bob", "Carol", "Dave"}, match(`NOT shift = night`))
+	require.Equal(t, []string{"Carol", "Dave"}, match(`shift NOT IN (day, night)`))
+	require.Equal(t, []string{"Alice", "Carol"}, match(`name CONTAINS "a" AND (shift = night OR building = A)`))
+	require.Equal(t, []string{"Dave"}, match(`building = "B'; DROP TABLE contacts; --"`))
+	require.Equal(t, []string{"Bob"}, match(`phone = "+79990000002"`))
+}
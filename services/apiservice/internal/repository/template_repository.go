@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
@@ -10,7 +12,8 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
-// TemplateRepository handles CRUD operations on the message_templates table.
+// TemplateRepository handles CRUD operations on the message_templates table, recording every
+// version of a template in the template_versions table.
 type TemplateRepository struct {
 	db domain.DBConn
 }
@@ -22,16 +25,31 @@ func NewTemplateRepository(db domain.DBConn) *TemplateRepository {
 	}
 }
 
-// GetTemplatesCountByUserID retrieves count of templates belonging to the specified user.
-func (tr *TemplateRepository) GetTemplatesCountByUserID(ctx context.Context, userID int) (int, error) {
-	const q = `
+// templateFilterConds returns the WHERE conditions selecting the user's templates matching
+// the filter, together with their positional arguments.
+func templateFilterConds(userID int, filter domain.TemplateFilter) ([]string, []any) {
+	conds := []string{"user_id = $1"}
+	args := []any{userID}
+
+	if filter.Search != "" {
+		args = append(args, "%"+escapeLike(filter.Search)+"%")
+		conds = append(conds, fmt.Sprintf("(name ILIKE $%[1]d OR body ILIKE $%[1]d)", len(args)))
+	}
+
+	return createdRangeConds(filter.CreatedFrom, filter.CreatedTo, conds, args)
+}
+
+// GetTemplatesCountByUserID retrieves count of the user's templates matching the filter.
+func (tr *TemplateRepository) GetTemplatesCountByUserID(ctx context.Context, userID int, filter domain.TemplateFilter) (int, error) {
+	conds, args := templateFilterConds(userID, filter)
+	q := `
 		SELECT COUNT(*)
 		FROM message_templates
-		WHERE user_id = $1
+		WHERE ` + strings.Join(conds, " AND ") + `
 	`
 
 	var count int
-	err := tr.db.QueryRow(ctx, q, userID).Scan(&count)
+	err := tr.db.QueryRow(ctx, q, args...).Scan(&count)
 	if err != nil {
 		return 0, err
 	}
@@ -39,31 +57,43 @@ func (tr *TemplateRepository) GetTemplatesCountByUserID(ctx context.Context, use
 	return count, nil
 }
 
-// GetTemplatesPageByUserID retrieves a paginated list of templates for the specified user.
-// It applies the given limit and offset for pagination.
-func (tr *TemplateRepository) GetTemplatesPageByUserID(ctx context.Context, userID, limit, offset int) ([]*models.Template, error) {
-	const q = `
-		SELECT id, user_id, name, body, created_at, updated_at
+// templateSortColumns lists the columns templates can be sorted by besides the ID.
+var templateSortColumns = map[string]sortColumn{
+	"name":      {expr: "name", cast: "text"},
+	"createdAt": {expr: "created_at", cast: "timestamptz"},
+	"updatedAt": {expr: "updated_at", cast: "timestamptz"},
+}
+
+// GetTemplatesPageByUserID retrieves a page of the user's templates matching the filter, along with
+// the cursor of the next page, which is empty on the last one. Pages are read by keyset when a
+// cursor is given, and by limit and offset otherwise.
+// Returns domain.ErrInvalidSort or domain.ErrInvalidCursor for an unsupported sort or a bad cursor.
+func (tr *TemplateRepository) GetTemplatesPageByUserID(ctx context.Context, userID int, filter domain.TemplateFilter, page domain.PageRequest) ([]*models.Template, string, error) {
+	conds, args := templateFilterConds(userID, filter)
+	clause, args, err := pageClause(templateSortColumns, page, conds, args)
+	if err != nil {
+		return nil, "", err
+	}
+	q := `
+		SELECT id, user_id, name, body, sender_id, version, created_at, updated_at
 		FROM message_templates
-		WHERE user_id = $1
-		ORDER BY id
-		LIMIT $2 OFFSET $3
+		` + clause + `
 	`
 
 	templates := make([]*models.Template, 0)
 
-	rows, err := tr.db.Query(ctx, q, userID, limit, offset)
+	rows, err := tr.db.Query(ctx, q, args...)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
 		var t models.Template
 
-		err := rows.Scan(&t.ID, &t.UserID, &t.Name, &t.Body, &t.CreationTime, &t.UpdateTime)
+		err := rows.Scan(&t.ID, &t.UserID, &t.Name, &t.Body, &t.SenderID, &t.Version, &t.CreationTime, &t.UpdateTime)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 
 		templates = append(templates, &t)
@@ -71,17 +101,37 @@ func (tr *TemplateRepository) GetTemplatesPageByUserID(ctx context.Context, user
 
 	err = rows.Err()
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	return templates, nil
+	if len(templates) <= page.Limit {
+		return templates, "", nil
+	}
+
+	templates = templates[:page.Limit]
+	last := templates[len(templates)-1]
+	return templates, nextCursor(page.Sort, last.ID, templateSortValue(last, page.Sort.Field)), nil
+}
+
+// templateSortValue returns the value of the sort column of the template for its cursor.
+func templateSortValue(t *models.Template, field string) string {
+	switch field {
+	case "name":
+		return t.Name
+	case "createdAt":
+		return formatTime(t.CreationTime)
+	case "updatedAt":
+		return formatTime(t.UpdateTime)
+	default:
+		return ""
+	}
 }
 
 // GetTemplateByID retrieves a single template by user ID and template ID.
 // Returns domain.ErrTemplateNotExists if no matching row is found.
 func (tr *TemplateRepository) GetTemplateByID(ctx context.Context, userID int, tmplID int) (*models.Template, error) {
 	const q = `
-		SELECT id, user_id, name, body, created_at, updated_at
+		SELECT id, user_id, name, body, sender_id, version, created_at, updated_at
 		FROM message_templates
 		WHERE user_id = $1
 		  AND id = $2
@@ -90,7 +140,7 @@ func (tr *TemplateRepository) GetTemplateByID(ctx context.Context, userID int, t
 	var t models.Template
 
 	row := tr.db.QueryRow(ctx, q, userID, tmplID)
-	err := row.Scan(&t.ID, &t.UserID, &t.Name, &t.Body, &t.CreationTime, &t.UpdateTime)
+	err := row.Scan(&t.ID, &t.UserID, &t.Name, &t.Body, &t.SenderID, &t.Version, &t.CreationTime, &t.UpdateTime)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrTemplateNotExists
@@ -102,19 +152,27 @@ func (tr *TemplateRepository) GetTemplateByID(ctx context.Context, userID int, t
 	return &t, nil
 }
 
-// CreateTemplate inserts a new template and returns the created record.
+// CreateTemplate inserts a new template as its first version and returns the created record.
 // Returns an error if insertion fails.
 func (tr *TemplateRepository) CreateTemplate(ctx context.Context, tmpl *models.Template) (*models.Template, error) {
 	const q = `
-		INSERT INTO message_templates (user_id, name, body)
-		VALUES ($1, $2, $3)
-		RETURNING id, user_id, name, body, created_at, updated_at
+		WITH t AS (
+			INSERT INTO message_templates (user_id, name, body, sender_id)
+			VALUES ($1, $2, $3, $4)
+			RETURNING *
+		), v AS (
+			INSERT INTO template_versions (template_id, version, user_id, name, body, sender_id, created_at)
+			SELECT id, version, user_id, name, body, sender_id, updated_at
+			FROM t
+		)
+		SELECT id, user_id, name, body, sender_id, version, created_at, updated_at
+		FROM t
 	`
 
 	var t models.Template
 
-	row := tr.db.QueryRow(ctx, q, tmpl.UserID, tmpl.Name, tmpl.Body)
-	err := row.Scan(&t.ID, &t.UserID, &t.Name, &t.Body, &t.CreationTime, &t.UpdateTime)
+	row := tr.db.QueryRow(ctx, q, tmpl.UserID, tmpl.Name, tmpl.Body, tmpl.SenderID)
+	err := row.Scan(&t.ID, &t.UserID, &t.Name, &t.Body, &t.SenderID, &t.Version, &t.CreationTime, &t.UpdateTime)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
@@ -127,24 +185,35 @@ func (tr *TemplateRepository) CreateTemplate(ctx context.Context, tmpl *models.T
 	return &t, nil
 }
 
-// UpdateTemplate modifies an existing template’s body and updated_at timestamp.
+// UpdateTemplate modifies an existing template’s body and updated_at timestamp, recording its
+// contents as a new version.
 // Returns domain.ErrTemplateNotExists if no template was updated.
 func (tr *TemplateRepository) UpdateTemplate(ctx context.Context, userID int, tmplID int, updatedTmpl *models.Template) (*models.Template, error) {
 	const q = `
-		UPDATE message_templates
-		SET user_id    = $1,
-		    name       = $2,
-			body       = $3,
-			updated_at = now()
-		WHERE id = $4
-		  AND user_id = $5
-		RETURNING id, user_id, name, body, created_at, updated_at;
+		WITH t AS (
+			UPDATE message_templates
+			SET user_id    = $1,
+			    name       = $2,
+			    body       = $3,
+			    sender_id  = $4,
+			    version    = version + 1,
+			    updated_at = now()
+			WHERE id = $5
+			  AND user_id = $6
+			RETURNING *
+		), v AS (
+			INSERT INTO template_versions (template_id, version, user_id, name, body, sender_id, created_at)
+			SELECT id, version, user_id, name, body, sender_id, updated_at
+			FROM t
+		)
+		SELECT id, user_id, name, body, sender_id, version, created_at, updated_at
+		FROM t
 	`
 
-	row := tr.db.QueryRow(ctx, q, updatedTmpl.UserID, updatedTmpl.Name, updatedTmpl.Body, tmplID, userID)
+	row := tr.db.QueryRow(ctx, q, updatedTmpl.UserID, updatedTmpl.Name, updatedTmpl.Body, updatedTmpl.SenderID, tmplID, userID)
 
 	var t models.Template
-	err := row.Scan(&t.ID, &t.UserID, &t.Name, &t.Body, &t.CreationTime, &t.UpdateTime)
+	err := row.Scan(&t.ID, &t.UserID, &t.Name, &t.Body, &t.SenderID, &t.Version, &t.CreationTime, &t.UpdateTime)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrTemplateNotExists
@@ -182,3 +251,71 @@ func (tr *TemplateRepository) DeleteTemplate(ctx context.Context, userID int, tm
 
 	return nil
 }
+
+// GetTemplateVersions retrieves every version of the user's template, newest first.
+// Versions of deleted templates are kept, so they are listed as well.
+// Returns domain.ErrTemplateNotExists if the user has no template with the ID.
+func (tr *TemplateRepository) GetTemplateVersions(ctx context.Context, userID, tmplID int) ([]*models.TemplateVersion, error) {
+	const q = `
+		SELECT template_id, version, name, body, sender_id, created_at
+		FROM template_versions
+		WHERE user_id = $1
+		  AND template_id = $2
+		ORDER BY version DESC
+	`
+
+	rows, err := tr.db.Query(ctx, q, userID, tmplID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := make([]*models.TemplateVersion, 0)
+	for rows.Next() {
+		var v models.TemplateVersion
+
+		err := rows.Scan(&v.TemplateID, &v.Version, &v.Name, &v.Body, &v.SenderID, &v.CreationTime)
+		if err != nil {
+			return nil, err
+		}
+
+		versions = append(versions, &v)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(versions) == 0 {
+		return nil, domain.ErrTemplateNotExists
+	}
+
+	return versions, nil
+}
+
+// GetTemplateVersion retrieves a single version of the user's template.
+// Returns domain.ErrTemplateVersionNotExists if no matching row is found.
+func (tr *TemplateRepository) GetTemplateVersion(ctx context.Context, userID, tmplID, version int) (*models.TemplateVersion, error) {
+	const q = `
+		SELECT template_id, version, name, body, sender_id, created_at
+		FROM template_versions
+		WHERE user_id = $1
+		  AND template_id = $2
+		  AND version = $3
+	`
+
+	var v models.TemplateVersion
+
+	row := tr.db.QueryRow(ctx, q, userID, tmplID, version)
+	err := row.Scan(&v.TemplateID, &v.Version, &v.Name, &v.Body, &v.SenderID, &v.CreationTime)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrTemplateVersionNotExists
+		}
+
+		return nil, err
+	}
+
+	return &v, nil
+}
@@ -16,7 +16,7 @@ import (
 
 func clearTemplates(t *testing.T, db *sql.DB) {
 	t.Helper()
-	_, err := db.Exec("TRUNCATE message_templates RESTART IDENTITY CASCADE")
+	_, err := db.Exec("TRUNCATE message_templates, template_versions RESTART IDENTITY CASCADE")
 	require.NoError(t, err)
 }
 
@@ -30,7 +30,7 @@ func TestTemplateRepository_GetTemplatesCountByUserID(t *testing.T) {
 	userID := 1
 	repo := repository.NewTemplateRepository(testPool)
 
-	count, err := repo.GetTemplatesCountByUserID(ctx, userID)
+	count, err := repo.GetTemplatesCountByUserID(ctx, userID, domain.TemplateFilter{})
 	require.NoError(t, err)
 	require.Equal(t, 0, count)
 
@@ -42,7 +42,7 @@ func TestTemplateRepository_GetTemplatesCountByUserID(t *testing.T) {
 	_, err = repo.CreateTemplate(ctx, template)
 	require.NoError(t, err)
 
-	count, err = repo.GetTemplatesCountByUserID(ctx, userID)
+	count, err = repo.GetTemplatesCountByUserID(ctx, userID, domain.TemplateFilter{})
 	require.NoError(t, err)
 	require.Equal(t, 1, count)
 }
@@ -71,6 +71,21 @@ func TestTemplateRepository_CRUD(t *testing.T) {
 		require.Equal(t, created.ID, fetched.ID)
 	})
 
+	t.Run("Create with sender ID", func(t *testing.T) {
+		t.Cleanup(func() { clearTemplates(t, testDB) })
+
+		created, err := repo.CreateTemplate(ctx, &models.Template{UserID: userID, Name: "Branded", Body: "B", SenderID: "CityAlert"})
+		require.NoError(t, err)
+
+		fetched, err := repo.GetTemplateByID(ctx, userID, created.ID)
+		require.NoError(t, err)
+		require.Equal(t, "CityAlert", fetched.SenderID)
+
+		updated, err := repo.UpdateTemplate(ctx, userID, created.ID, &models.Template{UserID: userID, Name: "Branded", Body: "B"})
+		require.NoError(t, err)
+		require.Empty(t, updated.SenderID)
+	})
+
 	t.Run("List Templates", func(t *testing.T) {
 		t.Cleanup(func() { clearTemplates(t, testDB) })
 
@@ -79,14 +94,42 @@ func TestTemplateRepository_CRUD(t *testing.T) {
 		_, err = repo.CreateTemplate(ctx, &models.Template{UserID: userID, Name: "T2", Body: "B2"})
 		require.NoError(t, err)
 
-		list, err := repo.GetTemplatesPageByUserID(ctx, userID, 100, 0)
+		list, next, err := repo.GetTemplatesPageByUserID(ctx, userID, domain.TemplateFilter{}, domain.PageRequest{Limit: 100})
 		require.NoError(t, err)
+		require.Empty(t, next)
 		require.GreaterOrEqual(t, len(list), 2)
 		names := []string{list[0].Name, list[1].Name}
 		require.Contains(t, names, "T1")
 		require.Contains(t, names, "T2")
 	})
 
+	t.Run("List Templates by keyset", func(t *testing.T) {
+		t.Cleanup(func() { clearTemplates(t, testDB) })
+
+		for _, name := range []string{"Storm", "Flood", "Fire"} {
+			_, err := repo.CreateTemplate(ctx, &models.Template{UserID: userID, Name: name, Body: name + " warning"})
+			require.NoError(t, err)
+		}
+
+		page := domain.PageRequest{Limit: 2, Sort: domain.Sort{Field: "name", Desc: true}}
+		first, next, err := repo.GetTemplatesPageByUserID(ctx, userID, domain.TemplateFilter{}, page)
+		require.NoError(t, err)
+		require.Len(t, first, 2)
+		require.Equal(t, []string{"Storm", "Flood"}, []string{first[0].Name, first[1].Name})
+		require.NotEmpty(t, next)
+
+		page.Cursor = next
+		second, next, err := repo.GetTemplatesPageByUserID(ctx, userID, domain.TemplateFilter{}, page)
+		require.NoError(t, err)
+		require.Len(t, second, 1)
+		require.Equal(t, "Fire", second[0].Name)
+		require.Empty(t, next)
+
+		count, err := repo.GetTemplatesCountByUserID(ctx, userID, domain.TemplateFilter{Search: "FLOOD"})
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+	})
+
 	t.Run("GetByID_NotExists", func(t *testing.T) {
 		t.Cleanup(func() { clearTemplates(t, testDB) })
 
@@ -155,4 +198,42 @@ func TestTemplateRepository_CRUD(t *testing.T) {
 		err = repo.DeleteTemplate(ctx, userID+1, tmpl.ID)
 		require.ErrorIs(t, err, domain.ErrTemplateNotExists)
 	})
+
+	t.Run("Versions", func(t *testing.T) {
+		t.Cleanup(func() { clearTemplates(t, testDB) })
+
+		created, err := repo.CreateTemplate(ctx, &models.Template{UserID: userID, Name: "Flood", Body: "Leave the area"})
+		require.NoError(t, err)
+		require.Equal(t, 1, created.Version)
+
+		updated, err := repo.UpdateTemplate(ctx, userID, created.ID, &models.Template{UserID: userID, Name: "Flood", Body: "Leave the district", SenderID: "City"})
+		require.NoError(t, err)
+		require.Equal(t, 2, updated.Version)
+
+		versions, err := repo.GetTemplateVersions(ctx, userID, created.ID)
+		require.NoError(t, err)
+		require.Len(t, versions, 2)
+		require.Equal(t, []int{2, 1}, []int{versions[0].Version, versions[1].Version})
+		require.Equal(t, "Leave the district", versions[0].Body)
+		require.Equal(t, "City", versions[0].SenderID)
+
+		first, err := repo.GetTemplateVersion(ctx, userID, created.ID, 1)
+		require.NoError(t, err)
+		require.Equal(t, "Leave the area", first.Body)
+
+		_, err = repo.GetTemplateVersion(ctx, userID, created.ID, 3)
+		require.ErrorIs(t, err, domain.ErrTemplateVersionNotExists)
+
+		_, err = repo.GetTemplateVersion(ctx, userID+1, created.ID, 1)
+		require.ErrorIs(t, err, domain.ErrTemplateVersionNotExists)
+
+		_, err = repo.GetTemplateVersions(ctx, userID+1, created.ID)
+		require.ErrorIs(t, err, domain.ErrTemplateNotExists)
+
+		require.NoError(t, repo.DeleteTemplate(ctx, userID, created.ID))
+
+		versions, err = repo.GetTemplateVersions(ctx, userID, created.ID)
+		require.NoError(t, err)
+		require.Len(t, versions, 2)
+	})
 }
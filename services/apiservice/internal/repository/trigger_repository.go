@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// TriggerRepository handles CRUD operations on the triggers table and the firings of triggers.
+type TriggerRepository struct {
+	db domain.DBConn
+}
+
+// NewTriggerRepository constructs a TriggerRepository using the provided DB connection.
+func NewTriggerRepository(db domain.DBConn) *TriggerRepository {
+	return &TriggerRepository{
+		db: db,
+	}
+}
+
+// GetTriggersByUserID retrieves every trigger of the user, ordered by name.
+func (tr *TriggerRepository) GetTriggersByUserID(ctx context.Context, userID int) ([]*models.Trigger, error) {
+	const q = `
+		SELECT id, user_id, name, matchers, template_id, resolved_template_id, contact_group, priority, created_at, updated_at
+		FROM triggers
+		WHERE user_id = $1
+		ORDER BY lower(name)
+	`
+
+	rows, err := tr.db.Query(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	triggers := make([]*models.Trigger, 0)
+	for rows.Next() {
+		t, err := scanTrigger(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		triggers = append(triggers, t)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return triggers, nil
+}
+
+// GetTriggerByID retrieves a trigger by its ID and user ID.
+// Returns domain.ErrTriggerNotExists if the user has no such trigger.
+func (tr *TriggerRepository) GetTriggerByID(ctx context.Context, userID, triggerID int) (*models.Trigger, error) {
+	const q = `
+		SELECT id, user_id, name, matchers, template_id, resolved_template_id, contact_group, priority, created_at, updated_at
+		FROM triggers
+		WHERE id = $1
+		  AND user_id = $2
+	`
+
+	t, err := scanTrigger(tr.db.QueryRow(ctx, q, triggerID, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrTriggerNotExists
+		}
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// CreateTrigger inserts a new trigger and returns the created record.
+// Returns domain.ErrTriggerAlreadyExists if the user has a trigger with the same name.
+func (tr *TriggerRepository) CreateTrigger(ctx context.Context, trigger *models.Trigger) (*models.Trigger, error) {
+	const q = `
+		INSERT INTO triggers (user_id, name, matchers, template_id, resolved_template_id, contact_group, priority)
+		VALUES ($1, $2, $3::jsonb, $4, $5, $6, $7)
+		RETURNING id, user_id, name, matchers, template_id, resolved_template_id, contact_group, priority, created_at, updated_at
+	`
+
+	t, err := scanTrigger(tr.db.QueryRow(ctx, q,
+		trigger.UserID, trigger.Name, trigger.Matchers, trigger.TemplateID, trigger.ResolvedTemplateID, trigger.Group, trigger.Priority,
+	))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, domain.ErrTriggerAlreadyExists
+		}
+
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// UpdateTrigger replaces the rule of an existing trigger. Alerts that fired it stay recorded,
+// so that they aren't notified again, unless the matchers change: the firings of the old
+// matchers are cleared then, so that the alerts matching the new ones fire the trigger.
+// Returns domain.ErrTriggerNotExists if no trigger was updated.
+func (tr *TriggerRepository) UpdateTrigger(ctx context.Context, userID, triggerID int, trigger *models.Trigger) (*models.Trigger, error) {
+	// every part of the statement sees the triggers as they were before the update
+	const q = `
+		WITH cleared AS (
+			DELETE
+			FROM trigger_firings f
+			USING triggers t
+			WHERE f.trigger_id = t.id
+			  AND t.id = $7
+			  AND t.user_id = $8
+			  AND t.matchers <> $2::jsonb
+		)
+		UPDATE triggers
+		SET name                 = $1,
+		    matchers             = $2::jsonb,
+		    template_id          = $3,
+		    resolved_template_id = $4,
+		    contact_group        = $5,
+		    priority             = $6,
+		    updated_at           = now()
+		WHERE id = $7
+		  AND user_id = $8
+		RETURNING id, user_id, name, matchers, template_id, resolved_template_id, contact_group, priority, created_at, updated_at
+	`
+
+	t, err := scanTrigger(tr.db.QueryRow(ctx, q,
+		trigger.Name, trigger.Matchers, trigger.TemplateID, trigger.ResolvedTemplateID, trigger.Group, trigger.Priority, triggerID, userID,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrTriggerNotExists
+		}
+
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, domain.ErrTriggerAlreadyExists
+		}
+
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// DeleteTrigger removes a trigger by ID and user ID, along with its firings.
+// Returns domain.ErrTriggerNotExists if no row was deleted.
+func (tr *TriggerRepository) DeleteTrigger(ctx context.Context, userID, triggerID int) error {
+	const q = `
+		DELETE
+		FROM triggers
+		WHERE id = $1
+		  AND user_id = $2
+	`
+
+	res, err := tr.db.Exec(ctx, q, triggerID, userID)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return domain.ErrTriggerNotExists
+	}
+
+	return nil
+}
+
+// StartFiring records that the alert with the fingerprint, which started at startsAt, fired the trigger.
+// Reports false if the alert is already firing it. An alert with another known start is a new firing,
+// and so is an alert that fired the trigger more than repeatInterval ago; zero never repeats.
+func (tr *TriggerRepository) StartFiring(ctx context.Context, triggerID int, fingerprint string, startsAt *time.Time, repeatInterval time.Duration) (bool, error) {
+	const q = `
+		INSERT INTO trigger_firings (trigger_id, fingerprint, starts_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (trigger_id, fingerprint) DO UPDATE
+			SET starts_at = excluded.starts_at,
+			    fired_at  = now()
+			WHERE (excluded.starts_at IS NOT NULL AND trigger_firings.starts_at IS DISTINCT FROM excluded.starts_at)
+			   OR ($4::float8 > 0 AND trigger_firings.fired_at <= now() - make_interval(secs => $4::float8))
+	`
+
+	res, err := tr.db.Exec(ctx, q, triggerID, fingerprint, startsAt, repeatInterval.Seconds())
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, nil
+}
+
+// ResolveFiring records that the alert with the fingerprint no longer fires the trigger.
+// Reports false if the alert wasn't firing it.
+func (tr *TriggerRepository) ResolveFiring(ctx context.Context, triggerID int, fingerprint string) (bool, error) {
+	const q = `
+		DELETE
+		FROM trigger_firings
+		WHERE trigger_id = $1
+		  AND fingerprint = $2
+	`
+
+	res, err := tr.db.Exec(ctx, q, triggerID, fingerprint)
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, nil
+}
+
+func scanTrigger(row pgx.Row) (*models.Trigger, error) {
+	var t models.Trigger
+
+	err := row.Scan(
+		&t.ID, &t.UserID, &t.Name, &t.Matchers, &t.TemplateID, &t.ResolvedTemplateID, &t.Group, &t.Priority,
+		&t.CreationTime, &t.UpdateTime,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
@@ -0,0 +1,156 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+func clearTriggers(t *testing.T, db *sql.DB) {
+	t.Helper()
+	_, err := db.Exec("TRUNCATE triggers CASCADE")
+	require.NoError(t, err)
+}
+
+func TestTriggerRepository_CRUD(t *testing.T) {
+	t.Cleanup(func() {
+		clearTriggers(t, testDB)
+		clearTemplates(t, testDB)
+	})
+
+	fixtures := makeFixtures(t, testDB, "../../../../db/fixtures/users.yml")
+	require.NoError(t, fixtures.Load())
+
+	ctx := context.Background()
+	tmplRepo := repository.NewTemplateRepository(testPool)
+	firing, err := tmplRepo.CreateTemplate(ctx, &models.Template{UserID: 1, Name: "Disk full", Body: "Disk is full"})
+	require.NoError(t, err)
+	resolved, err := tmplRepo.CreateTemplate(ctx, &models.Template{UserID: 1, Name: "Disk ok", Body: "Disk is fine"})
+	require.NoError(t, err)
+
+	repo := repository.NewTriggerRepository(testPool)
+	matchers := []models.LabelMatcher{
+		{Name: "alertname", Type: models.MatchEqual, Value: "DiskFull"},
+		{Name: "env", Type: models.MatchNotRegexp, Value: "dev.*"},
+	}
+
+	trigger, err := repo.CreateTrigger(ctx, &models.Trigger{
+		UserID: 1, Name: "Disk full", Matchers: matchers, TemplateID: firing.ID,
+		ResolvedTemplateID: &resolved.ID, Group: "ops", Priority: models.PriorityCritical,
+	})
+	require.NoError(t, err)
+	require.Equal(t, matchers, trigger.Matchers)
+	require.Equal(t, resolved.ID, *trigger.ResolvedTemplateID)
+	_, err = repo.CreateTrigger(ctx, &models.Trigger{UserID: 1, Name: "DISK FULL", Matchers: matchers, TemplateID: firing.ID, Priority: models.PriorityNormal})
+	require.ErrorIs(t, err, domain.ErrTriggerAlreadyExists)
+
+	got, err := repo.GetTriggerByID(ctx, 1, trigger.ID)
+	require.NoError(t, err)
+	require.Equal(t, "ops", got.Group)
+	_, err = repo.GetTriggerByID(ctx, 2, trigger.ID)
+	require.ErrorIs(t, err, domain.ErrTriggerNotExists)
+
+	updated, err := repo.UpdateTrigger(ctx, 1, trigger.ID, &models.Trigger{Name: "Disk", Matchers: matchers[:1], TemplateID: firing.ID, Priority: models.PriorityNormal})
+	require.NoError(t, err)
+	require.Equal(t, "Disk", updated.Name)
+	require.Nil(t, updated.ResolvedTemplateID)
+	require.Len(t, updated.Matchers, 1)
+
+	triggers, err := repo.GetTriggersByUserID(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, triggers, 1)
+
+	require.NoError(t, repo.DeleteTrigger(ctx, 1, trigger.ID))
+	require.ErrorIs(t, repo.DeleteTrigger(ctx, 1, trigger.ID), domain.ErrTriggerNotExists)
+}
+
+func TestTriggerRepository_Firing(t *testing.T) {
+	t.Cleanup(func() {
+		clearTriggers(t, testDB)
+		clearTemplates(t, testDB)
+	})
+
+	fixtures := makeFixtures(t, testDB, "../../../../db/fixtures/users.yml")
+	require.NoError(t, fixtures.Load())
+
+	ctx := context.Background()
+	tmpl, err := repository.NewTemplateRepository(testPool).CreateTemplate(ctx, &models.Template{UserID: 1, Name: "Down", Body: "Service is down"})
+	require.NoError(t, err)
+
+	repo := repository.NewTriggerRepository(testPool)
+	trigger, err := repo.CreateTrigger(ctx, &models.Trigger{
+		UserID: 1, Name: "Down", TemplateID: tmpl.ID, Priority: models.PriorityCritical,
+		Matchers: []models.LabelMatcher{{Name: "alertname", Type: models.MatchEqual, Value: "Down"}},
+	})
+	require.NoError(t, err)
+
+	started, err := repo.StartFiring(ctx, trigger.ID, "abc", nil, 0)
+	require.NoError(t, err)
+	require.True(t, started)
+	started, err = repo.StartFiring(ctx, trigger.ID, "abc", nil, 0)
+	require.NoError(t, err)
+	require.False(t, started)
+
+	resolved, err := repo.ResolveFiring(ctx, trigger.ID, "abc")
+	require.NoError(t, err)
+	require.True(t, resolved)
+	resolved, err = repo.ResolveFiring(ctx, trigger.ID, "abc")
+	require.NoError(t, err)
+	require.False(t, resolved)
+
+	// another start of the alert is a new firing, a repeated one isn't
+	firstStart := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	secondStart := firstStart.Add(time.Hour)
+	started, err = repo.StartFiring(ctx, trigger.ID, "def", &firstStart, 0)
+	require.NoError(t, err)
+	require.True(t, started)
+	started, err = repo.StartFiring(ctx, trigger.ID, "def", &firstStart, 0)
+	require.NoError(t, err)
+	require.False(t, started)
+	started, err = repo.StartFiring(ctx, trigger.ID, "def", nil, 0)
+	require.NoError(t, err)
+	require.False(t, started)
+	started, err = repo.StartFiring(ctx, trigger.ID, "def", &secondStart, 0)
+	require.NoError(t, err)
+	require.True(t, started)
+
+	// an alert that fired longer than the repeat interval ago fires again
+	started, err = repo.StartFiring(ctx, trigger.ID, "def", &secondStart, time.Hour)
+	require.NoError(t, err)
+	require.False(t, started)
+	_, err = testDB.Exec("UPDATE trigger_firings SET fired_at = now() - interval '2 hours'")
+	require.NoError(t, err)
+	started, err = repo.StartFiring(ctx, trigger.ID, "def", &secondStart, time.Hour)
+	require.NoError(t, err)
+	require.True(t, started)
+
+	// firings stay when the matchers don't change, and are cleared when they do
+	trigger.Name = "Service down"
+	_, err = repo.UpdateTrigger(ctx, 1, trigger.ID, trigger)
+	require.NoError(t, err)
+	var count int
+	require.NoError(t, testDB.QueryRow("SELECT count(*) FROM trigger_firings").Scan(&count))
+	require.Equal(t, 1, count)
+	trigger.Matchers = []models.LabelMatcher{{Name: "alertname", Type: models.MatchEqual, Value: "ServiceDown"}}
+	_, err = repo.UpdateTrigger(ctx, 1, trigger.ID, trigger)
+	require.NoError(t, err)
+	require.NoError(t, testDB.QueryRow("SELECT count(*) FROM trigger_firings").Scan(&count))
+	require.Zero(t, count)
+
+	// firings go away with their trigger
+	started, err = repo.StartFiring(ctx, trigger.ID, "abc", nil, 0)
+	require.NoError(t, err)
+	require.True(t, started)
+	require.NoError(t, repo.DeleteTrigger(ctx, 1, trigger.ID))
+	require.NoError(t, testDB.QueryRow("SELECT count(*) FROM trigger_firings").Scan(&count))
+	require.Zero(t, count)
+}
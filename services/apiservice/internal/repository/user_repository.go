@@ -26,13 +26,13 @@ func (ur *UserRepository) CreateUser(ctx context.Context, user *models.User) (*m
 	const q = `
 		INSERT INTO users (email, password_hash)
 		VALUES ($1, $2)
-		RETURNING id, email, created_at
+		RETURNING id, email, default_region, created_at
 	`
 
 	var newUser models.User
 
 	row := ur.db.QueryRow(ctx, q, user.Email, user.PasswordHash)
-	err := row.Scan(&newUser.ID, &newUser.Email, &newUser.CreationTime)
+	err := row.Scan(&newUser.ID, &newUser.Email, &newUser.DefaultRegion, &newUser.CreationTime)
 	if err != nil {
 		return nil, err
 	}
@@ -43,7 +43,7 @@ func (ur *UserRepository) CreateUser(ctx context.Context, user *models.User) (*m
 // GetUserByEmail fetches a user by email. Returns domain.ErrUserNotExists if no user is found.
 func (ur *UserRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
 	const q = `
-		SELECT id, email, password_hash, created_at
+		SELECT id, email, password_hash, default_region, created_at
 		FROM users
 		WHERE email = $1
 	`
@@ -51,7 +51,7 @@ func (ur *UserRepository) GetUserByEmail(ctx context.Context, email string) (*mo
 	var user models.User
 
 	row := ur.db.QueryRow(ctx, q, email)
-	err := row.Scan(&user.ID, &user.Email, &user.PasswordHash, &user.CreationTime)
+	err := row.Scan(&user.ID, &user.Email, &user.PasswordHash, &user.DefaultRegion, &user.CreationTime)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrUserNotExists
@@ -66,7 +66,7 @@ func (ur *UserRepository) GetUserByEmail(ctx context.Context, email string) (*mo
 // GetUserByID fetches a user by ID. Returns domain.ErrUserNotExists if no user is found.
 func (ur *UserRepository) GetUserByID(ctx context.Context, id int) (*models.User, error) {
 	const q = `
-		SELECT id, email, password_hash, created_at
+		SELECT id, email, password_hash, default_region, created_at
 		FROM users
 		WHERE id = $1
 	`
@@ -74,7 +74,32 @@ func (ur *UserRepository) GetUserByID(ctx context.Context, id int) (*models.User
 	var user models.User
 
 	row := ur.db.QueryRow(ctx, q, id)
-	err := row.Scan(&user.ID, &user.Email, &user.PasswordHash, &user.CreationTime)
+	err := row.Scan(&user.ID, &user.Email, &user.PasswordHash, &user.DefaultRegion, &user.CreationTime)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrUserNotExists
+		}
+
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// UpdateUserDefaultRegion sets the region used to parse the user's phone numbers.
+// Returns domain.ErrUserNotExists if no user is found.
+func (ur *UserRepository) UpdateUserDefaultRegion(ctx context.Context, id int, region string) (*models.User, error) {
+	const q = `
+		UPDATE users
+		SET default_region = $1
+		WHERE id = $2
+		RETURNING id, email, password_hash, default_region, created_at
+	`
+
+	var user models.User
+
+	row := ur.db.QueryRow(ctx, q, region, id)
+	err := row.Scan(&user.ID, &user.Email, &user.PasswordHash, &user.DefaultRegion, &user.CreationTime)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrUserNotExists
@@ -33,6 +33,7 @@ func TestUserRepository_CRUD(t *testing.T) {
 		require.NotZero(t, created.ID)
 		require.Equal(t, orig.Email, created.Email)
 		require.False(t, created.CreationTime.IsZero())
+		require.Equal(t, "RU", created.DefaultRegion)
 
 		byEmail, err := repo.GetUserByEmail(ctx, orig.Email)
 		require.NoError(t, err)
@@ -54,6 +55,24 @@ func TestUserRepository_CRUD(t *testing.T) {
 		require.ErrorIs(t, err, domain.ErrUserNotExists)
 	})
 
+	t.Run("UpdateUserDefaultRegion", func(t *testing.T) {
+		created, err := repo.CreateUser(ctx, &models.User{Email: "region@example.com", PasswordHash: "h"})
+		require.NoError(t, err)
+
+		updated, err := repo.UpdateUserDefaultRegion(ctx, created.ID, "DE")
+		require.NoError(t, err)
+		require.Equal(t, "DE", updated.DefaultRegion)
+
+		byID, err := repo.GetUserByID(ctx, created.ID)
+		require.NoError(t, err)
+		require.Equal(t, "DE", byID.DefaultRegion)
+	})
+
+	t.Run("UpdateUserDefaultRegion NotExists", func(t *testing.T) {
+		_, err := repo.UpdateUserDefaultRegion(ctx, 9999, "DE")
+		require.ErrorIs(t, err, domain.ErrUserNotExists)
+	})
+
 	t.Run("CreateUser DuplicateEmail", func(t *testing.T) {
 		_, err := repo.CreateUser(ctx, &models.User{Email: "dup@example.com", PasswordHash: "h"})
 		require.NoError(t, err)
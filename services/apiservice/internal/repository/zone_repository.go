@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/geo"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ZoneRepository handles CRUD operations on the zones table.
+type ZoneRepository struct {
+	db domain.DBConn
+}
+
+// NewZoneRepository constructs a ZoneRepository using the provided DB connection.
+func NewZoneRepository(db domain.DBConn) *ZoneRepository {
+	return &ZoneRepository{
+		db: db,
+	}
+}
+
+// GetZonesByUserID retrieves every zone of the user, ordered by name.
+func (zr *ZoneRepository) GetZonesByUserID(ctx context.Context, userID int) ([]*models.Zone, error) {
+	const q = `
+		SELECT id, user_id, name, area, created_at, updated_at
+		FROM zones
+		WHERE user_id = $1
+		ORDER BY lower(name)
+	`
+
+	rows, err := zr.db.Query(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	zones := make([]*models.Zone, 0)
+	for rows.Next() {
+		var z models.Zone
+
+		err := rows.Scan(&z.ID, &z.UserID, &z.Name, &z.Area, &z.CreationTime, &z.UpdateTime)
+		if err != nil {
+			return nil, err
+		}
+
+		zones = append(zones, &z)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return zones, nil
+}
+
+// GetZoneByID retrieves a zone by its ID and user ID.
+// Returns domain.ErrZoneNotExists if the user has no such zone.
+func (zr *ZoneRepository) GetZoneByID(ctx context.Context, userID, zoneID int) (*models.Zone, error) {
+	const q = `
+		SELECT id, user_id, name, area, created_at, updated_at
+		FROM zones
+		WHERE id = $1
+		  AND user_id = $2
+	`
+
+	var z models.Zone
+
+	row := zr.db.QueryRow(ctx, q, zoneID, userID)
+	err := row.Scan(&z.ID, &z.UserID, &z.Name, &z.Area, &z.CreationTime, &z.UpdateTime)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrZoneNotExists
+		}
+		return nil, err
+	}
+
+	return &z, nil
+}
+
+// CreateZone inserts a new zone and returns the created record.
+// Returns domain.ErrZoneAlreadyExists if the user has a zone with the same name.
+func (zr *ZoneRepository) CreateZone(ctx context.Context, zone *models.Zone) (*models.Zone, error) {
+	const q = `
+		INSERT INTO zones (user_id, name, area)
+		VALUES ($1, $2, $3::jsonb)
+		RETURNING id, user_id, name, area, created_at, updated_at
+	`
+
+	var z models.Zone
+
+	row := zr.db.QueryRow(ctx, q, zone.UserID, zone.Name, zone.Area)
+	err := row.Scan(&z.ID, &z.UserID, &z.Name, &z.Area, &z.CreationTime, &z.UpdateTime)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, domain.ErrZoneAlreadyExists
+		}
+
+		return nil, err
+	}
+
+	return &z, nil
+}
+
+// UpdateZone replaces the name and area of an existing zone.
+// Returns domain.ErrZoneNotExists if no zone was updated.
+func (zr *ZoneRepository) UpdateZone(ctx context.Context, userID, zoneID int, zone *models.Zone) (*models.Zone, error) {
+	const q = `
+		UPDATE zones
+		SET name       = $1,
+		    area       = $2::jsonb,
+		    updated_at = now()
+		WHERE id = $3
+		  AND user_id = $4
+		RETURNING id, user_id, name, area, created_at, updated_at
+	`
+
+	var z models.Zone
+
+	row := zr.db.QueryRow(ctx, q, zone.Name, zone.Area, zoneID, userID)
+	err := row.Scan(&z.ID, &z.UserID, &z.Name, &z.Area, &z.CreationTime, &z.UpdateTime)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrZoneNotExists
+		}
+
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, domain.ErrZoneAlreadyExists
+		}
+
+		return nil, err
+	}
+
+	return &z, nil
+}
+
+// DeleteZone removes a zone by ID and user ID.
+// Returns domain.ErrZoneNotExists if no row was deleted.
+func (zr *ZoneRepository) DeleteZone(ctx context.Context, userID, zoneID int) error {
+	const q = `
+		DELETE
+		FROM zones
+		WHERE id = $1
+		  AND user_id = $2
+	`
+
+	res, err := zr.db.Exec(ctx, q, zoneID, userID)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return domain.ErrZoneNotExists
+	}
+
+	return nil
+}
+
+// areaCond compiles an area into a condition on the location of contacts, which is false for
+// contacts without one. Circles are matched by great-circle distance after a bounding box check
+// that can use the index; polygons by point containment, with positions as longitude and latitude.
+// The area must have been validated; a polygon that fails to parse matches nothing.
+func areaCond(area *geo.Area, args []any) (string, []any) {
+	if area.Circle != nil {
+		c := area.Circle
+		args = append(args, c.Lat, c.Lon, c.Radius)
+		lat, lon, radius := len(args)-2, len(args)-1, len(args)
+		cond := fmt.Sprintf(
+			"2 * %[4].1f * asin(least(1, sqrt("+
+				"power(sin(radians(location[1] - $%[1]d::float8) / 2), 2) + "+
+				"cos(radians($%[1]d::float8)) * cos(radians(location[1])) * power(sin(radians(location[0] - $%[2]d::float8) / 2), 2)"+
+				"))) <= $%[3]d::float8",
+			lat, lon, radius, geo.EarthRadius,
+		)
+
+		if minLon, minLat, maxLon, maxLat, ok := c.BoundingBox(); ok {
+			args = append(args, minLon, minLat, maxLon, maxLat)
+			n := len(args)
+			cond = fmt.Sprintf("location <@ box(point($%d::float8, $%d::float8), point($%d::float8, $%d::float8)) AND %s", n-3, n-2, n-1, n, cond)
+		}
+
+		return "(" + cond + ")", args
+	}
+
+	polygons, err := area.Polygon.Polygons()
+	if err != nil {
+		return "false", args
+	}
+
+	conds := make([]string, 0, len(polygons))
+	for _, p := range polygons {
+		parts := make([]string, 0, len(p))
+		for i, ring := range p {
+			args = append(args, ring.String())
+			part := fmt.Sprintf("location <@ $%d::polygon", len(args))
+			if i > 0 {
+				part = "NOT " + part
+			}
+			parts = append(parts, part)
+		}
+		conds = append(conds, "("+strings.Join(parts, " AND ")+")")
+	}
+
+	return "(" + strings.Join(conds, " OR ") + ")", args
+}
@@ -0,0 +1,113 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/geo"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+func clearZones(t *testing.T, db *sql.DB) {
+	t.Helper()
+	_, err := db.Exec("TRUNCATE zones")
+	require.NoError(t, err)
+}
+
+func TestZoneRepository_CRUD(t *testing.T) {
+	t.Cleanup(func() { clearZones(t, testDB) })
+
+	fixtures := makeFixtures(t, testDB, "../../../../db/fixtures/users.yml")
+	require.NoError(t, fixtures.Load())
+
+	ctx := context.Background()
+	repo := repository.NewZoneRepository(testPool)
+	circle := geo.Area{Circle: &geo.Circle{Lat: 55.75, Lon: 37.62, Radius: 2000}}
+
+	center, err := repo.CreateZone(ctx, &models.Zone{UserID: 1, Name: "Center", Area: circle})
+	require.NoError(t, err)
+	_, err = repo.CreateZone(ctx, &models.Zone{UserID: 1, Name: "CENTER", Area: circle})
+	require.ErrorIs(t, err, domain.ErrZoneAlreadyExists)
+
+	got, err := repo.GetZoneByID(ctx, 1, center.ID)
+	require.NoError(t, err)
+	require.Equal(t, circle, got.Area)
+	_, err = repo.GetZoneByID(ctx, 2, center.ID)
+	require.ErrorIs(t, err, domain.ErrZoneNotExists)
+
+	polygon := geo.Area{Polygon: &geo.Geometry{
+		Type:        geo.GeometryPolygon,
+		Coordinates: []byte(`[[[37.5,55.7],[37.7,55.7],[37.7,55.8],[37.5,55.7]]]`),
+	}}
+	updated, err := repo.UpdateZone(ctx, 1, center.ID, &models.Zone{Name: "District", Area: polygon})
+	require.NoError(t, err)
+	require.Equal(t, "District", updated.Name)
+	require.Equal(t, geo.GeometryPolygon, updated.Area.Polygon.Type)
+
+	zones, err := repo.GetZonesByUserID(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, zones, 1)
+
+	require.NoError(t, repo.DeleteZone(ctx, 1, center.ID))
+	require.ErrorIs(t, repo.DeleteZone(ctx, 1, center.ID), domain.ErrZoneNotExists)
+}
+
+func TestContactsRepository_Area(t *testing.T) {
+	t.Cleanup(func() { clearContacts(t, testDB) })
+
+	fixtures := makeFixtures(t, testDB, "../../../../db/fixtures/users.yml")
+	require.NoError(t, fixtures.Load())
+
+	ctx := context.Background()
+	repo := repository.NewContactsRepository(testPool)
+
+	_, err := repo.CreateContacts(ctx, 1, []*models.Contact{
+		{UserID: 1, Name: "Kremlin", Phone: "+79990000001", Location: &models.Location{Lat: 55.7520, Lon: 37.6175}},
+		{UserID: 1, Name: "Arbat", Phone: "+79990000002", Location: &models.Location{Lat: 55.7494, Lon: 37.5910}},
+		{UserID: 1, Name: "Khimki", Phone: "+79990000003", Location: &models.Location{Lat: 55.8970, Lon: 37.4297}},
+		{UserID: 1, Name: "Nowhere", Phone: "+79990000004"},
+	})
+	require.NoError(t, err)
+
+	match := func(area *geo.Area) []string {
+		var names []string
+		err := repo.StreamContacts(ctx, 1, domain.ContactFilter{Area: area}, func(c *models.Contact) error {
+			names = append(names, c.Name)
+			return nil
+		})
+		require.NoError(t, err)
+		sort.Strings(names)
+		return names
+	}
+
+	require.Equal(t, []string{"Kremlin"}, match(&geo.Area{Circle: &geo.Circle{Lat: 55.7520, Lon: 37.6175, Radius: 1000}}))
+	require.Equal(t, []string{"Arbat", "Kremlin"}, match(&geo.Area{Circle: &geo.Circle{Lat: 55.7520, Lon: 37.6175, Radius: 2000}}))
+	require.Equal(t, []string{"Arbat", "Khimki", "Kremlin"}, match(&geo.Area{Circle: &geo.Circle{Lat: 55.7520, Lon: 37.6175, Radius: 30000}}))
+
+	square := `[[37.55,55.70],[37.70,55.70],[37.70,55.80],[37.55,55.80],[37.55,55.70]]`
+	hole := `[[37.60,55.74],[37.63,55.74],[37.63,55.76],[37.60,55.76],[37.60,55.74]]`
+	require.Equal(t, []string{"Arbat", "Kremlin"}, match(&geo.Area{Polygon: &geo.Geometry{
+		Type: geo.GeometryPolygon, Coordinates: []byte("[" + square + "]"),
+	}}))
+	require.Equal(t, []string{"Arbat"}, match(&geo.Area{Polygon: &geo.Geometry{
+		Type: geo.GeometryPolygon, Coordinates: []byte("[" + square + "," + hole + "]"),
+	}}))
+	require.Equal(t, []string{"Arbat", "Khimki", "Kremlin"}, match(&geo.Area{Polygon: &geo.Geometry{
+		Type:        geo.GeometryMultiPolygon,
+		Coordinates: []byte(`[[` + square + `],[[[37.40,55.88],[37.46,55.88],[37.46,55.92],[37.40,55.92],[37.40,55.88]]]]`),
+	}}))
+
+	count, err := repo.GetContactsCountByUserID(ctx, 1, domain.ContactFilter{Area: &geo.Area{
+		Circle: &geo.Circle{Lat: 55.7520, Lon: 37.6175, Radius: 2000},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+}
@@ -0,0 +1,364 @@
+package segmentrule
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var (
+	// ErrSyntax is returned when a rule can't be parsed; the error gives the position of the problem.
+	ErrSyntax = fmt.Errorf("syntax error")
+	// ErrTooComplex is returned when a rule has more conditions or nesting than allowed.
+	ErrTooComplex = fmt.Errorf("rule is too complex")
+)
+
+const (
+	// MaxConditions caps the number of comparisons in a rule.
+	MaxConditions = 50
+	// MaxDepth caps the nesting of parentheses and NOT in a rule.
+	MaxDepth = 20
+)
+
+const (
+	// FieldName refers to the contact's name.
+	FieldName = "name"
+	// FieldPhone refers to the contact's phone number.
+	FieldPhone = "phone"
+)
+
+// Op is the operator of a rule node.
+type Op string
+
+const (
+	// OpAnd matches when every operand matches.
+	OpAnd Op = "AND"
+	// OpOr matches when any operand matches.
+	OpOr Op = "OR"
+	// OpNot matches when its only operand doesn't.
+	OpNot Op = "NOT"
+	// OpEq matches when the field equals the value.
+	OpEq Op = "="
+	// OpNe matches when the field differs from the value or is not set.
+	OpNe Op = "!="
+	// OpIn matches when the field equals any of the values.
+	OpIn Op = "IN"
+	// OpContains matches when the field contains the value, case-insensitively.
+	OpContains Op = "CONTAINS"
+)
+
+// Expr is a node of a parsed rule. AND, OR and NOT nodes combine their Operands;
+// comparisons test the contact Field against Values, which hold a single value
+// except for IN. Field is FieldName, FieldPhone or the name of an attribute.
+type Expr struct {
+	Op       Op
+	Operands []*Expr
+	Field    string
+	Values   []string
+}
+
+// IsComparison reports whether the node compares a field rather than combining other nodes.
+func (e *Expr) IsComparison() bool {
+	return e.Op != OpAnd && e.Op != OpOr && e.Op != OpNot
+}
+
+// Walk calls fn for every comparison of the rule, left to right.
+func (e *Expr) Walk(fn func(*Expr)) {
+	if e.IsComparison() {
+		fn(e)
+		return
+	}
+	for _, o := range e.Operands {
+		o.Walk(fn)
+	}
+}
+
+// Parse parses a rule such as `building = "B" AND (shift IN (night, evening) OR NOT name CONTAINS "temp")`.
+//
+// The fields name and phone, in any case, refer to the contact's name and phone; other fields
+// are attribute names. Keywords are case-insensitive and NOT binds tighter than AND, which binds tighter than OR.
+// Fields and values are bare words, or double-quoted strings where `\"` and `\\` escape a quote
+// and a backslash; fields with spaces and values that are keywords must be quoted. `<>` is
+// accepted for `!=`, and `field NOT IN (...)` and `field NOT CONTAINS value` negate the comparison.
+func Parse(rule string) (*Expr, error) {
+	tokens, err := tokenize(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	e, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.errorf(t, "unexpected %s", t)
+	}
+
+	return e, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOp
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of rule"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// keyword returns the upper-cased keyword the token spells, or "" if it isn't one.
+func (t token) keyword() string {
+	if t.kind != tokenWord {
+		return ""
+	}
+	switch kw := strings.ToUpper(t.text); kw {
+	case "AND", "OR", "NOT", "IN", "CONTAINS":
+		return kw
+	}
+	return ""
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_.-+:@", r)
+}
+
+// tokenize splits the rule into tokens; positions are 1-based character offsets.
+func tokenize(rule string) ([]token, error) {
+	var tokens []token
+
+	runes := []rune(rule)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: pos})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: pos})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: pos})
+			i++
+		case r == '=':
+			tokens = append(tokens, token{kind: tokenOp, text: string(OpEq), pos: pos})
+			i++
+		case i+1 < len(runes) && (r == '!' && runes[i+1] == '=' || r == '<' && runes[i+1] == '>'):
+			tokens = append(tokens, token{kind: tokenOp, text: string(OpNe), pos: pos})
+			i += 2
+		case r == '"':
+			var sb strings.Builder
+			for i++; ; i++ {
+				if i >= len(runes) {
+					return nil, fmt.Errorf("%w at position %d: unterminated string", ErrSyntax, pos)
+				}
+				if runes[i] == '"' {
+					i++
+					break
+				}
+				if runes[i] == '\\' {
+					i++
+					if i >= len(runes) || (runes[i] != '"' && runes[i] != '\\') {
+						return nil, fmt.Errorf("%w at position %d: unknown escape", ErrSyntax, i)
+					}
+				}
+				sb.WriteRune(runes[i])
+			}
+			tokens = append(tokens, token{kind: tokenString, text: sb.String(), pos: pos})
+		case isWordRune(r):
+			end := i
+			for end < len(runes) && isWordRune(runes[end]) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: string(runes[i:end]), pos: pos})
+			i = end
+		default:
+			return nil, fmt.Errorf("%w at position %d: unexpected character %q", ErrSyntax, pos, r)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(runes) + 1}), nil
+}
+
+type parser struct {
+	tokens     []token
+	next       int
+	conditions int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) advance() token {
+	t := p.tokens[p.next]
+	if t.kind != tokenEOF {
+		p.next++
+	}
+	return t
+}
+
+func (p *parser) errorf(t token, format string, args ...any) error {
+	return fmt.Errorf("%w at position %d: %s", ErrSyntax, t.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) parseOr(depth int) (*Expr, error) {
+	return p.parseJunction(depth, OpOr, p.parseAnd)
+}
+
+func (p *parser) parseAnd(depth int) (*Expr, error) {
+	return p.parseJunction(depth, OpAnd, p.parseUnary)
+}
+
+// parseJunction parses operands joined by the AND or OR keyword into a single node.
+func (p *parser) parseJunction(depth int, op Op, operand func(int) (*Expr, error)) (*Expr, error) {
+	e, err := operand(depth)
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().keyword() != string(op) {
+		return e, nil
+	}
+
+	j := &Expr{Op: op, Operands: []*Expr{e}}
+	for p.peek().keyword() == string(op) {
+		p.advance()
+		e, err = operand(depth)
+		if err != nil {
+			return nil, err
+		}
+		j.Operands = append(j.Operands, e)
+	}
+
+	return j, nil
+}
+
+func (p *parser) parseUnary(depth int) (*Expr, error) {
+	if depth > MaxDepth {
+		return nil, ErrTooComplex
+	}
+
+	t := p.peek()
+	switch {
+	case t.keyword() == "NOT":
+		p.advance()
+		e, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return &Expr{Op: OpNot, Operands: []*Expr{e}}, nil
+	case t.kind == tokenLParen:
+		p.advance()
+		e, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if t := p.advance(); t.kind != tokenRParen {
+			return nil, p.errorf(t, "expected \")\", got %s", t)
+		}
+		return e, nil
+	default:
+		return p.parseComparison()
+	}
+}
+
+func (p *parser) parseComparison() (*Expr, error) {
+	t := p.advance()
+	if (t.kind != tokenWord || t.keyword() != "") && t.kind != tokenString {
+		return nil, p.errorf(t, "expected field, got %s", t)
+	}
+	if t.text == "" {
+		return nil, p.errorf(t, "empty field")
+	}
+
+	p.conditions++
+	if p.conditions > MaxConditions {
+		return nil, ErrTooComplex
+	}
+
+	e := &Expr{Field: t.text}
+	if strings.EqualFold(e.Field, FieldName) || strings.EqualFold(e.Field, FieldPhone) {
+		e.Field = strings.ToLower(e.Field)
+	}
+
+	negate := false
+	if p.peek().keyword() == "NOT" {
+		p.advance()
+		negate = true
+	}
+
+	t = p.advance()
+	switch {
+	case t.kind == tokenOp && !negate:
+		e.Op = Op(t.text)
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		e.Values = []string{v}
+	case t.keyword() == "CONTAINS":
+		e.Op = OpContains
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		e.Values = []string{v}
+	case t.keyword() == "IN":
+		e.Op = OpIn
+		if t := p.advance(); t.kind != tokenLParen {
+			return nil, p.errorf(t, "expected \"(\", got %s", t)
+		}
+		for {
+			v, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			e.Values = append(e.Values, v)
+
+			t := p.advance()
+			if t.kind == tokenRParen {
+				break
+			}
+			if t.kind != tokenComma {
+				return nil, p.errorf(t, "expected \",\" or \")\", got %s", t)
+			}
+		}
+	case negate:
+		return nil, p.errorf(t, "expected IN or CONTAINS, got %s", t)
+	default:
+		return nil, p.errorf(t, "expected operator, got %s", t)
+	}
+
+	if negate {
+		return &Expr{Op: OpNot, Operands: []*Expr{e}}, nil
+	}
+	return e, nil
+}
+
+func (p *parser) parseValue() (string, error) {
+	t := p.advance()
+	if (t.kind != tokenWord || t.keyword() != "") && t.kind != tokenString {
+		return "", p.errorf(t, "expected value, got %s", t)
+	}
+	return t.text, nil
+}
@@ -0,0 +1,143 @@
+package segmentrule_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/segmentrule"
+	"github.com/stretchr/testify/assert"
+)
+
+func cmp(op segmentrule.Op, field string, values ...string) *segmentrule.Expr {
+	return &segmentrule.Expr{Op: op, Field: field, Values: values}
+}
+
+func node(op segmentrule.Op, operands ...*segmentrule.Expr) *segmentrule.Expr {
+	return &segmentrule.Expr{Op: op, Operands: operands}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+		want *segmentrule.Expr
+	}{
+		{
+			name: "single comparison",
+			rule: `building = "B"`,
+			want: cmp(segmentrule.OpEq, "building", "B"),
+		},
+		{
+			name: "and",
+			rule: `building = "B" AND shift = "night"`,
+			want: node(segmentrule.OpAnd, cmp(segmentrule.OpEq, "building", "B"), cmp(segmentrule.OpEq, "shift", "night")),
+		},
+		{
+			name: "and binds tighter than or",
+			rule: `a = 1 or b = 2 and c = 3`,
+			want: node(segmentrule.OpOr,
+				cmp(segmentrule.OpEq, "a", "1"),
+				node(segmentrule.OpAnd, cmp(segmentrule.OpEq, "b", "2"), cmp(segmentrule.OpEq, "c", "3")),
+			),
+		},
+		{
+			name: "parentheses and not",
+			rule: `NOT (a = 1 OR b <> 2) AND c != 3`,
+			want: node(segmentrule.OpAnd,
+				node(segmentrule.OpNot, node(segmentrule.OpOr, cmp(segmentrule.OpEq, "a", "1"), cmp(segmentrule.OpNe, "b", "2"))),
+				cmp(segmentrule.OpNe, "c", "3"),
+			),
+		},
+		{
+			name: "in and not in",
+			rule: `shift IN (day, "night") AND floor NOT IN (1,2)`,
+			want: node(segmentrule.OpAnd,
+				cmp(segmentrule.OpIn, "shift", "day", "night"),
+				node(segmentrule.OpNot, cmp(segmentrule.OpIn, "floor", "1", "2")),
+			),
+		},
+		{
+			name: "contains",
+			rule: `Name contains "Иван" and PHONE not contains +7912`,
+			want: node(segmentrule.OpAnd,
+				cmp(segmentrule.OpContains, "name", "Иван"),
+				node(segmentrule.OpNot, cmp(segmentrule.OpContains, "phone", "+7912")),
+			),
+		},
+		{
+			name: "quoted field and escapes",
+			rule: `"On call" = "say \"yes\" \\ no"`,
+			want: cmp(segmentrule.OpEq, "On call", `say "yes" \ no`),
+		},
+		{
+			name: "quoted keyword value",
+			rule: `status = "and"`,
+			want: cmp(segmentrule.OpEq, "status", "and"),
+		},
+		{
+			name: "sql is just a value",
+			rule: `building = "B'; DROP TABLE contacts; --"`,
+			want: cmp(segmentrule.OpEq, "building", "B'; DROP TABLE contacts; --"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := segmentrule.Parse(tc.rule)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		wantErr error
+		wantMsg string
+	}{
+		{name: "empty", rule: "", wantErr: segmentrule.ErrSyntax, wantMsg: "position 1: expected field, got end of rule"},
+		{name: "missing value", rule: "a =", wantErr: segmentrule.ErrSyntax, wantMsg: "position 4: expected value"},
+		{name: "missing operator", rule: "a b", wantErr: segmentrule.ErrSyntax, wantMsg: "position 3: expected operator"},
+		{name: "unquoted keyword value", rule: "a = and", wantErr: segmentrule.ErrSyntax},
+		{name: "unclosed parenthesis", rule: "(a = 1", wantErr: segmentrule.ErrSyntax},
+		{name: "trailing input", rule: "a = 1 b", wantErr: segmentrule.ErrSyntax, wantMsg: "unexpected \"b\""},
+		{name: "unterminated string", rule: `a = "b`, wantErr: segmentrule.ErrSyntax, wantMsg: "unterminated string"},
+		{name: "unknown escape", rule: `a = "\n"`, wantErr: segmentrule.ErrSyntax},
+		{name: "unknown character", rule: "a = 'b'", wantErr: segmentrule.ErrSyntax},
+		{name: "not with operator", rule: "a NOT = 1", wantErr: segmentrule.ErrSyntax},
+		{name: "empty in", rule: "a IN ()", wantErr: segmentrule.ErrSyntax},
+		{name: "empty field", rule: `"" = 1`, wantErr: segmentrule.ErrSyntax},
+		{
+			name:    "too many conditions",
+			rule:    strings.Repeat("a = 1 OR ", segmentrule.MaxConditions) + "a = 1",
+			wantErr: segmentrule.ErrTooComplex,
+		},
+		{
+			name:    "too deep",
+			rule:    strings.Repeat("(", segmentrule.MaxDepth+1) + "a = 1" + strings.Repeat(")", segmentrule.MaxDepth+1),
+			wantErr: segmentrule.ErrTooComplex,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := segmentrule.Parse(tc.rule)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Contains(t, err.Error(), tc.wantMsg)
+			assert.Nil(t, got)
+		})
+	}
+}
+
+func TestExpr_Walk(t *testing.T) {
+	e, err := segmentrule.Parse(`a = 1 AND (NOT b = 2 OR c IN (3))`)
+	assert.NoError(t, err)
+
+	var fields []string
+	e.Walk(func(c *segmentrule.Expr) {
+		fields = append(fields, c.Field)
+	})
+	assert.Equal(t, []string{"a", "b", "c"}, fields)
+}
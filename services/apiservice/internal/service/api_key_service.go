@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+)
+
+const (
+	apiKeyPrefix     = "ens_"
+	apiKeyBytes      = 32
+	apiKeyShownLen   = 12
+	maxAPIKeyNameLen = 100
+)
+
+// APIKeyService issues, lists, revokes and checks API keys.
+type APIKeyService struct {
+	repository domain.APIKeyRepository
+}
+
+// NewAPIKeyService creates an APIKeyService with the given repository.
+func NewAPIKeyService(r domain.APIKeyRepository) *APIKeyService {
+	return &APIKeyService{
+		repository: r,
+	}
+}
+
+// GetAPIKeysByUserID retrieves every API key of the user.
+func (aks *APIKeyService) GetAPIKeysByUserID(ctx context.Context, userID int) ([]*models.APIKey, error) {
+	return aks.repository.GetAPIKeysByUserID(ctx, userID)
+}
+
+// CreateAPIKey generates a random API key for the user and stores its hash. The key is
+// returned only here and can't be recovered later.
+// Returns domain.ErrInvalidAPIKeyName for an empty or too long name.
+func (aks *APIKeyService) CreateAPIKey(ctx context.Context, userID int, name string) (*domain.CreatedAPIKey, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxAPIKeyNameLen {
+		return nil, domain.ErrInvalidAPIKeyName
+	}
+
+	buf := make([]byte, apiKeyBytes)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(buf)
+
+	created, err := aks.repository.CreateAPIKey(ctx, &models.APIKey{
+		UserID: userID,
+		Name:   name,
+		Prefix: key[:apiKeyShownLen],
+		Hash:   hashAPIKey(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.CreatedAPIKey{APIKey: created, Key: key}, nil
+}
+
+// DeleteAPIKey revokes an API key of the user.
+func (aks *APIKeyService) DeleteAPIKey(ctx context.Context, userID, keyID int) error {
+	return aks.repository.DeleteAPIKey(ctx, userID, keyID)
+}
+
+// Authenticate returns the ID of the user owning the key.
+// Returns domain.ErrAPIKeyNotExists for an unknown or revoked key.
+func (aks *APIKeyService) Authenticate(ctx context.Context, key string) (int, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return 0, domain.ErrAPIKeyNotExists
+	}
+
+	k, err := aks.repository.UseAPIKey(ctx, hashAPIKey(key))
+	if err != nil {
+		return 0, err
+	}
+
+	return k.UserID, nil
+}
+
+// hashAPIKey returns the SHA-256 hash of the key. API keys are random and long enough that a
+// fast unsalted hash can't be reversed, and it lets keys be looked up by hash.
+func hashAPIKey(key string) []byte {
+	sum := sha256.Sum256([]byte(key))
+	return sum[:]
+}
@@ -0,0 +1,83 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"strings"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyService_CreateAPIKey(t *testing.T) {
+	t.Run("stores the hash of the key", func(t *testing.T) {
+		r := new(MockAPIKeyRepository)
+		var stored *models.APIKey
+		r.On("CreateAPIKey", mock.Anything, mock.AnythingOfType("*models.APIKey")).
+			Run(func(args mock.Arguments) { stored = args.Get(1).(*models.APIKey) }).
+			Return(&models.APIKey{ID: 1, UserID: 1, Name: "Feed"}, nil).Once()
+		svc := service.NewAPIKeyService(r)
+
+		created, err := svc.CreateAPIKey(context.Background(), 1, " Feed ")
+		require.NoError(t, err)
+
+		assert.True(t, strings.HasPrefix(created.Key, "ens_"))
+		assert.Equal(t, 1, created.ID)
+		assert.Equal(t, "Feed", stored.Name)
+		assert.Equal(t, created.Key[:12], stored.Prefix)
+		sum := sha256.Sum256([]byte(created.Key))
+		assert.Equal(t, sum[:], stored.Hash)
+		r.AssertExpectations(t)
+	})
+
+	for name, keyName := range map[string]string{"empty name": " ", "name too long": strings.Repeat("я", 101)} {
+		t.Run(name, func(t *testing.T) {
+			r := new(MockAPIKeyRepository)
+			svc := service.NewAPIKeyService(r)
+
+			_, err := svc.CreateAPIKey(context.Background(), 1, keyName)
+			assert.ErrorIs(t, err, domain.ErrInvalidAPIKeyName)
+			r.AssertNotCalled(t, "CreateAPIKey", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	key := "ens_secret"
+	sum := sha256.Sum256([]byte(key))
+
+	t.Run("known key", func(t *testing.T) {
+		r := new(MockAPIKeyRepository)
+		r.On("UseAPIKey", mock.Anything, sum[:]).Return(&models.APIKey{ID: 1, UserID: 7}, nil).Once()
+		svc := service.NewAPIKeyService(r)
+
+		userID, err := svc.Authenticate(context.Background(), key)
+		assert.NoError(t, err)
+		assert.Equal(t, 7, userID)
+		r.AssertExpectations(t)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		r := new(MockAPIKeyRepository)
+		r.On("UseAPIKey", mock.Anything, sum[:]).Return(nil, domain.ErrAPIKeyNotExists).Once()
+		svc := service.NewAPIKeyService(r)
+
+		_, err := svc.Authenticate(context.Background(), key)
+		assert.ErrorIs(t, err, domain.ErrAPIKeyNotExists)
+		r.AssertExpectations(t)
+	})
+
+	t.Run("not an api key", func(t *testing.T) {
+		r := new(MockAPIKeyRepository)
+		svc := service.NewAPIKeyService(r)
+
+		_, err := svc.Authenticate(context.Background(), "Bearer token")
+		assert.ErrorIs(t, err, domain.ErrAPIKeyNotExists)
+		r.AssertNotCalled(t, "UseAPIKey", mock.Anything, mock.Anything)
+	})
+}
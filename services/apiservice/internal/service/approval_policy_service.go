@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+)
+
+const (
+	defaultApprovalWindowMinutes = 60
+	maxApprovalWindowMinutes     = 7 * minutesPerDay
+	maxApprovers                 = 10
+)
+
+// ApprovalPolicyService provides operations for managing a user's approval policy. A user can
+// make their policy stricter at will, but relaxing or deleting it takes the approval of one of
+// its approvers, so that two-person approval can't be turned off by the person it restrains.
+type ApprovalPolicyService struct {
+	repository     domain.ApprovalPolicyRepository
+	userRepository domain.UserRepository
+}
+
+// NewApprovalPolicyService creates an ApprovalPolicyService. Approvers are looked up by e-mail with ur.
+func NewApprovalPolicyService(r domain.ApprovalPolicyRepository, ur domain.UserRepository) *ApprovalPolicyService {
+	return &ApprovalPolicyService{
+		repository:     r,
+		userRepository: ur,
+	}
+}
+
+// GetApprovalPolicy returns the approval policy of the user or domain.ErrApprovalPolicyNotExists if none is configured.
+func (aps *ApprovalPolicyService) GetApprovalPolicy(ctx context.Context, userID int) (*models.ApprovalPolicy, error) {
+	return aps.repository.GetApprovalPolicyByUserID(ctx, userID)
+}
+
+// UpdateApprovalPolicy validates the approval policy of the user and stores it, unless it relaxes
+// the current policy: then the change is recorded as pending until an approver of the current
+// policy approves it, and returned instead of the policy. A policy relaxes the current one if it
+// raises or drops the recipient threshold, stops requiring approval of critical campaigns or adds
+// approvers. The approval window defaults to an hour.
+// Returns an error wrapping domain.ErrInvalidApprovalPolicy if the policy requires approval of
+// nothing, its window is out of range, or its approvers are missing, not registered or include
+// the user, who can't approve their own campaigns, and domain.ErrApprovalPolicyChangePending if
+// an earlier change still awaits a decision.
+func (aps *ApprovalPolicyService) UpdateApprovalPolicy(ctx context.Context, userID int, p *models.ApprovalPolicy) (*models.ApprovalPolicy, *models.ApprovalPolicyChange, error) {
+	err := aps.validateApprovalPolicy(ctx, userID, p)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	current, err := aps.repository.GetApprovalPolicyByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, domain.ErrApprovalPolicyNotExists) {
+		return nil, nil, err
+	}
+	if current == nil || !relaxesApprovalPolicy(current, p) {
+		policy, err := aps.repository.UpsertApprovalPolicy(ctx, userID, p)
+		return policy, nil, err
+	}
+
+	change, err := aps.requestChange(ctx, userID, current, p)
+	return nil, change, err
+}
+
+// DeleteApprovalPolicy requests turning approval of campaigns off for the user, which takes effect
+// once an approver of the policy approves it, and returns the pending change.
+// Returns domain.ErrApprovalPolicyNotExists if the user has no policy, and
+// domain.ErrApprovalPolicyChangePending if an earlier change still awaits a decision.
+func (aps *ApprovalPolicyService) DeleteApprovalPolicy(ctx context.Context, userID int) (*models.ApprovalPolicyChange, error) {
+	current, err := aps.repository.GetApprovalPolicyByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return aps.requestChange(ctx, userID, current, nil)
+}
+
+// GetPolicyChanges returns the approval policy changes requested by the user or awaiting their decision.
+func (aps *ApprovalPolicyService) GetPolicyChanges(ctx context.Context, userID int) ([]*models.ApprovalPolicyChange, error) {
+	return aps.repository.GetPolicyChangesByUserID(ctx, userID)
+}
+
+// ApprovePolicyChange records the approval of the policy change by the user and applies it.
+// Returns domain.ErrApprovalPolicyChangeNotExists if the change is neither requested by the user
+// nor awaiting their decision, domain.ErrNotApprover if the user requested it, domain.ErrApprovalExpired
+// if its deadline has passed, domain.ErrApprovalNotPending if it has already been decided on, and
+// domain.ErrInvalidApprovalComment for comments longer than 1000 characters.
+func (aps *ApprovalPolicyService) ApprovePolicyChange(ctx context.Context, userID, changeID int, comment string) (*models.ApprovalPolicyChange, error) {
+	return aps.decideChange(ctx, userID, changeID, models.ApprovalApproved, comment)
+}
+
+// RejectPolicyChange records the rejection of the policy change by the user, leaving the policy as
+// it is. Returns the errors of ApprovePolicyChange.
+func (aps *ApprovalPolicyService) RejectPolicyChange(ctx context.Context, userID, changeID int, comment string) (*models.ApprovalPolicyChange, error) {
+	return aps.decideChange(ctx, userID, changeID, models.ApprovalRejected, comment)
+}
+
+// requestChange records the change of the current policy to p, or its deletion if p is nil, as
+// pending for the approval window of the current policy.
+func (aps *ApprovalPolicyService) requestChange(ctx context.Context, userID int, current, p *models.ApprovalPolicy) (*models.ApprovalPolicyChange, error) {
+	return aps.repository.CreatePolicyChange(ctx, &models.ApprovalPolicyChange{
+		UserID:      userID,
+		Policy:      p,
+		ApproverIDs: current.ApproverIDs,
+		ExpiresAt:   time.Now().Add(time.Duration(current.ApprovalWindowMinutes) * time.Minute),
+	})
+}
+
+func (aps *ApprovalPolicyService) decideChange(ctx context.Context, userID, changeID int, status models.ApprovalStatus, comment string) (*models.ApprovalPolicyChange, error) {
+	if utf8.RuneCountInString(comment) > maxApprovalCommentLen {
+		return nil, fmt.Errorf("%w: comment is longer than %d characters", domain.ErrInvalidApprovalComment, maxApprovalCommentLen)
+	}
+
+	c, err := aps.repository.GetPolicyChangeByID(ctx, userID, changeID)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(c.ApproverIDs, userID) {
+		return nil, domain.ErrNotApprover
+	}
+
+	switch c.Status {
+	case models.ApprovalPending:
+		return aps.repository.DecidePolicyChange(ctx, userID, changeID, status, comment)
+	case models.ApprovalExpired:
+		return nil, domain.ErrApprovalExpired
+	default:
+		return nil, domain.ErrApprovalNotPending
+	}
+}
+
+// validateApprovalPolicy checks the policy, defaults its approval window and resolves its approvers.
+func (aps *ApprovalPolicyService) validateApprovalPolicy(ctx context.Context, userID int, p *models.ApprovalPolicy) error {
+	if p.RecipientThreshold < 0 {
+		return fmt.Errorf("%w: recipient threshold can't be negative", domain.ErrInvalidApprovalPolicy)
+	}
+	if p.RecipientThreshold == 0 && !p.RequireForCritical {
+		return fmt.Errorf("%w: either a recipient threshold or approval of critical campaigns is required", domain.ErrInvalidApprovalPolicy)
+	}
+
+	if p.ApprovalWindowMinutes == 0 {
+		p.ApprovalWindowMinutes = defaultApprovalWindowMinutes
+	}
+	if p.ApprovalWindowMinutes < 0 || p.ApprovalWindowMinutes > maxApprovalWindowMinutes {
+		return fmt.Errorf("%w: approval window must be from 1 to %d minutes", domain.ErrInvalidApprovalPolicy, maxApprovalWindowMinutes)
+	}
+
+	if len(p.Approvers) == 0 || len(p.Approvers) > maxApprovers {
+		return fmt.Errorf("%w: from 1 to %d approvers are allowed", domain.ErrInvalidApprovalPolicy, maxApprovers)
+	}
+
+	p.ApproverIDs = make([]int, 0, len(p.Approvers))
+	for _, email := range p.Approvers {
+		user, err := aps.userRepository.GetUserByEmail(ctx, strings.TrimSpace(email))
+		if err != nil {
+			if errors.Is(err, domain.ErrUserNotExists) {
+				return fmt.Errorf("%w: approver %q is not registered", domain.ErrInvalidApprovalPolicy, email)
+			}
+			return err
+		}
+		if user.ID == userID {
+			return fmt.Errorf("%w: users can't approve their own campaigns", domain.ErrInvalidApprovalPolicy)
+		}
+		if !slices.Contains(p.ApproverIDs, user.ID) {
+			p.ApproverIDs = append(p.ApproverIDs, user.ID)
+		}
+	}
+
+	return nil
+}
+
+// relaxesApprovalPolicy reports whether p lets through a campaign that current holds for approval,
+// or lets someone approve campaigns who can't under current. A zero threshold is no threshold.
+func relaxesApprovalPolicy(current, p *models.ApprovalPolicy) bool {
+	if current.RequireForCritical && !p.RequireForCritical {
+		return true
+	}
+	if current.RecipientThreshold > 0 && (p.RecipientThreshold == 0 || p.RecipientThreshold > current.RecipientThreshold) {
+		return true
+	}
+	for _, id := range p.ApproverIDs {
+		if !slices.Contains(current.ApproverIDs, id) {
+			return true
+		}
+	}
+
+	return false
+}
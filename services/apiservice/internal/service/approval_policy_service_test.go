@@ -0,0 +1,267 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestApprovalPolicyService_UpdateApprovalPolicy(t *testing.T) {
+	users := map[string]*models.User{
+		"alice@example.com": {ID: 1, Email: "alice@example.com"},
+		"bob@example.com":   {ID: 2, Email: "bob@example.com"},
+		"carol@example.com": {ID: 3, Email: "carol@example.com"},
+	}
+
+	tests := map[string]struct {
+		policy  *models.ApprovalPolicy
+		want    *models.ApprovalPolicy
+		wantErr error
+	}{
+		"threshold with default window": {
+			policy: &models.ApprovalPolicy{RecipientThreshold: 1000, Approvers: []string{" bob@example.com", "carol@example.com", "bob@example.com"}},
+			want: &models.ApprovalPolicy{
+				RecipientThreshold: 1000, ApprovalWindowMinutes: 60,
+				Approvers:   []string{" bob@example.com", "carol@example.com", "bob@example.com"},
+				ApproverIDs: []int{2, 3},
+			},
+		},
+		"critical only": {
+			policy: &models.ApprovalPolicy{RequireForCritical: true, ApprovalWindowMinutes: 15, Approvers: []string{"bob@example.com"}},
+			want:   &models.ApprovalPolicy{RequireForCritical: true, ApprovalWindowMinutes: 15, Approvers: []string{"bob@example.com"}, ApproverIDs: []int{2}},
+		},
+		"requires nothing": {
+			policy:  &models.ApprovalPolicy{Approvers: []string{"bob@example.com"}},
+			wantErr: domain.ErrInvalidApprovalPolicy,
+		},
+		"negative threshold": {
+			policy:  &models.ApprovalPolicy{RecipientThreshold: -1, RequireForCritical: true, Approvers: []string{"bob@example.com"}},
+			wantErr: domain.ErrInvalidApprovalPolicy,
+		},
+		"window too long": {
+			policy:  &models.ApprovalPolicy{RequireForCritical: true, ApprovalWindowMinutes: 8 * 24 * 60, Approvers: []string{"bob@example.com"}},
+			wantErr: domain.ErrInvalidApprovalPolicy,
+		},
+		"no approvers": {
+			policy:  &models.ApprovalPolicy{RequireForCritical: true},
+			wantErr: domain.ErrInvalidApprovalPolicy,
+		},
+		"unknown approver": {
+			policy:  &models.ApprovalPolicy{RequireForCritical: true, Approvers: []string{"dave@example.com"}},
+			wantErr: domain.ErrInvalidApprovalPolicy,
+		},
+		"self approval": {
+			policy:  &models.ApprovalPolicy{RequireForCritical: true, Approvers: []string{"alice@example.com"}},
+			wantErr: domain.ErrInvalidApprovalPolicy,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			apr := new(MockApprovalPolicyRepository)
+			ur := new(MockUserRepository)
+			for email, u := range users {
+				ur.On("GetUserByEmail", mock.Anything, email).Return(u, nil).Maybe()
+			}
+			ur.On("GetUserByEmail", mock.Anything, "dave@example.com").Return((*models.User)(nil), domain.ErrUserNotExists).Maybe()
+			if tc.want != nil {
+				apr.On("GetApprovalPolicyByUserID", mock.Anything, 1).Return(nil, domain.ErrApprovalPolicyNotExists).Once()
+				apr.On("UpsertApprovalPolicy", mock.Anything, 1, tc.want).Return(tc.want, nil).Once()
+			}
+			svc := service.NewApprovalPolicyService(apr, ur)
+
+			got, change, err := svc.UpdateApprovalPolicy(context.Background(), 1, tc.policy)
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Nil(t, got)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.want, got)
+			}
+			assert.Nil(t, change)
+			apr.AssertExpectations(t)
+		})
+	}
+}
+
+func TestApprovalPolicyService_UpdateApprovalPolicy_Relaxing(t *testing.T) {
+	current := &models.ApprovalPolicy{
+		RecipientThreshold: 1000, RequireForCritical: true, ApprovalWindowMinutes: 30,
+		Approvers: []string{"bob@example.com", "carol@example.com"}, ApproverIDs: []int{2, 3},
+	}
+
+	tests := map[string]struct {
+		policy   *models.ApprovalPolicy
+		relaxing bool
+	}{
+		"lower threshold": {
+			policy: &models.ApprovalPolicy{RecipientThreshold: 500, RequireForCritical: true, Approvers: []string{"bob@example.com", "carol@example.com"}},
+		},
+		"fewer approvers and another window": {
+			policy: &models.ApprovalPolicy{RecipientThreshold: 1000, RequireForCritical: true, ApprovalWindowMinutes: 120, Approvers: []string{"bob@example.com"}},
+		},
+		"higher threshold": {
+			policy:   &models.ApprovalPolicy{RecipientThreshold: 5000, RequireForCritical: true, Approvers: []string{"bob@example.com"}},
+			relaxing: true,
+		},
+		"threshold dropped": {
+			policy:   &models.ApprovalPolicy{RequireForCritical: true, Approvers: []string{"bob@example.com"}},
+			relaxing: true,
+		},
+		"critical no longer required": {
+			policy:   &models.ApprovalPolicy{RecipientThreshold: 1000, Approvers: []string{"bob@example.com"}},
+			relaxing: true,
+		},
+		"new approver": {
+			policy:   &models.ApprovalPolicy{RecipientThreshold: 1000, RequireForCritical: true, Approvers: []string{"dave@example.com"}},
+			relaxing: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			apr := new(MockApprovalPolicyRepository)
+			ur := new(MockUserRepository)
+			for id, email := range map[int]string{2: "bob@example.com", 3: "carol@example.com", 4: "dave@example.com"} {
+				ur.On("GetUserByEmail", mock.Anything, email).Return(&models.User{ID: id, Email: email}, nil).Maybe()
+			}
+			apr.On("GetApprovalPolicyByUserID", mock.Anything, 1).Return(current, nil).Once()
+
+			pending := &models.ApprovalPolicyChange{ID: 5, UserID: 1, Policy: tc.policy, Status: models.ApprovalPending}
+			if tc.relaxing {
+				apr.On("CreatePolicyChange", mock.Anything, mock.MatchedBy(func(c *models.ApprovalPolicyChange) bool {
+					return c.UserID == 1 && c.Policy == tc.policy && assert.ObjectsAreEqual([]int{2, 3}, c.ApproverIDs) &&
+						time.Until(c.ExpiresAt) > 29*time.Minute && time.Until(c.ExpiresAt) <= 30*time.Minute
+				})).Return(pending, nil).Once()
+			} else {
+				apr.On("UpsertApprovalPolicy", mock.Anything, 1, tc.policy).Return(tc.policy, nil).Once()
+			}
+			svc := service.NewApprovalPolicyService(apr, ur)
+
+			got, change, err := svc.UpdateApprovalPolicy(context.Background(), 1, tc.policy)
+
+			assert.NoError(t, err)
+			if tc.relaxing {
+				assert.Nil(t, got)
+				assert.Equal(t, pending, change)
+			} else {
+				assert.Equal(t, tc.policy, got)
+				assert.Nil(t, change)
+			}
+			apr.AssertExpectations(t)
+		})
+	}
+}
+
+func TestApprovalPolicyService_DeleteApprovalPolicy(t *testing.T) {
+	current := &models.ApprovalPolicy{RequireForCritical: true, ApprovalWindowMinutes: 60, ApproverIDs: []int{2}}
+
+	tests := map[string]struct {
+		getErr    error
+		createErr error
+		wantErr   error
+	}{
+		"deletion pending": {},
+		"not configured": {
+			getErr:  domain.ErrApprovalPolicyNotExists,
+			wantErr: domain.ErrApprovalPolicyNotExists,
+		},
+		"earlier change pending": {
+			createErr: domain.ErrApprovalPolicyChangePending,
+			wantErr:   domain.ErrApprovalPolicyChangePending,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			apr := new(MockApprovalPolicyRepository)
+			if tc.getErr != nil {
+				apr.On("GetApprovalPolicyByUserID", mock.Anything, 1).Return(nil, tc.getErr).Once()
+			} else {
+				apr.On("GetApprovalPolicyByUserID", mock.Anything, 1).Return(current, nil).Once()
+			}
+
+			var pending *models.ApprovalPolicyChange
+			if tc.createErr == nil {
+				pending = &models.ApprovalPolicyChange{ID: 5, UserID: 1, Status: models.ApprovalPending}
+			}
+			if tc.getErr == nil {
+				apr.On("CreatePolicyChange", mock.Anything, mock.MatchedBy(func(c *models.ApprovalPolicyChange) bool {
+					return c.UserID == 1 && c.Policy == nil && assert.ObjectsAreEqual([]int{2}, c.ApproverIDs)
+				})).Return(pending, tc.createErr).Once()
+			}
+			svc := service.NewApprovalPolicyService(apr, new(MockUserRepository))
+
+			change, err := svc.DeleteApprovalPolicy(context.Background(), 1)
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Nil(t, change)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, pending, change)
+			}
+			apr.AssertExpectations(t)
+		})
+	}
+}
+
+func TestApprovalPolicyService_DecidePolicyChange(t *testing.T) {
+	tests := map[string]struct {
+		status  models.ApprovalStatus
+		userID  int
+		comment string
+		getErr  error
+		wantErr error
+	}{
+		"pending":       {status: models.ApprovalPending, userID: 2},
+		"requester":     {status: models.ApprovalPending, userID: 1, wantErr: domain.ErrNotApprover},
+		"expired":       {status: models.ApprovalExpired, userID: 2, wantErr: domain.ErrApprovalExpired},
+		"decided":       {status: models.ApprovalRejected, userID: 2, wantErr: domain.ErrApprovalNotPending},
+		"not found":     {userID: 2, getErr: domain.ErrApprovalPolicyChangeNotExists, wantErr: domain.ErrApprovalPolicyChangeNotExists},
+		"long comment":  {userID: 2, comment: string(make([]rune, 1001)), wantErr: domain.ErrInvalidApprovalComment},
+		"with comment":  {status: models.ApprovalPending, userID: 2, comment: "fine by me"},
+		"other decider": {status: models.ApprovalPending, userID: 3, wantErr: domain.ErrNotApprover},
+	}
+
+	for _, decision := range []models.ApprovalStatus{models.ApprovalApproved, models.ApprovalRejected} {
+		for name, tc := range tests {
+			t.Run(string(decision)+" "+name, func(t *testing.T) {
+				apr := new(MockApprovalPolicyRepository)
+				stored := &models.ApprovalPolicyChange{ID: 5, UserID: 1, Status: tc.status, ApproverIDs: []int{2}}
+				if tc.getErr != nil {
+					apr.On("GetPolicyChangeByID", mock.Anything, tc.userID, 5).Return(nil, tc.getErr).Once()
+				} else if tc.wantErr != domain.ErrInvalidApprovalComment {
+					apr.On("GetPolicyChangeByID", mock.Anything, tc.userID, 5).Return(stored, nil).Once()
+				}
+				decided := &models.ApprovalPolicyChange{ID: 5, UserID: 1, Status: decision, ApproverIDs: []int{2}}
+				if tc.wantErr == nil {
+					apr.On("DecidePolicyChange", mock.Anything, tc.userID, 5, decision, tc.comment).Return(decided, nil).Once()
+				}
+				svc := service.NewApprovalPolicyService(apr, new(MockUserRepository))
+
+				decide := svc.ApprovePolicyChange
+				if decision == models.ApprovalRejected {
+					decide = svc.RejectPolicyChange
+				}
+				got, err := decide(context.Background(), tc.userID, 5, tc.comment)
+
+				if tc.wantErr != nil {
+					assert.ErrorIs(t, err, tc.wantErr)
+					assert.Nil(t, got)
+				} else {
+					assert.NoError(t, err)
+					assert.Equal(t, decided, got)
+				}
+				apr.AssertExpectations(t)
+			})
+		}
+	}
+}
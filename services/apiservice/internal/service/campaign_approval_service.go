@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+	"unicode/utf8"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/google/uuid"
+)
+
+const (
+	maxApprovalCommentLen = 1000
+	// maxTestSendRecipients is the most contacts a test send reaches without approval.
+	maxTestSendRecipients = 20
+)
+
+// CampaignApprovalService holds campaigns that need the approval of a second user, per the
+// approval policy of their sender, until an approver approves or rejects them.
+type CampaignApprovalService struct {
+	repository         domain.CampaignApprovalRepository
+	policyRepository   domain.ApprovalPolicyRepository
+	templateRepository domain.TemplateRepository
+	sender             domain.SendNotificationService
+}
+
+// NewCampaignApprovalService creates a CampaignApprovalService. Approved campaigns are sent through sns.
+func NewCampaignApprovalService(r domain.CampaignApprovalRepository, pr domain.ApprovalPolicyRepository, tr domain.TemplateRepository, sns domain.SendNotificationService) *CampaignApprovalService {
+	return &CampaignApprovalService{
+		repository:         r,
+		policyRepository:   pr,
+		templateRepository: tr,
+		sender:             sns,
+	}
+}
+
+// RequestApproval checks whether the campaign of the template with the options needs approval
+// under the user's policy: because it is critical and the policy requires approval of critical
+// campaigns, or because it would reach more contacts than the threshold of the policy. If it does,
+// the campaign is recorded as pending until the approval window of the policy passes, and returned;
+// otherwise RequestApproval returns nil and the campaign can be sent right away. Test sends to
+// at most 20 contacts never need approval, so that a campaign can be checked before it is requested.
+// The options are validated as SendNotification does and the same errors are returned for them,
+// as well as domain.ErrContactNotExists if the campaign has no recipients.
+func (cas *CampaignApprovalService) RequestApproval(ctx context.Context, userID, templateID int, opts *domain.SendNotificationRequest) (*models.CampaignApproval, error) {
+	return cas.request(ctx, userID, opts, func(ctx context.Context) (*models.CampaignApproval, error) {
+		tmpl, err := cas.templateRepository.GetTemplateByID(ctx, userID, templateID)
+		if err != nil {
+			return nil, err
+		}
+		return &models.CampaignApproval{TemplateID: templateID, Message: tmpl.Body}, nil
+	})
+}
+
+// RequestMessageApproval is RequestApproval for a text that isn't a stored template, such as a
+// CAP alert, which is sent as the campaign with the given ID once approved.
+func (cas *CampaignApprovalService) RequestMessageApproval(ctx context.Context, userID int, campaignID uuid.UUID, text string, opts *domain.SendNotificationRequest) (*models.CampaignApproval, error) {
+	return cas.request(ctx, userID, opts, func(context.Context) (*models.CampaignApproval, error) {
+		return &models.CampaignApproval{CampaignID: &campaignID, Message: text}, nil
+	})
+}
+
+// request records the campaign described by newApproval as pending if it needs approval under
+// the user's policy, and returns nil otherwise.
+func (cas *CampaignApprovalService) request(ctx context.Context, userID int, opts *domain.SendNotificationRequest, newApproval func(context.Context) (*models.CampaignApproval, error)) (*models.CampaignApproval, error) {
+	if opts == nil {
+		opts = &domain.SendNotificationRequest{}
+	}
+
+	policy, err := cas.policyRepository.GetApprovalPolicyByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrApprovalPolicyNotExists) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	recipients, err := cas.sender.CountRecipients(ctx, userID, opts)
+	if err != nil {
+		return nil, err
+	}
+	if recipients == 0 {
+		return nil, domain.ErrContactNotExists
+	}
+	if opts.Test && recipients <= maxTestSendRecipients {
+		return nil, nil
+	}
+
+	var reason models.ApprovalReason
+	switch {
+	case policy.RequireForCritical && (opts.Priority == "" || opts.Priority == models.PriorityCritical):
+		reason = models.ApprovalReasonCritical
+	case policy.RecipientThreshold > 0 && recipients > policy.RecipientThreshold:
+		reason = models.ApprovalReasonRecipients
+	default:
+		return nil, nil
+	}
+
+	a, err := newApproval(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	a.Options, err = json.Marshal(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	a.UserID = userID
+	a.Recipients = recipients
+	a.Reason = reason
+	a.ApproverIDs = policy.ApproverIDs
+	a.ExpiresAt = time.Now().Add(time.Duration(policy.ApprovalWindowMinutes) * time.Minute)
+
+	return cas.repository.CreateApproval(ctx, a)
+}
+
+// GetApprovals returns the campaign approvals requested by the user or awaiting their decision.
+func (cas *CampaignApprovalService) GetApprovals(ctx context.Context, userID int) ([]*models.CampaignApproval, error) {
+	return cas.repository.GetApprovalsByUserID(ctx, userID)
+}
+
+// GetApprovalByID returns a campaign approval requested by the user or awaiting their decision,
+// along with its audit trail.
+func (cas *CampaignApprovalService) GetApprovalByID(ctx context.Context, userID, approvalID int) (*models.CampaignApproval, error) {
+	return cas.repository.GetApprovalByID(ctx, userID, approvalID)
+}
+
+// ApproveCampaign records the approval of the campaign by the user and sends it to the contacts
+// matching its options now; messages without a template are sent as their campaign. If sending
+// fails, the approval is recorded as failed and the campaign has to be requested again. Returns
+// domain.ErrApprovalTemplateChanged if the template has changed since approval was requested,
+// and the errors of RejectCampaign.
+func (cas *CampaignApprovalService) ApproveCampaign(ctx context.Context, userID, approvalID int, comment string) (*models.CampaignApproval, error) {
+	a, err := cas.pendingApproval(ctx, userID, approvalID, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.CampaignID == nil {
+		tmpl, err := cas.templateRepository.GetTemplateByID(ctx, a.UserID, a.TemplateID)
+		if err != nil {
+			return nil, err
+		}
+		if tmpl.Body != a.Message {
+			return nil, domain.ErrApprovalTemplateChanged
+		}
+	}
+
+	var opts domain.SendNotificationRequest
+	err = json.Unmarshal(a.Options, &opts)
+	if err != nil {
+		return nil, err
+	}
+
+	approved, err := cas.repository.DecideApproval(ctx, userID, approvalID, models.ApprovalApproved, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.CampaignID != nil {
+		err = cas.sender.SendMessage(ctx, a.UserID, *a.CampaignID, a.Message, &opts)
+	} else {
+		err = cas.sender.SendNotification(ctx, a.UserID, a.TemplateID, &opts)
+	}
+	if err != nil {
+		ferr := cas.repository.FailApproval(context.WithoutCancel(ctx), approvalID)
+		return nil, errors.Join(err, ferr)
+	}
+
+	return approved, nil
+}
+
+// RejectCampaign records the rejection of the campaign by the user; it is never sent.
+// Returns domain.ErrCampaignApprovalNotExists for approvals the user can't see, domain.ErrNotApprover
+// if the user isn't an approver of the campaign, domain.ErrApprovalExpired if its deadline has passed,
+// domain.ErrApprovalNotPending if it has already been decided on, and an error wrapping
+// domain.ErrInvalidApprovalComment for comments longer than 1000 characters.
+func (cas *CampaignApprovalService) RejectCampaign(ctx context.Context, userID, approvalID int, comment string) (*models.CampaignApproval, error) {
+	_, err := cas.pendingApproval(ctx, userID, approvalID, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	return cas.repository.DecideApproval(ctx, userID, approvalID, models.ApprovalRejected, comment)
+}
+
+// pendingApproval returns the approval if the user can decide on it now. An approval found past
+// its deadline has its expiry recorded.
+func (cas *CampaignApprovalService) pendingApproval(ctx context.Context, userID, approvalID int, comment string) (*models.CampaignApproval, error) {
+	if utf8.RuneCountInString(comment) > maxApprovalCommentLen {
+		return nil, fmt.Errorf("%w: comment is longer than %d characters", domain.ErrInvalidApprovalComment, maxApprovalCommentLen)
+	}
+
+	a, err := cas.repository.GetApprovalByID(ctx, userID, approvalID)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(a.ApproverIDs, userID) {
+		return nil, domain.ErrNotApprover
+	}
+
+	switch a.Status {
+	case models.ApprovalPending:
+		return a, nil
+	case models.ApprovalExpired:
+		err = cas.repository.ExpireApproval(ctx, approvalID)
+		if err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrApprovalExpired
+	default:
+		return nil, domain.ErrApprovalNotPending
+	}
+}
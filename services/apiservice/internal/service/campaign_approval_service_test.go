@@ -0,0 +1,266 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCampaignApprovalService_RequestApproval(t *testing.T) {
+	userID, templateID := 1, 5
+	policy := &models.ApprovalPolicy{RecipientThreshold: 100, RequireForCritical: true, ApprovalWindowMinutes: 30, ApproverIDs: []int{2}}
+
+	tests := map[string]struct {
+		policy     *models.ApprovalPolicy
+		policyErr  error
+		opts       *domain.SendNotificationRequest
+		recipients int
+		wantReason models.ApprovalReason
+		wantErr    error
+	}{
+		"no policy": {
+			policyErr: domain.ErrApprovalPolicyNotExists,
+		},
+		"below threshold": {
+			policy:     policy,
+			opts:       &domain.SendNotificationRequest{Priority: models.PriorityNormal},
+			recipients: 100,
+		},
+		"above threshold": {
+			policy:     policy,
+			opts:       &domain.SendNotificationRequest{Priority: models.PriorityNormal},
+			recipients: 101,
+			wantReason: models.ApprovalReasonRecipients,
+		},
+		"critical by default": {
+			policy:     policy,
+			recipients: 1,
+			wantReason: models.ApprovalReasonCritical,
+		},
+		"critical not required": {
+			policy:     &models.ApprovalPolicy{RecipientThreshold: 100, ApprovalWindowMinutes: 30, ApproverIDs: []int{2}},
+			opts:       &domain.SendNotificationRequest{Priority: models.PriorityCritical},
+			recipients: 5,
+		},
+		"small test send": {
+			policy:     policy,
+			opts:       &domain.SendNotificationRequest{Priority: models.PriorityCritical, Test: true},
+			recipients: 20,
+		},
+		"large test send": {
+			policy:     policy,
+			opts:       &domain.SendNotificationRequest{Priority: models.PriorityCritical, Test: true},
+			recipients: 21,
+			wantReason: models.ApprovalReasonCritical,
+		},
+		"no recipients": {
+			policy:  policy,
+			wantErr: domain.ErrContactNotExists,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			car := new(MockCampaignApprovalRepository)
+			apr := new(MockApprovalPolicyRepository)
+			tr := new(MockTemplateRepository)
+			sns := new(MockSendNotificationService)
+
+			apr.On("GetApprovalPolicyByUserID", mock.Anything, userID).Return(tc.policy, tc.policyErr).Once()
+			if tc.policy != nil {
+				sns.On("CountRecipients", mock.Anything, userID, mock.Anything).Return(tc.recipients, nil).Once()
+			}
+			if tc.wantReason != "" {
+				tr.On("GetTemplateByID", mock.Anything, userID, templateID).Return(&models.Template{ID: templateID, Body: "Evacuate"}, nil).Once()
+				car.On("CreateApproval", mock.Anything, mock.MatchedBy(func(a *models.CampaignApproval) bool {
+					return a.UserID == userID && a.TemplateID == templateID && a.Message == "Evacuate" &&
+						a.Reason == tc.wantReason && a.Recipients == tc.recipients &&
+						assert.ObjectsAreEqual([]int{2}, a.ApproverIDs) &&
+						time.Until(a.ExpiresAt) > 29*time.Minute && time.Until(a.ExpiresAt) <= 30*time.Minute
+				})).Return(&models.CampaignApproval{ID: 9, Status: models.ApprovalPending}, nil).Once()
+			}
+			svc := service.NewCampaignApprovalService(car, apr, tr, sns)
+
+			got, err := svc.RequestApproval(context.Background(), userID, templateID, tc.opts)
+
+			assert.ErrorIs(t, err, tc.wantErr)
+			if tc.wantReason != "" {
+				assert.NotNil(t, got)
+			} else {
+				assert.Nil(t, got)
+			}
+			car.AssertExpectations(t)
+			apr.AssertExpectations(t)
+			tr.AssertExpectations(t)
+			sns.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCampaignApprovalService_ApproveCampaign(t *testing.T) {
+	requesterID, approverID, approvalID, templateID := 1, 2, 7, 5
+	opts := &domain.SendNotificationRequest{Priority: models.PriorityNormal}
+	rawOpts, _ := json.Marshal(opts)
+	pending := func() *models.CampaignApproval {
+		return &models.CampaignApproval{
+			ID: approvalID, UserID: requesterID, TemplateID: templateID, Message: "Evacuate",
+			Options: rawOpts, Status: models.ApprovalPending, ApproverIDs: []int{approverID},
+		}
+	}
+
+	t.Run("approves and sends", func(t *testing.T) {
+		car := new(MockCampaignApprovalRepository)
+		tr := new(MockTemplateRepository)
+		sns := new(MockSendNotificationService)
+		approved := pending()
+		approved.Status = models.ApprovalApproved
+
+		car.On("GetApprovalByID", mock.Anything, approverID, approvalID).Return(pending(), nil).Once()
+		tr.On("GetTemplateByID", mock.Anything, requesterID, templateID).Return(&models.Template{Body: "Evacuate"}, nil).Once()
+		car.On("DecideApproval", mock.Anything, approverID, approvalID, models.ApprovalApproved, "ok").Return(approved, nil).Once()
+		sns.On("SendNotification", mock.Anything, requesterID, templateID, opts).Return(nil).Once()
+		svc := service.NewCampaignApprovalService(car, new(MockApprovalPolicyRepository), tr, sns)
+
+		got, err := svc.ApproveCampaign(context.Background(), approverID, approvalID, "ok")
+
+		assert.NoError(t, err)
+		assert.Equal(t, approved, got)
+		car.AssertExpectations(t)
+		tr.AssertExpectations(t)
+		sns.AssertExpectations(t)
+	})
+
+	t.Run("sends a message as its campaign", func(t *testing.T) {
+		car := new(MockCampaignApprovalRepository)
+		tr := new(MockTemplateRepository)
+		sns := new(MockSendNotificationService)
+		campaignID := uuid.New()
+		msg := pending()
+		msg.TemplateID = 0
+		msg.CampaignID = &campaignID
+
+		car.On("GetApprovalByID", mock.Anything, approverID, approvalID).Return(msg, nil).Once()
+		car.On("DecideApproval", mock.Anything, approverID, approvalID, models.ApprovalApproved, "").Return(msg, nil).Once()
+		sns.On("SendMessage", mock.Anything, requesterID, campaignID, "Evacuate", opts).Return(nil).Once()
+		svc := service.NewCampaignApprovalService(car, new(MockApprovalPolicyRepository), tr, sns)
+
+		_, err := svc.ApproveCampaign(context.Background(), approverID, approvalID, "")
+
+		assert.NoError(t, err)
+		car.AssertExpectations(t)
+		sns.AssertExpectations(t)
+		tr.AssertNotCalled(t, "GetTemplateByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("send failure marks approval failed", func(t *testing.T) {
+		car := new(MockCampaignApprovalRepository)
+		tr := new(MockTemplateRepository)
+		sns := new(MockSendNotificationService)
+		sendErr := errors.New("kafka down")
+
+		car.On("GetApprovalByID", mock.Anything, approverID, approvalID).Return(pending(), nil).Once()
+		tr.On("GetTemplateByID", mock.Anything, requesterID, templateID).Return(&models.Template{Body: "Evacuate"}, nil).Once()
+		car.On("DecideApproval", mock.Anything, approverID, approvalID, models.ApprovalApproved, "").Return(pending(), nil).Once()
+		sns.On("SendNotification", mock.Anything, requesterID, templateID, opts).Return(sendErr).Once()
+		car.On("FailApproval", mock.Anything, approvalID).Return(nil).Once()
+		svc := service.NewCampaignApprovalService(car, new(MockApprovalPolicyRepository), tr, sns)
+
+		got, err := svc.ApproveCampaign(context.Background(), approverID, approvalID, "")
+
+		assert.ErrorIs(t, err, sendErr)
+		assert.Nil(t, got)
+		car.AssertExpectations(t)
+		sns.AssertExpectations(t)
+	})
+
+	t.Run("template changed", func(t *testing.T) {
+		car := new(MockCampaignApprovalRepository)
+		tr := new(MockTemplateRepository)
+		sns := new(MockSendNotificationService)
+
+		car.On("GetApprovalByID", mock.Anything, approverID, approvalID).Return(pending(), nil).Once()
+		tr.On("GetTemplateByID", mock.Anything, requesterID, templateID).Return(&models.Template{Body: "All clear"}, nil).Once()
+		svc := service.NewCampaignApprovalService(car, new(MockApprovalPolicyRepository), tr, sns)
+
+		_, err := svc.ApproveCampaign(context.Background(), approverID, approvalID, "")
+
+		assert.ErrorIs(t, err, domain.ErrApprovalTemplateChanged)
+		car.AssertExpectations(t)
+		sns.AssertNotCalled(t, "SendNotification", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestCampaignApprovalService_RejectCampaign(t *testing.T) {
+	requesterID, approverID, approvalID := 1, 2, 7
+	approval := func(status models.ApprovalStatus) *models.CampaignApproval {
+		return &models.CampaignApproval{ID: approvalID, UserID: requesterID, Status: status, ApproverIDs: []int{approverID}}
+	}
+
+	t.Run("rejects", func(t *testing.T) {
+		car := new(MockCampaignApprovalRepository)
+		rejected := approval(models.ApprovalRejected)
+		car.On("GetApprovalByID", mock.Anything, approverID, approvalID).Return(approval(models.ApprovalPending), nil).Once()
+		car.On("DecideApproval", mock.Anything, approverID, approvalID, models.ApprovalRejected, "too broad").Return(rejected, nil).Once()
+		svc := service.NewCampaignApprovalService(car, new(MockApprovalPolicyRepository), new(MockTemplateRepository), new(MockSendNotificationService))
+
+		got, err := svc.RejectCampaign(context.Background(), approverID, approvalID, "too broad")
+
+		assert.NoError(t, err)
+		assert.Equal(t, rejected, got)
+		car.AssertExpectations(t)
+	})
+
+	t.Run("requester can't decide", func(t *testing.T) {
+		car := new(MockCampaignApprovalRepository)
+		car.On("GetApprovalByID", mock.Anything, requesterID, approvalID).Return(approval(models.ApprovalPending), nil).Once()
+		svc := service.NewCampaignApprovalService(car, new(MockApprovalPolicyRepository), new(MockTemplateRepository), new(MockSendNotificationService))
+
+		_, err := svc.RejectCampaign(context.Background(), requesterID, approvalID, "")
+
+		assert.ErrorIs(t, err, domain.ErrNotApprover)
+		car.AssertExpectations(t)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		car := new(MockCampaignApprovalRepository)
+		car.On("GetApprovalByID", mock.Anything, approverID, approvalID).Return(approval(models.ApprovalExpired), nil).Once()
+		car.On("ExpireApproval", mock.Anything, approvalID).Return(nil).Once()
+		svc := service.NewCampaignApprovalService(car, new(MockApprovalPolicyRepository), new(MockTemplateRepository), new(MockSendNotificationService))
+
+		_, err := svc.RejectCampaign(context.Background(), approverID, approvalID, "")
+
+		assert.ErrorIs(t, err, domain.ErrApprovalExpired)
+		car.AssertExpectations(t)
+	})
+
+	t.Run("already decided", func(t *testing.T) {
+		car := new(MockCampaignApprovalRepository)
+		car.On("GetApprovalByID", mock.Anything, approverID, approvalID).Return(approval(models.ApprovalApproved), nil).Once()
+		svc := service.NewCampaignApprovalService(car, new(MockApprovalPolicyRepository), new(MockTemplateRepository), new(MockSendNotificationService))
+
+		_, err := svc.RejectCampaign(context.Background(), approverID, approvalID, "")
+
+		assert.ErrorIs(t, err, domain.ErrApprovalNotPending)
+		car.AssertExpectations(t)
+	})
+
+	t.Run("comment too long", func(t *testing.T) {
+		car := new(MockCampaignApprovalRepository)
+		svc := service.NewCampaignApprovalService(car, new(MockApprovalPolicyRepository), new(MockTemplateRepository), new(MockSendNotificationService))
+
+		_, err := svc.RejectCampaign(context.Background(), approverID, approvalID, strings.Repeat("я", 1001))
+
+		assert.ErrorIs(t, err, domain.ErrInvalidApprovalComment)
+		car.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+)
+
+// CampaignService lists the campaigns users sent from their templates.
+type CampaignService struct {
+	repository   domain.CampaignRepository
+	defaultLimit int
+	maxLimit     int
+}
+
+// NewCampaignService creates a CampaignService with the given repository and page limits.
+func NewCampaignService(r domain.CampaignRepository, defaultLimit, maxLimit int) *CampaignService {
+	return &CampaignService{
+		repository:   r,
+		defaultLimit: defaultLimit,
+		maxLimit:     maxLimit,
+	}
+}
+
+// GetCampaigns retrieves a page of the user's campaigns matching the filter, newest first, each
+// with the template version it was sent from. Pages are read by limit and offset, the limit being
+// clamped to the configured maximum.
+func (cs *CampaignService) GetCampaigns(ctx context.Context, userID int, filter domain.CampaignFilter, page domain.PageRequest) ([]*models.Campaign, error) {
+	limit, offset := clampPage(page.Limit, page.Offset, cs.defaultLimit, cs.maxLimit)
+
+	return cs.repository.GetCampaignsByUserID(ctx, userID, filter, limit, offset)
+}
@@ -0,0 +1,53 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCampaignService_GetCampaigns(t *testing.T) {
+	campaigns := []*models.Campaign{
+		{ID: uuid.New(), UserID: 1, TemplateID: 2, TemplateVersion: 3, Body: "Evacuate"},
+	}
+	filter := domain.CampaignFilter{TemplateID: 2}
+
+	tests := map[string]struct {
+		page       domain.PageRequest
+		wantLimit  int
+		wantOffset int
+	}{
+		"default limit": {
+			page:      domain.PageRequest{},
+			wantLimit: 50,
+		},
+		"clamped limit": {
+			page:       domain.PageRequest{Limit: 1000, Offset: 20},
+			wantLimit:  100,
+			wantOffset: 20,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := new(MockCampaignRepository)
+			m.
+				On("GetCampaignsByUserID", mock.Anything, 1, filter, tc.wantLimit, tc.wantOffset).
+				Return(campaigns, nil).
+				Once()
+
+			svc := service.NewCampaignService(m, 50, 100)
+			out, err := svc.GetCampaigns(context.Background(), 1, filter, tc.page)
+
+			assert.NoError(t, err)
+			assert.Equal(t, campaigns, out)
+			m.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/cap"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+// CAPService launches, updates and cancels campaigns from Common Alerting Protocol alerts.
+type CAPService struct {
+	repository  domain.CAPAlertRepository
+	sender      domain.SendNotificationService
+	approvals   domain.CampaignApprovalService
+	kafkaWriter domain.KafkaWriter
+}
+
+// NewCAPService creates a CAPService. Campaigns are sent through sns unless as holds them for
+// approval, and cancellations of campaigns are written with kw to the campaign cancellations topic.
+func NewCAPService(r domain.CAPAlertRepository, sns domain.SendNotificationService, as domain.CampaignApprovalService, kw domain.KafkaWriter) *CAPService {
+	return &CAPService{
+		repository:  r,
+		sender:      sns,
+		approvals:   as,
+		kafkaWriter: kw,
+	}
+}
+
+// IngestAlert acts on a CAP alert of the user according to its message type:
+//   - Alert launches a campaign with the text of the first info to the contacts inside the areas
+//     of all infos, or to all contacts if the alert has no areas;
+//   - Update launches a campaign the same way and then cancels the campaigns of the alerts it refers to;
+//   - Cancel cancels the campaigns of the alerts it refers to.
+//
+// Only notifications not yet handed to a provider are cancelled. Alerts whose status isn't Actual
+// and Ack and Error messages are ignored. Returns domain.ErrInvalidCAPAlert for alerts that can't be
+// acted on, domain.ErrInvalidArea for invalid areas, domain.ErrCAPAlertAlreadyExists for an alert
+// received before and domain.ErrCAPAlertNotExists for a cancellation of unknown alerts.
+func (cs *CAPService) IngestAlert(ctx context.Context, userID int, alert *cap.Alert) (*domain.CAPResult, error) {
+	if alert.Status != cap.StatusActual || alert.MsgType == cap.MsgTypeAck || alert.MsgType == cap.MsgTypeError {
+		return &domain.CAPResult{Action: domain.CAPActionIgnored, CancelledCampaigns: []uuid.UUID{}}, nil
+	}
+
+	refs, err := alert.ParseReferences()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidCAPAlert, err)
+	}
+	if alert.MsgType != cap.MsgTypeAlert && len(refs) == 0 {
+		return nil, fmt.Errorf("%w: %s must have references", domain.ErrInvalidCAPAlert, alert.MsgType)
+	}
+
+	var referenced []*models.CAPAlert
+	if len(refs) > 0 {
+		referenced, err = cs.repository.GetCAPAlertsByReferences(ctx, userID, refs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if alert.MsgType == cap.MsgTypeCancel {
+		return cs.cancelAlert(ctx, userID, alert, referenced)
+	}
+
+	return cs.launchAlert(ctx, userID, alert, referenced)
+}
+
+// launchAlert sends the alert to the contacts in its area, or holds it for approval if the approval
+// policy of the user requires it, like any other campaign. A held alert is sent as its campaign once
+// approved, so that updates and cancellations of it still apply.
+func (cs *CAPService) launchAlert(ctx context.Context, userID int, alert *cap.Alert, referenced []*models.CAPAlert) (*domain.CAPResult, error) {
+	if len(alert.Infos) == 0 {
+		return nil, fmt.Errorf("%w: %s has no info", domain.ErrInvalidCAPAlert, alert.MsgType)
+	}
+	text := alert.Infos[0].Text()
+	if text == "" {
+		return nil, fmt.Errorf("%w: info has no text", domain.ErrInvalidCAPAlert)
+	}
+	if alert.Expired(time.Now()) {
+		return nil, fmt.Errorf("%w: alert has expired", domain.ErrInvalidCAPAlert)
+	}
+
+	area, err := alert.Area()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidCAPAlert, err)
+	}
+	if area != nil {
+		err = validateArea(area)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	campaignID := uuid.New()
+	record, err := cs.repository.CreateCAPAlert(ctx, &models.CAPAlert{
+		UserID:     userID,
+		Sender:     alert.Sender,
+		Identifier: alert.Identifier,
+		Sent:       alert.Sent,
+		MsgType:    alert.MsgType,
+		CampaignID: &campaignID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &domain.SendNotificationRequest{
+		Priority: capPriority(alert.Infos),
+		Area:     area,
+	}
+	approval, err := cs.approvals.RequestMessageApproval(ctx, userID, campaignID, text, opts)
+	if err == nil && approval == nil {
+		err = cs.sender.SendMessage(ctx, userID, campaignID, text, opts)
+	}
+	// an alert for an area without contacts is still recorded, so that its updates are accepted
+	if err != nil && !errors.Is(err, domain.ErrContactNotExists) {
+		derr := cs.repository.DeleteCAPAlert(context.WithoutCancel(ctx), userID, record.ID)
+		if derr != nil {
+			return nil, errors.Join(err, derr)
+		}
+		return nil, err
+	}
+
+	cancelled, err := cs.cancelCampaigns(ctx, userID, referenced)
+	if err != nil {
+		return nil, err
+	}
+
+	action := domain.CAPActionLaunched
+	if alert.MsgType == cap.MsgTypeUpdate {
+		action = domain.CAPActionUpdated
+	}
+
+	return &domain.CAPResult{Action: action, CampaignID: &campaignID, CancelledCampaigns: cancelled, Approval: approval}, nil
+}
+
+func (cs *CAPService) cancelAlert(ctx context.Context, userID int, alert *cap.Alert, referenced []*models.CAPAlert) (*domain.CAPResult, error) {
+	if len(referenced) == 0 {
+		return nil, domain.ErrCAPAlertNotExists
+	}
+
+	_, err := cs.repository.CreateCAPAlert(ctx, &models.CAPAlert{
+		UserID:     userID,
+		Sender:     alert.Sender,
+		Identifier: alert.Identifier,
+		Sent:       alert.Sent,
+		MsgType:    alert.MsgType,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cancelled, err := cs.cancelCampaigns(ctx, userID, referenced)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.CAPResult{Action: domain.CAPActionCancelled, CancelledCampaigns: cancelled}, nil
+}
+
+// cancelCampaigns writes a cancellation of every campaign of the alerts that is not cancelled yet
+// and records that it was cancelled. Returns the IDs of the cancelled campaigns.
+func (cs *CAPService) cancelCampaigns(ctx context.Context, userID int, alerts []*models.CAPAlert) ([]uuid.UUID, error) {
+	cancelled := make([]uuid.UUID, 0, len(alerts))
+	alertIDs := make([]int, 0, len(alerts))
+	msgs := make([]kafka.Message, 0, len(alerts))
+	for _, a := range alerts {
+		if a.CampaignID == nil || a.CancelTime != nil {
+			continue
+		}
+
+		msgBytes, err := json.Marshal(&domain.CampaignCancellation{UserID: userID, CampaignID: *a.CampaignID})
+		if err != nil {
+			return nil, err
+		}
+
+		msgs = append(msgs, kafka.Message{Value: msgBytes})
+		cancelled = append(cancelled, *a.CampaignID)
+		alertIDs = append(alertIDs, a.ID)
+	}
+	if len(msgs) == 0 {
+		return cancelled, nil
+	}
+
+	err := cs.kafkaWriter.WriteMessages(ctx, msgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cs.repository.CancelCAPAlerts(ctx, userID, alertIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return cancelled, nil
+}
+
+// capPriority maps the infos of an alert to a priority. An alert is critical, and so ignores quiet
+// hours, if any info is Extreme or Severe, Immediate or Expected, and not Unlikely; otherwise it is normal.
+func capPriority(infos []cap.Info) models.Priority {
+	for _, info := range infos {
+		severe := info.Severity == cap.SeverityExtreme || info.Severity == cap.SeveritySevere
+		urgent := info.Urgency == cap.UrgencyImmediate || info.Urgency == cap.UrgencyExpected
+		if severe && urgent && info.Certainty != cap.CertaintyUnlikely {
+			return models.PriorityCritical
+		}
+	}
+	return models.PriorityNormal
+}
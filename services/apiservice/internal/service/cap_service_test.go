@@ -0,0 +1,328 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/cap"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/geo"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testCAPAlert(msgType string) *cap.Alert {
+	alert := &cap.Alert{
+		Identifier: "A2",
+		Sender:     "alerts@example.org",
+		Sent:       "2026-10-19T10:00:00+03:00",
+		Status:     cap.StatusActual,
+		MsgType:    msgType,
+		Infos: []cap.Info{{
+			Event:       "Flood",
+			Urgency:     cap.UrgencyImmediate,
+			Severity:    cap.SeveritySevere,
+			Certainty:   cap.CertaintyObserved,
+			Description: "The river is overflowing.",
+			Instruction: "Move to higher ground.",
+			Areas:       []cap.Area{{Desc: "Valley", Circles: []string{"55.75,37.62 2"}}},
+		}},
+	}
+	if msgType != cap.MsgTypeAlert {
+		alert.References = "alerts@example.org,A1,2026-10-19T09:00:00+03:00"
+	}
+	return alert
+}
+
+var testCAPRefs = []cap.Reference{{Sender: "alerts@example.org", Identifier: "A1", Sent: "2026-10-19T09:00:00+03:00"}}
+
+// noApprovals returns a CampaignApprovalService that lets every campaign be sent right away.
+func noApprovals() *MockCampaignApprovalService {
+	as := new(MockCampaignApprovalService)
+	as.On("RequestApproval", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	as.On("RequestMessageApproval", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	return as
+}
+
+func TestCAPService_IngestAlert_Alert(t *testing.T) {
+	alert := testCAPAlert(cap.MsgTypeAlert)
+
+	r := new(MockCAPAlertRepository)
+	sns := new(MockSendNotificationService)
+	kw := new(MockKafkaWriter)
+
+	var campaignID uuid.UUID
+	r.On("CreateCAPAlert", mock.Anything, mock.AnythingOfType("*models.CAPAlert")).
+		Run(func(args mock.Arguments) { campaignID = *args.Get(1).(*models.CAPAlert).CampaignID }).
+		Return(&models.CAPAlert{ID: 2}, nil).Once()
+	sns.On("SendMessage", mock.Anything, 1, mock.AnythingOfType("uuid.UUID"), "The river is overflowing.\nMove to higher ground.", &domain.SendNotificationRequest{
+		Priority: models.PriorityCritical,
+		Area:     &geo.Area{Circle: &geo.Circle{Lat: 55.75, Lon: 37.62, Radius: 2000}},
+	}).Return(nil).Once()
+
+	svc := service.NewCAPService(r, sns, noApprovals(), kw)
+	result, err := svc.IngestAlert(context.Background(), 1, alert)
+	require.NoError(t, err)
+
+	assert.Equal(t, domain.CAPActionLaunched, result.Action)
+	assert.Equal(t, &campaignID, result.CampaignID)
+	assert.Empty(t, result.CancelledCampaigns)
+	assert.Equal(t, campaignID, sns.Calls[0].Arguments.Get(2))
+	r.AssertExpectations(t)
+	sns.AssertExpectations(t)
+	kw.AssertNotCalled(t, "WriteMessages", mock.Anything, mock.Anything)
+}
+
+func TestCAPService_IngestAlert_HeldForApproval(t *testing.T) {
+	r := new(MockCAPAlertRepository)
+	sns := new(MockSendNotificationService)
+	kw := new(MockKafkaWriter)
+	pr := new(MockApprovalPolicyRepository)
+	ar := new(MockCampaignApprovalRepository)
+
+	var campaignID uuid.UUID
+	r.On("CreateCAPAlert", mock.Anything, mock.AnythingOfType("*models.CAPAlert")).
+		Run(func(args mock.Arguments) { campaignID = *args.Get(1).(*models.CAPAlert).CampaignID }).
+		Return(&models.CAPAlert{ID: 2}, nil).Once()
+	pr.On("GetApprovalPolicyByUserID", mock.Anything, 1).
+		Return(&models.ApprovalPolicy{RequireForCritical: true, ApproverIDs: []int{2}, ApprovalWindowMinutes: 30}, nil).Once()
+	sns.On("CountRecipients", mock.Anything, 1, mock.Anything).Return(3, nil).Once()
+	ar.On("CreateApproval", mock.Anything, mock.MatchedBy(func(a *models.CampaignApproval) bool {
+		return a.CampaignID != nil && *a.CampaignID == campaignID && a.TemplateID == 0 &&
+			a.Message == "The river is overflowing.\nMove to higher ground." && a.Reason == models.ApprovalReasonCritical
+	})).Return(&models.CampaignApproval{ID: 7, Status: models.ApprovalPending}, nil).Once()
+
+	as := service.NewCampaignApprovalService(ar, pr, new(MockTemplateRepository), sns)
+	svc := service.NewCAPService(r, sns, as, kw)
+	result, err := svc.IngestAlert(context.Background(), 1, testCAPAlert(cap.MsgTypeAlert))
+	require.NoError(t, err)
+
+	assert.Equal(t, domain.CAPActionLaunched, result.Action)
+	assert.Equal(t, &campaignID, result.CampaignID)
+	require.NotNil(t, result.Approval)
+	assert.Equal(t, 7, result.Approval.ID)
+	r.AssertExpectations(t)
+	pr.AssertExpectations(t)
+	ar.AssertExpectations(t)
+	sns.AssertNotCalled(t, "SendMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCAPService_IngestAlert_Update(t *testing.T) {
+	alert := testCAPAlert(cap.MsgTypeUpdate)
+	oldCampaign := uuid.New()
+
+	r := new(MockCAPAlertRepository)
+	sns := new(MockSendNotificationService)
+	kw := new(MockKafkaWriter)
+
+	r.On("GetCAPAlertsByReferences", mock.Anything, 1, testCAPRefs).
+		Return([]*models.CAPAlert{{ID: 1, CampaignID: &oldCampaign}}, nil).Once()
+	r.On("CreateCAPAlert", mock.Anything, mock.AnythingOfType("*models.CAPAlert")).Return(&models.CAPAlert{ID: 2}, nil).Once()
+	sns.On("SendMessage", mock.Anything, 1, mock.AnythingOfType("uuid.UUID"), mock.Anything, mock.Anything).Return(nil).Once()
+	kw.On("WriteMessages", mock.Anything, mock.MatchedBy(func(msgs []kafka.Message) bool {
+		var c domain.CampaignCancellation
+		return len(msgs) == 1 && json.Unmarshal(msgs[0].Value, &c) == nil && c == domain.CampaignCancellation{UserID: 1, CampaignID: oldCampaign}
+	})).Return(nil).Once()
+	r.On("CancelCAPAlerts", mock.Anything, 1, []int{1}).Return(nil).Once()
+
+	svc := service.NewCAPService(r, sns, noApprovals(), kw)
+	result, err := svc.IngestAlert(context.Background(), 1, alert)
+	require.NoError(t, err)
+
+	assert.Equal(t, domain.CAPActionUpdated, result.Action)
+	assert.NotNil(t, result.CampaignID)
+	assert.Equal(t, []uuid.UUID{oldCampaign}, result.CancelledCampaigns)
+	r.AssertExpectations(t)
+	sns.AssertExpectations(t)
+	kw.AssertExpectations(t)
+}
+
+func TestCAPService_IngestAlert_Cancel(t *testing.T) {
+	oldCampaign := uuid.New()
+	cancelledAt := time.Now()
+
+	tests := []struct {
+		name       string
+		referenced []*models.CAPAlert
+		wantCancel []uuid.UUID
+		wantErr    error
+	}{
+		{
+			name:       "cancels the campaign",
+			referenced: []*models.CAPAlert{{ID: 1, CampaignID: &oldCampaign}},
+			wantCancel: []uuid.UUID{oldCampaign},
+		},
+		{
+			name:       "already cancelled",
+			referenced: []*models.CAPAlert{{ID: 1, CampaignID: &oldCampaign, CancelTime: &cancelledAt}},
+			wantCancel: []uuid.UUID{},
+		},
+		{
+			name:    "unknown alert",
+			wantErr: domain.ErrCAPAlertNotExists,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			alert := testCAPAlert(cap.MsgTypeCancel)
+
+			r := new(MockCAPAlertRepository)
+			sns := new(MockSendNotificationService)
+			kw := new(MockKafkaWriter)
+
+			r.On("GetCAPAlertsByReferences", mock.Anything, 1, testCAPRefs).Return(tc.referenced, nil).Once()
+			if tc.wantErr == nil {
+				r.On("CreateCAPAlert", mock.Anything, mock.MatchedBy(func(a *models.CAPAlert) bool {
+					return a.CampaignID == nil && a.MsgType == cap.MsgTypeCancel
+				})).Return(&models.CAPAlert{ID: 2}, nil).Once()
+			}
+			if len(tc.wantCancel) > 0 {
+				kw.On("WriteMessages", mock.Anything, mock.Anything).Return(nil).Once()
+				r.On("CancelCAPAlerts", mock.Anything, 1, []int{1}).Return(nil).Once()
+			}
+
+			svc := service.NewCAPService(r, sns, noApprovals(), kw)
+			result, err := svc.IngestAlert(context.Background(), 1, alert)
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, domain.CAPActionCancelled, result.Action)
+				assert.Nil(t, result.CampaignID)
+				assert.Equal(t, tc.wantCancel, result.CancelledCampaigns)
+			}
+			r.AssertExpectations(t)
+			kw.AssertExpectations(t)
+			sns.AssertNotCalled(t, "SendMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCAPService_IngestAlert_Ignored(t *testing.T) {
+	for _, alert := range []*cap.Alert{
+		{Status: cap.StatusTest, MsgType: cap.MsgTypeAlert},
+		{Status: cap.StatusExercise, MsgType: cap.MsgTypeAlert},
+		{Status: cap.StatusActual, MsgType: cap.MsgTypeAck},
+	} {
+		r := new(MockCAPAlertRepository)
+		sns := new(MockSendNotificationService)
+		kw := new(MockKafkaWriter)
+		svc := service.NewCAPService(r, sns, noApprovals(), kw)
+
+		result, err := svc.IngestAlert(context.Background(), 1, alert)
+		require.NoError(t, err)
+		assert.Equal(t, domain.CAPActionIgnored, result.Action)
+		r.AssertNotCalled(t, "CreateCAPAlert", mock.Anything, mock.Anything)
+	}
+}
+
+func TestCAPService_IngestAlert_Invalid(t *testing.T) {
+	noInfo := testCAPAlert(cap.MsgTypeAlert)
+	noInfo.Infos = nil
+	expired := testCAPAlert(cap.MsgTypeAlert)
+	expired.Infos[0].Expires = "2020-01-01T00:00:00+00:00"
+	geocodes := testCAPAlert(cap.MsgTypeAlert)
+	geocodes.Infos[0].Areas = []cap.Area{{Desc: "County", Geocodes: []cap.Geocode{{ValueName: "FIPS6", Value: "006109"}}}}
+	noRefs := testCAPAlert(cap.MsgTypeUpdate)
+	noRefs.References = ""
+
+	for name, alert := range map[string]*cap.Alert{
+		"no info":            noInfo,
+		"expired":            expired,
+		"geocodes only":      geocodes,
+		"update without ref": noRefs,
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := new(MockCAPAlertRepository)
+			sns := new(MockSendNotificationService)
+			kw := new(MockKafkaWriter)
+			svc := service.NewCAPService(r, sns, noApprovals(), kw)
+
+			_, err := svc.IngestAlert(context.Background(), 1, alert)
+			assert.ErrorIs(t, err, domain.ErrInvalidCAPAlert)
+			r.AssertNotCalled(t, "CreateCAPAlert", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCAPService_IngestAlert_SendFailure(t *testing.T) {
+	t.Run("deletes the alert", func(t *testing.T) {
+		r := new(MockCAPAlertRepository)
+		sns := new(MockSendNotificationService)
+		kw := new(MockKafkaWriter)
+
+		sendErr := errors.New("kafka down")
+		r.On("CreateCAPAlert", mock.Anything, mock.Anything).Return(&models.CAPAlert{ID: 2}, nil).Once()
+		sns.On("SendMessage", mock.Anything, 1, mock.Anything, mock.Anything, mock.Anything).Return(sendErr).Once()
+		r.On("DeleteCAPAlert", mock.Anything, 1, 2).Return(nil).Once()
+
+		svc := service.NewCAPService(r, sns, noApprovals(), kw)
+		_, err := svc.IngestAlert(context.Background(), 1, testCAPAlert(cap.MsgTypeAlert))
+		assert.ErrorIs(t, err, sendErr)
+		r.AssertExpectations(t)
+	})
+
+	t.Run("no contacts in the area", func(t *testing.T) {
+		r := new(MockCAPAlertRepository)
+		sns := new(MockSendNotificationService)
+		kw := new(MockKafkaWriter)
+
+		r.On("CreateCAPAlert", mock.Anything, mock.Anything).Return(&models.CAPAlert{ID: 2}, nil).Once()
+		sns.On("SendMessage", mock.Anything, 1, mock.Anything, mock.Anything, mock.Anything).Return(domain.ErrContactNotExists).Once()
+
+		svc := service.NewCAPService(r, sns, noApprovals(), kw)
+		result, err := svc.IngestAlert(context.Background(), 1, testCAPAlert(cap.MsgTypeAlert))
+		require.NoError(t, err)
+		assert.Equal(t, domain.CAPActionLaunched, result.Action)
+		r.AssertNotCalled(t, "DeleteCAPAlert", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestCAPService_IngestAlert_Priority(t *testing.T) {
+	tests := []struct {
+		name      string
+		urgency   string
+		severity  string
+		certainty string
+		want      models.Priority
+	}{
+		{name: "extreme immediate", urgency: cap.UrgencyImmediate, severity: cap.SeverityExtreme, certainty: cap.CertaintyLikely, want: models.PriorityCritical},
+		{name: "severe expected", urgency: cap.UrgencyExpected, severity: cap.SeveritySevere, certainty: cap.CertaintyObserved, want: models.PriorityCritical},
+		{name: "severe but unlikely", urgency: cap.UrgencyImmediate, severity: cap.SeveritySevere, certainty: cap.CertaintyUnlikely, want: models.PriorityNormal},
+		{name: "severe in future", urgency: cap.UrgencyFuture, severity: cap.SeveritySevere, certainty: cap.CertaintyLikely, want: models.PriorityNormal},
+		{name: "moderate", urgency: cap.UrgencyImmediate, severity: cap.SeverityModerate, certainty: cap.CertaintyObserved, want: models.PriorityNormal},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			alert := testCAPAlert(cap.MsgTypeAlert)
+			alert.Infos[0].Urgency = tc.urgency
+			alert.Infos[0].Severity = tc.severity
+			alert.Infos[0].Certainty = tc.certainty
+
+			r := new(MockCAPAlertRepository)
+			sns := new(MockSendNotificationService)
+			kw := new(MockKafkaWriter)
+
+			r.On("CreateCAPAlert", mock.Anything, mock.Anything).Return(&models.CAPAlert{ID: 2}, nil).Once()
+			sns.On("SendMessage", mock.Anything, 1, mock.Anything, mock.Anything, mock.MatchedBy(func(opts *domain.SendNotificationRequest) bool {
+				return opts.Priority == tc.want
+			})).Return(nil).Once()
+
+			svc := service.NewCAPService(r, sns, noApprovals(), kw)
+			_, err := svc.IngestAlert(context.Background(), 1, alert)
+			require.NoError(t, err)
+			sns.AssertExpectations(t)
+		})
+	}
+}
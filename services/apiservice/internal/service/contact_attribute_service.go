@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+)
+
+// maxContactAttributeOptions caps the number of values of an enum attribute.
+const maxContactAttributeOptions = 100
+
+// ContactAttributeService validates and manages the typed attributes users define for their contacts.
+type ContactAttributeService struct {
+	repository domain.ContactAttributeRepository
+}
+
+// NewContactAttributeService creates a ContactAttributeService with the given repository.
+func NewContactAttributeService(r domain.ContactAttributeRepository) *ContactAttributeService {
+	return &ContactAttributeService{
+		repository: r,
+	}
+}
+
+// GetContactAttributes retrieves every contact attribute defined by the user.
+func (as *ContactAttributeService) GetContactAttributes(ctx context.Context, userID int) ([]*models.ContactAttribute, error) {
+	return as.repository.GetContactAttributes(ctx, userID)
+}
+
+// CreateContactAttribute validates and creates a contact attribute definition.
+// Returns an error wrapping domain.ErrInvalidContactAttribute for invalid definitions.
+func (as *ContactAttributeService) CreateContactAttribute(ctx context.Context, attr *models.ContactAttribute) (*models.ContactAttribute, error) {
+	err := validateContactAttribute(attr)
+	if err != nil {
+		return nil, err
+	}
+
+	return as.repository.CreateContactAttribute(ctx, attr)
+}
+
+// UpdateContactAttribute validates and replaces a contact attribute definition. Values contacts
+// already hold are checked against the new definition only when those contacts are next changed.
+func (as *ContactAttributeService) UpdateContactAttribute(ctx context.Context, userID, attrID int, attr *models.ContactAttribute) (*models.ContactAttribute, error) {
+	err := validateContactAttribute(attr)
+	if err != nil {
+		return nil, err
+	}
+
+	return as.repository.UpdateContactAttribute(ctx, userID, attrID, attr)
+}
+
+// DeleteContactAttribute removes a contact attribute definition of the user.
+func (as *ContactAttributeService) DeleteContactAttribute(ctx context.Context, userID, attrID int) error {
+	return as.repository.DeleteContactAttribute(ctx, userID, attrID)
+}
+
+// validateContactAttribute checks the name, type and options of the definition and trims its
+// name and options. Only enum attributes have options.
+func validateContactAttribute(attr *models.ContactAttribute) error {
+	attr.Name = strings.TrimSpace(attr.Name)
+	if len(attr.Name) == 0 || len(attr.Name) > maxContactAttributeNameLen {
+		return domain.ErrInvalidContactAttributeName
+	}
+
+	switch attr.Type {
+	case models.AttributeText, models.AttributeNumber, models.AttributeBoolean, models.AttributeDate:
+		if len(attr.Options) > 0 {
+			return domain.ErrInvalidContactAttributeOptions
+		}
+		attr.Options = nil
+		return nil
+	case models.AttributeEnum:
+	default:
+		return domain.ErrInvalidContactAttributeType
+	}
+
+	if len(attr.Options) == 0 || len(attr.Options) > maxContactAttributeOptions {
+		return domain.ErrInvalidContactAttributeOptions
+	}
+	seen := make(map[string]bool, len(attr.Options))
+	for i, option := range attr.Options {
+		option = strings.TrimSpace(option)
+		key := strings.ToLower(option)
+		if len(option) == 0 || len(option) > maxContactAttributeLen || seen[key] {
+			return domain.ErrInvalidContactAttributeOptions
+		}
+		seen[key] = true
+		attr.Options[i] = option
+	}
+
+	return nil
+}
+
+// applyContactAttributes checks the contact's attribute values against the user's definitions,
+// matching names case-insensitively, and returns them with the defined names and canonical
+// values. Attributes without a definition are kept as they are.
+func applyContactAttributes(values map[string]string, defs []*models.ContactAttribute) (map[string]string, error) {
+	if len(defs) == 0 {
+		return values, nil
+	}
+
+	byName := make(map[string]*models.ContactAttribute, len(defs))
+	for _, d := range defs {
+		byName[strings.ToLower(d.Name)] = d
+	}
+
+	var result map[string]string
+	if len(values) > 0 {
+		result = make(map[string]string, len(values))
+	}
+	for name, value := range values {
+		d, ok := byName[strings.ToLower(name)]
+		if !ok {
+			result[name] = value
+			continue
+		}
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		normalized, ok := d.Normalize(value)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", domain.ErrInvalidContactAttributeValue, d.Name)
+		}
+		result[d.Name] = normalized
+	}
+
+	for _, d := range defs {
+		if _, ok := result[d.Name]; d.Required && !ok {
+			return nil, fmt.Errorf("%w: %s", domain.ErrMissingContactAttribute, d.Name)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,158 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestContactAttributeService_CreateContactAttribute(t *testing.T) {
+	tests := map[string]struct {
+		attr    *models.ContactAttribute
+		want    *models.ContactAttribute
+		wantErr error
+	}{
+		"text": {
+			attr: &models.ContactAttribute{UserID: 1, Name: " Building ", Type: models.AttributeText},
+			want: &models.ContactAttribute{UserID: 1, Name: "Building", Type: models.AttributeText},
+		},
+		"enum": {
+			attr: &models.ContactAttribute{UserID: 1, Name: "Shift", Type: models.AttributeEnum, Options: []string{"Day ", "Night"}, Required: true},
+			want: &models.ContactAttribute{UserID: 1, Name: "Shift", Type: models.AttributeEnum, Options: []string{"Day", "Night"}, Required: true},
+		},
+		"empty name": {
+			attr:    &models.ContactAttribute{UserID: 1, Name: " ", Type: models.AttributeText},
+			wantErr: domain.ErrInvalidContactAttributeName,
+		},
+		"name too long": {
+			attr:    &models.ContactAttribute{UserID: 1, Name: strings.Repeat("x", 65), Type: models.AttributeText},
+			wantErr: domain.ErrInvalidContactAttributeName,
+		},
+		"unknown type": {
+			attr:    &models.ContactAttribute{UserID: 1, Name: "Shift", Type: "time"},
+			wantErr: domain.ErrInvalidContactAttributeType,
+		},
+		"enum without options": {
+			attr:    &models.ContactAttribute{UserID: 1, Name: "Shift", Type: models.AttributeEnum},
+			wantErr: domain.ErrInvalidContactAttributeOptions,
+		},
+		"duplicate options": {
+			attr:    &models.ContactAttribute{UserID: 1, Name: "Shift", Type: models.AttributeEnum, Options: []string{"Day", "day"}},
+			wantErr: domain.ErrInvalidContactAttributeOptions,
+		},
+		"options of number": {
+			attr:    &models.ContactAttribute{UserID: 1, Name: "Floor", Type: models.AttributeNumber, Options: []string{"1"}},
+			wantErr: domain.ErrInvalidContactAttributeOptions,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			repo := new(MockContactAttributeRepository)
+			if tc.want != nil {
+				repo.
+					On("CreateContactAttribute", mock.Anything, tc.want).
+					Return(tc.want, nil).
+					Once()
+			}
+			svc := service.NewContactAttributeService(repo)
+
+			got, err := svc.CreateContactAttribute(context.Background(), tc.attr)
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.ErrorIs(t, err, domain.ErrInvalidContactAttribute)
+				assert.Nil(t, got)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.want, got)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestContactAttributeService_UpdateContactAttribute(t *testing.T) {
+	repo := new(MockContactAttributeRepository)
+	attr := &models.ContactAttribute{UserID: 1, Name: "Floor", Type: models.AttributeNumber}
+	repo.
+		On("UpdateContactAttribute", mock.Anything, 1, 7, attr).
+		Return((*models.ContactAttribute)(nil), domain.ErrContactAttributeNotExists).
+		Once()
+	svc := service.NewContactAttributeService(repo)
+
+	_, err := svc.UpdateContactAttribute(context.Background(), 1, 7, attr)
+	assert.ErrorIs(t, err, domain.ErrContactAttributeNotExists)
+	repo.AssertExpectations(t)
+}
+
+func TestContactsService_CreateContact_TypedAttributes(t *testing.T) {
+	defs := []*models.ContactAttribute{
+		{Name: "Shift", Type: models.AttributeEnum, Options: []string{"Day", "Night"}, Required: true},
+		{Name: "Floor", Type: models.AttributeNumber},
+		{Name: "On call", Type: models.AttributeBoolean},
+		{Name: "Hired", Type: models.AttributeDate},
+	}
+
+	tests := map[string]struct {
+		attributes map[string]string
+		want       map[string]string
+		wantErr    error
+	}{
+		"normalized": {
+			attributes: map[string]string{"shift": "night", "Floor": "3,50", "on call": "да", "Hired": "31.01.2024", "Note": "free text"},
+			want:       map[string]string{"Shift": "Night", "Floor": "3.5", "On call": "true", "Hired": "2024-01-31", "Note": "free text"},
+		},
+		"invalid enum": {
+			attributes: map[string]string{"Shift": "Evening"},
+			wantErr:    domain.ErrInvalidContactAttributeValue,
+		},
+		"invalid number": {
+			attributes: map[string]string{"Shift": "Day", "Floor": "third"},
+			wantErr:    domain.ErrInvalidContactAttributeValue,
+		},
+		"invalid date": {
+			attributes: map[string]string{"Shift": "Day", "Hired": "2024-02-30"},
+			wantErr:    domain.ErrInvalidContactAttributeValue,
+		},
+		"missing required": {
+			attributes: map[string]string{"Floor": "3"},
+			wantErr:    domain.ErrMissingContactAttribute,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := new(MockContactsRepository)
+			if tc.want != nil {
+				m.
+					On("CreateContact", mock.Anything, mock.MatchedBy(func(c *models.Contact) bool {
+						return assert.ObjectsAreEqual(tc.want, c.Attributes)
+					})).
+					Return(&models.Contact{ID: 1}, nil).
+					Once()
+			}
+			noTakenAddresses(m)
+			ar := new(MockContactAttributeRepository)
+			ar.On("GetContactAttributes", mock.Anything, 123).Return(defs, nil).Once()
+			svc := service.NewContactsService(m, newRegionUsers(), ar, 50, 100, 1000)
+
+			_, err := svc.CreateContact(context.Background(), &models.Contact{UserID: 123, Name: "Alice", Phone: "+79123456789", Attributes: tc.attributes}, "")
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.ErrorIs(t, err, domain.ErrInvalidContact)
+			} else {
+				assert.NoError(t, err)
+			}
+			m.AssertExpectations(t)
+			ar.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	// exportSheet is the name of the worksheet contacts are written to in XLSX exports.
+	exportSheet = "Contacts"
+	// exportFailTimeout bounds marking an export as failed, which happens after the
+	// export's own timeout may already have run out.
+	exportFailTimeout = 5 * time.Second
+)
+
+// ContactExportService writes a user's contacts as CSV or XLSX files with the columns
+// Name, Phone and one column per attribute, so that an export can be imported back.
+type ContactExportService struct {
+	exports    domain.ContactExportRepository
+	contacts   domain.ContactsRepository
+	s3Client   domain.S3Client
+	presigner  domain.S3Presigner
+	bucket     string
+	timeout    time.Duration
+	linkExpiry time.Duration
+	logger     *zap.Logger
+	wg         sync.WaitGroup
+}
+
+// NewContactExportService constructs a ContactExportService. timeout bounds how long an
+// asynchronous export may run, and linkExpiry is the lifetime of its download links.
+// logger reports errors of asynchronous exports that cannot be recorded on the job.
+func NewContactExportService(
+	er domain.ContactExportRepository,
+	cr domain.ContactsRepository,
+	s3Client domain.S3Client,
+	presigner domain.S3Presigner,
+	bucket string,
+	timeout, linkExpiry time.Duration,
+	logger *zap.Logger,
+) *ContactExportService {
+	return &ContactExportService{
+		exports:    er,
+		contacts:   cr,
+		s3Client:   s3Client,
+		presigner:  presigner,
+		bucket:     bucket,
+		timeout:    timeout,
+		linkExpiry: linkExpiry,
+		logger:     logger,
+	}
+}
+
+// ExportContacts writes the user's contacts matching the filter to w in the given format.
+// Contacts are streamed from the database as they are written.
+// Returns domain.ErrInvalidExportFormat for formats other than CSV and XLSX.
+func (ces *ContactExportService) ExportContacts(ctx context.Context, userID int, format models.ExportFormat, filter domain.ContactFilter, w io.Writer) error {
+	if !isValidExportFormat(format) {
+		return domain.ErrInvalidExportFormat
+	}
+
+	_, err := ces.writeContacts(ctx, userID, format, filter, w)
+	return err
+}
+
+// StartContactExport creates a queued export job and writes the file to S3 in the background.
+// The job is marked done with the number of contacts written, or failed with the reason.
+// Returns domain.ErrInvalidExportFormat for formats other than CSV and XLSX.
+func (ces *ContactExportService) StartContactExport(ctx context.Context, userID int, format models.ExportFormat, filter domain.ContactFilter) (*models.ContactExport, error) {
+	if !isValidExportFormat(format) {
+		return nil, domain.ErrInvalidExportFormat
+	}
+
+	ce, err := ces.exports.CreateContactExport(ctx, &models.ContactExport{
+		UserID: userID,
+		Format: format,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ces.wg.Add(1)
+	go func() {
+		defer ces.wg.Done()
+
+		runCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), ces.timeout)
+		defer cancel()
+
+		err := ces.runExport(runCtx, ce, filter)
+		if err != nil {
+			ces.failExport(runCtx, ce.ID, err)
+		}
+	}()
+
+	return ce, nil
+}
+
+// Wait blocks until every export started by StartContactExport has finished.
+// It is called on shutdown, after the server has stopped accepting requests.
+func (ces *ContactExportService) Wait() {
+	ces.wg.Wait()
+}
+
+// failExport marks the export as failed with the reason. The export's context is
+// usually done by now, e.g. when the export timed out, so the job is updated with a
+// fresh timeout instead.
+func (ces *ContactExportService) failExport(ctx context.Context, exportID int, reason error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), exportFailTimeout)
+	defer cancel()
+
+	err := ces.exports.FailContactExport(ctx, exportID, reason.Error())
+	if err != nil {
+		ces.logger.Error("failed to mark contact export as failed",
+			zap.Int("exportID", exportID),
+			zap.NamedError("reason", reason),
+			zap.Error(err),
+		)
+	}
+}
+
+// GetContactExport returns the export job of the user. Once the job is done, DownloadURL
+// is a presigned link to the exported file.
+func (ces *ContactExportService) GetContactExport(ctx context.Context, userID, exportID int) (*models.ContactExport, error) {
+	ce, err := ces.exports.GetContactExportByID(ctx, userID, exportID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ce.Status == models.ExportStatusDone && ce.S3Key != "" {
+		req, _ := ces.presigner.GetObjectRequest(&s3.GetObjectInput{
+			Bucket:                     aws.String(ces.bucket),
+			Key:                        aws.String(ce.S3Key),
+			ResponseContentDisposition: aws.String(fmt.Sprintf(`attachment; filename="contacts.%s"`, ce.Format)),
+		})
+		ce.DownloadURL, err = req.Presign(ces.linkExpiry)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ce, nil
+}
+
+// runExport writes the export to a temporary file, uploads it to S3 and completes the job.
+func (ces *ContactExportService) runExport(ctx context.Context, ce *models.ContactExport, filter domain.ContactFilter) (err error) {
+	err = ces.exports.StartContactExport(ctx, ce.ID)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp("", "contacts-export-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, tmp.Close(), os.Remove(tmp.Name()))
+	}()
+
+	rows, err := ces.writeContacts(ctx, ce.UserID, ce.Format, filter, tmp)
+	if err != nil {
+		return err
+	}
+
+	_, err = tmp.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("exports/%d/contacts.%s", ce.ID, ce.Format)
+	_, err = ces.s3Client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(ces.bucket),
+		Key:         aws.String(key),
+		Body:        tmp,
+		ContentType: aws.String(ce.Format.ContentType()),
+	})
+	if err != nil {
+		return err
+	}
+
+	return ces.exports.CompleteContactExport(ctx, ce.ID, rows, key)
+}
+
+// writeContacts writes the header and the matching contacts to w and returns the number
+// of contacts written.
+func (ces *ContactExportService) writeContacts(ctx context.Context, userID int, format models.ExportFormat, filter domain.ContactFilter, w io.Writer) (int, error) {
+	attributes, err := ces.contacts.GetContactAttributeNames(ctx, userID, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	header := append([]string{"Name", "Phone"}, attributes...)
+	record := func(c *models.Contact) []string {
+		rec := make([]string, 0, len(header))
+		rec = append(rec, c.Name, c.Phone)
+		for _, name := range attributes {
+			rec = append(rec, c.Attributes[name])
+		}
+		return rec
+	}
+
+	if format == models.ExportFormatXLSX {
+		return ces.writeXLSX(ctx, userID, filter, header, record, w)
+	}
+	return ces.writeCSV(ctx, userID, filter, escapeCSVRecord(header), func(c *models.Contact) []string {
+		return escapeCSVRecord(record(c))
+	}, w)
+}
+
+// escapeCSVRecord prefixes cells that spreadsheet applications would evaluate as
+// formulas with a single quote, so opening an export cannot run what a contact holds.
+// The phone column is left as is: it is always E.164, which starts with "+" but holds
+// nothing but digits, and the prefix would keep the export from being imported back.
+// XLSX cells are written as strings and need no escaping.
+func escapeCSVRecord(rec []string) []string {
+	for i, cell := range rec {
+		if i == 1 {
+			continue
+		}
+		rec[i] = escapeCSVCell(cell)
+	}
+	return rec
+}
+
+// escapeCSVCell prefixes the cell with a single quote if it starts with a character
+// that makes spreadsheet applications treat it as a formula.
+func escapeCSVCell(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+func (ces *ContactExportService) writeCSV(
+	ctx context.Context,
+	userID int,
+	filter domain.ContactFilter,
+	header []string,
+	record func(*models.Contact) []string,
+	w io.Writer,
+) (int, error) {
+	cw := csv.NewWriter(w)
+	err := cw.Write(header)
+	if err != nil {
+		return 0, err
+	}
+
+	rows := 0
+	err = ces.contacts.StreamContacts(ctx, userID, filter, func(c *models.Contact) error {
+		rows++
+		return cw.Write(record(c))
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	cw.Flush()
+	return rows, cw.Error()
+}
+
+func (ces *ContactExportService) writeXLSX(
+	ctx context.Context,
+	userID int,
+	filter domain.ContactFilter,
+	header []string,
+	record func(*models.Contact) []string,
+	w io.Writer,
+) (rows int, err error) {
+	f := excelize.NewFile()
+	defer func() {
+		err = errors.Join(err, f.Close())
+	}()
+
+	err = f.SetSheetName("Sheet1", exportSheet)
+	if err != nil {
+		return 0, err
+	}
+
+	sw, err := f.NewStreamWriter(exportSheet)
+	if err != nil {
+		return 0, err
+	}
+
+	writeRow := func(line int, values []string) error {
+		cell, err := excelize.CoordinatesToCellName(1, line)
+		if err != nil {
+			return err
+		}
+		row := make([]any, len(values))
+		for i, v := range values {
+			row[i] = v
+		}
+		return sw.SetRow(cell, row)
+	}
+
+	err = writeRow(1, header)
+	if err != nil {
+		return 0, err
+	}
+
+	err = ces.contacts.StreamContacts(ctx, userID, filter, func(c *models.Contact) error {
+		rows++
+		return writeRow(rows+1, record(c))
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	err = sw.Flush()
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = f.WriteTo(w)
+	if err != nil {
+		return 0, err
+	}
+
+	return rows, nil
+}
+
+func isValidExportFormat(format models.ExportFormat) bool {
+	return format == models.ExportFormatCSV || format == models.ExportFormatXLSX
+}
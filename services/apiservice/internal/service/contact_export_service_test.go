@@ -0,0 +1,294 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap"
+)
+
+var testExportContacts = []*models.Contact{
+	{ID: 1, UserID: 1, Name: "Alice", Phone: "+79123456789", Attributes: map[string]string{"City": "Moscow", "Group": "Sales"}},
+	{ID: 2, UserID: 1, Name: "Bob", Phone: "+79123456788", Attributes: map[string]string{"Group": "Sales"}},
+}
+
+var testExportRecords = [][]string{
+	{"Name", "Phone", "City", "Group"},
+	{"Alice", "+79123456789", "Moscow", "Sales"},
+	{"Bob", "+79123456788", "", "Sales"},
+}
+
+// newTestPresigner returns an S3 client that presigns links offline.
+func newTestPresigner(t *testing.T) *s3.S3 {
+	t.Helper()
+	sess, err := session.NewSession(&aws.Config{
+		Credentials:      credentials.NewStaticCredentials("id", "secret", ""),
+		Region:           aws.String("us-east-1"),
+		Endpoint:         aws.String("http://minio:9000"),
+		S3ForcePathStyle: aws.Bool(true),
+	})
+	require.NoError(t, err)
+	return s3.New(sess)
+}
+
+func readXLSXRows(t *testing.T, data []byte) [][]string {
+	t.Helper()
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	defer func() {
+		_ = f.Close()
+	}()
+
+	rows, err := f.GetRows("Contacts")
+	require.NoError(t, err)
+	return rows
+}
+
+func TestContactExportService_ExportContacts(t *testing.T) {
+	filter := domain.ContactFilter{Group: "Sales"}
+
+	tests := map[string]struct {
+		format    models.ExportFormat
+		streamErr error
+		expectErr error
+	}{
+		"csv": {
+			format: models.ExportFormatCSV,
+		},
+		"xlsx": {
+			format: models.ExportFormatXLSX,
+		},
+		"invalid format": {
+			format:    "pdf",
+			expectErr: domain.ErrInvalidExportFormat,
+		},
+		"stream error": {
+			format:    models.ExportFormatCSV,
+			streamErr: assert.AnError,
+			expectErr: assert.AnError,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			contacts := new(MockContactsRepository)
+			if tc.expectErr != domain.ErrInvalidExportFormat {
+				contacts.
+					On("GetContactAttributeNames", mock.Anything, 1, filter).
+					Return([]string{"City", "Group"}, nil).
+					Once()
+				contacts.
+					On("StreamContacts", mock.Anything, 1, filter).
+					Return(testExportContacts, tc.streamErr).
+					Once()
+			}
+
+			svc := service.NewContactExportService(new(MockContactExportRepository), contacts, new(MockS3Client), newTestPresigner(t), "bucket", time.Minute, time.Hour, zap.NewNop())
+
+			var buf bytes.Buffer
+			err := svc.ExportContacts(context.Background(), 1, tc.format, filter, &buf)
+
+			if tc.expectErr != nil {
+				assert.ErrorIs(t, err, tc.expectErr)
+			} else {
+				require.NoError(t, err)
+				var records [][]string
+				if tc.format == models.ExportFormatXLSX {
+					records = readXLSXRows(t, buf.Bytes())
+				} else {
+					records, err = csv.NewReader(&buf).ReadAll()
+					require.NoError(t, err)
+				}
+				assert.Equal(t, testExportRecords, records)
+			}
+			contacts.AssertExpectations(t)
+		})
+	}
+}
+
+func TestContactExportService_ExportContacts_EscapesFormulas(t *testing.T) {
+	filter := domain.ContactFilter{}
+	contacts := new(MockContactsRepository)
+	contacts.
+		On("GetContactAttributeNames", mock.Anything, 1, filter).
+		Return([]string{"=Note", "Group"}, nil).
+		Once()
+	contacts.
+		On("StreamContacts", mock.Anything, 1, filter).
+		Return([]*models.Contact{
+			{ID: 1, UserID: 1, Name: "=HYPERLINK(\"http://evil\")", Phone: "+79123456789", Attributes: map[string]string{"=Note": "+1+2", "Group": "@SUM(A1)"}},
+			{ID: 2, UserID: 1, Name: "-Bob", Phone: "+79123456788", Attributes: map[string]string{"=Note": "\tcmd", "Group": "\rcmd"}},
+			{ID: 3, UserID: 1, Name: "Carol", Phone: "+79123456787", Attributes: map[string]string{"=Note": "a=b", "Group": "'quoted"}},
+		}, nil).
+		Once()
+
+	svc := service.NewContactExportService(new(MockContactExportRepository), contacts, new(MockS3Client), newTestPresigner(t), "bucket", time.Minute, time.Hour, zap.NewNop())
+
+	var buf bytes.Buffer
+	err := svc.ExportContacts(context.Background(), 1, models.ExportFormatCSV, filter, &buf)
+	require.NoError(t, err)
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Name", "Phone", "'=Note", "Group"},
+		{"'=HYPERLINK(\"http://evil\")", "+79123456789", "'+1+2", "'@SUM(A1)"},
+		{"'-Bob", "+79123456788", "'\tcmd", "'\rcmd"},
+		{"Carol", "+79123456787", "a=b", "'quoted"},
+	}, records)
+	contacts.AssertExpectations(t)
+}
+
+func TestContactExportService_StartContactExport(t *testing.T) {
+	filter := domain.ContactFilter{Search: "a"}
+
+	tests := map[string]struct {
+		timeout    time.Duration
+		putWaits   bool
+		putErr     error
+		wantFailed bool
+	}{
+		"done": {
+			timeout: time.Minute,
+		},
+		"upload fails": {
+			timeout:    time.Minute,
+			putErr:     assert.AnError,
+			wantFailed: true,
+		},
+		"times out": {
+			timeout:    10 * time.Millisecond,
+			putWaits:   true,
+			putErr:     context.DeadlineExceeded,
+			wantFailed: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			exports := new(MockContactExportRepository)
+			contacts := new(MockContactsRepository)
+			s3Client := new(MockS3Client)
+
+			exports.
+				On("CreateContactExport", mock.Anything, &models.ContactExport{UserID: 1, Format: models.ExportFormatCSV}).
+				Return(&models.ContactExport{ID: 4, UserID: 1, Format: models.ExportFormatCSV, Status: models.ExportStatusQueued}, nil).
+				Once()
+			exports.On("StartContactExport", mock.Anything, 4).Return(nil).Once()
+			contacts.On("GetContactAttributeNames", mock.Anything, 1, filter).Return([]string{"City", "Group"}, nil).Once()
+			contacts.On("StreamContacts", mock.Anything, 1, filter).Return(testExportContacts, nil).Once()
+
+			var uploaded [][]string
+			s3Client.
+				On("PutObjectWithContext", mock.Anything, mock.MatchedBy(func(in *s3.PutObjectInput) bool {
+					return aws.StringValue(in.Bucket) == "bucket" &&
+						aws.StringValue(in.Key) == "exports/4/contacts.csv" &&
+						aws.StringValue(in.ContentType) == "text/csv"
+				})).
+				Run(func(args mock.Arguments) {
+					body, err := io.ReadAll(args.Get(1).(*s3.PutObjectInput).Body)
+					require.NoError(t, err)
+					uploaded, err = csv.NewReader(bytes.NewReader(body)).ReadAll()
+					require.NoError(t, err)
+					if tc.putWaits {
+						<-args.Get(0).(context.Context).Done()
+					}
+				}).
+				Return(&s3.PutObjectOutput{}, tc.putErr).
+				Once()
+
+			if tc.wantFailed {
+				// the job is failed even when the export's own context has run out
+				liveCtx := mock.MatchedBy(func(ctx context.Context) bool { return ctx.Err() == nil })
+				exports.On("FailContactExport", liveCtx, 4, tc.putErr.Error()).Return(nil).Once()
+			} else {
+				exports.On("CompleteContactExport", mock.Anything, 4, 2, "exports/4/contacts.csv").Return(nil).Once()
+			}
+
+			svc := service.NewContactExportService(exports, contacts, s3Client, newTestPresigner(t), "bucket", tc.timeout, time.Hour, zap.NewNop())
+			out, err := svc.StartContactExport(context.Background(), 1, models.ExportFormatCSV, filter)
+			require.NoError(t, err)
+			assert.Equal(t, 4, out.ID)
+			assert.Equal(t, models.ExportStatusQueued, out.Status)
+
+			svc.Wait()
+			assert.Equal(t, testExportRecords, uploaded)
+			exports.AssertExpectations(t)
+			contacts.AssertExpectations(t)
+			s3Client.AssertExpectations(t)
+		})
+	}
+}
+
+func TestContactExportService_StartContactExport_InvalidFormat(t *testing.T) {
+	exports := new(MockContactExportRepository)
+	svc := service.NewContactExportService(exports, new(MockContactsRepository), new(MockS3Client), newTestPresigner(t), "bucket", time.Minute, time.Hour, zap.NewNop())
+
+	out, err := svc.StartContactExport(context.Background(), 1, "pdf", domain.ContactFilter{})
+	assert.ErrorIs(t, err, domain.ErrInvalidExportFormat)
+	assert.Nil(t, out)
+	exports.AssertNotCalled(t, "CreateContactExport", mock.Anything, mock.Anything)
+}
+
+func TestContactExportService_GetContactExport(t *testing.T) {
+	tests := map[string]struct {
+		stored       *models.ContactExport
+		repoErr      error
+		wantDownload bool
+	}{
+		"done": {
+			stored:       &models.ContactExport{ID: 4, UserID: 1, Format: models.ExportFormatXLSX, Status: models.ExportStatusDone, S3Key: "exports/4/contacts.xlsx"},
+			wantDownload: true,
+		},
+		"processing": {
+			stored: &models.ContactExport{ID: 4, UserID: 1, Format: models.ExportFormatXLSX, Status: models.ExportStatusProcessing},
+		},
+		"not exists": {
+			repoErr: domain.ErrContactExportNotExists,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			exports := new(MockContactExportRepository)
+			exports.
+				On("GetContactExportByID", mock.Anything, 1, 4).
+				Return(tc.stored, tc.repoErr).
+				Once()
+
+			svc := service.NewContactExportService(exports, new(MockContactsRepository), new(MockS3Client), newTestPresigner(t), "bucket", time.Minute, time.Hour, zap.NewNop())
+			out, err := svc.GetContactExport(context.Background(), 1, 4)
+
+			if tc.repoErr != nil {
+				assert.ErrorIs(t, err, tc.repoErr)
+				assert.Nil(t, out)
+			} else if tc.wantDownload {
+				require.NoError(t, err)
+				link, err := url.Parse(out.DownloadURL)
+				require.NoError(t, err)
+				assert.Equal(t, "/bucket/exports/4/contacts.xlsx", link.Path)
+				assert.Equal(t, "3600", link.Query().Get("X-Amz-Expires"))
+			} else {
+				require.NoError(t, err)
+				assert.Empty(t, out.DownloadURL)
+			}
+			exports.AssertExpectations(t)
+		})
+	}
+}
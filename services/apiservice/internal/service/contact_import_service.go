@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// ContactImportService reports the progress of contact imports and serves
+// the CSV reports of rows rejected and contacts deleted by the contacts worker.
+type ContactImportService struct {
+	repository domain.ContactImportRepository
+	s3Client   domain.S3Client
+	bucket     string
+}
+
+// NewContactImportService constructs a ContactImportService.
+func NewContactImportService(r domain.ContactImportRepository, s3Client domain.S3Client, bucket string) *ContactImportService {
+	return &ContactImportService{
+		repository: r,
+		s3Client:   s3Client,
+		bucket:     bucket,
+	}
+}
+
+// GetContactImport returns the import job of the user. If the job produced a
+// rejection report, ReportURL points to the endpoint serving it, and likewise
+// DeletionsURL for the report of deleted contacts.
+func (cis *ContactImportService) GetContactImport(ctx context.Context, userID, importID int) (*models.ContactImport, error) {
+	ci, err := cis.repository.GetContactImportByID(ctx, userID, importID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ci.ReportKey != "" {
+		ci.ReportURL = fmt.Sprintf("/imports/%d/report", ci.ID)
+	}
+	if ci.DeletionsKey != "" {
+		ci.DeletionsURL = fmt.Sprintf("/imports/%d/deletions", ci.ID)
+	}
+
+	return ci, nil
+}
+
+// GetRejectionReport opens the CSV report of rejected rows of the import job.
+// Returns domain.ErrContactImportReportNotExists if the job has not rejected any rows.
+// The caller must close the returned reader.
+func (cis *ContactImportService) GetRejectionReport(ctx context.Context, userID, importID int) (io.ReadCloser, error) {
+	ci, err := cis.repository.GetContactImportByID(ctx, userID, importID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ci.ReportKey == "" {
+		return nil, domain.ErrContactImportReportNotExists
+	}
+
+	return cis.openReport(ctx, ci.ReportKey)
+}
+
+// GetDeletionsReport opens the CSV report of the contacts a sync import deleted, or would
+// delete in a dry run. Returns domain.ErrContactImportDeletionsNotExists if there are none.
+// The caller must close the returned reader.
+func (cis *ContactImportService) GetDeletionsReport(ctx context.Context, userID, importID int) (io.ReadCloser, error) {
+	ci, err := cis.repository.GetContactImportByID(ctx, userID, importID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ci.DeletionsKey == "" {
+		return nil, domain.ErrContactImportDeletionsNotExists
+	}
+
+	return cis.openReport(ctx, ci.DeletionsKey)
+}
+
+func (cis *ContactImportService) openReport(ctx context.Context, key string) (io.ReadCloser, error) {
+	object, err := cis.s3Client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(cis.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return object.Body, nil
+}
@@ -0,0 +1,189 @@
+package service_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestContactImportService_GetContactImport(t *testing.T) {
+	tests := map[string]struct {
+		stored           *models.ContactImport
+		repoErr          error
+		wantReportURL    string
+		wantDeletionsURL string
+	}{
+		"without rejected rows": {
+			stored: &models.ContactImport{ID: 3, UserID: 1, Status: models.ImportStatusDone, RowsRead: 2, RowsInserted: 2},
+		},
+		"with rejection report": {
+			stored:        &models.ContactImport{ID: 3, UserID: 1, Status: models.ImportStatusDone, RowsRead: 2, RowsInvalid: 1, ReportKey: "imports/3/rejected.csv"},
+			wantReportURL: "/imports/3/report",
+		},
+		"with deletions report": {
+			stored:           &models.ContactImport{ID: 3, UserID: 1, Mode: models.ImportModeSync, Status: models.ImportStatusDone, RowsDeleted: 1, DeletionsKey: "imports/3/deleted.csv"},
+			wantDeletionsURL: "/imports/3/deletions",
+		},
+		"not exists": {
+			stored:  nil,
+			repoErr: domain.ErrContactImportNotExists,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			repo := new(MockContactImportRepository)
+			repo.
+				On("GetContactImportByID", mock.Anything, 1, 3).
+				Return(tc.stored, tc.repoErr).
+				Once()
+
+			svc := service.NewContactImportService(repo, new(MockS3Client), "bucket")
+			out, err := svc.GetContactImport(context.Background(), 1, 3)
+
+			if tc.repoErr != nil {
+				assert.ErrorIs(t, err, tc.repoErr)
+				assert.Nil(t, out)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.wantReportURL, out.ReportURL)
+				assert.Equal(t, tc.wantDeletionsURL, out.DeletionsURL)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestContactImportService_GetRejectionReport(t *testing.T) {
+	tests := map[string]struct {
+		stored    *models.ContactImport
+		repoErr   error
+		setupS3   func(m *MockS3Client)
+		wantBody  string
+		expectErr error
+	}{
+		"success": {
+			stored: &models.ContactImport{ID: 3, ReportKey: "imports/3/rejected.csv"},
+			setupS3: func(m *MockS3Client) {
+				m.
+					On("GetObjectWithContext", mock.Anything, mock.MatchedBy(func(in *s3.GetObjectInput) bool {
+						return aws.StringValue(in.Bucket) == "bucket" && aws.StringValue(in.Key) == "imports/3/rejected.csv"
+					})).
+					Return(&s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader("line,name,phone,reason\n"))}, nil).
+					Once()
+			},
+			wantBody: "line,name,phone,reason\n",
+		},
+		"no rejected rows": {
+			stored:    &models.ContactImport{ID: 3},
+			setupS3:   func(m *MockS3Client) {},
+			expectErr: domain.ErrContactImportReportNotExists,
+		},
+		"import not exists": {
+			stored:    nil,
+			repoErr:   domain.ErrContactImportNotExists,
+			setupS3:   func(m *MockS3Client) {},
+			expectErr: domain.ErrContactImportNotExists,
+		},
+		"s3 error": {
+			stored: &models.ContactImport{ID: 3, ReportKey: "imports/3/rejected.csv"},
+			setupS3: func(m *MockS3Client) {
+				m.
+					On("GetObjectWithContext", mock.Anything, mock.Anything).
+					Return((*s3.GetObjectOutput)(nil), assert.AnError).
+					Once()
+			},
+			expectErr: assert.AnError,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			repo := new(MockContactImportRepository)
+			repo.
+				On("GetContactImportByID", mock.Anything, 1, 3).
+				Return(tc.stored, tc.repoErr).
+				Once()
+			s3c := new(MockS3Client)
+			tc.setupS3(s3c)
+
+			svc := service.NewContactImportService(repo, s3c, "bucket")
+			report, err := svc.GetRejectionReport(context.Background(), 1, 3)
+
+			if tc.expectErr != nil {
+				assert.ErrorIs(t, err, tc.expectErr)
+				assert.Nil(t, report)
+			} else {
+				assert.NoError(t, err)
+				body, err := io.ReadAll(report)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.wantBody, string(body))
+			}
+			repo.AssertExpectations(t)
+			s3c.AssertExpectations(t)
+		})
+	}
+}
+
+func TestContactImportService_GetDeletionsReport(t *testing.T) {
+	tests := map[string]struct {
+		stored    *models.ContactImport
+		setupS3   func(m *MockS3Client)
+		wantBody  string
+		expectErr error
+	}{
+		"success": {
+			stored: &models.ContactImport{ID: 3, DeletionsKey: "imports/3/deleted.csv"},
+			setupS3: func(m *MockS3Client) {
+				m.
+					On("GetObjectWithContext", mock.Anything, mock.MatchedBy(func(in *s3.GetObjectInput) bool {
+						return aws.StringValue(in.Bucket) == "bucket" && aws.StringValue(in.Key) == "imports/3/deleted.csv"
+					})).
+					Return(&s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader("name,phone\n"))}, nil).
+					Once()
+			},
+			wantBody: "name,phone\n",
+		},
+		"nothing deleted": {
+			stored:    &models.ContactImport{ID: 3, ReportKey: "imports/3/rejected.csv"},
+			setupS3:   func(m *MockS3Client) {},
+			expectErr: domain.ErrContactImportDeletionsNotExists,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			repo := new(MockContactImportRepository)
+			repo.
+				On("GetContactImportByID", mock.Anything, 1, 3).
+				Return(tc.stored, nil).
+				Once()
+			s3c := new(MockS3Client)
+			tc.setupS3(s3c)
+
+			svc := service.NewContactImportService(repo, s3c, "bucket")
+			report, err := svc.GetDeletionsReport(context.Background(), 1, 3)
+
+			if tc.expectErr != nil {
+				assert.ErrorIs(t, err, tc.expectErr)
+				assert.Nil(t, report)
+			} else {
+				assert.NoError(t, err)
+				body, err := io.ReadAll(report)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.wantBody, string(body))
+			}
+			repo.AssertExpectations(t)
+			s3c.AssertExpectations(t)
+		})
+	}
+}
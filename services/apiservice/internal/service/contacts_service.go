@@ -2,8 +2,14 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"slices"
+	"strings"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/geo"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/phoneutils"
 )
@@ -12,36 +18,38 @@ import (
 // It validates input and delegates persistence to a ContactsRepository.
 type ContactsService struct {
 	repository   domain.ContactsRepository
+	users        domain.UserRepository
+	attributes   domain.ContactAttributeRepository
 	defaultLimit int
 	maxLimit     int
+	bulkLimit    int
 }
 
 // NewContactsService constructs a ContactsService given a repository implementation.
-func NewContactsService(r domain.ContactsRepository, defaultLimit, maxLimit int) *ContactsService {
+// The user repository supplies each user's default region for parsing phone numbers, and the
+// attribute repository the definitions attribute values are checked against.
+// bulkLimit caps the number of contacts created, or of IDs named, in one bulk request.
+func NewContactsService(r domain.ContactsRepository, ur domain.UserRepository, ar domain.ContactAttributeRepository, defaultLimit, maxLimit, bulkLimit int) *ContactsService {
 	return &ContactsService{
 		repository:   r,
+		users:        ur,
+		attributes:   ar,
 		defaultLimit: defaultLimit,
 		maxLimit:     maxLimit,
+		bulkLimit:    bulkLimit,
 	}
 }
 
-// GetContactsCountByUserID retrieves count of contacts belonging to the specified user.
-func (cs *ContactsService) GetContactsCountByUserID(ctx context.Context, userID int) (int, error) {
-	return cs.repository.GetContactsCountByUserID(ctx, userID)
+// GetContactsCountByUserID retrieves count of the user's contacts matching the filter.
+func (cs *ContactsService) GetContactsCountByUserID(ctx context.Context, userID int, filter domain.ContactFilter) (int, error) {
+	return cs.repository.GetContactsCountByUserID(ctx, userID, filter)
 }
 
-// GetContactsPageByUserID retrieves page of contacts for a given user.
-func (cs *ContactsService) GetContactsPageByUserID(ctx context.Context, userID, limit, offset int) ([]*models.Contact, error) {
-	if limit <= 0 {
-		limit = cs.defaultLimit
-	}
-	if limit > cs.maxLimit {
-		limit = cs.maxLimit
-	}
-	if offset < 0 {
-		offset = 0
-	}
-	return cs.repository.GetContactsPageByUserID(ctx, userID, limit, offset)
+// GetContactsPageByUserID retrieves a page of the user's contacts matching the filter and the
+// cursor of the next page. The limit is clamped to the configured maximum.
+func (cs *ContactsService) GetContactsPageByUserID(ctx context.Context, userID int, filter domain.ContactFilter, page domain.PageRequest) ([]*models.Contact, string, error) {
+	page.Limit, page.Offset = clampPage(page.Limit, page.Offset, cs.defaultLimit, cs.maxLimit)
+	return cs.repository.GetContactsPageByUserID(ctx, userID, filter, page)
 }
 
 // GetContactByID retrieves a single contact by its ID for a given user.
@@ -50,34 +58,54 @@ func (cs *ContactsService) GetContactByID(ctx context.Context, userID, contactID
 }
 
 // CreateContact validates the incoming contact, formats its phone number, and then creates it via the repository.
-// Returns the created Contact model or a domain error on validation or persistence failure.
-func (cs *ContactsService) CreateContact(ctx context.Context, contact *models.Contact) (*models.Contact, error) {
-	if len(contact.Name) == 0 || len(contact.Name) > 32 {
-		return nil, domain.ErrInvalidContactName
+// The phone number is parsed in the given region, or in the user's default region when it is empty.
+// Attribute values are checked against, and normalized by, the user's attribute definitions.
+// Returns the created Contact model or a domain error on validation or persistence failure,
+// domain.ErrContactAlreadyExists if another contact of the user uses its phone number or endpoints.
+func (cs *ContactsService) CreateContact(ctx context.Context, contact *models.Contact, region string) (*models.Contact, error) {
+	defs, err := cs.attributes.GetContactAttributes(ctx, contact.UserID)
+	if err != nil {
+		return nil, err
 	}
 
-	normalizedNum, err := phoneutils.FormatToE164(contact.Phone, phoneutils.RegionRU)
+	err = cs.validateContact(ctx, contact.UserID, contact, region, defs)
 	if err != nil {
-		return nil, domain.ErrInvalidContactPhone
+		return nil, err
 	}
 
-	contact.Phone = normalizedNum
+	err = cs.checkAddressesFree(ctx, contact.UserID, 0, contact)
+	if err != nil {
+		return nil, err
+	}
 
 	return cs.repository.CreateContact(ctx, contact)
 }
 
-// UpdateContact validates and formats the updated contact, then applies changes via repository.
-func (cs *ContactsService) UpdateContact(ctx context.Context, userID, contactID int, updatedContact *models.Contact) (*models.Contact, error) {
-	if len(updatedContact.Name) == 0 || len(updatedContact.Name) > 32 {
-		return nil, domain.ErrInvalidContactName
+// UpdateContact merges the request into the stored contact, keeping the optional fields it omits,
+// then validates and formats the result and applies changes via repository.
+// The region and attributes are handled as in CreateContact.
+// Returns domain.ErrContactNotExists if the user has no such contact.
+func (cs *ContactsService) UpdateContact(ctx context.Context, userID, contactID int, req *domain.PutContactRequest) (*models.Contact, error) {
+	stored, err := cs.repository.GetContactByID(ctx, userID, contactID)
+	if err != nil {
+		return nil, err
+	}
+	updatedContact := req.Merge(userID, stored)
+
+	defs, err := cs.attributes.GetContactAttributes(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	normalizedNum, err := phoneutils.FormatToE164(updatedContact.Phone, phoneutils.RegionRU)
+	err = cs.validateContact(ctx, userID, updatedContact, req.Region, defs)
 	if err != nil {
-		return nil, domain.ErrInvalidContactPhone
+		return nil, err
 	}
 
-	updatedContact.Phone = normalizedNum
+	err = cs.checkAddressesFree(ctx, userID, contactID, updatedContact)
+	if err != nil {
+		return nil, err
+	}
 
 	return cs.repository.UpdateContact(ctx, userID, contactID, updatedContact)
 }
@@ -87,3 +115,376 @@ func (cs *ContactsService) UpdateContact(ctx context.Context, userID, contactID
 func (cs *ContactsService) DeleteContact(ctx context.Context, userID, contactID int) error {
 	return cs.repository.DeleteContact(ctx, userID, contactID)
 }
+
+// CreateContacts validates and creates many contacts of the user at once, reporting the outcome
+// of every item. Invalid items and phone numbers or endpoints the user already has, or that repeat
+// within the request, are reported and skipped; the others are written together or not at all.
+// Returns domain.ErrTooManyContacts if the request exceeds the bulk limit.
+func (cs *ContactsService) CreateContacts(ctx context.Context, userID int, contacts []domain.PostContactRequest) (*domain.BulkCreateContactsResponse, error) {
+	if len(contacts) > cs.bulkLimit {
+		return nil, domain.ErrTooManyContacts
+	}
+
+	resp := &domain.BulkCreateContactsResponse{
+		Results: make([]*domain.BulkContactResult, len(contacts)),
+	}
+
+	defaultRegion, err := cs.bulkDefaultRegion(ctx, userID, contacts)
+	if err != nil {
+		return nil, err
+	}
+
+	defs, err := cs.attributes.GetContactAttributes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	valid := make([]*models.Contact, 0, len(contacts))
+	indexByPhone := make(map[string]int, len(contacts))
+	claimed := make(map[models.ContactEndpoint]bool)
+	for i, req := range contacts {
+		result := &domain.BulkContactResult{Index: i}
+		resp.Results[i] = result
+
+		region := req.Region
+		if region == "" {
+			region = defaultRegion
+		}
+		contact := &models.Contact{
+			UserID:     userID,
+			Name:       req.Name,
+			Phone:      req.Phone,
+			TimeZone:   req.TimeZone,
+			Attributes: req.Attributes,
+			Endpoints:  req.Endpoints,
+			Location:   req.Location,
+		}
+
+		err := cs.validateContact(ctx, userID, contact, region, defs)
+		switch {
+		case errors.Is(err, domain.ErrInvalidContact):
+			result.Status, result.Error = domain.BulkItemInvalid, err.Error()
+			continue
+		case err != nil:
+			return nil, err
+		}
+
+		if _, ok := indexByPhone[contact.Phone]; ok {
+			result.Status = domain.BulkItemDuplicate
+			continue
+		}
+		addresses := contactAddresses(contact)
+		if slices.ContainsFunc(addresses, func(a *models.ContactEndpoint) bool { return claimed[*a] }) {
+			result.Status = domain.BulkItemDuplicate
+			continue
+		}
+		for _, a := range addresses {
+			claimed[*a] = true
+		}
+		indexByPhone[contact.Phone] = i
+		valid = append(valid, contact)
+	}
+
+	valid, err = cs.withoutTakenAddresses(ctx, userID, valid)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(valid) > 0 {
+		created, err := cs.repository.CreateContacts(ctx, userID, valid)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range created {
+			result := resp.Results[indexByPhone[c.Phone]]
+			result.Status, result.Contact = domain.BulkItemCreated, c
+		}
+	}
+
+	for _, result := range resp.Results {
+		if result.Status == "" {
+			result.Status = domain.BulkItemDuplicate
+		}
+		switch result.Status {
+		case domain.BulkItemCreated:
+			resp.Created++
+		case domain.BulkItemDuplicate:
+			resp.Duplicates++
+		case domain.BulkItemInvalid:
+			resp.Invalid++
+		}
+	}
+
+	return resp, nil
+}
+
+// DeleteContacts removes the user's contacts matching the filter and returns how many were deleted.
+// Returns domain.ErrEmptyContactFilter for an empty filter, as deleting every contact is
+// requested with DeleteAllContacts, and domain.ErrTooManyContacts if it names too many IDs.
+func (cs *ContactsService) DeleteContacts(ctx context.Context, userID int, filter domain.ContactFilter) (int, error) {
+	err := cs.checkBulkFilter(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	return cs.repository.DeleteContacts(ctx, userID, filter)
+}
+
+// DeleteAllContacts removes every contact of the user and returns how many were deleted.
+func (cs *ContactsService) DeleteAllContacts(ctx context.Context, userID int) (int, error) {
+	return cs.repository.DeleteContacts(ctx, userID, domain.ContactFilter{})
+}
+
+// SetContactsGroup assigns the user's contacts matching the filter to the group, or removes them
+// from their groups when it is empty, and returns how many contacts were changed.
+// The filter is checked as in DeleteContacts; returns domain.ErrInvalidContactGroup for a group
+// longer than an attribute value may be.
+func (cs *ContactsService) SetContactsGroup(ctx context.Context, userID int, filter domain.ContactFilter, group string) (int, error) {
+	err := cs.checkBulkFilter(filter)
+	if err != nil {
+		return 0, err
+	}
+	if len(group) > maxContactAttributeLen {
+		return 0, domain.ErrInvalidContactGroup
+	}
+
+	return cs.repository.SetContactsGroup(ctx, userID, filter, group)
+}
+
+func (cs *ContactsService) checkBulkFilter(filter domain.ContactFilter) error {
+	if filter.IsEmpty() {
+		return domain.ErrEmptyContactFilter
+	}
+	if len(filter.IDs) > cs.bulkLimit {
+		return domain.ErrTooManyContacts
+	}
+	return nil
+}
+
+// bulkDefaultRegion returns the user's default region if any of the contacts needs it to parse
+// a phone number, so that the user is looked up once per request rather than once per contact.
+func (cs *ContactsService) bulkDefaultRegion(ctx context.Context, userID int, contacts []domain.PostContactRequest) (string, error) {
+	for _, c := range contacts {
+		if c.Region == "" && needsRegion(c.Phone, c.Endpoints) {
+			user, err := cs.users.GetUserByID(ctx, userID)
+			if err != nil {
+				return "", err
+			}
+			return user.DefaultRegion, nil
+		}
+	}
+	return "", nil
+}
+
+// needsRegion reports whether the phone or one of the SMS endpoints lacks a country code.
+func needsRegion(phone string, endpoints []*models.ContactEndpoint) bool {
+	if !strings.HasPrefix(strings.TrimSpace(phone), "+") {
+		return true
+	}
+	for _, e := range endpoints {
+		if e != nil && e.Channel == models.ChannelSMS && !strings.HasPrefix(strings.TrimSpace(e.Address), "+") {
+			return true
+		}
+	}
+	return false
+}
+
+// validateContact checks the name, time zone, attributes, location and endpoints of the contact and converts
+// its phone numbers to E.164, parsing them in the region as described for CreateContact. Attribute
+// values are normalized according to defs.
+func (cs *ContactsService) validateContact(ctx context.Context, userID int, contact *models.Contact, region string, defs []*models.ContactAttribute) error {
+	if len(contact.Name) == 0 || len(contact.Name) > 32 {
+		return domain.ErrInvalidContactName
+	}
+
+	normalizedNum, err := cs.formatPhone(ctx, userID, contact.Phone, region)
+	if err != nil {
+		return err
+	}
+
+	if contact.TimeZone != "" && !isValidTimeZone(contact.TimeZone) {
+		return domain.ErrInvalidContactTimeZone
+	}
+
+	if !isValidAttributes(contact.Attributes) {
+		return domain.ErrInvalidContactAttributes
+	}
+
+	if contact.Location != nil && !geo.ValidLatLon(contact.Location.Lat, contact.Location.Lon) {
+		return domain.ErrInvalidContactLocation
+	}
+
+	attributes, err := applyContactAttributes(contact.Attributes, defs)
+	if err != nil {
+		return err
+	}
+
+	endpoints, err := cs.validateEndpoints(ctx, userID, normalizedNum, contact.Endpoints, region)
+	if err != nil {
+		return err
+	}
+
+	contact.Phone = normalizedNum
+	contact.Attributes = attributes
+	contact.Endpoints = endpoints
+
+	return nil
+}
+
+// formatPhone converts the phone number to E.164. Numbers without a country code are parsed
+// in the region override if given, otherwise in the user's default region; the user is only
+// looked up when the number actually needs a region.
+func (cs *ContactsService) formatPhone(ctx context.Context, userID int, phone, region string) (string, error) {
+	var code phoneutils.ISO3166Alpha2
+	switch {
+	case region != "":
+		var ok bool
+		code, ok = phoneutils.ParseRegion(region)
+		if !ok {
+			return "", domain.ErrInvalidContactRegion
+		}
+	case !strings.HasPrefix(strings.TrimSpace(phone), "+"):
+		user, err := cs.users.GetUserByID(ctx, userID)
+		if err != nil {
+			return "", err
+		}
+		code = phoneutils.ISO3166Alpha2(user.DefaultRegion)
+	}
+
+	normalizedNum, err := phoneutils.FormatToE164(phone, code)
+	if err != nil {
+		return "", domain.ErrInvalidContactPhone
+	}
+
+	return normalizedNum, nil
+}
+
+// contactAddresses returns the phone number of the contact as an SMS endpoint followed by its
+// endpoints, without their labels.
+func contactAddresses(contact *models.Contact) []*models.ContactEndpoint {
+	addresses := make([]*models.ContactEndpoint, 0, len(contact.Endpoints)+1)
+	addresses = append(addresses, &models.ContactEndpoint{Channel: models.ChannelSMS, Address: contact.Phone})
+	for _, e := range contact.Endpoints {
+		addresses = append(addresses, &models.ContactEndpoint{Channel: e.Channel, Address: e.Address})
+	}
+	return addresses
+}
+
+// checkAddressesFree returns domain.ErrContactAlreadyExists if a contact of the user other than
+// contactID already uses the phone number or an endpoint of the contact, so that the same person
+// isn't stored, and notified, twice.
+func (cs *ContactsService) checkAddressesFree(ctx context.Context, userID, contactID int, contact *models.Contact) error {
+	taken, err := cs.repository.GetTakenContactAddresses(ctx, userID, contactID, contactAddresses(contact))
+	if err != nil {
+		return err
+	}
+	if len(taken) > 0 {
+		return fmt.Errorf("%w: %s is used by another contact", domain.ErrContactAlreadyExists, taken[0].Address)
+	}
+
+	return nil
+}
+
+// withoutTakenAddresses returns the new contacts without those using a phone number or endpoint
+// that another contact of the user already uses.
+func (cs *ContactsService) withoutTakenAddresses(ctx context.Context, userID int, contacts []*models.Contact) ([]*models.Contact, error) {
+	if len(contacts) == 0 {
+		return contacts, nil
+	}
+
+	addresses := make([]*models.ContactEndpoint, 0, len(contacts))
+	for _, c := range contacts {
+		addresses = append(addresses, contactAddresses(c)...)
+	}
+	taken, err := cs.repository.GetTakenContactAddresses(ctx, userID, 0, addresses)
+	if err != nil {
+		return nil, err
+	}
+	if len(taken) == 0 {
+		return contacts, nil
+	}
+
+	takenSet := make(map[models.ContactEndpoint]bool, len(taken))
+	for _, a := range taken {
+		takenSet[*a] = true
+	}
+
+	return slices.DeleteFunc(contacts, func(c *models.Contact) bool {
+		return slices.ContainsFunc(contactAddresses(c), func(a *models.ContactEndpoint) bool { return takenSet[*a] })
+	}), nil
+}
+
+// validateEndpoints checks the contact's further endpoints and returns them with phone numbers
+// in E.164 and e-mail addresses in lower case. An endpoint may repeat neither another one nor the
+// primary phone of the contact.
+func (cs *ContactsService) validateEndpoints(ctx context.Context, userID int, phone string, endpoints []*models.ContactEndpoint, region string) ([]*models.ContactEndpoint, error) {
+	if len(endpoints) == 0 {
+		return nil, nil
+	}
+	if len(endpoints) > maxContactEndpoints {
+		return nil, domain.ErrInvalidContactEndpoints
+	}
+
+	seen := map[models.ContactEndpoint]bool{{Channel: models.ChannelSMS, Address: phone}: true}
+	result := make([]*models.ContactEndpoint, len(endpoints))
+	for i, e := range endpoints {
+		if e == nil {
+			return nil, domain.ErrInvalidContactEndpoints
+		}
+		label := strings.TrimSpace(e.Label)
+		if len(label) > maxContactEndpointLabelLen {
+			return nil, domain.ErrInvalidContactEndpoints
+		}
+
+		var address string
+		switch e.Channel {
+		case models.ChannelSMS:
+			num, err := cs.formatPhone(ctx, userID, e.Address, region)
+			if errors.Is(err, domain.ErrInvalidContactPhone) {
+				return nil, fmt.Errorf("%w: invalid phone %q", domain.ErrInvalidContactEndpoints, e.Address)
+			}
+			if err != nil {
+				return nil, err
+			}
+			address = num
+		case models.ChannelEmail:
+			addr, err := mail.ParseAddress(strings.TrimSpace(e.Address))
+			if err != nil || addr.Name != "" || addr.Address != strings.TrimSpace(e.Address) {
+				return nil, fmt.Errorf("%w: invalid e-mail %q", domain.ErrInvalidContactEndpoints, e.Address)
+			}
+			address = strings.ToLower(addr.Address)
+		default:
+			return nil, fmt.Errorf("%w: unknown channel %q", domain.ErrInvalidContactEndpoints, e.Channel)
+		}
+
+		key := models.ContactEndpoint{Channel: e.Channel, Address: address}
+		if seen[key] {
+			return nil, fmt.Errorf("%w: duplicate %s", domain.ErrInvalidContactEndpoints, address)
+		}
+		seen[key] = true
+		result[i] = &models.ContactEndpoint{Channel: e.Channel, Address: address, Label: label}
+	}
+
+	return result, nil
+}
+
+const (
+	maxContactAttributes       = 50
+	maxContactAttributeNameLen = 64
+	maxContactAttributeLen     = 1024
+	maxContactEndpoints        = 10
+	maxContactEndpointLabelLen = 64
+)
+
+// isValidAttributes limits the number of contact attributes and the length of their names and values.
+func isValidAttributes(attributes map[string]string) bool {
+	if len(attributes) > maxContactAttributes {
+		return false
+	}
+	for name, value := range attributes {
+		if len(name) == 0 || len(name) > maxContactAttributeNameLen || len(value) > maxContactAttributeLen {
+			return false
+		}
+	}
+	return true
+}
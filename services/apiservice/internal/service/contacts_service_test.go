@@ -13,14 +13,16 @@ import (
 )
 
 func TestContactsService_GetContactsCountByUserID(t *testing.T) {
+	filter := domain.ContactFilter{Group: "Sales"}
+
 	m := new(MockContactsRepository)
 	m.
-		On("GetContactsCountByUserID", mock.Anything, 123).
+		On("GetContactsCountByUserID", mock.Anything, 123, filter).
 		Return(5, nil).
 		Once()
-	svc := service.NewContactsService(m, 50, 100)
+	svc := service.NewContactsService(m, new(MockUserRepository), noContactAttributes(), 50, 100, 1000)
 
-	count, err := svc.GetContactsCountByUserID(context.Background(), 123)
+	count, err := svc.GetContactsCountByUserID(context.Background(), 123, filter)
 	assert.NoError(t, err)
 	assert.Equal(t, 5, count)
 	m.AssertExpectations(t)
@@ -31,18 +33,43 @@ func TestContactsService_GetContactsByUserID(t *testing.T) {
 		{ID: 1, UserID: 123, Name: "Alice", Phone: "+79123456789"},
 		{ID: 2, UserID: 123, Name: "Bob", Phone: "+79129876543"},
 	}
+	filter := domain.ContactFilter{Search: "a"}
+	sort := domain.Sort{Field: "name", Desc: true}
 
-	m := new(MockContactsRepository)
-	m.
-		On("GetContactsPageByUserID", mock.Anything, 123, 50, 0).
-		Return(contacts, nil).
-		Once()
-	svc := service.NewContactsService(m, 50, 100)
+	tests := map[string]struct {
+		page     domain.PageRequest
+		wantPage domain.PageRequest
+	}{
+		"default limit": {
+			page:     domain.PageRequest{Sort: sort},
+			wantPage: domain.PageRequest{Limit: 50, Sort: sort},
+		},
+		"limit capped and negative offset": {
+			page:     domain.PageRequest{Limit: 1000, Offset: -5, Sort: sort},
+			wantPage: domain.PageRequest{Limit: 100, Sort: sort},
+		},
+		"cursor passed through": {
+			page:     domain.PageRequest{Limit: 2, Cursor: "abc", Sort: sort},
+			wantPage: domain.PageRequest{Limit: 2, Cursor: "abc", Sort: sort},
+		},
+	}
 
-	res, err := svc.GetContactsPageByUserID(context.Background(), 123, 0, 0)
-	assert.NoError(t, err)
-	assert.Equal(t, contacts, res)
-	m.AssertExpectations(t)
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := new(MockContactsRepository)
+			m.
+				On("GetContactsPageByUserID", mock.Anything, 123, filter, tc.wantPage).
+				Return(contacts, "next", nil).
+				Once()
+			svc := service.NewContactsService(m, new(MockUserRepository), noContactAttributes(), 50, 100, 1000)
+
+			res, next, err := svc.GetContactsPageByUserID(context.Background(), 123, filter, tc.page)
+			assert.NoError(t, err)
+			assert.Equal(t, contacts, res)
+			assert.Equal(t, "next", next)
+			m.AssertExpectations(t)
+		})
+	}
 }
 
 func TestContactsService_GetContactByID(t *testing.T) {
@@ -53,7 +80,7 @@ func TestContactsService_GetContactByID(t *testing.T) {
 		On("GetContactByID", mock.Anything, 123, 456).
 		Return(contact, nil).
 		Once()
-	svc := service.NewContactsService(m, 50, 100)
+	svc := service.NewContactsService(m, new(MockUserRepository), noContactAttributes(), 50, 100, 1000)
 
 	res, err := svc.GetContactByID(context.Background(), 123, 456)
 	assert.NoError(t, err)
@@ -61,10 +88,28 @@ func TestContactsService_GetContactByID(t *testing.T) {
 	m.AssertExpectations(t)
 }
 
+// newRegionUsers returns a user repository where user 123 parses local numbers as Russian,
+// user 456 as American and looking up user 789 fails.
+func newRegionUsers() *MockUserRepository {
+	ur := new(MockUserRepository)
+	ur.On("GetUserByID", mock.Anything, 123).Return(&models.User{ID: 123, DefaultRegion: "RU"}, nil).Maybe()
+	ur.On("GetUserByID", mock.Anything, 456).Return(&models.User{ID: 456, DefaultRegion: "US"}, nil).Maybe()
+	ur.On("GetUserByID", mock.Anything, 789).Return((*models.User)(nil), assert.AnError).Maybe()
+	return ur
+}
+
+// noContactAttributes returns an attribute repository of users without attribute definitions.
+func noContactAttributes() *MockContactAttributeRepository {
+	ar := new(MockContactAttributeRepository)
+	ar.On("GetContactAttributes", mock.Anything, mock.Anything).Return([]*models.ContactAttribute{}, nil).Maybe()
+	return ar
+}
+
 func TestContactsService_CreateContact(t *testing.T) {
 	type args struct {
 		ctx     context.Context
 		contact *models.Contact
+		region  string
 	}
 	tests := []struct {
 		name       string
@@ -82,8 +127,8 @@ func TestContactsService_CreateContact(t *testing.T) {
 					Once()
 			},
 			args: args{
-				context.Background(),
-				&models.Contact{UserID: 123, Name: "Alice", Phone: "+79123456789"},
+				ctx:     context.Background(),
+				contact: &models.Contact{UserID: 123, Name: "Alice", Phone: "+79123456789"},
 			},
 			wantResult: &models.Contact{ID: 1, UserID: 123, Name: "Alice", Phone: "+79123456789"},
 			wantErr:    nil,
@@ -92,8 +137,8 @@ func TestContactsService_CreateContact(t *testing.T) {
 			name:      "name too short",
 			mockSetup: func(m *MockContactsRepository) {},
 			args: args{
-				context.Background(),
-				&models.Contact{ID: 1, UserID: 123, Name: "", Phone: "+79123456789"},
+				ctx:     context.Background(),
+				contact: &models.Contact{ID: 1, UserID: 123, Name: "", Phone: "+79123456789"},
 			},
 			wantResult: nil,
 			wantErr:    domain.ErrInvalidContactName,
@@ -102,8 +147,8 @@ func TestContactsService_CreateContact(t *testing.T) {
 			name:      "name too long",
 			mockSetup: func(m *MockContactsRepository) {},
 			args: args{
-				context.Background(),
-				&models.Contact{ID: 1, UserID: 123, Name: strings.Repeat("A", 33), Phone: "+79123456789"},
+				ctx:     context.Background(),
+				contact: &models.Contact{ID: 1, UserID: 123, Name: strings.Repeat("A", 33), Phone: "+79123456789"},
 			},
 			wantResult: nil,
 			wantErr:    domain.ErrInvalidContactName,
@@ -118,6 +163,196 @@ func TestContactsService_CreateContact(t *testing.T) {
 			wantResult: nil,
 			wantErr:    domain.ErrInvalidContactPhone,
 		},
+		{
+			name: "explicit time zone",
+			mockSetup: func(m *MockContactsRepository) {
+				m.
+					On("CreateContact", mock.Anything, &models.Contact{UserID: 123, Name: "Alice", Phone: "+79123456789", TimeZone: "Asia/Yekaterinburg"}).
+					Return(&models.Contact{ID: 1, UserID: 123, Name: "Alice", Phone: "+79123456789", TimeZone: "Asia/Yekaterinburg"}, nil).
+					Once()
+			},
+			args: args{
+				ctx:     context.Background(),
+				contact: &models.Contact{UserID: 123, Name: "Alice", Phone: "+79123456789", TimeZone: "Asia/Yekaterinburg"},
+			},
+			wantResult: &models.Contact{ID: 1, UserID: 123, Name: "Alice", Phone: "+79123456789", TimeZone: "Asia/Yekaterinburg"},
+			wantErr:    nil,
+		},
+		{
+			name: "local number in user's default region",
+			mockSetup: func(m *MockContactsRepository) {
+				m.
+					On("CreateContact", mock.Anything, &models.Contact{UserID: 456, Name: "Bob", Phone: "+12025550123"}).
+					Return(&models.Contact{ID: 2, UserID: 456, Name: "Bob", Phone: "+12025550123"}, nil).
+					Once()
+			},
+			args: args{
+				ctx:     context.Background(),
+				contact: &models.Contact{UserID: 456, Name: "Bob", Phone: "(202) 555-0123"},
+			},
+			wantResult: &models.Contact{ID: 2, UserID: 456, Name: "Bob", Phone: "+12025550123"},
+			wantErr:    nil,
+		},
+		{
+			name: "local number in region override",
+			mockSetup: func(m *MockContactsRepository) {
+				m.
+					On("CreateContact", mock.Anything, &models.Contact{UserID: 123, Name: "Bob", Phone: "+12025550123"}).
+					Return(&models.Contact{ID: 2, UserID: 123, Name: "Bob", Phone: "+12025550123"}, nil).
+					Once()
+			},
+			args: args{
+				ctx:     context.Background(),
+				contact: &models.Contact{UserID: 123, Name: "Bob", Phone: "(202) 555-0123"},
+				region:  "us",
+			},
+			wantResult: &models.Contact{ID: 2, UserID: 123, Name: "Bob", Phone: "+12025550123"},
+			wantErr:    nil,
+		},
+		{
+			name:      "unknown region override",
+			mockSetup: func(m *MockContactsRepository) {},
+			args: args{
+				ctx:     context.Background(),
+				contact: &models.Contact{UserID: 123, Name: "Alice", Phone: "89123456789"},
+				region:  "XX",
+			},
+			wantResult: nil,
+			wantErr:    domain.ErrInvalidContactRegion,
+		},
+		{
+			name:      "user lookup error",
+			mockSetup: func(m *MockContactsRepository) {},
+			args: args{
+				ctx:     context.Background(),
+				contact: &models.Contact{UserID: 789, Name: "Alice", Phone: "89123456789"},
+			},
+			wantResult: nil,
+			wantErr:    assert.AnError,
+		},
+		{
+			name:      "invalid time zone",
+			mockSetup: func(m *MockContactsRepository) {},
+			args: args{
+				ctx:     context.Background(),
+				contact: &models.Contact{UserID: 123, Name: "Alice", Phone: "+79123456789", TimeZone: "Mars/Olympus"},
+			},
+			wantResult: nil,
+			wantErr:    domain.ErrInvalidContactTimeZone,
+		},
+		{
+			name:      "empty attribute name",
+			mockSetup: func(m *MockContactsRepository) {},
+			args: args{
+				ctx:     context.Background(),
+				contact: &models.Contact{UserID: 123, Name: "Alice", Phone: "+79123456789", Attributes: map[string]string{"": "x"}},
+			},
+			wantResult: nil,
+			wantErr:    domain.ErrInvalidContactAttributes,
+		},
+		{
+			name: "fallback endpoints normalized",
+			mockSetup: func(m *MockContactsRepository) {
+				m.
+					On("CreateContact", mock.Anything, &models.Contact{UserID: 123, Name: "Alice", Phone: "+79123456789", Endpoints: []*models.ContactEndpoint{
+						{Channel: models.ChannelSMS, Address: "+12025550123", Label: "personal"},
+						{Channel: models.ChannelEmail, Address: "alice@example.com"},
+					}}).
+					Return(&models.Contact{ID: 1, UserID: 123, Name: "Alice", Phone: "+79123456789"}, nil).
+					Once()
+			},
+			args: args{
+				ctx: context.Background(),
+				contact: &models.Contact{UserID: 123, Name: "Alice", Phone: "+79123456789", Endpoints: []*models.ContactEndpoint{
+					{Channel: models.ChannelSMS, Address: "(202) 555-0123", Label: " personal "},
+					{Channel: models.ChannelEmail, Address: "Alice@Example.com"},
+				}},
+				region: "us",
+			},
+			wantResult: &models.Contact{ID: 1, UserID: 123, Name: "Alice", Phone: "+79123456789"},
+			wantErr:    nil,
+		},
+		{
+			name: "endpoint used by another contact",
+			mockSetup: func(m *MockContactsRepository) {
+				m.
+					On("GetTakenContactAddresses", mock.Anything, 123, 0, []*models.ContactEndpoint{
+						{Channel: models.ChannelSMS, Address: "+79123456789"},
+						{Channel: models.ChannelEmail, Address: "bob@example.com"},
+					}).
+					Return([]*models.ContactEndpoint{{Channel: models.ChannelEmail, Address: "bob@example.com"}}, nil).
+					Once()
+			},
+			args: args{
+				ctx: context.Background(),
+				contact: &models.Contact{UserID: 123, Name: "Alice", Phone: "+79123456789", Endpoints: []*models.ContactEndpoint{
+					{Channel: models.ChannelEmail, Address: "bob@example.com"},
+				}},
+			},
+			wantResult: nil,
+			wantErr:    domain.ErrContactAlreadyExists,
+		},
+		{
+			name:      "endpoint repeats primary phone",
+			mockSetup: func(m *MockContactsRepository) {},
+			args: args{
+				ctx: context.Background(),
+				contact: &models.Contact{UserID: 123, Name: "Alice", Phone: "+79123456789", Endpoints: []*models.ContactEndpoint{
+					{Channel: models.ChannelSMS, Address: "+7 912 345-67-89"},
+				}},
+			},
+			wantResult: nil,
+			wantErr:    domain.ErrInvalidContactEndpoints,
+		},
+		{
+			name:      "endpoint with display name",
+			mockSetup: func(m *MockContactsRepository) {},
+			args: args{
+				ctx: context.Background(),
+				contact: &models.Contact{UserID: 123, Name: "Alice", Phone: "+79123456789", Endpoints: []*models.ContactEndpoint{
+					{Channel: models.ChannelEmail, Address: "Alice <alice@example.com>"},
+				}},
+			},
+			wantResult: nil,
+			wantErr:    domain.ErrInvalidContactEndpoints,
+		},
+		{
+			name:      "endpoint of unknown channel",
+			mockSetup: func(m *MockContactsRepository) {},
+			args: args{
+				ctx: context.Background(),
+				contact: &models.Contact{UserID: 123, Name: "Alice", Phone: "+79123456789", Endpoints: []*models.ContactEndpoint{
+					{Channel: "pager", Address: "12345"},
+				}},
+			},
+			wantResult: nil,
+			wantErr:    domain.ErrInvalidContactEndpoints,
+		},
+		{
+			name: "with location",
+			mockSetup: func(m *MockContactsRepository) {
+				m.
+					On("CreateContact", mock.Anything, &models.Contact{UserID: 123, Name: "Alice", Phone: "+79123456789", Location: &models.Location{Lat: 55.75, Lon: 37.62}}).
+					Return(&models.Contact{ID: 1, UserID: 123, Name: "Alice", Phone: "+79123456789", Location: &models.Location{Lat: 55.75, Lon: 37.62}}, nil).
+					Once()
+			},
+			args: args{
+				ctx:     context.Background(),
+				contact: &models.Contact{UserID: 123, Name: "Alice", Phone: "+79123456789", Location: &models.Location{Lat: 55.75, Lon: 37.62}},
+			},
+			wantResult: &models.Contact{ID: 1, UserID: 123, Name: "Alice", Phone: "+79123456789", Location: &models.Location{Lat: 55.75, Lon: 37.62}},
+			wantErr:    nil,
+		},
+		{
+			name:      "location out of range",
+			mockSetup: func(m *MockContactsRepository) {},
+			args: args{
+				ctx:     context.Background(),
+				contact: &models.Contact{UserID: 123, Name: "Alice", Phone: "+79123456789", Location: &models.Location{Lat: 37.62, Lon: 255.75}},
+			},
+			wantResult: nil,
+			wantErr:    domain.ErrInvalidContactLocation,
+		},
 		{
 			name: "repository error",
 			mockSetup: func(m *MockContactsRepository) {
@@ -139,9 +374,10 @@ func TestContactsService_CreateContact(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			m := new(MockContactsRepository)
 			tc.mockSetup(m)
-			svc := service.NewContactsService(m, 50, 100)
+			noTakenAddresses(m)
+			svc := service.NewContactsService(m, newRegionUsers(), noContactAttributes(), 50, 100, 1000)
 
-			res, err := svc.CreateContact(tc.args.ctx, tc.args.contact)
+			res, err := svc.CreateContact(tc.args.ctx, tc.args.contact, tc.args.region)
 			if tc.wantErr != nil {
 				assert.ErrorIs(t, err, tc.wantErr)
 			} else {
@@ -154,10 +390,30 @@ func TestContactsService_CreateContact(t *testing.T) {
 }
 
 func TestContactsService_UpdateContact(t *testing.T) {
+	nowhere := "Nowhere"
+	moscow := "Europe/Moscow"
+	stored := &models.Contact{
+		ID:         42,
+		UserID:     123,
+		Name:       "Alice",
+		Phone:      "+79123456789",
+		TimeZone:   "Asia/Yekaterinburg",
+		Attributes: map[string]string{"Group": "Night shift"},
+		Endpoints:  []*models.ContactEndpoint{{Channel: models.ChannelEmail, Address: "alice@example.com"}},
+		Location:   &models.Location{Lat: 55.75, Lon: 37.62},
+	}
+	keptFields := func(c *models.Contact) *models.Contact {
+		c.TimeZone = stored.TimeZone
+		c.Attributes = stored.Attributes
+		c.Endpoints = stored.Endpoints
+		c.Location = stored.Location
+		return c
+	}
+
 	type args struct {
-		ctx            context.Context
-		userID, cid    int
-		updatedContact *models.Contact
+		ctx         context.Context
+		userID, cid int
+		req         *domain.PutContactRequest
 	}
 
 	tests := []struct {
@@ -168,10 +424,10 @@ func TestContactsService_UpdateContact(t *testing.T) {
 		wantErr    error
 	}{
 		{
-			name: "success",
+			name: "omitted fields keep stored values",
 			mockSetup: func(m *MockContactsRepository) {
 				normalized := "+79123456789"
-				input := &models.Contact{UserID: 123, Name: "Alice", Phone: normalized}
+				input := keptFields(&models.Contact{UserID: 123, Name: "Alice", Phone: normalized})
 				output := &models.Contact{ID: 42, UserID: 123, Name: "Alice", Phone: normalized}
 				m.
 					On("UpdateContact", mock.Anything, 123, 42, input).
@@ -179,10 +435,10 @@ func TestContactsService_UpdateContact(t *testing.T) {
 					Once()
 			},
 			args: args{
-				ctx:            context.Background(),
-				userID:         123,
-				cid:            42,
-				updatedContact: &models.Contact{UserID: 123, Name: "Alice", Phone: "8 (912) 345-6789"},
+				ctx:    context.Background(),
+				userID: 123,
+				cid:    42,
+				req:    &domain.PutContactRequest{Name: "Alice", Phone: "8 (912) 345-6789"},
 			},
 			wantResult: &models.Contact{ID: 42, UserID: 123, Name: "Alice", Phone: "+79123456789"},
 			wantErr:    nil,
@@ -191,10 +447,10 @@ func TestContactsService_UpdateContact(t *testing.T) {
 			name:      "name too short",
 			mockSetup: func(m *MockContactsRepository) {},
 			args: args{
-				ctx:            context.Background(),
-				userID:         123,
-				cid:            42,
-				updatedContact: &models.Contact{UserID: 123, Name: "", Phone: "+79123456789"},
+				ctx:    context.Background(),
+				userID: 123,
+				cid:    42,
+				req:    &domain.PutContactRequest{Name: "", Phone: "+79123456789"},
 			},
 			wantResult: nil,
 			wantErr:    domain.ErrInvalidContactName,
@@ -203,10 +459,10 @@ func TestContactsService_UpdateContact(t *testing.T) {
 			name:      "name too long",
 			mockSetup: func(m *MockContactsRepository) {},
 			args: args{
-				ctx:            context.Background(),
-				userID:         123,
-				cid:            42,
-				updatedContact: &models.Contact{UserID: 123, Name: strings.Repeat("A", 33), Phone: "+79123456789"},
+				ctx:    context.Background(),
+				userID: 123,
+				cid:    42,
+				req:    &domain.PutContactRequest{Name: strings.Repeat("A", 33), Phone: "+79123456789"},
 			},
 			wantResult: nil,
 			wantErr:    domain.ErrInvalidContactName,
@@ -215,19 +471,115 @@ func TestContactsService_UpdateContact(t *testing.T) {
 			name:      "invalid phone",
 			mockSetup: func(m *MockContactsRepository) {},
 			args: args{
-				ctx:            context.Background(),
-				userID:         123,
-				cid:            42,
-				updatedContact: &models.Contact{UserID: 123, Name: "Alice", Phone: "not-a-number"},
+				ctx:    context.Background(),
+				userID: 123,
+				cid:    42,
+				req:    &domain.PutContactRequest{Name: "Alice", Phone: "not-a-number"},
 			},
 			wantResult: nil,
 			wantErr:    domain.ErrInvalidContactPhone,
 		},
+		{
+			name: "local number in region override",
+			mockSetup: func(m *MockContactsRepository) {
+				input := keptFields(&models.Contact{UserID: 123, Name: "Bob", Phone: "+12025550123"})
+				output := &models.Contact{ID: 42, UserID: 123, Name: "Bob", Phone: "+12025550123"}
+				m.
+					On("UpdateContact", mock.Anything, 123, 42, input).
+					Return(output, nil).
+					Once()
+			},
+			args: args{
+				ctx:    context.Background(),
+				userID: 123,
+				cid:    42,
+				req:    &domain.PutContactRequest{Name: "Bob", Phone: "(202) 555-0123", Region: "US"},
+			},
+			wantResult: &models.Contact{ID: 42, UserID: 123, Name: "Bob", Phone: "+12025550123"},
+			wantErr:    nil,
+		},
+		{
+			name:      "unknown region override",
+			mockSetup: func(m *MockContactsRepository) {},
+			args: args{
+				ctx:    context.Background(),
+				userID: 123,
+				cid:    42,
+				req:    &domain.PutContactRequest{Name: "Alice", Phone: "89123456789", Region: "RUS"},
+			},
+			wantResult: nil,
+			wantErr:    domain.ErrInvalidContactRegion,
+		},
+		{
+			name:      "invalid time zone",
+			mockSetup: func(m *MockContactsRepository) {},
+			args: args{
+				ctx:    context.Background(),
+				userID: 123,
+				cid:    42,
+				req:    &domain.PutContactRequest{Name: "Alice", Phone: "+79123456789", TimeZone: &nowhere},
+			},
+			wantResult: nil,
+			wantErr:    domain.ErrInvalidContactTimeZone,
+		},
+		{
+			name:      "attribute value too long",
+			mockSetup: func(m *MockContactsRepository) {},
+			args: args{
+				ctx:    context.Background(),
+				userID: 123,
+				cid:    42,
+				req:    &domain.PutContactRequest{Name: "Alice", Phone: "+79123456789", Attributes: map[string]string{"dept": strings.Repeat("x", 1025)}},
+			},
+			wantResult: nil,
+			wantErr:    domain.ErrInvalidContactAttributes,
+		},
+		{
+			name: "given fields replace stored ones",
+			mockSetup: func(m *MockContactsRepository) {
+				input := &models.Contact{UserID: 123, Name: "Alice", Phone: "+79123456789", TimeZone: "Europe/Moscow", Attributes: map[string]string{}}
+				output := &models.Contact{ID: 42, UserID: 123, Name: "Alice", Phone: "+79123456789", TimeZone: "Europe/Moscow"}
+				m.
+					On("UpdateContact", mock.Anything, 123, 42, input).
+					Return(output, nil).
+					Once()
+			},
+			args: args{
+				ctx:    context.Background(),
+				userID: 123,
+				cid:    42,
+				req: &domain.PutContactRequest{
+					Name:       "Alice",
+					Phone:      "+79123456789",
+					TimeZone:   &moscow,
+					Attributes: map[string]string{},
+					Endpoints:  []*models.ContactEndpoint{},
+					Location:   domain.NullableLocation{Set: true},
+				},
+			},
+			wantResult: &models.Contact{ID: 42, UserID: 123, Name: "Alice", Phone: "+79123456789", TimeZone: "Europe/Moscow"},
+		},
+		{
+			name: "not found",
+			mockSetup: func(m *MockContactsRepository) {
+				m.
+					On("GetContactByID", mock.Anything, 123, 43).
+					Return((*models.Contact)(nil), domain.ErrContactNotExists).
+					Once()
+			},
+			args: args{
+				ctx:    context.Background(),
+				userID: 123,
+				cid:    43,
+				req:    &domain.PutContactRequest{Name: "Alice", Phone: "+79123456789"},
+			},
+			wantErr: domain.ErrContactNotExists,
+		},
 		{
 			name: "repository error",
 			mockSetup: func(m *MockContactsRepository) {
 				normalized := "+79123456789"
-				input := &models.Contact{UserID: 123, Name: "Alice", Phone: normalized}
+				input := keptFields(&models.Contact{UserID: 123, Name: "Alice", Phone: normalized})
 
 				m.
 					On("UpdateContact", mock.Anything, 123, 42, input).
@@ -235,10 +587,10 @@ func TestContactsService_UpdateContact(t *testing.T) {
 					Once()
 			},
 			args: args{
-				ctx:            context.Background(),
-				userID:         123,
-				cid:            42,
-				updatedContact: &models.Contact{UserID: 123, Name: "Alice", Phone: "+79123456789"},
+				ctx:    context.Background(),
+				userID: 123,
+				cid:    42,
+				req:    &domain.PutContactRequest{Name: "Alice", Phone: "+79123456789"},
 			},
 			wantResult: nil,
 			wantErr:    assert.AnError,
@@ -249,9 +601,11 @@ func TestContactsService_UpdateContact(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			m := new(MockContactsRepository)
 			tc.mockSetup(m)
-			svc := service.NewContactsService(m, 50, 100)
+			m.On("GetContactByID", mock.Anything, 123, 42).Return(stored, nil).Maybe()
+			noTakenAddresses(m)
+			svc := service.NewContactsService(m, newRegionUsers(), noContactAttributes(), 50, 100, 1000)
 
-			res, err := svc.UpdateContact(tc.args.ctx, tc.args.userID, tc.args.cid, tc.args.updatedContact)
+			res, err := svc.UpdateContact(tc.args.ctx, tc.args.userID, tc.args.cid, tc.args.req)
 
 			if tc.wantErr != nil {
 				assert.ErrorIs(t, err, tc.wantErr)
@@ -272,9 +626,231 @@ func TestContactsService_DeleteContact(t *testing.T) {
 		On("DeleteContact", mock.Anything, 123, 42).
 		Return(nil).
 		Once()
-	svc := service.NewContactsService(m, 50, 100)
+	svc := service.NewContactsService(m, new(MockUserRepository), noContactAttributes(), 50, 100, 1000)
 
 	err := svc.DeleteContact(context.Background(), 123, 42)
 	assert.NoError(t, err)
 	m.AssertExpectations(t)
 }
+
+// noTakenAddresses makes the phone numbers and endpoints of every contact free.
+func noTakenAddresses(m *MockContactsRepository) {
+	m.On("GetTakenContactAddresses", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+}
+
+func TestContactsService_CreateContacts(t *testing.T) {
+	t.Run("reports every item", func(t *testing.T) {
+		m := new(MockContactsRepository)
+		created := &models.Contact{ID: 1, UserID: 123, Name: "Alice", Phone: "+79123456789"}
+		m.
+			On("CreateContacts", mock.Anything, 123, []*models.Contact{
+				{UserID: 123, Name: "Alice", Phone: "+79123456789"},
+				{UserID: 123, Name: "Bob", Phone: "+79123456780"},
+			}).
+			Return([]*models.Contact{created}, nil).
+			Once()
+		noTakenAddresses(m)
+		svc := service.NewContactsService(m, newRegionUsers(), noContactAttributes(), 50, 100, 1000)
+
+		resp, err := svc.CreateContacts(context.Background(), 123, []domain.PostContactRequest{
+			{Name: "Alice", Phone: "89123456789"},
+			{Name: "", Phone: "+79123456781"},
+			{Name: "Alice again", Phone: "+7 912 345-67-89"},
+			{Name: "Bob", Phone: "+79123456780"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.BulkCreateContactsResponse{
+			Created:    1,
+			Duplicates: 2,
+			Invalid:    1,
+			Results: []*domain.BulkContactResult{
+				{Index: 0, Status: domain.BulkItemCreated, Contact: created},
+				{Index: 1, Status: domain.BulkItemInvalid, Error: domain.ErrInvalidContactName.Error()},
+				{Index: 2, Status: domain.BulkItemDuplicate},
+				{Index: 3, Status: domain.BulkItemDuplicate},
+			},
+		}, resp)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("skips contacts sharing endpoints", func(t *testing.T) {
+		m := new(MockContactsRepository)
+		created := &models.Contact{ID: 1, UserID: 123, Name: "Alice", Phone: "+79123456789"}
+		alice := &models.Contact{UserID: 123, Name: "Alice", Phone: "+79123456789", Endpoints: []*models.ContactEndpoint{
+			{Channel: models.ChannelEmail, Address: "family@example.com"},
+		}}
+		m.
+			On("GetTakenContactAddresses", mock.Anything, 123, 0, mock.Anything).
+			Return([]*models.ContactEndpoint{{Channel: models.ChannelSMS, Address: "+79123456781"}}, nil).
+			Once()
+		m.
+			On("CreateContacts", mock.Anything, 123, []*models.Contact{alice}).
+			Return([]*models.Contact{created}, nil).
+			Once()
+		svc := service.NewContactsService(m, newRegionUsers(), noContactAttributes(), 50, 100, 1000)
+
+		resp, err := svc.CreateContacts(context.Background(), 123, []domain.PostContactRequest{
+			{Name: "Alice", Phone: "+79123456789", Endpoints: []*models.ContactEndpoint{{Channel: models.ChannelEmail, Address: "family@example.com"}}},
+			{Name: "Bob", Phone: "+79123456780", Endpoints: []*models.ContactEndpoint{{Channel: models.ChannelEmail, Address: "family@example.com"}}},
+			{Name: "Carl", Phone: "+79123456782", Endpoints: []*models.ContactEndpoint{{Channel: models.ChannelSMS, Address: "+79123456781"}}},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, resp.Created)
+		assert.Equal(t, 2, resp.Duplicates)
+		assert.Equal(t, domain.BulkItemDuplicate, resp.Results[1].Status)
+		assert.Equal(t, domain.BulkItemDuplicate, resp.Results[2].Status)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("all invalid", func(t *testing.T) {
+		m := new(MockContactsRepository)
+		svc := service.NewContactsService(m, newRegionUsers(), noContactAttributes(), 50, 100, 1000)
+
+		resp, err := svc.CreateContacts(context.Background(), 123, []domain.PostContactRequest{
+			{Name: "Alice", Phone: "123"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, resp.Invalid)
+		assert.Equal(t, domain.BulkItemInvalid, resp.Results[0].Status)
+		m.AssertNotCalled(t, "CreateContacts", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("too many contacts", func(t *testing.T) {
+		m := new(MockContactsRepository)
+		svc := service.NewContactsService(m, newRegionUsers(), noContactAttributes(), 50, 100, 1)
+
+		resp, err := svc.CreateContacts(context.Background(), 123, []domain.PostContactRequest{
+			{Name: "Alice", Phone: "+79123456789"},
+			{Name: "Bob", Phone: "+79123456780"},
+		})
+
+		assert.ErrorIs(t, err, domain.ErrTooManyContacts)
+		assert.Nil(t, resp)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		m := new(MockContactsRepository)
+		m.
+			On("CreateContacts", mock.Anything, 123, mock.Anything).
+			Return(nil, assert.AnError).
+			Once()
+		noTakenAddresses(m)
+		svc := service.NewContactsService(m, newRegionUsers(), noContactAttributes(), 50, 100, 1000)
+
+		resp, err := svc.CreateContacts(context.Background(), 123, []domain.PostContactRequest{
+			{Name: "Alice", Phone: "+79123456789"},
+		})
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, resp)
+		m.AssertExpectations(t)
+	})
+}
+
+func TestContactsService_DeleteContacts(t *testing.T) {
+	tests := map[string]struct {
+		filter    domain.ContactFilter
+		mockSetup func(m *MockContactsRepository)
+		want      int
+		wantErr   error
+	}{
+		"by ids": {
+			filter: domain.ContactFilter{IDs: []int{1, 2}},
+			mockSetup: func(m *MockContactsRepository) {
+				m.
+					On("DeleteContacts", mock.Anything, 123, domain.ContactFilter{IDs: []int{1, 2}}).
+					Return(2, nil).
+					Once()
+			},
+			want: 2,
+		},
+		"empty filter": {
+			filter:    domain.ContactFilter{},
+			mockSetup: func(m *MockContactsRepository) {},
+			wantErr:   domain.ErrEmptyContactFilter,
+		},
+		"too many ids": {
+			filter:    domain.ContactFilter{IDs: []int{1, 2, 3}},
+			mockSetup: func(m *MockContactsRepository) {},
+			wantErr:   domain.ErrTooManyContacts,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := new(MockContactsRepository)
+			tc.mockSetup(m)
+			svc := service.NewContactsService(m, new(MockUserRepository), noContactAttributes(), 50, 100, 2)
+
+			n, err := svc.DeleteContacts(context.Background(), 123, tc.filter)
+
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, n)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestContactsService_DeleteAllContacts(t *testing.T) {
+	m := new(MockContactsRepository)
+	m.
+		On("DeleteContacts", mock.Anything, 123, domain.ContactFilter{}).
+		Return(5, nil).
+		Once()
+	svc := service.NewContactsService(m, new(MockUserRepository), noContactAttributes(), 50, 100, 1000)
+
+	n, err := svc.DeleteAllContacts(context.Background(), 123)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, n)
+	m.AssertExpectations(t)
+}
+
+func TestContactsService_SetContactsGroup(t *testing.T) {
+	tests := map[string]struct {
+		filter    domain.ContactFilter
+		group     string
+		mockSetup func(m *MockContactsRepository)
+		want      int
+		wantErr   error
+	}{
+		"assign": {
+			filter: domain.ContactFilter{Search: "ali"},
+			group:  "Staff",
+			mockSetup: func(m *MockContactsRepository) {
+				m.
+					On("SetContactsGroup", mock.Anything, 123, domain.ContactFilter{Search: "ali"}, "Staff").
+					Return(3, nil).
+					Once()
+			},
+			want: 3,
+		},
+		"empty filter": {
+			group:     "Staff",
+			mockSetup: func(m *MockContactsRepository) {},
+			wantErr:   domain.ErrEmptyContactFilter,
+		},
+		"group too long": {
+			filter:    domain.ContactFilter{IDs: []int{1}},
+			group:     strings.Repeat("x", 1025),
+			mockSetup: func(m *MockContactsRepository) {},
+			wantErr:   domain.ErrInvalidContactGroup,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := new(MockContactsRepository)
+			tc.mockSetup(m)
+			svc := service.NewContactsService(m, new(MockUserRepository), noContactAttributes(), 50, 100, 1000)
+
+			n, err := svc.SetContactsGroup(context.Background(), 123, tc.filter, tc.group)
+
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, n)
+			m.AssertExpectations(t)
+		})
+	}
+}
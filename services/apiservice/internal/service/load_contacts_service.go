@@ -3,60 +3,144 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"time"
+	"unicode/utf8"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/phoneutils"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/segmentio/kafka-go"
 )
 
-// LoadContactsService uploads contact files to S3 and enqueues a processing task.
+// LoadContactsService uploads contact files to S3, records an import job and enqueues a processing task.
 type LoadContactsService struct {
+	repository  domain.ContactImportRepository
+	users       domain.UserRepository
 	s3Client    domain.S3Client
 	bucket      string
 	kafkaWriter domain.KafkaWriter
 }
 
 // NewLoadContactsService constructs a LoadContactsService.
-func NewLoadContactsService(s3Client domain.S3Client, bucket string, kafkaWriter domain.KafkaWriter) *LoadContactsService {
+func NewLoadContactsService(r domain.ContactImportRepository, ur domain.UserRepository, s3Client domain.S3Client, bucket string, kafkaWriter domain.KafkaWriter) *LoadContactsService {
 	return &LoadContactsService{
+		repository:  r,
+		users:       ur,
 		s3Client:    s3Client,
 		bucket:      bucket,
 		kafkaWriter: kafkaWriter,
 	}
 }
 
-// ProcessUpload streams the payload to S3, generates a unique storage key, and publishes a
-// LoadContactsTask message to Kafka.
-func (lcs *LoadContactsService) ProcessUpload(ctx context.Context, userID int, filename string, payload io.ReadSeeker) error {
+// ProcessUpload streams the payload to S3 under a unique storage key, creates a queued
+// import job and publishes a LoadContactsTask message, carrying the column mapping, phone region, import mode and
+// sheet selection, to Kafka. The region defaults to the user's one and the mode to insert-only; all options are
+// validated before anything is stored. It returns the created job, whose progress can be followed while the contacts worker processes the file.
+func (lcs *LoadContactsService) ProcessUpload(ctx context.Context, userID int, filename string, opts domain.ImportOptions, payload io.ReadSeeker) (*models.ContactImport, error) {
+	mode := opts.Mode
+	switch mode {
+	case "":
+		mode = models.ImportModeInsertOnly
+	case models.ImportModeInsertOnly, models.ImportModeUpsert, models.ImportModeSync:
+	default:
+		return nil, domain.ErrInvalidImportMode
+	}
+
+	if !isValidSheetSelection(opts.Sheets) {
+		return nil, domain.ErrInvalidSheetSelection
+	}
+
+	region, err := lcs.importRegion(ctx, userID, opts.Region)
+	if err != nil {
+		return nil, err
+	}
+
 	key := fmt.Sprintf("contacts/%d_%s", time.Now().UnixNano(), filename)
 
-	_, err := lcs.s3Client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+	_, err = lcs.s3Client.PutObjectWithContext(ctx, &s3.PutObjectInput{
 		Bucket: aws.String(lcs.bucket),
 		Key:    aws.String(key),
 		Body:   payload,
 	})
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	contactImport, err := lcs.repository.CreateContactImport(ctx, &models.ContactImport{
+		UserID:   userID,
+		Filename: filename,
+		S3Key:    key,
+		Mode:     mode,
+		DryRun:   opts.DryRun,
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	jsonTask, err := json.Marshal(&domain.LoadContactsTask{
-		UserID: userID,
-		S3Key:  key,
+		ImportID: contactImport.ID,
+		UserID:   userID,
+		S3Key:    key,
+		Mapping:  opts.Mapping,
+		Region:   string(region),
+		Mode:     mode,
+		DryRun:   opts.DryRun,
+		Sheets:   opts.Sheets,
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	err = lcs.kafkaWriter.WriteMessages(ctx, kafka.Message{
 		Value: jsonTask,
 	})
 	if err != nil {
-		return err
+		// the job would otherwise stay queued forever
+		ferr := lcs.repository.FailContactImport(context.WithoutCancel(ctx), contactImport.ID, "failed to enqueue import")
+		return nil, errors.Join(err, ferr)
 	}
 
-	return nil
+	return contactImport, nil
+}
+
+// importRegion returns the validated region override, or the user's default region when there is none.
+func (lcs *LoadContactsService) importRegion(ctx context.Context, userID int, override string) (phoneutils.ISO3166Alpha2, error) {
+	if override != "" {
+		region, ok := phoneutils.ParseRegion(override)
+		if !ok {
+			return "", domain.ErrInvalidPhoneRegion
+		}
+		return region, nil
+	}
+
+	user, err := lcs.users.GetUserByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	return phoneutils.ISO3166Alpha2(user.DefaultRegion), nil
+}
+
+// maxSheetNameLen is the longest sheet name Excel allows.
+const maxSheetNameLen = 31
+
+// isValidSheetSelection checks the sheet names and the groups they are mapped to,
+// which become contact attributes.
+func isValidSheetSelection(sheets domain.SheetSelection) bool {
+	for _, name := range sheets.Names {
+		if name == "" || utf8.RuneCountInString(name) > maxSheetNameLen {
+			return false
+		}
+	}
+	for name, group := range sheets.Groups {
+		if name == "" || utf8.RuneCountInString(name) > maxSheetNameLen || group == "" || len(group) > maxContactAttributeLen {
+			return false
+		}
+	}
+	return true
 }
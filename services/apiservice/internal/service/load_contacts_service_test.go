@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3"
@@ -22,15 +23,24 @@ func TestLoadContactsService_ProcessUpload(t *testing.T) {
 	userID := 123
 	s3Err := errors.New("s3 failure")
 	kafkaErr := errors.New("kafka failure")
+	repoErr := errors.New("db failure")
+	mapping := domain.ColumnMapping{Name: "Full Name", Phone: "3"}
+	user := &models.User{ID: userID, DefaultRegion: "RU"}
+	queued := &models.ContactImport{ID: 5, UserID: userID, Filename: filename, Status: models.ImportStatusQueued}
 
 	tests := []struct {
 		name      string
-		mockSetup func(ms3 *MockS3Client, mKafka *MockKafkaWriter, capturedKey *string)
+		region    string
+		mode      models.ImportMode
+		dryRun    bool
+		sheets    domain.SheetSelection
+		mockSetup func(ms3 *MockS3Client, mRepo *MockContactImportRepository, mKafka *MockKafkaWriter, capturedKey *string)
 		wantErr   error
+		wantID    int
 	}{
 		{
 			name: "success",
-			mockSetup: func(s3c *MockS3Client, kw *MockKafkaWriter, capturedKey *string) {
+			mockSetup: func(s3c *MockS3Client, repo *MockContactImportRepository, kw *MockKafkaWriter, capturedKey *string) {
 				s3c.
 					On("PutObjectWithContext", mock.Anything, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
 						key := aws.StringValue(input.Key)
@@ -46,6 +56,13 @@ func TestLoadContactsService_ProcessUpload(t *testing.T) {
 					})).
 					Return(&s3.PutObjectOutput{}, nil).
 					Once()
+				repo.
+					On("CreateContactImport", mock.Anything, mock.MatchedBy(func(ci *models.ContactImport) bool {
+						return ci.UserID == userID && ci.Filename == filename && ci.S3Key == *capturedKey &&
+							ci.Mode == models.ImportModeInsertOnly && !ci.DryRun
+					})).
+					Return(queued, nil).
+					Once()
 				kw.
 					On("WriteMessages", mock.Anything, mock.MatchedBy(func(msgs []kafka.Message) bool {
 						if len(msgs) != 1 {
@@ -54,16 +71,128 @@ func TestLoadContactsService_ProcessUpload(t *testing.T) {
 						// Unmarshal JSON
 						var task domain.LoadContactsTask
 						err := json.Unmarshal(msgs[0].Value, &task)
-						return err == nil && task.UserID == userID && task.S3Key == *capturedKey
+						return err == nil && task.ImportID == queued.ID && task.Mapping == mapping && task.Region == "RU" &&
+							task.UserID == userID && task.S3Key == *capturedKey && task.Mode == models.ImportModeInsertOnly
+					})).
+					Return(nil).
+					Once()
+			},
+			wantErr: nil,
+			wantID:  queued.ID,
+		},
+		{
+			name:   "region override",
+			region: "de",
+			mockSetup: func(s3c *MockS3Client, repo *MockContactImportRepository, kw *MockKafkaWriter, _ *string) {
+				s3c.
+					On("PutObjectWithContext", mock.Anything, mock.Anything).
+					Return(&s3.PutObjectOutput{}, nil).
+					Once()
+				repo.
+					On("CreateContactImport", mock.Anything, mock.Anything).
+					Return(queued, nil).
+					Once()
+				kw.
+					On("WriteMessages", mock.Anything, mock.MatchedBy(func(msgs []kafka.Message) bool {
+						var task domain.LoadContactsTask
+						err := json.Unmarshal(msgs[0].Value, &task)
+						return err == nil && task.Region == "DE"
+					})).
+					Return(nil).
+					Once()
+			},
+			wantErr: nil,
+			wantID:  queued.ID,
+		},
+		{
+			name:   "sync dry run",
+			mode:   models.ImportModeSync,
+			dryRun: true,
+			mockSetup: func(s3c *MockS3Client, repo *MockContactImportRepository, kw *MockKafkaWriter, _ *string) {
+				s3c.
+					On("PutObjectWithContext", mock.Anything, mock.Anything).
+					Return(&s3.PutObjectOutput{}, nil).
+					Once()
+				repo.
+					On("CreateContactImport", mock.Anything, mock.MatchedBy(func(ci *models.ContactImport) bool {
+						return ci.Mode == models.ImportModeSync && ci.DryRun
+					})).
+					Return(queued, nil).
+					Once()
+				kw.
+					On("WriteMessages", mock.Anything, mock.MatchedBy(func(msgs []kafka.Message) bool {
+						var task domain.LoadContactsTask
+						err := json.Unmarshal(msgs[0].Value, &task)
+						return err == nil && task.Mode == models.ImportModeSync && task.DryRun
+					})).
+					Return(nil).
+					Once()
+			},
+			wantErr: nil,
+			wantID:  queued.ID,
+		},
+		{
+			name:   "sheet selection",
+			sheets: domain.SheetSelection{Names: []string{"IT", "Sales"}, Groups: map[string]string{"Sales": "Sales team"}},
+			mockSetup: func(s3c *MockS3Client, repo *MockContactImportRepository, kw *MockKafkaWriter, _ *string) {
+				s3c.
+					On("PutObjectWithContext", mock.Anything, mock.Anything).
+					Return(&s3.PutObjectOutput{}, nil).
+					Once()
+				repo.
+					On("CreateContactImport", mock.Anything, mock.Anything).
+					Return(queued, nil).
+					Once()
+				kw.
+					On("WriteMessages", mock.Anything, mock.MatchedBy(func(msgs []kafka.Message) bool {
+						var task domain.LoadContactsTask
+						err := json.Unmarshal(msgs[0].Value, &task)
+						return err == nil && assert.ObjectsAreEqual(domain.SheetSelection{
+							Names:  []string{"IT", "Sales"},
+							Groups: map[string]string{"Sales": "Sales team"},
+						}, task.Sheets)
 					})).
 					Return(nil).
 					Once()
 			},
 			wantErr: nil,
+			wantID:  queued.ID,
+		},
+		{
+			name:   "sheet name too long",
+			sheets: domain.SheetSelection{Names: []string{strings.Repeat("x", 32)}},
+			mockSetup: func(s3c *MockS3Client, repo *MockContactImportRepository, kw *MockKafkaWriter, _ *string) {
+				// nothing is stored for an upload that can't be processed
+			},
+			wantErr: domain.ErrInvalidSheetSelection,
+		},
+		{
+			name:   "empty group",
+			sheets: domain.SheetSelection{All: true, Groups: map[string]string{"Sales": ""}},
+			mockSetup: func(s3c *MockS3Client, repo *MockContactImportRepository, kw *MockKafkaWriter, _ *string) {
+				// nothing is stored for an upload that can't be processed
+			},
+			wantErr: domain.ErrInvalidSheetSelection,
+		},
+		{
+			name: "invalid mode",
+			mode: "replace",
+			mockSetup: func(s3c *MockS3Client, repo *MockContactImportRepository, kw *MockKafkaWriter, _ *string) {
+				// nothing is stored for an upload that can't be processed
+			},
+			wantErr: domain.ErrInvalidImportMode,
+		},
+		{
+			name:   "invalid region override",
+			region: "XX",
+			mockSetup: func(s3c *MockS3Client, repo *MockContactImportRepository, kw *MockKafkaWriter, _ *string) {
+				// nothing is stored for an upload that can't be processed
+			},
+			wantErr: domain.ErrInvalidPhoneRegion,
 		},
 		{
 			name: "s3 error",
-			mockSetup: func(s3c *MockS3Client, kw *MockKafkaWriter, _ *string) {
+			mockSetup: func(s3c *MockS3Client, repo *MockContactImportRepository, kw *MockKafkaWriter, _ *string) {
 				s3c.
 					On("PutObjectWithContext", mock.Anything, mock.Anything).
 					Return((*s3.PutObjectOutput)(nil), s3Err).
@@ -73,17 +202,40 @@ func TestLoadContactsService_ProcessUpload(t *testing.T) {
 			wantErr: s3Err,
 		},
 		{
-			name: "kafka error",
-			mockSetup: func(s3c *MockS3Client, kw *MockKafkaWriter, capturedKey *string) {
+			name: "repository error",
+			mockSetup: func(s3c *MockS3Client, repo *MockContactImportRepository, kw *MockKafkaWriter, _ *string) {
+				s3c.
+					On("PutObjectWithContext", mock.Anything, mock.Anything).
+					Return(&s3.PutObjectOutput{}, nil).
+					Once()
+				repo.
+					On("CreateContactImport", mock.Anything, mock.Anything).
+					Return((*models.ContactImport)(nil), repoErr).
+					Once()
+				// kafka writer should not be called
+			},
+			wantErr: repoErr,
+		},
+		{
+			name: "kafka error marks import failed",
+			mockSetup: func(s3c *MockS3Client, repo *MockContactImportRepository, kw *MockKafkaWriter, capturedKey *string) {
 				s3c.
 					On("PutObjectWithContext", mock.Anything, mock.Anything).
 					Return(&s3.PutObjectOutput{}, nil).
 					Once()
 				*capturedKey = "dummy"
+				repo.
+					On("CreateContactImport", mock.Anything, mock.Anything).
+					Return(queued, nil).
+					Once()
 				kw.
 					On("WriteMessages", mock.Anything, mock.Anything).
 					Return(kafkaErr).
 					Once()
+				repo.
+					On("FailContactImport", mock.Anything, queued.ID, mock.Anything).
+					Return(nil).
+					Once()
 			},
 			wantErr: kafkaErr,
 		},
@@ -92,22 +244,30 @@ func TestLoadContactsService_ProcessUpload(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			s3Mock := new(MockS3Client)
+			repoMock := new(MockContactImportRepository)
 			kafkaMock := new(MockKafkaWriter)
 			var capturedKey string
-			tc.mockSetup(s3Mock, kafkaMock, &capturedKey)
+			tc.mockSetup(s3Mock, repoMock, kafkaMock, &capturedKey)
+
+			userMock := new(MockUserRepository)
+			userMock.On("GetUserByID", mock.Anything, userID).Return(user, nil).Maybe()
 
-			svc := service.NewLoadContactsService(s3Mock, bucket, kafkaMock)
+			svc := service.NewLoadContactsService(repoMock, userMock, s3Mock, bucket, kafkaMock)
 			// provide a simple payload
 			payload := strings.NewReader("data")
-			err := svc.ProcessUpload(context.Background(), userID, filename, payload)
+			opts := domain.ImportOptions{Mapping: mapping, Region: tc.region, Mode: tc.mode, DryRun: tc.dryRun, Sheets: tc.sheets}
+			ci, err := svc.ProcessUpload(context.Background(), userID, filename, opts, payload)
 			if tc.wantErr != nil {
 				assert.Error(t, err)
 				assert.Contains(t, err.Error(), tc.wantErr.Error())
+				assert.Nil(t, ci)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, tc.wantID, ci.ID)
 			}
 
 			s3Mock.AssertExpectations(t)
+			repoMock.AssertExpectations(t)
 			kafkaMock.AssertExpectations(t)
 		})
 	}
@@ -2,11 +2,15 @@ package service_test
 
 import (
 	"context"
+	"time"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/bootstrap"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/cap"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/segmentio/kafka-go"
@@ -51,8 +55,8 @@ type MockContactsRepository struct {
 	mock.Mock
 }
 
-func (m *MockContactsRepository) GetContactsCountByUserID(ctx context.Context, userID int) (int, error) {
-	args := m.Called(ctx, userID)
+func (m *MockContactsRepository) GetContactsCountByUserID(ctx context.Context, userID int, filter domain.ContactFilter) (int, error) {
+	args := m.Called(ctx, userID, filter)
 	return args.Get(0).(int), args.Error(1)
 }
 
@@ -61,9 +65,15 @@ func (m *MockContactsRepository) GetAllContactsByUserID(ctx context.Context, use
 	return args.Get(0).([]*models.Contact), args.Error(1)
 }
 
-func (m *MockContactsRepository) GetContactsPageByUserID(ctx context.Context, userID, limit, offset int) ([]*models.Contact, error) {
-	args := m.Called(ctx, userID, limit, offset)
-	return args.Get(0).([]*models.Contact), args.Error(1)
+func (m *MockContactsRepository) GetContactsPageByUserID(ctx context.Context, userID int, filter domain.ContactFilter, page domain.PageRequest) ([]*models.Contact, string, error) {
+	args := m.Called(ctx, userID, filter, page)
+	return args.Get(0).([]*models.Contact), args.String(1), args.Error(2)
+}
+
+func (m *MockContactsRepository) GetTakenContactAddresses(ctx context.Context, userID, exceptContactID int, addresses []*models.ContactEndpoint) ([]*models.ContactEndpoint, error) {
+	args := m.Called(ctx, userID, exceptContactID, addresses)
+	taken, _ := args.Get(0).([]*models.ContactEndpoint)
+	return taken, args.Error(1)
 }
 
 func (m *MockContactsRepository) GetContactByID(ctx context.Context, userID, contactID int) (*models.Contact, error) {
@@ -85,10 +95,73 @@ func (m *MockContactsRepository) DeleteContact(ctx context.Context, userID, cont
 	return m.Called(ctx, userID, contactID).Error(0)
 }
 
+func (m *MockContactsRepository) CreateContacts(ctx context.Context, userID int, contacts []*models.Contact) ([]*models.Contact, error) {
+	args := m.Called(ctx, userID, contacts)
+	created, _ := args.Get(0).([]*models.Contact)
+	return created, args.Error(1)
+}
+
+func (m *MockContactsRepository) DeleteContacts(ctx context.Context, userID int, filter domain.ContactFilter) (int, error) {
+	args := m.Called(ctx, userID, filter)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockContactsRepository) SetContactsGroup(ctx context.Context, userID int, filter domain.ContactFilter, group string) (int, error) {
+	args := m.Called(ctx, userID, filter, group)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockContactsRepository) GetContactAttributeNames(ctx context.Context, userID int, filter domain.ContactFilter) ([]string, error) {
+	args := m.Called(ctx, userID, filter)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// StreamContacts passes the contacts given to Return to fn one by one.
+func (m *MockContactsRepository) StreamContacts(ctx context.Context, userID int, filter domain.ContactFilter, fn func(*models.Contact) error) error {
+	args := m.Called(ctx, userID, filter)
+	for _, c := range args.Get(0).([]*models.Contact) {
+		if err := fn(c); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+type MockContactAttributeRepository struct {
+	mock.Mock
+}
+
+func (m *MockContactAttributeRepository) GetContactAttributes(ctx context.Context, userID int) ([]*models.ContactAttribute, error) {
+	args := m.Called(ctx, userID)
+	attrs, _ := args.Get(0).([]*models.ContactAttribute)
+	return attrs, args.Error(1)
+}
+
+func (m *MockContactAttributeRepository) CreateContactAttribute(ctx context.Context, attr *models.ContactAttribute) (*models.ContactAttribute, error) {
+	args := m.Called(ctx, attr)
+	created, _ := args.Get(0).(*models.ContactAttribute)
+	return created, args.Error(1)
+}
+
+func (m *MockContactAttributeRepository) UpdateContactAttribute(ctx context.Context, userID, attrID int, attr *models.ContactAttribute) (*models.ContactAttribute, error) {
+	args := m.Called(ctx, userID, attrID, attr)
+	updated, _ := args.Get(0).(*models.ContactAttribute)
+	return updated, args.Error(1)
+}
+
+func (m *MockContactAttributeRepository) DeleteContactAttribute(ctx context.Context, userID, attrID int) error {
+	return m.Called(ctx, userID, attrID).Error(0)
+}
+
 type MockS3Client struct {
 	mock.Mock
 }
 
+func (m *MockS3Client) GetObjectWithContext(ctx context.Context, input *s3.GetObjectInput, opts ...request.Option) (*s3.GetObjectOutput, error) {
+	args := m.Called(ctx, input)
+	return args.Get(0).(*s3.GetObjectOutput), args.Error(1)
+}
+
 func (m *MockS3Client) PutObjectWithContext(ctx context.Context, input *s3.PutObjectInput, opts ...request.Option) (*s3.PutObjectOutput, error) {
 	args := m.Called(ctx, input)
 	return args.Get(0).(*s3.PutObjectOutput), args.Error(1)
@@ -122,18 +195,23 @@ func (m *MockUserRepository) GetUserByID(ctx context.Context, id int) (*models.U
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
+func (m *MockUserRepository) UpdateUserDefaultRegion(ctx context.Context, id int, region string) (*models.User, error) {
+	args := m.Called(ctx, id, region)
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
 type MockTemplateRepository struct {
 	mock.Mock
 }
 
-func (m *MockTemplateRepository) GetTemplatesCountByUserID(ctx context.Context, userID int) (int, error) {
-	args := m.Called(ctx, userID)
+func (m *MockTemplateRepository) GetTemplatesCountByUserID(ctx context.Context, userID int, filter domain.TemplateFilter) (int, error) {
+	args := m.Called(ctx, userID, filter)
 	return args.Get(0).(int), args.Error(1)
 }
 
-func (m *MockTemplateRepository) GetTemplatesPageByUserID(ctx context.Context, userID, limit, offset int) ([]*models.Template, error) {
-	args := m.Called(ctx, userID, limit, offset)
-	return args.Get(0).([]*models.Template), args.Error(1)
+func (m *MockTemplateRepository) GetTemplatesPageByUserID(ctx context.Context, userID int, filter domain.TemplateFilter, page domain.PageRequest) ([]*models.Template, string, error) {
+	args := m.Called(ctx, userID, filter, page)
+	return args.Get(0).([]*models.Template), args.String(1), args.Error(2)
 }
 
 func (m *MockTemplateRepository) GetTemplateByID(ctx context.Context, userID, tmplID int) (*models.Template, error) {
@@ -154,3 +232,409 @@ func (m *MockTemplateRepository) UpdateTemplate(ctx context.Context, userID, tmp
 func (m *MockTemplateRepository) DeleteTemplate(ctx context.Context, userID, tmplID int) error {
 	return m.Called(ctx, userID, tmplID).Error(0)
 }
+
+func (m *MockTemplateRepository) GetTemplateVersions(ctx context.Context, userID, tmplID int) ([]*models.TemplateVersion, error) {
+	args := m.Called(ctx, userID, tmplID)
+	versions, _ := args.Get(0).([]*models.TemplateVersion)
+	return versions, args.Error(1)
+}
+
+func (m *MockTemplateRepository) GetTemplateVersion(ctx context.Context, userID, tmplID, version int) (*models.TemplateVersion, error) {
+	args := m.Called(ctx, userID, tmplID, version)
+	v, _ := args.Get(0).(*models.TemplateVersion)
+	return v, args.Error(1)
+}
+
+type MockRetryPolicyRepository struct {
+	mock.Mock
+}
+
+func (m *MockRetryPolicyRepository) GetRetryPolicyByUserID(ctx context.Context, userID int) (*models.RetryPolicy, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(*models.RetryPolicy), args.Error(1)
+}
+
+func (m *MockRetryPolicyRepository) UpsertRetryPolicy(ctx context.Context, userID int, policy *models.RetryPolicy) (*models.RetryPolicy, error) {
+	args := m.Called(ctx, userID, policy)
+	return args.Get(0).(*models.RetryPolicy), args.Error(1)
+}
+
+type MockQuietHoursRepository struct {
+	mock.Mock
+}
+
+func (m *MockQuietHoursRepository) GetQuietHoursByUserID(ctx context.Context, userID int) (*models.QuietHours, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(*models.QuietHours), args.Error(1)
+}
+
+func (m *MockQuietHoursRepository) UpsertQuietHours(ctx context.Context, userID int, qh *models.QuietHours) (*models.QuietHours, error) {
+	args := m.Called(ctx, userID, qh)
+	return args.Get(0).(*models.QuietHours), args.Error(1)
+}
+
+func (m *MockQuietHoursRepository) DeleteQuietHours(ctx context.Context, userID int) error {
+	return m.Called(ctx, userID).Error(0)
+}
+
+type MockContactImportRepository struct {
+	mock.Mock
+}
+
+func (m *MockContactImportRepository) CreateContactImport(ctx context.Context, contactImport *models.ContactImport) (*models.ContactImport, error) {
+	args := m.Called(ctx, contactImport)
+	return args.Get(0).(*models.ContactImport), args.Error(1)
+}
+
+func (m *MockContactImportRepository) GetContactImportByID(ctx context.Context, userID, importID int) (*models.ContactImport, error) {
+	args := m.Called(ctx, userID, importID)
+	return args.Get(0).(*models.ContactImport), args.Error(1)
+}
+
+func (m *MockContactImportRepository) FailContactImport(ctx context.Context, importID int, reason string) error {
+	return m.Called(ctx, importID, reason).Error(0)
+}
+
+type MockContactExportRepository struct {
+	mock.Mock
+}
+
+func (m *MockContactExportRepository) CreateContactExport(ctx context.Context, export *models.ContactExport) (*models.ContactExport, error) {
+	args := m.Called(ctx, export)
+	return args.Get(0).(*models.ContactExport), args.Error(1)
+}
+
+func (m *MockContactExportRepository) GetContactExportByID(ctx context.Context, userID, exportID int) (*models.ContactExport, error) {
+	args := m.Called(ctx, userID, exportID)
+	return args.Get(0).(*models.ContactExport), args.Error(1)
+}
+
+func (m *MockContactExportRepository) StartContactExport(ctx context.Context, exportID int) error {
+	return m.Called(ctx, exportID).Error(0)
+}
+
+func (m *MockContactExportRepository) CompleteContactExport(ctx context.Context, exportID, rowsWritten int, s3Key string) error {
+	return m.Called(ctx, exportID, rowsWritten, s3Key).Error(0)
+}
+
+func (m *MockContactExportRepository) FailContactExport(ctx context.Context, exportID int, reason string) error {
+	return m.Called(ctx, exportID, reason).Error(0)
+}
+
+type MockSegmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockSegmentRepository) GetSegmentsByUserID(ctx context.Context, userID int) ([]*models.Segment, error) {
+	args := m.Called(ctx, userID)
+	segments, _ := args.Get(0).([]*models.Segment)
+	return segments, args.Error(1)
+}
+
+func (m *MockSegmentRepository) GetSegmentByID(ctx context.Context, userID, segmentID int) (*models.Segment, error) {
+	args := m.Called(ctx, userID, segmentID)
+	segment, _ := args.Get(0).(*models.Segment)
+	return segment, args.Error(1)
+}
+
+func (m *MockSegmentRepository) CreateSegment(ctx context.Context, segment *models.Segment) (*models.Segment, error) {
+	args := m.Called(ctx, segment)
+	created, _ := args.Get(0).(*models.Segment)
+	return created, args.Error(1)
+}
+
+func (m *MockSegmentRepository) UpdateSegment(ctx context.Context, userID, segmentID int, segment *models.Segment) (*models.Segment, error) {
+	args := m.Called(ctx, userID, segmentID, segment)
+	updated, _ := args.Get(0).(*models.Segment)
+	return updated, args.Error(1)
+}
+
+func (m *MockSegmentRepository) DeleteSegment(ctx context.Context, userID, segmentID int) error {
+	args := m.Called(ctx, userID, segmentID)
+	return args.Error(0)
+}
+
+type MockZoneRepository struct {
+	mock.Mock
+}
+
+func (m *MockZoneRepository) GetZonesByUserID(ctx context.Context, userID int) ([]*models.Zone, error) {
+	args := m.Called(ctx, userID)
+	zones, _ := args.Get(0).([]*models.Zone)
+	return zones, args.Error(1)
+}
+
+func (m *MockZoneRepository) GetZoneByID(ctx context.Context, userID, zoneID int) (*models.Zone, error) {
+	args := m.Called(ctx, userID, zoneID)
+	zone, _ := args.Get(0).(*models.Zone)
+	return zone, args.Error(1)
+}
+
+func (m *MockZoneRepository) CreateZone(ctx context.Context, zone *models.Zone) (*models.Zone, error) {
+	args := m.Called(ctx, zone)
+	created, _ := args.Get(0).(*models.Zone)
+	return created, args.Error(1)
+}
+
+func (m *MockZoneRepository) UpdateZone(ctx context.Context, userID, zoneID int, zone *models.Zone) (*models.Zone, error) {
+	args := m.Called(ctx, userID, zoneID, zone)
+	updated, _ := args.Get(0).(*models.Zone)
+	return updated, args.Error(1)
+}
+
+func (m *MockZoneRepository) DeleteZone(ctx context.Context, userID, zoneID int) error {
+	args := m.Called(ctx, userID, zoneID)
+	return args.Error(0)
+}
+
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) GetAPIKeysByUserID(ctx context.Context, userID int) ([]*models.APIKey, error) {
+	args := m.Called(ctx, userID)
+	keys, _ := args.Get(0).([]*models.APIKey)
+	return keys, args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) CreateAPIKey(ctx context.Context, key *models.APIKey) (*models.APIKey, error) {
+	args := m.Called(ctx, key)
+	created, _ := args.Get(0).(*models.APIKey)
+	return created, args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) DeleteAPIKey(ctx context.Context, userID, keyID int) error {
+	args := m.Called(ctx, userID, keyID)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) UseAPIKey(ctx context.Context, hash []byte) (*models.APIKey, error) {
+	args := m.Called(ctx, hash)
+	key, _ := args.Get(0).(*models.APIKey)
+	return key, args.Error(1)
+}
+
+type MockCAPAlertRepository struct {
+	mock.Mock
+}
+
+func (m *MockCAPAlertRepository) CreateCAPAlert(ctx context.Context, alert *models.CAPAlert) (*models.CAPAlert, error) {
+	args := m.Called(ctx, alert)
+	created, _ := args.Get(0).(*models.CAPAlert)
+	return created, args.Error(1)
+}
+
+func (m *MockCAPAlertRepository) DeleteCAPAlert(ctx context.Context, userID, alertID int) error {
+	args := m.Called(ctx, userID, alertID)
+	return args.Error(0)
+}
+
+func (m *MockCAPAlertRepository) GetCAPAlertsByReferences(ctx context.Context, userID int, refs []cap.Reference) ([]*models.CAPAlert, error) {
+	args := m.Called(ctx, userID, refs)
+	alerts, _ := args.Get(0).([]*models.CAPAlert)
+	return alerts, args.Error(1)
+}
+
+func (m *MockCAPAlertRepository) CancelCAPAlerts(ctx context.Context, userID int, alertIDs []int) error {
+	args := m.Called(ctx, userID, alertIDs)
+	return args.Error(0)
+}
+
+type MockSendNotificationService struct {
+	mock.Mock
+}
+
+func (m *MockSendNotificationService) SendNotification(ctx context.Context, userID, templateID int, opts *domain.SendNotificationRequest) error {
+	return m.Called(ctx, userID, templateID, opts).Error(0)
+}
+
+func (m *MockSendNotificationService) SendMessage(ctx context.Context, userID int, campaignID uuid.UUID, text string, opts *domain.SendNotificationRequest) error {
+	return m.Called(ctx, userID, campaignID, text, opts).Error(0)
+}
+
+func (m *MockSendNotificationService) CountRecipients(ctx context.Context, userID int, opts *domain.SendNotificationRequest) (int, error) {
+	args := m.Called(ctx, userID, opts)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockSendNotificationService) PreviewNotification(ctx context.Context, userID, templateID int, opts *domain.SendNotificationRequest) (*domain.CampaignPreview, error) {
+	args := m.Called(ctx, userID, templateID, opts)
+	preview, _ := args.Get(0).(*domain.CampaignPreview)
+	return preview, args.Error(1)
+}
+
+type MockTriggerRepository struct {
+	mock.Mock
+}
+
+func (m *MockTriggerRepository) GetTriggersByUserID(ctx context.Context, userID int) ([]*models.Trigger, error) {
+	args := m.Called(ctx, userID)
+	triggers, _ := args.Get(0).([]*models.Trigger)
+	return triggers, args.Error(1)
+}
+
+func (m *MockTriggerRepository) GetTriggerByID(ctx context.Context, userID, triggerID int) (*models.Trigger, error) {
+	args := m.Called(ctx, userID, triggerID)
+	trigger, _ := args.Get(0).(*models.Trigger)
+	return trigger, args.Error(1)
+}
+
+func (m *MockTriggerRepository) CreateTrigger(ctx context.Context, trigger *models.Trigger) (*models.Trigger, error) {
+	args := m.Called(ctx, trigger)
+	created, _ := args.Get(0).(*models.Trigger)
+	return created, args.Error(1)
+}
+
+func (m *MockTriggerRepository) UpdateTrigger(ctx context.Context, userID, triggerID int, trigger *models.Trigger) (*models.Trigger, error) {
+	args := m.Called(ctx, userID, triggerID, trigger)
+	updated, _ := args.Get(0).(*models.Trigger)
+	return updated, args.Error(1)
+}
+
+func (m *MockTriggerRepository) DeleteTrigger(ctx context.Context, userID, triggerID int) error {
+	args := m.Called(ctx, userID, triggerID)
+	return args.Error(0)
+}
+
+func (m *MockTriggerRepository) StartFiring(ctx context.Context, triggerID int, fingerprint string, startsAt *time.Time, repeatInterval time.Duration) (bool, error) {
+	args := m.Called(ctx, triggerID, fingerprint, startsAt, repeatInterval)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTriggerRepository) ResolveFiring(ctx context.Context, triggerID int, fingerprint string) (bool, error) {
+	args := m.Called(ctx, triggerID, fingerprint)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockApprovalPolicyRepository struct {
+	mock.Mock
+}
+
+func (m *MockApprovalPolicyRepository) GetApprovalPolicyByUserID(ctx context.Context, userID int) (*models.ApprovalPolicy, error) {
+	args := m.Called(ctx, userID)
+	policy, _ := args.Get(0).(*models.ApprovalPolicy)
+	return policy, args.Error(1)
+}
+
+func (m *MockApprovalPolicyRepository) UpsertApprovalPolicy(ctx context.Context, userID int, p *models.ApprovalPolicy) (*models.ApprovalPolicy, error) {
+	args := m.Called(ctx, userID, p)
+	policy, _ := args.Get(0).(*models.ApprovalPolicy)
+	return policy, args.Error(1)
+}
+
+func (m *MockApprovalPolicyRepository) CreatePolicyChange(ctx context.Context, c *models.ApprovalPolicyChange) (*models.ApprovalPolicyChange, error) {
+	args := m.Called(ctx, c)
+	change, _ := args.Get(0).(*models.ApprovalPolicyChange)
+	return change, args.Error(1)
+}
+
+func (m *MockApprovalPolicyRepository) GetPolicyChangesByUserID(ctx context.Context, userID int) ([]*models.ApprovalPolicyChange, error) {
+	args := m.Called(ctx, userID)
+	changes, _ := args.Get(0).([]*models.ApprovalPolicyChange)
+	return changes, args.Error(1)
+}
+
+func (m *MockApprovalPolicyRepository) GetPolicyChangeByID(ctx context.Context, userID, changeID int) (*models.ApprovalPolicyChange, error) {
+	args := m.Called(ctx, userID, changeID)
+	change, _ := args.Get(0).(*models.ApprovalPolicyChange)
+	return change, args.Error(1)
+}
+
+func (m *MockApprovalPolicyRepository) DecidePolicyChange(ctx context.Context, approverID, changeID int, status models.ApprovalStatus, comment string) (*models.ApprovalPolicyChange, error) {
+	args := m.Called(ctx, approverID, changeID, status, comment)
+	change, _ := args.Get(0).(*models.ApprovalPolicyChange)
+	return change, args.Error(1)
+}
+
+type MockCampaignApprovalRepository struct {
+	mock.Mock
+}
+
+func (m *MockCampaignApprovalRepository) CreateApproval(ctx context.Context, a *models.CampaignApproval) (*models.CampaignApproval, error) {
+	args := m.Called(ctx, a)
+	created, _ := args.Get(0).(*models.CampaignApproval)
+	return created, args.Error(1)
+}
+
+func (m *MockCampaignApprovalRepository) GetApprovalsByUserID(ctx context.Context, userID int) ([]*models.CampaignApproval, error) {
+	args := m.Called(ctx, userID)
+	approvals, _ := args.Get(0).([]*models.CampaignApproval)
+	return approvals, args.Error(1)
+}
+
+func (m *MockCampaignApprovalRepository) GetApprovalByID(ctx context.Context, userID, approvalID int) (*models.CampaignApproval, error) {
+	args := m.Called(ctx, userID, approvalID)
+	approval, _ := args.Get(0).(*models.CampaignApproval)
+	return approval, args.Error(1)
+}
+
+func (m *MockCampaignApprovalRepository) DecideApproval(ctx context.Context, approverID, approvalID int, status models.ApprovalStatus, comment string) (*models.CampaignApproval, error) {
+	args := m.Called(ctx, approverID, approvalID, status, comment)
+	approval, _ := args.Get(0).(*models.CampaignApproval)
+	return approval, args.Error(1)
+}
+
+func (m *MockCampaignApprovalRepository) ExpireApproval(ctx context.Context, approvalID int) error {
+	args := m.Called(ctx, approvalID)
+	return args.Error(0)
+}
+
+func (m *MockCampaignApprovalRepository) FailApproval(ctx context.Context, approvalID int) error {
+	args := m.Called(ctx, approvalID)
+	return args.Error(0)
+}
+
+type MockCampaignApprovalService struct {
+	mock.Mock
+}
+
+func (m *MockCampaignApprovalService) RequestApproval(ctx context.Context, userID, templateID int, opts *domain.SendNotificationRequest) (*models.CampaignApproval, error) {
+	args := m.Called(ctx, userID, templateID, opts)
+	approval, _ := args.Get(0).(*models.CampaignApproval)
+	return approval, args.Error(1)
+}
+
+func (m *MockCampaignApprovalService) RequestMessageApproval(ctx context.Context, userID int, campaignID uuid.UUID, text string, opts *domain.SendNotificationRequest) (*models.CampaignApproval, error) {
+	args := m.Called(ctx, userID, campaignID, text, opts)
+	approval, _ := args.Get(0).(*models.CampaignApproval)
+	return approval, args.Error(1)
+}
+
+func (m *MockCampaignApprovalService) GetApprovals(ctx context.Context, userID int) ([]*models.CampaignApproval, error) {
+	args := m.Called(ctx, userID)
+	approvals, _ := args.Get(0).([]*models.CampaignApproval)
+	return approvals, args.Error(1)
+}
+
+func (m *MockCampaignApprovalService) GetApprovalByID(ctx context.Context, userID, approvalID int) (*models.CampaignApproval, error) {
+	args := m.Called(ctx, userID, approvalID)
+	approval, _ := args.Get(0).(*models.CampaignApproval)
+	return approval, args.Error(1)
+}
+
+func (m *MockCampaignApprovalService) ApproveCampaign(ctx context.Context, userID, approvalID int, comment string) (*models.CampaignApproval, error) {
+	args := m.Called(ctx, userID, approvalID, comment)
+	approval, _ := args.Get(0).(*models.CampaignApproval)
+	return approval, args.Error(1)
+}
+
+func (m *MockCampaignApprovalService) RejectCampaign(ctx context.Context, userID, approvalID int, comment string) (*models.CampaignApproval, error) {
+	args := m.Called(ctx, userID, approvalID, comment)
+	approval, _ := args.Get(0).(*models.CampaignApproval)
+	return approval, args.Error(1)
+}
+
+type MockCampaignRepository struct {
+	mock.Mock
+}
+
+func (m *MockCampaignRepository) CreateCampaign(ctx context.Context, c *models.Campaign) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockCampaignRepository) GetCampaignsByUserID(ctx context.Context, userID int, filter domain.CampaignFilter, limit, offset int) ([]*models.Campaign, error) {
+	args := m.Called(ctx, userID, filter, limit, offset)
+	campaigns, _ := args.Get(0).([]*models.Campaign)
+	return campaigns, args.Error(1)
+}
@@ -0,0 +1,16 @@
+package service
+
+// clampPage applies the default limit to a missing one, caps it at maxLimit and
+// replaces a negative offset with zero.
+func clampPage(limit, offset, defaultLimit, maxLimit int) (int, int) {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
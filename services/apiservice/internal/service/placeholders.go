@@ -0,0 +1,41 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+)
+
+// placeholderPattern matches template placeholders such as {{name}}, {{ Department }} or
+// {{.Name}}; the leading dot is optional.
+var placeholderPattern = regexp.MustCompile(`\{\{\s*\.?([^{}]+?)\s*\}\}`)
+
+// hasPlaceholders reports whether the template body refers to any contact field.
+func hasPlaceholders(body string) bool {
+	return placeholderPattern.MatchString(body)
+}
+
+// renderTemplate substitutes the placeholders of the template body with the contact's fields:
+// {{name}} and {{phone}}, or else the attribute of that name, matched case-insensitively.
+// Placeholders of attributes the contact doesn't have are removed.
+func renderTemplate(body string, c *models.Contact) string {
+	return placeholderPattern.ReplaceAllStringFunc(body, func(m string) string {
+		field := placeholderPattern.FindStringSubmatch(m)[1]
+		switch strings.ToLower(field) {
+		case "name":
+			return c.Name
+		case "phone":
+			return c.Phone
+		}
+		if value, ok := c.Attributes[field]; ok {
+			return value
+		}
+		for name, value := range c.Attributes {
+			if strings.EqualFold(name, field) {
+				return value
+			}
+		}
+		return ""
+	})
+}
@@ -5,9 +5,10 @@ import (
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/phoneutils"
 )
 
-// ProfileService handles retrieval of user profile data.
+// ProfileService handles retrieval and updates of user profile data.
 type ProfileService struct {
 	repository domain.UserRepository
 }
@@ -24,3 +25,14 @@ func NewProfileService(r domain.UserRepository) *ProfileService {
 func (ps *ProfileService) GetUserByID(ctx context.Context, id int) (*models.User, error) {
 	return ps.repository.GetUserByID(ctx, id)
 }
+
+// UpdateDefaultRegion validates the region code and stores it as the region used to parse the
+// user's phone numbers that have no country code. Returns domain.ErrInvalidPhoneRegion for unknown codes.
+func (ps *ProfileService) UpdateDefaultRegion(ctx context.Context, id int, region string) (*models.User, error) {
+	code, ok := phoneutils.ParseRegion(region)
+	if !ok {
+		return nil, domain.ErrInvalidPhoneRegion
+	}
+
+	return ps.repository.UpdateUserDefaultRegion(ctx, id, string(code))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
 	"github.com/stretchr/testify/assert"
@@ -25,3 +26,31 @@ func TestProfileService_GetUserByID(t *testing.T) {
 	assert.Equal(t, user, res)
 	m.AssertExpectations(t)
 }
+
+func TestProfileService_UpdateDefaultRegion(t *testing.T) {
+	t.Run("normalizes region", func(t *testing.T) {
+		user := &models.User{ID: 123, Email: "test@test.com", DefaultRegion: "DE"}
+
+		m := new(MockUserRepository)
+		m.
+			On("UpdateUserDefaultRegion", mock.Anything, 123, "DE").
+			Return(user, nil).
+			Once()
+		svc := service.NewProfileService(m)
+
+		res, err := svc.UpdateDefaultRegion(context.Background(), 123, " de ")
+		assert.NoError(t, err)
+		assert.Equal(t, user, res)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("unknown region", func(t *testing.T) {
+		m := new(MockUserRepository)
+		svc := service.NewProfileService(m)
+
+		res, err := svc.UpdateDefaultRegion(context.Background(), 123, "XX")
+		assert.ErrorIs(t, err, domain.ErrInvalidPhoneRegion)
+		assert.Nil(t, res)
+		m.AssertNotCalled(t, "UpdateUserDefaultRegion", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
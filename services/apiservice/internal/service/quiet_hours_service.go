@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+)
+
+const minutesPerDay = 24 * 60
+
+// QuietHoursService provides operations for managing a user's quiet hours.
+type QuietHoursService struct {
+	repository domain.QuietHoursRepository
+}
+
+// NewQuietHoursService creates a QuietHoursService with the given repository.
+func NewQuietHoursService(r domain.QuietHoursRepository) *QuietHoursService {
+	return &QuietHoursService{
+		repository: r,
+	}
+}
+
+// GetQuietHours returns the quiet hours of the user or domain.ErrQuietHoursNotExists if none are configured.
+func (qhs *QuietHoursService) GetQuietHours(ctx context.Context, userID int) (*models.QuietHours, error) {
+	return qhs.repository.GetQuietHoursByUserID(ctx, userID)
+}
+
+// UpdateQuietHours validates and stores the quiet hours of the user.
+// Returns domain.ErrInvalidQuietHours if the window or time zone is invalid.
+func (qhs *QuietHoursService) UpdateQuietHours(ctx context.Context, userID int, qh *models.QuietHours) (*models.QuietHours, error) {
+	if qh.StartMinute < 0 || qh.StartMinute >= minutesPerDay || qh.EndMinute < 0 || qh.EndMinute >= minutesPerDay {
+		return nil, domain.ErrInvalidQuietHours
+	}
+	if qh.StartMinute == qh.EndMinute {
+		return nil, domain.ErrInvalidQuietHours
+	}
+
+	if !isValidTimeZone(qh.TimeZone) {
+		return nil, domain.ErrInvalidQuietHours
+	}
+
+	return qhs.repository.UpsertQuietHours(ctx, userID, qh)
+}
+
+// DeleteQuietHours turns quiet hours off for the user.
+func (qhs *QuietHoursService) DeleteQuietHours(ctx context.Context, userID int) error {
+	return qhs.repository.DeleteQuietHours(ctx, userID)
+}
+
+func isValidTimeZone(tz string) bool {
+	if tz == "" || tz == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(tz)
+	return err == nil
+}
@@ -0,0 +1,110 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestQuietHoursService_GetQuietHours(t *testing.T) {
+	expected := &models.QuietHours{StartMinute: 22 * 60, EndMinute: 7 * 60, TimeZone: "Europe/Moscow"}
+	m := new(MockQuietHoursRepository)
+	m.
+		On("GetQuietHoursByUserID", mock.Anything, 42).
+		Return(expected, nil).
+		Once()
+
+	svc := service.NewQuietHoursService(m)
+	out, err := svc.GetQuietHours(context.Background(), 42)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, out)
+	m.AssertExpectations(t)
+}
+
+func TestQuietHoursService_UpdateQuietHours(t *testing.T) {
+	tests := map[string]struct {
+		qh        *models.QuietHours
+		repoErr   error
+		expectErr error
+	}{
+		"window over midnight": {
+			qh: &models.QuietHours{StartMinute: 22 * 60, EndMinute: 7 * 60, TimeZone: "Europe/Moscow"},
+		},
+		"window within a day": {
+			qh: &models.QuietHours{StartMinute: 13 * 60, EndMinute: 15 * 60, TimeZone: "UTC"},
+		},
+		"start out of range": {
+			qh:        &models.QuietHours{StartMinute: 24 * 60, EndMinute: 7 * 60, TimeZone: "UTC"},
+			expectErr: domain.ErrInvalidQuietHours,
+		},
+		"negative end": {
+			qh:        &models.QuietHours{StartMinute: 22 * 60, EndMinute: -1, TimeZone: "UTC"},
+			expectErr: domain.ErrInvalidQuietHours,
+		},
+		"empty window": {
+			qh:        &models.QuietHours{StartMinute: 60, EndMinute: 60, TimeZone: "UTC"},
+			expectErr: domain.ErrInvalidQuietHours,
+		},
+		"missing time zone": {
+			qh:        &models.QuietHours{StartMinute: 22 * 60, EndMinute: 7 * 60},
+			expectErr: domain.ErrInvalidQuietHours,
+		},
+		"unknown time zone": {
+			qh:        &models.QuietHours{StartMinute: 22 * 60, EndMinute: 7 * 60, TimeZone: "Mars/Olympus"},
+			expectErr: domain.ErrInvalidQuietHours,
+		},
+		"repository error": {
+			qh:        &models.QuietHours{StartMinute: 22 * 60, EndMinute: 7 * 60, TimeZone: "UTC"},
+			repoErr:   assert.AnError,
+			expectErr: assert.AnError,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := new(MockQuietHoursRepository)
+			if tc.expectErr == nil || tc.repoErr != nil {
+				var stored *models.QuietHours
+				if tc.repoErr == nil {
+					stored = tc.qh
+				}
+				m.
+					On("UpsertQuietHours", mock.Anything, 42, tc.qh).
+					Return(stored, tc.repoErr).
+					Once()
+			}
+
+			svc := service.NewQuietHoursService(m)
+			out, err := svc.UpdateQuietHours(context.Background(), 42, tc.qh)
+
+			if tc.expectErr != nil {
+				assert.ErrorIs(t, err, tc.expectErr)
+				assert.Nil(t, out)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.qh, out)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestQuietHoursService_DeleteQuietHours(t *testing.T) {
+	m := new(MockQuietHoursRepository)
+	m.
+		On("DeleteQuietHours", mock.Anything, 42).
+		Return(domain.ErrQuietHoursNotExists).
+		Once()
+
+	svc := service.NewQuietHoursService(m)
+	err := svc.DeleteQuietHours(context.Background(), 42)
+
+	assert.ErrorIs(t, err, domain.ErrQuietHoursNotExists)
+	m.AssertExpectations(t)
+}
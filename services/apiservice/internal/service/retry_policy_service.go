@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+)
+
+// RetryPolicyService provides operations for managing a user's default retry policy.
+type RetryPolicyService struct {
+	repository    domain.RetryPolicyRepository
+	defaultPolicy models.RetryPolicy
+}
+
+// NewRetryPolicyService creates a RetryPolicyService. defaultPolicy is returned
+// for users that have not stored a policy of their own.
+func NewRetryPolicyService(r domain.RetryPolicyRepository, defaultPolicy models.RetryPolicy) *RetryPolicyService {
+	return &RetryPolicyService{
+		repository:    r,
+		defaultPolicy: defaultPolicy,
+	}
+}
+
+// GetRetryPolicy returns the retry policy stored for the user or the system default if there is none.
+func (rps *RetryPolicyService) GetRetryPolicy(ctx context.Context, userID int) (*models.RetryPolicy, error) {
+	policy, err := rps.repository.GetRetryPolicyByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrRetryPolicyNotExists) {
+			p := rps.defaultPolicy
+			return &p, nil
+		}
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// UpdateRetryPolicy validates and stores the user's default retry policy.
+// Returns domain.ErrInvalidRetryPolicy if the policy is out of the allowed bounds.
+func (rps *RetryPolicyService) UpdateRetryPolicy(ctx context.Context, userID int, policy *models.RetryPolicy) (*models.RetryPolicy, error) {
+	err := validateRetryPolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	return rps.repository.UpsertRetryPolicy(ctx, userID, policy)
+}
+
+func validateRetryPolicy(p *models.RetryPolicy) error {
+	err := p.Validate()
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrInvalidRetryPolicy, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var defaultRetryPolicy = models.RetryPolicy{
+	MaxAttempts:  5,
+	Backoff:      models.BackoffExponential,
+	BaseDelayMs:  1000,
+	MaxDelayMs:   300_000,
+	Jitter:       0.2,
+	StaleAfterMs: 300_000,
+}
+
+func TestRetryPolicyService_GetRetryPolicy(t *testing.T) {
+	stored := &models.RetryPolicy{
+		MaxAttempts:  3,
+		Backoff:      models.BackoffConstant,
+		BaseDelayMs:  5000,
+		MaxDelayMs:   5000,
+		StaleAfterMs: 60_000,
+	}
+
+	tests := map[string]struct {
+		repoPolicy *models.RetryPolicy
+		repoErr    error
+		expected   *models.RetryPolicy
+		expectErr  error
+	}{
+		"stored policy": {
+			repoPolicy: stored,
+			expected:   stored,
+		},
+		"no stored policy falls back to default": {
+			repoErr:  domain.ErrRetryPolicyNotExists,
+			expected: &defaultRetryPolicy,
+		},
+		"repository error": {
+			repoErr:   assert.AnError,
+			expectErr: assert.AnError,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := new(MockRetryPolicyRepository)
+			m.
+				On("GetRetryPolicyByUserID", mock.Anything, 42).
+				Return(tc.repoPolicy, tc.repoErr).
+				Once()
+
+			svc := service.NewRetryPolicyService(m, defaultRetryPolicy)
+			out, err := svc.GetRetryPolicy(context.Background(), 42)
+
+			if tc.expectErr != nil {
+				assert.ErrorIs(t, err, tc.expectErr)
+				assert.Nil(t, out)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, out)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRetryPolicyService_UpdateRetryPolicy(t *testing.T) {
+	valid := func(modify func(p *models.RetryPolicy)) *models.RetryPolicy {
+		p := defaultRetryPolicy
+		modify(&p)
+		return &p
+	}
+
+	tests := map[string]struct {
+		policy    *models.RetryPolicy
+		repoErr   error
+		expectErr error
+	}{
+		"valid policy": {
+			policy: valid(func(p *models.RetryPolicy) {}),
+		},
+		"unknown backoff": {
+			policy:    valid(func(p *models.RetryPolicy) { p.Backoff = "fibonacci" }),
+			expectErr: domain.ErrInvalidRetryPolicy,
+		},
+		"zero attempts": {
+			policy:    valid(func(p *models.RetryPolicy) { p.MaxAttempts = 0 }),
+			expectErr: domain.ErrInvalidRetryPolicy,
+		},
+		"too many attempts": {
+			policy:    valid(func(p *models.RetryPolicy) { p.MaxAttempts = 51 }),
+			expectErr: domain.ErrInvalidRetryPolicy,
+		},
+		"base delay too small": {
+			policy:    valid(func(p *models.RetryPolicy) { p.BaseDelayMs = 10 }),
+			expectErr: domain.ErrInvalidRetryPolicy,
+		},
+		"max delay below base delay": {
+			policy:    valid(func(p *models.RetryPolicy) { p.MaxDelayMs = 500 }),
+			expectErr: domain.ErrInvalidRetryPolicy,
+		},
+		"jitter out of range": {
+			policy:    valid(func(p *models.RetryPolicy) { p.Jitter = 1.5 }),
+			expectErr: domain.ErrInvalidRetryPolicy,
+		},
+		"stale threshold too small": {
+			policy:    valid(func(p *models.RetryPolicy) { p.StaleAfterMs = 1000 }),
+			expectErr: domain.ErrInvalidRetryPolicy,
+		},
+		"repository error": {
+			policy:    valid(func(p *models.RetryPolicy) {}),
+			repoErr:   assert.AnError,
+			expectErr: assert.AnError,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := new(MockRetryPolicyRepository)
+			if tc.expectErr == nil || tc.repoErr != nil {
+				var stored *models.RetryPolicy
+				if tc.repoErr == nil {
+					stored = tc.policy
+				}
+				m.
+					On("UpsertRetryPolicy", mock.Anything, 42, tc.policy).
+					Return(stored, tc.repoErr).
+					Once()
+			}
+
+			svc := service.NewRetryPolicyService(m, defaultRetryPolicy)
+			out, err := svc.UpdateRetryPolicy(context.Background(), 42, tc.policy)
+
+			if tc.expectErr != nil {
+				assert.ErrorIs(t, err, tc.expectErr)
+				assert.Nil(t, out)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.policy, out)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
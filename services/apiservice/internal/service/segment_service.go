@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/segmentrule"
+)
+
+const (
+	maxSegmentNameLen = 100
+	maxSegmentRuleLen = 4096
+	// segmentPreviewSampleSize is the number of matching contacts a preview returns.
+	segmentPreviewSampleSize = 10
+)
+
+// SegmentService validates and manages the user's segments and previews the contacts they match.
+type SegmentService struct {
+	repository         domain.SegmentRepository
+	attributes         domain.ContactAttributeRepository
+	contactsRepository domain.ContactsRepository
+}
+
+// NewSegmentService creates a SegmentService with the given segment, contact attribute and contact repositories.
+func NewSegmentService(r domain.SegmentRepository, ar domain.ContactAttributeRepository, cr domain.ContactsRepository) *SegmentService {
+	return &SegmentService{
+		repository:         r,
+		attributes:         ar,
+		contactsRepository: cr,
+	}
+}
+
+// GetSegmentsByUserID retrieves every segment of the user.
+func (ss *SegmentService) GetSegmentsByUserID(ctx context.Context, userID int) ([]*models.Segment, error) {
+	return ss.repository.GetSegmentsByUserID(ctx, userID)
+}
+
+// GetSegmentByID retrieves a segment of the user.
+func (ss *SegmentService) GetSegmentByID(ctx context.Context, userID, segmentID int) (*models.Segment, error) {
+	return ss.repository.GetSegmentByID(ctx, userID, segmentID)
+}
+
+// CreateSegment validates the name and rule of the segment and creates it.
+// Returns an error wrapping domain.ErrInvalidSegment for invalid segments.
+func (ss *SegmentService) CreateSegment(ctx context.Context, segment *models.Segment) (*models.Segment, error) {
+	err := ss.validateSegment(ctx, segment)
+	if err != nil {
+		return nil, err
+	}
+
+	return ss.repository.CreateSegment(ctx, segment)
+}
+
+// UpdateSegment validates the name and rule of the segment and replaces the stored one.
+func (ss *SegmentService) UpdateSegment(ctx context.Context, userID, segmentID int, segment *models.Segment) (*models.Segment, error) {
+	err := ss.validateSegment(ctx, segment)
+	if err != nil {
+		return nil, err
+	}
+
+	return ss.repository.UpdateSegment(ctx, userID, segmentID, segment)
+}
+
+// DeleteSegment removes a segment of the user.
+func (ss *SegmentService) DeleteSegment(ctx context.Context, userID, segmentID int) error {
+	return ss.repository.DeleteSegment(ctx, userID, segmentID)
+}
+
+// PreviewRule counts the user's contacts the rule matches and returns the first of them,
+// so that a rule can be checked before it is saved.
+func (ss *SegmentService) PreviewRule(ctx context.Context, userID int, rule string) (*domain.SegmentPreview, error) {
+	expr, err := compileSegmentRule(ctx, ss.attributes, userID, rule)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := domain.ContactFilter{Rule: expr}
+
+	count, err := ss.contactsRepository.GetContactsCountByUserID(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	sample, _, err := ss.contactsRepository.GetContactsPageByUserID(ctx, userID, filter, domain.PageRequest{Limit: segmentPreviewSampleSize})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.SegmentPreview{
+		Count:  count,
+		Sample: sample,
+	}, nil
+}
+
+// PreviewSegment previews the rule of a saved segment.
+func (ss *SegmentService) PreviewSegment(ctx context.Context, userID, segmentID int) (*domain.SegmentPreview, error) {
+	segment, err := ss.repository.GetSegmentByID(ctx, userID, segmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ss.PreviewRule(ctx, userID, segment.Rule)
+}
+
+// validateSegment trims the name of the segment and checks it along with the rule.
+func (ss *SegmentService) validateSegment(ctx context.Context, segment *models.Segment) error {
+	segment.Name = strings.TrimSpace(segment.Name)
+	if segment.Name == "" || utf8.RuneCountInString(segment.Name) > maxSegmentNameLen {
+		return domain.ErrInvalidSegmentName
+	}
+
+	segment.Rule = strings.TrimSpace(segment.Rule)
+	_, err := compileSegmentRule(ctx, ss.attributes, segment.UserID, segment.Rule)
+	return err
+}
+
+// compileSegmentRule parses the rule and resolves it against the user's attribute definitions:
+// attribute names are matched case-insensitively and values compared for equality with typed
+// attributes are normalized like the stored values, so `shift = night` matches "Night".
+// Returns an error wrapping domain.ErrInvalidSegmentRule that describes the problem.
+func compileSegmentRule(ctx context.Context, ar domain.ContactAttributeRepository, userID int, rule string) (*segmentrule.Expr, error) {
+	if len(rule) > maxSegmentRuleLen {
+		return nil, fmt.Errorf("%w: rule is too long", domain.ErrInvalidSegmentRule)
+	}
+
+	expr, err := segmentrule.Parse(rule)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrInvalidSegmentRule, err)
+	}
+
+	defs, err := ar.GetContactAttributes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(defs) == 0 {
+		return expr, nil
+	}
+
+	byName := make(map[string]*models.ContactAttribute, len(defs))
+	for _, d := range defs {
+		byName[strings.ToLower(d.Name)] = d
+	}
+
+	expr.Walk(func(c *segmentrule.Expr) {
+		if err != nil || c.Field == segmentrule.FieldName || c.Field == segmentrule.FieldPhone {
+			return
+		}
+		d, ok := byName[strings.ToLower(c.Field)]
+		if !ok {
+			return
+		}
+		c.Field = d.Name
+		if c.Op == segmentrule.OpContains {
+			return
+		}
+		for i, v := range c.Values {
+			normalized, ok := d.Normalize(v)
+			if !ok {
+				err = fmt.Errorf("%w: invalid value %q of attribute %s", domain.ErrInvalidSegmentRule, v, d.Name)
+				return
+			}
+			c.Values[i] = normalized
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return expr, nil
+}
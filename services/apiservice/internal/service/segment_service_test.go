@@ -0,0 +1,128 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/segmentrule"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSegmentService_CreateSegment(t *testing.T) {
+	defs := []*models.ContactAttribute{
+		{Name: "Shift", Type: models.AttributeEnum, Options: []string{"Day", "Night"}},
+		{Name: "Floor", Type: models.AttributeNumber},
+	}
+
+	tests := map[string]struct {
+		segment  *models.Segment
+		withDefs bool
+		want     *models.Segment
+		wantErr  error
+	}{
+		"valid": {
+			segment:  &models.Segment{UserID: 1, Name: " Night shift ", Rule: ` shift = night AND building = "B" `},
+			withDefs: true,
+			want:     &models.Segment{UserID: 1, Name: "Night shift", Rule: `shift = night AND building = "B"`},
+		},
+		"empty name": {
+			segment: &models.Segment{UserID: 1, Name: "  ", Rule: "a = 1"},
+			wantErr: domain.ErrInvalidSegmentName,
+		},
+		"name too long": {
+			segment: &models.Segment{UserID: 1, Name: strings.Repeat("я", 101), Rule: "a = 1"},
+			wantErr: domain.ErrInvalidSegmentName,
+		},
+		"syntax error": {
+			segment: &models.Segment{UserID: 1, Name: "Broken", Rule: "a = "},
+			wantErr: domain.ErrInvalidSegmentRule,
+		},
+		"rule too long": {
+			segment: &models.Segment{UserID: 1, Name: "Long", Rule: "a = " + strings.Repeat("x", 5000)},
+			wantErr: domain.ErrInvalidSegmentRule,
+		},
+		"value of wrong type": {
+			segment:  &models.Segment{UserID: 1, Name: "Floors", Rule: "floor IN (1, first)"},
+			withDefs: true,
+			wantErr:  domain.ErrInvalidSegmentRule,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			sr := new(MockSegmentRepository)
+			ar := new(MockContactAttributeRepository)
+			if tc.withDefs {
+				ar.On("GetContactAttributes", mock.Anything, 1).Return(defs, nil).Once()
+			}
+			if tc.want != nil {
+				sr.On("CreateSegment", mock.Anything, tc.want).Return(tc.want, nil).Once()
+			}
+			svc := service.NewSegmentService(sr, ar, new(MockContactsRepository))
+
+			got, err := svc.CreateSegment(context.Background(), tc.segment)
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.ErrorIs(t, err, domain.ErrInvalidSegment)
+				assert.Nil(t, got)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.want, got)
+			}
+			sr.AssertExpectations(t)
+			ar.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSegmentService_PreviewSegment(t *testing.T) {
+	sample := []*models.Contact{{ID: 1, UserID: 1, Name: "Alice", Phone: "+79123456789"}}
+	matchesRule := mock.MatchedBy(func(f domain.ContactFilter) bool {
+		return f.Rule != nil && f.Rule.Op == segmentrule.OpIn && f.Rule.Field == "Shift" &&
+			assert.ObjectsAreEqual([]string{"Day", "Night"}, f.Rule.Values)
+	})
+
+	sr := new(MockSegmentRepository)
+	sr.
+		On("GetSegmentByID", mock.Anything, 1, 7).
+		Return(&models.Segment{ID: 7, UserID: 1, Rule: "SHIFT in (day, NIGHT)"}, nil).
+		Once()
+	ar := new(MockContactAttributeRepository)
+	ar.
+		On("GetContactAttributes", mock.Anything, 1).
+		Return([]*models.ContactAttribute{{Name: "Shift", Type: models.AttributeEnum, Options: []string{"Day", "Night"}}}, nil).
+		Once()
+	cr := new(MockContactsRepository)
+	cr.On("GetContactsCountByUserID", mock.Anything, 1, matchesRule).Return(25, nil).Once()
+	cr.
+		On("GetContactsPageByUserID", mock.Anything, 1, matchesRule, domain.PageRequest{Limit: 10}).
+		Return(sample, "cursor", nil).
+		Once()
+	svc := service.NewSegmentService(sr, ar, cr)
+
+	got, err := svc.PreviewSegment(context.Background(), 1, 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.SegmentPreview{Count: 25, Sample: sample}, got)
+	sr.AssertExpectations(t)
+	ar.AssertExpectations(t)
+	cr.AssertExpectations(t)
+}
+
+func TestSegmentService_PreviewSegment_NotExists(t *testing.T) {
+	sr := new(MockSegmentRepository)
+	sr.On("GetSegmentByID", mock.Anything, 1, 7).Return(nil, domain.ErrSegmentNotExists).Once()
+	svc := service.NewSegmentService(sr, new(MockContactAttributeRepository), new(MockContactsRepository))
+
+	got, err := svc.PreviewSegment(context.Background(), 1, 7)
+
+	assert.ErrorIs(t, err, domain.ErrSegmentNotExists)
+	assert.Nil(t, got)
+	sr.AssertExpectations(t)
+}
@@ -3,49 +3,160 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/geo"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/phoneutils"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/smsutils"
+	"github.com/google/uuid"
 	"github.com/segmentio/kafka-go"
 )
 
+// previewSampleSize is the number of recipients whose messages are shown in campaign previews.
+const previewSampleSize = 5
+
 // SendNotificationService orchestrates reading a template and contacts,
 // splitting them into chunks, and emitting one Kafka message per chunk.
 type SendNotificationService struct {
-	contactsRepository domain.ContactsRepository
-	templateRepository domain.TemplateRepository
-	kafkaWriter        domain.KafkaWriter
-	contactsPerMessage int
+	contactsRepository    domain.ContactsRepository
+	templateRepository    domain.TemplateRepository
+	retryPolicyRepository domain.RetryPolicyRepository
+	quietHoursRepository  domain.QuietHoursRepository
+	segmentRepository     domain.SegmentRepository
+	attributeRepository   domain.ContactAttributeRepository
+	zoneRepository        domain.ZoneRepository
+	campaignRepository    domain.CampaignRepository
+	kafkaWriter           domain.KafkaWriter
+	contactsPerMessage    int
+	defaultRetryPolicy    models.RetryPolicy
+	pricing               models.SMSPricing
+	maxSegments           int
 }
 
 // NewSendNotificationService constructs a SendNotificationService.
-func NewSendNotificationService(cr domain.ContactsRepository, tr domain.TemplateRepository, kw domain.KafkaWriter, cpm int) *SendNotificationService {
+// The pricing is used to estimate the cost of campaigns in previews, which also report the
+// recipients whose rendered message takes more than maxSegments SMS segments.
+func NewSendNotificationService(cr domain.ContactsRepository, tr domain.TemplateRepository, rpr domain.RetryPolicyRepository, qhr domain.QuietHoursRepository, sr domain.SegmentRepository, ar domain.ContactAttributeRepository, zr domain.ZoneRepository, cmr domain.CampaignRepository, kw domain.KafkaWriter, cpm int, defaultRetryPolicy models.RetryPolicy, pricing models.SMSPricing, maxSegments int) *SendNotificationService {
 	return &SendNotificationService{
-		contactsRepository: cr,
-		templateRepository: tr,
-		kafkaWriter:        kw,
-		contactsPerMessage: cpm,
+		contactsRepository:    cr,
+		templateRepository:    tr,
+		retryPolicyRepository: rpr,
+		quietHoursRepository:  qhr,
+		segmentRepository:     sr,
+		attributeRepository:   ar,
+		zoneRepository:        zr,
+		campaignRepository:    cmr,
+		kafkaWriter:           kw,
+		contactsPerMessage:    cpm,
+		defaultRetryPolicy:    defaultRetryPolicy,
+		pricing:               pricing,
+		maxSegments:           maxSegments,
 	}
 }
 
 // SendNotification loads the template and contacts for userId/templateID,
 // splits contacts into batches of size contactsPerMessage, and writes one
-// Kafka message per batch. Returns an error if any repository or Kafka call fails.
-func (sns *SendNotificationService) SendNotification(ctx context.Context, userID int, templateID int) error {
-	tmpl, err := sns.templateRepository.GetTemplateByID(ctx, userID, templateID)
+// Kafka message per batch. Every message carries a new campaign ID and the campaign retry policy:
+// opts.RetryPolicy if given, otherwise the user's stored default, otherwise the system default. Non-critical campaigns also carry
+// the user's quiet hours and each recipient's time zone, so that delivery can be deferred until morning.
+// opts.Filter and opts.SegmentID, if given, narrow the recipients to the contacts matching both; the
+// segment's rule is evaluated against the contacts as they are at send time. opts.Area or opts.ZoneID
+// further narrow them to the contacts located in the area; contacts without a location are skipped.
+// Templates with placeholders are rendered for every recipient, whose text is then sent along with the contact.
+// The campaign is recorded along with the version of the template it was sent from before its batches are written.
+// Returns domain.ErrInvalidRetryPolicy or domain.ErrInvalidPriority for invalid options,
+// domain.ErrSegmentNotExists for an unknown segment or domain.ErrInvalidSegmentRule if its rule no
+// longer matches the user's attribute definitions, domain.ErrZoneNotExists for an unknown zone,
+// domain.ErrInvalidArea for an invalid area or one given along with a zone,
+// or an error if any repository or Kafka call fails.
+func (sns *SendNotificationService) SendNotification(ctx context.Context, userID int, templateID int, opts *domain.SendNotificationRequest) error {
+	return sns.send(ctx, userID, uuid.New(), opts, func(ctx context.Context) (*models.Template, error) {
+		return sns.templateRepository.GetTemplateByID(ctx, userID, templateID)
+	})
+}
+
+// SendMessage sends text, rather than a stored template, to the contacts selected by opts just like
+// SendNotification does, as the campaign campaignID. It is used for messages coming from other
+// systems, such as CAP alerts, and returns the same errors except those about the template.
+func (sns *SendNotificationService) SendMessage(ctx context.Context, userID int, campaignID uuid.UUID, text string, opts *domain.SendNotificationRequest) error {
+	return sns.send(ctx, userID, campaignID, opts, func(context.Context) (*models.Template, error) {
+		return &models.Template{UserID: userID, Body: text}, nil
+	})
+}
+
+// send resolves the options and the recipients of a campaign and writes its batches to Kafka.
+// The message is loaded by loadTemplate once the options are known to be valid.
+func (sns *SendNotificationService) send(ctx context.Context, userID int, campaignID uuid.UUID, opts *domain.SendNotificationRequest, loadTemplate func(context.Context) (*models.Template, error)) error {
+	if opts == nil {
+		opts = &domain.SendNotificationRequest{}
+	}
+
+	priority, err := campaignPriority(opts.Priority)
 	if err != nil {
 		return err
 	}
 
-	contacts, err := sns.contactsRepository.GetAllContactsByUserID(ctx, userID)
+	policy, err := sns.resolveRetryPolicy(ctx, userID, opts.RetryPolicy)
 	if err != nil {
 		return err
 	}
+
+	var quietHours *models.QuietHours
+	if priority != models.PriorityCritical {
+		quietHours, err = sns.quietHoursRepository.GetQuietHoursByUserID(ctx, userID)
+		if err != nil && !errors.Is(err, domain.ErrQuietHoursNotExists) {
+			return err
+		}
+	}
+
+	tmpl, err := loadTemplate(ctx)
+	if err != nil {
+		return err
+	}
+
+	filter, err := sns.recipientsFilter(ctx, userID, opts)
+	if err != nil {
+		return err
+	}
+
+	contacts, err := sns.getRecipients(ctx, userID, filter)
+	if err != nil {
+		return err
+	}
+	contacts = uniqueRecipients(contacts)
 	if len(contacts) == 0 {
 		return domain.ErrContactNotExists
 	}
 
 	slimContacts := models.ToSlim(contacts)
+	if hasPlaceholders(tmpl.Body) {
+		for i, c := range contacts {
+			slimContacts[i].Text = renderTemplate(tmpl.Body, c)
+		}
+	}
+	if quietHours != nil {
+		for _, c := range slimContacts {
+			if c.TimeZone == "" {
+				c.TimeZone, _ = phoneutils.TimeZoneForNumber(c.Phone)
+			}
+		}
+	}
+
+	if tmpl.ID != 0 {
+		err = sns.campaignRepository.CreateCampaign(ctx, &models.Campaign{
+			ID:              campaignID,
+			UserID:          userID,
+			TemplateID:      tmpl.ID,
+			TemplateVersion: tmpl.Version,
+		})
+		if err != nil {
+			return err
+		}
+	}
 
 	for start := 0; start < len(slimContacts); start += sns.contactsPerMessage {
 		end := start + sns.contactsPerMessage
@@ -55,9 +166,14 @@ func (sns *SendNotificationService) SendNotification(ctx context.Context, userID
 		chunk := slimContacts[start:end]
 
 		notification := &domain.OutgoingNotification{
-			UserID:   userID,
-			Template: tmpl.Body,
-			Contacts: chunk,
+			UserID:      userID,
+			CampaignID:  campaignID,
+			Template:    tmpl.Body,
+			Contacts:    chunk,
+			RetryPolicy: policy,
+			Priority:    priority,
+			QuietHours:  quietHours,
+			SenderID:    tmpl.SenderID,
 		}
 
 		msgBytes, err := json.Marshal(notification)
@@ -75,3 +191,291 @@ func (sns *SendNotificationService) SendNotification(ctx context.Context, userID
 
 	return nil
 }
+
+// CountRecipients returns the number of contacts SendNotification would send a campaign with the
+// options to, as of now. It returns the same errors for invalid options, without sending anything.
+func (sns *SendNotificationService) CountRecipients(ctx context.Context, userID int, opts *domain.SendNotificationRequest) (int, error) {
+	if opts == nil {
+		opts = &domain.SendNotificationRequest{}
+	}
+
+	err := validateCampaignOptions(opts)
+	if err != nil {
+		return 0, err
+	}
+
+	filter, err := sns.recipientsFilter(ctx, userID, opts)
+	if err != nil {
+		return 0, err
+	}
+	if filter == nil {
+		filter = &domain.ContactFilter{}
+	}
+
+	return sns.contactsRepository.GetContactsCountByUserID(ctx, userID, *filter)
+}
+
+// PreviewNotification reports what SendNotification would send with the options now, without
+// sending anything: the number of recipients, the encoding and segments of their messages, the cost
+// of the segments estimated with the configured pricing and the messages of the first recipients.
+// Templates are limited to maxSegments as written, so the preview also counts the recipients whose
+// message exceeds the limit once the placeholders are substituted.
+// Contacts sharing a phone number are counted once, as SendNotification messages them once.
+// It returns the same errors as SendNotification.
+func (sns *SendNotificationService) PreviewNotification(ctx context.Context, userID int, templateID int, opts *domain.SendNotificationRequest) (*domain.CampaignPreview, error) {
+	if opts == nil {
+		opts = &domain.SendNotificationRequest{}
+	}
+
+	err := validateCampaignOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := sns.templateRepository.GetTemplateByID(ctx, userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := sns.recipientsFilter(ctx, userID, opts)
+	if err != nil {
+		return nil, err
+	}
+	if filter == nil {
+		filter = &domain.ContactFilter{}
+	}
+
+	preview := &domain.CampaignPreview{
+		Encoding:    smsutils.EncodingGSM7,
+		MaxSegments: sns.maxSegments,
+		Currency:    sns.pricing.Currency,
+		Samples:     []*domain.PreviewMessage{},
+	}
+	personalized := hasPlaceholders(tmpl.Body)
+	segmentation := smsutils.Segment(tmpl.Body)
+	seen := make(map[string]struct{})
+
+	err = sns.contactsRepository.StreamContacts(ctx, userID, *filter, func(c *models.Contact) error {
+		phone := recipientPhone(c.Phone)
+		if _, ok := seen[phone]; ok {
+			return nil
+		}
+		seen[phone] = struct{}{}
+
+		text := tmpl.Body
+		if personalized {
+			text = renderTemplate(tmpl.Body, c)
+			segmentation = smsutils.Segment(text)
+		}
+
+		preview.Recipients++
+		preview.TotalSegments += segmentation.Segments
+		preview.SegmentsPerMessage = max(preview.SegmentsPerMessage, segmentation.Segments)
+		if sns.maxSegments > 0 && segmentation.Segments > sns.maxSegments {
+			preview.RecipientsOverMaxSegments++
+		}
+		if segmentation.Encoding == smsutils.EncodingUCS2 {
+			preview.Encoding = smsutils.EncodingUCS2
+		}
+		if len(preview.Samples) < previewSampleSize {
+			preview.Samples = append(preview.Samples, &domain.PreviewMessage{
+				Name:         c.Name,
+				Phone:        c.Phone,
+				Text:         text,
+				Segmentation: segmentation,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if preview.Recipients == 0 {
+		return nil, domain.ErrContactNotExists
+	}
+
+	// Prices of a segment are often fractions of a cent; round away only the floating-point noise.
+	preview.EstimatedCost = math.Round(float64(preview.TotalSegments)*sns.pricing.SegmentPrice*1e6) / 1e6
+
+	return preview, nil
+}
+
+// validateCampaignOptions checks the priority and the retry policy override of the options.
+func validateCampaignOptions(opts *domain.SendNotificationRequest) error {
+	_, err := campaignPriority(opts.Priority)
+	if err != nil {
+		return err
+	}
+	if opts.RetryPolicy != nil {
+		return validateRetryPolicy(opts.RetryPolicy)
+	}
+	return nil
+}
+
+// campaignPriority returns the priority of a campaign, which is critical unless given.
+func campaignPriority(p models.Priority) (models.Priority, error) {
+	switch p {
+	case "":
+		return models.PriorityCritical, nil
+	case models.PriorityCritical, models.PriorityNormal:
+		return p, nil
+	default:
+		return "", domain.ErrInvalidPriority
+	}
+}
+
+// recipientsFilter returns opts.Filter narrowed by the segment and the area of the options.
+func (sns *SendNotificationService) recipientsFilter(ctx context.Context, userID int, opts *domain.SendNotificationRequest) (*domain.ContactFilter, error) {
+	var err error
+
+	filter := opts.Filter
+	if opts.SegmentID != 0 {
+		filter, err = sns.segmentFilter(ctx, userID, opts.SegmentID, opts.Filter)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if opts.Area != nil || opts.ZoneID != 0 {
+		filter, err = sns.areaFilter(ctx, userID, opts.Area, opts.ZoneID, filter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return filter, nil
+}
+
+// getRecipients returns the user's contacts matching the filter, or all of them without one.
+func (sns *SendNotificationService) getRecipients(ctx context.Context, userID int, filter *domain.ContactFilter) ([]*models.Contact, error) {
+	if filter == nil || filter.IsEmpty() {
+		return sns.contactsRepository.GetAllContactsByUserID(ctx, userID)
+	}
+
+	var contacts []*models.Contact
+	err := sns.contactsRepository.StreamContacts(ctx, userID, *filter, func(c *models.Contact) error {
+		contacts = append(contacts, c)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return contacts, nil
+}
+
+// uniqueRecipients returns the contacts without those whose phone number, in E.164, was already
+// taken by an earlier contact, so that nobody receives a campaign twice. Endpoints repeating the
+// phone number of another recipient or an endpoint of an earlier one are dropped as well, since
+// contacts created before endpoints were checked for duplicates may share them.
+func uniqueRecipients(contacts []*models.Contact) []*models.Contact {
+	seen := make(map[models.ContactEndpoint]struct{}, len(contacts))
+	unique := make([]*models.Contact, 0, len(contacts))
+	for _, c := range contacts {
+		key := models.ContactEndpoint{Channel: models.ChannelSMS, Address: recipientPhone(c.Phone)}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		unique = append(unique, c)
+	}
+
+	for i, c := range unique {
+		endpoints := make([]*models.ContactEndpoint, 0, len(c.Endpoints))
+		for _, e := range c.Endpoints {
+			key := models.ContactEndpoint{Channel: e.Channel, Address: e.Address}
+			if e.Channel == models.ChannelSMS {
+				key.Address = recipientPhone(e.Address)
+			}
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			endpoints = append(endpoints, e)
+		}
+		if len(endpoints) < len(c.Endpoints) {
+			deduped := *c
+			deduped.Endpoints = endpoints
+			unique[i] = &deduped
+		}
+	}
+
+	return unique
+}
+
+// recipientPhone returns the phone number of a recipient in E.164, or as stored if it doesn't parse.
+func recipientPhone(phone string) string {
+	e164, err := phoneutils.FormatToE164(phone, "")
+	if err != nil {
+		return phone
+	}
+	return e164
+}
+
+// segmentFilter returns the filter narrowed to the contacts matching the rule of the segment.
+func (sns *SendNotificationService) segmentFilter(ctx context.Context, userID, segmentID int, filter *domain.ContactFilter) (*domain.ContactFilter, error) {
+	segment, err := sns.segmentRepository.GetSegmentByID(ctx, userID, segmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	expr, err := compileSegmentRule(ctx, sns.attributeRepository, userID, segment.Rule)
+	if err != nil {
+		return nil, err
+	}
+
+	narrowed := domain.ContactFilter{}
+	if filter != nil {
+		narrowed = *filter
+	}
+	narrowed.Rule = expr
+
+	return &narrowed, nil
+}
+
+// areaFilter returns the filter narrowed to the contacts located in the area or the saved zone.
+func (sns *SendNotificationService) areaFilter(ctx context.Context, userID int, area *geo.Area, zoneID int, filter *domain.ContactFilter) (*domain.ContactFilter, error) {
+	if area != nil && zoneID != 0 {
+		return nil, fmt.Errorf("%w: either area or zoneId may be given", domain.ErrInvalidArea)
+	}
+	if zoneID != 0 {
+		zone, err := sns.zoneRepository.GetZoneByID(ctx, userID, zoneID)
+		if err != nil {
+			return nil, err
+		}
+		area = &zone.Area
+	}
+
+	err := validateArea(area)
+	if err != nil {
+		return nil, err
+	}
+
+	narrowed := domain.ContactFilter{}
+	if filter != nil {
+		narrowed = *filter
+	}
+	narrowed.Area = area
+
+	return &narrowed, nil
+}
+
+func (sns *SendNotificationService) resolveRetryPolicy(ctx context.Context, userID int, override *models.RetryPolicy) (*models.RetryPolicy, error) {
+	if override != nil {
+		err := validateRetryPolicy(override)
+		if err != nil {
+			return nil, err
+		}
+		return override, nil
+	}
+
+	policy, err := sns.retryPolicyRepository.GetRetryPolicyByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrRetryPolicyNotExists) {
+			p := sns.defaultRetryPolicy
+			return &p, nil
+		}
+		return nil, err
+	}
+
+	return policy, nil
+}
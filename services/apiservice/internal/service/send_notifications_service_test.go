@@ -2,11 +2,17 @@ package service_test
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/geo"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/smsutils"
+	"github.com/google/uuid"
 	"github.com/segmentio/kafka-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -16,24 +22,71 @@ func TestSendNotificationService_SendNotification(t *testing.T) {
 	userID := 42
 	tmplID := 123
 
-	tmpl := &models.Template{ID: tmplID, UserID: userID, Body: "Hello {{.Name}}"}
+	tmpl := &models.Template{ID: tmplID, UserID: userID, Body: "Hello {{.Name}}", Version: 3}
 	contacts := []*models.Contact{
 		{ID: 1, UserID: userID, Name: "A", Phone: "+100"},
 		{ID: 2, UserID: userID, Name: "B", Phone: "+200"},
 		{ID: 3, UserID: userID, Name: "C", Phone: "+300"},
 	}
 
+	defaultPolicy := models.RetryPolicy{
+		MaxAttempts:  5,
+		Backoff:      models.BackoffExponential,
+		BaseDelayMs:  1000,
+		MaxDelayMs:   300_000,
+		Jitter:       0.2,
+		StaleAfterMs: 300_000,
+	}
+	userPolicy := &models.RetryPolicy{
+		MaxAttempts:  10,
+		Backoff:      models.BackoffLinear,
+		BaseDelayMs:  2000,
+		MaxDelayMs:   60_000,
+		StaleAfterMs: 120_000,
+	}
+	campaignPolicy := &models.RetryPolicy{
+		MaxAttempts:  2,
+		Backoff:      models.BackoffConstant,
+		BaseDelayMs:  500,
+		MaxDelayMs:   500,
+		StaleAfterMs: 60_000,
+	}
+
+	quietHours := &models.QuietHours{StartMinute: 22 * 60, EndMinute: 7 * 60, TimeZone: "Europe/Moscow"}
+	tzContacts := []*models.Contact{
+		{ID: 1, UserID: userID, Name: "A", Phone: "+12025550123"},
+		{ID: 2, UserID: userID, Name: "B", Phone: "+18005550199"},
+		{ID: 3, UserID: userID, Name: "C", Phone: "+12025550124", TimeZone: "America/Chicago"},
+	}
+
+	withPolicy := func(expected models.RetryPolicy) any {
+		return mock.MatchedBy(func(msgs []kafka.Message) bool {
+			var n domain.OutgoingNotification
+			err := json.Unmarshal(msgs[0].Value, &n)
+			return err == nil && n.RetryPolicy != nil && *n.RetryPolicy == expected
+		})
+	}
+
 	tests := []struct {
 		name                 string
 		contactsPerMsg       int
-		setupMocks           func(cr *MockContactsRepository, tr *MockTemplateRepository, kw *MockKafkaWriter)
+		opts                 *domain.SendNotificationRequest
+		setupQuietHours      func(qhr *MockQuietHoursRepository)
+		setupSegments        func(sr *MockSegmentRepository, ar *MockContactAttributeRepository)
+		setupZones           func(zr *MockZoneRepository)
+		setupCampaigns       func(cmr *MockCampaignRepository)
+		setupMocks           func(cr *MockContactsRepository, tr *MockTemplateRepository, rpr *MockRetryPolicyRepository, kw *MockKafkaWriter)
 		wantErr              error
 		expectedKafkaBatches int
 	}{
 		{
 			name:           "template error",
 			contactsPerMsg: 2,
-			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, kw *MockKafkaWriter) {
+			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, rpr *MockRetryPolicyRepository, kw *MockKafkaWriter) {
+				rpr.
+					On("GetRetryPolicyByUserID", mock.Anything, userID).
+					Return(userPolicy, nil).
+					Once()
 				tr.
 					On("GetTemplateByID", mock.Anything, userID, tmplID).
 					Return((*models.Template)(nil), assert.AnError).
@@ -44,7 +97,11 @@ func TestSendNotificationService_SendNotification(t *testing.T) {
 		{
 			name:           "contacts error",
 			contactsPerMsg: 2,
-			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, kw *MockKafkaWriter) {
+			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, rpr *MockRetryPolicyRepository, kw *MockKafkaWriter) {
+				rpr.
+					On("GetRetryPolicyByUserID", mock.Anything, userID).
+					Return(userPolicy, nil).
+					Once()
 				tr.
 					On("GetTemplateByID", mock.Anything, userID, tmplID).
 					Return(tmpl, nil).
@@ -59,7 +116,11 @@ func TestSendNotificationService_SendNotification(t *testing.T) {
 		{
 			name:           "no contacts",
 			contactsPerMsg: 2,
-			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, kw *MockKafkaWriter) {
+			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, rpr *MockRetryPolicyRepository, kw *MockKafkaWriter) {
+				rpr.
+					On("GetRetryPolicyByUserID", mock.Anything, userID).
+					Return(userPolicy, nil).
+					Once()
 				tr.
 					On("GetTemplateByID", mock.Anything, userID, tmplID).
 					Return(tmpl, nil).
@@ -74,7 +135,11 @@ func TestSendNotificationService_SendNotification(t *testing.T) {
 		{
 			name:           "kafka write failure",
 			contactsPerMsg: 2,
-			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, kw *MockKafkaWriter) {
+			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, rpr *MockRetryPolicyRepository, kw *MockKafkaWriter) {
+				rpr.
+					On("GetRetryPolicyByUserID", mock.Anything, userID).
+					Return(userPolicy, nil).
+					Once()
 				tr.
 					On("GetTemplateByID", mock.Anything, userID, tmplID).
 					Return(tmpl, nil).
@@ -94,9 +159,13 @@ func TestSendNotificationService_SendNotification(t *testing.T) {
 			wantErr: assert.AnError,
 		},
 		{
-			name:           "successful chunking",
+			name:           "successful chunking with user policy",
 			contactsPerMsg: 2,
-			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, kw *MockKafkaWriter) {
+			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, rpr *MockRetryPolicyRepository, kw *MockKafkaWriter) {
+				rpr.
+					On("GetRetryPolicyByUserID", mock.Anything, userID).
+					Return(userPolicy, nil).
+					Once()
 				tr.
 					On("GetTemplateByID", mock.Anything, userID, tmplID).
 					Return(tmpl, nil).
@@ -108,27 +177,606 @@ func TestSendNotificationService_SendNotification(t *testing.T) {
 
 				// Expect ceil(3/2)=2 calls to WriteMessages
 				kw.
-					On("WriteMessages", mock.Anything, mock.Anything).
+					On("WriteMessages", mock.Anything, withPolicy(*userPolicy)).
 					Return(nil).
 					Twice()
 			},
 			wantErr:              nil,
 			expectedKafkaBatches: 2,
 		},
+		{
+			name:           "template sender id is forwarded",
+			contactsPerMsg: 5,
+			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, rpr *MockRetryPolicyRepository, kw *MockKafkaWriter) {
+				rpr.
+					On("GetRetryPolicyByUserID", mock.Anything, userID).
+					Return(userPolicy, nil).
+					Once()
+				tr.
+					On("GetTemplateByID", mock.Anything, userID, tmplID).
+					Return(&models.Template{ID: tmplID, UserID: userID, Body: "Hello", SenderID: "CityAlert"}, nil).
+					Once()
+				cr.
+					On("GetAllContactsByUserID", mock.Anything, userID).
+					Return(contacts, nil).
+					Once()
+				kw.
+					On("WriteMessages", mock.Anything, mock.MatchedBy(func(msgs []kafka.Message) bool {
+						var n domain.OutgoingNotification
+						err := json.Unmarshal(msgs[0].Value, &n)
+						return err == nil && n.SenderID == "CityAlert"
+					})).
+					Return(nil).
+					Once()
+			},
+			wantErr:              nil,
+			expectedKafkaBatches: 1,
+		},
+		{
+			name:           "placeholders are rendered per contact",
+			contactsPerMsg: 5,
+			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, rpr *MockRetryPolicyRepository, kw *MockKafkaWriter) {
+				rpr.
+					On("GetRetryPolicyByUserID", mock.Anything, userID).
+					Return(userPolicy, nil).
+					Once()
+				tr.
+					On("GetTemplateByID", mock.Anything, userID, tmplID).
+					Return(&models.Template{ID: tmplID, UserID: userID, Body: "{{.Name}}, go to {{ building }}{{Floor}}"}, nil).
+					Once()
+				cr.
+					On("GetAllContactsByUserID", mock.Anything, userID).
+					Return([]*models.Contact{
+						{ID: 1, UserID: userID, Name: "A", Phone: "+100", Attributes: map[string]string{"Building": "B2"}},
+						{ID: 2, UserID: userID, Name: "B", Phone: "+200"},
+					}, nil).
+					Once()
+				kw.
+					On("WriteMessages", mock.Anything, mock.MatchedBy(func(msgs []kafka.Message) bool {
+						var n domain.OutgoingNotification
+						err := json.Unmarshal(msgs[0].Value, &n)
+						return err == nil && len(n.Contacts) == 2 &&
+							n.Contacts[0].Text == "A, go to B2" &&
+							n.Contacts[1].Text == "B, go to "
+					})).
+					Return(nil).
+					Once()
+			},
+			wantErr:              nil,
+			expectedKafkaBatches: 1,
+		},
+		{
+			name:           "filter narrows recipients",
+			contactsPerMsg: 5,
+			opts:           &domain.SendNotificationRequest{Filter: &domain.ContactFilter{Attributes: map[string]string{"Shift": "Night"}}},
+			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, rpr *MockRetryPolicyRepository, kw *MockKafkaWriter) {
+				rpr.
+					On("GetRetryPolicyByUserID", mock.Anything, userID).
+					Return(userPolicy, nil).
+					Once()
+				tr.
+					On("GetTemplateByID", mock.Anything, userID, tmplID).
+					Return(&models.Template{ID: tmplID, UserID: userID, Body: "Hello"}, nil).
+					Once()
+				cr.
+					On("StreamContacts", mock.Anything, userID, domain.ContactFilter{Attributes: map[string]string{"Shift": "Night"}}).
+					Return(contacts[:1], nil).
+					Once()
+				kw.
+					On("WriteMessages", mock.Anything, mock.MatchedBy(func(msgs []kafka.Message) bool {
+						var n domain.OutgoingNotification
+						err := json.Unmarshal(msgs[0].Value, &n)
+						return err == nil && len(n.Contacts) == 1 && n.Contacts[0].Phone == "+100" && n.Contacts[0].Text == ""
+					})).
+					Return(nil).
+					Once()
+			},
+			wantErr:              nil,
+			expectedKafkaBatches: 1,
+		},
+		{
+			name:           "segment narrows recipients",
+			contactsPerMsg: 5,
+			opts:           &domain.SendNotificationRequest{SegmentID: 7, Filter: &domain.ContactFilter{Group: "Staff"}},
+			setupSegments: func(sr *MockSegmentRepository, ar *MockContactAttributeRepository) {
+				sr.
+					On("GetSegmentByID", mock.Anything, userID, 7).
+					Return(&models.Segment{ID: 7, UserID: userID, Name: "Night B", Rule: `building = "B" AND shift = night`}, nil).
+					Once()
+				ar.
+					On("GetContactAttributes", mock.Anything, userID).
+					Return([]*models.ContactAttribute{{Name: "Shift", Type: models.AttributeEnum, Options: []string{"Day", "Night"}}}, nil).
+					Once()
+			},
+			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, rpr *MockRetryPolicyRepository, kw *MockKafkaWriter) {
+				rpr.
+					On("GetRetryPolicyByUserID", mock.Anything, userID).
+					Return(userPolicy, nil).
+					Once()
+				tr.
+					On("GetTemplateByID", mock.Anything, userID, tmplID).
+					Return(&models.Template{ID: tmplID, UserID: userID, Body: "Hello"}, nil).
+					Once()
+				cr.
+					On("StreamContacts", mock.Anything, userID, mock.MatchedBy(func(f domain.ContactFilter) bool {
+						return f.Group == "Staff" && f.Rule != nil && len(f.Rule.Operands) == 2 &&
+							f.Rule.Operands[0].Field == "building" && f.Rule.Operands[0].Values[0] == "B" &&
+							f.Rule.Operands[1].Field == "Shift" && f.Rule.Operands[1].Values[0] == "Night"
+					})).
+					Return(contacts[:2], nil).
+					Once()
+				kw.
+					On("WriteMessages", mock.Anything, mock.Anything).
+					Return(nil).
+					Once()
+			},
+			expectedKafkaBatches: 1,
+		},
+		{
+			name:           "unknown segment",
+			contactsPerMsg: 5,
+			opts:           &domain.SendNotificationRequest{SegmentID: 7},
+			setupSegments: func(sr *MockSegmentRepository, ar *MockContactAttributeRepository) {
+				sr.
+					On("GetSegmentByID", mock.Anything, userID, 7).
+					Return(nil, domain.ErrSegmentNotExists).
+					Once()
+			},
+			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, rpr *MockRetryPolicyRepository, kw *MockKafkaWriter) {
+				rpr.
+					On("GetRetryPolicyByUserID", mock.Anything, userID).
+					Return(userPolicy, nil).
+					Once()
+				tr.
+					On("GetTemplateByID", mock.Anything, userID, tmplID).
+					Return(tmpl, nil).
+					Once()
+			},
+			wantErr: domain.ErrSegmentNotExists,
+		},
+		{
+			name:           "area narrows recipients",
+			contactsPerMsg: 5,
+			opts: &domain.SendNotificationRequest{
+				Filter: &domain.ContactFilter{Group: "Staff"},
+				Area:   &geo.Area{Circle: &geo.Circle{Lat: 55.75, Lon: 37.62, Radius: 5000}},
+			},
+			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, rpr *MockRetryPolicyRepository, kw *MockKafkaWriter) {
+				rpr.
+					On("GetRetryPolicyByUserID", mock.Anything, userID).
+					Return(userPolicy, nil).
+					Once()
+				tr.
+					On("GetTemplateByID", mock.Anything, userID, tmplID).
+					Return(tmpl, nil).
+					Once()
+				cr.
+					On("StreamContacts", mock.Anything, userID, mock.MatchedBy(func(f domain.ContactFilter) bool {
+						return f.Group == "Staff" && f.Area != nil && f.Area.Circle != nil && f.Area.Circle.Radius == 5000
+					})).
+					Return(contacts[:1], nil).
+					Once()
+				kw.
+					On("WriteMessages", mock.Anything, mock.Anything).
+					Return(nil).
+					Once()
+			},
+			expectedKafkaBatches: 1,
+		},
+		{
+			name:           "zone narrows recipients",
+			contactsPerMsg: 5,
+			opts:           &domain.SendNotificationRequest{ZoneID: 3},
+			setupZones: func(zr *MockZoneRepository) {
+				zr.
+					On("GetZoneByID", mock.Anything, userID, 3).
+					Return(&models.Zone{ID: 3, UserID: userID, Name: "Center", Area: geo.Area{
+						Polygon: &geo.Geometry{Type: geo.GeometryPolygon, Coordinates: []byte(`[[[37,55],[38,55],[38,56],[37,55]]]`)},
+					}}, nil).
+					Once()
+			},
+			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, rpr *MockRetryPolicyRepository, kw *MockKafkaWriter) {
+				rpr.
+					On("GetRetryPolicyByUserID", mock.Anything, userID).
+					Return(userPolicy, nil).
+					Once()
+				tr.
+					On("GetTemplateByID", mock.Anything, userID, tmplID).
+					Return(tmpl, nil).
+					Once()
+				cr.
+					On("StreamContacts", mock.Anything, userID, mock.MatchedBy(func(f domain.ContactFilter) bool {
+						return f.Area != nil && f.Area.Polygon != nil
+					})).
+					Return(contacts[:1], nil).
+					Once()
+				kw.
+					On("WriteMessages", mock.Anything, mock.Anything).
+					Return(nil).
+					Once()
+			},
+			expectedKafkaBatches: 1,
+		},
+		{
+			name:           "unknown zone",
+			contactsPerMsg: 5,
+			opts:           &domain.SendNotificationRequest{ZoneID: 3},
+			setupZones: func(zr *MockZoneRepository) {
+				zr.
+					On("GetZoneByID", mock.Anything, userID, 3).
+					Return(nil, domain.ErrZoneNotExists).
+					Once()
+			},
+			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, rpr *MockRetryPolicyRepository, kw *MockKafkaWriter) {
+				rpr.
+					On("GetRetryPolicyByUserID", mock.Anything, userID).
+					Return(userPolicy, nil).
+					Once()
+				tr.
+					On("GetTemplateByID", mock.Anything, userID, tmplID).
+					Return(tmpl, nil).
+					Once()
+			},
+			wantErr: domain.ErrZoneNotExists,
+		},
+		{
+			name:           "invalid area",
+			contactsPerMsg: 5,
+			opts:           &domain.SendNotificationRequest{Area: &geo.Area{Circle: &geo.Circle{Lat: 95, Lon: 37, Radius: 100}}},
+			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, rpr *MockRetryPolicyRepository, kw *MockKafkaWriter) {
+				rpr.
+					On("GetRetryPolicyByUserID", mock.Anything, userID).
+					Return(userPolicy, nil).
+					Once()
+				tr.
+					On("GetTemplateByID", mock.Anything, userID, tmplID).
+					Return(tmpl, nil).
+					Once()
+			},
+			wantErr: domain.ErrInvalidArea,
+		},
+		{
+			name:           "area and zone together",
+			contactsPerMsg: 5,
+			opts: &domain.SendNotificationRequest{
+				Area:   &geo.Area{Circle: &geo.Circle{Lat: 55.75, Lon: 37.62, Radius: 5000}},
+				ZoneID: 3,
+			},
+			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, rpr *MockRetryPolicyRepository, kw *MockKafkaWriter) {
+				rpr.
+					On("GetRetryPolicyByUserID", mock.Anything, userID).
+					Return(userPolicy, nil).
+					Once()
+				tr.
+					On("GetTemplateByID", mock.Anything, userID, tmplID).
+					Return(tmpl, nil).
+					Once()
+			},
+			wantErr: domain.ErrInvalidArea,
+		},
+		{
+			name:           "falls back to default policy",
+			contactsPerMsg: 5,
+			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, rpr *MockRetryPolicyRepository, kw *MockKafkaWriter) {
+				rpr.
+					On("GetRetryPolicyByUserID", mock.Anything, userID).
+					Return((*models.RetryPolicy)(nil), domain.ErrRetryPolicyNotExists).
+					Once()
+				tr.
+					On("GetTemplateByID", mock.Anything, userID, tmplID).
+					Return(tmpl, nil).
+					Once()
+				cr.
+					On("GetAllContactsByUserID", mock.Anything, userID).
+					Return(contacts, nil).
+					Once()
+				kw.
+					On("WriteMessages", mock.Anything, withPolicy(defaultPolicy)).
+					Return(nil).
+					Once()
+			},
+			wantErr:              nil,
+			expectedKafkaBatches: 1,
+		},
+		{
+			name:           "campaign policy overrides user policy",
+			contactsPerMsg: 5,
+			opts:           &domain.SendNotificationRequest{RetryPolicy: campaignPolicy},
+			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, rpr *MockRetryPolicyRepository, kw *MockKafkaWriter) {
+				tr.
+					On("GetTemplateByID", mock.Anything, userID, tmplID).
+					Return(tmpl, nil).
+					Once()
+				cr.
+					On("GetAllContactsByUserID", mock.Anything, userID).
+					Return(contacts, nil).
+					Once()
+				kw.
+					On("WriteMessages", mock.Anything, withPolicy(*campaignPolicy)).
+					Return(nil).
+					Once()
+			},
+			wantErr:              nil,
+			expectedKafkaBatches: 1,
+		},
+		{
+			name:           "invalid campaign policy",
+			contactsPerMsg: 2,
+			opts:           &domain.SendNotificationRequest{RetryPolicy: &models.RetryPolicy{MaxAttempts: 0, Backoff: models.BackoffLinear}},
+			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, rpr *MockRetryPolicyRepository, kw *MockKafkaWriter) {
+			},
+			wantErr: domain.ErrInvalidRetryPolicy,
+		},
+		{
+			name:           "retry policy repository error",
+			contactsPerMsg: 2,
+			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, rpr *MockRetryPolicyRepository, kw *MockKafkaWriter) {
+				rpr.
+					On("GetRetryPolicyByUserID", mock.Anything, userID).
+					Return((*models.RetryPolicy)(nil), assert.AnError).
+					Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name:           "normal priority carries quiet hours and recipient time zones",
+			contactsPerMsg: 5,
+			opts:           &domain.SendNotificationRequest{Priority: models.PriorityNormal},
+			setupQuietHours: func(qhr *MockQuietHoursRepository) {
+				qhr.
+					On("GetQuietHoursByUserID", mock.Anything, userID).
+					Return(quietHours, nil).
+					Once()
+			},
+			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, rpr *MockRetryPolicyRepository, kw *MockKafkaWriter) {
+				rpr.
+					On("GetRetryPolicyByUserID", mock.Anything, userID).
+					Return(userPolicy, nil).
+					Once()
+				tr.
+					On("GetTemplateByID", mock.Anything, userID, tmplID).
+					Return(tmpl, nil).
+					Once()
+				cr.
+					On("GetAllContactsByUserID", mock.Anything, userID).
+					Return(tzContacts, nil).
+					Once()
+				kw.
+					On("WriteMessages", mock.Anything, mock.MatchedBy(func(msgs []kafka.Message) bool {
+						var n domain.OutgoingNotification
+						err := json.Unmarshal(msgs[0].Value, &n)
+						return err == nil &&
+							n.Priority == models.PriorityNormal &&
+							n.QuietHours != nil && *n.QuietHours == *quietHours &&
+							n.Contacts[0].TimeZone == "America/New_York" &&
+							n.Contacts[1].TimeZone == "" &&
+							n.Contacts[2].TimeZone == "America/Chicago"
+					})).
+					Return(nil).
+					Once()
+			},
+			expectedKafkaBatches: 1,
+		},
+		{
+			name:           "normal priority without quiet hours",
+			contactsPerMsg: 5,
+			opts:           &domain.SendNotificationRequest{Priority: models.PriorityNormal},
+			setupQuietHours: func(qhr *MockQuietHoursRepository) {
+				qhr.
+					On("GetQuietHoursByUserID", mock.Anything, userID).
+					Return((*models.QuietHours)(nil), domain.ErrQuietHoursNotExists).
+					Once()
+			},
+			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, rpr *MockRetryPolicyRepository, kw *MockKafkaWriter) {
+				rpr.
+					On("GetRetryPolicyByUserID", mock.Anything, userID).
+					Return(userPolicy, nil).
+					Once()
+				tr.
+					On("GetTemplateByID", mock.Anything, userID, tmplID).
+					Return(tmpl, nil).
+					Once()
+				cr.
+					On("GetAllContactsByUserID", mock.Anything, userID).
+					Return(contacts, nil).
+					Once()
+				kw.
+					On("WriteMessages", mock.Anything, mock.MatchedBy(func(msgs []kafka.Message) bool {
+						var n domain.OutgoingNotification
+						err := json.Unmarshal(msgs[0].Value, &n)
+						return err == nil && n.Priority == models.PriorityNormal && n.QuietHours == nil
+					})).
+					Return(nil).
+					Once()
+			},
+			expectedKafkaBatches: 1,
+		},
+		{
+			name:           "quiet hours repository error",
+			contactsPerMsg: 5,
+			opts:           &domain.SendNotificationRequest{Priority: models.PriorityNormal},
+			setupQuietHours: func(qhr *MockQuietHoursRepository) {
+				qhr.
+					On("GetQuietHoursByUserID", mock.Anything, userID).
+					Return((*models.QuietHours)(nil), assert.AnError).
+					Once()
+			},
+			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, rpr *MockRetryPolicyRepository, kw *MockKafkaWriter) {
+				rpr.
+					On("GetRetryPolicyByUserID", mock.Anything, userID).
+					Return(userPolicy, nil).
+					Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name:           "campaign is recorded with the template version",
+			contactsPerMsg: 5,
+			setupCampaigns: func(cmr *MockCampaignRepository) {
+				cmr.
+					On("CreateCampaign", mock.Anything, mock.MatchedBy(func(c *models.Campaign) bool {
+						return c.ID != uuid.Nil && c.UserID == userID && c.TemplateID == tmplID && c.TemplateVersion == 3
+					})).
+					Return(nil).
+					Once()
+			},
+			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, rpr *MockRetryPolicyRepository, kw *MockKafkaWriter) {
+				rpr.
+					On("GetRetryPolicyByUserID", mock.Anything, userID).
+					Return(userPolicy, nil).
+					Once()
+				tr.
+					On("GetTemplateByID", mock.Anything, userID, tmplID).
+					Return(tmpl, nil).
+					Once()
+				cr.
+					On("GetAllContactsByUserID", mock.Anything, userID).
+					Return(contacts, nil).
+					Once()
+				kw.
+					On("WriteMessages", mock.Anything, mock.Anything).
+					Return(nil).
+					Once()
+			},
+			expectedKafkaBatches: 1,
+		},
+		{
+			name:           "campaign recording failure sends nothing",
+			contactsPerMsg: 5,
+			setupCampaigns: func(cmr *MockCampaignRepository) {
+				cmr.
+					On("CreateCampaign", mock.Anything, mock.Anything).
+					Return(assert.AnError).
+					Once()
+			},
+			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, rpr *MockRetryPolicyRepository, kw *MockKafkaWriter) {
+				rpr.
+					On("GetRetryPolicyByUserID", mock.Anything, userID).
+					Return(userPolicy, nil).
+					Once()
+				tr.
+					On("GetTemplateByID", mock.Anything, userID, tmplID).
+					Return(tmpl, nil).
+					Once()
+				cr.
+					On("GetAllContactsByUserID", mock.Anything, userID).
+					Return(contacts, nil).
+					Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name:           "contacts sharing a phone are messaged once",
+			contactsPerMsg: 5,
+			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, rpr *MockRetryPolicyRepository, kw *MockKafkaWriter) {
+				rpr.
+					On("GetRetryPolicyByUserID", mock.Anything, userID).
+					Return(userPolicy, nil).
+					Once()
+				tr.
+					On("GetTemplateByID", mock.Anything, userID, tmplID).
+					Return(tmpl, nil).
+					Once()
+				cr.
+					On("GetAllContactsByUserID", mock.Anything, userID).
+					Return([]*models.Contact{
+						{ID: 1, UserID: userID, Name: "A", Phone: "+79120000001"},
+						{ID: 2, UserID: userID, Name: "B", Phone: "+7 912 000-00-01"},
+						{ID: 3, UserID: userID, Name: "C", Phone: "+79120000002"},
+					}, nil).
+					Once()
+				kw.
+					On("WriteMessages", mock.Anything, mock.MatchedBy(func(msgs []kafka.Message) bool {
+						var n domain.OutgoingNotification
+						err := json.Unmarshal(msgs[0].Value, &n)
+						return err == nil && len(n.Contacts) == 2 &&
+							n.Contacts[0].Name == "A" && n.Contacts[0].Phone == "+79120000001" &&
+							n.Contacts[1].Name == "C"
+					})).
+					Return(nil).
+					Once()
+			},
+			expectedKafkaBatches: 1,
+		},
+		{
+			name:           "endpoints of another recipient are not fallen back to",
+			contactsPerMsg: 5,
+			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, rpr *MockRetryPolicyRepository, kw *MockKafkaWriter) {
+				rpr.
+					On("GetRetryPolicyByUserID", mock.Anything, userID).
+					Return(userPolicy, nil).
+					Once()
+				tr.
+					On("GetTemplateByID", mock.Anything, userID, tmplID).
+					Return(tmpl, nil).
+					Once()
+				cr.
+					On("GetAllContactsByUserID", mock.Anything, userID).
+					Return([]*models.Contact{
+						{ID: 1, UserID: userID, Name: "A", Phone: "+79120000001", Endpoints: []*models.ContactEndpoint{
+							{Channel: models.ChannelSMS, Address: "+79120000002"},
+							{Channel: models.ChannelEmail, Address: "home@example.com"},
+						}},
+						{ID: 2, UserID: userID, Name: "B", Phone: "+79120000002", Endpoints: []*models.ContactEndpoint{
+							{Channel: models.ChannelEmail, Address: "home@example.com"},
+						}},
+					}, nil).
+					Once()
+				kw.
+					On("WriteMessages", mock.Anything, mock.MatchedBy(func(msgs []kafka.Message) bool {
+						var n domain.OutgoingNotification
+						err := json.Unmarshal(msgs[0].Value, &n)
+						return err == nil && len(n.Contacts) == 2 &&
+							assert.ObjectsAreEqual([]*models.Fallback{{Channel: models.ChannelEmail, Address: "home@example.com"}}, n.Contacts[0].Fallbacks) &&
+							len(n.Contacts[1].Fallbacks) == 0
+					})).
+					Return(nil).
+					Once()
+			},
+			expectedKafkaBatches: 1,
+		},
+		{
+			name:           "invalid priority",
+			contactsPerMsg: 5,
+			opts:           &domain.SendNotificationRequest{Priority: "whenever"},
+			setupMocks: func(cr *MockContactsRepository, tr *MockTemplateRepository, rpr *MockRetryPolicyRepository, kw *MockKafkaWriter) {
+			},
+			wantErr: domain.ErrInvalidPriority,
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			cr := new(MockContactsRepository)
 			tr := new(MockTemplateRepository)
+			rpr := new(MockRetryPolicyRepository)
+			qhr := new(MockQuietHoursRepository)
 			kw := new(MockKafkaWriter)
-			tc.setupMocks(cr, tr, kw)
+			tc.setupMocks(cr, tr, rpr, kw)
+			if tc.setupQuietHours != nil {
+				tc.setupQuietHours(qhr)
+			}
+			sr := new(MockSegmentRepository)
+			ar := new(MockContactAttributeRepository)
+			if tc.setupSegments != nil {
+				tc.setupSegments(sr, ar)
+			}
+			zr := new(MockZoneRepository)
+			if tc.setupZones != nil {
+				tc.setupZones(zr)
+			}
+			cmr := new(MockCampaignRepository)
+			if tc.setupCampaigns != nil {
+				tc.setupCampaigns(cmr)
+			} else {
+				cmr.On("CreateCampaign", mock.Anything, mock.Anything).Return(nil).Maybe()
+			}
 
-			svc := service.NewSendNotificationService(cr, tr, kw, tc.contactsPerMsg)
-			err := svc.SendNotification(context.Background(), userID, tmplID)
+			svc := service.NewSendNotificationService(cr, tr, rpr, qhr, sr, ar, zr, cmr, kw, tc.contactsPerMsg, defaultPolicy, models.SMSPricing{}, 3)
+			err := svc.SendNotification(context.Background(), userID, tmplID, tc.opts)
 
 			if tc.wantErr != nil {
-				assert.Error(t, err)
+				assert.ErrorIs(t, err, tc.wantErr)
 			} else {
 				assert.NoError(t, err)
 			}
@@ -139,7 +787,206 @@ func TestSendNotificationService_SendNotification(t *testing.T) {
 
 			cr.AssertExpectations(t)
 			tr.AssertExpectations(t)
+			rpr.AssertExpectations(t)
+			qhr.AssertExpectations(t)
+			sr.AssertExpectations(t)
+			ar.AssertExpectations(t)
+			zr.AssertExpectations(t)
+			cmr.AssertExpectations(t)
 			kw.AssertExpectations(t)
 		})
 	}
 }
+
+func TestSendNotificationService_SendMessage(t *testing.T) {
+	userID := 42
+	campaignID := uuid.New()
+	contacts := []*models.Contact{
+		{ID: 1, UserID: userID, Name: "A", Phone: "+100"},
+		{ID: 2, UserID: userID, Name: "B", Phone: "+200"},
+	}
+
+	cr := new(MockContactsRepository)
+	tr := new(MockTemplateRepository)
+	rpr := new(MockRetryPolicyRepository)
+	qhr := new(MockQuietHoursRepository)
+	kw := new(MockKafkaWriter)
+
+	rpr.On("GetRetryPolicyByUserID", mock.Anything, userID).Return((*models.RetryPolicy)(nil), domain.ErrRetryPolicyNotExists).Once()
+	cr.On("GetAllContactsByUserID", mock.Anything, userID).Return(contacts, nil).Once()
+	kw.On("WriteMessages", mock.Anything, mock.MatchedBy(func(msgs []kafka.Message) bool {
+		var n domain.OutgoingNotification
+		err := json.Unmarshal(msgs[0].Value, &n)
+		return err == nil && n.CampaignID == campaignID && n.Template == "Flood warning" && len(n.Contacts) == 2
+	})).Return(nil).Once()
+
+	svc := service.NewSendNotificationService(cr, tr, rpr, qhr, new(MockSegmentRepository), new(MockContactAttributeRepository), new(MockZoneRepository), new(MockCampaignRepository), kw, 10, models.RetryPolicy{MaxAttempts: 1}, models.SMSPricing{}, 3)
+	err := svc.SendMessage(context.Background(), userID, campaignID, "Flood warning", nil)
+
+	assert.NoError(t, err)
+	cr.AssertExpectations(t)
+	rpr.AssertExpectations(t)
+	kw.AssertExpectations(t)
+	tr.AssertNotCalled(t, "GetTemplateByID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSendNotificationService_CountRecipients(t *testing.T) {
+	userID := 42
+	circle := &geo.Area{Circle: &geo.Circle{Lat: 55.75, Lon: 37.62, Radius: 2000}}
+
+	t.Run("all contacts", func(t *testing.T) {
+		cr := new(MockContactsRepository)
+		cr.On("GetContactsCountByUserID", mock.Anything, userID, domain.ContactFilter{}).Return(120, nil).Once()
+		svc := service.NewSendNotificationService(cr, new(MockTemplateRepository), new(MockRetryPolicyRepository), new(MockQuietHoursRepository), new(MockSegmentRepository), new(MockContactAttributeRepository), new(MockZoneRepository), new(MockCampaignRepository), new(MockKafkaWriter), 10, models.RetryPolicy{MaxAttempts: 1}, models.SMSPricing{}, 3)
+
+		count, err := svc.CountRecipients(context.Background(), userID, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 120, count)
+		cr.AssertExpectations(t)
+	})
+
+	t.Run("filter narrowed by area", func(t *testing.T) {
+		cr := new(MockContactsRepository)
+		cr.On("GetContactsCountByUserID", mock.Anything, userID, domain.ContactFilter{Group: "ops", Area: circle}).Return(7, nil).Once()
+		svc := service.NewSendNotificationService(cr, new(MockTemplateRepository), new(MockRetryPolicyRepository), new(MockQuietHoursRepository), new(MockSegmentRepository), new(MockContactAttributeRepository), new(MockZoneRepository), new(MockCampaignRepository), new(MockKafkaWriter), 10, models.RetryPolicy{MaxAttempts: 1}, models.SMSPricing{}, 3)
+
+		count, err := svc.CountRecipients(context.Background(), userID, &domain.SendNotificationRequest{
+			Filter: &domain.ContactFilter{Group: "ops"},
+			Area:   circle,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 7, count)
+		cr.AssertExpectations(t)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		cr := new(MockContactsRepository)
+		svc := service.NewSendNotificationService(cr, new(MockTemplateRepository), new(MockRetryPolicyRepository), new(MockQuietHoursRepository), new(MockSegmentRepository), new(MockContactAttributeRepository), new(MockZoneRepository), new(MockCampaignRepository), new(MockKafkaWriter), 10, models.RetryPolicy{MaxAttempts: 1}, models.SMSPricing{}, 3)
+
+		_, err := svc.CountRecipients(context.Background(), userID, &domain.SendNotificationRequest{Priority: "whenever"})
+		assert.ErrorIs(t, err, domain.ErrInvalidPriority)
+
+		_, err = svc.CountRecipients(context.Background(), userID, &domain.SendNotificationRequest{RetryPolicy: &models.RetryPolicy{}})
+		assert.ErrorIs(t, err, domain.ErrInvalidRetryPolicy)
+
+		cr.AssertExpectations(t)
+	})
+}
+
+func TestSendNotificationService_PreviewNotification(t *testing.T) {
+	userID, templateID := 42, 7
+	pricing := models.SMSPricing{SegmentPrice: 0.1, Currency: "USD"}
+	contacts := make([]*models.Contact, 7)
+	for i := range contacts {
+		contacts[i] = &models.Contact{ID: i + 1, Name: fmt.Sprintf("Contact %d", i+1), Phone: fmt.Sprintf("+7912000000%d", i), Attributes: map[string]string{}}
+	}
+	contacts[0].Name = "Иван"
+
+	t.Run("plain template", func(t *testing.T) {
+		cr := new(MockContactsRepository)
+		tr := new(MockTemplateRepository)
+		kw := new(MockKafkaWriter)
+		tr.On("GetTemplateByID", mock.Anything, userID, templateID).Return(&models.Template{Body: strings.Repeat("a", 161)}, nil).Once()
+		cr.On("StreamContacts", mock.Anything, userID, domain.ContactFilter{Group: "ops"}).Return(contacts, nil).Once()
+		svc := service.NewSendNotificationService(cr, tr, new(MockRetryPolicyRepository), new(MockQuietHoursRepository), new(MockSegmentRepository), new(MockContactAttributeRepository), new(MockZoneRepository), new(MockCampaignRepository), kw, 10, models.RetryPolicy{MaxAttempts: 1}, pricing, 3)
+
+		preview, err := svc.PreviewNotification(context.Background(), userID, templateID, &domain.SendNotificationRequest{Filter: &domain.ContactFilter{Group: "ops"}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 7, preview.Recipients)
+		assert.Equal(t, smsutils.EncodingGSM7, preview.Encoding)
+		assert.Equal(t, 2, preview.SegmentsPerMessage)
+		assert.Equal(t, 14, preview.TotalSegments)
+		assert.Equal(t, 1.4, preview.EstimatedCost)
+		assert.Equal(t, "USD", preview.Currency)
+		assert.Len(t, preview.Samples, 5)
+		assert.Equal(t, "+79120000000", preview.Samples[0].Phone)
+		cr.AssertExpectations(t)
+		tr.AssertExpectations(t)
+		kw.AssertNotCalled(t, "WriteMessages", mock.Anything, mock.Anything)
+	})
+
+	t.Run("personalized template", func(t *testing.T) {
+		cr := new(MockContactsRepository)
+		tr := new(MockTemplateRepository)
+		tr.On("GetTemplateByID", mock.Anything, userID, templateID).Return(&models.Template{Body: "Hello, {{name}}"}, nil).Once()
+		cr.On("StreamContacts", mock.Anything, userID, domain.ContactFilter{}).Return(contacts[:2], nil).Once()
+		svc := service.NewSendNotificationService(cr, tr, new(MockRetryPolicyRepository), new(MockQuietHoursRepository), new(MockSegmentRepository), new(MockContactAttributeRepository), new(MockZoneRepository), new(MockCampaignRepository), new(MockKafkaWriter), 10, models.RetryPolicy{MaxAttempts: 1}, pricing, 3)
+
+		preview, err := svc.PreviewNotification(context.Background(), userID, templateID, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, preview.Recipients)
+		assert.Equal(t, smsutils.EncodingUCS2, preview.Encoding)
+		assert.Equal(t, "Hello, Иван", preview.Samples[0].Text)
+		assert.Equal(t, smsutils.EncodingUCS2, preview.Samples[0].Encoding)
+		assert.Equal(t, "Hello, Contact 2", preview.Samples[1].Text)
+		assert.Equal(t, smsutils.EncodingGSM7, preview.Samples[1].Encoding)
+		assert.Equal(t, 0.2, preview.EstimatedCost)
+		cr.AssertExpectations(t)
+	})
+
+	t.Run("rendered messages over the segment limit", func(t *testing.T) {
+		cr := new(MockContactsRepository)
+		tr := new(MockTemplateRepository)
+		tr.On("GetTemplateByID", mock.Anything, userID, templateID).Return(&models.Template{Body: "Go to {{shelter}}"}, nil).Once()
+		cr.On("StreamContacts", mock.Anything, userID, domain.ContactFilter{}).Return([]*models.Contact{
+			{ID: 1, Name: "A", Phone: "+79120000001", Attributes: map[string]string{"Shelter": strings.Repeat("a", 500)}},
+			{ID: 2, Name: "B", Phone: "+79120000002", Attributes: map[string]string{"Shelter": "School 5"}},
+		}, nil).Once()
+		svc := service.NewSendNotificationService(cr, tr, new(MockRetryPolicyRepository), new(MockQuietHoursRepository), new(MockSegmentRepository), new(MockContactAttributeRepository), new(MockZoneRepository), new(MockCampaignRepository), new(MockKafkaWriter), 10, models.RetryPolicy{MaxAttempts: 1}, pricing, 3)
+
+		preview, err := svc.PreviewNotification(context.Background(), userID, templateID, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, preview.SegmentsPerMessage)
+		assert.Equal(t, 3, preview.MaxSegments)
+		assert.Equal(t, 1, preview.RecipientsOverMaxSegments)
+		cr.AssertExpectations(t)
+	})
+
+	t.Run("contacts sharing a phone", func(t *testing.T) {
+		cr := new(MockContactsRepository)
+		tr := new(MockTemplateRepository)
+		tr.On("GetTemplateByID", mock.Anything, userID, templateID).Return(&models.Template{Body: "Evacuate"}, nil).Once()
+		cr.On("StreamContacts", mock.Anything, userID, domain.ContactFilter{}).Return([]*models.Contact{
+			{ID: 1, Name: "A", Phone: "+79120000001"},
+			{ID: 2, Name: "B", Phone: "+7 912 000-00-01"},
+			{ID: 3, Name: "C", Phone: "+79120000002"},
+		}, nil).Once()
+		svc := service.NewSendNotificationService(cr, tr, new(MockRetryPolicyRepository), new(MockQuietHoursRepository), new(MockSegmentRepository), new(MockContactAttributeRepository), new(MockZoneRepository), new(MockCampaignRepository), new(MockKafkaWriter), 10, models.RetryPolicy{MaxAttempts: 1}, pricing, 3)
+
+		preview, err := svc.PreviewNotification(context.Background(), userID, templateID, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, preview.Recipients)
+		assert.Equal(t, 2, preview.TotalSegments)
+		if assert.Len(t, preview.Samples, 2) {
+			assert.Equal(t, "A", preview.Samples[0].Name)
+			assert.Equal(t, "C", preview.Samples[1].Name)
+		}
+		cr.AssertExpectations(t)
+	})
+
+	t.Run("no recipients", func(t *testing.T) {
+		cr := new(MockContactsRepository)
+		tr := new(MockTemplateRepository)
+		tr.On("GetTemplateByID", mock.Anything, userID, templateID).Return(&models.Template{Body: "Evacuate"}, nil).Once()
+		cr.On("StreamContacts", mock.Anything, userID, domain.ContactFilter{}).Return([]*models.Contact{}, nil).Once()
+		svc := service.NewSendNotificationService(cr, tr, new(MockRetryPolicyRepository), new(MockQuietHoursRepository), new(MockSegmentRepository), new(MockContactAttributeRepository), new(MockZoneRepository), new(MockCampaignRepository), new(MockKafkaWriter), 10, models.RetryPolicy{MaxAttempts: 1}, pricing, 3)
+
+		_, err := svc.PreviewNotification(context.Background(), userID, templateID, nil)
+
+		assert.ErrorIs(t, err, domain.ErrContactNotExists)
+	})
+
+	t.Run("invalid priority", func(t *testing.T) {
+		svc := service.NewSendNotificationService(new(MockContactsRepository), new(MockTemplateRepository), new(MockRetryPolicyRepository), new(MockQuietHoursRepository), new(MockSegmentRepository), new(MockContactAttributeRepository), new(MockZoneRepository), new(MockCampaignRepository), new(MockKafkaWriter), 10, models.RetryPolicy{MaxAttempts: 1}, pricing, 3)
+
+		_, err := svc.PreviewNotification(context.Background(), userID, templateID, &domain.SendNotificationRequest{Priority: "urgent"})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidPriority)
+	})
+}
@@ -2,77 +2,128 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"regexp"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/smsutils"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/textdiff"
 )
 
+// senderIDPattern matches alphanumeric sender IDs as accepted by carriers:
+// up to 11 latin letters, digits and inner spaces.
+var senderIDPattern = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9 ]{0,9}[A-Za-z0-9])?$`)
+
+// senderIDLetter is required because all-digit sender IDs are treated as phone numbers.
+var senderIDLetter = regexp.MustCompile(`[A-Za-z]`)
+
+func isValidSenderID(id string) bool {
+	return senderIDPattern.MatchString(id) && senderIDLetter.MatchString(id)
+}
+
 // TemplateService provides operations for managing message templates.
+// Templates are returned along with the encoding and the SMS segments of their body.
 type TemplateService struct {
 	repository   domain.TemplateRepository
 	defaultLimit int
 	maxLimit     int
+	maxSegments  int
 }
 
 // NewTemplateService creates and returns a new TemplateService with the given template repository.
-func NewTemplateService(r domain.TemplateRepository, defaultLimit, maxLimit int) *TemplateService {
+// Template bodies may take at most maxSegments SMS segments.
+func NewTemplateService(r domain.TemplateRepository, defaultLimit, maxLimit, maxSegments int) *TemplateService {
 	return &TemplateService{
 		repository:   r,
 		defaultLimit: defaultLimit,
 		maxLimit:     maxLimit,
+		maxSegments:  maxSegments,
 	}
 }
 
-// GetTemplatesCountByUserID retrieves count of templates belonging to the specified user.
-func (ts *TemplateService) GetTemplatesCountByUserID(ctx context.Context, userID int) (int, error) {
-	return ts.repository.GetTemplatesCountByUserID(ctx, userID)
+// GetTemplatesCountByUserID retrieves count of the user's templates matching the filter.
+func (ts *TemplateService) GetTemplatesCountByUserID(ctx context.Context, userID int, filter domain.TemplateFilter) (int, error) {
+	return ts.repository.GetTemplatesCountByUserID(ctx, userID, filter)
 }
 
-// GetTemplatesPageByUserID retrieves page of templates belonging to the specified user.
-func (ts *TemplateService) GetTemplatesPageByUserID(ctx context.Context, userID, limit, offset int) ([]*models.Template, error) {
-	if limit <= 0 {
-		limit = ts.defaultLimit
-	}
-	if limit > ts.maxLimit {
-		limit = ts.maxLimit
+// GetTemplatesPageByUserID retrieves a page of the user's templates matching the filter and the
+// cursor of the next page. The limit is clamped to the configured maximum.
+func (ts *TemplateService) GetTemplatesPageByUserID(ctx context.Context, userID int, filter domain.TemplateFilter, page domain.PageRequest) ([]*models.Template, string, error) {
+	page.Limit, page.Offset = clampPage(page.Limit, page.Offset, ts.defaultLimit, ts.maxLimit)
+
+	templates, cursor, err := ts.repository.GetTemplatesPageByUserID(ctx, userID, filter, page)
+	if err != nil {
+		return nil, "", err
 	}
-	if offset < 0 {
-		offset = 0
+
+	for _, tmpl := range templates {
+		tmpl.Segmentation = smsutils.Segment(tmpl.Body)
 	}
-	return ts.repository.GetTemplatesPageByUserID(ctx, userID, limit, offset)
+
+	return templates, cursor, nil
 }
 
 // GetTemplateByID retrieves a specific template by its ID for the given user.
 func (ts *TemplateService) GetTemplateByID(ctx context.Context, userID int, tmplID int) (*models.Template, error) {
-	return ts.repository.GetTemplateByID(ctx, userID, tmplID)
+	return withSegmentation(ts.repository.GetTemplateByID(ctx, userID, tmplID))
 }
 
 // CreateTemplate validates and creates a new message template.
-// Returns the created Template model or a domain.ErrInvalidTemplate if body length is invalid.
+// Returns the created Template model or a domain.ErrInvalidTemplate if it is invalid.
 func (ts *TemplateService) CreateTemplate(ctx context.Context, tmpl *models.Template) (*models.Template, error) {
-	if len(tmpl.Name) == 0 || len(tmpl.Name) > 32 {
-		return nil, domain.ErrInvalidTemplate
-	}
-
-	if len(tmpl.Body) == 0 || len(tmpl.Body) > 256 {
-		return nil, domain.ErrInvalidTemplate
+	err := ts.validateTemplate(tmpl)
+	if err != nil {
+		return nil, err
 	}
 
-	return ts.repository.CreateTemplate(ctx, tmpl)
+	return withSegmentation(ts.repository.CreateTemplate(ctx, tmpl))
 }
 
 // UpdateTemplate validates and updates an existing message template for the user.
 // Returns the updated Template model or a domain.ErrInvalidTemplate / domain.ErrTemplateNotExists as appropriate.
 func (ts *TemplateService) UpdateTemplate(ctx context.Context, userID int, tmplID int, updatedTmpl *models.Template) (*models.Template, error) {
-	if len(updatedTmpl.Name) == 0 || len(updatedTmpl.Name) > 32 {
-		return nil, domain.ErrInvalidTemplate
+	err := ts.validateTemplate(updatedTmpl)
+	if err != nil {
+		return nil, err
+	}
+
+	return withSegmentation(ts.repository.UpdateTemplate(ctx, userID, tmplID, updatedTmpl))
+}
+
+// validateTemplate checks the name, the sender ID and the body of the template,
+// which must not be empty and may take at most maxSegments SMS segments. Placeholders are counted
+// as written; campaign previews report the messages that exceed the limit once rendered.
+func (ts *TemplateService) validateTemplate(tmpl *models.Template) error {
+	if len(tmpl.Name) == 0 || len(tmpl.Name) > 32 {
+		return fmt.Errorf("%w: name must be 1 to 32 characters long", domain.ErrInvalidTemplate)
+	}
+
+	if len(tmpl.Body) == 0 {
+		return fmt.Errorf("%w: body is empty", domain.ErrInvalidTemplate)
+	}
+
+	segmentation := smsutils.Segment(tmpl.Body)
+	if segmentation.Segments > ts.maxSegments {
+		return fmt.Errorf("%w: body takes %d SMS segments in %s, at most %d are allowed", domain.ErrInvalidTemplate, segmentation.Segments, segmentation.Encoding, ts.maxSegments)
 	}
 
-	if len(updatedTmpl.Body) == 0 || len(updatedTmpl.Body) > 256 {
-		return nil, domain.ErrInvalidTemplate
+	if tmpl.SenderID != "" && !isValidSenderID(tmpl.SenderID) {
+		return domain.ErrInvalidTemplateSenderID
 	}
 
-	return ts.repository.UpdateTemplate(ctx, userID, tmplID, updatedTmpl)
+	return nil
+}
+
+// withSegmentation sets the segmentation of the body of the template returned by a repository call.
+func withSegmentation(tmpl *models.Template, err error) (*models.Template, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl.Segmentation = smsutils.Segment(tmpl.Body)
+
+	return tmpl, nil
 }
 
 // DeleteTemplate removes the specified template for the user.
@@ -80,3 +131,57 @@ func (ts *TemplateService) UpdateTemplate(ctx context.Context, userID int, tmplI
 func (ts *TemplateService) DeleteTemplate(ctx context.Context, userID, tmplID int) error {
 	return ts.repository.DeleteTemplate(ctx, userID, tmplID)
 }
+
+// GetTemplateVersions retrieves every version of the user's template, newest first.
+// Returns domain.ErrTemplateNotExists if the template has no versions.
+func (ts *TemplateService) GetTemplateVersions(ctx context.Context, userID, tmplID int) ([]*models.TemplateVersion, error) {
+	return ts.repository.GetTemplateVersions(ctx, userID, tmplID)
+}
+
+// GetTemplateVersion retrieves a single version of the user's template.
+// Returns domain.ErrTemplateVersionNotExists if there is no such version.
+func (ts *TemplateService) GetTemplateVersion(ctx context.Context, userID, tmplID, version int) (*models.TemplateVersion, error) {
+	return ts.repository.GetTemplateVersion(ctx, userID, tmplID, version)
+}
+
+// DiffTemplateVersions compares two versions of the user's template word by word.
+// Returns domain.ErrTemplateVersionNotExists if either version doesn't exist.
+func (ts *TemplateService) DiffTemplateVersions(ctx context.Context, userID, tmplID, from, to int) (*domain.TemplateDiff, error) {
+	fromVersion, err := ts.repository.GetTemplateVersion(ctx, userID, tmplID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	toVersion, err := ts.repository.GetTemplateVersion(ctx, userID, tmplID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TemplateDiff{
+		TemplateID: tmplID,
+		From:       from,
+		To:         to,
+		Name:       textdiff.Diff(fromVersion.Name, toVersion.Name),
+		Body:       textdiff.Diff(fromVersion.Body, toVersion.Body),
+		SenderID:   textdiff.Diff(fromVersion.SenderID, toVersion.SenderID),
+	}, nil
+}
+
+// RestoreTemplateVersion brings the contents of a past version back into the user's template.
+// Versions are immutable, so the restored contents become a new version of the template rather
+// than discarding the versions after the restored one. The contents are validated against the
+// current limits, which may have changed since the version was created.
+// Returns domain.ErrTemplateVersionNotExists if there is no such version.
+func (ts *TemplateService) RestoreTemplateVersion(ctx context.Context, userID, tmplID, version int) (*models.Template, error) {
+	v, err := ts.repository.GetTemplateVersion(ctx, userID, tmplID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return ts.UpdateTemplate(ctx, userID, tmplID, &models.Template{
+		UserID:   userID,
+		Name:     v.Name,
+		Body:     v.Body,
+		SenderID: v.SenderID,
+	})
+}
@@ -8,19 +8,23 @@ import (
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
 	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/smsutils"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/textdiff"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
 func TestTemplateService_GetTemplatesCountByUserID(t *testing.T) {
+	filter := domain.TemplateFilter{Search: "alert"}
+
 	m := new(MockTemplateRepository)
 	m.
-		On("GetTemplatesCountByUserID", mock.Anything, 123).
+		On("GetTemplatesCountByUserID", mock.Anything, 123, filter).
 		Return(7, nil).
 		Once()
-	svc := service.NewTemplateService(m, 50, 100)
+	svc := service.NewTemplateService(m, 50, 100, 2)
 
-	count, err := svc.GetTemplatesCountByUserID(context.Background(), 123)
+	count, err := svc.GetTemplatesCountByUserID(context.Background(), 123, filter)
 	assert.NoError(t, err)
 	assert.Equal(t, 7, count)
 	m.AssertExpectations(t)
@@ -30,33 +34,37 @@ func TestTemplateService_GetTemplatesByUserID(t *testing.T) {
 	expected := []*models.Template{
 		{ID: 1, UserID: 42, Name: "T1", Body: "B1"},
 	}
+	sort := domain.Sort{Field: "createdAt"}
 	m := new(MockTemplateRepository)
 	m.
-		On("GetTemplatesPageByUserID", mock.Anything, 42, mock.Anything, mock.Anything).
-		Return(expected, nil).
+		On("GetTemplatesPageByUserID", mock.Anything, 42, domain.TemplateFilter{}, domain.PageRequest{Limit: 50, Sort: sort}).
+		Return(expected, "", nil).
 		Once()
 
-	svc := service.NewTemplateService(m, 50, 100)
-	out, err := svc.GetTemplatesPageByUserID(context.Background(), 42, 0, 0)
+	svc := service.NewTemplateService(m, 50, 100, 2)
+	out, next, err := svc.GetTemplatesPageByUserID(context.Background(), 42, domain.TemplateFilter{}, domain.PageRequest{Offset: -1, Sort: sort})
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, out)
+	assert.Equal(t, smsutils.Segmentation{Encoding: smsutils.EncodingGSM7, Length: 2, Segments: 1}, out[0].Segmentation)
+	assert.Empty(t, next)
 	m.AssertExpectations(t)
 }
 
 func TestTemplateService_GetTemplateByID(t *testing.T) {
-	expected := &models.Template{ID: 2, UserID: 42, Name: "T2", Body: "B2"}
+	expected := &models.Template{ID: 2, UserID: 42, Name: "T2", Body: "Внимание"}
 	m := new(MockTemplateRepository)
 	m.
 		On("GetTemplateByID", mock.Anything, 42, 2).
 		Return(expected, nil).
 		Once()
 
-	svc := service.NewTemplateService(m, 50, 100)
+	svc := service.NewTemplateService(m, 50, 100, 2)
 	out, err := svc.GetTemplateByID(context.Background(), 42, 2)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, out)
+	assert.Equal(t, smsutils.Segmentation{Encoding: smsutils.EncodingUCS2, Length: 8, Segments: 1}, out.Segmentation)
 	m.AssertExpectations(t)
 }
 
@@ -88,10 +96,55 @@ func TestTemplateService_CreateTemplate(t *testing.T) {
 			wantErr: domain.ErrInvalidTemplate,
 		},
 		{
-			name:    "body too long",
-			args:    args{tmpl: &models.Template{UserID: 1, Name: "n", Body: strings.Repeat("y", 257)}},
+			name:    "GSM-7 body over segment limit",
+			args:    args{tmpl: &models.Template{UserID: 1, Name: "n", Body: strings.Repeat("y", 307)}},
+			wantErr: domain.ErrInvalidTemplate,
+		},
+		{
+			name:    "UCS-2 body over segment limit",
+			args:    args{tmpl: &models.Template{UserID: 1, Name: "n", Body: strings.Repeat("я", 135)}},
 			wantErr: domain.ErrInvalidTemplate,
 		},
+		{
+			name: "UCS-2 body within segment limit",
+			args: args{tmpl: &models.Template{UserID: 1, Name: "n", Body: strings.Repeat("я", 134)}},
+			mockSetup: func(m *MockTemplateRepository) {
+				m.
+					On("CreateTemplate", mock.Anything, &models.Template{UserID: 1, Name: "n", Body: strings.Repeat("я", 134)}).
+					Return(&models.Template{ID: 99, UserID: 1, Name: "n", Body: strings.Repeat("я", 134)}, nil).
+					Once()
+			},
+			want: &models.Template{
+				ID: 99, UserID: 1, Name: "n", Body: strings.Repeat("я", 134),
+				Segmentation: smsutils.Segmentation{Encoding: smsutils.EncodingUCS2, Length: 134, Segments: 2},
+			},
+		},
+		{
+			name:    "sender id too long",
+			args:    args{tmpl: &models.Template{UserID: 1, Name: "n", Body: "b", SenderID: "ACMEALERTS12"}},
+			wantErr: domain.ErrInvalidTemplateSenderID,
+		},
+		{
+			name:    "sender id without letters",
+			args:    args{tmpl: &models.Template{UserID: 1, Name: "n", Body: "b", SenderID: "112"}},
+			wantErr: domain.ErrInvalidTemplateSenderID,
+		},
+		{
+			name:    "sender id with symbols",
+			args:    args{tmpl: &models.Template{UserID: 1, Name: "n", Body: "b", SenderID: "ACME-ALERT"}},
+			wantErr: domain.ErrInvalidTemplateSenderID,
+		},
+		{
+			name: "success with sender id",
+			args: args{tmpl: &models.Template{UserID: 1, Name: "n", Body: "b", SenderID: "City 112"}},
+			mockSetup: func(m *MockTemplateRepository) {
+				m.
+					On("CreateTemplate", mock.Anything, &models.Template{UserID: 1, Name: "n", Body: "b", SenderID: "City 112"}).
+					Return(&models.Template{ID: 99, UserID: 1, Name: "n", Body: "b", SenderID: "City 112"}, nil).
+					Once()
+			},
+			want: &models.Template{ID: 99, UserID: 1, Name: "n", Body: "b", SenderID: "City 112", Segmentation: smsutils.Segmentation{Encoding: smsutils.EncodingGSM7, Length: 1, Segments: 1}},
+		},
 		{
 			name: "repo error",
 			args: args{tmpl: &models.Template{UserID: 1, Name: "n", Body: "b"}},
@@ -113,7 +166,7 @@ func TestTemplateService_CreateTemplate(t *testing.T) {
 					Return(out, nil).
 					Once()
 			},
-			want: &models.Template{ID: 99, UserID: 1, Name: "n", Body: "b"},
+			want: &models.Template{ID: 99, UserID: 1, Name: "n", Body: "b", Segmentation: smsutils.Segmentation{Encoding: smsutils.EncodingGSM7, Length: 1, Segments: 1}},
 		},
 	}
 
@@ -123,7 +176,7 @@ func TestTemplateService_CreateTemplate(t *testing.T) {
 			if tc.mockSetup != nil {
 				tc.mockSetup(m)
 			}
-			svc := service.NewTemplateService(m, 50, 100)
+			svc := service.NewTemplateService(m, 50, 100, 2)
 
 			out, err := svc.CreateTemplate(context.Background(), tc.args.tmpl)
 			if tc.wantErr != nil {
@@ -162,6 +215,16 @@ func TestTemplateService_UpdateTemplate(t *testing.T) {
 			args:    args{userID: 1, tmplID: 2, update: &models.Template{UserID: 1, Name: "n", Body: ""}},
 			wantErr: domain.ErrInvalidTemplate,
 		},
+		{
+			name:    "body over segment limit",
+			args:    args{userID: 1, tmplID: 2, update: &models.Template{UserID: 1, Name: "n", Body: strings.Repeat("€", 154)}},
+			wantErr: domain.ErrInvalidTemplate,
+		},
+		{
+			name:    "invalid sender id",
+			args:    args{userID: 1, tmplID: 2, update: &models.Template{UserID: 1, Name: "n", Body: "b", SenderID: " ACME"}},
+			wantErr: domain.ErrInvalidTemplateSenderID,
+		},
 		{
 			name: "repo error",
 			args: args{userID: 1, tmplID: 2, update: &models.Template{UserID: 1, Name: "n", Body: "b"}},
@@ -183,7 +246,7 @@ func TestTemplateService_UpdateTemplate(t *testing.T) {
 					Return(out, nil).
 					Once()
 			},
-			want: &models.Template{ID: 2, UserID: 1, Name: "n", Body: "b"},
+			want: &models.Template{ID: 2, UserID: 1, Name: "n", Body: "b", Segmentation: smsutils.Segmentation{Encoding: smsutils.EncodingGSM7, Length: 1, Segments: 1}},
 		},
 	}
 
@@ -193,7 +256,7 @@ func TestTemplateService_UpdateTemplate(t *testing.T) {
 			if tc.mockSetup != nil {
 				tc.mockSetup(m)
 			}
-			svc := service.NewTemplateService(m, 50, 100)
+			svc := service.NewTemplateService(m, 50, 100, 2)
 
 			out, err := svc.UpdateTemplate(context.Background(), tc.args.userID, tc.args.tmplID, tc.args.update)
 			if tc.wantErr != nil {
@@ -216,9 +279,121 @@ func TestTemplateService_DeleteTemplate(t *testing.T) {
 		Return(nil).
 		Once()
 
-	svc := service.NewTemplateService(m, 50, 100)
+	svc := service.NewTemplateService(m, 50, 100, 2)
 	err := svc.DeleteTemplate(context.Background(), 1, 2)
 
 	assert.NoError(t, err)
 	m.AssertExpectations(t)
 }
+
+func TestTemplateService_GetTemplateVersions(t *testing.T) {
+	versions := []*models.TemplateVersion{
+		{TemplateID: 2, Version: 2, Name: "n", Body: "new"},
+		{TemplateID: 2, Version: 1, Name: "n", Body: "old"},
+	}
+
+	m := new(MockTemplateRepository)
+	m.On("GetTemplateVersions", mock.Anything, 1, 2).Return(versions, nil).Once()
+	m.On("GetTemplateVersions", mock.Anything, 1, 3).Return(nil, domain.ErrTemplateNotExists).Once()
+
+	svc := service.NewTemplateService(m, 50, 100, 2)
+
+	out, err := svc.GetTemplateVersions(context.Background(), 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, versions, out)
+
+	_, err = svc.GetTemplateVersions(context.Background(), 1, 3)
+	assert.ErrorIs(t, err, domain.ErrTemplateNotExists)
+
+	m.AssertExpectations(t)
+}
+
+func TestTemplateService_DiffTemplateVersions(t *testing.T) {
+	v1 := &models.TemplateVersion{TemplateID: 2, Version: 1, Name: "Flood", Body: "Leave the area now", SenderID: "City"}
+	v2 := &models.TemplateVersion{TemplateID: 2, Version: 2, Name: "Flood", Body: "Leave the district now"}
+
+	t.Run("success", func(t *testing.T) {
+		m := new(MockTemplateRepository)
+		m.On("GetTemplateVersion", mock.Anything, 1, 2, 1).Return(v1, nil).Once()
+		m.On("GetTemplateVersion", mock.Anything, 1, 2, 2).Return(v2, nil).Once()
+
+		svc := service.NewTemplateService(m, 50, 100, 2)
+		diff, err := svc.DiffTemplateVersions(context.Background(), 1, 2, 1, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.TemplateDiff{
+			TemplateID: 2,
+			From:       1,
+			To:         2,
+			Name:       []textdiff.Edit{{Op: textdiff.OpEqual, Text: "Flood"}},
+			Body: []textdiff.Edit{
+				{Op: textdiff.OpEqual, Text: "Leave the "},
+				{Op: textdiff.OpDelete, Text: "area"},
+				{Op: textdiff.OpInsert, Text: "district"},
+				{Op: textdiff.OpEqual, Text: " now"},
+			},
+			SenderID: []textdiff.Edit{{Op: textdiff.OpDelete, Text: "City"}},
+		}, diff)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("missing version", func(t *testing.T) {
+		m := new(MockTemplateRepository)
+		m.On("GetTemplateVersion", mock.Anything, 1, 2, 1).Return(v1, nil).Once()
+		m.On("GetTemplateVersion", mock.Anything, 1, 2, 9).Return(nil, domain.ErrTemplateVersionNotExists).Once()
+
+		svc := service.NewTemplateService(m, 50, 100, 2)
+		_, err := svc.DiffTemplateVersions(context.Background(), 1, 2, 1, 9)
+
+		assert.ErrorIs(t, err, domain.ErrTemplateVersionNotExists)
+		m.AssertExpectations(t)
+	})
+}
+
+func TestTemplateService_RestoreTemplateVersion(t *testing.T) {
+	t.Run("restores as a new version", func(t *testing.T) {
+		m := new(MockTemplateRepository)
+		m.
+			On("GetTemplateVersion", mock.Anything, 1, 2, 1).
+			Return(&models.TemplateVersion{TemplateID: 2, Version: 1, Name: "n", Body: "old", SenderID: "City"}, nil).
+			Once()
+		m.
+			On("UpdateTemplate", mock.Anything, 1, 2, &models.Template{UserID: 1, Name: "n", Body: "old", SenderID: "City"}).
+			Return(&models.Template{ID: 2, UserID: 1, Name: "n", Body: "old", SenderID: "City", Version: 4}, nil).
+			Once()
+
+		svc := service.NewTemplateService(m, 50, 100, 2)
+		out, err := svc.RestoreTemplateVersion(context.Background(), 1, 2, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, out.Version)
+		assert.Equal(t, "old", out.Body)
+		assert.Equal(t, smsutils.EncodingGSM7, out.Encoding)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("missing version", func(t *testing.T) {
+		m := new(MockTemplateRepository)
+		m.On("GetTemplateVersion", mock.Anything, 1, 2, 9).Return(nil, domain.ErrTemplateVersionNotExists).Once()
+
+		svc := service.NewTemplateService(m, 50, 100, 2)
+		_, err := svc.RestoreTemplateVersion(context.Background(), 1, 2, 9)
+
+		assert.ErrorIs(t, err, domain.ErrTemplateVersionNotExists)
+		m.AssertNotCalled(t, "UpdateTemplate", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("version exceeding the current segment limit", func(t *testing.T) {
+		m := new(MockTemplateRepository)
+		m.
+			On("GetTemplateVersion", mock.Anything, 1, 2, 1).
+			Return(&models.TemplateVersion{TemplateID: 2, Version: 1, Name: "n", Body: strings.Repeat("a", 400)}, nil).
+			Once()
+
+		svc := service.NewTemplateService(m, 50, 100, 2)
+		_, err := svc.RestoreTemplateVersion(context.Background(), 1, 2, 1)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidTemplate)
+		m.AssertNotCalled(t, "UpdateTemplate", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
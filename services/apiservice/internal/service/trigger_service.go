@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+)
+
+const (
+	maxTriggerNameLen     = 100
+	maxTriggerMatchers    = 20
+	maxAlertsPerWebhook   = 1000
+	alertFingerprintBytes = 8
+)
+
+// TriggerService manages the rules that turn alerts from monitoring into notifications,
+// and evaluates them against the alerts received by webhooks.
+type TriggerService struct {
+	repository         domain.TriggerRepository
+	templateRepository domain.TemplateRepository
+	sender             domain.SendNotificationService
+	approvals          domain.CampaignApprovalService
+	repeatInterval     time.Duration
+}
+
+// NewTriggerService creates a TriggerService. Notifications of fired triggers are sent through sns
+// unless as holds them for approval. An alert that keeps firing notifies again once repeatInterval
+// has passed; zero notifies only once.
+func NewTriggerService(r domain.TriggerRepository, tr domain.TemplateRepository, sns domain.SendNotificationService, as domain.CampaignApprovalService, repeatInterval time.Duration) *TriggerService {
+	return &TriggerService{
+		repository:         r,
+		templateRepository: tr,
+		sender:             sns,
+		approvals:          as,
+		repeatInterval:     repeatInterval,
+	}
+}
+
+// GetTriggersByUserID retrieves every trigger of the user.
+func (ts *TriggerService) GetTriggersByUserID(ctx context.Context, userID int) ([]*models.Trigger, error) {
+	return ts.repository.GetTriggersByUserID(ctx, userID)
+}
+
+// GetTriggerByID retrieves a trigger of the user.
+func (ts *TriggerService) GetTriggerByID(ctx context.Context, userID, triggerID int) (*models.Trigger, error) {
+	return ts.repository.GetTriggerByID(ctx, userID, triggerID)
+}
+
+// CreateTrigger validates the trigger and creates it.
+// Returns domain.ErrInvalidTriggerName, domain.ErrInvalidTriggerMatchers, domain.ErrInvalidContactGroup
+// or domain.ErrInvalidPriority for invalid triggers, and domain.ErrTemplateNotExists if the user
+// has no template or resolved template with the given ID.
+func (ts *TriggerService) CreateTrigger(ctx context.Context, trigger *models.Trigger) (*models.Trigger, error) {
+	err := ts.validateTrigger(ctx, trigger)
+	if err != nil {
+		return nil, err
+	}
+
+	return ts.repository.CreateTrigger(ctx, trigger)
+}
+
+// UpdateTrigger validates the trigger as CreateTrigger does and replaces the stored one.
+func (ts *TriggerService) UpdateTrigger(ctx context.Context, userID, triggerID int, trigger *models.Trigger) (*models.Trigger, error) {
+	trigger.UserID = userID
+	err := ts.validateTrigger(ctx, trigger)
+	if err != nil {
+		return nil, err
+	}
+
+	return ts.repository.UpdateTrigger(ctx, userID, triggerID, trigger)
+}
+
+// DeleteTrigger removes a trigger of the user.
+func (ts *TriggerService) DeleteTrigger(ctx context.Context, userID, triggerID int) error {
+	return ts.repository.DeleteTrigger(ctx, userID, triggerID)
+}
+
+// HandleAlerts evaluates the user's triggers against the alerts. A firing alert sends the template
+// of every trigger it matches, unless it already fired that trigger and hasn't resolved since, so
+// that repeated notifications of an alert send nothing. An alert with another startsAt, which means
+// its resolution was lost, fires again, and so does one that fired more than the repeat interval ago.
+// A resolved alert sends the resolved template of the triggers it fired, if they have one.
+// Returns an error wrapping domain.ErrInvalidWebhook, before anything is sent, if any alert has an
+// unknown status or no labels. If sending fails, the alert is left in its previous state, so that
+// the notification is sent when the webhook is retried.
+func (ts *TriggerService) HandleAlerts(ctx context.Context, userID int, alerts []*domain.WebhookAlert) (*domain.WebhookResult, error) {
+	if len(alerts) > maxAlertsPerWebhook {
+		return nil, fmt.Errorf("%w: more than %d alerts", domain.ErrInvalidWebhook, maxAlertsPerWebhook)
+	}
+	for _, a := range alerts {
+		if a.Status != domain.AlertStatusFiring && a.Status != domain.AlertStatusResolved {
+			return nil, fmt.Errorf("%w: unknown alert status %q", domain.ErrInvalidWebhook, a.Status)
+		}
+		if len(a.Labels) == 0 {
+			return nil, fmt.Errorf("%w: alert has no labels", domain.ErrInvalidWebhook)
+		}
+	}
+
+	triggers, err := ts.repository.GetTriggersByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.WebhookResult{}
+	for _, a := range alerts {
+		fingerprint := a.Fingerprint
+		if fingerprint == "" {
+			fingerprint = alertFingerprint(a.Labels)
+		}
+		startsAt := a.StartsAt
+		if startsAt != nil && startsAt.IsZero() {
+			startsAt = nil
+		}
+
+		matched := false
+		for _, t := range triggers {
+			if !matchLabels(t.Matchers, a.Labels) {
+				continue
+			}
+			matched = true
+
+			if a.Status == domain.AlertStatusFiring {
+				err = ts.fire(ctx, t, fingerprint, startsAt, result)
+			} else {
+				err = ts.resolve(ctx, t, fingerprint, startsAt, result)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		if !matched {
+			result.Unmatched++
+		}
+	}
+
+	return result, nil
+}
+
+func (ts *TriggerService) fire(ctx context.Context, t *models.Trigger, fingerprint string, startsAt *time.Time, result *domain.WebhookResult) error {
+	started, err := ts.repository.StartFiring(ctx, t.ID, fingerprint, startsAt, ts.repeatInterval)
+	if err != nil {
+		return err
+	}
+	if !started {
+		result.Deduplicated++
+		return nil
+	}
+
+	held, err := ts.send(ctx, t, t.TemplateID)
+	if err != nil {
+		_, rerr := ts.repository.ResolveFiring(context.WithoutCancel(ctx), t.ID, fingerprint)
+		return errors.Join(err, rerr)
+	}
+	if held {
+		result.PendingApproval++
+	}
+
+	result.Fired++
+	return nil
+}
+
+func (ts *TriggerService) resolve(ctx context.Context, t *models.Trigger, fingerprint string, startsAt *time.Time, result *domain.WebhookResult) error {
+	resolved, err := ts.repository.ResolveFiring(ctx, t.ID, fingerprint)
+	if err != nil {
+		return err
+	}
+	if !resolved {
+		result.Deduplicated++
+		return nil
+	}
+
+	if t.ResolvedTemplateID != nil {
+		held, err := ts.send(ctx, t, *t.ResolvedTemplateID)
+		if err != nil {
+			_, serr := ts.repository.StartFiring(context.WithoutCancel(ctx), t.ID, fingerprint, startsAt, 0)
+			return errors.Join(err, serr)
+		}
+		if held {
+			result.PendingApproval++
+		}
+	}
+
+	result.Resolved++
+	return nil
+}
+
+// send sends the template to the target of the trigger, or holds it for approval if the approval
+// policy of the user requires it, like any other campaign, and reports whether it was held.
+// A trigger whose group has no contacts sends nothing, which isn't an error.
+func (ts *TriggerService) send(ctx context.Context, t *models.Trigger, templateID int) (bool, error) {
+	opts := &domain.SendNotificationRequest{Priority: t.Priority}
+	if t.Group != "" {
+		opts.Filter = &domain.ContactFilter{Group: t.Group}
+	}
+
+	approval, err := ts.approvals.RequestApproval(ctx, t.UserID, templateID, opts)
+	if err == nil && approval == nil {
+		err = ts.sender.SendNotification(ctx, t.UserID, templateID, opts)
+	}
+	if err != nil && !errors.Is(err, domain.ErrContactNotExists) {
+		return false, err
+	}
+
+	return approval != nil, nil
+}
+
+// validateTrigger trims the name and group of the trigger, defaults its priority to critical
+// and checks its fields and that its templates exist.
+func (ts *TriggerService) validateTrigger(ctx context.Context, trigger *models.Trigger) error {
+	trigger.Name = strings.TrimSpace(trigger.Name)
+	if trigger.Name == "" || utf8.RuneCountInString(trigger.Name) > maxTriggerNameLen {
+		return domain.ErrInvalidTriggerName
+	}
+
+	err := validateMatchers(trigger.Matchers)
+	if err != nil {
+		return err
+	}
+
+	trigger.Group = strings.TrimSpace(trigger.Group)
+	if len(trigger.Group) > maxContactAttributeLen {
+		return domain.ErrInvalidContactGroup
+	}
+
+	switch trigger.Priority {
+	case "":
+		trigger.Priority = models.PriorityCritical
+	case models.PriorityCritical, models.PriorityNormal:
+	default:
+		return domain.ErrInvalidPriority
+	}
+
+	_, err = ts.templateRepository.GetTemplateByID(ctx, trigger.UserID, trigger.TemplateID)
+	if err != nil {
+		return err
+	}
+	if trigger.ResolvedTemplateID != nil {
+		_, err = ts.templateRepository.GetTemplateByID(ctx, trigger.UserID, *trigger.ResolvedTemplateID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateMatchers checks that there is at least one matcher and that every matcher has a label
+// name, a known operator and, for regular expressions, a valid one.
+func validateMatchers(matchers []models.LabelMatcher) error {
+	if len(matchers) == 0 || len(matchers) > maxTriggerMatchers {
+		return fmt.Errorf("%w: from 1 to %d matchers are allowed", domain.ErrInvalidTriggerMatchers, maxTriggerMatchers)
+	}
+
+	for _, m := range matchers {
+		if m.Name == "" {
+			return fmt.Errorf("%w: empty label name", domain.ErrInvalidTriggerMatchers)
+		}
+		switch m.Type {
+		case models.MatchEqual, models.MatchNotEqual:
+		case models.MatchRegexp, models.MatchNotRegexp:
+			_, err := compileMatcherRegexp(m.Value)
+			if err != nil {
+				return fmt.Errorf("%w: %v", domain.ErrInvalidTriggerMatchers, err)
+			}
+		default:
+			return fmt.Errorf("%w: unknown match type %q", domain.ErrInvalidTriggerMatchers, m.Type)
+		}
+	}
+
+	return nil
+}
+
+// matchLabels reports whether the labels match all matchers. A missing label is matched as
+// empty, so {severity!="info"} matches alerts without a severity, just like in Alertmanager.
+func matchLabels(matchers []models.LabelMatcher, labels map[string]string) bool {
+	for _, m := range matchers {
+		value := labels[m.Name]
+
+		var ok bool
+		switch m.Type {
+		case models.MatchEqual:
+			ok = value == m.Value
+		case models.MatchNotEqual:
+			ok = value != m.Value
+		case models.MatchRegexp, models.MatchNotRegexp:
+			re, err := compileMatcherRegexp(m.Value)
+			ok = err == nil && re.MatchString(value) == (m.Type == models.MatchRegexp)
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// compileMatcherRegexp compiles the regular expression of a matcher, anchored at both ends.
+func compileMatcherRegexp(expr string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + expr + ")$")
+}
+
+// alertFingerprint identifies an alert by its labels, for webhooks that don't send fingerprints.
+func alertFingerprint(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		h.Write([]byte(name))
+		h.Write([]byte{0xff})
+		h.Write([]byte(labels[name]))
+		h.Write([]byte{0xff})
+	}
+
+	return hex.EncodeToString(h.Sum(nil)[:alertFingerprintBytes])
+}
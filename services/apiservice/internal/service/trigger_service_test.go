@@ -0,0 +1,354 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTriggerService_CreateTrigger(t *testing.T) {
+	critical := []models.LabelMatcher{{Name: "severity", Type: models.MatchEqual, Value: "critical"}}
+	resolvedID := 6
+
+	tests := map[string]struct {
+		trigger   *models.Trigger
+		templates map[int]error
+		want      *models.Trigger
+		wantErr   error
+	}{
+		"defaults": {
+			trigger:   &models.Trigger{UserID: 1, Name: " Disk full ", Matchers: critical, TemplateID: 5, Group: " ops "},
+			templates: map[int]error{5: nil},
+			want:      &models.Trigger{UserID: 1, Name: "Disk full", Matchers: critical, TemplateID: 5, Group: "ops", Priority: models.PriorityCritical},
+		},
+		"resolved template and regexp": {
+			trigger: &models.Trigger{UserID: 1, Name: "API down", TemplateID: 5, ResolvedTemplateID: &resolvedID, Priority: models.PriorityNormal, Matchers: []models.LabelMatcher{
+				{Name: "job", Type: models.MatchRegexp, Value: "api|gateway"},
+			}},
+			templates: map[int]error{5: nil, 6: nil},
+			want: &models.Trigger{UserID: 1, Name: "API down", TemplateID: 5, ResolvedTemplateID: &resolvedID, Priority: models.PriorityNormal, Matchers: []models.LabelMatcher{
+				{Name: "job", Type: models.MatchRegexp, Value: "api|gateway"},
+			}},
+		},
+		"empty name": {
+			trigger: &models.Trigger{UserID: 1, Name: " ", Matchers: critical, TemplateID: 5},
+			wantErr: domain.ErrInvalidTriggerName,
+		},
+		"name too long": {
+			trigger: &models.Trigger{UserID: 1, Name: strings.Repeat("я", 101), Matchers: critical, TemplateID: 5},
+			wantErr: domain.ErrInvalidTriggerName,
+		},
+		"no matchers": {
+			trigger: &models.Trigger{UserID: 1, Name: "All", TemplateID: 5},
+			wantErr: domain.ErrInvalidTriggerMatchers,
+		},
+		"unknown match type": {
+			trigger: &models.Trigger{UserID: 1, Name: "Like", TemplateID: 5, Matchers: []models.LabelMatcher{{Name: "job", Type: "~", Value: "api"}}},
+			wantErr: domain.ErrInvalidTriggerMatchers,
+		},
+		"invalid regexp": {
+			trigger: &models.Trigger{UserID: 1, Name: "Broken", TemplateID: 5, Matchers: []models.LabelMatcher{{Name: "job", Type: models.MatchNotRegexp, Value: "api("}}},
+			wantErr: domain.ErrInvalidTriggerMatchers,
+		},
+		"empty label name": {
+			trigger: &models.Trigger{UserID: 1, Name: "Nameless", TemplateID: 5, Matchers: []models.LabelMatcher{{Type: models.MatchEqual, Value: "api"}}},
+			wantErr: domain.ErrInvalidTriggerMatchers,
+		},
+		"unknown priority": {
+			trigger: &models.Trigger{UserID: 1, Name: "Urgent", Matchers: critical, TemplateID: 5, Priority: "urgent"},
+			wantErr: domain.ErrInvalidPriority,
+		},
+		"group too long": {
+			trigger: &models.Trigger{UserID: 1, Name: "Ops", Matchers: critical, TemplateID: 5, Group: strings.Repeat("g", 1025)},
+			wantErr: domain.ErrInvalidContactGroup,
+		},
+		"unknown template": {
+			trigger:   &models.Trigger{UserID: 1, Name: "Disk full", Matchers: critical, TemplateID: 5},
+			templates: map[int]error{5: domain.ErrTemplateNotExists},
+			wantErr:   domain.ErrTemplateNotExists,
+		},
+		"unknown resolved template": {
+			trigger:   &models.Trigger{UserID: 1, Name: "Disk full", Matchers: critical, TemplateID: 5, ResolvedTemplateID: &resolvedID},
+			templates: map[int]error{5: nil, 6: domain.ErrTemplateNotExists},
+			wantErr:   domain.ErrTemplateNotExists,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			tr := new(MockTriggerRepository)
+			tmplr := new(MockTemplateRepository)
+			for id, err := range tc.templates {
+				if err != nil {
+					tmplr.On("GetTemplateByID", mock.Anything, 1, id).Return((*models.Template)(nil), err).Once()
+				} else {
+					tmplr.On("GetTemplateByID", mock.Anything, 1, id).Return(&models.Template{ID: id, UserID: 1}, nil).Once()
+				}
+			}
+			if tc.want != nil {
+				tr.On("CreateTrigger", mock.Anything, tc.want).Return(tc.want, nil).Once()
+			}
+			svc := service.NewTriggerService(tr, tmplr, nil, noApprovals(), 0)
+
+			got, err := svc.CreateTrigger(context.Background(), tc.trigger)
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Nil(t, got)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.want, got)
+			}
+			tr.AssertExpectations(t)
+			tmplr.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTriggerService_UpdateTrigger_NotFound(t *testing.T) {
+	trigger := &models.Trigger{Name: "Disk full", TemplateID: 5, Matchers: []models.LabelMatcher{{Name: "alertname", Type: models.MatchEqual, Value: "DiskFull"}}}
+	tr := new(MockTriggerRepository)
+	tmplr := new(MockTemplateRepository)
+	tmplr.On("GetTemplateByID", mock.Anything, 1, 5).Return(&models.Template{ID: 5, UserID: 1}, nil).Once()
+	tr.On("UpdateTrigger", mock.Anything, 1, 3, mock.MatchedBy(func(t *models.Trigger) bool {
+		return t.UserID == 1 && t.Priority == models.PriorityCritical
+	})).Return(nil, domain.ErrTriggerNotExists).Once()
+	svc := service.NewTriggerService(tr, tmplr, nil, noApprovals(), 0)
+
+	_, err := svc.UpdateTrigger(context.Background(), 1, 3, trigger)
+
+	assert.ErrorIs(t, err, domain.ErrTriggerNotExists)
+	tr.AssertExpectations(t)
+	tmplr.AssertExpectations(t)
+}
+
+func TestTriggerService_HandleAlerts(t *testing.T) {
+	resolvedID := 8
+	diskFull := &models.Trigger{
+		ID: 1, UserID: 1, Name: "Disk full", TemplateID: 7, ResolvedTemplateID: &resolvedID, Group: "ops", Priority: models.PriorityCritical,
+		Matchers: []models.LabelMatcher{
+			{Name: "alertname", Type: models.MatchEqual, Value: "DiskFull"},
+			{Name: "severity", Type: models.MatchNotEqual, Value: "info"},
+		},
+	}
+	apiDown := &models.Trigger{
+		ID: 2, UserID: 1, Name: "API down", TemplateID: 9, Priority: models.PriorityNormal,
+		Matchers: []models.LabelMatcher{
+			{Name: "job", Type: models.MatchRegexp, Value: "api|gateway"},
+			{Name: "env", Type: models.MatchNotRegexp, Value: "dev.*"},
+		},
+	}
+	triggers := []*models.Trigger{diskFull, apiDown}
+	diskOpts := &domain.SendNotificationRequest{Priority: models.PriorityCritical, Filter: &domain.ContactFilter{Group: "ops"}}
+	apiOpts := &domain.SendNotificationRequest{Priority: models.PriorityNormal}
+
+	t.Run("fires matching triggers", func(t *testing.T) {
+		tr := new(MockTriggerRepository)
+		sns := new(MockSendNotificationService)
+		tr.On("GetTriggersByUserID", mock.Anything, 1).Return(triggers, nil).Once()
+		tr.On("StartFiring", mock.Anything, 1, "disk", (*time.Time)(nil), time.Hour).Return(true, nil).Once()
+		tr.On("StartFiring", mock.Anything, 2, mock.AnythingOfType("string"), (*time.Time)(nil), time.Hour).Return(true, nil).Once()
+		sns.On("SendNotification", mock.Anything, 1, 7, diskOpts).Return(nil).Once()
+		sns.On("SendNotification", mock.Anything, 1, 9, apiOpts).Return(nil).Once()
+		svc := service.NewTriggerService(tr, nil, sns, noApprovals(), time.Hour)
+
+		res, err := svc.HandleAlerts(context.Background(), 1, []*domain.WebhookAlert{
+			{Status: domain.AlertStatusFiring, Fingerprint: "disk", Labels: map[string]string{"alertname": "DiskFull", "severity": "critical"}},
+			{Status: domain.AlertStatusFiring, Labels: map[string]string{"alertname": "Down", "job": "gateway", "env": "prod"}},
+			{Status: domain.AlertStatusFiring, Labels: map[string]string{"alertname": "Down", "job": "api-internal"}},
+			{Status: domain.AlertStatusFiring, Labels: map[string]string{"alertname": "Down", "job": "api", "env": "dev-2"}},
+			{Status: domain.AlertStatusFiring, Labels: map[string]string{"alertname": "DiskFull", "severity": "info"}},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, &domain.WebhookResult{Fired: 2, Unmatched: 3}, res)
+		tr.AssertExpectations(t)
+		sns.AssertExpectations(t)
+	})
+
+	t.Run("holds campaigns for approval", func(t *testing.T) {
+		tr := new(MockTriggerRepository)
+		sns := new(MockSendNotificationService)
+		as := new(MockCampaignApprovalService)
+		tr.On("GetTriggersByUserID", mock.Anything, 1).Return(triggers, nil).Once()
+		tr.On("StartFiring", mock.Anything, 1, "disk", (*time.Time)(nil), time.Hour).Return(true, nil).Once()
+		as.On("RequestApproval", mock.Anything, 1, 7, diskOpts).Return(&models.CampaignApproval{ID: 3, Status: models.ApprovalPending}, nil).Once()
+		svc := service.NewTriggerService(tr, nil, sns, as, time.Hour)
+
+		res, err := svc.HandleAlerts(context.Background(), 1, []*domain.WebhookAlert{
+			{Status: domain.AlertStatusFiring, Fingerprint: "disk", Labels: map[string]string{"alertname": "DiskFull"}},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, &domain.WebhookResult{Fired: 1, PendingApproval: 1}, res)
+		tr.AssertExpectations(t)
+		as.AssertExpectations(t)
+		sns.AssertNotCalled(t, "SendNotification", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("deduplicates repeated firing", func(t *testing.T) {
+		tr := new(MockTriggerRepository)
+		sns := new(MockSendNotificationService)
+		tr.On("GetTriggersByUserID", mock.Anything, 1).Return(triggers, nil).Once()
+		tr.On("StartFiring", mock.Anything, 1, "disk", (*time.Time)(nil), time.Hour).Return(false, nil).Once()
+		svc := service.NewTriggerService(tr, nil, sns, noApprovals(), time.Hour)
+
+		res, err := svc.HandleAlerts(context.Background(), 1, []*domain.WebhookAlert{
+			{Status: domain.AlertStatusFiring, Fingerprint: "disk", Labels: map[string]string{"alertname": "DiskFull"}},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, &domain.WebhookResult{Deduplicated: 1}, res)
+		tr.AssertExpectations(t)
+		sns.AssertExpectations(t)
+	})
+
+	t.Run("passes alert start and repeat interval", func(t *testing.T) {
+		startsAt := time.Date(2026, 10, 19, 8, 30, 0, 0, time.UTC)
+		tr := new(MockTriggerRepository)
+		sns := new(MockSendNotificationService)
+		tr.On("GetTriggersByUserID", mock.Anything, 1).Return(triggers, nil).Once()
+		tr.On("StartFiring", mock.Anything, 1, "disk", &startsAt, 30*time.Minute).Return(true, nil).Once()
+		tr.On("StartFiring", mock.Anything, 2, "api", (*time.Time)(nil), 30*time.Minute).Return(false, nil).Once()
+		sns.On("SendNotification", mock.Anything, 1, 7, diskOpts).Return(nil).Once()
+		svc := service.NewTriggerService(tr, nil, sns, noApprovals(), 30*time.Minute)
+
+		res, err := svc.HandleAlerts(context.Background(), 1, []*domain.WebhookAlert{
+			{Status: domain.AlertStatusFiring, Fingerprint: "disk", StartsAt: &startsAt, Labels: map[string]string{"alertname": "DiskFull"}},
+			// Alertmanager sends the zero time for unknown timestamps
+			{Status: domain.AlertStatusFiring, Fingerprint: "api", StartsAt: &time.Time{}, Labels: map[string]string{"job": "api"}},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, &domain.WebhookResult{Fired: 1, Deduplicated: 1}, res)
+		tr.AssertExpectations(t)
+		sns.AssertExpectations(t)
+	})
+
+	t.Run("fingerprint derived from labels is stable", func(t *testing.T) {
+		var fingerprints []string
+		tr := new(MockTriggerRepository)
+		sns := new(MockSendNotificationService)
+		tr.On("GetTriggersByUserID", mock.Anything, 1).Return(triggers, nil).Twice()
+		tr.On("StartFiring", mock.Anything, 2, mock.AnythingOfType("string"), (*time.Time)(nil), time.Hour).Run(func(args mock.Arguments) {
+			fingerprints = append(fingerprints, args.String(2))
+		}).Return(true, nil).Times(3)
+		sns.On("SendNotification", mock.Anything, 1, 9, apiOpts).Return(nil).Times(3)
+		svc := service.NewTriggerService(tr, nil, sns, noApprovals(), time.Hour)
+
+		_, err := svc.HandleAlerts(context.Background(), 1, []*domain.WebhookAlert{
+			{Status: domain.AlertStatusFiring, Labels: map[string]string{"job": "api", "instance": "a"}},
+			{Status: domain.AlertStatusFiring, Labels: map[string]string{"job": "api", "instance": "b"}},
+		})
+		require.NoError(t, err)
+		_, err = svc.HandleAlerts(context.Background(), 1, []*domain.WebhookAlert{
+			{Status: domain.AlertStatusFiring, Labels: map[string]string{"instance": "a", "job": "api"}},
+		})
+		require.NoError(t, err)
+
+		require.Len(t, fingerprints, 3)
+		assert.NotEqual(t, fingerprints[0], fingerprints[1])
+		assert.Equal(t, fingerprints[0], fingerprints[2])
+	})
+
+	t.Run("resolved sends resolved template", func(t *testing.T) {
+		tr := new(MockTriggerRepository)
+		sns := new(MockSendNotificationService)
+		tr.On("GetTriggersByUserID", mock.Anything, 1).Return(triggers, nil).Once()
+		tr.On("ResolveFiring", mock.Anything, 1, "disk").Return(true, nil).Once()
+		tr.On("ResolveFiring", mock.Anything, 1, "other").Return(false, nil).Once()
+		tr.On("ResolveFiring", mock.Anything, 2, "api").Return(true, nil).Once()
+		sns.On("SendNotification", mock.Anything, 1, 8, diskOpts).Return(nil).Once()
+		svc := service.NewTriggerService(tr, nil, sns, noApprovals(), time.Hour)
+
+		res, err := svc.HandleAlerts(context.Background(), 1, []*domain.WebhookAlert{
+			{Status: domain.AlertStatusResolved, Fingerprint: "disk", Labels: map[string]string{"alertname": "DiskFull"}},
+			{Status: domain.AlertStatusResolved, Fingerprint: "other", Labels: map[string]string{"alertname": "DiskFull"}},
+			{Status: domain.AlertStatusResolved, Fingerprint: "api", Labels: map[string]string{"job": "api"}},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, &domain.WebhookResult{Resolved: 2, Deduplicated: 1}, res)
+		tr.AssertExpectations(t)
+		sns.AssertExpectations(t)
+	})
+
+	t.Run("group without contacts", func(t *testing.T) {
+		tr := new(MockTriggerRepository)
+		sns := new(MockSendNotificationService)
+		tr.On("GetTriggersByUserID", mock.Anything, 1).Return(triggers, nil).Once()
+		tr.On("StartFiring", mock.Anything, 1, "disk", (*time.Time)(nil), time.Hour).Return(true, nil).Once()
+		sns.On("SendNotification", mock.Anything, 1, 7, diskOpts).Return(domain.ErrContactNotExists).Once()
+		svc := service.NewTriggerService(tr, nil, sns, noApprovals(), time.Hour)
+
+		res, err := svc.HandleAlerts(context.Background(), 1, []*domain.WebhookAlert{
+			{Status: domain.AlertStatusFiring, Fingerprint: "disk", Labels: map[string]string{"alertname": "DiskFull"}},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, &domain.WebhookResult{Fired: 1}, res)
+		tr.AssertExpectations(t)
+	})
+
+	t.Run("send failure rolls back firing", func(t *testing.T) {
+		tr := new(MockTriggerRepository)
+		sns := new(MockSendNotificationService)
+		tr.On("GetTriggersByUserID", mock.Anything, 1).Return(triggers, nil).Once()
+		tr.On("StartFiring", mock.Anything, 1, "disk", (*time.Time)(nil), time.Hour).Return(true, nil).Once()
+		tr.On("ResolveFiring", mock.Anything, 1, "disk").Return(true, nil).Once()
+		sns.On("SendNotification", mock.Anything, 1, 7, diskOpts).Return(assert.AnError).Once()
+		svc := service.NewTriggerService(tr, nil, sns, noApprovals(), time.Hour)
+
+		_, err := svc.HandleAlerts(context.Background(), 1, []*domain.WebhookAlert{
+			{Status: domain.AlertStatusFiring, Fingerprint: "disk", Labels: map[string]string{"alertname": "DiskFull"}},
+		})
+
+		assert.ErrorIs(t, err, assert.AnError)
+		tr.AssertExpectations(t)
+	})
+
+	t.Run("send failure of resolved restores firing", func(t *testing.T) {
+		tr := new(MockTriggerRepository)
+		sns := new(MockSendNotificationService)
+		tr.On("GetTriggersByUserID", mock.Anything, 1).Return(triggers, nil).Once()
+		tr.On("ResolveFiring", mock.Anything, 1, "disk").Return(true, nil).Once()
+		tr.On("StartFiring", mock.Anything, 1, "disk", (*time.Time)(nil), time.Duration(0)).Return(true, nil).Once()
+		sns.On("SendNotification", mock.Anything, 1, 8, diskOpts).Return(assert.AnError).Once()
+		svc := service.NewTriggerService(tr, nil, sns, noApprovals(), time.Hour)
+
+		_, err := svc.HandleAlerts(context.Background(), 1, []*domain.WebhookAlert{
+			{Status: domain.AlertStatusResolved, Fingerprint: "disk", Labels: map[string]string{"alertname": "DiskFull"}},
+		})
+
+		assert.ErrorIs(t, err, assert.AnError)
+		tr.AssertExpectations(t)
+	})
+
+	t.Run("invalid alerts", func(t *testing.T) {
+		for name, alert := range map[string]*domain.WebhookAlert{
+			"unknown status": {Status: "pending", Labels: map[string]string{"alertname": "DiskFull"}},
+			"no labels":      {Status: domain.AlertStatusFiring},
+		} {
+			t.Run(name, func(t *testing.T) {
+				tr := new(MockTriggerRepository)
+				svc := service.NewTriggerService(tr, nil, nil, noApprovals(), 0)
+
+				_, err := svc.HandleAlerts(context.Background(), 1, []*domain.WebhookAlert{
+					{Status: domain.AlertStatusFiring, Labels: map[string]string{"alertname": "DiskFull"}},
+					alert,
+				})
+
+				assert.ErrorIs(t, err, domain.ErrInvalidWebhook)
+				tr.AssertExpectations(t)
+			})
+		}
+	})
+}
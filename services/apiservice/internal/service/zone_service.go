@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/geo"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+)
+
+const maxZoneNameLen = 100
+
+// ZoneService validates and manages the named areas users target notifications at.
+type ZoneService struct {
+	repository domain.ZoneRepository
+}
+
+// NewZoneService creates a ZoneService with the given repository.
+func NewZoneService(r domain.ZoneRepository) *ZoneService {
+	return &ZoneService{
+		repository: r,
+	}
+}
+
+// GetZonesByUserID retrieves every zone of the user.
+func (zs *ZoneService) GetZonesByUserID(ctx context.Context, userID int) ([]*models.Zone, error) {
+	return zs.repository.GetZonesByUserID(ctx, userID)
+}
+
+// GetZoneByID retrieves a zone of the user.
+func (zs *ZoneService) GetZoneByID(ctx context.Context, userID, zoneID int) (*models.Zone, error) {
+	return zs.repository.GetZoneByID(ctx, userID, zoneID)
+}
+
+// CreateZone validates the name and area of the zone and creates it.
+// Returns domain.ErrInvalidZoneName or an error wrapping domain.ErrInvalidArea for invalid zones.
+func (zs *ZoneService) CreateZone(ctx context.Context, zone *models.Zone) (*models.Zone, error) {
+	err := validateZone(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	return zs.repository.CreateZone(ctx, zone)
+}
+
+// UpdateZone validates the name and area of the zone and replaces the stored one.
+func (zs *ZoneService) UpdateZone(ctx context.Context, userID, zoneID int, zone *models.Zone) (*models.Zone, error) {
+	err := validateZone(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	return zs.repository.UpdateZone(ctx, userID, zoneID, zone)
+}
+
+// DeleteZone removes a zone of the user.
+func (zs *ZoneService) DeleteZone(ctx context.Context, userID, zoneID int) error {
+	return zs.repository.DeleteZone(ctx, userID, zoneID)
+}
+
+// validateZone trims the name of the zone and checks it and the area.
+func validateZone(zone *models.Zone) error {
+	zone.Name = strings.TrimSpace(zone.Name)
+	if zone.Name == "" || utf8.RuneCountInString(zone.Name) > maxZoneNameLen {
+		return domain.ErrInvalidZoneName
+	}
+
+	return validateArea(&zone.Area)
+}
+
+// validateArea checks the area, returning an error wrapping domain.ErrInvalidArea that
+// describes the problem.
+func validateArea(area *geo.Area) error {
+	err := area.Validate()
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrInvalidArea, err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/geo"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestZoneService_CreateZone(t *testing.T) {
+	circle := geo.Area{Circle: &geo.Circle{Lat: 55.75, Lon: 37.62, Radius: 2000}}
+	polygon := geo.Area{Polygon: &geo.Geometry{
+		Type:        geo.GeometryPolygon,
+		Coordinates: []byte(`[[[37.5,55.7],[37.7,55.7],[37.7,55.8],[37.5,55.7]]]`),
+	}}
+
+	tests := map[string]struct {
+		zone    *models.Zone
+		want    *models.Zone
+		wantErr error
+	}{
+		"circle": {
+			zone: &models.Zone{UserID: 1, Name: " Center ", Area: circle},
+			want: &models.Zone{UserID: 1, Name: "Center", Area: circle},
+		},
+		"polygon": {
+			zone: &models.Zone{UserID: 1, Name: "District", Area: polygon},
+			want: &models.Zone{UserID: 1, Name: "District", Area: polygon},
+		},
+		"empty name": {
+			zone:    &models.Zone{UserID: 1, Name: "  ", Area: circle},
+			wantErr: domain.ErrInvalidZoneName,
+		},
+		"name too long": {
+			zone:    &models.Zone{UserID: 1, Name: strings.Repeat("я", 101), Area: circle},
+			wantErr: domain.ErrInvalidZoneName,
+		},
+		"no area": {
+			zone:    &models.Zone{UserID: 1, Name: "Empty"},
+			wantErr: domain.ErrInvalidArea,
+		},
+		"radius out of range": {
+			zone:    &models.Zone{UserID: 1, Name: "Huge", Area: geo.Area{Circle: &geo.Circle{Lat: 55.75, Lon: 37.62, Radius: geo.MaxRadius + 1}}},
+			wantErr: domain.ErrInvalidArea,
+		},
+		"open ring": {
+			zone: &models.Zone{UserID: 1, Name: "Open", Area: geo.Area{Polygon: &geo.Geometry{
+				Type:        geo.GeometryPolygon,
+				Coordinates: []byte(`[[[37.5,55.7],[37.7,55.7],[37.7,55.8],[37.5,55.8]]]`),
+			}}},
+			wantErr: domain.ErrInvalidArea,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			zr := new(MockZoneRepository)
+			if tc.want != nil {
+				zr.On("CreateZone", mock.Anything, tc.want).Return(tc.want, nil).Once()
+			}
+			svc := service.NewZoneService(zr)
+
+			got, err := svc.CreateZone(context.Background(), tc.zone)
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Nil(t, got)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.want, got)
+			}
+			zr.AssertExpectations(t)
+		})
+	}
+}
+
+func TestZoneService_UpdateZone(t *testing.T) {
+	area := geo.Area{Circle: &geo.Circle{Lat: 55.75, Lon: 37.62, Radius: 2000}}
+
+	t.Run("not found", func(t *testing.T) {
+		zone := &models.Zone{UserID: 1, Name: "Center", Area: area}
+		zr := new(MockZoneRepository)
+		zr.On("UpdateZone", mock.Anything, 1, 3, zone).Return(nil, domain.ErrZoneNotExists).Once()
+		svc := service.NewZoneService(zr)
+
+		_, err := svc.UpdateZone(context.Background(), 1, 3, zone)
+
+		assert.ErrorIs(t, err, domain.ErrZoneNotExists)
+		zr.AssertExpectations(t)
+	})
+
+	t.Run("invalid area", func(t *testing.T) {
+		zr := new(MockZoneRepository)
+		svc := service.NewZoneService(zr)
+
+		_, err := svc.UpdateZone(context.Background(), 1, 3, &models.Zone{UserID: 1, Name: "Center", Area: geo.Area{
+			Circle: &geo.Circle{Lat: 91, Lon: 0, Radius: 10},
+		}})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidArea)
+		zr.AssertExpectations(t)
+	})
+}
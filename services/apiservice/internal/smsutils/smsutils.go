@@ -0,0 +1,110 @@
+package smsutils
+
+import "unicode/utf16"
+
+// Encoding is the character set an SMS is sent in.
+type Encoding string
+
+const (
+	// EncodingGSM7 packs texts written in the GSM 03.38 alphabet into 7 bits per character.
+	EncodingGSM7 Encoding = "GSM-7"
+	// EncodingUCS2 sends any other text, such as Cyrillic, as 16-bit UTF-16 code units.
+	EncodingUCS2 Encoding = "UCS-2"
+)
+
+const (
+	gsm7SingleSegment = 160
+	gsm7MultiSegment  = 153
+	ucs2SingleSegment = 70
+	ucs2MultiSegment  = 67
+)
+
+// gsm7Basic is the basic character set of GSM 03.38; every character takes one septet.
+const gsm7Basic = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+	"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+
+// gsm7Extension is the extension table of GSM 03.38; every character takes two septets, an escape and itself.
+const gsm7Extension = "\f^{}\\[~]|€"
+
+var gsm7Septets = func() map[rune]int {
+	septets := make(map[rune]int)
+	for _, r := range gsm7Basic {
+		septets[r] = 1
+	}
+	for _, r := range gsm7Extension {
+		septets[r] = 2
+	}
+	return septets
+}()
+
+// Segmentation describes how a text is sent as SMS: its encoding, its length in units of the
+// encoding (septets for GSM-7, UTF-16 code units for UCS-2) and the number of segments it takes.
+type Segmentation struct {
+	Encoding Encoding `json:"encoding"`
+	Length   int      `json:"length"`
+	Segments int      `json:"segments"`
+}
+
+// Segment calculates the segmentation of the text. Texts written entirely in the GSM 03.38 alphabet
+// are sent in GSM-7, taking 160 septets in one segment or 153 per segment of a concatenated message;
+// any other text is sent in UCS-2, taking 70 code units in one segment or 67 per concatenated one.
+// Characters are never split between segments. An empty text takes no segments.
+func Segment(text string) Segmentation {
+	encoding := EncodingGSM7
+	single, multi := gsm7SingleSegment, gsm7MultiSegment
+	widths, ok := gsm7Widths(text)
+	if !ok {
+		encoding = EncodingUCS2
+		single, multi = ucs2SingleSegment, ucs2MultiSegment
+		widths = ucs2Widths(text)
+	}
+
+	length := 0
+	for _, w := range widths {
+		length += w
+	}
+
+	s := Segmentation{Encoding: encoding, Length: length}
+	switch {
+	case length == 0:
+		s.Segments = 0
+	case length <= single:
+		s.Segments = 1
+	default:
+		s.Segments = 1
+		used := 0
+		for _, w := range widths {
+			if used+w > multi {
+				s.Segments++
+				used = 0
+			}
+			used += w
+		}
+	}
+
+	return s
+}
+
+// gsm7Widths returns the septets every character of the text takes in GSM-7,
+// or false if the text has a character outside the GSM 03.38 alphabet.
+func gsm7Widths(text string) ([]int, bool) {
+	var widths []int
+	for _, r := range text {
+		w, ok := gsm7Septets[r]
+		if !ok {
+			return nil, false
+		}
+		widths = append(widths, w)
+	}
+	return widths, true
+}
+
+// ucs2Widths returns the UTF-16 code units every character of the text takes in UCS-2:
+// two for the characters outside the Basic Multilingual Plane, such as emoji.
+func ucs2Widths(text string) []int {
+	var widths []int
+	for _, r := range text {
+		widths = append(widths, utf16.RuneLen(r))
+	}
+	return widths
+}
@@ -0,0 +1,54 @@
+package smsutils_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/smsutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSegment(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want smsutils.Segmentation
+	}{
+		{name: "Empty", text: "", want: smsutils.Segmentation{Encoding: smsutils.EncodingGSM7}},
+		{name: "Short latin", text: "Flood warning", want: smsutils.Segmentation{Encoding: smsutils.EncodingGSM7, Length: 13, Segments: 1}},
+		{name: "Full GSM-7 segment", text: strings.Repeat("a", 160), want: smsutils.Segmentation{Encoding: smsutils.EncodingGSM7, Length: 160, Segments: 1}},
+		{name: "Concatenated GSM-7", text: strings.Repeat("a", 161), want: smsutils.Segmentation{Encoding: smsutils.EncodingGSM7, Length: 161, Segments: 2}},
+		{name: "Three GSM-7 segments", text: strings.Repeat("a", 307), want: smsutils.Segmentation{Encoding: smsutils.EncodingGSM7, Length: 307, Segments: 3}},
+		{name: "Extension characters take two septets", text: strings.Repeat("€", 80), want: smsutils.Segmentation{Encoding: smsutils.EncodingGSM7, Length: 160, Segments: 1}},
+		{
+			name: "Escape sequence isn't split",
+			text: strings.Repeat("a", 152) + "{" + strings.Repeat("a", 10),
+			want: smsutils.Segmentation{Encoding: smsutils.EncodingGSM7, Length: 164, Segments: 2},
+		},
+		{
+			name: "Escape sequence moves to the next segment",
+			text: strings.Repeat("a", 152) + "{" + strings.Repeat("a", 152),
+			want: smsutils.Segmentation{Encoding: smsutils.EncodingGSM7, Length: 306, Segments: 3},
+		},
+		{name: "Cyrillic", text: "Внимание! Наводнение", want: smsutils.Segmentation{Encoding: smsutils.EncodingUCS2, Length: 20, Segments: 1}},
+		{name: "Full UCS-2 segment", text: strings.Repeat("я", 70), want: smsutils.Segmentation{Encoding: smsutils.EncodingUCS2, Length: 70, Segments: 1}},
+		{name: "Concatenated UCS-2", text: strings.Repeat("я", 71), want: smsutils.Segmentation{Encoding: smsutils.EncodingUCS2, Length: 71, Segments: 2}},
+		{name: "One non-GSM character switches to UCS-2", text: strings.Repeat("a", 100) + "ё", want: smsutils.Segmentation{Encoding: smsutils.EncodingUCS2, Length: 101, Segments: 2}},
+		{
+			name: "Surrogate pair isn't split",
+			text: strings.Repeat("я", 66) + "🚨" + "я",
+			want: smsutils.Segmentation{Encoding: smsutils.EncodingUCS2, Length: 69, Segments: 1},
+		},
+		{
+			name: "Surrogate pair moves to the next segment",
+			text: strings.Repeat("я", 66) + "🚨" + strings.Repeat("я", 5),
+			want: smsutils.Segmentation{Encoding: smsutils.EncodingUCS2, Length: 73, Segments: 2},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, smsutils.Segment(tc.text))
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package textdiff
+
+import "regexp"
+
+// Op is the kind of an edit.
+type Op string
+
+const (
+	// OpEqual keeps text present in both texts.
+	OpEqual Op = "equal"
+	// OpDelete removes text of the old text.
+	OpDelete Op = "delete"
+	// OpInsert adds text of the new text.
+	OpInsert Op = "insert"
+)
+
+// Edit is a run of text kept, removed or added when turning the old text into the new one.
+type Edit struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+// tokenPattern splits a text into words and the whitespace between them.
+var tokenPattern = regexp.MustCompile(`\s+|\S+`)
+
+// Diff returns the word-level edits turning the text from into the text to: a longest common
+// subsequence of their words and whitespace is kept, and the rest is deleted or inserted, deletions
+// first. Consecutive edits of the same kind are merged. Concatenating the texts of the equal and
+// deleted edits gives from, and of the equal and inserted ones to.
+func Diff(from, to string) []Edit {
+	a := tokenPattern.FindAllString(from, -1)
+	b := tokenPattern.FindAllString(to, -1)
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var edits []Edit
+	add := func(op Op, text string) {
+		if n := len(edits); n > 0 && edits[n-1].Op == op {
+			edits[n-1].Text += text
+			return
+		}
+		edits = append(edits, Edit{Op: op, Text: text})
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			add(OpEqual, a[i])
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			add(OpDelete, a[i])
+			i++
+		default:
+			add(OpInsert, b[j])
+			j++
+		}
+	}
+
+	return edits
+}
@@ -0,0 +1,65 @@
+package textdiff_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/apiservice/internal/textdiff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want []textdiff.Edit
+	}{
+		{name: "Both empty", old: "", new: "", want: nil},
+		{name: "Unchanged", old: "Flood warning", new: "Flood warning", want: []textdiff.Edit{{Op: textdiff.OpEqual, Text: "Flood warning"}}},
+		{name: "Added", old: "", new: "Flood warning", want: []textdiff.Edit{{Op: textdiff.OpInsert, Text: "Flood warning"}}},
+		{name: "Removed", old: "Flood warning", new: "", want: []textdiff.Edit{{Op: textdiff.OpDelete, Text: "Flood warning"}}},
+		{
+			name: "Word replaced",
+			old:  "Сбор в 9:00 у входа",
+			new:  "Сбор в 10:00 у входа",
+			want: []textdiff.Edit{
+				{Op: textdiff.OpEqual, Text: "Сбор в "},
+				{Op: textdiff.OpDelete, Text: "9:00"},
+				{Op: textdiff.OpInsert, Text: "10:00"},
+				{Op: textdiff.OpEqual, Text: " у входа"},
+			},
+		},
+		{
+			name: "Words appended",
+			old:  "Evacuate now",
+			new:  "Evacuate now via exit B",
+			want: []textdiff.Edit{
+				{Op: textdiff.OpEqual, Text: "Evacuate now"},
+				{Op: textdiff.OpInsert, Text: " via exit B"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := textdiff.Diff(tc.old, tc.new)
+
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.old, join(got, textdiff.OpEqual, textdiff.OpDelete))
+			assert.Equal(t, tc.new, join(got, textdiff.OpEqual, textdiff.OpInsert))
+		})
+	}
+}
+
+func join(edits []textdiff.Edit, ops ...textdiff.Op) string {
+	var sb strings.Builder
+	for _, e := range edits {
+		for _, op := range ops {
+			if e.Op == op {
+				sb.WriteString(e.Text)
+			}
+		}
+	}
+	return sb.String()
+}
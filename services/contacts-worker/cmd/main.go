@@ -23,7 +23,8 @@ func main() {
 	contactsReader := app.KafkaFactory.NewReader(kafkaCfg.Topics["contacts.loading.tasks"], kafkaCfg.ConsumerGroup)
 
 	cr := repository.NewContactsRepository(app.DB)
-	cs := service.NewContactsService(cr, app.S3Client, app.Config.S3.Bucket, app.Config.App.ContextTimeout, app.Config.App.BatchSize)
+	cir := repository.NewContactImportRepository(app.DB)
+	cs := service.NewContactsService(cr, cir, app.S3Client, app.Config.S3.Bucket, app.Config.App.ContextTimeout, app.Config.App.BatchSize)
 	cc := consumers.NewContactsConsumer(cs, contactsReader, app.Logger)
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -48,13 +48,14 @@ func (cc *ContactsConsumer) StartConsumer(ctx context.Context) error {
 
 		start := time.Now()
 		cc.logger.Info("started task",
+			zap.Int("import_id", t.ImportID),
 			zap.Int("user_id", t.UserID),
 			zap.String("s3_key", t.S3Key),
 		)
 
-		processedContacts, err := cc.service.ProcessFile(ctx, &t)
+		stats, err := cc.service.ProcessFile(ctx, &t)
 		if err != nil {
-			cc.logger.Error("failed to process task", zap.String("file_key", t.S3Key), zap.Error(err))
+			cc.logger.Error("failed to process task", zap.Int("import_id", t.ImportID), zap.String("file_key", t.S3Key), zap.Error(err))
 			err := cc.kafkaReader.CommitMessages(ctx, msg)
 			if err != nil {
 				return err
@@ -64,10 +65,16 @@ func (cc *ContactsConsumer) StartConsumer(ctx context.Context) error {
 
 		duration := time.Since(start)
 		cc.logger.Info("finished task",
+			zap.Int("import_id", t.ImportID),
 			zap.Int("user_id", t.UserID),
 			zap.String("s3_key", t.S3Key),
 			zap.Duration("duration", duration),
-			zap.Int("processed_contacts", processedContacts),
+			zap.Int("rows_read", stats.RowsRead),
+			zap.Int("rows_inserted", stats.RowsInserted),
+			zap.Int("rows_updated", stats.RowsUpdated),
+			zap.Int("rows_duplicate", stats.RowsDuplicate),
+			zap.Int("rows_invalid", stats.RowsInvalid),
+			zap.Int("rows_deleted", stats.RowsDeleted),
 		)
 
 		err = cc.kafkaReader.CommitMessages(ctx, msg)
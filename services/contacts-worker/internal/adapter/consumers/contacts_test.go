@@ -7,6 +7,7 @@ import (
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/adapter/consumers"
 	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/models"
 	"github.com/segmentio/kafka-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -30,15 +31,16 @@ type MockContactsService struct {
 	mock.Mock
 }
 
-func (m *MockContactsService) ProcessFile(ctx context.Context, t *domain.Task) (int, error) {
+func (m *MockContactsService) ProcessFile(ctx context.Context, t *domain.Task) (*models.ImportStats, error) {
 	args := m.Called(ctx, t)
-	return args.Int(0), args.Error(1)
+	return args.Get(0).(*models.ImportStats), args.Error(1)
 }
 
 func TestContactsConsumer_StartConsumer(t *testing.T) {
 	validTask := domain.Task{
-		UserID: 42,
-		S3Key:  "contacts.csv",
+		ImportID: 7,
+		UserID:   42,
+		S3Key:    "contacts.csv",
 	}
 	validJSON, _ := json.Marshal(validTask)
 	validMsg := kafka.Message{Value: validJSON}
@@ -63,7 +65,7 @@ func TestContactsConsumer_StartConsumer(t *testing.T) {
 					Once()
 				ms.
 					On("ProcessFile", mock.Anything, &validTask).
-					Return(5, nil).
+					Return(&models.ImportStats{RowsRead: 6, RowsInserted: 5, RowsInvalid: 1}, nil).
 					Once()
 				mr.
 					On("FetchMessage", mock.Anything).
@@ -99,7 +101,7 @@ func TestContactsConsumer_StartConsumer(t *testing.T) {
 					Once()
 				ms.
 					On("ProcessFile", mock.Anything, &validTask).
-					Return(0, assert.AnError).
+					Return((*models.ImportStats)(nil), assert.AnError).
 					Once()
 				mr.
 					On("CommitMessages", mock.Anything, mock.Anything).
@@ -121,7 +123,7 @@ func TestContactsConsumer_StartConsumer(t *testing.T) {
 					Once()
 				ms.
 					On("ProcessFile", mock.Anything, &validTask).
-					Return(5, nil).
+					Return(&models.ImportStats{RowsRead: 6, RowsInserted: 5, RowsInvalid: 1}, nil).
 					Once()
 				mr.
 					On("CommitMessages", mock.Anything, mock.Anything).
@@ -2,26 +2,89 @@ package domain
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/models"
 )
 
+var (
+	// ErrUnsupportedFileType is returned when an uploaded file is not CSV, XLSX, vCard, JSON or NDJSON.
+	ErrUnsupportedFileType = fmt.Errorf("unsupported file type")
+	// ErrMalformedFile is returned when the structure of an uploaded file is broken beyond single records.
+	ErrMalformedFile = fmt.Errorf("malformed file")
+	// ErrSheetNotFound is returned when a selected sheet is missing from the workbook.
+	ErrSheetNotFound = fmt.Errorf("sheet not found")
+	// ErrColumnNotFound is returned when a mapped or required column is missing from the file.
+	ErrColumnNotFound = fmt.Errorf("column not found")
+	// ErrContactImportNotExists is returned when a task refers to an unknown import job.
+	ErrContactImportNotExists = fmt.Errorf("contact import doesn't exist")
+	// ErrUnsupportedImportMode is returned when a task asks for an unknown import mode.
+	ErrUnsupportedImportMode = fmt.Errorf("unsupported import mode")
+	// ErrEmptySyncImport is returned when a sync import has no valid contacts, which
+	// would otherwise delete every contact of the user.
+	ErrEmptySyncImport = fmt.Errorf("sync import has no valid contacts")
+)
+
 // ContactsService defines the interface for processing contact upload tasks.
 // Implementations should fetch the file from storage, process its contents,
-// and return the number of successfully processed records.
+// and return the row counters of the import.
 type ContactsService interface {
-	ProcessFile(ctx context.Context, task *Task) (processedContacts int, err error)
+	ProcessFile(ctx context.Context, task *Task) (*models.ImportStats, error)
 }
 
 // ContactsRepository encapsulates the persistence mechanism for storing Contact models.
 // Implementations should insert the provided slice of Contact objects into the database,
-// handling deduplication or conflict resolution as needed.
+// skip or update duplicates depending on the import mode and return the number of contacts
+// actually inserted and updated.
+//
+// Sync imports and dry runs stage their rows with StageContacts instead, then apply
+// them, or only count the changes, at once with MergeStagedContacts.
+// GetContactAttributes returns the typed attributes imported values are checked against.
 type ContactsRepository interface {
-	SaveContacts(ctx context.Context, contacts []*models.Contact) error
+	SaveContacts(ctx context.Context, contacts []*models.Contact, mode models.ImportMode) (inserted, updated int, err error)
+	StageContacts(ctx context.Context, importID int, contacts []*models.Contact) error
+	MergeStagedContacts(ctx context.Context, userID, importID int, mode models.ImportMode, dryRun bool) (*models.ImportChanges, error)
+	ClearStagedContacts(ctx context.Context, importID int) error
+	GetContactAttributes(ctx context.Context, userID int) ([]*models.ContactAttribute, error)
+}
+
+// ContactImportRepository tracks the lifecycle of the import job a task belongs to.
+type ContactImportRepository interface {
+	StartContactImport(ctx context.Context, importID int) error
+	CompleteContactImport(ctx context.Context, importID int, stats *models.ImportStats, reportKey, deletionsKey string) error
+	FailContactImport(ctx context.Context, importID int, reason string) error
 }
 
 // Task represents a job to load contacts from an S3 object for a specific user.
+// ImportID is zero for tasks published before import jobs were tracked. Region is the
+// ISO 3166-1 alpha-2 code used to parse phone numbers without a country code. An empty
+// Mode means insert-only; a DryRun only counts the changes the import would make.
+// Sheets selects the sheets of an XLSX workbook and is ignored for other files.
 type Task struct {
-	UserID int    `json:"userID"`
-	S3Key  string `json:"s3Key"`
+	ImportID int               `json:"importID"`
+	UserID   int               `json:"userID"`
+	S3Key    string            `json:"s3Key"`
+	Mapping  ColumnMapping     `json:"mapping"`
+	Region   string            `json:"region"`
+	Mode     models.ImportMode `json:"mode"`
+	DryRun   bool              `json:"dryRun"`
+	Sheets   SheetSelection    `json:"sheets"`
+}
+
+// SheetSelection names the sheets of a workbook to import, matched case-insensitively.
+// All imports every sheet in workbook order; otherwise the named sheets are imported in
+// the given order, or only the first sheet when none are named. Groups maps sheet names
+// to the contact group stored in the "Group" attribute of the contacts of that sheet.
+type SheetSelection struct {
+	All    bool              `json:"all,omitempty"`
+	Names  []string          `json:"names,omitempty"`
+	Groups map[string]string `json:"groups,omitempty"`
+}
+
+// ColumnMapping names the columns holding the contact's name and phone, either by
+// header name (case-insensitive) or by 1-based column number. Empty values are
+// detected from the header row.
+type ColumnMapping struct {
+	Name  string `json:"name,omitempty"`
+	Phone string `json:"phone,omitempty"`
 }
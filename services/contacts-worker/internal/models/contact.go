@@ -3,11 +3,13 @@ package models
 import "time"
 
 // Contact represents a user's contact information stored in the system.
+// Attributes holds the extra named columns of the imported file.
 type Contact struct {
-	ID           int       `json:"id"`
-	UserID       int       `json:"userId"`
-	Name         string    `json:"name"`
-	Phone        string    `json:"phone"`
-	CreationTime time.Time `json:"creationTime"`
-	UpdateTime   time.Time `json:"updateTime"`
+	ID           int               `json:"id"`
+	UserID       int               `json:"userId"`
+	Name         string            `json:"name"`
+	Phone        string            `json:"phone"`
+	Attributes   map[string]string `json:"attributes,omitempty"`
+	CreationTime time.Time         `json:"creationTime"`
+	UpdateTime   time.Time         `json:"updateTime"`
 }
@@ -0,0 +1,46 @@
+package models
+
+// ImportStatus describes the lifecycle stage of a contact import job.
+type ImportStatus string
+
+const (
+	// ImportStatusProcessing means the worker is reading the uploaded file.
+	ImportStatusProcessing ImportStatus = "processing"
+	// ImportStatusDone means every row of the file was handled.
+	ImportStatusDone ImportStatus = "done"
+	// ImportStatusFailed means the file could not be processed.
+	ImportStatusFailed ImportStatus = "failed"
+)
+
+// ImportMode decides what happens to contacts that already exist or are missing from the file.
+type ImportMode string
+
+const (
+	// ImportModeInsertOnly adds new contacts and leaves existing ones untouched.
+	ImportModeInsertOnly ImportMode = "insert-only"
+	// ImportModeUpsert adds new contacts and updates the name and attributes of existing ones.
+	ImportModeUpsert ImportMode = "upsert"
+	// ImportModeSync upserts the contacts of the file and deletes the user's contacts missing from it.
+	ImportModeSync ImportMode = "sync"
+)
+
+// ImportStats holds the row counters of a processed contacts file.
+// Every row read is either inserted, updated, skipped as a duplicate of an
+// existing contact, or rejected as invalid. RowsDeleted counts the existing
+// contacts removed by a sync import.
+type ImportStats struct {
+	RowsRead      int
+	RowsInserted  int
+	RowsUpdated   int
+	RowsDuplicate int
+	RowsInvalid   int
+	RowsDeleted   int
+}
+
+// ImportChanges describes what merging the staged rows of an import did, or
+// would do in a dry run, to the user's contacts.
+type ImportChanges struct {
+	Inserted int
+	Updated  int
+	Deleted  []*Contact
+}
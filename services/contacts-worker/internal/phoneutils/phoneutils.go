@@ -2,6 +2,7 @@ package phoneutils
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/nyaruka/phonenumbers"
 )
@@ -15,6 +16,16 @@ const (
 	RegionRU ISO3166Alpha2 = "RU"
 )
 
+// ParseRegion normalizes a region code such as "de" or " US " and reports whether
+// it is a region known to the phone number metadata.
+func ParseRegion(region string) (ISO3166Alpha2, bool) {
+	code := strings.ToUpper(strings.TrimSpace(region))
+	if !phonenumbers.GetSupportedRegions()[code] {
+		return "", false
+	}
+	return ISO3166Alpha2(code), true
+}
+
 // FormatToE164 parses a raw phone number string using the provided default region,
 // validates it, and returns the number formatted in E.164 standard (e.g. +1234567890).
 // Returns the formatted E.164 string on success, or an error if parsing or validation fails.
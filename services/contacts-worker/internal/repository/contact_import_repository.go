@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/models"
+)
+
+// ContactImportRepository updates the status and row counters of contact import jobs.
+type ContactImportRepository struct {
+	db domain.DBConn
+}
+
+// NewContactImportRepository creates a new ContactImportRepository with the given DB connection.
+func NewContactImportRepository(db domain.DBConn) *ContactImportRepository {
+	return &ContactImportRepository{
+		db: db,
+	}
+}
+
+// StartContactImport marks the import job as processing.
+// Returns domain.ErrContactImportNotExists if no row is found.
+func (cir *ContactImportRepository) StartContactImport(ctx context.Context, importID int) error {
+	const q = `
+		UPDATE contact_imports
+		SET status     = $2,
+		    updated_at = now()
+		WHERE id = $1
+	`
+
+	res, err := cir.db.Exec(ctx, q, importID, models.ImportStatusProcessing)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrContactImportNotExists
+	}
+
+	return nil
+}
+
+// CompleteContactImport marks the import job as done and stores its row counters
+// together with the S3 keys of the rejection report, which is empty if no row was rejected,
+// and of the report of deleted contacts, which is empty if a sync deleted nothing.
+// Returns domain.ErrContactImportNotExists if no row is found.
+func (cir *ContactImportRepository) CompleteContactImport(ctx context.Context, importID int, stats *models.ImportStats, reportKey, deletionsKey string) error {
+	const q = `
+		UPDATE contact_imports
+		SET status         = $2,
+		    rows_read      = $3,
+		    rows_inserted  = $4,
+		    rows_updated   = $5,
+		    rows_duplicate = $6,
+		    rows_invalid   = $7,
+		    rows_deleted   = $8,
+		    report_key     = $9,
+		    deletions_key  = $10,
+		    updated_at     = now()
+		WHERE id = $1
+	`
+
+	res, err := cir.db.Exec(ctx, q, importID, models.ImportStatusDone,
+		stats.RowsRead, stats.RowsInserted, stats.RowsUpdated, stats.RowsDuplicate, stats.RowsInvalid, stats.RowsDeleted,
+		reportKey, deletionsKey)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrContactImportNotExists
+	}
+
+	return nil
+}
+
+// FailContactImport marks the import job as failed with the given reason.
+// Returns domain.ErrContactImportNotExists if no row is found.
+func (cir *ContactImportRepository) FailContactImport(ctx context.Context, importID int, reason string) error {
+	const q = `
+		UPDATE contact_imports
+		SET status     = $2,
+		    error      = $3,
+		    updated_at = now()
+		WHERE id = $1
+	`
+
+	res, err := cir.db.Exec(ctx, q, importID, models.ImportStatusFailed, reason)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrContactImportNotExists
+	}
+
+	return nil
+}
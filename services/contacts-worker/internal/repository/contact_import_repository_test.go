@@ -0,0 +1,76 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContactImportRepository(t *testing.T) {
+	ctx := context.Background()
+
+	fixtures := makeFixtures(t, testDB, "../../../../db/fixtures/users.yml")
+	require.NoError(t, fixtures.Load())
+
+	var importID int
+	err := testPool.QueryRow(ctx, `
+		INSERT INTO contact_imports (user_id, filename, s3_key)
+		VALUES (1, 'contacts.csv', 'contacts/1_contacts.csv')
+		RETURNING id`).Scan(&importID)
+	require.NoError(t, err)
+
+	repo := repository.NewContactImportRepository(testPool)
+
+	status := func() (models.ImportStatus, models.ImportStats, []string, string) {
+		var (
+			s                               models.ImportStatus
+			st                              models.ImportStats
+			reportKey, deletionsKey, reason string
+		)
+		err := testPool.QueryRow(ctx, `
+			SELECT status, rows_read, rows_inserted, rows_updated, rows_duplicate, rows_invalid, rows_deleted,
+			       report_key, deletions_key, error
+			FROM contact_imports
+			WHERE id = $1`, importID).
+			Scan(&s, &st.RowsRead, &st.RowsInserted, &st.RowsUpdated, &st.RowsDuplicate, &st.RowsInvalid, &st.RowsDeleted,
+				&reportKey, &deletionsKey, &reason)
+		require.NoError(t, err)
+		return s, st, []string{reportKey, deletionsKey}, reason
+	}
+
+	t.Run("start", func(t *testing.T) {
+		require.NoError(t, repo.StartContactImport(ctx, importID))
+		s, _, _, _ := status()
+		assert.Equal(t, models.ImportStatusProcessing, s)
+	})
+
+	t.Run("complete", func(t *testing.T) {
+		stats := &models.ImportStats{RowsRead: 10, RowsInserted: 5, RowsUpdated: 1, RowsDuplicate: 1, RowsInvalid: 3, RowsDeleted: 2}
+		require.NoError(t, repo.CompleteContactImport(ctx, importID, stats, "imports/1/rejected.csv", "imports/1/deleted.csv"))
+		s, st, keys, _ := status()
+		assert.Equal(t, models.ImportStatusDone, s)
+		assert.Equal(t, *stats, st)
+		assert.Equal(t, []string{"imports/1/rejected.csv", "imports/1/deleted.csv"}, keys)
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		require.NoError(t, repo.FailContactImport(ctx, importID, "unsupported file type"))
+		s, _, _, reason := status()
+		assert.Equal(t, models.ImportStatusFailed, s)
+		assert.Equal(t, "unsupported file type", reason)
+	})
+
+	t.Run("unknown import", func(t *testing.T) {
+		assert.ErrorIs(t, repo.StartContactImport(ctx, importID+1), domain.ErrContactImportNotExists)
+		assert.ErrorIs(t, repo.CompleteContactImport(ctx, importID+1, &models.ImportStats{}, "", ""), domain.ErrContactImportNotExists)
+		assert.ErrorIs(t, repo.FailContactImport(ctx, importID+1, "x"), domain.ErrContactImportNotExists)
+	})
+}
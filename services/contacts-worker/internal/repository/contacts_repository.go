@@ -21,12 +21,15 @@ func NewContactsRepository(db domain.DBConn) *ContactsRepository {
 }
 
 // SaveContacts inserts a slice of Contact models into the database in bulk.
-// It stages records in a temporary table, then copies them into the main contacts table,
-// ignoring any duplicates on (user_id, phone). All operations are executed in a transaction.
-func (cr *ContactsRepository) SaveContacts(ctx context.Context, contacts []*models.Contact) error {
+// It stages records in a temporary table, then copies them into the main contacts table.
+// Duplicates on (user_id, phone) are ignored in insert-only mode; in upsert mode their name
+// and attributes are overwritten, and a phone repeated within the batch keeps its last row.
+// All operations are executed in a transaction.
+// It returns the number of contacts actually inserted and updated.
+func (cr *ContactsRepository) SaveContacts(ctx context.Context, contacts []*models.Contact, mode models.ImportMode) (inserted, updated int, err error) {
 	tx, err := cr.db.Begin(ctx)
 	if err != nil {
-		return err
+		return 0, 0, err
 	}
 
 	defer func() {
@@ -40,42 +43,242 @@ func (cr *ContactsRepository) SaveContacts(ctx context.Context, contacts []*mode
 	const tempTableQuery = `
 		CREATE TEMP TABLE contacts_stage
 		(
-			user_id INT,
-			name    TEXT,
-			phone   TEXT
+			ord        INT,
+			user_id    INT,
+			name       TEXT,
+			phone      TEXT,
+			attributes JSONB
 		) ON COMMIT DROP 
 	`
 
 	_, err = tx.Exec(ctx, tempTableQuery)
 	if err != nil {
-		return err
+		return 0, 0, err
 	}
 
 	rows := make([][]any, len(contacts))
 	for i, c := range contacts {
-		rows[i] = []any{c.UserID, c.Name, c.Phone}
+		rows[i] = []any{i, c.UserID, c.Name, c.Phone, contactAttributes(c)}
 	}
 
 	_, err = tx.CopyFrom(ctx,
 		pgx.Identifier{"contacts_stage"},
-		[]string{"user_id", "name", "phone"},
+		[]string{"ord", "user_id", "name", "phone", "attributes"},
 		pgx.CopyFromRows(rows),
 	)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if mode == models.ImportModeInsertOnly {
+		const insertQuery = `
+			INSERT INTO contacts(user_id, name, phone, attributes)
+			SELECT user_id, name, phone, attributes
+			FROM contacts_stage
+			ON CONFLICT (user_id, phone) DO NOTHING
+		`
+
+		res, err := tx.Exec(ctx, insertQuery)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		return int(res.RowsAffected()), 0, nil
+	}
+
+	const upsertQuery = `
+		INSERT INTO contacts(user_id, name, phone, attributes)
+		SELECT DISTINCT ON (user_id, phone) user_id, name, phone, attributes
+		FROM contacts_stage
+		ORDER BY user_id, phone, ord DESC
+		ON CONFLICT (user_id, phone) DO UPDATE
+			SET name       = EXCLUDED.name,
+			    attributes = EXCLUDED.attributes,
+			    updated_at = now()
+			WHERE (contacts.name, contacts.attributes) IS DISTINCT FROM (EXCLUDED.name, EXCLUDED.attributes)
+		RETURNING xmax = 0
+	`
+
+	return countUpserted(tx.Query(ctx, upsertQuery))
+}
+
+// StageContacts stores the contacts of an import until MergeStagedContacts applies them.
+func (cr *ContactsRepository) StageContacts(ctx context.Context, importID int, contacts []*models.Contact) error {
+	rows := make([][]any, len(contacts))
+	for i, c := range contacts {
+		rows[i] = []any{importID, c.Name, c.Phone, contactAttributes(c)}
+	}
+
+	tx, err := cr.db.Begin(ctx)
 	if err != nil {
 		return err
 	}
 
+	_, err = tx.CopyFrom(ctx,
+		pgx.Identifier{"contact_import_rows"},
+		[]string{"import_id", "name", "phone", "attributes"},
+		pgx.CopyFromRows(rows),
+	)
+	if err != nil {
+		_ = tx.Rollback(ctx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// MergeStagedContacts applies the staged rows of an import to the user's contacts in one
+// transaction: new phones are inserted, existing ones are updated unless the mode is
+// insert-only, and in sync mode the contacts whose phones were not staged are deleted.
+// A phone staged several times keeps its last row. In a dry run the transaction is rolled
+// back, so only the returned changes tell what the import would do.
+func (cr *ContactsRepository) MergeStagedContacts(ctx context.Context, userID, importID int, mode models.ImportMode, dryRun bool) (changes *models.ImportChanges, err error) {
+	tx, err := cr.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil || dryRun {
+			_ = tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
 	const insertQuery = `
-		INSERT INTO contacts(user_id, name, phone)
-		SELECT user_id, name, phone
-		FROM contacts_stage
+		INSERT INTO contacts(user_id, name, phone, attributes)
+		SELECT DISTINCT ON (phone) $1::INT, name, phone, attributes
+		FROM contact_import_rows
+		WHERE import_id = $2
+		ORDER BY phone, id DESC
 		ON CONFLICT (user_id, phone) DO NOTHING
+		RETURNING xmax = 0
+	`
+
+	const upsertQuery = `
+		INSERT INTO contacts(user_id, name, phone, attributes)
+		SELECT DISTINCT ON (phone) $1::INT, name, phone, attributes
+		FROM contact_import_rows
+		WHERE import_id = $2
+		ORDER BY phone, id DESC
+		ON CONFLICT (user_id, phone) DO UPDATE
+			SET name       = EXCLUDED.name,
+			    attributes = EXCLUDED.attributes,
+			    updated_at = now()
+			WHERE (contacts.name, contacts.attributes) IS DISTINCT FROM (EXCLUDED.name, EXCLUDED.attributes)
+		RETURNING xmax = 0
 	`
 
-	_, err = tx.Exec(ctx, insertQuery)
+	q := upsertQuery
+	if mode == models.ImportModeInsertOnly {
+		q = insertQuery
+	}
+
+	changes = &models.ImportChanges{}
+	changes.Inserted, changes.Updated, err = countUpserted(tx.Query(ctx, q, userID, importID))
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if mode != models.ImportModeSync {
+		return changes, nil
+	}
+
+	const deleteQuery = `
+		DELETE FROM contacts c
+		WHERE c.user_id = $1
+		  AND NOT EXISTS (SELECT 1
+		                  FROM contact_import_rows r
+		                  WHERE r.import_id = $2
+		                    AND r.phone = c.phone)
+		RETURNING c.id, c.user_id, c.name, c.phone
+	`
+
+	rows, err := tx.Query(ctx, deleteQuery, userID, importID)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c models.Contact
+		err = rows.Scan(&c.ID, &c.UserID, &c.Name, &c.Phone)
+		if err != nil {
+			return nil, err
+		}
+		changes.Deleted = append(changes.Deleted, &c)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// ClearStagedContacts removes the staged rows of an import.
+func (cr *ContactsRepository) ClearStagedContacts(ctx context.Context, importID int) error {
+	const q = `
+		DELETE FROM contact_import_rows
+		WHERE import_id = $1
+	`
+
+	_, err := cr.db.Exec(ctx, q, importID)
+	return err
+}
 
-	return nil
+// GetContactAttributes returns the typed contact attributes defined by the user.
+func (cr *ContactsRepository) GetContactAttributes(ctx context.Context, userID int) ([]*models.ContactAttribute, error) {
+	const q = `
+		SELECT name, type, options, required
+		FROM contact_attributes
+		WHERE user_id = $1
+	`
+
+	rows, err := cr.db.Query(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attrs []*models.ContactAttribute
+	for rows.Next() {
+		var a models.ContactAttribute
+		if err := rows.Scan(&a.Name, &a.Type, &a.Options, &a.Required); err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, &a)
+	}
+
+	return attrs, rows.Err()
+}
+
+// countUpserted reads the "xmax = 0" column returned by an upsert, which is true for
+// inserted rows and false for updated ones.
+func countUpserted(rows pgx.Rows, err error) (inserted, updated int, _ error) {
+	if err != nil {
+		return 0, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var isInsert bool
+		if err := rows.Scan(&isInsert); err != nil {
+			return 0, 0, err
+		}
+		if isInsert {
+			inserted++
+		} else {
+			updated++
+		}
+	}
+
+	return inserted, updated, rows.Err()
+}
+
+func contactAttributes(c *models.Contact) map[string]string {
+	if c.Attributes == nil {
+		return map[string]string{}
+	}
+	return c.Attributes
 }
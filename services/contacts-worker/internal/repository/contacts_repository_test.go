@@ -9,7 +9,9 @@ import (
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/models"
 	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/repository"
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestContactsRepository_SaveContacts(t *testing.T) {
@@ -29,8 +31,15 @@ func TestContactsRepository_SaveContacts(t *testing.T) {
 			{UserID: 2, Name: "New Contact B", Phone: "111111111"},    // new for another user
 		}
 
-		err := repo.SaveContacts(ctx, contacts)
+		inserted, updated, err := repo.SaveContacts(ctx, contacts, models.ImportModeInsertOnly)
 		assert.NoError(t, err)
+		assert.Equal(t, 3, inserted)
+		assert.Zero(t, updated)
+
+		inserted, updated, err = repo.SaveContacts(ctx, contacts, models.ImportModeInsertOnly)
+		assert.NoError(t, err)
+		assert.Zero(t, inserted)
+		assert.Zero(t, updated)
 
 		rows, err := testPool.Query(ctx, `
 			SELECT user_id, name, phone FROM contacts 
@@ -52,4 +61,173 @@ func TestContactsRepository_SaveContacts(t *testing.T) {
 		assert.Contains(t, results, models.Contact{UserID: 1, Name: "New Contact A", Phone: "999999999"})
 		assert.Contains(t, results, models.Contact{UserID: 2, Name: "New Contact B", Phone: "111111111"})
 	})
+
+	t.Run("stores attributes", func(t *testing.T) {
+		fixtures := makeFixtures(t, testDB, "../../../../db/fixtures/users.yml")
+		if err := fixtures.Load(); err != nil {
+			t.Fatalf("cannot load fixtures: %v", err)
+		}
+
+		contacts := []*models.Contact{
+			{UserID: 1, Name: "With Attributes", Phone: "222222222", Attributes: map[string]string{"Department": "IT"}},
+			{UserID: 1, Name: "Without Attributes", Phone: "333333333"},
+		}
+
+		inserted, _, err := repo.SaveContacts(ctx, contacts, models.ImportModeInsertOnly)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, inserted)
+
+		var attributes map[string]string
+		err = testPool.QueryRow(ctx, `SELECT attributes FROM contacts WHERE phone = '222222222'`).Scan(&attributes)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"Department": "IT"}, attributes)
+
+		err = testPool.QueryRow(ctx, `SELECT attributes FROM contacts WHERE phone = '333333333'`).Scan(&attributes)
+		assert.NoError(t, err)
+		assert.Empty(t, attributes)
+	})
+
+	t.Run("upserts changed contacts", func(t *testing.T) {
+		fixtures := makeFixtures(t, testDB, "../../../../db/fixtures/users.yml")
+		if err := fixtures.Load(); err != nil {
+			t.Fatalf("cannot load fixtures: %v", err)
+		}
+
+		_, _, err := repo.SaveContacts(ctx, []*models.Contact{
+			{UserID: 1, Name: "Old Name", Phone: "444444444"},
+			{UserID: 1, Name: "Unchanged", Phone: "555555555"},
+		}, models.ImportModeInsertOnly)
+		assert.NoError(t, err)
+
+		inserted, updated, err := repo.SaveContacts(ctx, []*models.Contact{
+			{UserID: 1, Name: "First Name", Phone: "444444444"},
+			{UserID: 1, Name: "New Name", Phone: "444444444", Attributes: map[string]string{"Department": "IT"}}, // last row wins
+			{UserID: 1, Name: "Unchanged", Phone: "555555555"},
+			{UserID: 1, Name: "Brand New", Phone: "666666666"},
+		}, models.ImportModeUpsert)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, inserted)
+		assert.Equal(t, 1, updated)
+
+		var name string
+		var attributes map[string]string
+		err = testPool.QueryRow(ctx, `SELECT name, attributes FROM contacts WHERE phone = '444444444'`).Scan(&name, &attributes)
+		assert.NoError(t, err)
+		assert.Equal(t, "New Name", name)
+		assert.Equal(t, map[string]string{"Department": "IT"}, attributes)
+	})
+}
+
+func TestContactsRepository_MergeStagedContacts(t *testing.T) {
+	ctx := context.Background()
+
+	repo := repository.NewContactsRepository(testPool)
+
+	setup := func(t *testing.T) int {
+		fixtures := makeFixtures(t, testDB, "../../../../db/fixtures/users.yml")
+		require.NoError(t, fixtures.Load())
+
+		_, err := testPool.Exec(ctx, `TRUNCATE contacts, contact_imports CASCADE`)
+		require.NoError(t, err)
+
+		_, _, err = repo.SaveContacts(ctx, []*models.Contact{
+			{UserID: 1, Name: "Stays", Phone: "100000001"},
+			{UserID: 1, Name: "Renamed", Phone: "100000002"},
+			{UserID: 1, Name: "Leaves", Phone: "100000003"},
+			{UserID: 2, Name: "Other User", Phone: "100000004"},
+		}, models.ImportModeInsertOnly)
+		require.NoError(t, err)
+
+		var importID int
+		err = testPool.QueryRow(ctx, `
+			INSERT INTO contact_imports (user_id, filename, s3_key)
+			VALUES (1, 'contacts.csv', 'contacts/1_contacts.csv')
+			RETURNING id`).Scan(&importID)
+		require.NoError(t, err)
+
+		err = repo.StageContacts(ctx, importID, []*models.Contact{
+			{UserID: 1, Name: "Stays", Phone: "100000001"},
+			{UserID: 1, Name: "Renamed Now", Phone: "100000002"},
+			{UserID: 1, Name: "Joins", Phone: "100000005"},
+		})
+		require.NoError(t, err)
+
+		return importID
+	}
+
+	phones := func(t *testing.T, userID int) []string {
+		rows, err := testPool.Query(ctx, `SELECT phone FROM contacts WHERE user_id = $1 ORDER BY phone`, userID)
+		require.NoError(t, err)
+		res, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("sync deletes contacts missing from the file", func(t *testing.T) {
+		importID := setup(t)
+
+		changes, err := repo.MergeStagedContacts(ctx, 1, importID, models.ImportModeSync, false)
+		require.NoError(t, err)
+		assert.Equal(t, 1, changes.Inserted)
+		assert.Equal(t, 1, changes.Updated)
+		require.Len(t, changes.Deleted, 1)
+		assert.Equal(t, "Leaves", changes.Deleted[0].Name)
+
+		assert.Equal(t, []string{"100000001", "100000002", "100000005"}, phones(t, 1))
+		assert.Equal(t, []string{"100000004"}, phones(t, 2))
+
+		require.NoError(t, repo.ClearStagedContacts(ctx, importID))
+		var staged int
+		require.NoError(t, testPool.QueryRow(ctx, `SELECT count(*) FROM contact_import_rows`).Scan(&staged))
+		assert.Zero(t, staged)
+	})
+
+	t.Run("dry run changes nothing", func(t *testing.T) {
+		importID := setup(t)
+
+		changes, err := repo.MergeStagedContacts(ctx, 1, importID, models.ImportModeSync, true)
+		require.NoError(t, err)
+		assert.Equal(t, 1, changes.Inserted)
+		assert.Equal(t, 1, changes.Updated)
+		assert.Len(t, changes.Deleted, 1)
+
+		assert.Equal(t, []string{"100000001", "100000002", "100000003"}, phones(t, 1))
+	})
+
+	t.Run("insert-only keeps existing contacts", func(t *testing.T) {
+		importID := setup(t)
+
+		changes, err := repo.MergeStagedContacts(ctx, 1, importID, models.ImportModeInsertOnly, false)
+		require.NoError(t, err)
+		assert.Equal(t, &models.ImportChanges{Inserted: 1}, changes)
+
+		var name string
+		require.NoError(t, testPool.QueryRow(ctx, `SELECT name FROM contacts WHERE phone = '100000002'`).Scan(&name))
+		assert.Equal(t, "Renamed", name)
+	})
+}
+
+func TestContactsRepository_GetContactAttributes(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewContactsRepository(testPool)
+
+	fixtures := makeFixtures(t, testDB, "../../../../db/fixtures/users.yml")
+	require.NoError(t, fixtures.Load())
+	t.Cleanup(func() {
+		_, err := testPool.Exec(ctx, `TRUNCATE contact_attributes`)
+		require.NoError(t, err)
+	})
+
+	_, err := testPool.Exec(ctx, `
+		INSERT INTO contact_attributes (user_id, name, type, options, required)
+		VALUES (1, 'Shift', 'enum', '["Day", "Night"]', true),
+		       (2, 'Hired', 'date', '[]', false)
+	`)
+	require.NoError(t, err)
+
+	attrs, err := repo.GetContactAttributes(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, []*models.ContactAttribute{
+		{Name: "Shift", Type: models.AttributeEnum, Options: []string{"Day", "Night"}, Required: true},
+	}, attrs)
 }
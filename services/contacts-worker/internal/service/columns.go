@@ -0,0 +1,188 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/phoneutils"
+)
+
+// Header names recognised as the name and phone columns when the upload has no explicit mapping.
+var (
+	nameHeaders  = []string{"name", "full name", "fullname", "contact", "contact name", "employee", "имя", "фио", "ф.и.о.", "сотрудник"}
+	phoneHeaders = []string{"phone", "phone number", "mobile", "mobile phone", "cell", "cell phone", "tel", "phonenumber", "telephone", "телефон", "мобильный", "мобильный телефон", "номер телефона"}
+)
+
+const (
+	maxAttributes       = 50
+	maxAttributeNameLen = 64
+	maxAttributeLen     = 1024
+)
+
+// columnLayout tells which columns of a file hold the contact fields and how to read them.
+// Attributes maps the index of every other named column to its header; region is used
+// to parse phone numbers without a country code. Types maps the indexes of columns of
+// typed attributes to their definitions, and required lists the attributes every row must set.
+type columnLayout struct {
+	name       int
+	phone      int
+	attributes map[int]string
+	region     phoneutils.ISO3166Alpha2
+	types      map[int]*models.ContactAttribute
+	required   []*models.ContactAttribute
+}
+
+// withAttributes returns a copy of the layout whose attribute columns are matched, by
+// case-insensitive name, to the user's typed attributes and renamed as defined.
+func (l *columnLayout) withAttributes(defs []*models.ContactAttribute) *columnLayout {
+	if len(defs) == 0 {
+		return l
+	}
+
+	typed := *l
+	typed.attributes = make(map[int]string, len(l.attributes))
+	typed.types = make(map[int]*models.ContactAttribute)
+	for i, header := range l.attributes {
+		typed.attributes[i] = header
+		for _, d := range defs {
+			if strings.EqualFold(d.Name, header) {
+				typed.attributes[i] = d.Name
+				typed.types[i] = d
+				break
+			}
+		}
+	}
+	for _, d := range defs {
+		if d.Required {
+			typed.required = append(typed.required, d)
+		}
+	}
+
+	return &typed
+}
+
+// resolveLayout inspects the first record of a file and reports whether it is a header row.
+// Columns named in the mapping are looked up in the header by name or taken as 1-based
+// numbers; unmapped columns are recognised by well-known header names. Without a header
+// the name and phone are expected in the first two columns unless mapped by number, and
+// extra columns are ignored since they have no names.
+func (cs *ContactsService) resolveLayout(first []string, mapping domain.ColumnMapping, region phoneutils.ISO3166Alpha2) (*columnLayout, bool, error) {
+	if !cs.isHeader(first, mapping, region) {
+		name, err := columnNumber(mapping.Name, 0)
+		if err != nil {
+			return nil, false, err
+		}
+		phone, err := columnNumber(mapping.Phone, 1)
+		if err != nil {
+			return nil, false, err
+		}
+		return &columnLayout{name: name, phone: phone, region: region}, false, nil
+	}
+
+	layout, err := headerLayout(first, mapping, region)
+	return layout, true, err
+}
+
+// headerLayout finds the contact fields in a header row and keeps every other named column as an attribute.
+func headerLayout(first []string, mapping domain.ColumnMapping, region phoneutils.ISO3166Alpha2) (*columnLayout, error) {
+	headers := make([]string, len(first))
+	for i, h := range first {
+		headers[i] = normalizeHeader(h)
+	}
+
+	name, err := findColumn(headers, mapping.Name, nameHeaders, "name")
+	if err != nil {
+		return nil, err
+	}
+	phone, err := findColumn(headers, mapping.Phone, phoneHeaders, "phone")
+	if err != nil {
+		return nil, err
+	}
+
+	layout := &columnLayout{name: name, phone: phone, attributes: make(map[int]string), region: region}
+	for i, h := range first {
+		h = strings.TrimSpace(h)
+		if i == name || i == phone || h == "" || len(h) > maxAttributeNameLen {
+			continue
+		}
+		if len(layout.attributes) == maxAttributes {
+			break
+		}
+		layout.attributes[i] = h
+	}
+
+	return layout, nil
+}
+
+// isHeader reports whether the record looks like a header row: none of its cells is a
+// phone number, and a cell either matches a mapped column name or a well-known header.
+func (cs *ContactsService) isHeader(record []string, mapping domain.ColumnMapping, region phoneutils.ISO3166Alpha2) bool {
+	for _, cell := range record {
+		if _, ok := cs.validatePhone(cell, region); ok {
+			return false
+		}
+	}
+
+	for _, cell := range record {
+		h := normalizeHeader(cell)
+		if h == "" {
+			continue
+		}
+		if h == normalizeHeader(mapping.Name) || h == normalizeHeader(mapping.Phone) {
+			return true
+		}
+		if slices.Contains(nameHeaders, h) || slices.Contains(phoneHeaders, h) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// findColumn returns the index of the mapped column, or of the first header matching one of
+// the aliases when the field is not mapped.
+func findColumn(headers []string, mapped string, aliases []string, field string) (int, error) {
+	if mapped != "" {
+		if _, err := strconv.Atoi(mapped); err == nil {
+			return columnNumber(mapped, 0)
+		}
+		if i := slices.Index(headers, normalizeHeader(mapped)); i >= 0 {
+			return i, nil
+		}
+		return 0, fmt.Errorf("%w: %q", domain.ErrColumnNotFound, mapped)
+	}
+
+	for _, alias := range aliases {
+		if i := slices.Index(headers, alias); i >= 0 {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: no %s column in header", domain.ErrColumnNotFound, field)
+}
+
+// columnNumber converts a 1-based column number to an index, falling back to def when unmapped.
+func columnNumber(mapped string, def int) (int, error) {
+	if mapped == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(mapped)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q, file has no header row", domain.ErrColumnNotFound, mapped)
+	}
+	if n < 1 {
+		return 0, fmt.Errorf("%w: column number %d", domain.ErrColumnNotFound, n)
+	}
+	return n - 1, nil
+}
+
+// normalizeHeader lower-cases the header and treats underscores and dashes as spaces,
+// so that keys like "phone_number" in JSON files match the well-known headers.
+func normalizeHeader(h string) string {
+	h = strings.NewReplacer("_", " ", "-", " ").Replace(h)
+	return strings.ToLower(strings.TrimSpace(h))
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/phoneutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveLayout(t *testing.T) {
+	tests := []struct {
+		name       string
+		first      []string
+		mapping    domain.ColumnMapping
+		region     phoneutils.ISO3166Alpha2
+		wantLayout *columnLayout
+		wantHeader bool
+		wantErr    error
+	}{
+		{
+			name:       "data row without mapping",
+			first:      []string{"Alice", "+79123456789"},
+			wantLayout: &columnLayout{name: 0, phone: 1, region: phoneutils.RegionRU},
+		},
+		{
+			name:       "data row with numeric mapping",
+			first:      []string{"IT", "+79123456789", "Alice"},
+			mapping:    domain.ColumnMapping{Name: "3", Phone: "2"},
+			wantLayout: &columnLayout{name: 2, phone: 1, region: phoneutils.RegionRU},
+		},
+		{
+			name:       "local number in another region is data",
+			first:      []string{"Bob", "(202) 555-0123"},
+			region:     "US",
+			wantLayout: &columnLayout{name: 0, phone: 1, region: "US"},
+		},
+		{
+			name:    "data row mapped by header name",
+			first:   []string{"Alice", "+79123456789"},
+			mapping: domain.ColumnMapping{Phone: "Mobile"},
+			wantErr: domain.ErrColumnNotFound,
+		},
+		{
+			name:       "well-known headers",
+			first:      []string{"ФИО", "Телефон", "Отдел", ""},
+			wantLayout: &columnLayout{name: 0, phone: 1, attributes: map[int]string{2: "Отдел"}, region: phoneutils.RegionRU},
+			wantHeader: true,
+		},
+		{
+			name:       "mapped headers are case-insensitive",
+			first:      []string{"Employee ID", "MOBILE", "Full Name"},
+			mapping:    domain.ColumnMapping{Name: "full name", Phone: "mobile"},
+			wantLayout: &columnLayout{name: 2, phone: 1, attributes: map[int]string{0: "Employee ID"}, region: phoneutils.RegionRU},
+			wantHeader: true,
+		},
+		{
+			name:       "header with numeric mapping",
+			first:      []string{"Name", "Work", "Personal"},
+			mapping:    domain.ColumnMapping{Phone: "3"},
+			wantLayout: &columnLayout{name: 0, phone: 2, attributes: map[int]string{1: "Work"}, region: phoneutils.RegionRU},
+			wantHeader: true,
+		},
+		{
+			name:       "snake case keys",
+			first:      []string{"id", "full_name", "phone_number"},
+			wantLayout: &columnLayout{name: 1, phone: 2, attributes: map[int]string{0: "id"}, region: phoneutils.RegionRU},
+			wantHeader: true,
+		},
+		{
+			name:       "header without phone column",
+			first:      []string{"Name", "Email"},
+			wantHeader: true,
+			wantErr:    domain.ErrColumnNotFound,
+		},
+		{
+			name:    "invalid column number",
+			first:   []string{"Alice", "+79123456789"},
+			mapping: domain.ColumnMapping{Phone: "0"},
+			wantErr: domain.ErrColumnNotFound,
+		},
+	}
+
+	svc := &ContactsService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			region := tt.region
+			if region == "" {
+				region = phoneutils.RegionRU
+			}
+			layout, header, err := svc.resolveLayout(tt.first, tt.mapping, region)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantHeader, header)
+			assert.Equal(t, tt.wantLayout, layout)
+		})
+	}
+}
@@ -3,24 +3,91 @@ package service
 import (
 	"context"
 	"runtime"
+	"strings"
 	"sync"
 	"sync/atomic"
 
+	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/domain"
 	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/models"
 	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/phoneutils"
 )
 
-// rowProvider emits CSV records (as string slices) onto the provided jobsCh.
-type rowProvider func(jobsCh chan<- []string) error
+// Reasons reported for rows that are not imported.
+const (
+	rejectReasonMalformedRow   = "malformed row"
+	rejectReasonMissingColumns = "missing name or phone column"
+	rejectReasonInvalidName    = "name must be between 1 and 32 characters"
+	rejectReasonInvalidPhone   = "invalid phone number"
+	rejectReasonAttributeLen   = "attribute value is too long"
+	// the reasons below are followed by the name of the attribute
+	rejectReasonAttributeValue   = "invalid attribute value: "
+	rejectReasonMissingAttribute = "missing required attribute: "
+)
+
+// rawRow is a single record of the uploaded file. Line is the 1-based position of the
+// record in the file, or in its sheet for workbooks; Err is set when the record could not
+// be parsed. Header marks column names made up by providers of formats that have no header
+// row of their own. Layout is resolved from the first record of the file, or of each sheet,
+// and attached to every data row. Sheet is set for rows of workbooks only.
+type rawRow struct {
+	Line   int
+	Record []string
+	Err    error
+	Header bool
+	Layout *columnLayout
+	Sheet  *sheetRef
+}
+
+// rejectedRow is a row that was not imported, together with the reason why.
+// Sheet is empty for files other than workbooks.
+type rejectedRow struct {
+	Sheet      string
+	sheetIndex int
+	Line       int
+	Name       string
+	Phone      string
+	Reason     string
+}
+
+// reject records the row with the values of its name and phone columns, if present.
+func reject(row rawRow, reason string) rejectedRow {
+	r := rejectedRow{Line: row.Line, Reason: reason}
+	if row.Sheet != nil {
+		r.Sheet, r.sheetIndex = row.Sheet.Name, row.Sheet.Index
+	}
+	if row.Layout.name < len(row.Record) {
+		r.Name = row.Record[row.Layout.name]
+	}
+	if row.Layout.phone < len(row.Record) {
+		r.Phone = row.Record[row.Layout.phone]
+	}
+	return r
+}
+
+// ingestResult summarizes a processed file.
+type ingestResult struct {
+	stats    models.ImportStats
+	rejected []rejectedRow
+}
+
+// batchSaver persists a batch of valid contacts and returns how many of them were inserted and updated.
+type batchSaver func(ctx context.Context, batch []*models.Contact) (inserted, updated int, err error)
 
-// ingestAndSave reads rows via provider, validates & batches them, and writes to repository.
-func (cs *ContactsService) ingestAndSave(ctx context.Context, userID int, provider rowProvider) (int, error) {
+// rowProvider passes every record of a file, in order, to emit and stops on the first error emit returns.
+type rowProvider func(emit func(rawRow) error) error
+
+// ingestAndSave reads rows via provider, validates & batches them, and writes them with save.
+// The first row, of each sheet for workbooks, decides the column layout and is skipped if it is a header.
+// Phone numbers without a country code are parsed in the given region, and columns of the
+// user's typed attributes are checked against their definitions in attrs.
+// Rows failing validation are collected in the result instead of being saved.
+func (cs *ContactsService) ingestAndSave(ctx context.Context, userID int, mapping domain.ColumnMapping, region phoneutils.ISO3166Alpha2, attrs []*models.ContactAttribute, save batchSaver, provider rowProvider) (*ingestResult, error) {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	var total int32
+	var inserted, updated int32
 
-	jobsCh := make(chan []string, runtime.NumCPU()*2)
+	jobsCh := make(chan rawRow, runtime.NumCPU()*2)
 	writeCh := make(chan []*models.Contact, runtime.NumCPU())
 	errCh := make(chan error, 1)
 
@@ -29,23 +96,56 @@ func (cs *ContactsService) ingestAndSave(ctx context.Context, userID int, provid
 	wgWriter.Add(1)
 	go func() {
 		defer wgWriter.Done()
-		cs.runWriter(ctx, writeCh, errCh, &total)
+		cs.runWriter(ctx, save, writeCh, errCh, &inserted, &updated)
 	}()
 
-	// start workers
+	// start workers; each of them collects its own rejected rows and read count
+	workers := runtime.NumCPU()
+	rejected := make([][]rejectedRow, workers)
+	read := make([]int, workers)
 	var wgWorkers sync.WaitGroup
-	for i := 0; i < runtime.NumCPU(); i++ {
+	for i := 0; i < workers; i++ {
 		wgWorkers.Add(1)
 		go func() {
 			defer wgWorkers.Done()
-			cs.runWorker(ctx, userID, jobsCh, writeCh)
+			read[i], rejected[i] = cs.runWorker(ctx, userID, jobsCh, writeCh)
 		}()
 	}
 
-	// feed jobs
-	if err := provider(jobsCh); err != nil {
+	// feed jobs; the provider runs on this goroutine, so the layout needs no locking
+	var layout *columnLayout
+	var sheet *sheetRef
+	emit := func(row rawRow) error {
+		// every sheet of a workbook starts with its own header
+		if row.Sheet != sheet {
+			layout, sheet = nil, row.Sheet
+		}
+		if layout == nil {
+			var l *columnLayout
+			var header bool
+			var err error
+			if row.Header {
+				l, err = headerLayout(row.Record, mapping, region)
+				header = true
+			} else {
+				l, header, err = cs.resolveLayout(row.Record, mapping, region)
+			}
+			if err != nil {
+				return err
+			}
+			layout = l.withAttributes(attrs)
+			if header {
+				return nil
+			}
+		}
+		row.Layout = layout
+		jobsCh <- row
+		return nil
+	}
+
+	if err := provider(emit); err != nil {
 		close(jobsCh)
-		return 0, err
+		return nil, err
 	}
 	close(jobsCh)
 
@@ -55,14 +155,25 @@ func (cs *ContactsService) ingestAndSave(ctx context.Context, userID int, provid
 	wgWriter.Wait()
 	select {
 	case saveErr := <-errCh:
-		return int(atomic.LoadInt32(&total)), saveErr
+		return nil, saveErr
 	default:
-		return int(atomic.LoadInt32(&total)), nil
 	}
+
+	result := &ingestResult{}
+	for i := 0; i < workers; i++ {
+		result.stats.RowsRead += read[i]
+		result.rejected = append(result.rejected, rejected[i]...)
+	}
+	result.stats.RowsInvalid = len(result.rejected)
+	result.stats.RowsInserted = int(atomic.LoadInt32(&inserted))
+	result.stats.RowsUpdated = int(atomic.LoadInt32(&updated))
+	result.stats.RowsDuplicate = result.stats.RowsRead - result.stats.RowsInvalid - result.stats.RowsInserted - result.stats.RowsUpdated
+
+	return result, nil
 }
 
-// runWriter consumes batches from writeCh and saves them via repository.
-func (cs *ContactsService) runWriter(ctx context.Context, writeCh <-chan []*models.Contact, errCh chan<- error, total *int32) {
+// runWriter consumes batches from writeCh and saves them with save.
+func (cs *ContactsService) runWriter(ctx context.Context, save batchSaver, writeCh <-chan []*models.Contact, errCh chan<- error, inserted, updated *int32) {
 	for batch := range writeCh {
 		select {
 		case <-ctx.Done():
@@ -70,7 +181,8 @@ func (cs *ContactsService) runWriter(ctx context.Context, writeCh <-chan []*mode
 		default:
 		}
 
-		if err := cs.repository.SaveContacts(ctx, batch); err != nil {
+		ins, upd, err := save(ctx, batch)
+		if err != nil {
 			// publish first error only
 			select {
 			case errCh <- err:
@@ -79,13 +191,17 @@ func (cs *ContactsService) runWriter(ctx context.Context, writeCh <-chan []*mode
 			return
 		}
 
-		atomic.AddInt32(total, int32(len(batch)))
+		atomic.AddInt32(inserted, int32(ins))
+		atomic.AddInt32(updated, int32(upd))
 	}
 }
 
 // runWorker reads raw records, validates them, and sends full batches to writeCh.
-func (cs *ContactsService) runWorker(ctx context.Context, userID int, jobsCh <-chan []string, writeCh chan<- []*models.Contact) {
+// It returns the number of records read and the records that failed validation.
+func (cs *ContactsService) runWorker(ctx context.Context, userID int, jobsCh <-chan rawRow, writeCh chan<- []*models.Contact) (int, []rejectedRow) {
 	batch := make([]*models.Contact, 0, cs.batchSize)
+	var rejected []rejectedRow
+	var read int
 
 	flush := func() {
 		if len(batch) > 0 {
@@ -98,46 +214,99 @@ func (cs *ContactsService) runWorker(ctx context.Context, userID int, jobsCh <-c
 		select {
 		case <-ctx.Done():
 			flush()
-			return
+			return read, rejected
 
-		case rec, ok := <-jobsCh:
+		case row, ok := <-jobsCh:
 			if !ok {
 				flush()
-				return
+				return read, rejected
+			}
+
+			read++
+			c, reason := cs.makeContact(userID, row)
+			if c == nil {
+				rejected = append(rejected, reject(row, reason))
+				continue
 			}
 
-			if c := cs.makeContact(userID, rec); c != nil {
-				batch = append(batch, c)
-				if len(batch) >= cs.batchSize {
-					flush()
-				}
+			batch = append(batch, c)
+			if len(batch) >= cs.batchSize {
+				flush()
 			}
 		}
 	}
 }
 
-// makeContact applies validation to a raw record and returns a Contact or nil.
-func (cs *ContactsService) makeContact(userID int, rec []string) *models.Contact {
-	if len(rec) < 2 {
-		return nil
+// makeContact applies validation to a raw record and returns a Contact, or nil
+// together with the reason the record was rejected.
+func (cs *ContactsService) makeContact(userID int, row rawRow) (*models.Contact, string) {
+	if row.Err != nil {
+		return nil, rejectReasonMalformedRow
 	}
-	name, okName := cs.validateName(rec[0])
-	phone, okPhone := cs.validatePhone(rec[1])
-	if !okName || !okPhone {
-		return nil
+	layout := row.Layout
+	if len(row.Record) <= max(layout.name, layout.phone) {
+		return nil, rejectReasonMissingColumns
 	}
-	return &models.Contact{
-		UserID: userID,
-		Name:   name,
-		Phone:  phone,
+	name, ok := cs.validateName(strings.TrimSpace(row.Record[layout.name]))
+	if !ok {
+		return nil, rejectReasonInvalidName
 	}
+	phone, ok := cs.validatePhone(row.Record[layout.phone], layout.region)
+	if !ok {
+		return nil, rejectReasonInvalidPhone
+	}
+
+	var attributes map[string]string
+	for i, attr := range layout.attributes {
+		if i >= len(row.Record) {
+			continue
+		}
+		value := strings.TrimSpace(row.Record[i])
+		if value == "" {
+			continue
+		}
+		if len(value) > maxAttributeLen {
+			return nil, rejectReasonAttributeLen
+		}
+		if def := layout.types[i]; def != nil {
+			normalized, ok := def.Normalize(value)
+			if !ok {
+				return nil, rejectReasonAttributeValue + def.Name
+			}
+			value = normalized
+		}
+		if attributes == nil {
+			attributes = make(map[string]string, len(layout.attributes))
+		}
+		attributes[attr] = value
+	}
+
+	if row.Sheet != nil && row.Sheet.Group != "" {
+		if attributes == nil {
+			attributes = make(map[string]string, 1)
+		}
+		attributes[groupAttribute] = row.Sheet.Group
+	}
+
+	for _, def := range layout.required {
+		if _, ok := attributes[def.Name]; !ok {
+			return nil, rejectReasonMissingAttribute + def.Name
+		}
+	}
+
+	return &models.Contact{
+		UserID:     userID,
+		Name:       name,
+		Phone:      phone,
+		Attributes: attributes,
+	}, ""
 }
 
 func (cs *ContactsService) validateName(name string) (string, bool) {
 	return name, len(name) > 0 && len(name) <= 32
 }
 
-func (cs *ContactsService) validatePhone(phone string) (string, bool) {
-	formattedNum, err := phoneutils.FormatToE164(phone, phoneutils.RegionRU)
+func (cs *ContactsService) validatePhone(phone string, region phoneutils.ISO3166Alpha2) (string, bool) {
+	formattedNum, err := phoneutils.FormatToE164(phone, region)
 	return formattedNum, err == nil
 }
@@ -5,6 +5,9 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/phoneutils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -13,22 +16,27 @@ import (
 func TestIngestAndSave(t *testing.T) {
 	type setupFn func(m *MockContactsRepository)
 	providerFromRows := func(rows [][]string, errToReturn error) rowProvider {
-		return func(ch chan<- []string) error {
-			for _, r := range rows {
-				ch <- r
+		return func(emit func(rawRow) error) error {
+			for i, r := range rows {
+				if err := emit(rawRow{Line: i + 1, Record: r}); err != nil {
+					return err
+				}
 			}
 			return errToReturn
 		}
 	}
 
 	tests := []struct {
-		name        string
-		batchSize   int
-		rows        [][]string
-		providerErr error
-		setupMock   setupFn
-		wantTotal   int
-		wantErr     bool
+		name         string
+		batchSize    int
+		rows         [][]string
+		mapping      domain.ColumnMapping
+		region       phoneutils.ISO3166Alpha2
+		providerErr  error
+		setupMock    setupFn
+		wantStats    models.ImportStats
+		wantRejected []rejectedRow
+		wantErr      bool
 	}{
 		{
 			name:      "happy path, exact batch",
@@ -42,10 +50,10 @@ func TestIngestAndSave(t *testing.T) {
 				// here and further on we don't specify the expected number of calls because
 				// it might depend on the machine configuration and amount of logical cpus
 				m.
-					On("SaveContacts", mock.Anything, mock.Anything).
-					Return(nil)
+					On("SaveContacts", mock.Anything, mock.Anything, models.ImportModeInsertOnly).
+					Return(insertAll, 0, nil)
 			},
-			wantTotal: 2,
+			wantStats: models.ImportStats{RowsRead: 2, RowsInserted: 2},
 			wantErr:   false,
 		},
 		{
@@ -59,10 +67,29 @@ func TestIngestAndSave(t *testing.T) {
 			providerErr: nil,
 			setupMock: func(m *MockContactsRepository) {
 				m.
-					On("SaveContacts", mock.Anything, mock.Anything).
-					Return(nil)
+					On("SaveContacts", mock.Anything, mock.Anything, models.ImportModeInsertOnly).
+					Return(insertAll, 0, nil)
 			},
-			wantTotal: 2,
+			wantStats: models.ImportStats{RowsRead: 3, RowsInserted: 2, RowsInvalid: 1},
+			wantRejected: []rejectedRow{
+				{Line: 2, Name: "", Phone: "+79123456789", Reason: rejectReasonInvalidName},
+			},
+			wantErr: false,
+		},
+		{
+			name:      "duplicates of existing contacts",
+			batchSize: 2,
+			rows: [][]string{
+				{"Alice", "+79123456789"},
+				{"Bob", "+79123456788"},
+			},
+			providerErr: nil,
+			setupMock: func(m *MockContactsRepository) {
+				m.
+					On("SaveContacts", mock.Anything, mock.Anything, models.ImportModeInsertOnly).
+					Return(insertNone, 0, nil)
+			},
+			wantStats: models.ImportStats{RowsRead: 2, RowsDuplicate: 2},
 			wantErr:   false,
 		},
 		{
@@ -74,11 +101,10 @@ func TestIngestAndSave(t *testing.T) {
 			providerErr: nil,
 			setupMock: func(m *MockContactsRepository) {
 				m.
-					On("SaveContacts", mock.Anything, mock.Anything).
-					Return(assert.AnError)
+					On("SaveContacts", mock.Anything, mock.Anything, models.ImportModeInsertOnly).
+					Return(0, 0, assert.AnError)
 			},
-			wantTotal: 0, // nothing saved
-			wantErr:   true,
+			wantErr: true,
 		},
 		{
 			name:        "provider returns error",
@@ -86,7 +112,6 @@ func TestIngestAndSave(t *testing.T) {
 			rows:        [][]string{},
 			providerErr: assert.AnError,
 			setupMock:   func(m *MockContactsRepository) {},
-			wantTotal:   0,
 			wantErr:     true,
 		},
 		{
@@ -95,11 +120,73 @@ func TestIngestAndSave(t *testing.T) {
 			rows: [][]string{
 				{"", "+79120000000"},   // invalid name
 				{"Eve", "not-a-phone"}, // invalid phone
+				{"Mallory"},            // missing phone
 			},
 			providerErr: nil,
 			setupMock:   func(m *MockContactsRepository) {},
-			wantTotal:   0,
-			wantErr:     false,
+			wantStats:   models.ImportStats{RowsRead: 3, RowsInvalid: 3},
+			wantRejected: []rejectedRow{
+				{Line: 1, Name: "", Phone: "+79120000000", Reason: rejectReasonInvalidName},
+				{Line: 2, Name: "Eve", Phone: "not-a-phone", Reason: rejectReasonInvalidPhone},
+				{Line: 3, Name: "Mallory", Reason: rejectReasonMissingColumns},
+			},
+			wantErr: false,
+		},
+		{
+			name:      "header row is skipped and extra columns become attributes",
+			batchSize: 10,
+			rows: [][]string{
+				{"Department", "Mobile", "Full Name"},
+				{"IT", "+79123456789", "Alice"},
+				{"", "12", "Bob"},
+			},
+			mapping: domain.ColumnMapping{Name: "full name"},
+			setupMock: func(m *MockContactsRepository) {
+				m.
+					On("SaveContacts", mock.Anything, []*models.Contact{
+						{UserID: 42, Name: "Alice", Phone: "+79123456789", Attributes: map[string]string{"Department": "IT"}},
+					}, models.ImportModeInsertOnly).
+					Return(insertAll, 0, nil).
+					Once()
+			},
+			wantStats: models.ImportStats{RowsRead: 2, RowsInserted: 1, RowsInvalid: 1},
+			wantRejected: []rejectedRow{
+				{Line: 3, Name: "Bob", Phone: "12", Reason: rejectReasonInvalidPhone},
+			},
+			wantErr: false,
+		},
+		{
+			name:      "local numbers are parsed in the task region",
+			batchSize: 10,
+			rows: [][]string{
+				{"Alice", "(202) 555-0123"},
+				{"Bob", "8 (912) 345-6789"},
+			},
+			region: "US",
+			setupMock: func(m *MockContactsRepository) {
+				m.
+					On("SaveContacts", mock.Anything, []*models.Contact{
+						{UserID: 42, Name: "Alice", Phone: "+12025550123"},
+					}, models.ImportModeInsertOnly).
+					Return(insertAll, 0, nil).
+					Once()
+			},
+			wantStats: models.ImportStats{RowsRead: 2, RowsInserted: 1, RowsInvalid: 1},
+			wantRejected: []rejectedRow{
+				{Line: 2, Name: "Bob", Phone: "8 (912) 345-6789", Reason: rejectReasonInvalidPhone},
+			},
+			wantErr: false,
+		},
+		{
+			name:      "mapped column missing from header",
+			batchSize: 10,
+			rows: [][]string{
+				{"Name", "Phone"},
+				{"Alice", "+79123456789"},
+			},
+			mapping:   domain.ColumnMapping{Phone: "Mobile"},
+			setupMock: func(m *MockContactsRepository) {},
+			wantErr:   true,
 		},
 	}
 
@@ -113,20 +200,105 @@ func TestIngestAndSave(t *testing.T) {
 				batchSize:  tt.batchSize,
 			}
 
-			gotTotal, err := svc.ingestAndSave(context.Background(), 42, providerFromRows(tt.rows, tt.providerErr))
+			region := tt.region
+			if region == "" {
+				region = phoneutils.RegionRU
+			}
+
+			save := func(ctx context.Context, batch []*models.Contact) (int, int, error) {
+				return m.SaveContacts(ctx, batch, models.ImportModeInsertOnly)
+			}
+
+			got, err := svc.ingestAndSave(context.Background(), 42, tt.mapping, region, nil, save, providerFromRows(tt.rows, tt.providerErr))
 
 			if tt.wantErr {
 				require.Error(t, err)
 			} else {
 				require.NoError(t, err)
+				assert.Equal(t, tt.wantStats, got.stats)
+				assert.ElementsMatch(t, tt.wantRejected, got.rejected)
 			}
-			assert.Equal(t, tt.wantTotal, gotTotal)
 
 			m.AssertExpectations(t)
 		})
 	}
 }
 
+func TestMakeContact(t *testing.T) {
+	svc := &ContactsService{}
+	layout := &columnLayout{name: 0, phone: 1, attributes: map[int]string{2: "Department"}, region: phoneutils.RegionRU}
+	tests := []struct {
+		name       string
+		row        rawRow
+		wantReason string
+	}{
+		{"valid", rawRow{Record: []string{"John", "+79123456789"}, Layout: layout}, ""},
+		{"valid with attribute", rawRow{Record: []string{"John", "+79123456789", "IT"}, Layout: layout}, ""},
+		{"malformed", rawRow{Record: []string{"John", "+79123456789"}, Err: assert.AnError, Layout: layout}, rejectReasonMalformedRow},
+		{"missing columns", rawRow{Record: []string{"John"}, Layout: layout}, rejectReasonMissingColumns},
+		{"invalid name", rawRow{Record: []string{"", "+79123456789"}, Layout: layout}, rejectReasonInvalidName},
+		{"invalid phone", rawRow{Record: []string{"John", "12"}, Layout: layout}, rejectReasonInvalidPhone},
+		{"attribute too long", rawRow{Record: []string{"John", "+79123456789", strings.Repeat("x", maxAttributeLen+1)}, Layout: layout}, rejectReasonAttributeLen},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, reason := svc.makeContact(42, tt.row)
+			assert.Equal(t, tt.wantReason, reason)
+			assert.Equal(t, tt.wantReason == "", c != nil)
+		})
+	}
+}
+
+func TestMakeContact_TypedAttributes(t *testing.T) {
+	svc := &ContactsService{}
+	layout := (&columnLayout{
+		name:       0,
+		phone:      1,
+		attributes: map[int]string{2: "shift", 3: "Hired", 4: "Note"},
+		region:     phoneutils.RegionRU,
+	}).withAttributes([]*models.ContactAttribute{
+		{Name: "Shift", Type: models.AttributeEnum, Options: []string{"Day", "Night"}, Required: true},
+		{Name: "Hired", Type: models.AttributeDate},
+	})
+
+	tests := []struct {
+		name       string
+		record     []string
+		wantReason string
+		wantAttrs  map[string]string
+	}{
+		{
+			name:      "normalized",
+			record:    []string{"John", "+79123456789", "night", "31.01.2024", "free text"},
+			wantAttrs: map[string]string{"Shift": "Night", "Hired": "2024-01-31", "Note": "free text"},
+		},
+		{
+			name:       "invalid enum value",
+			record:     []string{"John", "+79123456789", "evening", "", ""},
+			wantReason: rejectReasonAttributeValue + "Shift",
+		},
+		{
+			name:       "invalid date",
+			record:     []string{"John", "+79123456789", "Day", "yesterday", ""},
+			wantReason: rejectReasonAttributeValue + "Hired",
+		},
+		{
+			name:       "missing required",
+			record:     []string{"John", "+79123456789", "", "2024-01-31", ""},
+			wantReason: rejectReasonMissingAttribute + "Shift",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, reason := svc.makeContact(42, rawRow{Record: tt.record, Layout: layout})
+			assert.Equal(t, tt.wantReason, reason)
+			if tt.wantReason == "" {
+				assert.Equal(t, tt.wantAttrs, c.Attributes)
+			}
+		})
+	}
+}
+
 func TestValidateName(t *testing.T) {
 	svc := &ContactsService{}
 	tests := []struct {
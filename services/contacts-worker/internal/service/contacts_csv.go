@@ -2,25 +2,30 @@ package service
 
 import (
 	"encoding/csv"
+	"errors"
 	"io"
 )
 
-func (cs *ContactsService) createCsvRowProvider(file io.Reader) (rowProvider, error) {
-	reader := csv.NewReader(file)
+// createCsvRowProvider reads CSV records from decoded text, splitting them on the delimiter
+// sniffed from the sample of the first lines.
+func (cs *ContactsService) createCsvRowProvider(text io.Reader, sample []byte) rowProvider {
+	reader := csv.NewReader(text)
+	reader.Comma = sniffDelimiter(sample)
 	reader.FieldsPerRecord = -1
 
-	provider := func(jobs chan<- []string) error {
-		for {
+	return func(emit func(rawRow) error) error {
+		for line := 1; ; line++ {
 			record, err := reader.Read()
-			if err != nil {
-				if err == io.EOF {
-					return nil
-				}
-				continue
+			if err == io.EOF {
+				return nil
+			}
+			var parseErr *csv.ParseError
+			if err != nil && !errors.As(err, &parseErr) {
+				return err
+			}
+			if err := emit(rawRow{Line: line, Record: record, Err: err}); err != nil {
+				return err
 			}
-			jobs <- record
 		}
 	}
-
-	return provider, nil
 }
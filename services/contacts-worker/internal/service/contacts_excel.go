@@ -4,53 +4,117 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 
+	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/domain"
 	"github.com/xuri/excelize/v2"
 )
 
-func (cs *ContactsService) createExcelRowProvider(file io.Reader) (rowProvider, error) {
-	f, rowsIter, err := cs.getRowsFromExcel(file)
+// groupAttribute is the contact attribute holding the group a sheet is mapped to.
+const groupAttribute = "Group"
+
+// sheetRef identifies the sheet a row was read from. Index is the position of the
+// sheet among the imported ones; Group is empty when the sheet is not mapped to one.
+type sheetRef struct {
+	Index int
+	Name  string
+	Group string
+}
+
+// createExcelRowProvider reads the selected sheets of a workbook one after another, streaming
+// each through excelize.Rows. Every sheet has its own header row, and lines are counted per sheet.
+// The workbook is closed once the provider has run.
+func (cs *ContactsService) createExcelRowProvider(file io.Reader, selection domain.SheetSelection) (rowProvider, error) {
+	f, err := excelize.OpenReader(file)
 	if err != nil {
 		return nil, err
 	}
+
+	sheets, err := selectSheets(f.GetSheetList(), selection)
+	if err != nil {
+		return nil, errors.Join(err, f.Close())
+	}
+
+	provider := func(emit func(rawRow) error) (err error) {
+		defer func() {
+			if cerr := f.Close(); cerr != nil {
+				err = errors.Join(err, cerr)
+			}
+		}()
+
+		for i, name := range sheets {
+			sheet := &sheetRef{Index: i, Name: name, Group: sheetGroup(selection, name)}
+			if err := emitSheetRows(f, sheet, emit); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return provider, nil
+}
+
+func emitSheetRows(f *excelize.File, sheet *sheetRef, emit func(rawRow) error) (err error) {
+	rowsIter, err := f.Rows(sheet.Name)
+	if err != nil {
+		return err
+	}
 	defer func() {
 		if cerr := rowsIter.Close(); cerr != nil {
 			err = errors.Join(err, cerr)
 		}
-		if cerr := f.Close(); cerr != nil {
-			err = errors.Join(err, cerr)
-		}
 	}()
 
-	provider := func(jobs chan<- []string) error {
-		for rowsIter.Next() {
-			cols, err := rowsIter.Columns()
-			if err != nil {
-				continue
-			}
-			jobs <- cols
+	for line := 1; rowsIter.Next(); line++ {
+		cols, err := rowsIter.Columns()
+		if err := emit(rawRow{Line: line, Record: cols, Err: err, Sheet: sheet}); err != nil {
+			return err
 		}
-		return rowsIter.Error()
 	}
-
-	return provider, err
+	return rowsIter.Error()
 }
 
-func (cs *ContactsService) getRowsFromExcel(file io.Reader) (*excelize.File, *excelize.Rows, error) {
-	f, err := excelize.OpenReader(file)
-	if err != nil {
-		return nil, nil, err
+// selectSheets returns the names of the workbook sheets to import, in import order.
+func selectSheets(list []string, selection domain.SheetSelection) ([]string, error) {
+	if len(list) == 0 {
+		return nil, fmt.Errorf("no sheets")
+	}
+	if selection.All {
+		return list, nil
+	}
+	if len(selection.Names) == 0 {
+		return list[:1], nil
 	}
 
-	sheets := f.GetSheetList()
-	if len(sheets) == 0 {
-		return nil, nil, fmt.Errorf("no sheets")
+	sheets := make([]string, 0, len(selection.Names))
+	for _, name := range selection.Names {
+		i := indexFold(list, name)
+		if i < 0 {
+			return nil, fmt.Errorf("%w: %q", domain.ErrSheetNotFound, name)
+		}
+		if indexFold(sheets, list[i]) < 0 {
+			sheets = append(sheets, list[i])
+		}
 	}
+	return sheets, nil
+}
 
-	rowsIter, err := f.Rows(sheets[0])
-	if err != nil {
-		return nil, nil, err
+// sheetGroup returns the group the sheet is mapped to, or an empty string.
+func sheetGroup(selection domain.SheetSelection, sheet string) string {
+	for name, group := range selection.Groups {
+		if strings.EqualFold(name, sheet) {
+			return group
+		}
 	}
+	return ""
+}
 
-	return f, rowsIter, nil
+// indexFold returns the index of the first element of list equal to s under case-folding, or -1.
+func indexFold(list []string, s string) int {
+	for i, v := range list {
+		if strings.EqualFold(v, s) {
+			return i
+		}
+	}
+	return -1
 }
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+// makeWorkbook builds an XLSX file with the given sheets in order.
+func makeWorkbook(t *testing.T, sheets []string, rows map[string][][]string) []byte {
+	t.Helper()
+	f := excelize.NewFile()
+	defer func() {
+		_ = f.Close()
+	}()
+
+	for i, sheet := range sheets {
+		if i == 0 {
+			require.NoError(t, f.SetSheetName("Sheet1", sheet))
+		} else {
+			_, err := f.NewSheet(sheet)
+			require.NoError(t, err)
+		}
+		for r, row := range rows[sheet] {
+			cell, err := excelize.CoordinatesToCellName(1, r+1)
+			require.NoError(t, err)
+			values := make([]any, len(row))
+			for i, v := range row {
+				values[i] = v
+			}
+			require.NoError(t, f.SetSheetRow(sheet, cell, &values))
+		}
+	}
+
+	buf, err := f.WriteToBuffer()
+	require.NoError(t, err)
+	return buf.Bytes()
+}
+
+func TestExcelRowProvider(t *testing.T) {
+	workbook := makeWorkbook(t, []string{"Sales", "IT", "Empty"}, map[string][][]string{
+		"Sales": {{"Name", "Phone"}, {"Alice", "+79123456789"}},
+		"IT":    {{"Phone", "Name"}, {"+79123456788", "Bob"}, {"+79123456787", "Carol"}},
+	})
+
+	tests := []struct {
+		name      string
+		selection domain.SheetSelection
+		want      []rawRow
+	}{
+		{
+			name: "first sheet by default",
+			want: []rawRow{
+				{Line: 1, Record: []string{"Name", "Phone"}},
+				{Line: 2, Record: []string{"Alice", "+79123456789"}},
+			},
+		},
+		{
+			name:      "selected sheets in the given order",
+			selection: domain.SheetSelection{Names: []string{"it", "Sales", "IT"}, Groups: map[string]string{"SALES": "Sales team"}},
+			want: []rawRow{
+				{Line: 1, Record: []string{"Phone", "Name"}, Sheet: &sheetRef{Index: 0, Name: "IT"}},
+				{Line: 2, Record: []string{"+79123456788", "Bob"}, Sheet: &sheetRef{Index: 0, Name: "IT"}},
+				{Line: 3, Record: []string{"+79123456787", "Carol"}, Sheet: &sheetRef{Index: 0, Name: "IT"}},
+				{Line: 1, Record: []string{"Name", "Phone"}, Sheet: &sheetRef{Index: 1, Name: "Sales", Group: "Sales team"}},
+				{Line: 2, Record: []string{"Alice", "+79123456789"}, Sheet: &sheetRef{Index: 1, Name: "Sales", Group: "Sales team"}},
+			},
+		},
+		{
+			name:      "all sheets",
+			selection: domain.SheetSelection{All: true},
+			want: []rawRow{
+				{Line: 1, Record: []string{"Name", "Phone"}, Sheet: &sheetRef{Index: 0, Name: "Sales"}},
+				{Line: 2, Record: []string{"Alice", "+79123456789"}, Sheet: &sheetRef{Index: 0, Name: "Sales"}},
+				{Line: 1, Record: []string{"Phone", "Name"}, Sheet: &sheetRef{Index: 1, Name: "IT"}},
+				{Line: 2, Record: []string{"+79123456788", "Bob"}, Sheet: &sheetRef{Index: 1, Name: "IT"}},
+				{Line: 3, Record: []string{"+79123456787", "Carol"}, Sheet: &sheetRef{Index: 1, Name: "IT"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &ContactsService{}
+			provider, err := svc.createExcelRowProvider(bytes.NewReader(workbook), tt.selection)
+			require.NoError(t, err)
+
+			rows := collectRows(t, provider)
+			require.Len(t, rows, len(tt.want))
+			for i, want := range tt.want {
+				assert.Equal(t, want.Line, rows[i].Line)
+				assert.Equal(t, want.Record, rows[i].Record)
+				if want.Sheet != nil {
+					assert.Equal(t, want.Sheet, rows[i].Sheet)
+				}
+			}
+		})
+	}
+}
+
+func TestExcelRowProvider_SheetNotFound(t *testing.T) {
+	workbook := makeWorkbook(t, []string{"Sales"}, nil)
+
+	svc := &ContactsService{}
+	_, err := svc.createExcelRowProvider(bytes.NewReader(workbook), domain.SheetSelection{Names: []string{"HR"}})
+	assert.ErrorIs(t, err, domain.ErrSheetNotFound)
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/domain"
+)
+
+var errNotJSONObject = errors.New("record is not a json object")
+
+// createJSONRowProvider reads a JSON array of contact objects. The array is decoded one
+// element at a time, so the line of a record is the 1-based position of its object.
+func (cs *ContactsService) createJSONRowProvider(text io.Reader) rowProvider {
+	return func(emit func(rawRow) error) error {
+		dec := json.NewDecoder(text)
+
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("%w: %v", domain.ErrMalformedFile, err)
+		}
+		if tok != json.Delim('[') {
+			return fmt.Errorf("%w: expected an array of objects", domain.ErrMalformedFile)
+		}
+
+		objects := &jsonObjectRows{emit: emit}
+		for line := 1; dec.More(); line++ {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return fmt.Errorf("%w: %v", domain.ErrMalformedFile, err)
+			}
+			if err := objects.add(line, raw); err != nil {
+				return err
+			}
+		}
+
+		if _, err := dec.Token(); err != nil {
+			return fmt.Errorf("%w: %v", domain.ErrMalformedFile, err)
+		}
+		return objects.flush()
+	}
+}
+
+// createNDJSONRowProvider reads newline-delimited JSON with one contact object per line.
+// Blank lines are skipped, and a line that is not a valid object is rejected on its own.
+func (cs *ContactsService) createNDJSONRowProvider(text io.Reader) rowProvider {
+	return func(emit func(rawRow) error) error {
+		br := bufio.NewReader(text)
+		objects := &jsonObjectRows{emit: emit}
+
+		for line := 1; ; line++ {
+			l, err := br.ReadBytes('\n')
+			if err != nil && !errors.Is(err, io.EOF) {
+				return err
+			}
+			if trimmed := bytes.TrimSpace(l); len(trimmed) > 0 {
+				if aerr := objects.add(line, trimmed); aerr != nil {
+					return aerr
+				}
+			}
+			if err != nil {
+				return objects.flush()
+			}
+		}
+	}
+}
+
+// jsonObjectRows turns JSON objects into records. The keys of the first valid object
+// become the header; later objects are laid out in its column order, and keys missing
+// from it are ignored. Invalid objects met before the header are held back until it is emitted.
+type jsonObjectRows struct {
+	emit    func(rawRow) error
+	columns map[string]int
+	header  []string
+	pending []rawRow
+}
+
+func (jr *jsonObjectRows) add(line int, raw []byte) error {
+	keys, values, err := parseJSONObject(raw)
+	if err != nil {
+		row := rawRow{Line: line, Err: err}
+		if jr.columns == nil {
+			jr.pending = append(jr.pending, row)
+			return nil
+		}
+		return jr.emit(row)
+	}
+
+	if jr.columns == nil {
+		jr.columns = make(map[string]int, len(keys))
+		for _, k := range keys {
+			if _, ok := jr.columns[k]; !ok {
+				jr.columns[k] = len(jr.header)
+				jr.header = append(jr.header, k)
+			}
+		}
+		if err := jr.emit(rawRow{Record: jr.header, Header: true}); err != nil {
+			return err
+		}
+		if err := jr.flush(); err != nil {
+			return err
+		}
+	}
+
+	record := make([]string, len(jr.header))
+	for i, k := range keys {
+		if col, ok := jr.columns[k]; ok {
+			record[col] = values[i]
+		}
+	}
+	return jr.emit(rawRow{Line: line, Record: record})
+}
+
+// flush emits the invalid objects held back before the header.
+func (jr *jsonObjectRows) flush() error {
+	for _, row := range jr.pending {
+		if err := jr.emit(row); err != nil {
+			return err
+		}
+	}
+	jr.pending = nil
+	return nil
+}
+
+// parseJSONObject returns the keys of a JSON object in their order together with their values as text.
+func parseJSONObject(raw []byte) ([]string, []string, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, nil, err
+	}
+	if tok != json.Delim('{') {
+		return nil, nil, errNotJSONObject
+	}
+
+	var keys, values []string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return nil, nil, errNotJSONObject
+		}
+
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, key)
+		values = append(values, jsonValueText(v))
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, nil, err
+	}
+	return keys, values, nil
+}
+
+// jsonValueText renders a JSON value as a cell: arrays are joined with commas
+// and nested objects are kept as compact JSON.
+func jsonValueText(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	case []any:
+		parts := make([]string, 0, len(v))
+		for _, e := range v {
+			parts = append(parts, jsonValueText(e))
+		}
+		return strings.Join(parts, ", ")
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
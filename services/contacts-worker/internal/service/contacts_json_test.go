@@ -0,0 +1,93 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONRowProvider(t *testing.T) {
+	const file = `[
+		{"name": "Alice", "phone": "+79123456789", "dept": "IT", "tags": ["a", "b"]},
+		"not an object",
+		{"phone": 79123456788, "name": "Bob", "extra": {"x": 1}, "vip": true}
+	]`
+
+	svc := &ContactsService{}
+	rows := collectRows(t, svc.createJSONRowProvider(strings.NewReader(file)))
+
+	require.Len(t, rows, 4)
+	assert.True(t, rows[0].Header)
+	assert.Equal(t, []string{"name", "phone", "dept", "tags"}, rows[0].Record)
+
+	assert.Equal(t, rawRow{Line: 1, Record: []string{"Alice", "+79123456789", "IT", "a, b"}}, rows[1])
+
+	assert.Equal(t, 2, rows[2].Line)
+	assert.ErrorIs(t, rows[2].Err, errNotJSONObject)
+
+	// keys missing from the first object are ignored
+	assert.Equal(t, rawRow{Line: 3, Record: []string{"Bob", "79123456788", "", ""}}, rows[3])
+}
+
+func TestJSONRowProvider_Malformed(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+	}{
+		{"not an array", `{"name": "Alice"}`},
+		{"broken syntax", `[{"name": "Alice"}, {"name": ]`},
+		{"unterminated array", `[{"name": "Alice", "phone": "+79123456789"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &ContactsService{}
+			err := svc.createJSONRowProvider(strings.NewReader(tt.file))(func(rawRow) error { return nil })
+			assert.ErrorIs(t, err, domain.ErrMalformedFile)
+		})
+	}
+}
+
+func TestNDJSONRowProvider(t *testing.T) {
+	const file = "{broken\n" +
+		`{"Full Name": "Alice", "Mobile": "+79123456789"}` + "\n" +
+		"\n" +
+		`{"Mobile": "+79123456788", "Full Name": "Bob"}`
+
+	svc := &ContactsService{}
+	rows := collectRows(t, svc.createNDJSONRowProvider(strings.NewReader(file)))
+
+	require.Len(t, rows, 4)
+	assert.True(t, rows[0].Header)
+	assert.Equal(t, []string{"Full Name", "Mobile"}, rows[0].Record)
+
+	// the broken first line is held back until the header is known
+	assert.Equal(t, 1, rows[1].Line)
+	assert.Error(t, rows[1].Err)
+
+	assert.Equal(t, rawRow{Line: 2, Record: []string{"Alice", "+79123456789"}}, rows[2])
+	assert.Equal(t, rawRow{Line: 4, Record: []string{"Bob", "+79123456788"}}, rows[3])
+}
+
+func TestDetectTextFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample string
+		want   textFormat
+	}{
+		{"csv", "name,phone\nAlice,+79123456789\n", textFormatCSV},
+		{"vcard", "\r\nbegin:vcard\r\nVERSION:3.0\r\n", textFormatVCard},
+		{"json array", "  [\n  {\"name\": \"Alice\"}", textFormatJSON},
+		{"ndjson", "{\"name\": \"Alice\"}\n{\"name\": \"Bob\"}\n", textFormatNDJSON},
+		{"empty", "", textFormatCSV},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, detectTextFormat([]byte(tt.sample)))
+		})
+	}
+}
@@ -2,49 +2,117 @@ package service
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"encoding/csv"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/phoneutils"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3"
 )
 
 // ContactsService processes tasks by downloading files from S3,
-// parsing their contents, validating each record, and sending
-// them to Kafka. It also deletes the file upon successful processing.
+// parsing their contents, validating each record, and saving valid
+// contacts to the database. Rejected rows are written to a CSV report
+// in S3 and the import job is updated with the outcome. It also deletes
+// the file upon successful processing.
 type ContactsService struct {
-	repository     domain.ContactsRepository
-	s3Client       domain.S3Client
-	bucket         string
-	contextTimeout time.Duration
-	batchSize      int
+	repository       domain.ContactsRepository
+	importRepository domain.ContactImportRepository
+	s3Client         domain.S3Client
+	bucket           string
+	contextTimeout   time.Duration
+	batchSize        int
 }
 
 // NewContactsService constructs a new ContactsService.
-func NewContactsService(r domain.ContactsRepository, s3Client domain.S3Client, bucket string, timeout time.Duration, batchSize int) *ContactsService {
+func NewContactsService(r domain.ContactsRepository, ir domain.ContactImportRepository, s3Client domain.S3Client, bucket string, timeout time.Duration, batchSize int) *ContactsService {
 	return &ContactsService{
-		repository:     r,
-		s3Client:       s3Client,
-		bucket:         bucket,
-		contextTimeout: timeout,
-		batchSize:      batchSize,
+		repository:       r,
+		importRepository: ir,
+		s3Client:         s3Client,
+		bucket:           bucket,
+		contextTimeout:   timeout,
+		batchSize:        batchSize,
 	}
 }
 
 // ProcessFile retrieves the file specified by task.S3Key from S3, determines
-// its type by magic bytes, and processes CSV or Excel accordingly. It returns
-// the number of successfully processed records and any error encountered.
-func (cs *ContactsService) ProcessFile(ctx context.Context, task *domain.Task) (total int, err error) {
+// its type by magic bytes, and processes Excel or a text format (CSV, vCard,
+// JSON or NDJSON) accordingly. It returns
+// the row counters of the import and any error encountered.
+//
+// When the task belongs to an import job, the job is marked processing, then
+// done with its counters and report keys, or failed with the error.
+//
+// Insert-only and upsert imports save the contacts batch by batch. Sync imports and
+// dry runs stage them first and merge them in one transaction once the whole file is
+// read; a sync then deletes the user's contacts missing from the file and lists them
+// in a CSV report. A dry run rolls the merge back, so it only reports the changes.
+func (cs *ContactsService) ProcessFile(ctx context.Context, task *domain.Task) (stats *models.ImportStats, err error) {
+	if task.ImportID != 0 {
+		err = cs.importRepository.StartContactImport(ctx, task.ImportID)
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			// stats are returned only once the job is completed, e.g. when
+			// just the cleanup of the uploaded file fails
+			if err != nil && stats == nil {
+				// the processing deadline may already be exceeded
+				ferr := cs.importRepository.FailContactImport(context.WithoutCancel(ctx), task.ImportID, err.Error())
+				err = errors.Join(err, ferr)
+			}
+		}()
+	}
+
+	mode := task.Mode
+	switch mode {
+	case "":
+		// tasks published before import modes existed carry none
+		mode = models.ImportModeInsertOnly
+	case models.ImportModeInsertOnly, models.ImportModeUpsert, models.ImportModeSync:
+	default:
+		return nil, domain.ErrUnsupportedImportMode
+	}
+
+	save := func(ctx context.Context, batch []*models.Contact) (int, int, error) {
+		return cs.repository.SaveContacts(ctx, batch, mode)
+	}
+
+	staged := mode == models.ImportModeSync || task.DryRun
+	if staged {
+		// staged rows are kept by import job
+		if task.ImportID == 0 {
+			return nil, domain.ErrContactImportNotExists
+		}
+
+		save = func(ctx context.Context, batch []*models.Contact) (int, int, error) {
+			return 0, 0, cs.repository.StageContacts(ctx, task.ImportID, batch)
+		}
+
+		defer func() {
+			cerr := cs.repository.ClearStagedContacts(context.WithoutCancel(ctx), task.ImportID)
+			err = errors.Join(err, cerr)
+		}()
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, cs.contextTimeout)
 	defer cancel()
 
 	object, err := cs.getFileFromS3(ctx, task.S3Key)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	defer func() {
 		if cerr := object.Body.Close(); cerr != nil {
@@ -56,33 +124,158 @@ func (cs *ContactsService) ProcessFile(ctx context.Context, task *domain.Task) (
 
 	header, err := br.Peek(512)
 	if err != nil && !errors.Is(err, bufio.ErrBufferFull) && !errors.Is(err, io.EOF) {
-		return 0, err
+		return nil, err
 	}
 
 	var rowProvider rowProvider
 	switch http.DetectContentType(header) {
-	case "text/plain; charset=utf-8", "text/csv":
-		rowProvider, err = cs.createCsvRowProvider(br)
+	case "text/plain; charset=utf-8", "text/plain; charset=utf-16le", "text/plain; charset=utf-16be", "text/csv":
+		rowProvider, err = cs.createTextRowProvider(br)
 	case "application/zip":
-		rowProvider, err = cs.createExcelRowProvider(br)
+		rowProvider, err = cs.createExcelRowProvider(br, task.Sheets)
 	default:
-		return 0, nil
+		return nil, domain.ErrUnsupportedFileType
 	}
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	total, err = cs.ingestAndSave(ctx, task.UserID, rowProvider)
+	// tasks published before regions were configurable carry none
+	region, ok := phoneutils.ParseRegion(task.Region)
+	if !ok {
+		region = phoneutils.RegionRU
+	}
+
+	attrs, err := cs.repository.GetContactAttributes(ctx, task.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := cs.ingestAndSave(ctx, task.UserID, task.Mapping, region, attrs, save, rowProvider)
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+
+	var deleted []*models.Contact
+	if staged {
+		deleted, err = cs.mergeStaged(ctx, task, mode, &result.stats)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if task.ImportID != 0 {
+		reportKey, err := cs.uploadRejectionReport(ctx, task.ImportID, result.rejected)
+		if err != nil {
+			return nil, err
+		}
+
+		deletionsKey, err := cs.uploadDeletionsReport(ctx, task.ImportID, deleted)
+		if err != nil {
+			return nil, err
+		}
+
+		err = cs.importRepository.CompleteContactImport(ctx, task.ImportID, &result.stats, reportKey, deletionsKey)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	_, err = cs.deleteFileFromS3(ctx, task.S3Key)
 	if err != nil {
-		return total, err
+		return &result.stats, err
 	}
 
-	return total, nil
+	return &result.stats, nil
+}
+
+// mergeStaged applies the staged rows of the task's import, or only counts the changes in a
+// dry run, and updates stats accordingly. It returns the contacts a sync deleted.
+// A sync of a file without a single valid contact is refused, as it would delete every contact.
+func (cs *ContactsService) mergeStaged(ctx context.Context, task *domain.Task, mode models.ImportMode, stats *models.ImportStats) ([]*models.Contact, error) {
+	if mode == models.ImportModeSync && stats.RowsRead == stats.RowsInvalid {
+		return nil, domain.ErrEmptySyncImport
+	}
+
+	changes, err := cs.repository.MergeStagedContacts(ctx, task.UserID, task.ImportID, mode, task.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.RowsInserted = changes.Inserted
+	stats.RowsUpdated = changes.Updated
+	stats.RowsDuplicate = stats.RowsRead - stats.RowsInvalid - stats.RowsInserted - stats.RowsUpdated
+	stats.RowsDeleted = len(changes.Deleted)
+
+	return changes.Deleted, nil
+}
+
+// uploadRejectionReport writes the rejected rows, ordered by sheet and line, as a CSV file
+// to S3 and returns its key. Reports of workbooks start with a sheet column. No report is written when nothing was rejected.
+func (cs *ContactsService) uploadRejectionReport(ctx context.Context, importID int, rejected []rejectedRow) (string, error) {
+	if len(rejected) == 0 {
+		return "", nil
+	}
+
+	sort.Slice(rejected, func(i, j int) bool {
+		if rejected[i].sheetIndex != rejected[j].sheetIndex {
+			return rejected[i].sheetIndex < rejected[j].sheetIndex
+		}
+		return rejected[i].Line < rejected[j].Line
+	})
+
+	// rows of workbooks are located by their sheet too
+	withSheet := rejected[0].Sheet != ""
+
+	header := []string{"line", "name", "phone", "reason"}
+	if withSheet {
+		header = append([]string{"sheet"}, header...)
+	}
+
+	records := make([][]string, len(rejected))
+	for i, r := range rejected {
+		records[i] = []string{strconv.Itoa(r.Line), r.Name, r.Phone, r.Reason}
+		if withSheet {
+			records[i] = append([]string{r.Sheet}, records[i]...)
+		}
+	}
+
+	key := fmt.Sprintf("imports/%d/rejected.csv", importID)
+	return key, cs.uploadReport(ctx, key, header, records)
+}
+
+// uploadDeletionsReport writes the contacts deleted by a sync, or that a dry run would
+// delete, as a CSV file to S3 and returns its key. No report is written when nothing is deleted.
+func (cs *ContactsService) uploadDeletionsReport(ctx context.Context, importID int, deleted []*models.Contact) (string, error) {
+	if len(deleted) == 0 {
+		return "", nil
+	}
+
+	records := make([][]string, len(deleted))
+	for i, c := range deleted {
+		records[i] = []string{c.Name, c.Phone}
+	}
+
+	key := fmt.Sprintf("imports/%d/deleted.csv", importID)
+	return key, cs.uploadReport(ctx, key, []string{"name", "phone"}, records)
+}
+
+func (cs *ContactsService) uploadReport(ctx context.Context, key string, header []string, records [][]string) error {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write(header)
+	_ = w.WriteAll(records)
+	if err := w.Error(); err != nil {
+		return err
+	}
+
+	_, err := cs.s3Client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(cs.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(buf.Bytes()),
+		ContentType: aws.String("text/csv"),
+	})
+	return err
 }
 
 func (cs *ContactsService) getFileFromS3(ctx context.Context, s3key string) (*s3.GetObjectOutput, error) {
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/contacts-worker/internal/models"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/stretchr/testify/assert"
@@ -15,55 +16,81 @@ import (
 )
 
 func TestContactsService_ProcessFile(t *testing.T) {
+	validCsv := []byte("Alice,+79123456789\nBob,+79123456788\n")
+	mixedCsv := []byte("Alice,+79123456789\nBob,12\n")
+	vcards := []byte("BEGIN:VCARD\nVERSION:3.0\nFN:Alice\nTEL;TYPE=CELL:+79123456789\nEMAIL:alice@example.com\nEND:VCARD\n")
+
+	getObject := func(s3c *MockS3Client, body []byte) {
+		s3c.
+			On("GetObjectWithContext", mock.Anything, mock.Anything, mock.Anything).
+			Return(&s3.GetObjectOutput{
+				Body: io.NopCloser(bytes.NewReader(body)),
+			}, nil).
+			Once()
+	}
+
+	workbook := makeWorkbook(t, []string{"Sales", "IT"}, map[string][][]string{
+		"Sales": {{"Name", "Phone"}, {"Alice", "+79123456789"}},
+		"IT":    {{"Phone", "Name"}, {"12", "Bob"}},
+	})
+
 	tests := []struct {
 		name          string
-		body          []byte
-		setupMocks    func(repo *MockContactsRepository, s3c *MockS3Client)
-		expectedTotal int
-		expectedErr   bool
+		task          *domain.Task
+		setupMocks    func(repo *MockContactsRepository, ir *MockContactImportRepository, s3c *MockS3Client)
+		expectedStats *models.ImportStats
+		expectedErr   error
 	}{
 		{
 			name: "S3 GetObject error",
-			body: nil,
-			setupMocks: func(repo *MockContactsRepository, s3c *MockS3Client) {
+			task: &domain.Task{ImportID: 7, UserID: 123, S3Key: "key.csv"},
+			setupMocks: func(repo *MockContactsRepository, ir *MockContactImportRepository, s3c *MockS3Client) {
+				ir.On("StartContactImport", mock.Anything, 7).Return(nil).Once()
 				s3c.
 					On("GetObjectWithContext", mock.Anything, mock.MatchedBy(func(input *s3.GetObjectInput) bool {
 						return aws.StringValue(input.Bucket) == "test-bucket" && aws.StringValue(input.Key) == "key.csv"
 					}), mock.Anything).
 					Return((*s3.GetObjectOutput)(nil), assert.AnError).
 					Once()
+				ir.On("FailContactImport", mock.Anything, 7, assert.AnError.Error()).Return(nil).Once()
 			},
-			expectedTotal: 0,
-			expectedErr:   true,
+			expectedStats: nil,
+			expectedErr:   assert.AnError,
 		},
 		{
 			name: "Unknown content type",
-			body: []byte{0x47, 0x49, 0x46, 0x38, 0x39, 0x61}, // GIF89a magic
-			setupMocks: func(repo *MockContactsRepository, s3c *MockS3Client) {
-				s3c.
-					On("GetObjectWithContext", mock.Anything, mock.Anything, mock.Anything).
-					Return(&s3.GetObjectOutput{
-						Body: io.NopCloser(bytes.NewReader([]byte{0x47, 0x49, 0x46, 0x38, 0x39, 0x61})),
-					}, nil).
-					Once()
+			task: &domain.Task{ImportID: 7, UserID: 123, S3Key: "key.csv"},
+			setupMocks: func(repo *MockContactsRepository, ir *MockContactImportRepository, s3c *MockS3Client) {
+				ir.On("StartContactImport", mock.Anything, 7).Return(nil).Once()
+				getObject(s3c, []byte{0x47, 0x49, 0x46, 0x38, 0x39, 0x61}) // GIF89a magic
+				ir.On("FailContactImport", mock.Anything, 7, domain.ErrUnsupportedFileType.Error()).Return(nil).Once()
 			},
-			expectedTotal: 0,
-			expectedErr:   false,
+			expectedStats: nil,
+			expectedErr:   domain.ErrUnsupportedFileType,
+		},
+		{
+			name: "Import job does not exist",
+			task: &domain.Task{ImportID: 7, UserID: 123, S3Key: "key.csv"},
+			setupMocks: func(repo *MockContactsRepository, ir *MockContactImportRepository, s3c *MockS3Client) {
+				ir.On("StartContactImport", mock.Anything, 7).Return(domain.ErrContactImportNotExists).Once()
+			},
+			expectedStats: nil,
+			expectedErr:   domain.ErrContactImportNotExists,
 		},
 		{
 			name: "CSV processing success",
-			body: []byte("name,email\nAlice,+79123456789\nBob,+79123456788\n"),
-			setupMocks: func(repo *MockContactsRepository, s3c *MockS3Client) {
+			task: &domain.Task{ImportID: 7, UserID: 123, S3Key: "key.csv"},
+			setupMocks: func(repo *MockContactsRepository, ir *MockContactImportRepository, s3c *MockS3Client) {
 				// here and further on we don't specify the expected number of calls because
 				// it might depend on the machine configuration and amount of logical cpus
 				repo.
-					On("SaveContacts", mock.Anything, mock.Anything).
-					Return(nil)
-				s3c.
-					On("GetObjectWithContext", mock.Anything, mock.Anything, mock.Anything).
-					Return(&s3.GetObjectOutput{
-						Body: io.NopCloser(bytes.NewReader([]byte("name,email\nAlice,+79123456789\nBob,+79123456788\n"))),
-					}, nil).
+					On("SaveContacts", mock.Anything, mock.Anything, models.ImportModeInsertOnly).
+					Return(insertAll, 0, nil)
+				ir.On("StartContactImport", mock.Anything, 7).Return(nil).Once()
+				getObject(s3c, validCsv)
+				ir.
+					On("CompleteContactImport", mock.Anything, 7, &models.ImportStats{RowsRead: 2, RowsInserted: 2}, "", "").
+					Return(nil).
 					Once()
 				s3c.
 					On("DeleteObjectWithContext", mock.Anything, mock.MatchedBy(func(input *s3.DeleteObjectInput) bool {
@@ -72,68 +99,297 @@ func TestContactsService_ProcessFile(t *testing.T) {
 					Return(&s3.DeleteObjectOutput{}, nil).
 					Once()
 			},
-			expectedTotal: 2,
-			expectedErr:   false,
+			expectedStats: &models.ImportStats{RowsRead: 2, RowsInserted: 2},
+			expectedErr:   nil,
 		},
 		{
-			name: "Repository save error",
-			body: []byte("name,email\nAlice,+79123456789\nBob,+79123456788\n"),
-			setupMocks: func(repo *MockContactsRepository, s3c *MockS3Client) {
+			name: "vCard processing success",
+			task: &domain.Task{ImportID: 7, UserID: 123, S3Key: "key.vcf"},
+			setupMocks: func(repo *MockContactsRepository, ir *MockContactImportRepository, s3c *MockS3Client) {
 				repo.
-					On("SaveContacts", mock.Anything, mock.Anything).
-					Return(assert.AnError).
+					On("SaveContacts", mock.Anything, []*models.Contact{{
+						UserID:     123,
+						Name:       "Alice",
+						Phone:      "+79123456789",
+						Attributes: map[string]string{"Email": "alice@example.com"},
+					}}, models.ImportModeInsertOnly).
+					Return(insertAll, 0, nil).
+					Once()
+				ir.On("StartContactImport", mock.Anything, 7).Return(nil).Once()
+				getObject(s3c, vcards)
+				ir.
+					On("CompleteContactImport", mock.Anything, 7, &models.ImportStats{RowsRead: 1, RowsInserted: 1}, "", "").
+					Return(nil).
 					Once()
 				s3c.
-					On("GetObjectWithContext", mock.Anything, mock.Anything, mock.Anything).
-					Return(&s3.GetObjectOutput{
-						Body: io.NopCloser(bytes.NewReader([]byte("name,email\nAlice,+79123456789\nBob,+79123456788\n"))),
-					}, nil).
+					On("DeleteObjectWithContext", mock.Anything, mock.Anything, mock.Anything).
+					Return(&s3.DeleteObjectOutput{}, nil).
 					Once()
 			},
-			expectedTotal: 0,
-			expectedErr:   true,
+			expectedStats: &models.ImportStats{RowsRead: 1, RowsInserted: 1},
+			expectedErr:   nil,
 		},
 		{
-			name: "Delete error",
-			body: []byte("name,email\nAlice,+79123456789\nBob,+79123456788\n"),
-			setupMocks: func(repo *MockContactsRepository, s3c *MockS3Client) {
+			name: "CSV with rejected rows uploads report",
+			task: &domain.Task{ImportID: 7, UserID: 123, S3Key: "key.csv"},
+			setupMocks: func(repo *MockContactsRepository, ir *MockContactImportRepository, s3c *MockS3Client) {
 				repo.
-					On("SaveContacts", mock.Anything, mock.Anything).
-					Return(nil)
+					On("SaveContacts", mock.Anything, mock.Anything, models.ImportModeInsertOnly).
+					Return(insertAll, 0, nil)
+				ir.On("StartContactImport", mock.Anything, 7).Return(nil).Once()
+				getObject(s3c, mixedCsv)
 				s3c.
-					On("GetObjectWithContext", mock.Anything, mock.Anything, mock.Anything).
-					Return(&s3.GetObjectOutput{
-						Body: io.NopCloser(bytes.NewReader([]byte("name,email\nAlice,+79123456789\nBob,+79123456788\n"))),
+					On("PutObjectWithContext", mock.Anything, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+						body, _ := io.ReadAll(input.Body)
+						return aws.StringValue(input.Key) == "imports/7/rejected.csv" &&
+							string(body) == "line,name,phone,reason\n2,Bob,12,"+rejectReasonInvalidPhone+"\n"
+					}), mock.Anything).
+					Return(&s3.PutObjectOutput{}, nil).
+					Once()
+				ir.
+					On("CompleteContactImport", mock.Anything, 7, &models.ImportStats{RowsRead: 2, RowsInserted: 1, RowsInvalid: 1}, "imports/7/rejected.csv", "").
+					Return(nil).
+					Once()
+				s3c.
+					On("DeleteObjectWithContext", mock.Anything, mock.Anything, mock.Anything).
+					Return(&s3.DeleteObjectOutput{}, nil).
+					Once()
+			},
+			expectedStats: &models.ImportStats{RowsRead: 2, RowsInserted: 1, RowsInvalid: 1},
+			expectedErr:   nil,
+		},
+		{
+			name: "Task without import job",
+			task: &domain.Task{UserID: 123, S3Key: "key.csv"},
+			setupMocks: func(repo *MockContactsRepository, ir *MockContactImportRepository, s3c *MockS3Client) {
+				repo.
+					On("SaveContacts", mock.Anything, mock.Anything, models.ImportModeInsertOnly).
+					Return(insertAll, 0, nil)
+				getObject(s3c, mixedCsv)
+				s3c.
+					On("DeleteObjectWithContext", mock.Anything, mock.Anything, mock.Anything).
+					Return(&s3.DeleteObjectOutput{}, nil).
+					Once()
+			},
+			expectedStats: &models.ImportStats{RowsRead: 2, RowsInserted: 1, RowsInvalid: 1},
+			expectedErr:   nil,
+		},
+		{
+			name: "XLSX sheets mapped to groups",
+			task: &domain.Task{ImportID: 7, UserID: 123, S3Key: "key.xlsx", Sheets: domain.SheetSelection{
+				All:    true,
+				Groups: map[string]string{"Sales": "Sales team"},
+			}},
+			setupMocks: func(repo *MockContactsRepository, ir *MockContactImportRepository, s3c *MockS3Client) {
+				repo.
+					On("SaveContacts", mock.Anything, []*models.Contact{{
+						UserID:     123,
+						Name:       "Alice",
+						Phone:      "+79123456789",
+						Attributes: map[string]string{"Group": "Sales team"},
+					}}, models.ImportModeInsertOnly).
+					Return(insertAll, 0, nil).
+					Once()
+				ir.On("StartContactImport", mock.Anything, 7).Return(nil).Once()
+				getObject(s3c, workbook)
+				s3c.
+					On("PutObjectWithContext", mock.Anything, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+						body, _ := io.ReadAll(input.Body)
+						return string(body) == "sheet,line,name,phone,reason\nIT,2,Bob,12,invalid phone number\n"
+					}), mock.Anything).
+					Return(&s3.PutObjectOutput{}, nil).
+					Once()
+				ir.
+					On("CompleteContactImport", mock.Anything, 7, &models.ImportStats{RowsRead: 2, RowsInserted: 1, RowsInvalid: 1}, "imports/7/rejected.csv", "").
+					Return(nil).
+					Once()
+				s3c.
+					On("DeleteObjectWithContext", mock.Anything, mock.Anything, mock.Anything).
+					Return(&s3.DeleteObjectOutput{}, nil).
+					Once()
+			},
+			expectedStats: &models.ImportStats{RowsRead: 2, RowsInserted: 1, RowsInvalid: 1},
+			expectedErr:   nil,
+		},
+		{
+			name: "XLSX sheet not found",
+			task: &domain.Task{ImportID: 7, UserID: 123, S3Key: "key.xlsx", Sheets: domain.SheetSelection{Names: []string{"HR"}}},
+			setupMocks: func(repo *MockContactsRepository, ir *MockContactImportRepository, s3c *MockS3Client) {
+				ir.On("StartContactImport", mock.Anything, 7).Return(nil).Once()
+				getObject(s3c, workbook)
+				ir.On("FailContactImport", mock.Anything, 7, `sheet not found: "HR"`).Return(nil).Once()
+			},
+			expectedStats: nil,
+			expectedErr:   domain.ErrSheetNotFound,
+		},
+		{
+			name: "Upsert updates existing contacts",
+			task: &domain.Task{ImportID: 7, UserID: 123, S3Key: "key.csv", Mode: models.ImportModeUpsert},
+			setupMocks: func(repo *MockContactsRepository, ir *MockContactImportRepository, s3c *MockS3Client) {
+				repo.
+					On("SaveContacts", mock.Anything, mock.Anything, models.ImportModeUpsert).
+					Return(0, 1, nil).
+					Once()
+				ir.On("StartContactImport", mock.Anything, 7).Return(nil).Once()
+				getObject(s3c, mixedCsv)
+				s3c.
+					On("PutObjectWithContext", mock.Anything, mock.Anything, mock.Anything).
+					Return(&s3.PutObjectOutput{}, nil).
+					Once()
+				ir.
+					On("CompleteContactImport", mock.Anything, 7, &models.ImportStats{RowsRead: 2, RowsUpdated: 1, RowsInvalid: 1}, "imports/7/rejected.csv", "").
+					Return(nil).
+					Once()
+				s3c.
+					On("DeleteObjectWithContext", mock.Anything, mock.Anything, mock.Anything).
+					Return(&s3.DeleteObjectOutput{}, nil).
+					Once()
+			},
+			expectedStats: &models.ImportStats{RowsRead: 2, RowsUpdated: 1, RowsInvalid: 1},
+			expectedErr:   nil,
+		},
+		{
+			name: "Sync deletes contacts missing from the file",
+			task: &domain.Task{ImportID: 7, UserID: 123, S3Key: "key.csv", Mode: models.ImportModeSync},
+			setupMocks: func(repo *MockContactsRepository, ir *MockContactImportRepository, s3c *MockS3Client) {
+				repo.On("StageContacts", mock.Anything, 7, mock.Anything).Return(nil)
+				repo.
+					On("MergeStagedContacts", mock.Anything, 123, 7, models.ImportModeSync, false).
+					Return(&models.ImportChanges{
+						Inserted: 1,
+						Deleted:  []*models.Contact{{Name: "Carol", Phone: "+79123456787"}},
 					}, nil).
 					Once()
+				repo.On("ClearStagedContacts", mock.Anything, 7).Return(nil).Once()
+				ir.On("StartContactImport", mock.Anything, 7).Return(nil).Once()
+				getObject(s3c, validCsv)
+				s3c.
+					On("PutObjectWithContext", mock.Anything, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+						body, _ := io.ReadAll(input.Body)
+						return aws.StringValue(input.Key) == "imports/7/deleted.csv" &&
+							string(body) == "name,phone\nCarol,+79123456787\n"
+					}), mock.Anything).
+					Return(&s3.PutObjectOutput{}, nil).
+					Once()
+				ir.
+					On("CompleteContactImport", mock.Anything, 7,
+						&models.ImportStats{RowsRead: 2, RowsInserted: 1, RowsDuplicate: 1, RowsDeleted: 1}, "", "imports/7/deleted.csv").
+					Return(nil).
+					Once()
+				s3c.
+					On("DeleteObjectWithContext", mock.Anything, mock.Anything, mock.Anything).
+					Return(&s3.DeleteObjectOutput{}, nil).
+					Once()
+			},
+			expectedStats: &models.ImportStats{RowsRead: 2, RowsInserted: 1, RowsDuplicate: 1, RowsDeleted: 1},
+			expectedErr:   nil,
+		},
+		{
+			name: "Dry run only counts the changes",
+			task: &domain.Task{ImportID: 7, UserID: 123, S3Key: "key.csv", Mode: models.ImportModeUpsert, DryRun: true},
+			setupMocks: func(repo *MockContactsRepository, ir *MockContactImportRepository, s3c *MockS3Client) {
+				repo.On("StageContacts", mock.Anything, 7, mock.Anything).Return(nil)
+				repo.
+					On("MergeStagedContacts", mock.Anything, 123, 7, models.ImportModeUpsert, true).
+					Return(&models.ImportChanges{Inserted: 1, Updated: 1}, nil).
+					Once()
+				repo.On("ClearStagedContacts", mock.Anything, 7).Return(nil).Once()
+				ir.On("StartContactImport", mock.Anything, 7).Return(nil).Once()
+				getObject(s3c, validCsv)
+				ir.
+					On("CompleteContactImport", mock.Anything, 7, &models.ImportStats{RowsRead: 2, RowsInserted: 1, RowsUpdated: 1}, "", "").
+					Return(nil).
+					Once()
+				s3c.
+					On("DeleteObjectWithContext", mock.Anything, mock.Anything, mock.Anything).
+					Return(&s3.DeleteObjectOutput{}, nil).
+					Once()
+			},
+			expectedStats: &models.ImportStats{RowsRead: 2, RowsInserted: 1, RowsUpdated: 1},
+			expectedErr:   nil,
+		},
+		{
+			name: "Sync without valid contacts",
+			task: &domain.Task{ImportID: 7, UserID: 123, S3Key: "key.csv", Mode: models.ImportModeSync},
+			setupMocks: func(repo *MockContactsRepository, ir *MockContactImportRepository, s3c *MockS3Client) {
+				repo.On("ClearStagedContacts", mock.Anything, 7).Return(nil).Once()
+				ir.On("StartContactImport", mock.Anything, 7).Return(nil).Once()
+				getObject(s3c, []byte("Alice,12\n"))
+				ir.On("FailContactImport", mock.Anything, 7, domain.ErrEmptySyncImport.Error()).Return(nil).Once()
+			},
+			expectedStats: nil,
+			expectedErr:   domain.ErrEmptySyncImport,
+		},
+		{
+			name: "Unsupported import mode",
+			task: &domain.Task{ImportID: 7, UserID: 123, S3Key: "key.csv", Mode: "replace"},
+			setupMocks: func(repo *MockContactsRepository, ir *MockContactImportRepository, s3c *MockS3Client) {
+				ir.On("StartContactImport", mock.Anything, 7).Return(nil).Once()
+				ir.On("FailContactImport", mock.Anything, 7, domain.ErrUnsupportedImportMode.Error()).Return(nil).Once()
+			},
+			expectedStats: nil,
+			expectedErr:   domain.ErrUnsupportedImportMode,
+		},
+		{
+			name: "Repository save error",
+			task: &domain.Task{ImportID: 7, UserID: 123, S3Key: "key.csv"},
+			setupMocks: func(repo *MockContactsRepository, ir *MockContactImportRepository, s3c *MockS3Client) {
+				repo.
+					On("SaveContacts", mock.Anything, mock.Anything, models.ImportModeInsertOnly).
+					Return(0, 0, assert.AnError).
+					Once()
+				ir.On("StartContactImport", mock.Anything, 7).Return(nil).Once()
+				getObject(s3c, validCsv)
+				ir.On("FailContactImport", mock.Anything, 7, assert.AnError.Error()).Return(nil).Once()
+			},
+			expectedStats: nil,
+			expectedErr:   assert.AnError,
+		},
+		{
+			name: "Delete error",
+			task: &domain.Task{ImportID: 7, UserID: 123, S3Key: "key.csv"},
+			setupMocks: func(repo *MockContactsRepository, ir *MockContactImportRepository, s3c *MockS3Client) {
+				repo.
+					On("SaveContacts", mock.Anything, mock.Anything, models.ImportModeInsertOnly).
+					Return(insertAll, 0, nil)
+				ir.On("StartContactImport", mock.Anything, 7).Return(nil).Once()
+				getObject(s3c, validCsv)
+				ir.
+					On("CompleteContactImport", mock.Anything, 7, mock.Anything, "", "").
+					Return(nil).
+					Once()
 				s3c.
 					On("DeleteObjectWithContext", mock.Anything, mock.Anything, mock.Anything).
 					Return((*s3.DeleteObjectOutput)(nil), assert.AnError).
 					Once()
+				// the import itself succeeded, so the job is not marked failed
 			},
-			expectedTotal: 2,
-			expectedErr:   true,
+			expectedStats: &models.ImportStats{RowsRead: 2, RowsInserted: 2},
+			expectedErr:   assert.AnError,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			repo := new(MockContactsRepository)
+			ir := new(MockContactImportRepository)
 			s3c := new(MockS3Client)
 			bucket := "test-bucket"
-			cs := NewContactsService(repo, s3c, bucket, 5*time.Second, 100)
+			cs := NewContactsService(repo, ir, s3c, bucket, 5*time.Second, 100)
 
-			tt.setupMocks(repo, s3c)
+			repo.On("GetContactAttributes", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+			tt.setupMocks(repo, ir, s3c)
 
-			total, err := cs.ProcessFile(context.Background(), &domain.Task{UserID: 123, S3Key: "key.csv"})
+			stats, err := cs.ProcessFile(context.Background(), tt.task)
 
-			if tt.expectedErr {
-				assert.Error(t, err)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
 			} else {
 				assert.NoError(t, err)
 			}
-			assert.Equal(t, tt.expectedTotal, total)
+			assert.Equal(t, tt.expectedStats, stats)
 			repo.AssertExpectations(t)
+			ir.AssertExpectations(t)
 			s3c.AssertExpectations(t)
 		})
 	}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+)
+
+// textFormat is the format of a plain text contacts file.
+type textFormat int
+
+const (
+	textFormatCSV textFormat = iota
+	textFormatVCard
+	textFormatJSON
+	textFormatNDJSON
+)
+
+// createTextRowProvider decodes a plain text file in any supported charset and picks
+// the row provider for its format: vCard, a JSON array, NDJSON or, by default, CSV.
+func (cs *ContactsService) createTextRowProvider(file io.Reader) (rowProvider, error) {
+	decoded, err := decodeText(file)
+	if err != nil {
+		return nil, err
+	}
+
+	br := bufio.NewReaderSize(decoded, sniffSize)
+	sample, err := br.Peek(sniffSize)
+	if err != nil && !errors.Is(err, bufio.ErrBufferFull) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	switch detectTextFormat(sample) {
+	case textFormatVCard:
+		return cs.createVCardRowProvider(br), nil
+	case textFormatJSON:
+		return cs.createJSONRowProvider(br), nil
+	case textFormatNDJSON:
+		return cs.createNDJSONRowProvider(br), nil
+	default:
+		return cs.createCsvRowProvider(br, sample), nil
+	}
+}
+
+// detectTextFormat tells the format by the first significant characters of the file.
+func detectTextFormat(sample []byte) textFormat {
+	s := bytes.TrimLeft(sample, " \t\r\n")
+	switch {
+	case len(s) >= len("BEGIN:VCARD") && bytes.EqualFold(s[:len("BEGIN:VCARD")], []byte("BEGIN:VCARD")):
+		return textFormatVCard
+	case len(s) > 0 && s[0] == '[':
+		return textFormatJSON
+	case len(s) > 0 && s[0] == '{':
+		return textFormatNDJSON
+	default:
+		return textFormatCSV
+	}
+}
@@ -0,0 +1,357 @@
+package service
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"mime/quotedprintable"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// vcardHeader names the columns of the records made from vCards. The other phones of a
+// card, its email, organization and title are stored as contact attributes.
+var vcardHeader = []string{"Name", "Phone", "Other phones", "Email", "Organization", "Title"}
+
+var errUnterminatedVCard = errors.New("vcard is not terminated")
+
+// createVCardRowProvider reads vCard 2.1, 3.0 and 4.0 files. Every card becomes a record
+// whose line is the line of its BEGIN:VCARD. When a card has several phone numbers, the
+// preferred mobile one is imported and the rest are kept in the "Other phones" attribute.
+func (cs *ContactsService) createVCardRowProvider(text io.Reader) rowProvider {
+	return func(emit func(rawRow) error) error {
+		if err := emit(rawRow{Record: vcardHeader, Header: true}); err != nil {
+			return err
+		}
+
+		lines := &vcardLineReader{r: bufio.NewReader(text)}
+		var card *vcard
+		var start int
+
+		for {
+			line, lineNo, err := lines.next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+
+			prop, ok := parseVCardLine(line)
+			if !ok {
+				continue
+			}
+
+			switch {
+			case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VCARD"):
+				if card != nil {
+					if err := emit(rawRow{Line: start, Record: card.record(), Err: errUnterminatedVCard}); err != nil {
+						return err
+					}
+				}
+				card, start = &vcard{}, lineNo
+			case prop.name == "END" && strings.EqualFold(prop.value, "VCARD"):
+				if card == nil {
+					continue
+				}
+				if err := emit(rawRow{Line: start, Record: card.record()}); err != nil {
+					return err
+				}
+				card = nil
+			case card != nil:
+				card.add(prop)
+			}
+		}
+
+		if card != nil {
+			return emit(rawRow{Line: start, Record: card.record(), Err: errUnterminatedVCard})
+		}
+		return nil
+	}
+}
+
+// vcardLineReader unfolds the physical lines of a vCard file into content lines.
+// Folded lines continue with a space or tab; quoted-printable values of vCard 2.1
+// continue after a trailing "=".
+type vcardLineReader struct {
+	r           *bufio.Reader
+	line        int
+	pending     string
+	pendingLine int
+	hasPending  bool
+}
+
+// next returns the next content line together with the number of its first physical line.
+func (lr *vcardLineReader) next() (string, int, error) {
+	if !lr.hasPending {
+		l, err := lr.readPhysical()
+		if err != nil {
+			return "", 0, err
+		}
+		lr.pending, lr.pendingLine = l, lr.line
+	}
+	cur, curLine := lr.pending, lr.pendingLine
+	lr.hasPending = false
+
+	for {
+		l, err := lr.readPhysical()
+		if errors.Is(err, io.EOF) {
+			return cur, curLine, nil
+		}
+		if err != nil {
+			return "", 0, err
+		}
+
+		switch {
+		case strings.HasPrefix(l, " ") || strings.HasPrefix(l, "\t"):
+			cur += l[1:]
+		case strings.HasSuffix(cur, "=") && isQuotedPrintable(cur):
+			cur = cur[:len(cur)-1] + l
+		default:
+			lr.pending, lr.pendingLine, lr.hasPending = l, lr.line, true
+			return cur, curLine, nil
+		}
+	}
+}
+
+func (lr *vcardLineReader) readPhysical() (string, error) {
+	l, err := lr.r.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || l == "") {
+		return "", err
+	}
+	lr.line++
+	return strings.TrimRight(l, "\r\n"), nil
+}
+
+func isQuotedPrintable(line string) bool {
+	params, _, _ := strings.Cut(line, ":")
+	return strings.Contains(strings.ToUpper(params), "QUOTED-PRINTABLE")
+}
+
+// vcardProperty is a content line split into the property name without its group,
+// the TYPE values and the decoded value.
+type vcardProperty struct {
+	name  string
+	types []string
+	pref  bool
+	value string
+}
+
+// parseVCardLine splits a content line such as "item1.TEL;TYPE=CELL,pref:+7 912 345-67-89".
+// Bare parameters of vCard 2.1 ("TEL;CELL:...") are taken as types.
+func parseVCardLine(line string) (vcardProperty, bool) {
+	head, value, ok := cutOutsideQuotes(line, ':')
+	if !ok {
+		return vcardProperty{}, false
+	}
+
+	params := splitOutsideQuotes(head, ';')
+	name := strings.ToUpper(strings.TrimSpace(params[0]))
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+
+	prop := vcardProperty{name: name}
+	var encoding, charset string
+	for _, p := range params[1:] {
+		k, v, found := strings.Cut(p, "=")
+		if !found {
+			k, v = "TYPE", p
+		}
+		v = strings.Trim(v, `"`)
+		switch strings.ToUpper(strings.TrimSpace(k)) {
+		case "TYPE":
+			for _, t := range strings.Split(v, ",") {
+				t = strings.ToLower(strings.TrimSpace(t))
+				switch t {
+				case "pref":
+					prop.pref = true
+				case "quoted-printable":
+					encoding = "QUOTED-PRINTABLE"
+				default:
+					prop.types = append(prop.types, t)
+				}
+			}
+		case "PREF":
+			prop.pref = true
+		case "ENCODING":
+			encoding = strings.ToUpper(v)
+		case "CHARSET":
+			charset = v
+		}
+	}
+
+	if encoding == "QUOTED-PRINTABLE" {
+		decoded, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(value)))
+		if err == nil {
+			value = string(decoded)
+		}
+		if enc, err := htmlindex.Get(charset); charset != "" && err == nil {
+			if s, err := enc.NewDecoder().String(value); err == nil {
+				value = s
+			}
+		}
+	}
+	prop.value = value
+
+	return prop, true
+}
+
+// vcard collects the properties of a card that are imported.
+type vcard struct {
+	fn     string
+	n      string
+	phones []vcardProperty
+	email  string
+	org    string
+	title  string
+}
+
+func (c *vcard) add(prop vcardProperty) {
+	switch prop.name {
+	case "FN":
+		c.fn = unescapeVCardText(prop.value)
+	case "N":
+		c.n = vcardStructuredName(prop.value)
+	case "TEL":
+		prop.value = vcardPhoneNumber(prop.value)
+		if prop.value != "" {
+			c.phones = append(c.phones, prop)
+		}
+	case "EMAIL":
+		if c.email == "" || prop.pref {
+			c.email = unescapeVCardText(prop.value)
+		}
+	case "ORG":
+		c.org = strings.Join(vcardComponents(prop.value), ", ")
+	case "TITLE":
+		c.title = unescapeVCardText(prop.value)
+	}
+}
+
+// record lays the card out in the columns of vcardHeader.
+func (c *vcard) record() []string {
+	name := c.fn
+	if name == "" {
+		name = c.n
+	}
+
+	var phone string
+	var others []string
+	if best := c.primaryPhone(); best >= 0 {
+		phone = c.phones[best].value
+		for i, p := range c.phones {
+			if i != best {
+				others = append(others, p.value)
+			}
+		}
+	}
+
+	return []string{name, phone, strings.Join(others, ", "), c.email, c.org, c.title}
+}
+
+// primaryPhone returns the index of the phone that can best receive SMS: a mobile number
+// ranks over a preferred one, and fax and pager numbers come last.
+func (c *vcard) primaryPhone() int {
+	best, bestScore := -1, 0
+	for i, p := range c.phones {
+		score := 0
+		if slices.Contains(p.types, "cell") || slices.Contains(p.types, "mobile") {
+			score += 2
+		}
+		if p.pref {
+			score++
+		}
+		if slices.Contains(p.types, "fax") || slices.Contains(p.types, "pager") {
+			score -= 4
+		}
+		if best < 0 || score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
+
+// vcardPhoneNumber strips the "tel:" scheme and URI parameters used by vCard 4.
+func vcardPhoneNumber(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 4 && strings.EqualFold(value[:4], "tel:") {
+		value, _, _ = strings.Cut(value[4:], ";")
+	}
+	return unescapeVCardText(value)
+}
+
+// vcardStructuredName joins the given, additional and family names of an N property.
+func vcardStructuredName(value string) string {
+	parts := splitOutsideEscapes(value, ';')
+	for len(parts) < 3 {
+		parts = append(parts, "")
+	}
+	var name []string
+	for _, p := range []string{parts[1], parts[2], parts[0]} {
+		if p = strings.TrimSpace(unescapeVCardText(p)); p != "" {
+			name = append(name, p)
+		}
+	}
+	return strings.Join(name, " ")
+}
+
+func vcardComponents(value string) []string {
+	var res []string
+	for _, p := range splitOutsideEscapes(value, ';') {
+		if p = strings.TrimSpace(unescapeVCardText(p)); p != "" {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+func unescapeVCardText(s string) string {
+	return strings.TrimSpace(strings.NewReplacer(`\\`, `\`, `\,`, ",", `\;`, ";", `\n`, " ", `\N`, " ").Replace(s))
+}
+
+// splitOutsideEscapes splits s on sep unless it is escaped with a backslash.
+func splitOutsideEscapes(s string, sep byte) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// splitOutsideQuotes splits s on sep unless it is inside double quotes.
+func splitOutsideQuotes(s string, sep byte) []string {
+	var parts []string
+	for {
+		before, after, ok := cutOutsideQuotes(s, sep)
+		parts = append(parts, before)
+		if !ok {
+			return parts
+		}
+		s = after
+	}
+}
+
+func cutOutsideQuotes(s string, sep byte) (string, string, bool) {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			quoted = !quoted
+		case sep:
+			if !quoted {
+				return s[:i], s[i+1:], true
+			}
+		}
+	}
+	return s, "", false
+}
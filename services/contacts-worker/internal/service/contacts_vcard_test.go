@@ -0,0 +1,114 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// collectRows runs the provider and returns every row it emits.
+func collectRows(t *testing.T, provider rowProvider) []rawRow {
+	t.Helper()
+	var rows []rawRow
+	err := provider(func(row rawRow) error {
+		rows = append(rows, row)
+		return nil
+	})
+	require.NoError(t, err)
+	return rows
+}
+
+func TestVCardRowProvider(t *testing.T) {
+	const file = "BEGIN:VCARD\r\n" +
+		"VERSION:3.0\r\n" +
+		"N:Ivanov;Ivan;;;\r\n" +
+		"FN:Ivan Ivanov\r\n" +
+		"ORG:ACME\\, Inc.;IT\r\n" +
+		"TEL;TYPE=WORK,VOICE:+7 495 123-45-67\r\n" +
+		"item1.TEL;TYPE=CELL:+7 912 345-67-89\r\n" +
+		"EMAIL;TYPE=INTERNET:ivan@example.com\r\n" +
+		"END:VCARD\r\n" +
+		"BEGIN:VCARD\r\n" +
+		"VERSION:4.0\r\n" +
+		"N:Petrova;Anna;Sergeevna;;\r\n" +
+		"TEL;VALUE=uri;TYPE=\"voice,home\":tel:+1-202-555-0123;ext=1\r\n" +
+		"TEL;VALUE=uri;PREF=1;TYPE=cell:tel:+7-912-345-67-88\r\n" +
+		"NOTE:a long note that is\r\n" +
+		"  folded\r\n" +
+		"END:VCARD\r\n" +
+		"BEGIN:VCARD\r\n" +
+		"VERSION:2.1\r\n" +
+		"N;CHARSET=UTF-8;ENCODING=QUOTED-PRINTABLE:=D0=9F=D0=B5=D1=82=D1=80=D0=BE=D0=B2;=D0=9F=D1=91=\r\n" +
+		"=D1=82=D1=80;;;\r\n" +
+		"TEL;FAX:+74951234568\r\n" +
+		"TEL;CELL:89123456787\r\n" +
+		"END:VCARD\r\n" +
+		"BEGIN:VCARD\r\n" +
+		"FN:Broken\r\n"
+
+	svc := &ContactsService{}
+	rows := collectRows(t, svc.createVCardRowProvider(strings.NewReader(file)))
+
+	require.Len(t, rows, 5)
+	assert.True(t, rows[0].Header)
+	assert.Equal(t, vcardHeader, rows[0].Record)
+
+	assert.Equal(t, 1, rows[1].Line)
+	assert.NoError(t, rows[1].Err)
+	assert.Equal(t, []string{"Ivan Ivanov", "+7 912 345-67-89", "+7 495 123-45-67", "ivan@example.com", "ACME, Inc., IT", ""}, rows[1].Record)
+
+	assert.Equal(t, 10, rows[2].Line)
+	assert.Equal(t, []string{"Anna Sergeevna Petrova", "+7-912-345-67-88", "+1-202-555-0123", "", "", ""}, rows[2].Record)
+
+	assert.Equal(t, 18, rows[3].Line)
+	assert.Equal(t, []string{"Пётр Петров", "89123456787", "+74951234568", "", "", ""}, rows[3].Record)
+
+	assert.Equal(t, 25, rows[4].Line)
+	assert.ErrorIs(t, rows[4].Err, errUnterminatedVCard)
+	assert.Equal(t, "Broken", rows[4].Record[0])
+}
+
+func TestParseVCardLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want vcardProperty
+		ok   bool
+	}{
+		{
+			name: "grouped property with types",
+			line: "item1.TEL;TYPE=CELL,pref:+79123456789",
+			want: vcardProperty{name: "TEL", types: []string{"cell"}, pref: true, value: "+79123456789"},
+			ok:   true,
+		},
+		{
+			name: "bare vcard 2.1 parameters",
+			line: "tel;Home;VOICE:123",
+			want: vcardProperty{name: "TEL", types: []string{"home", "voice"}, value: "123"},
+			ok:   true,
+		},
+		{
+			name: "colon in quoted parameter",
+			line: `TEL;LABEL="a:b";TYPE=work:123`,
+			want: vcardProperty{name: "TEL", types: []string{"work"}, value: "123"},
+			ok:   true,
+		},
+		{
+			name: "no value",
+			line: "garbage",
+			ok:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseVCardLine(tt.line)
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
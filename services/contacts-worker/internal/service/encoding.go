@@ -0,0 +1,114 @@
+package service
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// sniffSize is the number of leading bytes inspected to detect the charset and delimiter of a CSV file.
+const sniffSize = 4096
+
+// csvDelimiters are the field delimiters recognised in CSV files, in order of preference.
+var csvDelimiters = []rune{',', ';', '\t', '|'}
+
+// decodeText returns a reader that transcodes the file to UTF-8. A UTF-8 or UTF-16 byte
+// order mark wins; otherwise the file is read as UTF-8 if its leading bytes are valid
+// UTF-8, or as one of the Cyrillic code pages used by Russian Excel exports.
+func decodeText(file io.Reader) (io.Reader, error) {
+	br := bufio.NewReaderSize(file, sniffSize)
+	sample, err := br.Peek(sniffSize)
+	if err != nil && !errors.Is(err, bufio.ErrBufferFull) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	fallback := encoding.Nop
+	if !isUTF8(sample) {
+		fallback = detectCyrillicCharset(sample)
+	}
+
+	return transform.NewReader(br, unicode.BOMOverride(fallback.NewDecoder())), nil
+}
+
+// isUTF8 reports whether the sample is valid UTF-8, allowing it to end in the middle of a character.
+func isUTF8(sample []byte) bool {
+	for i := 0; i < utf8.UTFMax && len(sample) > 0; i++ {
+		if utf8.Valid(sample) {
+			return true
+		}
+		sample = sample[:len(sample)-1]
+	}
+	return len(sample) == 0
+}
+
+// detectCyrillicCharset tells CP1251 from KOI8-R. Both place Cyrillic letters in the upper
+// half of the byte range, but CP1251 puts lowercase letters at 0xE0-0xFF while KOI8-R puts
+// them at 0xC0-0xDF; as names are mostly lowercase, the busier range decides.
+func detectCyrillicCharset(sample []byte) encoding.Encoding {
+	var c0, e0 int
+	for _, b := range sample {
+		switch {
+		case b >= 0xE0:
+			e0++
+		case b >= 0xC0:
+			c0++
+		}
+	}
+	if c0 > e0 {
+		return charmap.KOI8R
+	}
+	return charmap.Windows1251
+}
+
+// sniffDelimiter picks the delimiter that splits every leading line of the sample into the
+// most fields. Delimiters inside quoted fields are ignored. Comma is the default.
+func sniffDelimiter(sample []byte) rune {
+	lines := strings.Split(string(sample), "\n")
+	if len(lines) > 1 {
+		// the last line may be cut off by the end of the sample
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) > 20 {
+		lines = lines[:20]
+	}
+
+	best, bestCount := csvDelimiters[0], 0
+	for _, d := range csvDelimiters {
+		minCount := -1
+		for _, line := range lines {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			c := countOutsideQuotes(line, d)
+			if minCount < 0 || c < minCount {
+				minCount = c
+			}
+		}
+		if minCount > bestCount {
+			best, bestCount = d, minCount
+		}
+	}
+
+	return best
+}
+
+func countOutsideQuotes(line string, d rune) int {
+	var count int
+	quoted := false
+	for _, r := range line {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == d && !quoted:
+			count++
+		}
+	}
+	return count
+}
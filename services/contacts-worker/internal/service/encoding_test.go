@@ -0,0 +1,92 @@
+package service
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func encode(t *testing.T, enc encoding.Encoding, s string) []byte {
+	t.Helper()
+	b, err := enc.NewEncoder().Bytes([]byte(s))
+	require.NoError(t, err)
+	return b
+}
+
+func TestDecodeText(t *testing.T) {
+	const text = "ФИО;Телефон\nИванов Иван Иванович;+79123456789\nпетрова анна;+79123456788\n"
+
+	tests := []struct {
+		name string
+		raw  []byte
+	}{
+		{"utf-8", []byte(text)},
+		{"utf-8 with bom", append([]byte("\xEF\xBB\xBF"), text...)},
+		{"utf-16le with bom", encode(t, unicode.UTF16(unicode.LittleEndian, unicode.UseBOM), text)},
+		{"utf-16be with bom", encode(t, unicode.UTF16(unicode.BigEndian, unicode.UseBOM), text)},
+		{"cp1251", encode(t, charmap.Windows1251, text)},
+		{"koi8-r", encode(t, charmap.KOI8R, text)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := decodeText(bytes.NewReader(tt.raw))
+			require.NoError(t, err)
+			got, err := io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, text, string(got))
+		})
+	}
+}
+
+func TestIsUTF8(t *testing.T) {
+	cyrillic := []byte("Иван")
+	assert.True(t, isUTF8(cyrillic))
+	assert.True(t, isUTF8(cyrillic[:len(cyrillic)-1]), "sample cut in the middle of a character")
+	assert.False(t, isUTF8(encode(t, charmap.Windows1251, "Иванов Иван")))
+}
+
+func TestSniffDelimiter(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample string
+		want   rune
+	}{
+		{"comma", "name,phone\nAlice,+79123456789\n", ','},
+		{"semicolon", "name;phone\nAlice;+79123456789\n", ';'},
+		{"tab", "name\tphone\nAlice\t+79123456789\n", '\t'},
+		{"semicolon with commas in quotes", "name;phone\n\"Ivanov, Ivan\";+79123456789\n", ';'},
+		{"semicolon with decimal commas", "name;phone;rate\nAlice;+79123456789;1,5\nBob;+79123456788;2\n", ';'},
+		{"single column defaults to comma", "phone\n+79123456789\n", ','},
+		{"cut off last line is ignored", "name;phone\nAlice;+79123456789\nBob,", ';'},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sniffDelimiter([]byte(tt.sample)))
+		})
+	}
+}
+
+func TestTextRowProvider_Cp1251Semicolon(t *testing.T) {
+	raw := encode(t, charmap.Windows1251, "ФИО;Телефон\r\n\"Иванов; Иван\";+79123456789\r\n")
+
+	svc := &ContactsService{}
+	provider, err := svc.createTextRowProvider(bytes.NewReader(raw))
+	require.NoError(t, err)
+
+	var rows [][]string
+	err = provider(func(row rawRow) error {
+		require.NoError(t, row.Err)
+		rows = append(rows, row.Record)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"ФИО", "Телефон"}, {"Иванов; Иван", "+79123456789"}}, rows)
+}
@@ -56,8 +56,58 @@ type MockContactsRepository struct {
 	mock.Mock
 }
 
-func (m *MockContactsRepository) SaveContacts(ctx context.Context, contacts []*models.Contact) error {
-	return m.Called(ctx, contacts).Error(0)
+func (m *MockContactsRepository) SaveContacts(ctx context.Context, contacts []*models.Contact, mode models.ImportMode) (int, int, error) {
+	args := m.Called(ctx, contacts, mode)
+	if inserted, ok := args.Get(0).(func([]*models.Contact) int); ok {
+		return inserted(contacts), args.Int(1), args.Error(2)
+	}
+	return args.Int(0), args.Int(1), args.Error(2)
+}
+
+func (m *MockContactsRepository) StageContacts(ctx context.Context, importID int, contacts []*models.Contact) error {
+	return m.Called(ctx, importID, contacts).Error(0)
+}
+
+func (m *MockContactsRepository) MergeStagedContacts(ctx context.Context, userID, importID int, mode models.ImportMode, dryRun bool) (*models.ImportChanges, error) {
+	args := m.Called(ctx, userID, importID, mode, dryRun)
+	changes, _ := args.Get(0).(*models.ImportChanges)
+	return changes, args.Error(1)
+}
+
+func (m *MockContactsRepository) ClearStagedContacts(ctx context.Context, importID int) error {
+	return m.Called(ctx, importID).Error(0)
+}
+
+func (m *MockContactsRepository) GetContactAttributes(ctx context.Context, userID int) ([]*models.ContactAttribute, error) {
+	args := m.Called(ctx, userID)
+	attrs, _ := args.Get(0).([]*models.ContactAttribute)
+	return attrs, args.Error(1)
+}
+
+// insertAll reports every contact of a batch as inserted.
+func insertAll(contacts []*models.Contact) int {
+	return len(contacts)
+}
+
+// insertNone reports every contact of a batch as a duplicate.
+func insertNone([]*models.Contact) int {
+	return 0
+}
+
+type MockContactImportRepository struct {
+	mock.Mock
+}
+
+func (m *MockContactImportRepository) StartContactImport(ctx context.Context, importID int) error {
+	return m.Called(ctx, importID).Error(0)
+}
+
+func (m *MockContactImportRepository) CompleteContactImport(ctx context.Context, importID int, stats *models.ImportStats, reportKey, deletionsKey string) error {
+	return m.Called(ctx, importID, stats, reportKey, deletionsKey).Error(0)
+}
+
+func (m *MockContactImportRepository) FailContactImport(ctx context.Context, importID int, reason string) error {
+	return m.Called(ctx, importID, reason).Error(0)
 }
 
 type MockS3Client struct {
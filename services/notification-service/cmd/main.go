@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	_ "time/tzdata"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/notification-service/internal/adapter/consumers"
 	"github.com/SteeperMold/Emergency-Notification-System/services/notification-service/internal/api/route"
@@ -21,12 +22,15 @@ func main() {
 
 	kafkaCfg := app.Config.Kafka
 	notificationRequestsReader := app.KafkaFactory.NewReader(kafkaCfg.Topics["notification.requests"], kafkaCfg.ConsumerGroup)
+	cancellationsReader := app.KafkaFactory.NewReader(kafkaCfg.Topics["notification.cancellations"], kafkaCfg.CancellationsConsumerGroup)
 	sendTasksWriter := app.KafkaFactory.NewWriter(kafkaCfg.Topics["notification.tasks"], bootstrap.WithBatchTimeout(kafkaCfg.NotificationTasksWriterBatchTimeout))
 
 	nr := repository.NewNotificationRepository(app.DB)
 	appCfg := app.Config.App
 	nrs := service.NewNotificationRequestsService(nr, sendTasksWriter, appCfg.NotificationTasksWriterBatchSize)
-	nrc := consumers.NewNotificationRequestsConsumer(nrs, notificationRequestsReader, app.Logger, appCfg.ContextTimeout, appCfg.NotificationConsumerBatchSize, appCfg.NotificationConsumerFlushInterval)
+	nrc := consumers.NewNotificationRequestsConsumer(nrs, notificationRequestsReader, app.Logger, appCfg.ContextTimeout, appCfg.NotificationConsumerBatchSize, appCfg.NotificationConsumerFlushInterval, appCfg.DefaultRetryPolicy)
+	ccs := service.NewCampaignCancellationsService(nr)
+	ccc := consumers.NewCampaignCancellationsConsumer(ccs, cancellationsReader, app.Logger, appCfg.ContextTimeout, appCfg.CancellationRetryInterval)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	sigs := make(chan os.Signal, 1)
@@ -41,6 +45,10 @@ func main() {
 		log.Fatal(nrc.StartConsumer(ctx))
 	}()
 
+	go func() {
+		log.Fatal(ccc.StartConsumer(ctx))
+	}()
+
 	log.Printf("listening on port %v", app.Config.App.Port)
 
 	route.Serve(app)
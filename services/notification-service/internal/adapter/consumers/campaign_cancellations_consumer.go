@@ -0,0 +1,95 @@
+package consumers
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/notification-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// CampaignCancellationsConsumer reads campaign cancellations from Kafka and applies them
+// via the CampaignCancellationsService one by one.
+type CampaignCancellationsConsumer struct {
+	service        domain.CampaignCancellationsService
+	kafkaReader    domain.KafkaReader
+	logger         *zap.Logger
+	contextTimeout time.Duration
+	retryInterval  time.Duration
+}
+
+// NewCampaignCancellationsConsumer constructs the consumer with required dependencies and settings.
+// A cancellation that fails to apply is retried every retryInterval, since dropping it would let
+// the campaign go on.
+func NewCampaignCancellationsConsumer(s domain.CampaignCancellationsService, kr domain.KafkaReader, logger *zap.Logger, timeout time.Duration, retryInterval time.Duration) *CampaignCancellationsConsumer {
+	return &CampaignCancellationsConsumer{
+		service:        s,
+		kafkaReader:    kr,
+		logger:         logger,
+		contextTimeout: timeout,
+		retryInterval:  retryInterval,
+	}
+}
+
+// StartConsumer begins polling Kafka for CampaignCancellation messages.
+// It runs until the provided context is cancelled or fetching a message fails.
+func (ccc *CampaignCancellationsConsumer) StartConsumer(ctx context.Context) error {
+	for {
+		raw, err := ccc.kafkaReader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		var c domain.CampaignCancellation
+		err = json.Unmarshal(raw.Value, &c)
+		if err != nil {
+			ccc.logger.Error("invalid campaign cancellation",
+				zap.String("raw_cancellation", string(raw.Value)),
+				zap.Error(err),
+			)
+		} else {
+			err = ccc.cancel(ctx, &c)
+			if err != nil {
+				return nil
+			}
+		}
+
+		err = ccc.kafkaReader.CommitMessages(ctx, raw)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// cancel applies the cancellation, retrying until it succeeds or ctx is cancelled.
+func (ccc *CampaignCancellationsConsumer) cancel(ctx context.Context, c *domain.CampaignCancellation) error {
+	for {
+		cancelCtx, cancel := context.WithTimeout(ctx, ccc.contextTimeout)
+		n, err := ccc.service.CancelCampaign(cancelCtx, c)
+		cancel()
+		if err == nil {
+			ccc.logger.Info("cancelled campaign",
+				zap.Int("user_id", c.UserID),
+				zap.String("campaign_id", c.CampaignID.String()),
+				zap.Int64("cancelled_notifications", n),
+			)
+			return nil
+		}
+
+		ccc.logger.Error("failed to cancel campaign, retrying",
+			zap.Int("user_id", c.UserID),
+			zap.String("campaign_id", c.CampaignID.String()),
+			zap.Error(err),
+		)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(ccc.retryInterval):
+		}
+	}
+}
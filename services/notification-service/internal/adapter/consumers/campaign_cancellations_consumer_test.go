@@ -0,0 +1,83 @@
+package consumers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/notification-service/internal/adapter/consumers"
+	"github.com/SteeperMold/Emergency-Notification-System/services/notification-service/internal/domain"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+type MockCampaignCancellationsService struct {
+	mock.Mock
+}
+
+func (m *MockCampaignCancellationsService) CancelCampaign(ctx context.Context, c *domain.CampaignCancellation) (int64, error) {
+	args := m.Called(ctx, c)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestCampaignCancellationsConsumer_StartConsumer(t *testing.T) {
+	cancellation := &domain.CampaignCancellation{UserID: 1, CampaignID: uuid.New()}
+	raw, _ := json.Marshal(cancellation)
+	errStop := errors.New("reader closed")
+
+	t.Run("failed cancellation is retried before commit", func(t *testing.T) {
+		mockKR := new(MockKafkaReader)
+		mockSvc := new(MockCampaignCancellationsService)
+
+		mockKR.On("FetchMessage", mock.Anything).Return(kafka.Message{Value: raw}, nil).Once()
+		mockSvc.On("CancelCampaign", mock.Anything, cancellation).Return(int64(0), assert.AnError).Once()
+		mockSvc.On("CancelCampaign", mock.Anything, cancellation).Return(int64(2), nil).Once()
+		mockKR.On("CommitMessages", mock.Anything, []kafka.Message{{Value: raw}}).Return(nil).Once()
+		mockKR.On("FetchMessage", mock.Anything).Return(kafka.Message{}, errStop).Once()
+
+		c := consumers.NewCampaignCancellationsConsumer(mockSvc, mockKR, zaptest.NewLogger(t), time.Second, time.Millisecond)
+		err := c.StartConsumer(context.Background())
+
+		assert.ErrorIs(t, err, errStop)
+		mockKR.AssertExpectations(t)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("invalid cancellation is skipped", func(t *testing.T) {
+		mockKR := new(MockKafkaReader)
+		mockSvc := new(MockCampaignCancellationsService)
+
+		mockKR.On("FetchMessage", mock.Anything).Return(kafka.Message{Value: []byte("{")}, nil).Once()
+		mockKR.On("CommitMessages", mock.Anything, mock.Anything).Return(nil).Once()
+		mockKR.On("FetchMessage", mock.Anything).Return(kafka.Message{}, errStop).Once()
+
+		c := consumers.NewCampaignCancellationsConsumer(mockSvc, mockKR, zaptest.NewLogger(t), time.Second, time.Millisecond)
+		err := c.StartConsumer(context.Background())
+
+		assert.ErrorIs(t, err, errStop)
+		mockKR.AssertExpectations(t)
+		mockSvc.AssertNotCalled(t, "CancelCampaign", mock.Anything, mock.Anything)
+	})
+
+	t.Run("shutdown while retrying leaves the message uncommitted", func(t *testing.T) {
+		mockKR := new(MockKafkaReader)
+		mockSvc := new(MockCampaignCancellationsService)
+		ctx, cancel := context.WithCancel(context.Background())
+
+		mockKR.On("FetchMessage", mock.Anything).Return(kafka.Message{Value: raw}, nil).Once()
+		mockSvc.On("CancelCampaign", mock.Anything, cancellation).
+			Run(func(mock.Arguments) { cancel() }).
+			Return(int64(0), assert.AnError).Once()
+
+		c := consumers.NewCampaignCancellationsConsumer(mockSvc, mockKR, zaptest.NewLogger(t), time.Second, time.Hour)
+		err := c.StartConsumer(ctx)
+
+		assert.NoError(t, err)
+		mockKR.AssertNotCalled(t, "CommitMessages", mock.Anything, mock.Anything)
+	})
+}
@@ -3,6 +3,7 @@ package consumers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/notification-service/internal/domain"
@@ -22,10 +23,15 @@ type NotificationRequestsConsumer struct {
 	contextTimeout time.Duration
 	batchSize      int
 	flushInterval  time.Duration
+	defaultPolicy  models.RetryPolicy
+	locations      map[string]*time.Location
 }
 
+var errEmptyTimeZone = errors.New("empty time zone")
+
 // NewNotificationRequestsConsumer constructs the consumer with required dependencies and settings.
-func NewNotificationRequestsConsumer(s domain.NotificationRequestsService, kr domain.KafkaReader, logger *zap.Logger, timeout time.Duration, batchSize int, flushInterval time.Duration) *NotificationRequestsConsumer {
+// defaultPolicy is applied to requests that do not carry their own retry policy.
+func NewNotificationRequestsConsumer(s domain.NotificationRequestsService, kr domain.KafkaReader, logger *zap.Logger, timeout time.Duration, batchSize int, flushInterval time.Duration, defaultPolicy models.RetryPolicy) *NotificationRequestsConsumer {
 	return &NotificationRequestsConsumer{
 		service:        s,
 		kafkaReader:    kr,
@@ -33,6 +39,8 @@ func NewNotificationRequestsConsumer(s domain.NotificationRequestsService, kr do
 		contextTimeout: timeout,
 		batchSize:      batchSize,
 		flushInterval:  flushInterval,
+		defaultPolicy:  defaultPolicy,
+		locations:      make(map[string]*time.Location),
 	}
 }
 
@@ -84,13 +92,37 @@ func (nrc *NotificationRequestsConsumer) StartConsumer(ctx context.Context) erro
 				continue
 			}
 
+			policy := nrc.defaultPolicy
+			if nr.RetryPolicy != nil {
+				policy = *nr.RetryPolicy
+			}
+
+			now := time.Now()
 			for _, c := range nr.Contacts {
-				buffered = append(buffered, &models.Notification{
+				text := nr.Template
+				if c.Text != "" {
+					text = c.Text
+				}
+				ntf := &models.Notification{
 					ID:             uuid.New(),
 					UserID:         nr.UserID,
-					Text:           nr.Template,
+					CampaignID:     nr.CampaignID,
+					Text:           text,
+					Channel:        models.ChannelSMS,
 					RecipientPhone: c.Phone,
-				})
+					Fallbacks:      c.Fallbacks,
+					RetryPolicy:    policy,
+					SenderID:       nr.SenderID,
+					Priority:       nr.Priority,
+				}
+				if qh, loc := nrc.quietHours(&nr, c); qh != nil {
+					ntf.QuietHours = qh
+					if until, ok := qh.DeferUntil(now, loc); ok {
+						ntf.Status = models.StatusPending
+						ntf.NextRunAt = until
+					}
+				}
+				buffered = append(buffered, ntf)
 			}
 
 			if len(buffered) >= nrc.batchSize {
@@ -111,6 +143,50 @@ func (nrc *NotificationRequestsConsumer) StartConsumer(ctx context.Context) erro
 	}
 }
 
+// quietHours returns the request's quiet hours in the time zone of contact c, and that time zone,
+// or nil if notifications for c are never deferred: for critical requests and requests without
+// quiet hours. The contact's own time zone is preferred; the quiet hours time zone is used when
+// it is unknown or invalid.
+func (nrc *NotificationRequestsConsumer) quietHours(nr *domain.NotificationRequest, c *models.SlimContact) (*models.QuietHours, *time.Location) {
+	if nr.QuietHours == nil || nr.Priority == "" || nr.Priority == models.PriorityCritical {
+		return nil, nil
+	}
+
+	qh := *nr.QuietHours
+	loc, err := nrc.location(c.TimeZone)
+	if err == nil {
+		qh.TimeZone = c.TimeZone
+	} else {
+		loc, err = nrc.location(nr.QuietHours.TimeZone)
+		if err != nil {
+			nrc.logger.Warn("invalid quiet hours time zone, delivering immediately",
+				zap.String("time_zone", nr.QuietHours.TimeZone),
+				zap.Error(err),
+			)
+			return nil, nil
+		}
+	}
+
+	return &qh, loc
+}
+
+func (nrc *NotificationRequestsConsumer) location(name string) (*time.Location, error) {
+	if name == "" {
+		return nil, errEmptyTimeZone
+	}
+	if loc, ok := nrc.locations[name]; ok {
+		return loc, nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	nrc.locations[name] = loc
+
+	return loc, nil
+}
+
 func (nrc *NotificationRequestsConsumer) startFetchLoop(ctx context.Context) chan message {
 	ch := make(chan message)
 	go func() {
@@ -38,6 +38,8 @@ func (m *MockNotificationRequestsService) SaveNotifications(ctx context.Context,
 }
 
 func TestNotificationRequestsConsumer_StartConsumer(t *testing.T) {
+	defaultPolicy := models.RetryPolicy{MaxAttempts: 5, Backoff: models.BackoffExponential, BaseDelayMs: 1000}
+
 	t.Run("valid notification triggers flush on batch size", func(t *testing.T) {
 		userID := 1
 		mockKR := new(MockKafkaReader)
@@ -75,7 +77,194 @@ func TestNotificationRequestsConsumer_StartConsumer(t *testing.T) {
 			Return(kafka.Message{}, context.Canceled).
 			Once()
 
-		c := consumers.NewNotificationRequestsConsumer(mockSvc, mockKR, logger, time.Second, 4, 500*time.Millisecond)
+		c := consumers.NewNotificationRequestsConsumer(mockSvc, mockKR, logger, time.Second, 4, 500*time.Millisecond, defaultPolicy)
+
+		go func() {
+			_ = c.StartConsumer(ctx)
+		}()
+
+		<-ctx.Done()
+		mockKR.AssertExpectations(t)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("request policy overrides default", func(t *testing.T) {
+		mockKR := new(MockKafkaReader)
+		mockSvc := new(MockNotificationRequestsService)
+		logger := zaptest.NewLogger(t)
+
+		override := &models.RetryPolicy{MaxAttempts: 2, Backoff: models.BackoffConstant, BaseDelayMs: 500}
+		legacy, _ := json.Marshal(domain.NotificationRequest{
+			UserID:   1,
+			Template: "Hello",
+			Contacts: []*models.SlimContact{{Phone: "123", Name: "Alice"}},
+		})
+		withPolicy, _ := json.Marshal(domain.NotificationRequest{
+			UserID:      1,
+			Template:    "Hello",
+			Contacts:    []*models.SlimContact{{Phone: "456", Name: "Ben"}},
+			RetryPolicy: override,
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		mockKR.
+			On("FetchMessage", mock.Anything).
+			Return(kafka.Message{Value: legacy}, nil).
+			Once()
+		mockKR.
+			On("FetchMessage", mock.Anything).
+			Return(kafka.Message{Value: withPolicy}, nil).
+			Once()
+		mockKR.
+			On("CommitMessages", mock.Anything, mock.Anything).
+			Return(nil).
+			Twice()
+		mockSvc.
+			On("SaveNotifications", mock.Anything, mock.MatchedBy(func(ntfs *[]*models.Notification) bool {
+				return len(*ntfs) == 2 &&
+					(*ntfs)[0].RetryPolicy == defaultPolicy &&
+					(*ntfs)[1].RetryPolicy == *override
+			})).
+			Return(nil).
+			Once()
+		mockKR.
+			On("FetchMessage", mock.Anything).
+			Return(kafka.Message{}, context.Canceled).
+			Once()
+
+		c := consumers.NewNotificationRequestsConsumer(mockSvc, mockKR, logger, time.Second, 2, 500*time.Millisecond, defaultPolicy)
+
+		go func() {
+			_ = c.StartConsumer(ctx)
+		}()
+
+		<-ctx.Done()
+		mockKR.AssertExpectations(t)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("rendered text overrides template", func(t *testing.T) {
+		mockKR := new(MockKafkaReader)
+		mockSvc := new(MockNotificationRequestsService)
+		logger := zaptest.NewLogger(t)
+
+		raw, _ := json.Marshal(domain.NotificationRequest{
+			UserID:   1,
+			Template: "Hello, {{name}}",
+			Contacts: []*models.SlimContact{
+				{Phone: "123", Name: "Alice", Text: "Hello, Alice"},
+				{Phone: "456", Name: "Ben"},
+			},
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		mockKR.
+			On("FetchMessage", mock.Anything).
+			Return(kafka.Message{Value: raw}, nil).
+			Once()
+		mockKR.
+			On("CommitMessages", mock.Anything, mock.Anything).
+			Return(nil).
+			Once()
+		mockSvc.
+			On("SaveNotifications", mock.Anything, mock.MatchedBy(func(ntfs *[]*models.Notification) bool {
+				return len(*ntfs) == 2 &&
+					(*ntfs)[0].Text == "Hello, Alice" &&
+					(*ntfs)[1].Text == "Hello, {{name}}"
+			})).
+			Return(nil).
+			Once()
+		mockKR.
+			On("FetchMessage", mock.Anything).
+			Return(kafka.Message{}, context.Canceled).
+			Once()
+
+		c := consumers.NewNotificationRequestsConsumer(mockSvc, mockKR, logger, time.Second, 2, 500*time.Millisecond, defaultPolicy)
+
+		go func() {
+			_ = c.StartConsumer(ctx)
+		}()
+
+		<-ctx.Done()
+		mockKR.AssertExpectations(t)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("normal priority is deferred during quiet hours", func(t *testing.T) {
+		mockKR := new(MockKafkaReader)
+		mockSvc := new(MockNotificationRequestsService)
+		logger := zaptest.NewLogger(t)
+
+		// A window covering the whole day except the last minute before its start,
+		// so that "now" is always inside it regardless of when the test runs.
+		now := time.Now().UTC()
+		minute := now.Hour()*60 + now.Minute()
+		quiet := &models.QuietHours{
+			StartMinute: minute,
+			EndMinute:   (minute + 24*60 - 1) % (24 * 60),
+			TimeZone:    "UTC",
+		}
+
+		normal, _ := json.Marshal(domain.NotificationRequest{
+			UserID:   1,
+			Template: "Hello",
+			Contacts: []*models.SlimContact{
+				{Phone: "123", Name: "Alice"},
+				{Phone: "456", Name: "Ben", TimeZone: "Invalid/Zone"},
+			},
+			Priority:   models.PriorityNormal,
+			QuietHours: quiet,
+		})
+		critical, _ := json.Marshal(domain.NotificationRequest{
+			UserID:     1,
+			Template:   "Alert",
+			Contacts:   []*models.SlimContact{{Phone: "789", Name: "Carl"}},
+			Priority:   models.PriorityCritical,
+			QuietHours: quiet,
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		mockKR.
+			On("FetchMessage", mock.Anything).
+			Return(kafka.Message{Value: normal}, nil).
+			Once()
+		mockKR.
+			On("FetchMessage", mock.Anything).
+			Return(kafka.Message{Value: critical}, nil).
+			Once()
+		mockKR.
+			On("CommitMessages", mock.Anything, mock.Anything).
+			Return(nil).
+			Twice()
+		mockSvc.
+			On("SaveNotifications", mock.Anything, mock.MatchedBy(func(ntfs *[]*models.Notification) bool {
+				if len(*ntfs) != 3 {
+					return false
+				}
+				for _, n := range (*ntfs)[:2] {
+					if n.Status != models.StatusPending || !n.NextRunAt.After(now) {
+						return false
+					}
+					if n.Priority != models.PriorityNormal || n.QuietHours == nil || n.QuietHours.TimeZone != "UTC" {
+						return false
+					}
+				}
+				return (*ntfs)[2].Status == "" && (*ntfs)[2].NextRunAt.IsZero() && (*ntfs)[2].QuietHours == nil
+			})).
+			Return(nil).
+			Once()
+		mockKR.
+			On("FetchMessage", mock.Anything).
+			Return(kafka.Message{}, context.Canceled).
+			Once()
+
+		c := consumers.NewNotificationRequestsConsumer(mockSvc, mockKR, logger, time.Second, 3, 500*time.Millisecond, defaultPolicy)
 
 		go func() {
 			_ = c.StartConsumer(ctx)
@@ -109,7 +298,7 @@ func TestNotificationRequestsConsumer_StartConsumer(t *testing.T) {
 			Return(kafka.Message{}, context.Canceled).
 			Once()
 
-		c := consumers.NewNotificationRequestsConsumer(mockSvc, mockKR, logger, time.Second, 2, 500*time.Millisecond)
+		c := consumers.NewNotificationRequestsConsumer(mockSvc, mockKR, logger, time.Second, 2, 500*time.Millisecond, defaultPolicy)
 
 		go func() {
 			_ = c.StartConsumer(ctx)
@@ -129,7 +318,7 @@ func TestNotificationRequestsConsumer_StartConsumer(t *testing.T) {
 
 		mockKR.On("FetchMessage", ctx).Return(kafka.Message{}, errors.New("fetch failed")).Once()
 
-		c := consumers.NewNotificationRequestsConsumer(mockSvc, mockKR, logger, time.Second, 2, time.Second)
+		c := consumers.NewNotificationRequestsConsumer(mockSvc, mockKR, logger, time.Second, 2, time.Second, defaultPolicy)
 
 		err := c.StartConsumer(ctx)
 		assert.EqualError(t, err, "fetch failed")
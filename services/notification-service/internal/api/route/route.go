@@ -26,7 +26,7 @@ func Serve(app *bootstrap.Application) {
 		r.Use(middleware.RequireValidTwilioSignatureMiddleware(app.Config.Twilio.StatusCallbackEndpoint, &validator))
 	}
 
-	NewTwilioCallbackRoute(r, app.DB, app.Logger, app.Config.App.MaxAttempts, app.Config.App.ContextTimeout)
+	NewTwilioCallbackRoute(r, app.DB, app.Logger, app.Config.App.ContextTimeout)
 
 	log.Fatal(http.ListenAndServe(":"+app.Config.App.Port, r))
 }
@@ -14,9 +14,9 @@ import (
 
 // NewTwilioCallbackRoute registers the HTTP endpoint for handling Twilio status callbacks.
 // It composes the repository, service, and handler layers and attaches the POST /callback route.
-func NewTwilioCallbackRoute(mux *mux.Router, db domain.DBConn, logger *zap.Logger, maxAttempts int, timeout time.Duration) {
+func NewTwilioCallbackRoute(mux *mux.Router, db domain.DBConn, logger *zap.Logger, timeout time.Duration) {
 	nr := repository.NewNotificationRepository(db)
-	cs := service.NewTwilioCallbackService(nr, maxAttempts)
+	cs := service.NewTwilioCallbackService(nr)
 	ch := handler.NewTwilioStatusCallbackHandler(cs, logger, timeout)
 
 	mux.HandleFunc("/callback", ch.ProcessCallback).Methods(http.MethodPost)
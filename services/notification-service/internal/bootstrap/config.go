@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/SteeperMold/Emergency-Notification-System/services/notification-service/internal/models"
 	"github.com/joho/godotenv"
 )
 
@@ -21,12 +22,13 @@ type Config struct {
 // AppConfig holds general application settings.
 type AppConfig struct {
 	AppEnv                            string
-	MaxAttempts                       int
+	DefaultRetryPolicy                models.RetryPolicy
 	Port                              string
 	ContextTimeout                    time.Duration
 	NotificationConsumerBatchSize     int
 	NotificationConsumerFlushInterval time.Duration
 	NotificationTasksWriterBatchSize  int
+	CancellationRetryInterval         time.Duration
 }
 
 // DBConfig holds PostgreSQL database connection settings.
@@ -44,6 +46,7 @@ type KafkaConfig struct {
 	KafkaAddrs                          []string
 	Topics                              map[string]string
 	ConsumerGroup                       string
+	CancellationsConsumerGroup          string
 	NotificationTasksWriterBatchTimeout time.Duration
 }
 
@@ -65,12 +68,13 @@ func NewConfig() *Config {
 	return &Config{
 		App: &AppConfig{
 			AppEnv:                            getEnv("APP_ENV", "development"),
-			MaxAttempts:                       getEnvAsInt("MAX_NOTIFICATION_ATTEMPTS", 5),
+			DefaultRetryPolicy:                getEnvAsRetryPolicy(),
 			Port:                              getEnv("PORT", "8081"),
 			ContextTimeout:                    getEnvAsDuration("CONTEXT_TIMEOUT_MS", 2000) * time.Millisecond,
 			NotificationConsumerBatchSize:     getEnvAsInt("NOTIFICATION_CONSUMER_BATCH_SIZE", 200_000),
 			NotificationConsumerFlushInterval: getEnvAsDuration("NOTIFICATION_CONSUMER_FLUSH_INTERVAL_MS", 5000) * time.Millisecond,
 			NotificationTasksWriterBatchSize:  getEnvAsInt("NOTIFICATION_TASKS_WRITER_BATCH_SIZE", 10_000),
+			CancellationRetryInterval:         getEnvAsDuration("CANCELLATION_RETRY_INTERVAL_MS", 1000) * time.Millisecond,
 		},
 		DB: &DBConfig{
 			Host:              getEnv("DB_HOST", "notification-service"),
@@ -83,10 +87,12 @@ func NewConfig() *Config {
 		Kafka: &KafkaConfig{
 			KafkaAddrs: getEnvAsSlice("KAFKA_ADDRS", []string{"kafka:9092"}, ","),
 			Topics: map[string]string{
-				"notification.requests": getEnv("KAFKA_TOPIC_NOTIFICATION_REQUESTS", "notification.requests"),
-				"notification.tasks":    getEnv("KAFKA_TOPIC_NOTIFICATION_TASKS", "notification.tasks"),
+				"notification.requests":      getEnv("KAFKA_TOPIC_NOTIFICATION_REQUESTS", "notification.requests"),
+				"notification.tasks":         getEnv("KAFKA_TOPIC_NOTIFICATION_TASKS", "notification.tasks"),
+				"notification.cancellations": getEnv("KAFKA_TOPIC_NOTIFICATION_CANCELLATIONS", "notification.cancellations"),
 			},
 			ConsumerGroup:                       getEnv("KAFKA_CONSUMER_GROUP", "notification-requests-group"),
+			CancellationsConsumerGroup:          getEnv("KAFKA_CANCELLATIONS_CONSUMER_GROUP", "notification-cancellations-group"),
 			NotificationTasksWriterBatchTimeout: getEnvAsDuration("KAFKA_NOTIFICATION_TASKS_WRITER_BATCH_TIMEOUT_MS", 1) * time.Millisecond,
 		},
 		Twilio: &TwilioConfig{
@@ -116,6 +122,17 @@ func getEnvAsInt(name string, defaultVal int) int {
 	return value
 }
 
+func getEnvAsFloat(name string, defaultVal float64) float64 {
+	valueStr := getEnv(name, "")
+
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return defaultVal
+	}
+
+	return value
+}
+
 func getEnvAsDuration(name string, defaultVal time.Duration) time.Duration {
 	value := getEnvAsInt(name, int(defaultVal))
 	return time.Duration(value)
@@ -142,3 +159,23 @@ func getEnvAsSlice(name string, defaultVal []string, sep string) []string {
 
 	return result
 }
+
+// getEnvAsRetryPolicy reads the default retry policy from MAX_NOTIFICATION_ATTEMPTS and the RETRY_*
+// variables. An invalid policy is fatal.
+func getEnvAsRetryPolicy() models.RetryPolicy {
+	policy := models.RetryPolicy{
+		MaxAttempts:  getEnvAsInt("MAX_NOTIFICATION_ATTEMPTS", 5),
+		Backoff:      models.BackoffKind(getEnv("RETRY_BACKOFF", string(models.BackoffExponential))),
+		BaseDelayMs:  getEnvAsInt("RETRY_BASE_DELAY_MS", 1000),
+		MaxDelayMs:   getEnvAsInt("RETRY_MAX_DELAY_MS", 300_000),
+		Jitter:       getEnvAsFloat("RETRY_JITTER", 0.2),
+		StaleAfterMs: getEnvAsInt("RETRY_STALE_AFTER_MS", 300_000),
+	}
+
+	err := policy.Validate()
+	if err != nil {
+		log.Fatalf("invalid default retry policy: %v", err)
+	}
+
+	return policy
+}
@@ -3,6 +3,7 @@ package domain
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/notification-service/internal/models"
 	"github.com/google/uuid"
@@ -19,27 +20,57 @@ type NotificationRequestsService interface {
 	SaveNotifications(ctx context.Context, notifications *[]*models.Notification) error
 }
 
+// CampaignCancellationsService defines the behavior for cancelling campaigns.
+// CancelCampaign returns the number of notifications it cancelled.
+type CampaignCancellationsService interface {
+	CancelCampaign(ctx context.Context, cancellation *CampaignCancellation) (int64, error)
+}
+
 // NotificationRepository encapsulates database operations
 // for notifications, including bulk creation and status updates
 type NotificationRepository interface {
 	CreateMultipleNotifications(ctx context.Context, notifications []*models.Notification) error
 	GetNotificationByID(ctx context.Context, id uuid.UUID) (*models.Notification, error)
 	ChangeNotificationStatus(ctx context.Context, id uuid.UUID, newStatus models.NotificationStatus) error
+	RescheduleNotification(ctx context.Context, id uuid.UUID, nextRunAt time.Time) error
+	FallBackNotification(ctx context.Context, id uuid.UUID, nextRunAt time.Time) (bool, error)
+	CancelCampaign(ctx context.Context, userID int, campaignID uuid.UUID) (int64, error)
+	GetCancelledCampaigns(ctx context.Context, campaignIDs []uuid.UUID) (map[uuid.UUID]bool, error)
 }
 
 // NotificationRequest represents the payload received from the API
 // containing a template and a list of contacts to notify.
+// RetryPolicy is nil for requests produced before retry policies were introduced.
+// QuietHours is only set for non-critical campaigns of users that configured them.
+// SenderID is the branded sender ID of the campaign template, if any.
+// CampaignID is the zero UUID for requests produced before campaigns had IDs.
 type NotificationRequest struct {
-	UserID   int                   `json:"userID"`
-	Template string                `json:"template"`
-	Contacts []*models.SlimContact `json:"contacts"`
+	UserID      int                   `json:"userID"`
+	CampaignID  uuid.UUID             `json:"campaignId"`
+	Template    string                `json:"template"`
+	Contacts    []*models.SlimContact `json:"contacts"`
+	RetryPolicy *models.RetryPolicy   `json:"retryPolicy,omitempty"`
+	Priority    models.Priority       `json:"priority,omitempty"`
+	QuietHours  *models.QuietHours    `json:"quietHours,omitempty"`
+	SenderID    string                `json:"senderId,omitempty"`
+}
+
+// CampaignCancellation represents the payload received from the API cancelling a campaign.
+type CampaignCancellation struct {
+	UserID     int       `json:"userID"`
+	CampaignID uuid.UUID `json:"campaignId"`
 }
 
 // SendNotificationTask describes the individual unit of work
-// sent to a worker for sending a single SMS.
+// sent to a worker for sending a single message. An empty Channel means SMS.
+// CampaignID identifies the campaign of the notification, so that the worker can skip cancelled ones.
 type SendNotificationTask struct {
-	ID             uuid.UUID `json:"id"`
-	Text           string    `json:"text"`
-	RecipientPhone string    `json:"recipientPhone"`
-	Attempts       int       `json:"attempts"`
+	ID             uuid.UUID           `json:"id"`
+	CampaignID     uuid.UUID           `json:"campaignId"`
+	Text           string              `json:"text"`
+	Channel        models.Channel      `json:"channel,omitempty"`
+	RecipientPhone string              `json:"recipientPhone"`
+	Attempts       int                 `json:"attempts"`
+	RetryPolicy    *models.RetryPolicy `json:"retryPolicy,omitempty"`
+	SenderID       string              `json:"senderId,omitempty"`
 }
@@ -1,8 +1,29 @@
 package models
 
 // SlimContact represents the minimal information needed to send a notification.
-// It omits database metadata and user associations
+// It omits database metadata and user associations. Text is the message rendered
+// for the contact when the template has placeholders; the template is sent as is otherwise.
+// Fallbacks are the further endpoints of the contact in order of preference.
 type SlimContact struct {
-	Name  string `json:"name"`
-	Phone string `json:"phone"`
+	Name      string      `json:"name"`
+	Phone     string      `json:"phone"`
+	TimeZone  string      `json:"timeZone,omitempty"`
+	Text      string      `json:"text,omitempty"`
+	Fallbacks []*Fallback `json:"fallbacks,omitempty"`
+}
+
+// Channel is the way a notification reaches its recipient.
+type Channel string
+
+const (
+	// ChannelSMS delivers the notification as a text message to a phone number.
+	ChannelSMS Channel = "sms"
+	// ChannelEmail delivers the notification as an e-mail.
+	ChannelEmail Channel = "email"
+)
+
+// Fallback is an endpoint a notification moves to when delivery to the current one fails permanently.
+type Fallback struct {
+	Channel Channel `json:"channel"`
+	Address string  `json:"address"`
 }
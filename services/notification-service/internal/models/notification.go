@@ -18,17 +18,48 @@ var (
 	StatusPending NotificationStatus = "pending"
 	// StatusInFlight indicates the notification is currently being sent
 	StatusInFlight NotificationStatus = "in_flight"
+	// StatusCancelled indicates the campaign of the notification was cancelled before it was sent
+	StatusCancelled NotificationStatus = "cancelled"
 )
 
-// Notification captures all relevant data for a single SMS notification task
+// Notification captures all relevant data for a single notification task.
+// RecipientPhone holds the address of the recipient on the channel; Fallbacks are the
+// endpoints still left to try when delivery to it fails permanently. CampaignID is the zero UUID
+// for notifications of campaigns sent before campaigns had IDs. QuietHours, in the time zone of the
+// recipient, are only set for non-critical notifications of users that configured them.
 type Notification struct {
 	ID             uuid.UUID
 	UserID         int
+	CampaignID     uuid.UUID
 	Text           string
+	Channel        Channel
 	RecipientPhone string
+	Fallbacks      []*Fallback
 	Status         NotificationStatus
 	Attempts       int
+	RetryPolicy    RetryPolicy
+	SenderID       string
+	Priority       Priority
+	QuietHours     *QuietHours
 	NextRunAt      time.Time
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 }
+
+// RunAt returns when the notification may run again if it is due at t: t itself, or the end of
+// the recipient's quiet hours if t falls into them. Critical notifications are never deferred.
+func (n *Notification) RunAt(t time.Time) time.Time {
+	if n.QuietHours == nil || n.Priority == "" || n.Priority == PriorityCritical {
+		return t
+	}
+
+	loc, err := time.LoadLocation(n.QuietHours.TimeZone)
+	if err != nil {
+		return t
+	}
+	if until, ok := n.QuietHours.DeferUntil(t, loc); ok {
+		return until
+	}
+
+	return t
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Priority classifies a campaign by urgency.
+type Priority string
+
+const (
+	// PriorityCritical is used for emergency alerts. They are delivered immediately and ignore quiet hours.
+	PriorityCritical Priority = "critical"
+	// PriorityNormal is used for informational sends. They are deferred while the recipient is in quiet hours.
+	PriorityNormal Priority = "normal"
+)
+
+// QuietHours is a daily window, in the recipient's local time, during which non-critical
+// notifications are not delivered. StartMinute and EndMinute are minutes since local midnight;
+// a window with StartMinute > EndMinute wraps over midnight (e.g. 22:00-07:00).
+// TimeZone is used for recipients whose own time zone is unknown.
+type QuietHours struct {
+	StartMinute int    `json:"startMinute"`
+	EndMinute   int    `json:"endMinute"`
+	TimeZone    string `json:"timeZone"`
+}
+
+// DeferUntil reports whether now falls into the quiet window in loc and, if so,
+// returns the moment the window ends.
+func (qh QuietHours) DeferUntil(now time.Time, loc *time.Location) (time.Time, bool) {
+	local := now.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	switch {
+	case qh.StartMinute < qh.EndMinute:
+		if minute < qh.StartMinute || minute >= qh.EndMinute {
+			return time.Time{}, false
+		}
+	case minute >= qh.StartMinute:
+		// Evening part of a window that wraps midnight: it ends tomorrow.
+		midnight = midnight.AddDate(0, 0, 1)
+	case minute >= qh.EndMinute:
+		return time.Time{}, false
+	}
+
+	end := time.Date(midnight.Year(), midnight.Month(), midnight.Day(), 0, qh.EndMinute, 0, 0, loc)
+	return end, true
+}
@@ -0,0 +1,108 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/notification-service/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuietHours_DeferUntil(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	assert.NoError(t, err)
+
+	overnight := models.QuietHours{StartMinute: 22 * 60, EndMinute: 7 * 60}
+	lunch := models.QuietHours{StartMinute: 13 * 60, EndMinute: 14*60 + 30}
+
+	tests := []struct {
+		name      string
+		qh        models.QuietHours
+		now       time.Time
+		wantUntil time.Time
+		wantQuiet bool
+	}{
+		{
+			name:      "overnight window, evening",
+			qh:        overnight,
+			now:       time.Date(2025, 3, 10, 23, 15, 0, 0, moscow),
+			wantUntil: time.Date(2025, 3, 11, 7, 0, 0, 0, moscow),
+			wantQuiet: true,
+		},
+		{
+			name:      "overnight window, early morning",
+			qh:        overnight,
+			now:       time.Date(2025, 3, 11, 3, 0, 0, 0, moscow),
+			wantUntil: time.Date(2025, 3, 11, 7, 0, 0, 0, moscow),
+			wantQuiet: true,
+		},
+		{
+			name:      "overnight window, daytime",
+			qh:        overnight,
+			now:       time.Date(2025, 3, 11, 12, 0, 0, 0, moscow),
+			wantQuiet: false,
+		},
+		{
+			name:      "overnight window, end is exclusive",
+			qh:        overnight,
+			now:       time.Date(2025, 3, 11, 7, 0, 0, 0, moscow),
+			wantQuiet: false,
+		},
+		{
+			name:      "daytime window, inside",
+			qh:        lunch,
+			now:       time.Date(2025, 3, 11, 13, 0, 0, 0, moscow),
+			wantUntil: time.Date(2025, 3, 11, 14, 30, 0, 0, moscow),
+			wantQuiet: true,
+		},
+		{
+			name:      "daytime window, outside",
+			qh:        lunch,
+			now:       time.Date(2025, 3, 11, 15, 0, 0, 0, moscow),
+			wantQuiet: false,
+		},
+		{
+			name:      "now in another zone",
+			qh:        overnight,
+			now:       time.Date(2025, 3, 10, 20, 0, 0, 0, time.UTC),
+			wantUntil: time.Date(2025, 3, 11, 4, 0, 0, 0, time.UTC),
+			wantQuiet: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			until, quiet := tc.qh.DeferUntil(tc.now, moscow)
+			assert.Equal(t, tc.wantQuiet, quiet)
+			if tc.wantQuiet {
+				assert.True(t, tc.wantUntil.Equal(until), "want %v, got %v", tc.wantUntil, until)
+			}
+		})
+	}
+}
+
+func TestNotification_RunAt(t *testing.T) {
+	overnight := &models.QuietHours{StartMinute: 22 * 60, EndMinute: 7 * 60, TimeZone: "Europe/Moscow"}
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	assert.NoError(t, err)
+	night := time.Date(2025, 3, 10, 23, 15, 0, 0, moscow)
+	day := time.Date(2025, 3, 10, 12, 0, 0, 0, moscow)
+
+	tests := []struct {
+		name string
+		ntf  models.Notification
+		at   time.Time
+		want time.Time
+	}{
+		{"normal at night", models.Notification{Priority: models.PriorityNormal, QuietHours: overnight}, night, time.Date(2025, 3, 11, 7, 0, 0, 0, moscow)},
+		{"normal by day", models.Notification{Priority: models.PriorityNormal, QuietHours: overnight}, day, day},
+		{"critical at night", models.Notification{Priority: models.PriorityCritical, QuietHours: overnight}, night, night},
+		{"no quiet hours", models.Notification{Priority: models.PriorityNormal}, night, night},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, tt.want.Equal(tt.ntf.RunAt(tt.at)))
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// BackoffKind names the curve used to grow the delay between delivery attempts.
+type BackoffKind string
+
+const (
+	// BackoffExponential doubles the delay after every attempt: base * 2^(attempt-1).
+	BackoffExponential BackoffKind = "exponential"
+	// BackoffLinear grows the delay linearly: base * attempt.
+	BackoffLinear BackoffKind = "linear"
+	// BackoffConstant waits the base delay between every attempt.
+	BackoffConstant BackoffKind = "constant"
+)
+
+// RetryPolicy describes how a notification is retried after a failed delivery attempt.
+// It is chosen when a campaign is launched and travels with every notification of that campaign.
+type RetryPolicy struct {
+	MaxAttempts  int         `json:"maxAttempts"`
+	Backoff      BackoffKind `json:"backoff"`
+	BaseDelayMs  int         `json:"baseDelayMs"`
+	MaxDelayMs   int         `json:"maxDelayMs"`
+	Jitter       float64     `json:"jitter"`
+	StaleAfterMs int         `json:"staleAfterMs"`
+}
+
+// Delay returns how long to wait before the attempt following the given one.
+// rnd must be a value in [0, 1) and is used to subtract up to Jitter*delay from the result,
+// so that retries of a large campaign do not hit the provider at the same moment.
+func (p RetryPolicy) Delay(attempt int, rnd float64) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	base := float64(p.BaseDelayMs)
+	var delayMs float64
+
+	switch p.Backoff {
+	case BackoffLinear:
+		delayMs = base * float64(attempt)
+	case BackoffConstant:
+		delayMs = base
+	default:
+		delayMs = base * math.Pow(2, float64(attempt-1))
+	}
+
+	if p.MaxDelayMs > 0 && delayMs > float64(p.MaxDelayMs) {
+		delayMs = float64(p.MaxDelayMs)
+	}
+
+	delayMs -= delayMs * p.Jitter * rnd
+
+	return time.Duration(delayMs * float64(time.Millisecond))
+}
+
+// bounds of the default retry policy; policies of campaigns are checked by the API service
+const (
+	maxAttemptsLimit = 50
+	minBaseDelayMs   = 100
+	maxDelayMsLimit  = 24 * 60 * 60 * 1000
+	minStaleAfterMs  = 60 * 1000
+)
+
+// Validate reports why the policy can't be used as the default one, if it can't.
+func (p RetryPolicy) Validate() error {
+	switch p.Backoff {
+	case BackoffExponential, BackoffLinear, BackoffConstant:
+	default:
+		return fmt.Errorf("unknown backoff %q", p.Backoff)
+	}
+
+	if p.MaxAttempts < 1 || p.MaxAttempts > maxAttemptsLimit {
+		return fmt.Errorf("max attempts must be between 1 and %d", maxAttemptsLimit)
+	}
+	if p.BaseDelayMs < minBaseDelayMs {
+		return fmt.Errorf("base delay must be at least %d ms", minBaseDelayMs)
+	}
+	if p.MaxDelayMs < p.BaseDelayMs || p.MaxDelayMs > maxDelayMsLimit {
+		return fmt.Errorf("max delay must be between the base delay and %d ms", maxDelayMsLimit)
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return fmt.Errorf("jitter must be between 0 and 1")
+	}
+	if p.StaleAfterMs < minStaleAfterMs {
+		return fmt.Errorf("stale-after must be at least %d ms", minStaleAfterMs)
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/notification-service/internal/domain"
 	"github.com/SteeperMold/Emergency-Notification-System/services/notification-service/internal/models"
@@ -24,17 +25,49 @@ func NewNotificationRepository(db domain.DBConn) *NotificationRepository {
 }
 
 // CreateMultipleNotifications inserts multiple notification records in a single batch using COPY FROM.
-// Each notification is initialized with status "in_flight" and attempts = 1.
+// Each notification is initialized with status "in_flight" and attempts = 1,
+// unless it is deferred (status "pending"), in which case it is stored with attempts = 0
+// and scheduled for its NextRunAt, or its campaign was cancelled (status "cancelled").
+// Every notification stores the retry policy it was created with, its campaign,
+// its channel, SMS when unset, the endpoints to fall back to, and its priority and quiet hours.
 func (nr *NotificationRepository) CreateMultipleNotifications(ctx context.Context, notifications []*models.Notification) error {
+	now := time.Now()
 	rows := make([][]any, len(notifications))
 	for i, n := range notifications {
+		status, attempts, nextRunAt := models.StatusInFlight, 1, now
+		switch n.Status {
+		case models.StatusPending:
+			status, attempts, nextRunAt = models.StatusPending, 0, n.NextRunAt
+		case models.StatusCancelled:
+			status, attempts = models.StatusCancelled, 0
+		}
+
+		var campaignID any
+		if n.CampaignID != uuid.Nil {
+			campaignID = n.CampaignID
+		}
+
+		channel := n.Channel
+		if channel == "" {
+			channel = models.ChannelSMS
+		}
+		fallbacks := n.Fallbacks
+		if fallbacks == nil {
+			fallbacks = []*models.Fallback{}
+		}
+
+		p := n.RetryPolicy
 		rows[i] = []any{
-			n.ID, n.UserID, n.Text, n.RecipientPhone, "in_flight", 1,
+			n.ID, n.UserID, n.Text, n.RecipientPhone, string(status), attempts, nextRunAt,
+			p.MaxAttempts, string(p.Backoff), p.BaseDelayMs, p.MaxDelayMs, p.Jitter, p.StaleAfterMs, n.SenderID,
+			string(channel), fallbacks, campaignID, string(n.Priority), n.QuietHours,
 		}
 	}
 
 	_, err := nr.db.CopyFrom(ctx, pgx.Identifier{"notifications"}, []string{
-		"id", "user_id", "text", "recipient_phone", "status", "attempts",
+		"id", "user_id", "text", "recipient_phone", "status", "attempts", "next_run_at",
+		"max_attempts", "backoff", "base_delay_ms", "max_delay_ms", "jitter", "stale_after_ms", "sender_id",
+		"channel", "fallbacks", "campaign_id", "priority", "quiet_hours",
 	}, pgx.CopyFromRows(rows))
 	if err != nil {
 		return err
@@ -47,21 +80,32 @@ func (nr *NotificationRepository) CreateMultipleNotifications(ctx context.Contex
 // Returns domain.ErrNotificationNotExists if no matching record is found.
 func (nr *NotificationRepository) GetNotificationByID(ctx context.Context, id uuid.UUID) (*models.Notification, error) {
 	const q = `
-		SELECT id, user_id, text, recipient_phone, status, attempts, next_run_at, created_at, updated_at
+		SELECT id, user_id, text, recipient_phone, status, attempts, next_run_at, created_at, updated_at,
+		       max_attempts, backoff, base_delay_ms, max_delay_ms, jitter, stale_after_ms, sender_id,
+		       channel, fallbacks, campaign_id, priority, quiet_hours
 		FROM notifications
 		WHERE id = $1
 	`
 
 	var n models.Notification
+	var campaignID *uuid.UUID
 
 	row := nr.db.QueryRow(ctx, q, id)
-	err := row.Scan(&n.ID, &n.UserID, &n.Text, &n.RecipientPhone, &n.Status, &n.Attempts, &n.NextRunAt, &n.CreatedAt, &n.UpdatedAt)
+	p := &n.RetryPolicy
+	err := row.Scan(
+		&n.ID, &n.UserID, &n.Text, &n.RecipientPhone, &n.Status, &n.Attempts, &n.NextRunAt, &n.CreatedAt, &n.UpdatedAt,
+		&p.MaxAttempts, &p.Backoff, &p.BaseDelayMs, &p.MaxDelayMs, &p.Jitter, &p.StaleAfterMs, &n.SenderID,
+		&n.Channel, &n.Fallbacks, &campaignID, &n.Priority, &n.QuietHours,
+	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrNotificationNotExists
 		}
 		return nil, err
 	}
+	if campaignID != nil {
+		n.CampaignID = *campaignID
+	}
 
 	return &n, nil
 }
@@ -87,3 +131,110 @@ func (nr *NotificationRepository) ChangeNotificationStatus(ctx context.Context,
 
 	return nil
 }
+
+// RescheduleNotification moves a notification back to "pending" so that the rebalancer
+// picks it up again once nextRunAt has passed.
+// Returns domain.ErrNotificationNotExists if the record does not exist.
+func (nr *NotificationRepository) RescheduleNotification(ctx context.Context, id uuid.UUID, nextRunAt time.Time) error {
+	const q = `
+		UPDATE notifications
+		SET status = 'pending',
+		    next_run_at = $2,
+		    updated_at = NOW()
+		WHERE id = $1
+	`
+
+	cmdTag, err := nr.db.Exec(ctx, q, id, nextRunAt)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return domain.ErrNotificationNotExists
+	}
+
+	return nil
+}
+
+// FallBackNotification moves a notification to the first of its remaining fallback endpoints and
+// schedules it for delivery there at nextRunAt with a fresh attempt count. It reports false, leaving
+// the notification unchanged, when no fallback endpoint is left.
+func (nr *NotificationRepository) FallBackNotification(ctx context.Context, id uuid.UUID, nextRunAt time.Time) (bool, error) {
+	const q = `
+		UPDATE notifications
+		SET channel = fallbacks -> 0 ->> 'channel',
+		    recipient_phone = fallbacks -> 0 ->> 'address',
+		    fallbacks = fallbacks - 0,
+		    status = 'pending',
+		    attempts = 0,
+		    next_run_at = $2,
+		    updated_at = NOW()
+		WHERE id = $1
+		  AND jsonb_array_length(fallbacks) > 0
+	`
+
+	cmdTag, err := nr.db.Exec(ctx, q, id, nextRunAt)
+	if err != nil {
+		return false, err
+	}
+
+	return cmdTag.RowsAffected() > 0, nil
+}
+
+// CancelCampaign records that the campaign of the user was cancelled and cancels its notifications
+// that are waiting to be sent, including those queued for the sender that it hasn't claimed yet.
+// Notifications the sender already claimed are handed to a provider and left as they are.
+// Returns the number of cancelled notifications; cancelling a campaign again is a no-op.
+func (nr *NotificationRepository) CancelCampaign(ctx context.Context, userID int, campaignID uuid.UUID) (int64, error) {
+	const q = `
+		WITH cancelled AS (
+			INSERT INTO cancelled_campaigns (campaign_id, user_id)
+			VALUES ($1, $2)
+			ON CONFLICT (campaign_id) DO NOTHING
+		)
+		UPDATE notifications
+		SET status = 'cancelled',
+		    updated_at = NOW()
+		WHERE campaign_id = $1
+		  AND user_id = $2
+		  AND status IN ('pending', 'in_flight')
+	`
+
+	cmdTag, err := nr.db.Exec(ctx, q, campaignID, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	return cmdTag.RowsAffected(), nil
+}
+
+// GetCancelledCampaigns reports which of the given campaigns were cancelled.
+func (nr *NotificationRepository) GetCancelledCampaigns(ctx context.Context, campaignIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	const q = `
+		SELECT campaign_id
+		FROM cancelled_campaigns
+		WHERE campaign_id = ANY($1)
+	`
+
+	rows, err := nr.db.Query(ctx, q, campaignIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cancelled := make(map[uuid.UUID]bool)
+	for rows.Next() {
+		var id uuid.UUID
+		err := rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+		cancelled[id] = true
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return cancelled, nil
+}
@@ -24,6 +24,15 @@ func TestNotificationRepository_CreateMultipleNotifications(t *testing.T) {
 		UserID:         101,
 		Text:           "First test",
 		RecipientPhone: "+10000000001",
+		RetryPolicy: models.RetryPolicy{
+			MaxAttempts:  7,
+			Backoff:      models.BackoffLinear,
+			BaseDelayMs:  2000,
+			MaxDelayMs:   60000,
+			Jitter:       0.1,
+			StaleAfterMs: 120000,
+		},
+		SenderID: "CityAlert",
 	}
 	ntf2 := &models.Notification{
 		ID:             uuid.New(),
@@ -40,12 +49,42 @@ func TestNotificationRepository_CreateMultipleNotifications(t *testing.T) {
 	assert.Equal(t, ntf1.Text, got1.Text)
 	assert.Equal(t, models.StatusInFlight, got1.Status)
 	assert.Equal(t, 1, got1.Attempts)
+	assert.Equal(t, ntf1.RetryPolicy, got1.RetryPolicy)
+	assert.Equal(t, ntf1.SenderID, got1.SenderID)
 
 	got2, err := repo.GetNotificationByID(ctx, ntf2.ID)
 	assert.NoError(t, err)
 	assert.Equal(t, ntf2.RecipientPhone, got2.RecipientPhone)
 }
 
+func TestNotificationRepository_CreateMultipleNotifications_Deferred(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewNotificationRepository(testPool)
+
+	nextRunAt := time.Now().Add(8 * time.Hour).UTC().Truncate(time.Second)
+	ntf := &models.Notification{
+		ID:             uuid.New(),
+		UserID:         102,
+		Text:           "Deferred",
+		RecipientPhone: "+10000000003",
+		Status:         models.StatusPending,
+		NextRunAt:      nextRunAt,
+		Priority:       models.PriorityNormal,
+		QuietHours:     &models.QuietHours{StartMinute: 22 * 60, EndMinute: 7 * 60, TimeZone: "Europe/Moscow"},
+	}
+
+	err := repo.CreateMultipleNotifications(ctx, []*models.Notification{ntf})
+	assert.NoError(t, err)
+
+	got, err := repo.GetNotificationByID(ctx, ntf.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusPending, got.Status)
+	assert.Equal(t, 0, got.Attempts)
+	assert.True(t, nextRunAt.Equal(got.NextRunAt))
+	assert.Equal(t, models.PriorityNormal, got.Priority)
+	assert.Equal(t, ntf.QuietHours, got.QuietHours)
+}
+
 func TestNotificationRepository_GetNotificationByID_NotExists(t *testing.T) {
 	ctx := context.Background()
 	repo := repository.NewNotificationRepository(testPool)
@@ -84,3 +123,165 @@ func TestNotificationRepository_ChangeNotificationStatus_NotExists(t *testing.T)
 	err := repo.ChangeNotificationStatus(ctx, uuid.New(), models.StatusFailed)
 	assert.ErrorIs(t, err, domain.ErrNotificationNotExists)
 }
+
+func TestNotificationRepository_RescheduleNotification(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewNotificationRepository(testPool)
+
+	ntf := &models.Notification{
+		ID:             uuid.New(),
+		UserID:         300,
+		Text:           "Will retry",
+		RecipientPhone: "+10000000004",
+	}
+
+	err := repo.CreateMultipleNotifications(ctx, []*models.Notification{ntf})
+	assert.NoError(t, err)
+
+	nextRunAt := time.Now().Add(time.Minute)
+	err = repo.RescheduleNotification(ctx, ntf.ID, nextRunAt)
+	assert.NoError(t, err)
+
+	updated, err := repo.GetNotificationByID(ctx, ntf.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusPending, updated.Status)
+	assert.WithinDuration(t, nextRunAt, updated.NextRunAt, time.Millisecond)
+}
+
+func TestNotificationRepository_RescheduleNotification_NotExists(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewNotificationRepository(testPool)
+
+	err := repo.RescheduleNotification(ctx, uuid.New(), time.Now())
+	assert.ErrorIs(t, err, domain.ErrNotificationNotExists)
+}
+
+func TestNotificationRepository_FallBackNotification(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewNotificationRepository(testPool)
+
+	ntf := &models.Notification{
+		ID:             uuid.New(),
+		UserID:         400,
+		Text:           "Falls back",
+		RecipientPhone: "+10000000005",
+		Fallbacks: []*models.Fallback{
+			{Channel: models.ChannelSMS, Address: "+10000000006"},
+			{Channel: models.ChannelEmail, Address: "ivan@example.com"},
+		},
+	}
+
+	err := repo.CreateMultipleNotifications(ctx, []*models.Notification{ntf})
+	assert.NoError(t, err)
+
+	got, err := repo.GetNotificationByID(ctx, ntf.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ChannelSMS, got.Channel)
+	assert.Equal(t, ntf.Fallbacks, got.Fallbacks)
+
+	fellBack, err := repo.FallBackNotification(ctx, ntf.ID, time.Now())
+	assert.NoError(t, err)
+	assert.True(t, fellBack)
+
+	got, err = repo.GetNotificationByID(ctx, ntf.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "+10000000006", got.RecipientPhone)
+	assert.Equal(t, models.StatusPending, got.Status)
+	assert.Equal(t, 0, got.Attempts)
+
+	morning := time.Now().Add(8 * time.Hour).UTC().Truncate(time.Second)
+	fellBack, err = repo.FallBackNotification(ctx, ntf.ID, morning)
+	assert.NoError(t, err)
+	assert.True(t, fellBack)
+
+	got, err = repo.GetNotificationByID(ctx, ntf.ID)
+	assert.NoError(t, err)
+	assert.True(t, morning.Equal(got.NextRunAt))
+	assert.Equal(t, models.ChannelEmail, got.Channel)
+	assert.Equal(t, "ivan@example.com", got.RecipientPhone)
+	assert.Empty(t, got.Fallbacks)
+
+	fellBack, err = repo.FallBackNotification(ctx, ntf.ID, time.Now())
+	assert.NoError(t, err)
+	assert.False(t, fellBack)
+}
+
+func TestNotificationRepository_CancelCampaign(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewNotificationRepository(testPool)
+
+	campaignID := uuid.New()
+	pending := &models.Notification{
+		ID:             uuid.New(),
+		UserID:         500,
+		CampaignID:     campaignID,
+		Text:           "Deferred",
+		RecipientPhone: "+10000000007",
+		Status:         models.StatusPending,
+		NextRunAt:      time.Now().Add(time.Hour),
+	}
+	inFlight := &models.Notification{
+		ID:             uuid.New(),
+		UserID:         500,
+		CampaignID:     campaignID,
+		Text:           "Queued for the sender",
+		RecipientPhone: "+10000000008",
+	}
+	sending := &models.Notification{
+		ID:             uuid.New(),
+		UserID:         500,
+		CampaignID:     campaignID,
+		Text:           "Claimed by the sender",
+		RecipientPhone: "+10000000010",
+	}
+
+	err := repo.CreateMultipleNotifications(ctx, []*models.Notification{pending, inFlight, sending})
+	assert.NoError(t, err)
+	_, err = testDB.ExecContext(ctx, `UPDATE notifications SET status = 'sending' WHERE id = $1`, sending.ID)
+	assert.NoError(t, err)
+
+	cancelled, err := repo.GetCancelledCampaigns(ctx, []uuid.UUID{campaignID})
+	assert.NoError(t, err)
+	assert.Empty(t, cancelled)
+
+	n, err := repo.CancelCampaign(ctx, 500, campaignID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	n, err = repo.CancelCampaign(ctx, 500, campaignID)
+	assert.NoError(t, err)
+	assert.Zero(t, n)
+
+	got, err := repo.GetNotificationByID(ctx, pending.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCancelled, got.Status)
+	assert.Equal(t, campaignID, got.CampaignID)
+
+	got, err = repo.GetNotificationByID(ctx, inFlight.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCancelled, got.Status)
+
+	got, err = repo.GetNotificationByID(ctx, sending.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.NotificationStatus("sending"), got.Status)
+
+	cancelled, err = repo.GetCancelledCampaigns(ctx, []uuid.UUID{campaignID, uuid.New()})
+	assert.NoError(t, err)
+	assert.Equal(t, map[uuid.UUID]bool{campaignID: true}, cancelled)
+
+	late := &models.Notification{
+		ID:             uuid.New(),
+		UserID:         500,
+		CampaignID:     campaignID,
+		Text:           "Late",
+		RecipientPhone: "+10000000009",
+		Status:         models.StatusCancelled,
+	}
+	err = repo.CreateMultipleNotifications(ctx, []*models.Notification{late})
+	assert.NoError(t, err)
+
+	got, err = repo.GetNotificationByID(ctx, late.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCancelled, got.Status)
+	assert.Equal(t, 0, got.Attempts)
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/notification-service/internal/domain"
+)
+
+// CampaignCancellationsService cancels the notifications of cancelled campaigns.
+type CampaignCancellationsService struct {
+	repository domain.NotificationRepository
+}
+
+// NewCampaignCancellationsService constructs a CampaignCancellationsService
+func NewCampaignCancellationsService(r domain.NotificationRepository) *CampaignCancellationsService {
+	return &CampaignCancellationsService{
+		repository: r,
+	}
+}
+
+// CancelCampaign records the cancellation of the campaign, so that its notifications arriving later
+// are not sent, and cancels those that are waiting to be sent.
+// Returns the number of notifications cancelled.
+func (ccs *CampaignCancellationsService) CancelCampaign(ctx context.Context, c *domain.CampaignCancellation) (int64, error) {
+	return ccs.repository.CancelCampaign(ctx, c.UserID, c.CampaignID)
+}
@@ -0,0 +1,26 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/notification-service/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/notification-service/internal/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCancelCampaign(t *testing.T) {
+	campaignID := uuid.New()
+
+	repo := new(MockNotificationRepository)
+	repo.On("CancelCampaign", mock.Anything, 7, campaignID).Return(int64(3), nil).Once()
+
+	svc := service.NewCampaignCancellationsService(repo)
+	n, err := svc.CancelCampaign(context.Background(), &domain.CampaignCancellation{UserID: 7, CampaignID: campaignID})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+	repo.AssertExpectations(t)
+}
@@ -2,6 +2,7 @@ package service_test
 
 import (
 	"context"
+	"time"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/notification-service/internal/bootstrap"
 	"github.com/SteeperMold/Emergency-Notification-System/services/notification-service/internal/models"
@@ -72,6 +73,26 @@ func (m *MockNotificationRepository) ChangeNotificationStatus(ctx context.Contex
 	return m.Called(ctx, id, newStatus).Error(0)
 }
 
+func (m *MockNotificationRepository) RescheduleNotification(ctx context.Context, id uuid.UUID, nextRunAt time.Time) error {
+	return m.Called(ctx, id, nextRunAt).Error(0)
+}
+
+func (m *MockNotificationRepository) FallBackNotification(ctx context.Context, id uuid.UUID, nextRunAt time.Time) (bool, error) {
+	args := m.Called(ctx, id, nextRunAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockNotificationRepository) CancelCampaign(ctx context.Context, userID int, campaignID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, userID, campaignID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationRepository) GetCancelledCampaigns(ctx context.Context, campaignIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	args := m.Called(ctx, campaignIDs)
+	cancelled, _ := args.Get(0).(map[uuid.UUID]bool)
+	return cancelled, args.Error(1)
+}
+
 type MockKafkaWriter struct {
 	mock.Mock
 }
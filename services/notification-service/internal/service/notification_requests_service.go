@@ -6,6 +6,7 @@ import (
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/notification-service/internal/domain"
 	"github.com/SteeperMold/Emergency-Notification-System/services/notification-service/internal/models"
+	"github.com/google/uuid"
 	"github.com/segmentio/kafka-go"
 )
 
@@ -28,24 +29,40 @@ func NewNotificationRequestsService(r domain.NotificationRepository, kw domain.K
 
 // SaveNotifications persists a slice of notifications to the database and then
 // publishes SendNotificationTask messages to Kafka in batches.
+// Notifications deferred by quiet hours are only persisted; the rebalancer publishes them once they are due.
+// Notifications of campaigns cancelled before they arrived are persisted as cancelled and never published.
 func (nrs *NotificationRequestsService) SaveNotifications(ctx context.Context, ntfs *[]*models.Notification) error {
-	err := nrs.repository.CreateMultipleNotifications(ctx, *ntfs)
+	err := nrs.cancelNotifications(ctx, *ntfs)
 	if err != nil {
 		return err
 	}
 
-	msgs := make([]kafka.Message, len(*ntfs))
-	for i, n := range *ntfs {
+	err = nrs.repository.CreateMultipleNotifications(ctx, *ntfs)
+	if err != nil {
+		return err
+	}
+
+	msgs := make([]kafka.Message, 0, len(*ntfs))
+	for _, n := range *ntfs {
+		if n.Status == models.StatusPending || n.Status == models.StatusCancelled {
+			continue
+		}
+
+		policy := n.RetryPolicy
 		taskBytes, err := json.Marshal(&domain.SendNotificationTask{
 			ID:             n.ID,
+			CampaignID:     n.CampaignID,
 			Text:           n.Text,
+			Channel:        n.Channel,
 			RecipientPhone: n.RecipientPhone,
 			Attempts:       1,
+			RetryPolicy:    &policy,
+			SenderID:       n.SenderID,
 		})
 		if err != nil {
 			return err
 		}
-		msgs[i] = kafka.Message{Value: taskBytes}
+		msgs = append(msgs, kafka.Message{Value: taskBytes})
 	}
 
 	for start := 0; start < len(msgs); start += nrs.batchSize {
@@ -64,3 +81,32 @@ func (nrs *NotificationRequestsService) SaveNotifications(ctx context.Context, n
 
 	return nil
 }
+
+// cancelNotifications marks the notifications of cancelled campaigns as cancelled.
+func (nrs *NotificationRequestsService) cancelNotifications(ctx context.Context, ntfs []*models.Notification) error {
+	seen := make(map[uuid.UUID]bool)
+	campaignIDs := make([]uuid.UUID, 0)
+	for _, n := range ntfs {
+		if n.CampaignID == uuid.Nil || seen[n.CampaignID] {
+			continue
+		}
+		seen[n.CampaignID] = true
+		campaignIDs = append(campaignIDs, n.CampaignID)
+	}
+	if len(campaignIDs) == 0 {
+		return nil
+	}
+
+	cancelled, err := nrs.repository.GetCancelledCampaigns(ctx, campaignIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, n := range ntfs {
+		if cancelled[n.CampaignID] {
+			n.Status = models.StatusCancelled
+		}
+	}
+
+	return nil
+}
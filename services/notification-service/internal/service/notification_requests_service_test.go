@@ -20,7 +20,14 @@ func TestSaveNotifications(t *testing.T) {
 		ID:             id,
 		Text:           "Test message",
 		RecipientPhone: "+1234567890",
+		RetryPolicy: models.RetryPolicy{
+			MaxAttempts: 5,
+			Backoff:     models.BackoffExponential,
+			BaseDelayMs: 1000,
+		},
 	}
+	cancelledCampaign := uuid.New()
+	liveCampaign := uuid.New()
 
 	tests := []struct {
 		name          string
@@ -43,9 +50,11 @@ func TestSaveNotifications(t *testing.T) {
 				for _, n := range []*models.Notification{baseNtf, baseNtf} {
 					taskBytes, _ := json.Marshal(&domain.SendNotificationTask{
 						ID:             n.ID,
+						CampaignID:     n.CampaignID,
 						Text:           n.Text,
 						RecipientPhone: n.RecipientPhone,
 						Attempts:       1,
+						RetryPolicy:    &n.RetryPolicy,
 					})
 					msgs = append(msgs, kafka.Message{Value: taskBytes})
 				}
@@ -57,6 +66,82 @@ func TestSaveNotifications(t *testing.T) {
 			},
 			expectErr: false,
 		},
+		{
+			name: "deferred notifications are not published",
+			notifications: []*models.Notification{
+				baseNtf,
+				{ID: uuid.New(), Text: "Later", RecipientPhone: "+1987654321", Status: models.StatusPending},
+			},
+			batchSize: 5,
+			setupMocks: func(r *MockNotificationRepository, w *MockKafkaWriter) {
+				r.
+					On("CreateMultipleNotifications", mock.Anything, mock.Anything).
+					Return(nil).
+					Once()
+
+				taskBytes, _ := json.Marshal(&domain.SendNotificationTask{
+					ID:             baseNtf.ID,
+					CampaignID:     baseNtf.CampaignID,
+					Text:           baseNtf.Text,
+					RecipientPhone: baseNtf.RecipientPhone,
+					Attempts:       1,
+					RetryPolicy:    &baseNtf.RetryPolicy,
+				})
+
+				w.
+					On("WriteMessages", mock.Anything, []kafka.Message{{Value: taskBytes}}).
+					Return(nil).
+					Once()
+			},
+			expectErr: false,
+		},
+		{
+			name: "notifications of cancelled campaigns are not published",
+			notifications: []*models.Notification{
+				{ID: uuid.New(), CampaignID: cancelledCampaign, Text: "Cancelled", RecipientPhone: "+1987654321"},
+				{ID: baseNtf.ID, CampaignID: liveCampaign, Text: baseNtf.Text, RecipientPhone: baseNtf.RecipientPhone, RetryPolicy: baseNtf.RetryPolicy},
+			},
+			batchSize: 5,
+			setupMocks: func(r *MockNotificationRepository, w *MockKafkaWriter) {
+				r.
+					On("GetCancelledCampaigns", mock.Anything, []uuid.UUID{cancelledCampaign, liveCampaign}).
+					Return(map[uuid.UUID]bool{cancelledCampaign: true}, nil).
+					Once()
+				r.
+					On("CreateMultipleNotifications", mock.Anything, mock.MatchedBy(func(ntfs []*models.Notification) bool {
+						return ntfs[0].Status == models.StatusCancelled && ntfs[1].Status == ""
+					})).
+					Return(nil).
+					Once()
+
+				taskBytes, _ := json.Marshal(&domain.SendNotificationTask{
+					ID:             baseNtf.ID,
+					CampaignID:     liveCampaign,
+					Text:           baseNtf.Text,
+					RecipientPhone: baseNtf.RecipientPhone,
+					Attempts:       1,
+					RetryPolicy:    &baseNtf.RetryPolicy,
+				})
+
+				w.
+					On("WriteMessages", mock.Anything, []kafka.Message{{Value: taskBytes}}).
+					Return(nil).
+					Once()
+			},
+			expectErr: false,
+		},
+		{
+			name:          "cancelled campaigns lookup failure",
+			notifications: []*models.Notification{{ID: uuid.New(), CampaignID: liveCampaign}},
+			batchSize:     2,
+			setupMocks: func(r *MockNotificationRepository, w *MockKafkaWriter) {
+				r.
+					On("GetCancelledCampaigns", mock.Anything, []uuid.UUID{liveCampaign}).
+					Return(nil, assert.AnError).
+					Once()
+			},
+			expectErr: true,
+		},
 		{
 			name:          "repository failure",
 			notifications: []*models.Notification{baseNtf},
@@ -83,9 +168,11 @@ func TestSaveNotifications(t *testing.T) {
 				for _, n := range []*models.Notification{baseNtf, baseNtf} {
 					taskBytes, _ := json.Marshal(&domain.SendNotificationTask{
 						ID:             n.ID,
+						CampaignID:     n.CampaignID,
 						Text:           n.Text,
 						RecipientPhone: n.RecipientPhone,
 						Attempts:       1,
+						RetryPolicy:    &n.RetryPolicy,
 					})
 					firstBatch = append(firstBatch, kafka.Message{Value: taskBytes})
 				}
@@ -97,9 +184,11 @@ func TestSaveNotifications(t *testing.T) {
 				third := baseNtf
 				taskBytes, _ := json.Marshal(&domain.SendNotificationTask{
 					ID:             third.ID,
+					CampaignID:     third.CampaignID,
 					Text:           third.Text,
 					RecipientPhone: third.RecipientPhone,
 					Attempts:       1,
+					RetryPolicy:    &third.RetryPolicy,
 				})
 				lastBatch := []kafka.Message{{Value: taskBytes}}
 
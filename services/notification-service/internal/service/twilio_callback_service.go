@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"math/rand"
+	"time"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/notification-service/internal/domain"
 	"github.com/SteeperMold/Emergency-Notification-System/services/notification-service/internal/models"
@@ -10,16 +12,17 @@ import (
 
 // TwilioCallbackService processes status callbacks from Twilio and updates
 // the corresponding notification record in the database.
+// Failed deliveries are retried according to the retry policy stored with the notification;
+// once the retries are exhausted the notification falls back to the next endpoint of the contact.
+// Retries and fallbacks of non-critical notifications wait for the end of the recipient's quiet hours.
 type TwilioCallbackService struct {
-	repository  domain.NotificationRepository
-	maxAttempts int
+	repository domain.NotificationRepository
 }
 
 // NewTwilioCallbackService constructs a TwilioCallbackService.
-func NewTwilioCallbackService(r domain.NotificationRepository, maxAttempts int) *TwilioCallbackService {
+func NewTwilioCallbackService(r domain.NotificationRepository) *TwilioCallbackService {
 	return &TwilioCallbackService{
-		repository:  r,
-		maxAttempts: maxAttempts,
+		repository: r,
 	}
 }
 
@@ -40,11 +43,18 @@ func (s *TwilioCallbackService) ProcessCallback(ctx context.Context, idStr, stat
 		if err != nil {
 			return err
 		}
-		if ntf.Attempts < s.maxAttempts {
-			newStatus = models.StatusPending
-		} else {
-			newStatus = models.StatusFailed
+		if ntf.Attempts < ntf.RetryPolicy.MaxAttempts {
+			delay := ntf.RetryPolicy.Delay(ntf.Attempts, rand.Float64())
+			return s.repository.RescheduleNotification(ctx, id, ntf.RunAt(time.Now().Add(delay)))
 		}
+		fellBack, err := s.repository.FallBackNotification(ctx, id, ntf.RunAt(time.Now()))
+		if err != nil {
+			return err
+		}
+		if fellBack {
+			return nil
+		}
+		newStatus = models.StatusFailed
 	}
 
 	err = s.repository.ChangeNotificationStatus(ctx, id, newStatus)
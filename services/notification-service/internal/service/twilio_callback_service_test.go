@@ -3,6 +3,7 @@ package service_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/notification-service/internal/models"
 	"github.com/SteeperMold/Emergency-Notification-System/services/notification-service/internal/service"
@@ -13,6 +14,21 @@ import (
 
 func TestTwilioCallbackService_ProcessCallback(t *testing.T) {
 	validID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	policy := models.RetryPolicy{
+		MaxAttempts: 3,
+		Backoff:     models.BackoffExponential,
+		BaseDelayMs: 1000,
+	}
+
+	// A window covering the whole day except the last minute before its start,
+	// so that a retry is always inside it regardless of when the test runs.
+	now := time.Now().UTC()
+	minute := now.Hour()*60 + now.Minute()
+	quiet := &models.QuietHours{
+		StartMinute: minute,
+		EndMinute:   (minute + 24*60 - 1) % (24 * 60),
+		TimeZone:    "UTC",
+	}
 
 	tests := []struct {
 		name          string
@@ -51,16 +67,90 @@ func TestTwilioCallbackService_ProcessCallback(t *testing.T) {
 				r.
 					On("GetNotificationByID", mock.Anything, validID).
 					Return(&models.Notification{
-						ID:       validID,
-						Attempts: 1,
+						ID:          validID,
+						Attempts:    2,
+						RetryPolicy: policy,
 					}, nil).
 					Once()
+				nextRunMin := time.Now().Add(2 * time.Second)
+				nextRunMax := time.Now().Add(2*time.Second + 50*time.Millisecond)
 				r.
-					On("ChangeNotificationStatus", mock.Anything, validID, models.StatusPending).
+					On("RescheduleNotification", mock.Anything, validID, mock.MatchedBy(func(t time.Time) bool {
+						return t.After(nextRunMin) && t.Before(nextRunMax)
+					})).
 					Return(nil).
 					Once()
 			},
 		},
+		{
+			name:   "retry of normal priority waits for the end of quiet hours",
+			idStr:  validID.String(),
+			status: "failed",
+			setupMocks: func(r *MockNotificationRepository) {
+				r.
+					On("GetNotificationByID", mock.Anything, validID).
+					Return(&models.Notification{
+						ID:          validID,
+						Attempts:    1,
+						RetryPolicy: policy,
+						Priority:    models.PriorityNormal,
+						QuietHours:  quiet,
+					}, nil).
+					Once()
+				r.
+					On("RescheduleNotification", mock.Anything, validID, mock.MatchedBy(func(t time.Time) bool {
+						return t.After(now.Add(23*time.Hour)) && t.Before(now.Add(24*time.Hour))
+					})).
+					Return(nil).
+					Once()
+			},
+		},
+		{
+			name:   "retry of critical priority ignores quiet hours",
+			idStr:  validID.String(),
+			status: "failed",
+			setupMocks: func(r *MockNotificationRepository) {
+				r.
+					On("GetNotificationByID", mock.Anything, validID).
+					Return(&models.Notification{
+						ID:          validID,
+						Attempts:    1,
+						RetryPolicy: policy,
+						Priority:    models.PriorityCritical,
+						QuietHours:  quiet,
+					}, nil).
+					Once()
+				r.
+					On("RescheduleNotification", mock.Anything, validID, mock.MatchedBy(func(t time.Time) bool {
+						return t.Before(time.Now().Add(time.Minute))
+					})).
+					Return(nil).
+					Once()
+			},
+		},
+		{
+			name:   "fallback of normal priority waits for the end of quiet hours",
+			idStr:  validID.String(),
+			status: "undelivered",
+			setupMocks: func(r *MockNotificationRepository) {
+				r.
+					On("GetNotificationByID", mock.Anything, validID).
+					Return(&models.Notification{
+						ID:          validID,
+						Attempts:    3,
+						RetryPolicy: policy,
+						Priority:    models.PriorityNormal,
+						QuietHours:  quiet,
+					}, nil).
+					Once()
+				r.
+					On("FallBackNotification", mock.Anything, validID, mock.MatchedBy(func(t time.Time) bool {
+						return t.After(now.Add(23 * time.Hour))
+					})).
+					Return(true, nil).
+					Once()
+			},
+		},
 		{
 			name:   "undelivered with attempts >= max => failed",
 			idStr:  validID.String(),
@@ -69,16 +159,104 @@ func TestTwilioCallbackService_ProcessCallback(t *testing.T) {
 				r.
 					On("GetNotificationByID", mock.Anything, validID).
 					Return(&models.Notification{
-						ID:       validID,
-						Attempts: 3,
+						ID:          validID,
+						Attempts:    3,
+						RetryPolicy: policy,
 					}, nil).
 					Once()
+				r.
+					On("FallBackNotification", mock.Anything, validID, mock.AnythingOfType("time.Time")).
+					Return(false, nil).
+					Once()
 				r.
 					On("ChangeNotificationStatus", mock.Anything, validID, models.StatusFailed).
 					Return(nil).
 					Once()
 			},
 		},
+		{
+			name:   "failed honours notification policy",
+			idStr:  validID.String(),
+			status: "failed",
+			setupMocks: func(r *MockNotificationRepository) {
+				r.
+					On("GetNotificationByID", mock.Anything, validID).
+					Return(&models.Notification{
+						ID:          validID,
+						Attempts:    3,
+						RetryPolicy: models.RetryPolicy{MaxAttempts: 1},
+					}, nil).
+					Once()
+				r.
+					On("FallBackNotification", mock.Anything, validID, mock.AnythingOfType("time.Time")).
+					Return(false, nil).
+					Once()
+				r.
+					On("ChangeNotificationStatus", mock.Anything, validID, models.StatusFailed).
+					Return(nil).
+					Once()
+			},
+		},
+		{
+			name:   "undelivered with attempts >= max => falls back to next endpoint",
+			idStr:  validID.String(),
+			status: "undelivered",
+			setupMocks: func(r *MockNotificationRepository) {
+				r.
+					On("GetNotificationByID", mock.Anything, validID).
+					Return(&models.Notification{
+						ID:          validID,
+						Attempts:    3,
+						RetryPolicy: policy,
+						Fallbacks:   []*models.Fallback{{Channel: models.ChannelEmail, Address: "ivan@example.com"}},
+					}, nil).
+					Once()
+				r.
+					On("FallBackNotification", mock.Anything, validID, mock.AnythingOfType("time.Time")).
+					Return(true, nil).
+					Once()
+			},
+		},
+		{
+			name:   "repo FallBackNotification fails",
+			idStr:  validID.String(),
+			status: "failed",
+			setupMocks: func(r *MockNotificationRepository) {
+				r.
+					On("GetNotificationByID", mock.Anything, validID).
+					Return(&models.Notification{
+						ID:          validID,
+						Attempts:    3,
+						RetryPolicy: policy,
+					}, nil).
+					Once()
+				r.
+					On("FallBackNotification", mock.Anything, validID, mock.AnythingOfType("time.Time")).
+					Return(false, assert.AnError).
+					Once()
+			},
+			expectedError: true,
+		},
+		{
+			name:   "repo RescheduleNotification fails",
+			idStr:  validID.String(),
+			status: "undelivered",
+			setupMocks: func(r *MockNotificationRepository) {
+				r.
+					On("GetNotificationByID", mock.Anything, validID).
+					Return(&models.Notification{
+						ID:          validID,
+						Attempts:    1,
+						RetryPolicy: policy,
+					}, nil).
+					Once()
+				r.
+					On("RescheduleNotification", mock.Anything, validID, mock.Anything).
+					Return(assert.AnError).
+					Once()
+			},
+			expectedError: true,
+		},
 		{
 			name:          "invalid UUID",
 			idStr:         "invalid-uuid",
@@ -118,7 +296,7 @@ func TestTwilioCallbackService_ProcessCallback(t *testing.T) {
 			if tt.setupMocks != nil {
 				tt.setupMocks(repo)
 			}
-			svc := service.NewTwilioCallbackService(repo, 3)
+			svc := service.NewTwilioCallbackService(repo)
 
 			err := svc.ProcessCallback(context.Background(), tt.idStr, tt.status)
 
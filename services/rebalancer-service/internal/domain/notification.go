@@ -15,9 +15,16 @@ type NotificationRepository interface {
 
 // SendNotificationTask describes the payload sent to worker services
 // for delivering a single notification via SMS or other channels.
+// SenderID is the branded sender ID requested by the campaign template, if any.
+// Channel is the channel of the recipient's current endpoint, "sms" or "email".
+// CampaignID identifies the campaign of the notification, so that the worker can skip cancelled ones.
 type SendNotificationTask struct {
-	ID             uuid.UUID `json:"id"`
-	Text           string    `json:"text"`
-	RecipientPhone string    `json:"recipientPhone"`
-	Attempts       int       `json:"attempts"`
+	ID             uuid.UUID           `json:"id"`
+	CampaignID     uuid.UUID           `json:"campaignId"`
+	Text           string              `json:"text"`
+	Channel        string              `json:"channel,omitempty"`
+	RecipientPhone string              `json:"recipientPhone"`
+	Attempts       int                 `json:"attempts"`
+	RetryPolicy    *models.RetryPolicy `json:"retryPolicy,omitempty"`
+	SenderID       string              `json:"senderId,omitempty"`
 }
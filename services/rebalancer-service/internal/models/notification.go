@@ -7,13 +7,19 @@ import (
 )
 
 // Notification represents a single notification record in the system.
+// RecipientPhone holds the address of the recipient on the channel, "sms" or "email".
+// CampaignID is the zero UUID for notifications of campaigns sent before campaigns had IDs.
 type Notification struct {
 	ID             uuid.UUID
 	UserID         int
+	CampaignID     uuid.UUID
 	Text           string
+	Channel        string
 	RecipientPhone string
 	Status         string
 	Attempts       int
+	RetryPolicy    RetryPolicy
+	SenderID       string
 	NextRunAt      time.Time
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
@@ -0,0 +1,25 @@
+package models
+
+// BackoffKind names the curve used to grow the delay between delivery attempts.
+type BackoffKind string
+
+const (
+	// BackoffExponential doubles the delay after every attempt: base * 2^(attempt-1).
+	BackoffExponential BackoffKind = "exponential"
+	// BackoffLinear grows the delay linearly: base * attempt.
+	BackoffLinear BackoffKind = "linear"
+	// BackoffConstant waits the base delay between every attempt.
+	BackoffConstant BackoffKind = "constant"
+)
+
+// RetryPolicy describes how a notification is retried after a failed delivery attempt.
+// The rebalancer uses StaleAfterMs to detect stuck in-flight notifications and forwards
+// the whole policy to the sender workers.
+type RetryPolicy struct {
+	MaxAttempts  int         `json:"maxAttempts"`
+	Backoff      BackoffKind `json:"backoff"`
+	BaseDelayMs  int         `json:"baseDelayMs"`
+	MaxDelayMs   int         `json:"maxDelayMs"`
+	Jitter       float64     `json:"jitter"`
+	StaleAfterMs int         `json:"staleAfterMs"`
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/rebalancer-service/internal/domain"
 	"github.com/SteeperMold/Emergency-Notification-System/services/rebalancer-service/internal/models"
+	"github.com/google/uuid"
 )
 
 // NotificationRepository provides methods to fetch and update pending notifications
@@ -20,27 +21,38 @@ func NewNotificationRepository(db domain.DBConn) *NotificationRepository {
 	}
 }
 
-// FetchAndUpdatePending atomically retrieves up to `limit` pending or stale in-flight
-// notifications, marks them as in-flight with an incremented attempt count, and returns
-// them as a slice. Uses SELECT ... FOR UPDATE SKIP LOCKED to avoid contention across
+// FetchAndUpdatePending atomically retrieves up to `limit` pending or stale in-flight and sending
+// notifications (in-flight for longer than their retry policy's stale threshold), marks them as in-flight with an incremented attempt count, and returns
+// them as a slice. Notifications of cancelled campaigns, such as failed attempts rescheduled after
+// the cancellation, are marked as cancelled instead and not returned. Uses SELECT ... FOR UPDATE SKIP LOCKED to avoid contention across
 // multiple rebalancer instances.
 func (nr *NotificationRepository) FetchAndUpdatePending(ctx context.Context, limit int) ([]*models.Notification, error) {
 	const q = `
 		WITH to_dequeue AS (
-			SELECT id
-			FROM notifications
-			WHERE (status = 'pending' AND next_run_at <= now())
-			   OR (status = 'in_flight' AND updated_at <= now() - interval '5 minute')
-			ORDER BY next_run_at
-			LIMIT $1 FOR UPDATE SKIP LOCKED
+			SELECT n.id,
+			       EXISTS (SELECT 1 FROM cancelled_campaigns c WHERE c.campaign_id = n.campaign_id) AS cancelled
+			FROM notifications n
+			WHERE (n.status = 'pending' AND n.next_run_at <= now())
+			   OR (n.status IN ('in_flight', 'sending') AND n.updated_at <= now() - n.stale_after_ms * interval '1 millisecond')
+			ORDER BY n.next_run_at
+			LIMIT $1 FOR UPDATE OF n SKIP LOCKED
+		),
+		dequeued AS (
+			UPDATE notifications n
+			SET status     = CASE WHEN d.cancelled THEN 'cancelled'::notification_status ELSE 'in_flight' END,
+				attempts   = CASE WHEN d.cancelled THEN n.attempts ELSE n.attempts + 1 END,
+				updated_at = now()
+			FROM to_dequeue d
+			WHERE n.id = d.id
+			RETURNING n.id, n.user_id, n.text, n.recipient_phone, n.status, n.attempts, n.next_run_at, n.created_at, n.updated_at,
+			          n.max_attempts, n.backoff, n.base_delay_ms, n.max_delay_ms, n.jitter, n.stale_after_ms, n.sender_id,
+			          n.channel, n.campaign_id
 		)
-		UPDATE notifications n
-		SET status     = 'in_flight',
-			attempts   = attempts + 1,
-			updated_at = now()
-		FROM to_dequeue d
-		WHERE n.id = d.id
-		RETURNING n.id, n.user_id, n.text, n.recipient_phone, n.status, n.attempts, n.next_run_at, n.created_at, n.updated_at
+		SELECT id, user_id, text, recipient_phone, status, attempts, next_run_at, created_at, updated_at,
+		       max_attempts, backoff, base_delay_ms, max_delay_ms, jitter, stale_after_ms, sender_id,
+		       channel, campaign_id
+		FROM dequeued
+		WHERE status = 'in_flight'
 	`
 
 	rows, err := nr.db.Query(ctx, q, limit)
@@ -53,11 +65,20 @@ func (nr *NotificationRepository) FetchAndUpdatePending(ctx context.Context, lim
 
 	for rows.Next() {
 		var n models.Notification
+		var campaignID *uuid.UUID
 
-		err := rows.Scan(&n.ID, &n.UserID, &n.Text, &n.RecipientPhone, &n.Status, &n.Attempts, &n.NextRunAt, &n.CreatedAt, &n.UpdatedAt)
+		p := &n.RetryPolicy
+		err := rows.Scan(
+			&n.ID, &n.UserID, &n.Text, &n.RecipientPhone, &n.Status, &n.Attempts, &n.NextRunAt, &n.CreatedAt, &n.UpdatedAt,
+			&p.MaxAttempts, &p.Backoff, &p.BaseDelayMs, &p.MaxDelayMs, &p.Jitter, &p.StaleAfterMs, &n.SenderID,
+			&n.Channel, &campaignID,
+		)
 		if err != nil {
 			return nil, err
 		}
+		if campaignID != nil {
+			n.CampaignID = *campaignID
+		}
 
 		notifications = append(notifications, &n)
 	}
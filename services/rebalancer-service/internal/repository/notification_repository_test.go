@@ -56,6 +56,8 @@ func TestFetchAndUpdatePending(t *testing.T) {
 		}
 		assert.Equal(t, "in_flight", n3.Status)
 		assert.Equal(t, 2, n3.Attempts)
+		assert.Equal(t, 300000, n3.RetryPolicy.StaleAfterMs)
+		assert.Equal(t, models.BackoffExponential, n3.RetryPolicy.Backoff)
 
 		for _, id := range []uuid.UUID{id1, id3} {
 			var status string
@@ -83,3 +85,81 @@ func TestFetchAndUpdatePending(t *testing.T) {
 		assert.Len(t, notifs, 1, "when limit=1 should fetch exactly one")
 	})
 }
+
+func TestFetchAndUpdatePending_StaleSending(t *testing.T) {
+	ctx := context.Background()
+
+	loader := makeFixtures(t, testDB, "./../../../../db/fixtures/notifications.yml")
+	if err := loader.Load(); err != nil {
+		t.Fatalf("failed loading fixtures: %v", err)
+	}
+
+	campaignID := uuid.New()
+	if _, err := testDB.ExecContext(ctx,
+		`UPDATE notifications SET status = 'sending', campaign_id = $1 WHERE id = '33333333-3333-3333-3333-333333333333'`, campaignID,
+	); err != nil {
+		t.Fatalf("failed claiming notification: %v", err)
+	}
+
+	repo := repository.NewNotificationRepository(testPool)
+	notifs, err := repo.FetchAndUpdatePending(ctx, 5)
+	if err != nil {
+		t.Fatalf("FetchAndUpdatePending returned error: %v", err)
+	}
+
+	got := make(map[uuid.UUID]*models.Notification, len(notifs))
+	for _, n := range notifs {
+		got[n.ID] = n
+	}
+	n3, ok := got[uuid.MustParse("33333333-3333-3333-3333-333333333333")]
+	if !assert.True(t, ok, "a stale sending notification should be republished") {
+		t.FailNow()
+	}
+	assert.Equal(t, "in_flight", n3.Status)
+	assert.Equal(t, campaignID, n3.CampaignID)
+	assert.Equal(t, uuid.Nil, got[uuid.MustParse("11111111-1111-1111-1111-111111111111")].CampaignID)
+}
+
+func TestFetchAndUpdatePending_CancelledCampaign(t *testing.T) {
+	ctx := context.Background()
+
+	loader := makeFixtures(t, testDB, "./../../../../db/fixtures/notifications.yml")
+	if err := loader.Load(); err != nil {
+		t.Fatalf("failed loading fixtures: %v", err)
+	}
+
+	campaignID := uuid.New()
+	if _, err := testDB.ExecContext(ctx,
+		`UPDATE notifications SET campaign_id = $1 WHERE id = '11111111-1111-1111-1111-111111111111'`, campaignID,
+	); err != nil {
+		t.Fatalf("failed setting campaign: %v", err)
+	}
+	if _, err := testDB.ExecContext(ctx,
+		`INSERT INTO cancelled_campaigns (campaign_id, user_id) VALUES ($1, 1)`, campaignID,
+	); err != nil {
+		t.Fatalf("failed cancelling campaign: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = testDB.ExecContext(ctx, `TRUNCATE cancelled_campaigns`)
+	})
+
+	repo := repository.NewNotificationRepository(testPool)
+	notifs, err := repo.FetchAndUpdatePending(ctx, 5)
+	if err != nil {
+		t.Fatalf("FetchAndUpdatePending returned error: %v", err)
+	}
+
+	assert.Len(t, notifs, 1, "should skip the notification of the cancelled campaign")
+	assert.Equal(t, uuid.MustParse("33333333-3333-3333-3333-333333333333"), notifs[0].ID)
+
+	var status string
+	var attempts int
+	row := testDB.QueryRowContext(ctx,
+		`SELECT status, attempts FROM notifications WHERE id = '11111111-1111-1111-1111-111111111111'`,
+	)
+	if err := row.Scan(&status, &attempts); err != nil {
+		t.Fatalf("failed scanning db: %v", err)
+	}
+	assert.Equal(t, "cancelled", status)
+	assert.Equal(t, 0, attempts)
+}
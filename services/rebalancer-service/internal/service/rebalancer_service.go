@@ -66,11 +66,16 @@ func (rs *RebalancerService) rebalance(ctx context.Context) {
 
 	msgs := make([]kafka.Message, len(notifications))
 	for i, n := range notifications {
+		policy := n.RetryPolicy
 		taskBytes, err := json.Marshal(&domain.SendNotificationTask{
 			ID:             n.ID,
+			CampaignID:     n.CampaignID,
 			Text:           n.Text,
+			Channel:        n.Channel,
 			RecipientPhone: n.RecipientPhone,
 			Attempts:       n.Attempts,
+			RetryPolicy:    &policy,
+			SenderID:       n.SenderID,
 		})
 		if err != nil {
 			rs.logger.Error("failed to marshal task", zap.Error(err))
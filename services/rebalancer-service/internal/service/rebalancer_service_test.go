@@ -60,12 +60,30 @@ func TestRebalancerService_rebalance(t *testing.T) {
 					Text:           "Hello",
 					RecipientPhone: "1234567890",
 					Attempts:       1,
+					RetryPolicy: models.RetryPolicy{
+						MaxAttempts: 5,
+						Backoff:     models.BackoffExponential,
+						BaseDelayMs: 1000,
+					},
 				},
 			},
 			fetchErr:         nil,
 			writeErr:         nil,
 			expectWriteCalls: true,
 		},
+		{
+			name: "fallback e-mail endpoint",
+			fetchResult: []*models.Notification{
+				{
+					ID:             id,
+					Text:           "Hello",
+					Channel:        "email",
+					RecipientPhone: "ivan@example.com",
+					Attempts:       1,
+				},
+			},
+			expectWriteCalls: true,
+		},
 		{
 			name: "write error",
 			fetchResult: []*models.Notification{
@@ -101,9 +119,13 @@ func TestRebalancerService_rebalance(t *testing.T) {
 				for _, n := range tt.fetchResult {
 					b, _ := json.Marshal(&domain.SendNotificationTask{
 						ID:             n.ID,
+						CampaignID:     n.CampaignID,
 						Text:           n.Text,
+						Channel:        n.Channel,
 						RecipientPhone: n.RecipientPhone,
 						Attempts:       n.Attempts,
+						RetryPolicy:    &n.RetryPolicy,
+						SenderID:       n.SenderID,
 					})
 					expectedMsgs = append(expectedMsgs, kafka.Message{Value: b})
 				}
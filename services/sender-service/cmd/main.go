@@ -22,8 +22,11 @@ func main() {
 	notificationTasksReader := app.KafkaFactory.NewReader(kafkaCfg.Topics["notification.tasks"], kafkaCfg.ConsumerGroup)
 
 	ntr := repository.NewNotificationTasksRepository(app.DB)
-	nts := service.NewNotificationTasksService(ntr, app.SmsSender, app.Config.App.MaxAttempts)
-	ntc := consumers.NewNotificationTasksConsumer(nts, notificationTasksReader, app.Logger, app.Config.App.ContextTimeout)
+	nts := service.NewNotificationTasksService(ntr, app.SmsSender, app.EmailSender, app.Config.App.RetryPolicy)
+	rlr := repository.NewRateLimitRepository(app.DB)
+	ts := service.NewThrottleService(rlr, app.Config.AccountRateLimit(), app.Config.SenderRateLimit())
+	sps := service.NewSenderPoolService(app.Config.Twilio.SenderPool, app.Config.Twilio.AlphanumericCountries)
+	ntc := consumers.NewNotificationTasksConsumer(nts, sps, ts, notificationTasksReader, app.Logger, app.Config.App.ContextTimeout, app.Config.App.SendConcurrency)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	sigs := make(chan os.Signal, 1)
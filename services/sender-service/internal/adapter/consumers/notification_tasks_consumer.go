@@ -4,74 +4,181 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/models"
+	"github.com/segmentio/kafka-go"
 	"go.uber.org/zap"
 )
 
 // NotificationTasksConsumer is responsible for consuming notification tasks from a Kafka topic,
 // sending them using the NotificationTasksService, and committing messages based on success or failure.
+// Each SMS task is assigned a sender identity by the SenderSelector, and sends are paced by the
+// SendThrottler so that provider rate limits are respected.
+// Up to concurrency tasks are processed at once; offsets are committed per partition
+// only once every earlier message of that partition is done.
 type NotificationTasksConsumer struct {
 	service        domain.NotificationTasksService
+	senders        domain.SenderSelector
+	throttler      domain.SendThrottler
 	kafkaReader    domain.KafkaReader
 	logger         *zap.Logger
 	contextTimeout time.Duration
+	concurrency    int
 }
 
 // NewNotificationTasksConsumer creates a new instance of NotificationTasksConsumer.
-func NewNotificationTasksConsumer(s domain.NotificationTasksService, kr domain.KafkaReader, logger *zap.Logger, timeout time.Duration) *NotificationTasksConsumer {
+// A concurrency below one is treated as one.
+func NewNotificationTasksConsumer(s domain.NotificationTasksService, ss domain.SenderSelector, t domain.SendThrottler, kr domain.KafkaReader, logger *zap.Logger, timeout time.Duration, concurrency int) *NotificationTasksConsumer {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
 	return &NotificationTasksConsumer{
 		service:        s,
+		senders:        ss,
+		throttler:      t,
 		kafkaReader:    kr,
 		logger:         logger,
 		contextTimeout: timeout,
+		concurrency:    concurrency,
 	}
 }
 
-// StartConsumer continuously reads messages from the Kafka topic, decodes them into NotificationTasks,
-// processes them using the NotificationTasksService, and commits messages to Kafka accordingly.
-// Retryable errors are skipped to allow future retries; permanent failures are logged and committed.
+type result struct {
+	msg    kafka.Message
+	commit bool
+}
+
+// StartConsumer continuously reads messages from the Kafka topic and hands them to a bounded pool of workers,
+// which decode them into NotificationTasks, select a sender, wait for the throttler and process them using the NotificationTasksService.
+// Completed messages are committed in offset order. Fetching blocks while all workers are busy.
+// On shutdown or fetch error, in-flight sends are awaited and their offsets committed before returning.
 func (ntc *NotificationTasksConsumer) StartConsumer(ctx context.Context) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	tracker := newOffsetTracker()
+	results := make(chan result, ntc.concurrency)
+	sem := make(chan struct{}, ntc.concurrency)
+
+	committerDone := make(chan struct{})
+	go func() {
+		defer close(committerDone)
+		ntc.commitLoop(ctx, cancel, tracker, results)
+	}()
+
+	var wg sync.WaitGroup
+	var fetchErr error
+
+fetch:
 	for {
 		msg, err := ntc.kafkaReader.FetchMessage(ctx)
 		if err != nil {
-			return err
+			fetchErr = err
+			break
 		}
+		tracker.add(msg)
 
-		var nt domain.NotificationTask
-		err = json.Unmarshal(msg.Value, &nt)
-		if err != nil {
-			ntc.logger.Error("invalid notification task", zap.String("raw_task", string(msg.Value)), zap.Error(err))
-			err := ntc.kafkaReader.CommitMessages(ctx, msg)
-			if err != nil {
-				return err
-			}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			fetchErr = ctx.Err()
+			break fetch
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results <- result{msg: msg, commit: ntc.process(ctx, msg)}
+		}()
+	}
+
+	wg.Wait()
+	close(results)
+	<-committerDone
+
+	if cause := context.Cause(ctx); cause != nil && !errors.Is(cause, context.Canceled) {
+		return cause
+	}
+	return fetchErr
+}
+
+// commitLoop commits the highest contiguous completed offset of each partition.
+// A commit failure stops the consumer.
+func (ntc *NotificationTasksConsumer) commitLoop(ctx context.Context, cancel context.CancelCauseFunc, tracker *offsetTracker, results <-chan result) {
+	// commits must outlive shutdown so that the work already done is not redelivered
+	commitCtx := context.WithoutCancel(ctx)
+	failed := false
+
+	for r := range results {
+		if !r.commit || failed {
 			continue
 		}
 
-		msgCtx, cancel := context.WithTimeout(ctx, ntc.contextTimeout)
-		start := time.Now()
+		msg, ok := tracker.complete(r.msg)
+		if !ok {
+			continue
+		}
 
-		ntc.logger.Info("read notification task", zap.String("notification_id", nt.ID.String()))
-		err = ntc.service.SendNotification(msgCtx, &nt)
-		cancel()
+		err := ntc.kafkaReader.CommitMessages(commitCtx, msg)
+		if err != nil {
+			ntc.logger.Error("failed to commit notification tasks", zap.Int64("offset", msg.Offset), zap.Error(err))
+			failed = true
+			cancel(err)
+		}
+	}
+}
 
+// process handles a single message and reports whether its offset may be committed.
+// Invalid payloads and permanent failures are committed. Retryable failures are committed as well,
+// since the rebalancer republishes notifications that stay in-flight; only work interrupted by
+// shutdown is left uncommitted.
+func (ntc *NotificationTasksConsumer) process(ctx context.Context, msg kafka.Message) bool {
+	var nt domain.NotificationTask
+	err := json.Unmarshal(msg.Value, &nt)
+	if err != nil {
+		ntc.logger.Error("invalid notification task", zap.String("raw_task", string(msg.Value)), zap.Error(err))
+		return true
+	}
+
+	// e-mails are neither sent from a sender identity nor subject to the SMS provider limits
+	var from models.SenderIdentity
+	if nt.Channel != models.ChannelEmail {
+		from = ntc.senders.SelectSender(&nt)
+
+		err = ntc.throttler.Wait(ctx, from)
 		if err != nil {
-			var rerr domain.SendError
-			if errors.As(err, &rerr) && rerr.Retryable() {
-				ntc.logger.Info("retryable send failure, will retry", zap.String("notification_id", nt.ID.String()), zap.Error(err))
-				continue
+			if ctx.Err() != nil {
+				return false
 			}
-
-			ntc.logger.Error("permanent send failure, commiting", zap.String("notification_id", nt.ID.String()), zap.Error(err))
+			// the shared bucket is unavailable; sending unthrottled is preferred to stalling the queue
+			ntc.logger.Warn("failed to acquire send token, sending unthrottled", zap.Error(err))
 		}
+	}
 
-		duration := time.Since(start)
-		ntc.logger.Info("finished notification task", zap.String("notification_id", nt.ID.String()), zap.Duration("duration", duration))
+	msgCtx, cancel := context.WithTimeout(ctx, ntc.contextTimeout)
+	start := time.Now()
 
-		if err := ntc.kafkaReader.CommitMessages(ctx, msg); err != nil {
-			return err
+	ntc.logger.Info("read notification task", zap.String("notification_id", nt.ID.String()), zap.String("campaign_id", nt.CampaignID.String()))
+	err = ntc.service.SendNotification(msgCtx, &nt, from)
+	cancel()
+
+	if err != nil {
+		var rerr domain.SendError
+		if errors.As(err, &rerr) && rerr.Retryable() {
+			ntc.logger.Info("retryable send failure, will retry", zap.String("notification_id", nt.ID.String()), zap.Error(err))
+			return true
 		}
+
+		ntc.logger.Error("permanent send failure, commiting", zap.String("notification_id", nt.ID.String()), zap.Error(err))
 	}
+
+	duration := time.Since(start)
+	ntc.logger.Info("finished notification task", zap.String("notification_id", nt.ID.String()), zap.Duration("duration", duration))
+
+	return true
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/adapter/consumers"
 	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/models"
 	"github.com/google/uuid"
 	"github.com/segmentio/kafka-go"
 	"github.com/stretchr/testify/assert"
@@ -20,8 +21,25 @@ type MockNotificationService struct {
 	mock.Mock
 }
 
-func (m *MockNotificationService) SendNotification(ctx context.Context, task *domain.NotificationTask) error {
-	return m.Called(ctx, task).Error(0)
+func (m *MockNotificationService) SendNotification(ctx context.Context, task *domain.NotificationTask, from models.SenderIdentity) error {
+	return m.Called(ctx, task, from).Error(0)
+}
+
+// fixedSender assigns every task the same sender identity
+type fixedSender struct{}
+
+func (fixedSender) SelectSender(*domain.NotificationTask) models.SenderIdentity {
+	return testSender
+}
+
+var testSender = models.SenderIdentity{Kind: models.SenderNumber, Value: "+199999"}
+
+type MockSendThrottler struct {
+	mock.Mock
+}
+
+func (m *MockSendThrottler) Wait(ctx context.Context, from models.SenderIdentity) error {
+	return m.Called(ctx, from).Error(0)
 }
 
 type MockKafkaReader struct {
@@ -116,9 +134,14 @@ func TestStartConsumer(t *testing.T) {
 						ID:             id,
 						RecipientPhone: "+123",
 						Text:           "hello",
-					}).
+					}, testSender).
 					Return(retryableErr{"temp fail"}).
 					Once()
+				// the rebalancer republishes the notification, so the offset is not held back
+				r.
+					On("CommitMessages", mock.Anything, mock.Anything).
+					Return(nil).
+					Once()
 				r.
 					On("FetchMessage", mock.Anything).
 					Return(kafka.Message{}, errors.New("stop")).
@@ -139,7 +162,7 @@ func TestStartConsumer(t *testing.T) {
 						ID:             id,
 						RecipientPhone: "+123",
 						Text:           "hello",
-					}).
+					}, testSender).
 					Return(permanentErr{"perm fail"}).
 					Once()
 				r.
@@ -166,7 +189,7 @@ func TestStartConsumer(t *testing.T) {
 						ID:             id,
 						RecipientPhone: "+123",
 						Text:           "hello",
-					}).
+					}, testSender).
 					Return(nil).
 					Once()
 				r.
@@ -185,12 +208,14 @@ func TestStartConsumer(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			svc := new(MockNotificationService)
+			thr := new(MockSendThrottler)
 			rdr := new(MockKafkaReader)
 			logger := zap.NewNop()
 
+			thr.On("Wait", mock.Anything, testSender).Return(nil).Maybe()
 			tc.setup(svc, rdr)
 
-			consumer := consumers.NewNotificationTasksConsumer(svc, rdr, logger, 100*time.Millisecond)
+			consumer := consumers.NewNotificationTasksConsumer(svc, fixedSender{}, thr, rdr, logger, 100*time.Millisecond, 4)
 			err := consumer.StartConsumer(ctx)
 
 			if tc.expectErr {
@@ -204,3 +229,187 @@ func TestStartConsumer(t *testing.T) {
 		})
 	}
 }
+
+func TestStartConsumer_Throttling(t *testing.T) {
+	id := uuid.New()
+	task := &domain.NotificationTask{ID: id, RecipientPhone: "+123", Text: "hello"}
+
+	t.Run("throttler failure does not block sending", func(t *testing.T) {
+		svc := new(MockNotificationService)
+		thr := new(MockSendThrottler)
+		rdr := new(MockKafkaReader)
+
+		rdr.
+			On("FetchMessage", mock.Anything).
+			Return(buildMsg(id), nil).
+			Once()
+		thr.
+			On("Wait", mock.Anything, testSender).
+			Return(errors.New("db down")).
+			Once()
+		svc.
+			On("SendNotification", mock.Anything, task, testSender).
+			Return(nil).
+			Once()
+		rdr.
+			On("CommitMessages", mock.Anything, mock.Anything).
+			Return(nil).
+			Once()
+		rdr.
+			On("FetchMessage", mock.Anything).
+			Return(kafka.Message{}, context.Canceled).
+			Once()
+
+		consumer := consumers.NewNotificationTasksConsumer(svc, fixedSender{}, thr, rdr, zap.NewNop(), 100*time.Millisecond, 4)
+		err := consumer.StartConsumer(context.Background())
+
+		assert.ErrorIs(t, err, context.Canceled)
+		svc.AssertExpectations(t)
+		thr.AssertExpectations(t)
+		rdr.AssertExpectations(t)
+	})
+
+	t.Run("shutdown while waiting for a token leaves the message uncommitted", func(t *testing.T) {
+		svc := new(MockNotificationService)
+		thr := new(MockSendThrottler)
+		rdr := new(MockKafkaReader)
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		rdr.
+			On("FetchMessage", mock.Anything).
+			Return(buildMsg(id), nil).
+			Once()
+		thr.
+			On("Wait", mock.Anything, testSender).
+			Run(func(mock.Arguments) { cancel() }).
+			Return(context.Canceled).
+			Once()
+		rdr.
+			On("FetchMessage", mock.Anything).
+			Return(kafka.Message{}, context.Canceled).
+			Maybe()
+
+		consumer := consumers.NewNotificationTasksConsumer(svc, fixedSender{}, thr, rdr, zap.NewNop(), 100*time.Millisecond, 4)
+		err := consumer.StartConsumer(ctx)
+
+		assert.ErrorIs(t, err, context.Canceled)
+		svc.AssertNotCalled(t, "SendNotification", mock.Anything, mock.Anything, mock.Anything)
+		rdr.AssertNotCalled(t, "CommitMessages", mock.Anything, mock.Anything)
+		thr.AssertExpectations(t)
+	})
+
+	t.Run("e-mails are not throttled", func(t *testing.T) {
+		svc := new(MockNotificationService)
+		thr := new(MockSendThrottler)
+		rdr := new(MockKafkaReader)
+
+		emailTask := &domain.NotificationTask{ID: id, Channel: models.ChannelEmail, RecipientPhone: "ivan@example.com", Text: "hello"}
+		data, _ := json.Marshal(emailTask)
+
+		rdr.
+			On("FetchMessage", mock.Anything).
+			Return(kafka.Message{Value: data}, nil).
+			Once()
+		svc.
+			On("SendNotification", mock.Anything, emailTask, models.SenderIdentity{}).
+			Return(nil).
+			Once()
+		rdr.
+			On("CommitMessages", mock.Anything, mock.Anything).
+			Return(nil).
+			Once()
+		rdr.
+			On("FetchMessage", mock.Anything).
+			Return(kafka.Message{}, context.Canceled).
+			Once()
+
+		consumer := consumers.NewNotificationTasksConsumer(svc, fixedSender{}, thr, rdr, zap.NewNop(), 100*time.Millisecond, 4)
+		err := consumer.StartConsumer(context.Background())
+
+		assert.ErrorIs(t, err, context.Canceled)
+		svc.AssertExpectations(t)
+		thr.AssertNotCalled(t, "Wait", mock.Anything, mock.Anything)
+		rdr.AssertExpectations(t)
+	})
+}
+
+func TestStartConsumer_CommitsContiguousOffsets(t *testing.T) {
+	svc := new(MockNotificationService)
+	thr := new(MockSendThrottler)
+	rdr := new(MockKafkaReader)
+
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	for i, id := range ids {
+		msg := buildMsg(id)
+		msg.Partition = 0
+		msg.Offset = int64(i)
+		rdr.
+			On("FetchMessage", mock.Anything).
+			Return(msg, nil).
+			Once()
+	}
+	rdr.
+		On("FetchMessage", mock.Anything).
+		Return(kafka.Message{}, context.Canceled).
+		Once()
+
+	thr.On("Wait", mock.Anything, testSender).Return(nil)
+
+	// the first task finishes last
+	svc.
+		On("SendNotification", mock.Anything, mock.MatchedBy(func(task *domain.NotificationTask) bool { return task.ID == ids[0] }), testSender).
+		Run(func(mock.Arguments) { time.Sleep(50 * time.Millisecond) }).
+		Return(nil).
+		Once()
+	svc.
+		On("SendNotification", mock.Anything, mock.MatchedBy(func(task *domain.NotificationTask) bool { return task.ID != ids[0] }), testSender).
+		Return(nil).
+		Twice()
+
+	var committed []int64
+	rdr.
+		On("CommitMessages", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			for _, m := range args.Get(1).([]kafka.Message) {
+				committed = append(committed, m.Offset)
+			}
+		}).
+		Return(nil)
+
+	consumer := consumers.NewNotificationTasksConsumer(svc, fixedSender{}, thr, rdr, zap.NewNop(), time.Second, 3)
+	err := consumer.StartConsumer(context.Background())
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []int64{2}, committed)
+	svc.AssertExpectations(t)
+	rdr.AssertExpectations(t)
+}
+
+func TestStartConsumer_CommitErrorStopsConsumer(t *testing.T) {
+	svc := new(MockNotificationService)
+	thr := new(MockSendThrottler)
+	rdr := new(MockKafkaReader)
+
+	rdr.
+		On("FetchMessage", mock.Anything).
+		Return(buildMsg(uuid.New()), nil).
+		Once()
+	rdr.
+		On("FetchMessage", mock.Anything).
+		Run(func(args mock.Arguments) { <-args.Get(0).(context.Context).Done() }).
+		Return(kafka.Message{}, context.Canceled).
+		Once()
+	thr.On("Wait", mock.Anything, testSender).Return(nil)
+	svc.On("SendNotification", mock.Anything, mock.Anything, testSender).Return(nil).Once()
+	rdr.
+		On("CommitMessages", mock.Anything, mock.Anything).
+		Return(errors.New("commit failed")).
+		Once()
+
+	consumer := consumers.NewNotificationTasksConsumer(svc, fixedSender{}, thr, rdr, zap.NewNop(), time.Second, 2)
+	err := consumer.StartConsumer(context.Background())
+
+	assert.EqualError(t, err, "commit failed")
+	rdr.AssertExpectations(t)
+}
@@ -0,0 +1,66 @@
+package consumers
+
+import (
+	"sync"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// offsetTracker remembers which fetched messages are still being processed so that only
+// contiguous completed offsets are committed, even when messages finish out of order.
+type offsetTracker struct {
+	mu         sync.Mutex
+	partitions map[int]*partitionOffsets
+}
+
+type partitionOffsets struct {
+	// pending holds offsets in fetch order, which Kafka guarantees to be increasing within a partition.
+	pending []int64
+	done    map[int64]kafka.Message
+}
+
+func newOffsetTracker() *offsetTracker {
+	return &offsetTracker{
+		partitions: make(map[int]*partitionOffsets),
+	}
+}
+
+// add registers a fetched message as in progress.
+func (ot *offsetTracker) add(msg kafka.Message) {
+	ot.mu.Lock()
+	defer ot.mu.Unlock()
+
+	p, ok := ot.partitions[msg.Partition]
+	if !ok {
+		p = &partitionOffsets{done: make(map[int64]kafka.Message)}
+		ot.partitions[msg.Partition] = p
+	}
+	p.pending = append(p.pending, msg.Offset)
+}
+
+// complete marks a message as processed. It returns the message with the highest offset
+// that can now be committed, and false if earlier messages of the partition are still in progress.
+func (ot *offsetTracker) complete(msg kafka.Message) (kafka.Message, bool) {
+	ot.mu.Lock()
+	defer ot.mu.Unlock()
+
+	p, ok := ot.partitions[msg.Partition]
+	if !ok {
+		return kafka.Message{}, false
+	}
+	p.done[msg.Offset] = msg
+
+	var commit kafka.Message
+	committable := false
+	for len(p.pending) > 0 {
+		m, ok := p.done[p.pending[0]]
+		if !ok {
+			break
+		}
+		delete(p.done, p.pending[0])
+		p.pending = p.pending[1:]
+		commit, committable = m, true
+	}
+
+	return commit, committable
+}
@@ -13,6 +13,7 @@ type Application struct {
 	Logger       *zap.Logger
 	KafkaFactory *KafkaFactory
 	SmsSender    domain.SmsSender
+	EmailSender  domain.EmailSender
 }
 
 // NewApp initializes and returns a new Application instance.
@@ -24,6 +25,7 @@ func NewApp() *Application {
 	app.Logger = NewLogger(app.Config.App.AppEnv)
 	app.KafkaFactory = NewKafkaFactory(app.Config.Kafka)
 	app.SmsSender = NewSmsSender(app.Config.App.AppEnv, app.Config.Twilio)
+	app.EmailSender = NewEmailSender(app.Config.App.AppEnv, app.Config.SMTP)
 
 	return app
 }
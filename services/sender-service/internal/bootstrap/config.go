@@ -7,23 +7,27 @@ import (
 	"strings"
 	"time"
 
+	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/models"
 	"github.com/joho/godotenv"
 )
 
 // Config holds all application and database configuration.
 type Config struct {
-	App    *AppConfig
-	DB     *DBConfig
-	Kafka  *KafkaConfig
-	Twilio *TwilioConfig
+	App      *AppConfig
+	DB       *DBConfig
+	Kafka    *KafkaConfig
+	Twilio   *TwilioConfig
+	SMTP     *SMTPConfig
+	Throttle *ThrottleConfig
 }
 
 // AppConfig holds general application settings.
 type AppConfig struct {
-	AppEnv         string
-	Port           string
-	MaxAttempts    int
-	ContextTimeout time.Duration
+	AppEnv          string
+	Port            string
+	RetryPolicy     models.RetryPolicy
+	ContextTimeout  time.Duration
+	SendConcurrency int
 }
 
 // DBConfig holds PostgreSQL database connection settings.
@@ -43,14 +47,47 @@ type KafkaConfig struct {
 	ConsumerGroup string
 }
 
-// TwilioConfig holds Twilio SMS API credentials and status callback settings.
+// TwilioConfig holds Twilio SMS API credentials, sender identities and status callback settings.
+// SenderPool falls back to FromNumber when no pool is configured.
 type TwilioConfig struct {
 	AccountSID             string
 	AuthToken              string
 	FromNumber             string
+	SenderPool             []models.SenderIdentity
+	AlphanumericCountries  []string
 	StatusCallbackEndpoint string
 }
 
+// SMTPConfig holds the SMTP relay settings used to deliver notifications by e-mail.
+// Authentication is skipped when Username is empty.
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	Subject  string
+}
+
+// ThrottleConfig holds provider rate limits shared by all sender-service replicas.
+// A non-positive rate disables the corresponding limit.
+type ThrottleConfig struct {
+	AccountRate  float64
+	AccountBurst float64
+	SenderRate   float64
+	SenderBurst  float64
+}
+
+// AccountRateLimit returns the token bucket shared by all sends of the configured Twilio account.
+func (c *Config) AccountRateLimit() models.RateLimit {
+	return models.RateLimit{Key: "account:" + c.Twilio.AccountSID, Rate: c.Throttle.AccountRate, Burst: c.Throttle.AccountBurst}
+}
+
+// SenderRateLimit returns the per-sender token bucket; its key is completed with the sender identity.
+func (c *Config) SenderRateLimit() models.RateLimit {
+	return models.RateLimit{Key: "sender:", Rate: c.Throttle.SenderRate, Burst: c.Throttle.SenderBurst}
+}
+
 // NewConfig loads configuration from environment variables with defaults.
 func NewConfig() *Config {
 	err := godotenv.Load()
@@ -58,12 +95,15 @@ func NewConfig() *Config {
 		log.Printf("failed to load .env file, using defaults: %v\n", err)
 	}
 
+	fromNumber := getEnv("TWILIO_FROM_NUMBER", "twilio-from-number")
+
 	return &Config{
 		App: &AppConfig{
-			AppEnv:         getEnv("APP_ENV", "development"),
-			Port:           getEnv("PORT", "8080"),
-			MaxAttempts:    getEnvAsInt("MAX_NOTIFICATION_ATTEMPTS", 3),
-			ContextTimeout: getEnvAsDuration("CONTEXT_TIMEOUT_MS", 2000) * time.Millisecond,
+			AppEnv:          getEnv("APP_ENV", "development"),
+			Port:            getEnv("PORT", "8080"),
+			RetryPolicy:     getEnvAsRetryPolicy(),
+			ContextTimeout:  getEnvAsDuration("CONTEXT_TIMEOUT_MS", 2000) * time.Millisecond,
+			SendConcurrency: getEnvAsInt("SEND_CONCURRENCY", 64),
 		},
 		DB: &DBConfig{
 			Host:              getEnv("DB_HOST", "postgres"),
@@ -83,9 +123,25 @@ func NewConfig() *Config {
 		Twilio: &TwilioConfig{
 			AccountSID:             getEnv("TWILIO_ACCOUNT_SID", "account-sid"),
 			AuthToken:              getEnv("TWILIO_AUTH_TOKEN", "twilio-auth-token"),
-			FromNumber:             getEnv("TWILIO_FROM_NUMBER", "twilio-from-number"),
+			FromNumber:             fromNumber,
+			SenderPool:             getEnvAsSenderPool("TWILIO_SENDER_POOL", fromNumber),
+			AlphanumericCountries:  getEnvAsCountryCodes("TWILIO_ALPHANUMERIC_COUNTRIES"),
 			StatusCallbackEndpoint: getEnv("STATUS_CALLBACK_ENDPOINT", "http://notification-service:8081"),
 		},
+		SMTP: &SMTPConfig{
+			Host:     getEnv("SMTP_HOST", "localhost"),
+			Port:     getEnv("SMTP_PORT", "587"),
+			Username: getEnv("SMTP_USERNAME", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+			From:     getEnv("SMTP_FROM", "notifications@localhost"),
+			Subject:  getEnv("EMAIL_SUBJECT", "Emergency notification"),
+		},
+		Throttle: &ThrottleConfig{
+			AccountRate:  getEnvAsFloat("SMS_ACCOUNT_RATE_LIMIT", 0),
+			AccountBurst: getEnvAsFloat("SMS_ACCOUNT_RATE_BURST", 1),
+			SenderRate:   getEnvAsFloat("SMS_SENDER_RATE_LIMIT", 0),
+			SenderBurst:  getEnvAsFloat("SMS_SENDER_RATE_BURST", 1),
+		},
 	}
 }
 
@@ -109,6 +165,17 @@ func getEnvAsInt(name string, defaultVal int) int {
 	return value
 }
 
+func getEnvAsFloat(name string, defaultVal float64) float64 {
+	valueStr := getEnv(name, "")
+
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return defaultVal
+	}
+
+	return value
+}
+
 func getEnvAsDuration(name string, defaultVal time.Duration) time.Duration {
 	value := getEnvAsInt(name, int(defaultVal))
 	return time.Duration(value)
@@ -135,3 +202,50 @@ func getEnvAsSlice(name string, defaultVal []string, sep string) []string {
 
 	return result
 }
+
+// getEnvAsCountryCodes parses a comma-separated list of ISO 3166-1 alpha-2 codes, upper-cased
+// to match the regions of phone numbers whatever case they are configured in.
+func getEnvAsCountryCodes(name string) []string {
+	codes := getEnvAsSlice(name, nil, ",")
+	for i, code := range codes {
+		codes[i] = strings.ToUpper(code)
+	}
+
+	return codes
+}
+
+// getEnvAsSenderPool parses the sender pool specification (see models.ParseSenderPool).
+// Without a pool, all traffic is sent from fromNumber. An invalid specification is fatal,
+// since silently sending from the wrong identity is worse than not starting.
+func getEnvAsSenderPool(name string, fromNumber string) []models.SenderIdentity {
+	pool, err := models.ParseSenderPool(getEnv(name, ""))
+	if err != nil {
+		log.Fatalf("invalid %s: %v", name, err)
+	}
+
+	if len(pool) == 0 {
+		return []models.SenderIdentity{{Kind: models.SenderNumber, Value: fromNumber}}
+	}
+
+	return pool
+}
+
+// getEnvAsRetryPolicy reads the default retry policy from MAX_NOTIFICATION_ATTEMPTS and the RETRY_*
+// variables. An invalid policy is fatal.
+func getEnvAsRetryPolicy() models.RetryPolicy {
+	policy := models.RetryPolicy{
+		MaxAttempts:  getEnvAsInt("MAX_NOTIFICATION_ATTEMPTS", 5),
+		Backoff:      models.BackoffKind(getEnv("RETRY_BACKOFF", string(models.BackoffExponential))),
+		BaseDelayMs:  getEnvAsInt("RETRY_BASE_DELAY_MS", 1000),
+		MaxDelayMs:   getEnvAsInt("RETRY_MAX_DELAY_MS", 300_000),
+		Jitter:       getEnvAsFloat("RETRY_JITTER", 0.2),
+		StaleAfterMs: getEnvAsInt("RETRY_STALE_AFTER_MS", 300_000),
+	}
+
+	err := policy.Validate()
+	if err != nil {
+		log.Fatalf("invalid default retry policy: %v", err)
+	}
+
+	return policy
+}
@@ -0,0 +1,26 @@
+package bootstrap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEnvAsCountryCodes(t *testing.T) {
+	tests := map[string]struct {
+		value    string
+		expected []string
+	}{
+		"lower case":          {value: "ru,de", expected: []string{"RU", "DE"}},
+		"mixed case and gaps": {value: " Gb , ,fr ", expected: []string{"GB", "FR"}},
+		"empty":               {value: "", expected: nil},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("TWILIO_ALPHANUMERIC_COUNTRIES", tc.value)
+
+			assert.Equal(t, tc.expected, getEnvAsCountryCodes("TWILIO_ALPHANUMERIC_COUNTRIES"))
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package bootstrap
+
+import (
+	"log"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/email"
+)
+
+// NewEmailSender initializes and returns a domain.EmailSender implementation
+// based on the provided application environment.
+//
+// In "production", it returns an SMTP sender relaying through the configured server.
+// In "development" and "test", it returns a file-based sender that saves e-mails locally.
+func NewEmailSender(appEnv string, smtpCfg *SMTPConfig) domain.EmailSender {
+	var emailSender domain.EmailSender
+	var err error
+
+	switch appEnv {
+	case "production":
+		emailSender = email.NewSMTPSender(smtpCfg.Host, smtpCfg.Port, smtpCfg.Username, smtpCfg.Password, smtpCfg.From, smtpCfg.Subject)
+	case "development":
+		emailSender, err = email.NewDevEmailSender("/tmp/email-dev", 0.05)
+	case "test":
+		emailSender, err = email.NewDevEmailSender("/tmp/email-test", 0.05)
+	}
+
+	if err != nil {
+		log.Fatalf("failed to create email sender: %v", err)
+	}
+
+	return emailSender
+}
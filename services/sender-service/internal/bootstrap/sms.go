@@ -20,7 +20,7 @@ func NewSmsSender(appEnv string, twilioCfg *TwilioConfig) domain.SmsSender {
 
 	switch appEnv {
 	case "production":
-		smsSender = sms.NewSmsSender(twilioCfg.AccountSID, twilioCfg.AuthToken, twilioCfg.FromNumber, twilioCfg.StatusCallbackEndpoint)
+		smsSender = sms.NewSmsSender(twilioCfg.AccountSID, twilioCfg.AuthToken, twilioCfg.StatusCallbackEndpoint)
 	case "development":
 		smsSender, err = sms.NewDevSmsSender("/tmp/sms-dev", twilioCfg.StatusCallbackEndpoint, 0.05, 0.2, 3*time.Second)
 	case "test":
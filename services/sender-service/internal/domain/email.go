@@ -0,0 +1,7 @@
+package domain
+
+// EmailSender defines an interface for delivering notifications by e-mail.
+// Errors implement SendError when the sender can tell whether they are retryable.
+type EmailSender interface {
+	SendEmail(to, body, notificationID string) error
+}
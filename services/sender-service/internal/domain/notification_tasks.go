@@ -16,20 +16,32 @@ var (
 
 // NotificationTasksService defines the interface for processing and sending notification tasks.
 type NotificationTasksService interface {
-	SendNotification(ctx context.Context, notification *NotificationTask) error
+	SendNotification(ctx context.Context, notification *NotificationTask, from models.SenderIdentity) error
 }
 
 // NotificationTasksRepository defines the interface for interacting with the notifications data store.
+// Claim reports false when the notification is no longer waiting to be sent, e.g. because its campaign was cancelled.
 type NotificationTasksRepository interface {
 	GetNotificationByID(ctx context.Context, id uuid.UUID) (*models.Notification, error)
+	Claim(ctx context.Context, id uuid.UUID) (bool, error)
 	Reschedule(ctx context.Context, id uuid.UUID, nextRunAt time.Time) (*models.Notification, error)
 	MarkFailed(ctx context.Context, id uuid.UUID) (*models.Notification, error)
+	MarkSent(ctx context.Context, id uuid.UUID) (*models.Notification, error)
+	FallBack(ctx context.Context, id uuid.UUID, nextRunAt time.Time) (bool, error)
 }
 
 // NotificationTask represents a task to send a single notification to a recipient.
+// RetryPolicy is nil for tasks published before retry policies were introduced.
+// SenderID is the branded sender ID requested by the campaign template, if any.
+// An empty Channel means SMS; for e-mails RecipientPhone holds the e-mail address.
+// CampaignID is the zero UUID for tasks of campaigns sent before campaigns had IDs.
 type NotificationTask struct {
-	ID             uuid.UUID `json:"id"`
-	Text           string    `json:"text"`
-	RecipientPhone string    `json:"recipientPhone"`
-	Attempts       int       `json:"attempts"`
+	ID             uuid.UUID           `json:"id"`
+	CampaignID     uuid.UUID           `json:"campaignId"`
+	Text           string              `json:"text"`
+	Channel        models.Channel      `json:"channel,omitempty"`
+	RecipientPhone string              `json:"recipientPhone"`
+	Attempts       int                 `json:"attempts"`
+	RetryPolicy    *models.RetryPolicy `json:"retryPolicy,omitempty"`
+	SenderID       string              `json:"senderId,omitempty"`
 }
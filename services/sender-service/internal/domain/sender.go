@@ -0,0 +1,9 @@
+package domain
+
+import "github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/models"
+
+// SenderSelector picks the identity a notification task is sent from.
+// Implementations must return the same identity for the same recipient as long as the pool is unchanged.
+type SenderSelector interface {
+	SelectSender(task *NotificationTask) models.SenderIdentity
+}
@@ -1,10 +1,13 @@
 package domain
 
-import openapi "github.com/twilio/twilio-go/rest/api/v2010"
+import (
+	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/models"
+	openapi "github.com/twilio/twilio-go/rest/api/v2010"
+)
 
-// SmsSender defines an interface for sending SMS messages.
+// SmsSender defines an interface for sending SMS messages from a given sender identity.
 type SmsSender interface {
-	SendSMS(to, body, notificationID string) error
+	SendSMS(from models.SenderIdentity, to, body, notificationID string) error
 }
 
 // SendError represents an error returned from an SMS sending operation
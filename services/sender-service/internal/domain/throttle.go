@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/models"
+)
+
+// SendThrottler limits the rate at which messages are handed to the SMS provider.
+// Wait blocks until a message may be sent from the given identity or the context is done.
+type SendThrottler interface {
+	Wait(ctx context.Context, from models.SenderIdentity) error
+}
+
+// RateLimitRepository stores token buckets in a store shared by all sender-service replicas.
+type RateLimitRepository interface {
+	// ReserveToken takes one token from the bucket identified by key, refilling it at rate tokens
+	// per second up to burst, and returns the number of tokens left. A negative result means
+	// the token was borrowed from the future and the caller has to wait until it is refilled.
+	ReserveToken(ctx context.Context, key string, rate, burst float64) (float64, error)
+}
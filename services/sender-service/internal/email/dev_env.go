@@ -0,0 +1,76 @@
+package email
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DevSendError simulates a structured error from the DevEmailSender.
+type DevSendError struct {
+	Message   string
+	retryable bool
+}
+
+// Error returns the error message for DevSendError.
+func (e DevSendError) Error() string {
+	return e.Message
+}
+
+// Retryable indicates whether the DevSendError is considered retryable.
+func (e DevSendError) Retryable() bool {
+	return e.retryable
+}
+
+// DevEmailSender is a mock e-mail sender used for development and test environments.
+// It simulates delivery by writing every e-mail to a file in Dir.
+type DevEmailSender struct {
+	Dir      string
+	FailRate float64 // 0.0–1.0 chance of send failure
+	rng      *rand.Rand
+	rngMu    sync.Mutex // rand.Rand is not safe for concurrent use
+}
+
+// NewDevEmailSender creates a new DevEmailSender.
+func NewDevEmailSender(dir string, failRate float64) (*DevEmailSender, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &DevEmailSender{
+		Dir:      dir,
+		FailRate: failRate,
+		rng:      rand.New(rand.NewSource(time.Now().UnixNano())),
+	}, nil
+}
+
+// SendEmail simulates sending an e-mail by writing it to a file in the configured directory.
+func (d *DevEmailSender) SendEmail(to, body, notificationID string) error {
+	if d.random() < d.FailRate {
+		return DevSendError{
+			Message:   "dev email sender: simulated send failure",
+			retryable: true,
+		}
+	}
+
+	now := time.Now()
+	filename := fmt.Sprintf("%s__%s.eml", now.Format("02.01.2006-15:04:05"), to)
+	msg := buildMessage("dev@localhost", to, "Notification", body, notificationID, now)
+
+	if err := os.WriteFile(filepath.Join(d.Dir, filename), msg, 0o644); err != nil {
+		return DevSendError{
+			Message:   fmt.Sprintf("failed to write email file: %v", err),
+			retryable: false,
+		}
+	}
+
+	return nil
+}
+
+func (d *DevEmailSender) random() float64 {
+	d.rngMu.Lock()
+	defer d.rngMu.Unlock()
+	return d.rng.Float64()
+}
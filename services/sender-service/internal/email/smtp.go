@@ -0,0 +1,103 @@
+package email
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// SMTPSendError represents an error returned by the SMTP e-mail sender.
+// Code is the SMTP reply code, or zero when the server could not be reached.
+type SMTPSendError struct {
+	Code      int
+	Message   string
+	retryable bool
+}
+
+// Error returns the error message for SMTPSendError.
+func (e SMTPSendError) Error() string {
+	return e.Message
+}
+
+// Retryable indicates whether the SMTPSendError is considered retryable.
+func (e SMTPSendError) Retryable() bool {
+	return e.retryable
+}
+
+// SMTPSender delivers notifications as plain-text e-mails through an SMTP relay.
+// Unlike SMS there are no delivery callbacks: an e-mail accepted by the relay counts as sent.
+type SMTPSender struct {
+	addr    string
+	auth    smtp.Auth
+	from    string
+	subject string
+	send    func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewSMTPSender creates an SMTPSender relaying through host:port. PLAIN authentication is
+// used when a username is given. from is the envelope and header sender of every e-mail.
+func NewSMTPSender(host, port, username, password, from, subject string) *SMTPSender {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &SMTPSender{
+		addr:    net.JoinHostPort(host, port),
+		auth:    auth,
+		from:    from,
+		subject: subject,
+		send:    smtp.SendMail,
+	}
+}
+
+// SendEmail sends the notification text to the address. Permanent SMTP failures (5xx replies),
+// such as an unknown mailbox, are not retryable; connection problems and transient replies are.
+func (s *SMTPSender) SendEmail(to, body, notificationID string) error {
+	msg := buildMessage(s.from, to, s.subject, body, notificationID, time.Now())
+
+	err := s.send(s.addr, s.auth, s.from, []string{to}, msg)
+	if err != nil {
+		var tpErr *textproto.Error
+		if errors.As(err, &tpErr) {
+			return SMTPSendError{
+				Code:      tpErr.Code,
+				Message:   tpErr.Error(),
+				retryable: tpErr.Code < 500,
+			}
+		}
+		return SMTPSendError{
+			Message:   err.Error(),
+			retryable: true,
+		}
+	}
+
+	return nil
+}
+
+// buildMessage renders a UTF-8 plain-text message with CRLF line endings. The notification ID
+// is kept in a header so that bounces can be traced back to the notification.
+func buildMessage(from, to, subject, body, notificationID string, date time.Time) []byte {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	fmt.Fprintf(&b, "X-Notification-ID: %s\r\n", notificationID)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+
+	return []byte(b.String())
+}
@@ -0,0 +1,84 @@
+package email
+
+import (
+	"errors"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSMTPSender_SendEmail(t *testing.T) {
+	tests := map[string]struct {
+		sendErr       error
+		expectErr     bool
+		expectRetry   bool
+		expectErrCode int
+	}{
+		"success": {},
+		"mailbox unavailable": {
+			sendErr:       &textproto.Error{Code: 550, Msg: "mailbox unavailable"},
+			expectErr:     true,
+			expectErrCode: 550,
+		},
+		"mailbox busy": {
+			sendErr:       &textproto.Error{Code: 450, Msg: "mailbox busy"},
+			expectErr:     true,
+			expectRetry:   true,
+			expectErrCode: 450,
+		},
+		"connection refused": {
+			sendErr:     errors.New("dial tcp: connection refused"),
+			expectErr:   true,
+			expectRetry: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			sender := NewSMTPSender("smtp.example.com", "587", "user", "secret", "alerts@example.com", "Alert")
+
+			var gotAddr, gotFrom string
+			var gotTo []string
+			var gotMsg []byte
+			sender.send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+				gotAddr, gotFrom, gotTo, gotMsg = addr, from, to, msg
+				return tc.sendErr
+			}
+
+			err := sender.SendEmail("ivan@example.com", "Evacuate now", "notif-1")
+
+			assert.Equal(t, "smtp.example.com:587", gotAddr)
+			assert.Equal(t, "alerts@example.com", gotFrom)
+			assert.Equal(t, []string{"ivan@example.com"}, gotTo)
+			assert.Contains(t, string(gotMsg), "X-Notification-ID: notif-1\r\n")
+
+			if !tc.expectErr {
+				assert.NoError(t, err)
+				return
+			}
+			var sendErr SMTPSendError
+			assert.ErrorAs(t, err, &sendErr)
+			assert.Equal(t, tc.expectRetry, sendErr.Retryable())
+			assert.Equal(t, tc.expectErrCode, sendErr.Code)
+		})
+	}
+}
+
+func TestBuildMessage(t *testing.T) {
+	date := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	msg := string(buildMessage("alerts@example.com", "ivan@example.com", "Тревога", "line 1\nline 2", "notif-1", date))
+
+	headers, body, ok := strings.Cut(msg, "\r\n\r\n")
+	assert.True(t, ok)
+	assert.Contains(t, headers, "From: alerts@example.com\r\n")
+	assert.Contains(t, headers, "To: ivan@example.com\r\n")
+	assert.Contains(t, headers, "Subject: =?utf-8?q?")
+	assert.Contains(t, headers, "Date: Fri, 01 Mar 2024 12:00:00 +0000\r\n")
+	assert.Contains(t, headers, "Content-Type: text/plain; charset=UTF-8\r\n")
+	assert.Equal(t, "line 1\r\nline 2\r\n", body)
+}
@@ -6,8 +6,19 @@ import (
 	"github.com/google/uuid"
 )
 
-// Notification represents a message that is scheduled to be sent to a recipient via SMS.
+// Channel is the way a notification reaches its recipient.
+type Channel string
+
+const (
+	// ChannelSMS delivers the notification as a text message to a phone number.
+	ChannelSMS Channel = "sms"
+	// ChannelEmail delivers the notification as an e-mail.
+	ChannelEmail Channel = "email"
+)
+
+// Notification represents a message that is scheduled to be sent to a recipient via SMS or e-mail.
 // It contains metadata about the user, status, retry attempts, scheduling, and timestamps.
+// QuietHours are only set for non-critical notifications of users that configured them.
 type Notification struct {
 	ID             uuid.UUID
 	UserID         int
@@ -15,7 +26,27 @@ type Notification struct {
 	RecipientPhone string
 	Status         string
 	Attempts       int
+	Priority       Priority
+	QuietHours     *QuietHours
 	NextRunAt      time.Time
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 }
+
+// RunAt returns when the notification may run again if it is due at t: t itself, or the end of
+// the recipient's quiet hours if t falls into them. Critical notifications are never deferred.
+func (n *Notification) RunAt(t time.Time) time.Time {
+	if n.QuietHours == nil || n.Priority == "" || n.Priority == PriorityCritical {
+		return t
+	}
+
+	loc, err := time.LoadLocation(n.QuietHours.TimeZone)
+	if err != nil {
+		return t
+	}
+	if until, ok := n.QuietHours.DeferUntil(t, loc); ok {
+		return until
+	}
+
+	return t
+}
@@ -0,0 +1,10 @@
+package models
+
+// RateLimit describes a token bucket shared by all sender-service replicas.
+// Rate is the sustained number of messages per second, Burst is the bucket capacity.
+// Key identifies the bucket, e.g. "account:<sid>" or "from:<number>".
+type RateLimit struct {
+	Key   string
+	Rate  float64
+	Burst float64
+}
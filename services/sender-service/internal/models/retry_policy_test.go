@@ -0,0 +1,105 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	tests := map[string]struct {
+		policy   models.RetryPolicy
+		attempt  int
+		rnd      float64
+		expected time.Duration
+	}{
+		"exponential first attempt": {
+			policy:   models.RetryPolicy{Backoff: models.BackoffExponential, BaseDelayMs: 1000},
+			attempt:  1,
+			expected: time.Second,
+		},
+		"exponential third attempt": {
+			policy:   models.RetryPolicy{Backoff: models.BackoffExponential, BaseDelayMs: 1000},
+			attempt:  3,
+			expected: 4 * time.Second,
+		},
+		"unknown backoff falls back to exponential": {
+			policy:   models.RetryPolicy{BaseDelayMs: 500},
+			attempt:  2,
+			expected: time.Second,
+		},
+		"linear": {
+			policy:   models.RetryPolicy{Backoff: models.BackoffLinear, BaseDelayMs: 1000},
+			attempt:  3,
+			expected: 3 * time.Second,
+		},
+		"constant": {
+			policy:   models.RetryPolicy{Backoff: models.BackoffConstant, BaseDelayMs: 2000},
+			attempt:  7,
+			expected: 2 * time.Second,
+		},
+		"capped by max delay": {
+			policy:   models.RetryPolicy{Backoff: models.BackoffExponential, BaseDelayMs: 1000, MaxDelayMs: 10000},
+			attempt:  10,
+			expected: 10 * time.Second,
+		},
+		"jitter shortens delay": {
+			policy:   models.RetryPolicy{Backoff: models.BackoffConstant, BaseDelayMs: 1000, Jitter: 0.5},
+			attempt:  1,
+			rnd:      0.5,
+			expected: 750 * time.Millisecond,
+		},
+		"zero attempt treated as first": {
+			policy:   models.RetryPolicy{Backoff: models.BackoffExponential, BaseDelayMs: 1000},
+			attempt:  0,
+			expected: time.Second,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.policy.Delay(tc.attempt, tc.rnd))
+		})
+	}
+}
+
+func TestRetryPolicy_Validate(t *testing.T) {
+	valid := models.RetryPolicy{
+		MaxAttempts:  5,
+		Backoff:      models.BackoffExponential,
+		BaseDelayMs:  1000,
+		MaxDelayMs:   300_000,
+		Jitter:       0.2,
+		StaleAfterMs: 300_000,
+	}
+
+	tests := map[string]struct {
+		modify  func(p *models.RetryPolicy)
+		wantErr bool
+	}{
+		"valid":                 {modify: func(p *models.RetryPolicy) {}},
+		"unknown backoff":       {modify: func(p *models.RetryPolicy) { p.Backoff = "fibonacci" }, wantErr: true},
+		"no attempts":           {modify: func(p *models.RetryPolicy) { p.MaxAttempts = 0 }, wantErr: true},
+		"too many attempts":     {modify: func(p *models.RetryPolicy) { p.MaxAttempts = 51 }, wantErr: true},
+		"base delay too short":  {modify: func(p *models.RetryPolicy) { p.BaseDelayMs = 10 }, wantErr: true},
+		"max delay below base":  {modify: func(p *models.RetryPolicy) { p.MaxDelayMs = 500 }, wantErr: true},
+		"jitter above one":      {modify: func(p *models.RetryPolicy) { p.Jitter = 1.5 }, wantErr: true},
+		"stale-after too short": {modify: func(p *models.RetryPolicy) { p.StaleAfterMs = 1000 }, wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := valid
+			tc.modify(&p)
+
+			err := p.Validate()
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
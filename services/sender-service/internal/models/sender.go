@@ -0,0 +1,79 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SenderKind names the type of identity a message is sent from.
+type SenderKind string
+
+const (
+	// SenderNumber is a phone number (long code or short code) owned by the provider account.
+	SenderNumber SenderKind = "number"
+	// SenderMessagingService is a Twilio Messaging Service SID; Twilio picks the number from the service's own pool.
+	SenderMessagingService SenderKind = "messaging_service"
+	// SenderAlphanumeric is a branded alphanumeric sender ID, supported only in some countries.
+	SenderAlphanumeric SenderKind = "alphanumeric"
+)
+
+// SenderIdentity is a single entry of the sender pool.
+// Countries lists ISO 3166-1 alpha-2 region codes the identity serves; an empty list means any country.
+type SenderIdentity struct {
+	Kind      SenderKind
+	Value     string
+	Countries []string
+}
+
+// ServesCountry reports whether the identity is explicitly configured for the given region.
+func (s SenderIdentity) ServesCountry(region string) bool {
+	for _, c := range s.Countries {
+		if c == region {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseSenderPool parses a sender pool specification of the form
+// "kind:value[@CC,CC];kind:value[@CC,CC]...", e.g.
+// "number:+15550000001@US,CA;messaging_service:MG0123@GB;alphanumeric:CityAlert@DE".
+func ParseSenderPool(spec string) ([]SenderIdentity, error) {
+	var pool []SenderIdentity
+
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		kind, rest, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("sender %q: missing kind", entry)
+		}
+
+		s := SenderIdentity{Kind: SenderKind(strings.TrimSpace(kind))}
+		switch s.Kind {
+		case SenderNumber, SenderMessagingService, SenderAlphanumeric:
+		default:
+			return nil, fmt.Errorf("sender %q: unknown kind %q", entry, kind)
+		}
+
+		value, countries, _ := strings.Cut(rest, "@")
+		s.Value = strings.TrimSpace(value)
+		if s.Value == "" {
+			return nil, fmt.Errorf("sender %q: missing value", entry)
+		}
+
+		for _, c := range strings.Split(countries, ",") {
+			c = strings.ToUpper(strings.TrimSpace(c))
+			if c != "" {
+				s.Countries = append(s.Countries, c)
+			}
+		}
+
+		pool = append(pool, s)
+	}
+
+	return pool, nil
+}
@@ -0,0 +1,53 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSenderPool(t *testing.T) {
+	tests := map[string]struct {
+		spec      string
+		want      []models.SenderIdentity
+		expectErr bool
+	}{
+		"empty": {
+			spec: "",
+			want: nil,
+		},
+		"mixed pool": {
+			spec: "number:+15550000001@US,ca; messaging_service:MG0123 ;alphanumeric:City Alert@DE",
+			want: []models.SenderIdentity{
+				{Kind: models.SenderNumber, Value: "+15550000001", Countries: []string{"US", "CA"}},
+				{Kind: models.SenderMessagingService, Value: "MG0123"},
+				{Kind: models.SenderAlphanumeric, Value: "City Alert", Countries: []string{"DE"}},
+			},
+		},
+		"missing kind": {
+			spec:      "+15550000001",
+			expectErr: true,
+		},
+		"unknown kind": {
+			spec:      "shortcode:12345",
+			expectErr: true,
+		},
+		"missing value": {
+			spec:      "number:@US",
+			expectErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := models.ParseSenderPool(tc.spec)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
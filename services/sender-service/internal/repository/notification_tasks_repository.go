@@ -23,11 +23,13 @@ func NewNotificationTasksRepository(db domain.DBConn) *NotificationTasksReposito
 	}
 }
 
-// GetNotificationByID retrieves a notification from the database by its UUID.
+// GetNotificationByID retrieves a notification from the database by its UUID, along with its
+// priority and quiet hours.
 // Returns domain.ErrNotificationNotExists if the record is not found.
 func (ntr *NotificationTasksRepository) GetNotificationByID(ctx context.Context, id uuid.UUID) (*models.Notification, error) {
 	const q = `
-		SELECT id, user_id, recipient_phone, status, attempts, next_run_at, created_at, updated_at
+		SELECT id, user_id, recipient_phone, status, attempts, next_run_at, created_at, updated_at,
+		       priority, quiet_hours
 		FROM notifications
 		WHERE id = $1
 	`
@@ -35,7 +37,10 @@ func (ntr *NotificationTasksRepository) GetNotificationByID(ctx context.Context,
 	row := ntr.db.QueryRow(ctx, q, id)
 
 	var n models.Notification
-	err := row.Scan(&n.ID, &n.UserID, &n.RecipientPhone, &n.Status, &n.Attempts, &n.NextRunAt, &n.CreatedAt, &n.UpdatedAt)
+	err := row.Scan(
+		&n.ID, &n.UserID, &n.RecipientPhone, &n.Status, &n.Attempts, &n.NextRunAt, &n.CreatedAt, &n.UpdatedAt,
+		&n.Priority, &n.QuietHours,
+	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrNotificationNotExists
@@ -46,6 +51,27 @@ func (ntr *NotificationTasksRepository) GetNotificationByID(ctx context.Context,
 	return &n, nil
 }
 
+// Claim marks an in-flight notification task as being sent, so that it is no longer cancelled
+// along with its campaign. It reports false, leaving the task unchanged, when the task is not
+// in flight, e.g. because it was already claimed, or when its campaign was cancelled.
+func (ntr *NotificationTasksRepository) Claim(ctx context.Context, id uuid.UUID) (bool, error) {
+	const q = `
+		UPDATE notifications n
+		SET status     = 'sending',
+			updated_at = NOW()
+		WHERE n.id = $1
+		  AND n.status = 'in_flight'
+		  AND NOT EXISTS (SELECT 1 FROM cancelled_campaigns c WHERE c.campaign_id = n.campaign_id)
+	`
+
+	cmdTag, err := ntr.db.Exec(ctx, q, id)
+	if err != nil {
+		return false, err
+	}
+
+	return cmdTag.RowsAffected() > 0, nil
+}
+
 // Reschedule updates a notification task's status to "pending" and sets a new next_run_at timestamp.
 // Returns domain.ErrNotificationNotExists if the task doesn't exist.
 func (ntr *NotificationTasksRepository) Reschedule(ctx context.Context, id uuid.UUID, nextRunAt time.Time) (*models.Notification, error) {
@@ -96,3 +122,54 @@ func (ntr *NotificationTasksRepository) MarkFailed(ctx context.Context, id uuid.
 
 	return &n, nil
 }
+
+// MarkSent marks a notification task as delivered by setting its status to "sent".
+// It is used for channels without delivery callbacks.
+// Returns domain.ErrNotificationNotExists if the task is not found.
+func (ntr *NotificationTasksRepository) MarkSent(ctx context.Context, id uuid.UUID) (*models.Notification, error) {
+	const q = `
+		UPDATE notifications
+		SET status     = 'sent',
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING id, user_id, recipient_phone, status, attempts, next_run_at, created_at, updated_at
+	`
+
+	row := ntr.db.QueryRow(ctx, q, id)
+
+	var n models.Notification
+	err := row.Scan(&n.ID, &n.UserID, &n.RecipientPhone, &n.Status, &n.Attempts, &n.NextRunAt, &n.CreatedAt, &n.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotificationNotExists
+		}
+		return nil, err
+	}
+
+	return &n, nil
+}
+
+// FallBack moves a notification task to the first of its remaining fallback endpoints and schedules
+// it for delivery there at nextRunAt with a fresh attempt count. It reports false, leaving the task
+// unchanged, when no fallback endpoint is left.
+func (ntr *NotificationTasksRepository) FallBack(ctx context.Context, id uuid.UUID, nextRunAt time.Time) (bool, error) {
+	const q = `
+		UPDATE notifications
+		SET channel         = fallbacks -> 0 ->> 'channel',
+		    recipient_phone = fallbacks -> 0 ->> 'address',
+		    fallbacks       = fallbacks - 0,
+		    status          = 'pending',
+		    attempts        = 0,
+		    next_run_at     = $2,
+			updated_at      = NOW()
+		WHERE id = $1
+		  AND jsonb_array_length(fallbacks) > 0
+	`
+
+	cmdTag, err := ntr.db.Exec(ctx, q, id, nextRunAt)
+	if err != nil {
+		return false, err
+	}
+
+	return cmdTag.RowsAffected() > 0, nil
+}
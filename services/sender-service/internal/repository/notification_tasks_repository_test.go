@@ -46,6 +46,8 @@ func TestGetNotificationByID(t *testing.T) {
 				assert.Equal(t, "+10000000001", n.RecipientPhone)
 				assert.Equal(t, "pending", n.Status)
 				assert.Equal(t, 0, n.Attempts)
+				assert.Equal(t, models.PriorityNormal, n.Priority)
+				assert.Equal(t, &models.QuietHours{StartMinute: 1320, EndMinute: 420, TimeZone: "Europe/Moscow"}, n.QuietHours)
 			},
 		},
 		{
@@ -69,6 +71,51 @@ func TestGetNotificationByID(t *testing.T) {
 	}
 }
 
+func TestClaim(t *testing.T) {
+	ctx := context.Background()
+
+	loader := makeFixtures(t, testDB, "./../../../../db/fixtures/notifications.yml")
+	if err := loader.Load(); err != nil {
+		t.Fatalf("failed loading fixtures: %v", err)
+	}
+
+	repo := repository.NewNotificationTasksRepository(testPool)
+	queued := uuid.MustParse("33333333-3333-3333-3333-333333333333")
+	cancelled := uuid.MustParse("44444444-4444-4444-4444-444444444444")
+	campaignID := uuid.New()
+
+	// the campaign of the second task is cancelled while the task is already queued
+	_, err := testDB.ExecContext(ctx, `UPDATE notifications SET campaign_id = $1 WHERE id IN ($2, $3)`, campaignID, queued, cancelled)
+	assert.NoError(t, err)
+
+	claimed, err := repo.Claim(ctx, queued)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+
+	claimed, err = repo.Claim(ctx, queued)
+	assert.NoError(t, err)
+	assert.False(t, claimed, "a redelivered task must not be claimed twice")
+
+	_, err = testDB.ExecContext(ctx, `INSERT INTO cancelled_campaigns (campaign_id, user_id) VALUES ($1, 4)`, campaignID)
+	assert.NoError(t, err)
+
+	claimed, err = repo.Claim(ctx, cancelled)
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+
+	var status string
+	err = testDB.QueryRowContext(ctx, `SELECT status FROM notifications WHERE id=$1`, queued).Scan(&status)
+	assert.NoError(t, err)
+	assert.Equal(t, "sending", status)
+	err = testDB.QueryRowContext(ctx, `SELECT status FROM notifications WHERE id=$1`, cancelled).Scan(&status)
+	assert.NoError(t, err)
+	assert.Equal(t, "in_flight", status)
+
+	claimed, err = repo.Claim(ctx, uuid.New())
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+}
+
 func TestReschedule(t *testing.T) {
 	ctx := context.Background()
 
@@ -182,3 +229,60 @@ func TestMarkFailed(t *testing.T) {
 		})
 	}
 }
+
+func TestMarkSent(t *testing.T) {
+	ctx := context.Background()
+
+	loader := makeFixtures(t, testDB, "./../../../../db/fixtures/notifications.yml")
+	if err := loader.Load(); err != nil {
+		t.Fatalf("failed loading fixtures: %v", err)
+	}
+
+	repo := repository.NewNotificationTasksRepository(testPool)
+
+	n, err := repo.MarkSent(ctx, uuid.MustParse("33333333-3333-3333-3333-333333333333"))
+	assert.NoError(t, err)
+	assert.Equal(t, "sent", n.Status)
+
+	_, err = repo.MarkSent(ctx, uuid.New())
+	assert.ErrorIs(t, err, domain.ErrNotificationNotExists)
+}
+
+func TestFallBack(t *testing.T) {
+	ctx := context.Background()
+
+	loader := makeFixtures(t, testDB, "./../../../../db/fixtures/notifications.yml")
+	if err := loader.Load(); err != nil {
+		t.Fatalf("failed loading fixtures: %v", err)
+	}
+
+	repo := repository.NewNotificationTasksRepository(testPool)
+	id := uuid.MustParse("33333333-3333-3333-3333-333333333333")
+
+	_, err := testDB.ExecContext(ctx,
+		`UPDATE notifications SET fallbacks = '[{"channel": "email", "address": "ivan@example.com"}]' WHERE id = $1`, id,
+	)
+	assert.NoError(t, err)
+
+	morning := time.Now().Add(8 * time.Hour).UTC().Truncate(time.Second)
+	fellBack, err := repo.FallBack(ctx, id, morning)
+	assert.NoError(t, err)
+	assert.True(t, fellBack)
+
+	var channel, recipient, status string
+	var attempts int
+	var nextRunAt time.Time
+	err = testDB.QueryRowContext(ctx,
+		`SELECT channel, recipient_phone, status, attempts, next_run_at FROM notifications WHERE id=$1`, id,
+	).Scan(&channel, &recipient, &status, &attempts, &nextRunAt)
+	assert.NoError(t, err)
+	assert.Equal(t, "email", channel)
+	assert.Equal(t, "ivan@example.com", recipient)
+	assert.Equal(t, "pending", status)
+	assert.Equal(t, 0, attempts)
+	assert.True(t, morning.Equal(nextRunAt))
+
+	fellBack, err = repo.FallBack(ctx, id, time.Now())
+	assert.NoError(t, err)
+	assert.False(t, fellBack)
+}
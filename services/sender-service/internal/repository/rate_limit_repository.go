@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/domain"
+)
+
+// RateLimitRepository keeps provider token buckets in the rate_limit_buckets table.
+type RateLimitRepository struct {
+	db domain.DBConn
+}
+
+// NewRateLimitRepository creates a new instance of RateLimitRepository.
+func NewRateLimitRepository(db domain.DBConn) *RateLimitRepository {
+	return &RateLimitRepository{
+		db: db,
+	}
+}
+
+// ReserveToken refills the bucket for the time elapsed since its last update and takes one token
+// in a single statement, so concurrent replicas never hand out the same token twice.
+// A new bucket starts full.
+func (rlr *RateLimitRepository) ReserveToken(ctx context.Context, key string, rate, burst float64) (float64, error) {
+	const q = `
+		INSERT INTO rate_limit_buckets AS b (key, tokens, updated_at)
+		VALUES ($1, $3::double precision - 1, clock_timestamp())
+		ON CONFLICT (key) DO UPDATE
+		SET tokens     = LEAST($3::double precision,
+		                       b.tokens + EXTRACT(EPOCH FROM clock_timestamp() - b.updated_at) * $2::double precision) - 1,
+		    updated_at = clock_timestamp()
+		RETURNING tokens
+	`
+
+	var tokens float64
+	err := rlr.db.QueryRow(ctx, q, key, rate, burst).Scan(&tokens)
+	if err != nil {
+		return 0, err
+	}
+
+	return tokens, nil
+}
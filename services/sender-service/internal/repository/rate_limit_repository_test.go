@@ -0,0 +1,45 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitRepository_ReserveToken(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewRateLimitRepository(testPool)
+	t.Cleanup(func() {
+		_, err := testDB.Exec("TRUNCATE rate_limit_buckets")
+		require.NoError(t, err)
+	})
+
+	// a new bucket starts full
+	tokens, err := repo.ReserveToken(ctx, "from:+100", 1, 3)
+	require.NoError(t, err)
+	assert.InDelta(t, 2, tokens, 0.01)
+
+	tokens, err = repo.ReserveToken(ctx, "from:+100", 1, 3)
+	require.NoError(t, err)
+	assert.InDelta(t, 1, tokens, 0.01)
+
+	tokens, err = repo.ReserveToken(ctx, "from:+100", 1, 3)
+	require.NoError(t, err)
+	assert.InDelta(t, 0, tokens, 0.01)
+
+	// an empty bucket lends tokens from the future
+	tokens, err = repo.ReserveToken(ctx, "from:+100", 1, 3)
+	require.NoError(t, err)
+	assert.InDelta(t, -1, tokens, 0.01)
+
+	// buckets are independent
+	tokens, err = repo.ReserveToken(ctx, "from:+200", 1, 3)
+	require.NoError(t, err)
+	assert.InDelta(t, 2, tokens, 0.01)
+}
@@ -51,3 +51,12 @@ func (m *MockKafkaFactory) NewWriter(topic string) *kafka.Writer {
 func (m *MockKafkaFactory) NewReader(topic string, groupID string) *kafka.Reader {
 	return m.Called(topic, groupID).Get(0).(*kafka.Reader)
 }
+
+type MockRateLimitRepository struct {
+	mock.Mock
+}
+
+func (m *MockRateLimitRepository) ReserveToken(ctx context.Context, key string, rate, burst float64) (float64, error) {
+	args := m.Called(ctx, key, rate, burst)
+	return args.Get(0).(float64), args.Error(1)
+}
@@ -2,46 +2,95 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand"
 	"time"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/models"
 )
 
-// NotificationTasksService coordinates the delivery and retry logic for SMS notification tasks.
-// It sends notifications using the provided SmsSender and handles rescheduling or marking as failed
-// based on the result and number of attempts.
+// NotificationTasksService coordinates the delivery and retry logic for notification tasks.
+// It sends notifications using the provided SmsSender or EmailSender and handles rescheduling,
+// falling back to the next endpoint of the contact or marking as failed based on the result,
+// the number of attempts and the task's retry policy.
 type NotificationTasksService struct {
-	repository  domain.NotificationTasksRepository
-	smsSender   domain.SmsSender
-	maxAttempts int
+	repository         domain.NotificationTasksRepository
+	smsSender          domain.SmsSender
+	emailSender        domain.EmailSender
+	defaultRetryPolicy models.RetryPolicy
 }
 
 // NewNotificationTasksService creates a new NotificationTasksService.
-func NewNotificationTasksService(r domain.NotificationTasksRepository, ss domain.SmsSender, maxAttempts int) *NotificationTasksService {
+// defaultPolicy is applied to tasks that do not carry their own retry policy.
+func NewNotificationTasksService(r domain.NotificationTasksRepository, ss domain.SmsSender, es domain.EmailSender, defaultPolicy models.RetryPolicy) *NotificationTasksService {
 	return &NotificationTasksService{
-		repository:  r,
-		smsSender:   ss,
-		maxAttempts: maxAttempts,
+		repository:         r,
+		smsSender:          ss,
+		emailSender:        es,
+		defaultRetryPolicy: defaultPolicy,
 	}
 }
 
-// SendNotification attempts to send a notification task via SMS.
-// If sending fails and the attempt count is below the maximum, it reschedules the task using exponential backoff.
-// If the maximum number of attempts is reached, it marks the task as permanently failed.
-func (nts *NotificationTasksService) SendNotification(ctx context.Context, task *domain.NotificationTask) error {
-	err := nts.smsSender.SendSMS(task.RecipientPhone, task.Text, task.ID.String())
+// SendNotification attempts to send a notification task over its channel, SMS messages from the
+// given sender identity. E-mails have no delivery callbacks and are marked sent once accepted.
+// The task is claimed first and skipped if it can't be, so that tasks of campaigns cancelled while
+// queued, as well as redelivered tasks, are not sent.
+// If sending fails and the attempt count is below the policy maximum, it reschedules the task
+// using the policy backoff. Once the attempts are exhausted, or the failure is not retryable,
+// the task falls back to the contact's next endpoint or, with none left, is marked as failed.
+// Retries and fallbacks of non-critical tasks wait for the end of the recipient's quiet hours.
+func (nts *NotificationTasksService) SendNotification(ctx context.Context, task *domain.NotificationTask, from models.SenderIdentity) error {
+	claimed, err := nts.repository.Claim(ctx, task.ID)
 	if err != nil {
-		if task.Attempts < nts.maxAttempts {
-			// exponential backoff: base * 2^(attempts-1)
-			delay := time.Second * (1 << (task.Attempts - 1))
-			nextRunAt := time.Now().Add(delay)
+		return fmt.Errorf("claim error: %w", err)
+	}
+	if !claimed {
+		return nil
+	}
+
+	if task.Channel == models.ChannelEmail {
+		err = nts.emailSender.SendEmail(task.RecipientPhone, task.Text, task.ID.String())
+		if err == nil {
+			_, repoErr := nts.repository.MarkSent(ctx, task.ID)
+			if repoErr != nil {
+				return fmt.Errorf("mark sent error: %w", repoErr)
+			}
+			return nil
+		}
+	} else {
+		err = nts.smsSender.SendSMS(from, task.RecipientPhone, task.Text, task.ID.String())
+	}
+	if err != nil {
+		policy := nts.defaultRetryPolicy
+		if task.RetryPolicy != nil {
+			policy = *task.RetryPolicy
+		}
+
+		ntf, repoErr := nts.repository.GetNotificationByID(ctx, task.ID)
+		if repoErr != nil {
+			return fmt.Errorf("send failed: %w; get notification error: %v", err, repoErr)
+		}
+
+		var sendErr domain.SendError
+		permanent := errors.As(err, &sendErr) && !sendErr.Retryable()
+
+		if !permanent && task.Attempts < policy.MaxAttempts {
+			nextRunAt := ntf.RunAt(time.Now().Add(policy.Delay(task.Attempts, rand.Float64())))
 
 			_, repoErr := nts.repository.Reschedule(ctx, task.ID, nextRunAt)
 			if repoErr != nil {
 				return fmt.Errorf("send failed: %w; reschedule failed: %v", err, repoErr)
 			}
-		} else {
+			return nil
+		}
+
+		fellBack, repoErr := nts.repository.FallBack(ctx, task.ID, ntf.RunAt(time.Now()))
+		if repoErr != nil {
+			return fmt.Errorf("send failed: %w; fall back error: %v", err, repoErr)
+		}
+		if !fellBack {
 			_, repoErr := nts.repository.MarkFailed(ctx, task.ID)
 			if repoErr != nil {
 				return fmt.Errorf("send failed: %w; mark failed error: %v", err, repoErr)
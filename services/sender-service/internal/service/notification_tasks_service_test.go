@@ -18,10 +18,25 @@ type MockSmsSender struct {
 	mock.Mock
 }
 
-func (m *MockSmsSender) SendSMS(phone, text, id string) error {
-	return m.Called(phone, text, id).Error(0)
+func (m *MockSmsSender) SendSMS(from models.SenderIdentity, phone, text, id string) error {
+	return m.Called(from, phone, text, id).Error(0)
 }
 
+type MockEmailSender struct {
+	mock.Mock
+}
+
+func (m *MockEmailSender) SendEmail(to, text, id string) error {
+	return m.Called(to, text, id).Error(0)
+}
+
+type sendError struct {
+	retryable bool
+}
+
+func (e sendError) Error() string   { return "send error" }
+func (e sendError) Retryable() bool { return e.retryable }
+
 type MockNotificationTasksRepository struct {
 	mock.Mock
 }
@@ -31,6 +46,11 @@ func (m *MockNotificationTasksRepository) GetNotificationByID(ctx context.Contex
 	return args.Get(0).(*models.Notification), args.Error(1)
 }
 
+func (m *MockNotificationTasksRepository) Claim(ctx context.Context, id uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockNotificationTasksRepository) Reschedule(ctx context.Context, id uuid.UUID, nextRunAt time.Time) (*models.Notification, error) {
 	args := m.Called(ctx, id, nextRunAt)
 	return args.Get(0).(*models.Notification), args.Error(1)
@@ -41,9 +61,31 @@ func (m *MockNotificationTasksRepository) MarkFailed(ctx context.Context, id uui
 	return args.Get(0).(*models.Notification), args.Error(1)
 }
 
+func (m *MockNotificationTasksRepository) MarkSent(ctx context.Context, id uuid.UUID) (*models.Notification, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.Notification), args.Error(1)
+}
+
+func (m *MockNotificationTasksRepository) FallBack(ctx context.Context, id uuid.UUID, nextRunAt time.Time) (bool, error) {
+	args := m.Called(ctx, id, nextRunAt)
+	return args.Bool(0), args.Error(1)
+}
+
 func TestSendNotification(t *testing.T) {
 	ctx := context.Background()
 	id := uuid.New()
+	from := models.SenderIdentity{Kind: models.SenderNumber, Value: "+199999"}
+
+	// A window covering the whole day except the last minute before its start,
+	// so that a retry is always inside it regardless of when the test runs.
+	now := time.Now().UTC()
+	minute := now.Hour()*60 + now.Minute()
+	quiet := &models.QuietHours{
+		StartMinute: minute,
+		EndMinute:   (minute + 24*60 - 1) % (24 * 60),
+		TimeZone:    "UTC",
+	}
+
 	tasks := map[string]struct {
 		task        domain.NotificationTask
 		maxAttempts int
@@ -75,6 +117,72 @@ func TestSendNotification(t *testing.T) {
 			},
 			expectErr: false,
 		},
+		"retry waits for the end of quiet hours": {
+			task:        domain.NotificationTask{ID: id, RecipientPhone: "+100", Text: "hello", Attempts: 1},
+			maxAttempts: 3,
+			senderErr:   errors.New("sms down"),
+			repoSetup: func(r *MockNotificationTasksRepository, task domain.NotificationTask) {
+				r.
+					On("GetNotificationByID", mock.Anything, task.ID).
+					Return(&models.Notification{ID: task.ID, Priority: models.PriorityNormal, QuietHours: quiet}, nil).
+					Once()
+				r.
+					On("Reschedule", mock.Anything, task.ID, mock.MatchedBy(func(t time.Time) bool {
+						return t.After(now.Add(23*time.Hour)) && t.Before(now.Add(24*time.Hour))
+					})).
+					Return((*models.Notification)(nil), nil).
+					Once()
+			},
+			expectErr: false,
+		},
+		"critical retry ignores quiet hours": {
+			task:        domain.NotificationTask{ID: id, RecipientPhone: "+100", Text: "hello", Attempts: 1},
+			maxAttempts: 3,
+			senderErr:   errors.New("sms down"),
+			repoSetup: func(r *MockNotificationTasksRepository, task domain.NotificationTask) {
+				r.
+					On("GetNotificationByID", mock.Anything, task.ID).
+					Return(&models.Notification{ID: task.ID, Priority: models.PriorityCritical, QuietHours: quiet}, nil).
+					Once()
+				r.
+					On("Reschedule", mock.Anything, task.ID, mock.MatchedBy(func(t time.Time) bool {
+						return t.Before(time.Now().Add(time.Minute))
+					})).
+					Return((*models.Notification)(nil), nil).
+					Once()
+			},
+			expectErr: false,
+		},
+		"fall back waits for the end of quiet hours": {
+			task:        domain.NotificationTask{ID: id, RecipientPhone: "+100", Text: "hello", Attempts: 3},
+			maxAttempts: 3,
+			senderErr:   errors.New("sms fail"),
+			repoSetup: func(r *MockNotificationTasksRepository, task domain.NotificationTask) {
+				r.
+					On("GetNotificationByID", mock.Anything, task.ID).
+					Return(&models.Notification{ID: task.ID, Priority: models.PriorityNormal, QuietHours: quiet}, nil).
+					Once()
+				r.
+					On("FallBack", mock.Anything, task.ID, mock.MatchedBy(func(t time.Time) bool {
+						return t.After(now.Add(23 * time.Hour))
+					})).
+					Return(true, nil).
+					Once()
+			},
+			expectErr: false,
+		},
+		"get notification repo error": {
+			task:        domain.NotificationTask{ID: id, RecipientPhone: "+100", Text: "hello", Attempts: 1},
+			maxAttempts: 3,
+			senderErr:   errors.New("sms down"),
+			repoSetup: func(r *MockNotificationTasksRepository, task domain.NotificationTask) {
+				r.
+					On("GetNotificationByID", mock.Anything, task.ID).
+					Return((*models.Notification)(nil), assert.AnError).
+					Once()
+			},
+			expectErr: true,
+		},
 		"reschedule repo error": {
 			task:        domain.NotificationTask{ID: id, RecipientPhone: "+100", Text: "hello", Attempts: 2},
 			maxAttempts: 3,
@@ -92,6 +200,10 @@ func TestSendNotification(t *testing.T) {
 			maxAttempts: 3,
 			senderErr:   errors.New("sms fail"),
 			repoSetup: func(r *MockNotificationTasksRepository, task domain.NotificationTask) {
+				r.
+					On("FallBack", mock.Anything, task.ID, mock.AnythingOfType("time.Time")).
+					Return(false, nil).
+					Once()
 				r.
 					On("MarkFailed", mock.Anything, task.ID).
 					Return((*models.Notification)(nil), nil).
@@ -99,11 +211,82 @@ func TestSendNotification(t *testing.T) {
 			},
 			expectErr: false,
 		},
+		"task policy overrides default": {
+			task: domain.NotificationTask{
+				ID: id, RecipientPhone: "+100", Text: "hello", Attempts: 3,
+				RetryPolicy: &models.RetryPolicy{MaxAttempts: 10, Backoff: models.BackoffConstant, BaseDelayMs: 5000},
+			},
+			maxAttempts: 3,
+			senderErr:   errors.New("sms down"),
+			repoSetup: func(r *MockNotificationTasksRepository, task domain.NotificationTask) {
+				nextRunMin := time.Now().Add(5 * time.Second)
+				nextRunMax := time.Now().Add(5*time.Second + 50*time.Millisecond)
+				r.
+					On("Reschedule", mock.Anything, task.ID, mock.MatchedBy(func(t time.Time) bool {
+						return t.After(nextRunMin) && t.Before(nextRunMax)
+					})).
+					Return((*models.Notification)(nil), nil).
+					Once()
+			},
+			expectErr: false,
+		},
+		"fall back on max attempts": {
+			task:        domain.NotificationTask{ID: id, RecipientPhone: "+100", Text: "hello", Attempts: 3},
+			maxAttempts: 3,
+			senderErr:   errors.New("sms fail"),
+			repoSetup: func(r *MockNotificationTasksRepository, task domain.NotificationTask) {
+				r.
+					On("FallBack", mock.Anything, task.ID, mock.AnythingOfType("time.Time")).
+					Return(true, nil).
+					Once()
+			},
+			expectErr: false,
+		},
+		"fall back on permanent failure": {
+			task:        domain.NotificationTask{ID: id, RecipientPhone: "+100", Text: "hello", Attempts: 1},
+			maxAttempts: 3,
+			senderErr:   sendError{retryable: false},
+			repoSetup: func(r *MockNotificationTasksRepository, task domain.NotificationTask) {
+				r.
+					On("FallBack", mock.Anything, task.ID, mock.AnythingOfType("time.Time")).
+					Return(true, nil).
+					Once()
+			},
+			expectErr: false,
+		},
+		"reschedule on retryable failure": {
+			task:        domain.NotificationTask{ID: id, RecipientPhone: "+100", Text: "hello", Attempts: 1},
+			maxAttempts: 3,
+			senderErr:   sendError{retryable: true},
+			repoSetup: func(r *MockNotificationTasksRepository, task domain.NotificationTask) {
+				r.
+					On("Reschedule", mock.Anything, task.ID, mock.Anything).
+					Return((*models.Notification)(nil), nil).
+					Once()
+			},
+			expectErr: false,
+		},
+		"fall back repo error": {
+			task:        domain.NotificationTask{ID: id, RecipientPhone: "+100", Text: "hello", Attempts: 3},
+			maxAttempts: 3,
+			senderErr:   errors.New("sms fail"),
+			repoSetup: func(r *MockNotificationTasksRepository, task domain.NotificationTask) {
+				r.
+					On("FallBack", mock.Anything, task.ID, mock.AnythingOfType("time.Time")).
+					Return(false, assert.AnError).
+					Once()
+			},
+			expectErr: true,
+		},
 		"mark failed repo error": {
 			task:        domain.NotificationTask{ID: id, RecipientPhone: "+100", Text: "hello", Attempts: 5},
 			maxAttempts: 5,
 			senderErr:   errors.New("sms fail"),
 			repoSetup: func(r *MockNotificationTasksRepository, task domain.NotificationTask) {
+				r.
+					On("FallBack", mock.Anything, task.ID, mock.AnythingOfType("time.Time")).
+					Return(false, nil).
+					Once()
 				r.
 					On("MarkFailed", mock.Anything, task.ID).
 					Return((*models.Notification)(nil), assert.AnError).
@@ -118,14 +301,21 @@ func TestSendNotification(t *testing.T) {
 			sender := &MockSmsSender{}
 			repo := &MockNotificationTasksRepository{}
 			sender.
-				On("SendSMS", tc.task.RecipientPhone, tc.task.Text, tc.task.ID.String()).
+				On("SendSMS", from, tc.task.RecipientPhone, tc.task.Text, tc.task.ID.String()).
 				Return(tc.senderErr)
 
+			repo.On("Claim", mock.Anything, tc.task.ID).Return(true, nil).Once()
 			tc.repoSetup(repo, tc.task)
+			repo.On("GetNotificationByID", mock.Anything, tc.task.ID).Return(&models.Notification{ID: tc.task.ID}, nil).Maybe()
 
-			svc := service.NewNotificationTasksService(repo, sender, tc.maxAttempts)
+			policy := models.RetryPolicy{
+				MaxAttempts: tc.maxAttempts,
+				Backoff:     models.BackoffExponential,
+				BaseDelayMs: 1000,
+			}
+			svc := service.NewNotificationTasksService(repo, sender, &MockEmailSender{}, policy)
 
-			err := svc.SendNotification(ctx, &tc.task)
+			err := svc.SendNotification(ctx, &tc.task, from)
 			if tc.expectErr {
 				assert.Error(t, err)
 			} else {
@@ -137,3 +327,106 @@ func TestSendNotification(t *testing.T) {
 		})
 	}
 }
+
+func TestSendNotification_Email(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.New()
+	policy := models.RetryPolicy{MaxAttempts: 3, Backoff: models.BackoffConstant, BaseDelayMs: 1000}
+
+	tests := map[string]struct {
+		senderErr error
+		repoSetup func(r *MockNotificationTasksRepository)
+		expectErr bool
+	}{
+		"marked sent on success": {
+			repoSetup: func(r *MockNotificationTasksRepository) {
+				r.On("MarkSent", mock.Anything, id).Return((*models.Notification)(nil), nil).Once()
+			},
+		},
+		"mark sent repo error": {
+			repoSetup: func(r *MockNotificationTasksRepository) {
+				r.On("MarkSent", mock.Anything, id).Return((*models.Notification)(nil), assert.AnError).Once()
+			},
+			expectErr: true,
+		},
+		"unknown mailbox marks failed without fallback": {
+			senderErr: sendError{retryable: false},
+			repoSetup: func(r *MockNotificationTasksRepository) {
+				r.On("FallBack", mock.Anything, id, mock.AnythingOfType("time.Time")).Return(false, nil).Once()
+				r.On("MarkFailed", mock.Anything, id).Return((*models.Notification)(nil), nil).Once()
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			sms := &MockSmsSender{}
+			emails := &MockEmailSender{}
+			repo := &MockNotificationTasksRepository{}
+			emails.On("SendEmail", "ivan@example.com", "hello", id.String()).Return(tc.senderErr).Once()
+			repo.On("Claim", mock.Anything, id).Return(true, nil).Once()
+			repo.On("GetNotificationByID", mock.Anything, id).Return(&models.Notification{ID: id}, nil).Maybe()
+			tc.repoSetup(repo)
+
+			svc := service.NewNotificationTasksService(repo, sms, emails, policy)
+			task := &domain.NotificationTask{ID: id, Channel: models.ChannelEmail, RecipientPhone: "ivan@example.com", Text: "hello", Attempts: 1}
+
+			err := svc.SendNotification(ctx, task, models.SenderIdentity{})
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			sms.AssertNotCalled(t, "SendSMS", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			emails.AssertExpectations(t)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSendNotification_NotClaimed(t *testing.T) {
+	ctx := context.Background()
+	from := models.SenderIdentity{Kind: models.SenderNumber, Value: "+199999"}
+	policy := models.RetryPolicy{MaxAttempts: 3, Backoff: models.BackoffConstant, BaseDelayMs: 1000}
+
+	tests := map[string]struct {
+		task      domain.NotificationTask
+		claimErr  error
+		expectErr bool
+	}{
+		"sms of a campaign cancelled while queued is skipped": {
+			task: domain.NotificationTask{ID: uuid.New(), CampaignID: uuid.New(), RecipientPhone: "+100", Text: "hello", Attempts: 1},
+		},
+		"e-mail of a campaign cancelled while queued is skipped": {
+			task: domain.NotificationTask{ID: uuid.New(), CampaignID: uuid.New(), Channel: models.ChannelEmail, RecipientPhone: "ivan@example.com", Text: "hello", Attempts: 1},
+		},
+		"claim repo error": {
+			task:      domain.NotificationTask{ID: uuid.New(), RecipientPhone: "+100", Text: "hello", Attempts: 1},
+			claimErr:  assert.AnError,
+			expectErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			sms := &MockSmsSender{}
+			emails := &MockEmailSender{}
+			repo := &MockNotificationTasksRepository{}
+			repo.On("Claim", mock.Anything, tc.task.ID).Return(false, tc.claimErr).Once()
+
+			svc := service.NewNotificationTasksService(repo, sms, emails, policy)
+
+			err := svc.SendNotification(ctx, &tc.task, from)
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			sms.AssertNotCalled(t, "SendSMS", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			emails.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything, mock.Anything)
+			repo.AssertExpectations(t)
+		})
+	}
+}
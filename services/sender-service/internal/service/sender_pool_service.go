@@ -0,0 +1,99 @@
+package service
+
+import (
+	"hash/fnv"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/models"
+	"github.com/nyaruka/phonenumbers"
+)
+
+// SenderPoolService selects a sender identity for each notification task from a configured pool.
+// Identities configured for the destination country are preferred over catch-all ones.
+// Among the candidates, the choice is made by rendezvous hashing on the recipient phone, so a recipient
+// always sees the same sender on every replica, and only recipients of a removed identity move when the pool changes.
+type SenderPoolService struct {
+	pool                  []models.SenderIdentity
+	alphanumericCountries map[string]bool
+}
+
+// NewSenderPoolService creates a new SenderPoolService.
+// alphanumericCountries lists the region codes where alphanumeric sender IDs may be used;
+// alphanumeric identities, including branded template sender IDs, are never used elsewhere.
+func NewSenderPoolService(pool []models.SenderIdentity, alphanumericCountries []string) *SenderPoolService {
+	allowed := make(map[string]bool, len(alphanumericCountries))
+	for _, c := range alphanumericCountries {
+		allowed[c] = true
+	}
+
+	return &SenderPoolService{
+		pool:                  pool,
+		alphanumericCountries: allowed,
+	}
+}
+
+// SelectSender returns the identity the task should be sent from.
+// A branded sender ID requested by the task wins when the destination country allows alphanumeric senders.
+// If no identity serves the destination, the first identity of the pool is used.
+func (sps *SenderPoolService) SelectSender(task *domain.NotificationTask) models.SenderIdentity {
+	region := regionOf(task.RecipientPhone)
+	alphanumericAllowed := region != "" && sps.alphanumericCountries[region]
+
+	if task.SenderID != "" && alphanumericAllowed {
+		return models.SenderIdentity{Kind: models.SenderAlphanumeric, Value: task.SenderID}
+	}
+
+	var local, global []models.SenderIdentity
+	for _, s := range sps.pool {
+		if s.Kind == models.SenderAlphanumeric && !alphanumericAllowed {
+			continue
+		}
+		switch {
+		case s.ServesCountry(region):
+			local = append(local, s)
+		case len(s.Countries) == 0:
+			global = append(global, s)
+		}
+	}
+
+	candidates := local
+	if len(candidates) == 0 {
+		candidates = global
+	}
+	if len(candidates) == 0 {
+		if len(sps.pool) == 0 {
+			return models.SenderIdentity{}
+		}
+		return sps.pool[0]
+	}
+
+	return pickSticky(task.RecipientPhone, candidates)
+}
+
+func regionOf(phone string) string {
+	num, err := phonenumbers.Parse(phone, "")
+	if err != nil {
+		return ""
+	}
+	return phonenumbers.GetRegionCodeForNumber(num)
+}
+
+// pickSticky implements rendezvous (highest random weight) hashing.
+func pickSticky(recipient string, candidates []models.SenderIdentity) models.SenderIdentity {
+	var best models.SenderIdentity
+	var bestScore uint64
+
+	for i, c := range candidates {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(recipient))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(c.Value))
+
+		score := h.Sum64()
+		if i == 0 || score > bestScore {
+			best, bestScore = c, score
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,100 @@
+package service_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSenderPoolService_SelectSender(t *testing.T) {
+	usNumber1 := models.SenderIdentity{Kind: models.SenderNumber, Value: "+15550000001", Countries: []string{"US", "CA"}}
+	usNumber2 := models.SenderIdentity{Kind: models.SenderNumber, Value: "+15550000002", Countries: []string{"US"}}
+	global := models.SenderIdentity{Kind: models.SenderMessagingService, Value: "MG0123"}
+	brandDE := models.SenderIdentity{Kind: models.SenderAlphanumeric, Value: "ACME", Countries: []string{"DE"}}
+	brandAny := models.SenderIdentity{Kind: models.SenderAlphanumeric, Value: "ACMEGLOBAL"}
+
+	pool := []models.SenderIdentity{usNumber1, usNumber2, global, brandDE, brandAny}
+	svc := service.NewSenderPoolService(pool, []string{"DE", "GB"})
+
+	tests := map[string]struct {
+		task    domain.NotificationTask
+		allowed []models.SenderIdentity
+	}{
+		"country specific numbers are preferred": {
+			task:    domain.NotificationTask{RecipientPhone: "+12025550123"},
+			allowed: []models.SenderIdentity{usNumber1, usNumber2},
+		},
+		"catch-all for countries without dedicated senders": {
+			task:    domain.NotificationTask{RecipientPhone: "+79123456789"},
+			allowed: []models.SenderIdentity{global},
+		},
+		"alphanumeric sender where allowed": {
+			task:    domain.NotificationTask{RecipientPhone: "+4915123456789"},
+			allowed: []models.SenderIdentity{brandDE},
+		},
+		"catch-all alphanumeric only where allowed": {
+			task:    domain.NotificationTask{RecipientPhone: "+447400123456"},
+			allowed: []models.SenderIdentity{global, brandAny},
+		},
+		"branded template sender id where allowed": {
+			task:    domain.NotificationTask{RecipientPhone: "+447400123456", SenderID: "CityAlert"},
+			allowed: []models.SenderIdentity{{Kind: models.SenderAlphanumeric, Value: "CityAlert"}},
+		},
+		"branded template sender id ignored where not allowed": {
+			task:    domain.NotificationTask{RecipientPhone: "+12025550123", SenderID: "CityAlert"},
+			allowed: []models.SenderIdentity{usNumber1, usNumber2},
+		},
+		"unparsable phone falls back to catch-all": {
+			task:    domain.NotificationTask{RecipientPhone: "phone"},
+			allowed: []models.SenderIdentity{global},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := svc.SelectSender(&tc.task)
+			assert.Contains(t, tc.allowed, got)
+		})
+	}
+}
+
+func TestSenderPoolService_SelectSender_Sticky(t *testing.T) {
+	pool := []models.SenderIdentity{
+		{Kind: models.SenderNumber, Value: "+15550000001"},
+		{Kind: models.SenderNumber, Value: "+15550000002"},
+		{Kind: models.SenderNumber, Value: "+15550000003"},
+	}
+	svc := service.NewSenderPoolService(pool, nil)
+
+	used := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		task := &domain.NotificationTask{RecipientPhone: fmt.Sprintf("+1202555%04d", i)}
+
+		first := svc.SelectSender(task)
+		for j := 0; j < 3; j++ {
+			assert.Equal(t, first, svc.SelectSender(task))
+		}
+
+		// another replica with the same pool picks the same sender
+		assert.Equal(t, first, service.NewSenderPoolService(pool, nil).SelectSender(task))
+
+		used[first.Value] = true
+	}
+
+	assert.Len(t, used, len(pool), "recipients should be spread over the whole pool")
+}
+
+func TestSenderPoolService_SelectSender_NoCandidates(t *testing.T) {
+	pool := []models.SenderIdentity{
+		{Kind: models.SenderNumber, Value: "+15550000001", Countries: []string{"US"}},
+	}
+	svc := service.NewSenderPoolService(pool, nil)
+
+	got := svc.SelectSender(&domain.NotificationTask{RecipientPhone: "+79123456789"})
+
+	assert.Equal(t, pool[0], got)
+}
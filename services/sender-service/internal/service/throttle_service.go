@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/models"
+)
+
+// ThrottleService enforces provider rate limits across all sender-service replicas.
+// Every send reserves a token in the account bucket and in the bucket of its sender identity,
+// then waits until the more contended of them has refilled, which turns provider limits into
+// consumer backpressure.
+type ThrottleService struct {
+	repository domain.RateLimitRepository
+	account    models.RateLimit
+	perSender  models.RateLimit
+}
+
+// NewThrottleService creates a new ThrottleService.
+// The Key of perSender is a prefix, completed with the sender identity of each message.
+// Limits with a non-positive rate are disabled; a burst below one is raised to one.
+func NewThrottleService(r domain.RateLimitRepository, account, perSender models.RateLimit) *ThrottleService {
+	return &ThrottleService{
+		repository: r,
+		account:    normalizeLimit(account),
+		perSender:  normalizeLimit(perSender),
+	}
+}
+
+func normalizeLimit(l models.RateLimit) models.RateLimit {
+	if l.Burst < 1 {
+		l.Burst = 1
+	}
+	return l
+}
+
+// Wait blocks until a message may be sent from the given identity under all configured limits.
+func (ts *ThrottleService) Wait(ctx context.Context, from models.SenderIdentity) error {
+	sender := ts.perSender
+	sender.Key += from.Value
+
+	var wait time.Duration
+
+	for _, l := range []models.RateLimit{ts.account, sender} {
+		if l.Rate <= 0 {
+			continue
+		}
+
+		tokens, err := ts.repository.ReserveToken(ctx, l.Key, l.Rate, l.Burst)
+		if err != nil {
+			return err
+		}
+
+		if tokens < 0 {
+			d := time.Duration(-tokens / l.Rate * float64(time.Second))
+			if d > wait {
+				wait = d
+			}
+		}
+	}
+
+	if wait == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,88 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/models"
+	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestThrottleService_Wait(t *testing.T) {
+	account := models.RateLimit{Key: "account:AC1", Rate: 100, Burst: 10}
+	perSender := models.RateLimit{Key: "sender:", Rate: 10, Burst: 0}
+	from := models.SenderIdentity{Kind: models.SenderNumber, Value: "+100"}
+
+	tests := map[string]struct {
+		setup     func(r *MockRateLimitRepository)
+		ctxCancel bool
+		minWait   time.Duration
+		expectErr error
+	}{
+		"tokens available": {
+			setup: func(r *MockRateLimitRepository) {
+				r.On("ReserveToken", mock.Anything, "account:AC1", 100.0, 10.0).Return(5.0, nil).Once()
+				r.On("ReserveToken", mock.Anything, "sender:+100", 10.0, 1.0).Return(0.0, nil).Once()
+			},
+		},
+		"waits for the slowest bucket": {
+			setup: func(r *MockRateLimitRepository) {
+				r.On("ReserveToken", mock.Anything, "account:AC1", 100.0, 10.0).Return(-1.0, nil).Once()
+				r.On("ReserveToken", mock.Anything, "sender:+100", 10.0, 1.0).Return(-0.5, nil).Once()
+			},
+			minWait: 50 * time.Millisecond,
+		},
+		"repository error": {
+			setup: func(r *MockRateLimitRepository) {
+				r.On("ReserveToken", mock.Anything, "account:AC1", 100.0, 10.0).Return(0.0, assert.AnError).Once()
+			},
+			expectErr: assert.AnError,
+		},
+		"context cancelled while waiting": {
+			setup: func(r *MockRateLimitRepository) {
+				r.On("ReserveToken", mock.Anything, "account:AC1", 100.0, 10.0).Return(0.0, nil).Once()
+				r.On("ReserveToken", mock.Anything, "sender:+100", 10.0, 1.0).Return(-100.0, nil).Once()
+			},
+			ctxCancel: true,
+			expectErr: context.Canceled,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			repo := new(MockRateLimitRepository)
+			tc.setup(repo)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.ctxCancel {
+				time.AfterFunc(10*time.Millisecond, cancel)
+			}
+
+			svc := service.NewThrottleService(repo, account, perSender)
+			start := time.Now()
+			err := svc.Wait(ctx, from)
+
+			if tc.expectErr != nil {
+				assert.ErrorIs(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+				assert.GreaterOrEqual(t, time.Since(start), tc.minWait)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestThrottleService_Wait_Disabled(t *testing.T) {
+	repo := new(MockRateLimitRepository)
+
+	svc := service.NewThrottleService(repo, models.RateLimit{Key: "account:AC1"}, models.RateLimit{Key: "sender:"})
+	err := svc.Wait(context.Background(), models.SenderIdentity{Kind: models.SenderNumber, Value: "+100"})
+
+	assert.NoError(t, err)
+	repo.AssertNotCalled(t, "ReserveToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -7,7 +7,10 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/models"
 )
 
 // DevSendError simulates a structured error from the DevSmsSender.
@@ -36,6 +39,7 @@ type DevSmsSender struct {
 	CallbackDelay    time.Duration // how long until we fire the callback
 	CallbackFailRate float64       // 0.0–1.0 chance of callback failure
 	rng              *rand.Rand
+	rngMu            sync.Mutex // rand.Rand is not safe for concurrent use
 }
 
 // NewDevSmsSender creates a new DevSmsSender.
@@ -56,22 +60,22 @@ func NewDevSmsSender(dir, callbackBaseURL string, failRate, cbFailRate float64,
 // SendSMS simulates sending an SMS message.
 // If the callback does not fail, the message content is written to a file in the configured directory.
 // Regardless of success, a simulated status callback is posted to the configured callback URL.
-func (d *DevSmsSender) SendSMS(to, body, notificationID string) error {
-	if d.rng.Float64() < d.FailRate {
+func (d *DevSmsSender) SendSMS(from models.SenderIdentity, to, body, notificationID string) error {
+	if d.random() < d.FailRate {
 		return DevSendError{
 			Message:   "dev sender: simulated send failure",
 			retryable: true,
 		}
 	}
 
-	callbackFailed := d.rng.Float64() < d.CallbackFailRate
+	callbackFailed := d.random() < d.CallbackFailRate
 
 	ts := time.Now().Format("02.01.2006-15:04:05")
 	filename := fmt.Sprintf("%s__%s.txt", ts, to)
 
 	if !callbackFailed {
 		path := filepath.Join(d.Dir, filename)
-		contents := fmt.Sprintf("To: %s\n\n%s\n\n%s", to, body, ts)
+		contents := fmt.Sprintf("From: %s\nTo: %s\n\n%s\n\n%s", from.Value, to, body, ts)
 		if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
 			return DevSendError{
 				Message:   fmt.Sprintf("failed to write sms file: %v", err),
@@ -105,7 +109,7 @@ func (d *DevSmsSender) SendSMS(to, body, notificationID string) error {
 			"MessageSid":    {sid},
 			"MessageStatus": {messageStatus},
 			"To":            {to},
-			"From":          {"DEV-SENDER"},
+			"From":          {from.Value},
 		}
 
 		_, _ = http.PostForm(cbURL, form)
@@ -113,3 +117,9 @@ func (d *DevSmsSender) SendSMS(to, body, notificationID string) error {
 
 	return nil
 }
+
+func (d *DevSmsSender) random() float64 {
+	d.rngMu.Lock()
+	defer d.rngMu.Unlock()
+	return d.rng.Float64()
+}
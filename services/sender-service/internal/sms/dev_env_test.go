@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/models"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -58,7 +59,7 @@ func TestDevSmsSender_SendSMS(t *testing.T) {
 			body := "Hello dev"
 			notifID := "notif-1"
 
-			err = sender.SendSMS(to, body, notifID)
+			err = sender.SendSMS(models.SenderIdentity{Kind: models.SenderAlphanumeric, Value: "CityAlert"}, to, body, notifID)
 			if tc.expectErr {
 				assert.Error(t, err)
 			} else {
@@ -71,6 +72,7 @@ func TestDevSmsSender_SendSMS(t *testing.T) {
 				content, _ := os.ReadFile(files[0])
 				assert.Contains(t, string(content), to)
 				assert.Contains(t, string(content), body)
+				assert.Contains(t, string(content), "From: CityAlert")
 			} else {
 				assert.Len(t, files, 0)
 			}
@@ -6,7 +6,10 @@ import (
 	"net/http"
 	"net/url"
 	"runtime"
+	"sync"
 	"time"
+
+	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/models"
 )
 
 // TestSendError simulates a structured error from the TestSmsSender.
@@ -34,11 +37,13 @@ type TestSmsSender struct {
 	CallbackDelay    time.Duration // how long until we fire the callback
 	CallbackFailRate float64       // 0.0–1.0 chance of callback failure
 	rng              *rand.Rand
+	rngMu            sync.Mutex // rand.Rand is not safe for concurrent use
 	jobs             chan callbackJob
 }
 
 type callbackJob struct {
 	to             string
+	from           string
 	notificationID string
 	sid            string
 	callbackFailed bool
@@ -65,15 +70,15 @@ func NewTestSmsSender(callbackBaseURL string, failRate, cbFailRate float64, cbDe
 // SendSMS simulates sending an SMS message.
 // It may return a retryable error depending on the configured FailRate.
 // If the sending is successful, a delivery callback will eventually be sent.
-func (d *TestSmsSender) SendSMS(to, body, notificationID string) error {
-	if d.rng.Float64() < d.FailRate {
+func (d *TestSmsSender) SendSMS(from models.SenderIdentity, to, body, notificationID string) error {
+	if d.random() < d.FailRate {
 		return TestSendError{"dev sender: simulated send failure", true}
 	}
 
 	sid := fmt.Sprintf("%s__%s.txt", time.Now().Format("02.01.2006-15:04:05"), to)
-	cbFailed := d.rng.Float64() < d.CallbackFailRate
+	cbFailed := d.random() < d.CallbackFailRate
 
-	d.jobs <- callbackJob{to, notificationID, sid, cbFailed}
+	d.jobs <- callbackJob{to, from.Value, notificationID, sid, cbFailed}
 	return nil
 }
 
@@ -100,9 +105,15 @@ func (d *TestSmsSender) worker() {
 			"MessageSid":    {job.sid},
 			"MessageStatus": {status},
 			"To":            {job.to},
-			"From":          {"DEV-SENDER"},
+			"From":          {job.from},
 		}
 
 		_, _ = http.PostForm(cbURL, form)
 	}
 }
+
+func (d *TestSmsSender) random() float64 {
+	d.rngMu.Lock()
+	defer d.rngMu.Unlock()
+	return d.rng.Float64()
+}
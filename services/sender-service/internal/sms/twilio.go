@@ -5,6 +5,7 @@ import (
 	"net/url"
 
 	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/domain"
+	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/models"
 	"github.com/twilio/twilio-go"
 	api "github.com/twilio/twilio-go/rest/api/v2010"
 )
@@ -31,12 +32,11 @@ func (e TwilioSendError) Retryable() bool {
 // It also registers a status callback for delivery reporting.
 type Sender struct {
 	twilioAPI       domain.TwilioAPI
-	fromNumber      string
 	callbackBaseURL string
 }
 
 // NewSmsSender initializes and returns a new SmsSender.
-func NewSmsSender(accountSID, authToken, fromNumber, callbackBaseURL string) *Sender {
+func NewSmsSender(accountSID, authToken, callbackBaseURL string) *Sender {
 	client := twilio.NewRestClientWithParams(twilio.ClientParams{
 		Username: accountSID,
 		Password: authToken,
@@ -44,15 +44,15 @@ func NewSmsSender(accountSID, authToken, fromNumber, callbackBaseURL string) *Se
 
 	return &Sender{
 		twilioAPI:       client.Api,
-		fromNumber:      fromNumber,
 		callbackBaseURL: callbackBaseURL,
 	}
 }
 
-// SendSMS sends an SMS message using Twilio's API.
+// SendSMS sends an SMS message using Twilio's API from the given sender identity.
+// Messaging Service identities are passed as MessagingServiceSid, numbers and alphanumeric IDs as From.
 // It sets a status callback for delivery tracking and returns a TwilioSendError
 // if sending fails or if Twilio returns an error code.
-func (s *Sender) SendSMS(to, body, notificationID string) error {
+func (s *Sender) SendSMS(from models.SenderIdentity, to, body, notificationID string) error {
 	cb, err := url.Parse(s.callbackBaseURL)
 	if err != nil {
 		return err
@@ -63,7 +63,11 @@ func (s *Sender) SendSMS(to, body, notificationID string) error {
 	cbURL := cb.String()
 
 	params := &api.CreateMessageParams{}
-	params.SetFrom(s.fromNumber)
+	if from.Kind == models.SenderMessagingService {
+		params.SetMessagingServiceSid(from.Value)
+	} else {
+		params.SetFrom(from.Value)
+	}
 	params.SetStatusCallback(cbURL)
 	params.SetTo(to)
 	params.SetBody(body)
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/SteeperMold/Emergency-Notification-System/services/sender-service/internal/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	api "github.com/twilio/twilio-go/rest/api/v2010"
@@ -13,7 +14,6 @@ import (
 func newTestSender(api *MockTwilioAPI) *Sender {
 	return &Sender{
 		twilioAPI:       api,
-		fromNumber:      "+199999",
 		callbackBaseURL: "http://callback.local/status",
 	}
 }
@@ -69,7 +69,7 @@ func TestSendSMS(t *testing.T) {
 
 			s := newTestSender(apiMock)
 
-			err := s.SendSMS("+100000", "hello", "notif-123")
+			err := s.SendSMS(models.SenderIdentity{Kind: models.SenderNumber, Value: "+199999"}, "+100000", "hello", "notif-123")
 
 			if tc.expectErr {
 				assert.Error(t, err)
@@ -95,3 +95,44 @@ func toIntPtr(v int) *int {
 func toStrPtr(s string) *string {
 	return &s
 }
+
+func TestSendSMS_SenderIdentity(t *testing.T) {
+	tests := map[string]struct {
+		from                 models.SenderIdentity
+		wantFrom             string
+		wantMessagingService string
+	}{
+		"number": {
+			from:     models.SenderIdentity{Kind: models.SenderNumber, Value: "+199999"},
+			wantFrom: "+199999",
+		},
+		"alphanumeric": {
+			from:     models.SenderIdentity{Kind: models.SenderAlphanumeric, Value: "CityAlert"},
+			wantFrom: "CityAlert",
+		},
+		"messaging service": {
+			from:                 models.SenderIdentity{Kind: models.SenderMessagingService, Value: "MG0123"},
+			wantMessagingService: "MG0123",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			apiMock := &MockTwilioAPI{}
+			apiMock.
+				On("CreateMessage", mock.MatchedBy(func(p *api.CreateMessageParams) bool {
+					if tc.wantFrom != "" {
+						return p.From != nil && *p.From == tc.wantFrom && p.MessagingServiceSid == nil
+					}
+					return p.MessagingServiceSid != nil && *p.MessagingServiceSid == tc.wantMessagingService && p.From == nil
+				})).
+				Return(&api.ApiV2010Message{Sid: toStrPtr("SM123")}, nil).
+				Once()
+
+			err := newTestSender(apiMock).SendSMS(tc.from, "+100000", "hello", "notif-123")
+
+			assert.NoError(t, err)
+			apiMock.AssertExpectations(t)
+		})
+	}
+}